@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
@@ -465,6 +467,43 @@ func (c *Client) SetLimiter(rateLimit float64, burst int) {
 	c.config.Limiter = rate.NewLimiter(rate.Limit(rateLimit), burst)
 }
 
+// ClientCertFingerprint returns the hex-encoded SHA-256 fingerprint of the
+// TLS client certificate presented to Vault, which tokens issued with client
+// certificate binding are bound to. It returns the empty string if no client
+// certificate is configured.
+func (c *Client) ClientCertFingerprint() (string, error) {
+	c.modifyLock.RLock()
+	c.config.modifyLock.RLock()
+	defer c.config.modifyLock.RUnlock()
+	c.modifyLock.RUnlock()
+
+	if c.config.HttpClient == nil {
+		return "", nil
+	}
+	transport, ok := c.config.HttpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		return "", nil
+	}
+
+	var cert *tls.Certificate
+	switch tlsConfig := transport.TLSClientConfig; {
+	case tlsConfig.GetClientCertificate != nil:
+		var err error
+		cert, err = tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		if err != nil {
+			return "", err
+		}
+	case len(tlsConfig.Certificates) > 0:
+		cert = &tlsConfig.Certificates[0]
+	}
+	if cert == nil || len(cert.Certificate) == 0 {
+		return "", nil
+	}
+
+	sum := sha256.Sum256(cert.Certificate[0])
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // SetMaxRetries sets the number of retries that will be used in the case of certain errors
 func (c *Client) SetMaxRetries(retries int) {
 	c.modifyLock.RLock()
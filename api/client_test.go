@@ -2,8 +2,12 @@ package api
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
@@ -196,6 +200,46 @@ func TestClientEnvSettings(t *testing.T) {
 	}
 }
 
+func TestClientCertFingerprint(t *testing.T) {
+	client, err := NewClient(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := client.ClientCertFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint != "" {
+		t.Fatalf("expected no fingerprint without a client certificate, got %q", fingerprint)
+	}
+
+	config := DefaultConfig()
+	if err := config.ConfigureTLS(&TLSConfig{
+		ClientCert: "test-fixtures/keys/cert.pem",
+		ClientKey:  "test-fixtures/keys/key.pem",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	client, err = NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err = client.ClientCertFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM, err := ioutil.ReadFile("test-fixtures/keys/cert.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(certPEM)
+	sum := sha256.Sum256(block.Bytes)
+	if expected := hex.EncodeToString(sum[:]); fingerprint != expected {
+		t.Fatalf("expected fingerprint %q, got %q", expected, fingerprint)
+	}
+}
+
 func TestClientEnvNamespace(t *testing.T) {
 	var seenNamespace string
 	handler := func(w http.ResponseWriter, req *http.Request) {
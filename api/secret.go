@@ -85,6 +85,30 @@ func (s *Secret) TokenAccessor() (string, error) {
 	return accessor, nil
 }
 
+// TokenBoundCertFingerprint returns the SHA-256 fingerprint of the TLS client
+// certificate the token in the given secret is bound to. If the secret is nil
+// or the token is not bound to a certificate, this returns the empty string.
+func (s *Secret) TokenBoundCertFingerprint() (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	if s.Auth != nil && len(s.Auth.BoundCertFingerprint) > 0 {
+		return s.Auth.BoundCertFingerprint, nil
+	}
+
+	if s.Data == nil || s.Data["bound_cert_fingerprint"] == nil {
+		return "", nil
+	}
+
+	fingerprint, ok := s.Data["bound_cert_fingerprint"].(string)
+	if !ok {
+		return "", fmt.Errorf("token bound certificate fingerprint found but in the wrong format")
+	}
+
+	return fingerprint, nil
+}
+
 // TokenRemainingUses returns the standardized remaining uses for the given
 // secret. If the secret is nil or does not contain the "num_uses", this
 // returns -1. On error, this will return -1 and a non-nil error.
@@ -295,6 +319,8 @@ type SecretAuth struct {
 	Orphan           bool              `json:"orphan"`
 	EntityID         string            `json:"entity_id"`
 
+	BoundCertFingerprint string `json:"bound_cert_fingerprint"`
+
 	LeaseDuration int  `json:"lease_duration"`
 	Renewable     bool `json:"renewable"`
 }
@@ -104,6 +104,138 @@ func (c *Sys) DeletePolicy(name string) error {
 	return err
 }
 
+// PolicyExplain evaluates a request against the policies of a token, or a
+// set of policies, and explains whether it would be allowed
+func (c *Sys) PolicyExplain(input *PolicyExplainInput) (*PolicyExplainOutput, error) {
+	r := c.c.NewRequest("PUT", "/v1/sys/policy-explain")
+	if err := r.SetJSONBody(input); err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	resp, err := c.c.RawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result PolicyExplainOutput
+	if err := mapstructure.Decode(secret.Data, &result); err != nil {
+		return nil, err
+	}
+	result.Warnings = secret.Warnings
+
+	return &result, nil
+}
+
+type PolicyExplainInput struct {
+	Token      string                 `json:"token,omitempty"`
+	Policies   []string               `json:"policies,omitempty"`
+	Path       string                 `json:"path"`
+	Operation  string                 `json:"operation,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type PolicyExplainOutput struct {
+	Path         string                    `mapstructure:"path"`
+	Operation    string                    `mapstructure:"operation"`
+	Allowed      bool                      `mapstructure:"allowed"`
+	Reason       string                    `mapstructure:"reason"`
+	Capabilities []string                  `mapstructure:"capabilities"`
+	MatchedRules []*PolicyExplainRule      `mapstructure:"matched_rules"`
+	Policies     []*PolicyExplainPolicy    `mapstructure:"policies"`
+	Parameters   []*PolicyExplainParameter `mapstructure:"parameters"`
+	Warnings     []string                  `mapstructure:"-"`
+}
+
+type PolicyExplainRule struct {
+	Path         string   `mapstructure:"path"`
+	Type         string   `mapstructure:"type"`
+	Selected     bool     `mapstructure:"selected"`
+	Conditional  bool     `mapstructure:"conditional"`
+	Capabilities []string `mapstructure:"capabilities"`
+	Reason       string   `mapstructure:"reason"`
+}
+
+type PolicyExplainPolicy struct {
+	Name            string   `mapstructure:"name"`
+	Namespace       string   `mapstructure:"namespace"`
+	Allowed         bool     `mapstructure:"allowed"`
+	Capabilities    []string `mapstructure:"capabilities"`
+	MatchedRule     string   `mapstructure:"matched_rule"`
+	MatchedRuleType string   `mapstructure:"matched_rule_type"`
+}
+
+type PolicyExplainParameter struct {
+	Name    string `mapstructure:"name"`
+	Allowed bool   `mapstructure:"allowed"`
+	Reason  string `mapstructure:"reason"`
+}
+
+// LintPolicy parses the given ACL policy and reports likely mistakes in it
+func (c *Sys) LintPolicy(rules string) (*PolicyLintOutput, error) {
+	return c.lintPolicy(map[string]interface{}{
+		"policy": rules,
+	})
+}
+
+// LintPolicyByName reports likely mistakes in the existing ACL policy
+func (c *Sys) LintPolicyByName(name string) (*PolicyLintOutput, error) {
+	return c.lintPolicy(map[string]interface{}{
+		"name": name,
+	})
+}
+
+func (c *Sys) lintPolicy(body map[string]interface{}) (*PolicyLintOutput, error) {
+	r := c.c.NewRequest("PUT", "/v1/sys/policy/lint")
+	if err := r.SetJSONBody(body); err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	resp, err := c.c.RawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result PolicyLintOutput
+	if err := mapstructure.Decode(secret.Data, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type PolicyLintOutput struct {
+	Findings []*PolicyLintFinding `mapstructure:"findings"`
+}
+
+type PolicyLintFinding struct {
+	Check   string `mapstructure:"check"`
+	Path    string `mapstructure:"path"`
+	Line    int    `mapstructure:"line"`
+	Message string `mapstructure:"message"`
+}
+
 type getPoliciesResp struct {
 	Rules string `json:"rules"`
 }
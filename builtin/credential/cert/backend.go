@@ -2,9 +2,12 @@ package cert
 
 import (
 	"context"
+	"net/http"
 	"strings"
 	"sync"
+	"time"
 
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -33,13 +36,22 @@ func Backend() *backend {
 			pathCerts(&b),
 			pathCRLs(&b),
 		},
-		AuthRenew:   b.pathLoginRenew,
-		Invalidate:  b.invalidate,
-		BackendType: logical.TypeCredential,
+		AuthRenew:    b.pathLoginRenew,
+		Invalidate:   b.invalidate,
+		PeriodicFunc: b.periodicFunc,
+		BackendType:  logical.TypeCredential,
 	}
 
 	b.crlUpdateMutex = &sync.RWMutex{}
 
+	b.ocspCache = make(map[string]*ocspCacheEntry)
+	b.ocspCacheMutex = &sync.RWMutex{}
+	b.fetchedCRLs = make(map[string]*fetchedCRL)
+	b.fetchedCRLsMutex = &sync.RWMutex{}
+
+	b.httpClient = cleanhttp.DefaultClient()
+	b.httpClient.Timeout = 30 * time.Second
+
 	return &b
 }
 
@@ -49,6 +61,24 @@ type backend struct {
 
 	crls           map[string]CRLInfo
 	crlUpdateMutex *sync.RWMutex
+
+	// ocspCache holds OCSP responses until their next update time
+	ocspCache      map[string]*ocspCacheEntry
+	ocspCacheMutex *sync.RWMutex
+
+	// fetchedCRLs holds CRLs retrieved from CRL distribution points, keyed
+	// by URL
+	fetchedCRLs      map[string]*fetchedCRL
+	fetchedCRLsMutex *sync.RWMutex
+
+	httpClient *http.Client
+}
+
+// periodicFunc refreshes CRLs fetched from distribution points and drops
+// stale OCSP responses.
+func (b *backend) periodicFunc(ctx context.Context, req *logical.Request) error {
+	b.pruneOCSPCache()
+	return b.refreshFetchedCRLs(ctx)
 }
 
 func (b *backend) invalidate(_ context.Context, key string) {
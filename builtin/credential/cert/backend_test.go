@@ -5,14 +5,19 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/asn1"
 	"encoding/pem"
 	mathrand "math/rand"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-sockaddr"
 
+	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/http2"
 
 	"crypto/rsa"
@@ -1466,6 +1471,369 @@ func TestBackend_invalidCIDR(t *testing.T) {
 	}
 }
 
+// testRevocationCertTemplate returns a client certificate template for the
+// revocation tests, which use the certificate on both sides of the connection.
+func testRevocationCertTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "example.com",
+		},
+		DNSNames:    []string{"example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement,
+		SerialNumber: big.NewInt(mathrand.Int63()),
+		NotBefore:    time.Now().Add(-30 * time.Second),
+		NotAfter:     time.Now().Add(262980 * time.Hour),
+	}
+}
+
+// testLoadCA reads the CA generated by generateTestCertAndConnState
+func testLoadCA(t *testing.T, tempDir string) ([]byte, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	caPEM, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_cert.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert := parsePEM(caPEM)[0]
+
+	keyPEM, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	caKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return caPEM, caCert, caKey
+}
+
+func testRevocationWriteCert(t *testing.T, b logical.Backend, storage logical.Storage, data map[string]interface{}) {
+	t.Helper()
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "certs/web",
+		Data:      data,
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+}
+
+func testRevocationLogin(t *testing.T, b logical.Backend, storage logical.Storage, connState tls.ConnectionState, expectSuccess bool) {
+	t.Helper()
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation:       logical.UpdateOperation,
+		Path:            "login",
+		Unauthenticated: true,
+		Storage:         storage,
+		Connection:      &logical.Connection{ConnState: &connState},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case expectSuccess && (resp == nil || resp.IsError() || resp.Auth == nil):
+		t.Fatalf("expected login to succeed, got: %#v", resp)
+	case !expectSuccess && resp != nil && resp.Auth != nil:
+		t.Fatalf("expected login to fail, got: %#v", resp)
+	}
+}
+
+func TestBackend_OCSP(t *testing.T) {
+	var status int32 = ocsp.Good
+	var caCert *x509.Certificate
+	var caKey *ecdsa.PrivateKey
+
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ocspReq, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ocspResp, err := ocsp.CreateResponse(caCert, caCert, ocsp.Response{
+			Status:       int(atomic.LoadInt32(&status)),
+			SerialNumber: ocspReq.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, caKey)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(ocspResp)
+	}))
+	defer responder.Close()
+
+	template := testRevocationCertTemplate()
+	template.OCSPServer = []string{"http://127.0.0.1:1/unreachable"}
+	tempDir, connState, err := generateTestCertAndConnState(t, template)
+	if tempDir != "" {
+		defer os.RemoveAll(tempDir)
+	}
+	if err != nil {
+		t.Fatalf("error testing connection state: %v", err)
+	}
+	var ca []byte
+	ca, caCert, caKey = testLoadCA(t, tempDir)
+
+	storage := &logical.InmemStorage{}
+	b := testFactory(t)
+
+	// The responder listed in the certificate is unreachable, so a fail
+	// closed check rejects the login while a fail open one allows it
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":  string(ca),
+		"policies":     "foo",
+		"ocsp_enabled": true,
+	})
+	testRevocationLogin(t, b, storage, connState, false)
+
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":    string(ca),
+		"policies":       "foo",
+		"ocsp_enabled":   true,
+		"ocsp_fail_open": true,
+	})
+	testRevocationLogin(t, b, storage, connState, true)
+
+	// Point the entry at the working responder
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":           string(ca),
+		"policies":              "foo",
+		"ocsp_enabled":          true,
+		"ocsp_servers_override": responder.URL,
+	})
+	testRevocationLogin(t, b, storage, connState, true)
+
+	atomic.StoreInt32(&status, ocsp.Unknown)
+	testRevocationLogin(t, b, storage, connState, false)
+
+	// Revoked certificates are rejected even when failing open
+	atomic.StoreInt32(&status, ocsp.Revoked)
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":           string(ca),
+		"policies":              "foo",
+		"ocsp_enabled":          true,
+		"ocsp_servers_override": responder.URL,
+		"ocsp_fail_open":        true,
+	})
+	testRevocationLogin(t, b, storage, connState, false)
+}
+
+func TestBackend_CRLDistributionPoints(t *testing.T) {
+	var lock sync.Mutex
+	var revoked []pkix.RevokedCertificate
+	var caCert *x509.Certificate
+	var caKey *ecdsa.PrivateKey
+
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		crlBytes, err := caCert.CreateCRL(rand.Reader, caKey, revoked, time.Now(), time.Now().Add(time.Hour))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(crlBytes)
+	}))
+	defer crlServer.Close()
+
+	template := testRevocationCertTemplate()
+	template.CRLDistributionPoints = []string{crlServer.URL + "/crl"}
+	tempDir, connState, err := generateTestCertAndConnState(t, template)
+	if tempDir != "" {
+		defer os.RemoveAll(tempDir)
+	}
+	if err != nil {
+		t.Fatalf("error testing connection state: %v", err)
+	}
+	var ca []byte
+	ca, caCert, caKey = testLoadCA(t, tempDir)
+
+	storage := &logical.InmemStorage{}
+	b := testFactory(t).(*backend)
+
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":                     string(ca),
+		"policies":                        "foo",
+		"crl_distribution_points_enabled": true,
+	})
+	testRevocationLogin(t, b, storage, connState, true)
+
+	expireFetchedCRLs := func() {
+		b.fetchedCRLsMutex.Lock()
+		defer b.fetchedCRLsMutex.Unlock()
+		for _, crl := range b.fetchedCRLs {
+			crl.refreshAt = time.Now().Add(-time.Second)
+		}
+	}
+
+	// Revoke the client certificate and let the periodic function pick up
+	// the new CRL
+	lock.Lock()
+	revoked = append(revoked, pkix.RevokedCertificate{
+		SerialNumber:   template.SerialNumber,
+		RevocationTime: time.Now(),
+	})
+	lock.Unlock()
+	expireFetchedCRLs()
+	if err := b.periodicFunc(context.Background(), &logical.Request{Storage: storage}); err != nil {
+		t.Fatal(err)
+	}
+	testRevocationLogin(t, b, storage, connState, false)
+
+	// With the distribution point unreachable, only a fail open entry
+	// allows the login
+	lock.Lock()
+	revoked = nil
+	lock.Unlock()
+	crlServer.Close()
+	expireFetchedCRLs()
+	if err := b.periodicFunc(context.Background(), &logical.Request{Storage: storage}); err == nil {
+		t.Fatal("expected error refreshing CRL from closed server")
+	}
+	testRevocationLogin(t, b, storage, connState, false)
+
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":                     string(ca),
+		"policies":                        "foo",
+		"crl_distribution_points_enabled": true,
+		"crl_fail_open":                   true,
+	})
+	testRevocationLogin(t, b, storage, connState, true)
+}
+
+func TestBackend_AliasFromCert(t *testing.T) {
+	u, err := url.Parse("spiffe://example.com/host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagValue, err := asn1.Marshal("engineering")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := testRevocationCertTemplate()
+	template.Subject.Organization = []string{"Example Inc"}
+	template.Subject.OrganizationalUnit = []string{"ops", "dev"}
+	template.EmailAddresses = []string{"valid@example.com"}
+	template.URIs = []*url.URL{u}
+	template.ExtraExtensions = []pkix.Extension{
+		{
+			Id:    asn1.ObjectIdentifier{1, 2, 3, 45},
+			Value: tagValue,
+		},
+	}
+	tempDir, connState, err := generateTestCertAndConnState(t, template)
+	if tempDir != "" {
+		defer os.RemoveAll(tempDir)
+	}
+	if err != nil {
+		t.Fatalf("error testing connection state: %v", err)
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_cert.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage := &logical.InmemStorage{}
+	b := testFactory(t)
+
+	login := func(op logical.Operation) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation:       op,
+			Path:            "login",
+			Unauthenticated: true,
+			Data: map[string]interface{}{
+				"name": "web",
+			},
+			Storage:    storage,
+			Connection: &logical.Connection{ConnState: &connState},
+		})
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+		}
+		return resp
+	}
+
+	cases := map[string]string{
+		"":              "example.com",
+		"common_name":   "example.com",
+		"dns_san":       "example.com",
+		"email_san":     "valid@example.com",
+		"uri_san":       "spiffe://example.com/host",
+		"serial_number": certutil.GetHexFormatted(template.SerialNumber.Bytes(), ":"),
+	}
+	for source, expected := range cases {
+		data := map[string]interface{}{
+			"certificate": string(ca),
+			"policies":    "foo",
+		}
+		if source != "" {
+			data["alias_name_source"] = source
+		}
+		testRevocationWriteCert(t, b, storage, data)
+
+		if name := login(logical.UpdateOperation).Auth.Alias.Name; name != expected {
+			t.Fatalf("source %q: expected alias name %q, got %q", source, expected, name)
+		}
+		if name := login(logical.AliasLookaheadOperation).Auth.Alias.Name; name != expected {
+			t.Fatalf("source %q: expected lookahead alias name %q, got %q", source, expected, name)
+		}
+	}
+
+	testRevocationWriteCert(t, b, storage, map[string]interface{}{
+		"certificate":                   string(ca),
+		"policies":                      "foo",
+		"alias_metadata_subject_fields": "organization,organizational_unit,locality",
+		"alias_metadata_extensions":     "1.2.3.45,1.2.3.45:team,1.2.3.46",
+	})
+	expected := map[string]string{
+		"organization":        "Example Inc",
+		"organizational_unit": "dev,ops",
+		"1-2-3-45":            "engineering",
+		"team":                "engineering",
+	}
+	if metadata := login(logical.UpdateOperation).Auth.Alias.Metadata; !reflect.DeepEqual(metadata, expected) {
+		t.Fatalf("expected alias metadata %#v, got %#v", expected, metadata)
+	}
+
+	for field, value := range map[string]string{
+		"alias_name_source":             "subject",
+		"alias_metadata_subject_fields": "serial",
+		"alias_metadata_extensions":     "1.2.x",
+	} {
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "certs/web",
+			Data: map[string]interface{}{
+				"certificate": string(ca),
+				field:         value,
+			},
+			Storage: storage,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("expected error for %s=%q, got: %#v", field, value, resp)
+		}
+	}
+}
+
 func testAccStepAddCRL(t *testing.T, crl []byte, connState tls.ConnectionState) logicaltest.TestStep {
 	return logicaltest.TestStep{
 		Operation: logical.UpdateOperation,
@@ -0,0 +1,209 @@
+package cert
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// maxFetchedCRLSize bounds the amount of data read from a CRL
+	// distribution point
+	maxFetchedCRLSize = 32 * 1024 * 1024
+
+	// defaultCRLRefreshInterval is used for fetched CRLs that carry no next
+	// update time when no refresh interval has been configured
+	defaultCRLRefreshInterval = time.Hour
+)
+
+// fetchedCRL is a CRL retrieved from a certificate's CRL distribution point.
+// The issuer is kept so that the CRL can be verified again when refreshed.
+type fetchedCRL struct {
+	url             string
+	issuer          *x509.Certificate
+	serials         map[string]struct{}
+	refreshInterval time.Duration
+	refreshAt       time.Time
+}
+
+// checkCRLDistributionPoints checks every non-root certificate of the chain
+// against the CRLs published at its distribution points. An error is returned
+// if any certificate is revoked, or if the CRLs could not be retrieved and the
+// entry is not configured to fail open.
+func (b *backend) checkCRLDistributionPoints(ctx context.Context, entry *CertEntry, chain []*x509.Certificate) error {
+	for i, cert := range chain {
+		if len(cert.CRLDistributionPoints) == 0 {
+			continue
+		}
+		if i+1 >= len(chain) {
+			// Self-signed roots can't be checked against a CRL they issued
+			// themselves
+			break
+		}
+
+		revoked, err := b.serialInDistributionPoints(ctx, entry, cert, chain[i+1])
+		if err != nil {
+			if entry.CRLFailOpen {
+				b.Logger().Warn("unable to retrieve CRL distribution points, failing open", "cert_name", entry.Name, "error", err)
+				continue
+			}
+			return err
+		}
+		if revoked {
+			return fmt.Errorf("certificate with serial %s has been revoked according to its CRL distribution points", cert.SerialNumber)
+		}
+	}
+
+	return nil
+}
+
+func (b *backend) serialInDistributionPoints(ctx context.Context, entry *CertEntry, cert, issuer *x509.Certificate) (bool, error) {
+	var errs *multierror.Error
+	var checked bool
+	for _, url := range cert.CRLDistributionPoints {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			continue
+		}
+
+		crl, err := b.fetchedCRL(ctx, url, issuer, entry.CRLRefreshInterval)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		checked = true
+
+		if _, ok := crl.serials[cert.SerialNumber.String()]; ok {
+			return true, nil
+		}
+	}
+
+	if !checked {
+		if errs.ErrorOrNil() == nil {
+			return false, fmt.Errorf("no supported CRL distribution points for certificate with serial %s", cert.SerialNumber)
+		}
+		return false, errs
+	}
+
+	return false, nil
+}
+
+// fetchedCRL returns the CRL published at the given URL, fetching it if it
+// isn't cached yet or is due for a refresh.
+func (b *backend) fetchedCRL(ctx context.Context, url string, issuer *x509.Certificate, refreshInterval time.Duration) (*fetchedCRL, error) {
+	b.fetchedCRLsMutex.RLock()
+	cached, ok := b.fetchedCRLs[url]
+	b.fetchedCRLsMutex.RUnlock()
+	if ok && cached.issuer.Equal(issuer) && time.Now().Before(cached.refreshAt) {
+		return cached, nil
+	}
+
+	crl, err := b.fetchCRL(ctx, url, issuer, refreshInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	b.fetchedCRLsMutex.Lock()
+	b.fetchedCRLs[url] = crl
+	b.fetchedCRLsMutex.Unlock()
+
+	return crl, nil
+}
+
+func (b *backend) fetchCRL(ctx context.Context, url string, issuer *x509.Certificate, refreshInterval time.Duration) (*fetchedCRL, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("error fetching CRL from %q: {{err}}", url), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching CRL from %q: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxFetchedCRLSize))
+	if err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("error reading CRL from %q: {{err}}", url), err)
+	}
+
+	certList, err := x509.ParseCRL(body)
+	if err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("error parsing CRL from %q: {{err}}", url), err)
+	}
+	if err := issuer.CheckCRLSignature(certList); err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("error verifying CRL from %q: {{err}}", url), err)
+	}
+
+	now := time.Now()
+	if certList.HasExpired(now) {
+		return nil, fmt.Errorf("CRL from %q has expired", url)
+	}
+
+	crl := &fetchedCRL{
+		url:             url,
+		issuer:          issuer,
+		serials:         make(map[string]struct{}, len(certList.TBSCertList.RevokedCertificates)),
+		refreshInterval: refreshInterval,
+	}
+	for _, revokedCert := range certList.TBSCertList.RevokedCertificates {
+		crl.serials[revokedCert.SerialNumber.String()] = struct{}{}
+	}
+
+	// Refresh at the next update time of the CRL, or earlier if a refresh
+	// interval has been configured
+	nextUpdate := certList.TBSCertList.NextUpdate
+	switch {
+	case refreshInterval > 0 && (nextUpdate.IsZero() || now.Add(refreshInterval).Before(nextUpdate)):
+		crl.refreshAt = now.Add(refreshInterval)
+	case !nextUpdate.IsZero():
+		crl.refreshAt = nextUpdate
+	default:
+		crl.refreshAt = now.Add(defaultCRLRefreshInterval)
+	}
+
+	return crl, nil
+}
+
+// refreshFetchedCRLs fetches any cached CRL that is due for a refresh. CRLs
+// that fail to refresh are dropped from the cache so that the next login
+// retries the distribution point.
+func (b *backend) refreshFetchedCRLs(ctx context.Context) error {
+	b.fetchedCRLsMutex.RLock()
+	var due []*fetchedCRL
+	now := time.Now()
+	for _, crl := range b.fetchedCRLs {
+		if now.After(crl.refreshAt) {
+			due = append(due, crl)
+		}
+	}
+	b.fetchedCRLsMutex.RUnlock()
+
+	var errs *multierror.Error
+	for _, stale := range due {
+		crl, err := b.fetchCRL(ctx, stale.url, stale.issuer, stale.refreshInterval)
+
+		b.fetchedCRLsMutex.Lock()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			delete(b.fetchedCRLs, stale.url)
+		} else {
+			b.fetchedCRLs[stale.url] = crl
+		}
+		b.fetchedCRLsMutex.Unlock()
+	}
+
+	return errs.ErrorOrNil()
+}
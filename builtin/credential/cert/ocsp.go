@@ -0,0 +1,171 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxOCSPResponseSize bounds the amount of data read from an OCSP responder
+const maxOCSPResponseSize = 1024 * 1024
+
+// ocspCacheEntry holds the verified status of a certificate as reported by
+// an OCSP responder, valid until nextUpdate.
+type ocspCacheEntry struct {
+	status     int
+	nextUpdate time.Time
+}
+
+func ocspCacheKey(cert, issuer *x509.Certificate) string {
+	issuerHash := sha256.Sum256(issuer.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(issuerHash[:]) + ":" + cert.SerialNumber.String()
+}
+
+// checkOCSP queries the OCSP responders for the given certificate. An error is
+// returned if the certificate is revoked, or if its status could not be
+// determined and the entry is not configured to fail open.
+func (b *backend) checkOCSP(ctx context.Context, entry *CertEntry, cert, issuer *x509.Certificate) error {
+	if issuer == nil {
+		return b.ocspFailure(entry, fmt.Errorf("unable to find the issuer of certificate with serial %s", cert.SerialNumber))
+	}
+
+	key := ocspCacheKey(cert, issuer)
+	if status, ok := b.cachedOCSPStatus(key); ok {
+		return b.ocspStatusError(entry, cert, status)
+	}
+
+	servers := entry.OCSPServersOverride
+	if len(servers) == 0 {
+		servers = cert.OCSPServer
+	}
+	if len(servers) == 0 {
+		return b.ocspFailure(entry, fmt.Errorf("no OCSP responders available for certificate with serial %s", cert.SerialNumber))
+	}
+
+	ocspReq, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return b.ocspFailure(entry, errwrap.Wrapf("error creating OCSP request: {{err}}", err))
+	}
+
+	var errs *multierror.Error
+	for _, server := range servers {
+		ocspResp, err := b.queryOCSPResponder(ctx, server, ocspReq, cert, issuer)
+		if err != nil {
+			errs = multierror.Append(errs, errwrap.Wrapf(fmt.Sprintf("error querying OCSP responder %q: {{err}}", server), err))
+			continue
+		}
+
+		// Responses without a next update time indicate that newer
+		// information is always available, so they are not cached
+		if !ocspResp.NextUpdate.IsZero() {
+			b.ocspCacheMutex.Lock()
+			b.ocspCache[key] = &ocspCacheEntry{
+				status:     ocspResp.Status,
+				nextUpdate: ocspResp.NextUpdate,
+			}
+			b.ocspCacheMutex.Unlock()
+		}
+
+		return b.ocspStatusError(entry, cert, ocspResp.Status)
+	}
+
+	return b.ocspFailure(entry, errs.ErrorOrNil())
+}
+
+func (b *backend) cachedOCSPStatus(key string) (int, bool) {
+	b.ocspCacheMutex.RLock()
+	defer b.ocspCacheMutex.RUnlock()
+
+	cached, ok := b.ocspCache[key]
+	if !ok || time.Now().After(cached.nextUpdate) {
+		return 0, false
+	}
+	return cached.status, true
+}
+
+// pruneOCSPCache removes cached responses that are past their next update time
+func (b *backend) pruneOCSPCache() {
+	b.ocspCacheMutex.Lock()
+	defer b.ocspCacheMutex.Unlock()
+
+	now := time.Now()
+	for key, cached := range b.ocspCache {
+		if now.After(cached.nextUpdate) {
+			delete(b.ocspCache, key)
+		}
+	}
+}
+
+func (b *backend) queryOCSPResponder(ctx context.Context, server string, ocspReq []byte, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, server, bytes.NewReader(ocspReq))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// This verifies the signature of the response against the issuer, or
+	// against a delegated responder certificate signed by the issuer
+	ocspResp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if ocspResp.ThisUpdate.After(now) {
+		return nil, fmt.Errorf("OCSP response is not yet valid")
+	}
+	if !ocspResp.NextUpdate.IsZero() && ocspResp.NextUpdate.Before(now) {
+		return nil, fmt.Errorf("OCSP response has expired")
+	}
+
+	return ocspResp, nil
+}
+
+func (b *backend) ocspStatusError(entry *CertEntry, cert *x509.Certificate, status int) error {
+	switch status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("certificate with serial %s has been revoked according to OCSP", cert.SerialNumber)
+	default:
+		return b.ocspFailure(entry, fmt.Errorf("OCSP status of certificate with serial %s is unknown", cert.SerialNumber))
+	}
+}
+
+// ocspFailure decides whether an inconclusive OCSP check should fail the
+// login based on the fail-open setting of the entry.
+func (b *backend) ocspFailure(entry *CertEntry, err error) error {
+	if entry.OCSPFailOpen {
+		b.Logger().Warn("unable to determine OCSP status, failing open", "cert_name", entry.Name, "error", err)
+		return nil
+	}
+	return err
+}
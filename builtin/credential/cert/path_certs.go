@@ -129,6 +129,72 @@ TTL will be set to the value of this parameter.`,
 				Description: `Comma separated string or list of CIDR blocks. If set, specifies the blocks of
 IP addresses which can perform the login operation.`,
 			},
+
+			"ocsp_enabled": &framework.FieldSchema{
+				Type: framework.TypeBool,
+				Description: `If set, the client certificate is checked against
+the OCSP responders listed in its Authority Information Access
+extension, or against ocsp_servers_override if set.`,
+			},
+
+			"ocsp_servers_override": &framework.FieldSchema{
+				Type: framework.TypeCommaStringSlice,
+				Description: `A comma-separated list of OCSP responder URLs to
+query instead of those listed in the client certificate.`,
+			},
+
+			"ocsp_fail_open": &framework.FieldSchema{
+				Type: framework.TypeBool,
+				Description: `If set, logins are allowed when the OCSP status
+of the client certificate can't be determined. Revoked certificates
+are always rejected. Defaults to false.`,
+			},
+
+			"crl_distribution_points_enabled": &framework.FieldSchema{
+				Type: framework.TypeBool,
+				Description: `If set, the certificates of the client's chain are
+checked against the CRLs fetched from their CRL distribution points.`,
+			},
+
+			"crl_fail_open": &framework.FieldSchema{
+				Type: framework.TypeBool,
+				Description: `If set, logins are allowed when the CRLs of the
+client's chain can't be retrieved. Revoked certificates are always
+rejected. Defaults to false.`,
+			},
+
+			"alias_name_source": &framework.FieldSchema{
+				Type:    framework.TypeString,
+				Default: aliasNameSourceCommonName,
+				Description: `The field of the client certificate used as the
+name of the identity alias. One of "common_name", "dns_san", "email_san",
+"uri_san" or "serial_number". For SANs, the first entry is used.
+Defaults to "common_name".`,
+			},
+
+			"alias_metadata_subject_fields": &framework.FieldSchema{
+				Type: framework.TypeCommaStringSlice,
+				Description: `A comma-separated list of subject fields of the
+client certificate to copy into the alias metadata. Supported fields are
+"common_name", "organization", "organizational_unit", "country",
+"locality", "province", "street_address" and "postal_code".`,
+			},
+
+			"alias_metadata_extensions": &framework.FieldSchema{
+				Type: framework.TypeCommaStringSlice,
+				Description: `A comma-separated string or array of extension
+OIDs of the client certificate to copy into the alias metadata, formatted
+as "oid" or "oid:key". Expects the extension value to be some type of
+ASN1 encoded string. If no key is given, the OID with dots replaced by
+dashes is used.`,
+			},
+
+			"crl_refresh_interval": &framework.FieldSchema{
+				Type: framework.TypeDurationSecond,
+				Description: `Duration after which CRLs fetched from distribution
+points are refreshed, if earlier than their next update time. Defaults
+to the next update time of the CRL, or one hour if it has none.`,
+			},
 		},
 
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -185,20 +251,29 @@ func (b *backend) pathCertRead(ctx context.Context, req *logical.Request, d *fra
 
 	return &logical.Response{
 		Data: map[string]interface{}{
-			"certificate":                  cert.Certificate,
-			"display_name":                 cert.DisplayName,
-			"policies":                     cert.Policies,
-			"ttl":                          cert.TTL / time.Second,
-			"max_ttl":                      cert.MaxTTL / time.Second,
-			"period":                       cert.Period / time.Second,
-			"allowed_names":                cert.AllowedNames,
-			"allowed_common_names":         cert.AllowedCommonNames,
-			"allowed_dns_sans":             cert.AllowedDNSSANs,
-			"allowed_email_sans":           cert.AllowedEmailSANs,
-			"allowed_uri_sans":             cert.AllowedURISANs,
-			"allowed_organizational_units": cert.AllowedOrganizationalUnits,
-			"required_extensions":          cert.RequiredExtensions,
-			"bound_cidrs":                  cert.BoundCIDRs,
+			"certificate":                     cert.Certificate,
+			"display_name":                    cert.DisplayName,
+			"policies":                        cert.Policies,
+			"ttl":                             cert.TTL / time.Second,
+			"max_ttl":                         cert.MaxTTL / time.Second,
+			"period":                          cert.Period / time.Second,
+			"allowed_names":                   cert.AllowedNames,
+			"allowed_common_names":            cert.AllowedCommonNames,
+			"allowed_dns_sans":                cert.AllowedDNSSANs,
+			"allowed_email_sans":              cert.AllowedEmailSANs,
+			"allowed_uri_sans":                cert.AllowedURISANs,
+			"allowed_organizational_units":    cert.AllowedOrganizationalUnits,
+			"required_extensions":             cert.RequiredExtensions,
+			"bound_cidrs":                     cert.BoundCIDRs,
+			"ocsp_enabled":                    cert.OCSPEnabled,
+			"ocsp_servers_override":           cert.OCSPServersOverride,
+			"ocsp_fail_open":                  cert.OCSPFailOpen,
+			"crl_distribution_points_enabled": cert.CRLDistributionPointsEnabled,
+			"crl_fail_open":                   cert.CRLFailOpen,
+			"crl_refresh_interval":            cert.CRLRefreshInterval / time.Second,
+			"alias_name_source":               cert.AliasNameSource,
+			"alias_metadata_subject_fields":   cert.AliasMetadataSubjectFields,
+			"alias_metadata_extensions":       cert.AliasMetadataExtensions,
 		},
 	}, nil
 }
@@ -215,6 +290,14 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 	allowedURISANs := d.Get("allowed_uri_sans").([]string)
 	allowedOrganizationalUnits := d.Get("allowed_organizational_units").([]string)
 	requiredExtensions := d.Get("required_extensions").([]string)
+	ocspEnabled := d.Get("ocsp_enabled").(bool)
+	ocspServersOverride := d.Get("ocsp_servers_override").([]string)
+	ocspFailOpen := d.Get("ocsp_fail_open").(bool)
+	crlDistributionPointsEnabled := d.Get("crl_distribution_points_enabled").(bool)
+	crlFailOpen := d.Get("crl_fail_open").(bool)
+	aliasNameSource := d.Get("alias_name_source").(string)
+	aliasMetadataSubjectFields := d.Get("alias_metadata_subject_fields").([]string)
+	aliasMetadataExtensions := d.Get("alias_metadata_extensions").([]string)
 
 	var resp logical.Response
 
@@ -257,6 +340,33 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 		return logical.ErrorResponse("period cannot be negative"), nil
 	}
 
+	crlRefreshInterval := time.Duration(d.Get("crl_refresh_interval").(int)) * time.Second
+	if crlRefreshInterval < time.Duration(0) {
+		return logical.ErrorResponse("crl_refresh_interval cannot be negative"), nil
+	}
+
+	for _, server := range ocspServersOverride {
+		if !strings.HasPrefix(server, "http://") && !strings.HasPrefix(server, "https://") {
+			return logical.ErrorResponse(fmt.Sprintf("invalid OCSP server %q: only http and https URLs are supported", server)), nil
+		}
+	}
+
+	if _, ok := aliasNameSources[aliasNameSource]; !ok {
+		return logical.ErrorResponse(fmt.Sprintf("invalid alias_name_source %q", aliasNameSource)), nil
+	}
+
+	for _, field := range aliasMetadataSubjectFields {
+		if _, ok := aliasMetadataSubjectFieldValues[field]; !ok {
+			return logical.ErrorResponse(fmt.Sprintf("invalid subject field %q in alias_metadata_subject_fields", field)), nil
+		}
+	}
+
+	for _, ext := range aliasMetadataExtensions {
+		if _, _, err := parseAliasMetadataExtension(ext); err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+	}
+
 	// Default the display name to the certificate name if not given
 	if displayName == "" {
 		displayName = name
@@ -287,21 +397,30 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 	}
 
 	certEntry := &CertEntry{
-		Name:                       name,
-		Certificate:                certificate,
-		DisplayName:                displayName,
-		Policies:                   policies,
-		AllowedNames:               allowedNames,
-		AllowedCommonNames:         allowedCommonNames,
-		AllowedDNSSANs:             allowedDNSSANs,
-		AllowedEmailSANs:           allowedEmailSANs,
-		AllowedURISANs:             allowedURISANs,
-		AllowedOrganizationalUnits: allowedOrganizationalUnits,
-		RequiredExtensions:         requiredExtensions,
-		TTL:                        ttl,
-		MaxTTL:                     maxTTL,
-		Period:                     period,
-		BoundCIDRs:                 parsedCIDRs,
+		Name:                         name,
+		Certificate:                  certificate,
+		DisplayName:                  displayName,
+		Policies:                     policies,
+		AllowedNames:                 allowedNames,
+		AllowedCommonNames:           allowedCommonNames,
+		AllowedDNSSANs:               allowedDNSSANs,
+		AllowedEmailSANs:             allowedEmailSANs,
+		AllowedURISANs:               allowedURISANs,
+		AllowedOrganizationalUnits:   allowedOrganizationalUnits,
+		RequiredExtensions:           requiredExtensions,
+		TTL:                          ttl,
+		MaxTTL:                       maxTTL,
+		Period:                       period,
+		BoundCIDRs:                   parsedCIDRs,
+		OCSPEnabled:                  ocspEnabled,
+		OCSPServersOverride:          ocspServersOverride,
+		OCSPFailOpen:                 ocspFailOpen,
+		CRLDistributionPointsEnabled: crlDistributionPointsEnabled,
+		CRLFailOpen:                  crlFailOpen,
+		CRLRefreshInterval:           crlRefreshInterval,
+		AliasNameSource:              aliasNameSource,
+		AliasMetadataSubjectFields:   aliasMetadataSubjectFields,
+		AliasMetadataExtensions:      aliasMetadataExtensions,
 	}
 
 	// Store it
@@ -321,21 +440,30 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 }
 
 type CertEntry struct {
-	Name                       string
-	Certificate                string
-	DisplayName                string
-	Policies                   []string
-	TTL                        time.Duration
-	MaxTTL                     time.Duration
-	Period                     time.Duration
-	AllowedNames               []string
-	AllowedCommonNames         []string
-	AllowedDNSSANs             []string
-	AllowedEmailSANs           []string
-	AllowedURISANs             []string
-	AllowedOrganizationalUnits []string
-	RequiredExtensions         []string
-	BoundCIDRs                 []*sockaddr.SockAddrMarshaler
+	Name                         string
+	Certificate                  string
+	DisplayName                  string
+	Policies                     []string
+	TTL                          time.Duration
+	MaxTTL                       time.Duration
+	Period                       time.Duration
+	AllowedNames                 []string
+	AllowedCommonNames           []string
+	AllowedDNSSANs               []string
+	AllowedEmailSANs             []string
+	AllowedURISANs               []string
+	AllowedOrganizationalUnits   []string
+	RequiredExtensions           []string
+	BoundCIDRs                   []*sockaddr.SockAddrMarshaler
+	OCSPEnabled                  bool
+	OCSPServersOverride          []string
+	OCSPFailOpen                 bool
+	CRLDistributionPointsEnabled bool
+	CRLFailOpen                  bool
+	CRLRefreshInterval           time.Duration
+	AliasNameSource              string
+	AliasMetadataSubjectFields   []string
+	AliasMetadataExtensions      []string
 }
 
 const pathCertHelpSyn = `
@@ -346,6 +474,11 @@ const pathCertHelpDesc = `
 This endpoint allows you to create, read, update, and delete trusted certificates
 that are allowed to authenticate.
 
+In addition to the CRLs configured via "crls/", a certificate can be set to
+check clients against OCSP responders and against the CRLs published at the
+CRL distribution points of the client's chain. Fetched CRLs and OCSP responses
+are cached until their next update time.
+
 Deleting a certificate will not revoke auth for prior authenticated connections.
 To do this, do a revoke on "login". If you don't need to revoke login immediately,
 then the next renew will cause the lease to expire.
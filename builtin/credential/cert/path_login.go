@@ -5,22 +5,59 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/certutil"
 	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/hashicorp/vault/sdk/helper/cidrutil"
 	glob "github.com/ryanuber/go-glob"
 )
 
+const (
+	aliasNameSourceCommonName   = "common_name"
+	aliasNameSourceDNSSAN       = "dns_san"
+	aliasNameSourceEmailSAN     = "email_san"
+	aliasNameSourceURISAN       = "uri_san"
+	aliasNameSourceSerialNumber = "serial_number"
+)
+
+var aliasNameSources = map[string]struct{}{
+	aliasNameSourceCommonName:   struct{}{},
+	aliasNameSourceDNSSAN:       struct{}{},
+	aliasNameSourceEmailSAN:     struct{}{},
+	aliasNameSourceURISAN:       struct{}{},
+	aliasNameSourceSerialNumber: struct{}{},
+}
+
+// aliasMetadataSubjectFieldValues maps the subject fields that can be copied
+// into alias metadata to their values in a certificate
+var aliasMetadataSubjectFieldValues = map[string]func(pkix.Name) []string{
+	"common_name":         func(n pkix.Name) []string { return []string{n.CommonName} },
+	"organization":        func(n pkix.Name) []string { return n.Organization },
+	"organizational_unit": func(n pkix.Name) []string { return n.OrganizationalUnit },
+	"country":             func(n pkix.Name) []string { return n.Country },
+	"locality":            func(n pkix.Name) []string { return n.Locality },
+	"province":            func(n pkix.Name) []string { return n.Province },
+	"street_address":      func(n pkix.Name) []string { return n.StreetAddress },
+	"postal_code":         func(n pkix.Name) []string { return n.PostalCode },
+}
+
+// aliasMetadataKeyRegex matches keys accepted by the identity store for
+// alias metadata
+var aliasMetadataKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9=/+_-]+$`)
+
 // ParsedCert is a certificate that has been configured as trusted
 type ParsedCert struct {
 	Entry        *CertEntry
@@ -49,10 +86,28 @@ func (b *backend) pathLoginAliasLookahead(ctx context.Context, req *logical.Requ
 		return nil, fmt.Errorf("no client certificate found")
 	}
 
+	// The alias name source can only be honored if the login is constrained
+	// to a single certificate role
+	entry := &CertEntry{}
+	if certName := d.Get("name").(string); certName != "" {
+		cert, err := b.Cert(ctx, req.Storage, certName)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil {
+			entry = cert
+		}
+	}
+
+	aliasName, err := aliasNameFromCert(entry, clientCerts[0])
+	if err != nil {
+		return nil, err
+	}
+
 	return &logical.Response{
 		Auth: &logical.Auth{
 			Alias: &logical.Alias{
-				Name: clientCerts[0].Subject.CommonName,
+				Name: aliasName,
 			},
 		},
 	}, nil
@@ -83,6 +138,11 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, data *fra
 	skid := base64.StdEncoding.EncodeToString(clientCerts[0].SubjectKeyId)
 	akid := base64.StdEncoding.EncodeToString(clientCerts[0].AuthorityKeyId)
 
+	aliasName, err := aliasNameFromCert(matched.Entry, clientCerts[0])
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
 	resp := &logical.Response{
 		Auth: &logical.Auth{
 			Period: matched.Entry.Period,
@@ -105,7 +165,8 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, data *fra
 				MaxTTL:    matched.Entry.MaxTTL,
 			},
 			Alias: &logical.Alias{
-				Name: clientCerts[0].Subject.CommonName,
+				Name:     aliasName,
+				Metadata: aliasMetadataFromCert(matched.Entry, clientCerts[0]),
 			},
 			BoundCIDRs: matched.Entry.BoundCIDRs,
 		},
@@ -200,6 +261,9 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, d
 		return nil, nil, err
 	}
 
+	// Revocation failures are reported if no other entry matches
+	var revocationErr error
+
 	// If trustedNonCAs is not empty it means that client had registered a non-CA cert
 	// with the backend.
 	if len(trustedNonCAs) != 0 {
@@ -209,6 +273,10 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, d
 			if tCert.SerialNumber.Cmp(clientCert.SerialNumber) == 0 &&
 				bytes.Equal(tCert.AuthorityKeyId, clientCert.AuthorityKeyId) &&
 				b.matchesConstraints(clientCert, trustedNonCA.Certificates, trustedNonCA) {
+				if err := b.checkRevocation(ctx, trustedNonCA.Entry, nonCAChain(clientCert, connState.PeerCertificates)); err != nil {
+					revocationErr = err
+					continue
+				}
 				return trustedNonCA, nil, nil
 			}
 		}
@@ -217,6 +285,9 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, d
 	// If no trusted chain was found, client is not authenticated
 	// This check happens after checking for a matching configured non-CA certs
 	if len(trustedChains) == 0 {
+		if revocationErr != nil {
+			return nil, logical.ErrorResponse(fmt.Sprintf("certificate failed revocation checks: %v", revocationErr)), nil
+		}
 		return nil, logical.ErrorResponse("invalid certificate or no client certificate supplied"), nil
 	}
 
@@ -228,6 +299,11 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, d
 				for _, cCert := range chain { // For each cert in the matched chain
 					if tCert.Equal(cCert) && // ParsedCert intersects with matched chain
 						b.matchesConstraints(clientCert, chain, trust) { // validate client cert + matched chain against the config
+						// Check the chain against the revocation sources of the config
+						if err := b.checkRevocation(ctx, trust.Entry, chain); err != nil {
+							revocationErr = err
+							continue
+						}
 						// Add the match to the list
 						matches = append(matches, trust)
 					}
@@ -238,6 +314,9 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, d
 
 	// Fail on no matches
 	if len(matches) == 0 {
+		if revocationErr != nil {
+			return nil, logical.ErrorResponse(fmt.Sprintf("certificate failed revocation checks: %v", revocationErr)), nil
+		}
 		return nil, logical.ErrorResponse("no chain matching all constraints could be found for this login certificate"), nil
 	}
 
@@ -391,6 +470,21 @@ func (b *backend) matchesCertificateExtensions(clientCert *x509.Certificate, con
 	}
 
 	// Build Client Extensions Map for Constraint Matching
+	clientExtMap := certificateExtensionValues(clientCert)
+	// If any of the required extensions don't match the constraint fails
+	for _, requiredExt := range config.Entry.RequiredExtensions {
+		reqExt := strings.SplitN(requiredExt, ":", 2)
+		clientExtValue, clientExtValueOk := clientExtMap[reqExt[0]]
+		if !clientExtValueOk || !glob.Glob(reqExt[1], clientExtValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// certificateExtensionValues returns the values of the certificate's
+// extensions keyed by OID.
+func certificateExtensionValues(clientCert *x509.Certificate) map[string]string {
 	// x509 Writes Extensions in ASN1 with a bitstring tag, which results in the field
 	// including its ASN.1 type tag bytes. For the sake of simplicity, assume string type
 	// and drop the tag bytes. And get the number of bytes from the tag.
@@ -400,15 +494,94 @@ func (b *backend) matchesCertificateExtensions(clientCert *x509.Certificate, con
 		asn1.Unmarshal(ext.Value, &parsedValue)
 		clientExtMap[ext.Id.String()] = parsedValue
 	}
-	// If any of the required extensions don't match the constraint fails
-	for _, requiredExt := range config.Entry.RequiredExtensions {
-		reqExt := strings.SplitN(requiredExt, ":", 2)
-		clientExtValue, clientExtValueOk := clientExtMap[reqExt[0]]
-		if !clientExtValueOk || !glob.Glob(reqExt[1], clientExtValue) {
-			return false
+	return clientExtMap
+}
+
+// aliasNameFromCert returns the identity alias name for the client
+// certificate based on the alias name source of the entry
+func aliasNameFromCert(entry *CertEntry, clientCert *x509.Certificate) (string, error) {
+	switch entry.AliasNameSource {
+	case "", aliasNameSourceCommonName:
+		return clientCert.Subject.CommonName, nil
+	case aliasNameSourceDNSSAN:
+		if len(clientCert.DNSNames) == 0 {
+			return "", fmt.Errorf("client certificate has no DNS SAN to use as alias name")
+		}
+		return clientCert.DNSNames[0], nil
+	case aliasNameSourceEmailSAN:
+		if len(clientCert.EmailAddresses) == 0 {
+			return "", fmt.Errorf("client certificate has no email SAN to use as alias name")
+		}
+		return clientCert.EmailAddresses[0], nil
+	case aliasNameSourceURISAN:
+		if len(clientCert.URIs) == 0 {
+			return "", fmt.Errorf("client certificate has no URI SAN to use as alias name")
 		}
+		return clientCert.URIs[0].String(), nil
+	case aliasNameSourceSerialNumber:
+		return certutil.GetHexFormatted(clientCert.SerialNumber.Bytes(), ":"), nil
+	default:
+		return "", fmt.Errorf("unknown alias name source %q", entry.AliasNameSource)
 	}
-	return true
+}
+
+// aliasMetadataFromCert copies the subject fields and extensions configured
+// on the entry from the client certificate into alias metadata. Fields that
+// are absent from the certificate are skipped.
+func aliasMetadataFromCert(entry *CertEntry, clientCert *x509.Certificate) map[string]string {
+	if len(entry.AliasMetadataSubjectFields) == 0 && len(entry.AliasMetadataExtensions) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string)
+	for _, field := range entry.AliasMetadataSubjectFields {
+		valueFunc, ok := aliasMetadataSubjectFieldValues[field]
+		if !ok {
+			continue
+		}
+		values := strutil.RemoveEmpty(valueFunc(clientCert.Subject))
+		if len(values) == 0 {
+			continue
+		}
+		metadata[field] = strings.Join(values, ",")
+	}
+
+	if len(entry.AliasMetadataExtensions) != 0 {
+		clientExtMap := certificateExtensionValues(clientCert)
+		for _, ext := range entry.AliasMetadataExtensions {
+			oid, key, err := parseAliasMetadataExtension(ext)
+			if err != nil {
+				continue
+			}
+			if value, ok := clientExtMap[oid]; ok && value != "" {
+				metadata[key] = value
+			}
+		}
+	}
+
+	return metadata
+}
+
+// parseAliasMetadataExtension parses an "oid" or "oid:key" alias metadata
+// extension and returns the OID and the metadata key to store its value under
+func parseAliasMetadataExtension(ext string) (string, string, error) {
+	parts := strings.SplitN(ext, ":", 2)
+	oid := parts[0]
+	for _, arc := range strings.Split(oid, ".") {
+		if _, err := strconv.ParseUint(arc, 10, 32); err != nil {
+			return "", "", fmt.Errorf("invalid OID %q in alias_metadata_extensions", oid)
+		}
+	}
+
+	key := strings.Replace(oid, ".", "-", -1)
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+	if !aliasMetadataKeyRegex.MatchString(key) {
+		return "", "", fmt.Errorf("invalid metadata key %q in alias_metadata_extensions", key)
+	}
+
+	return oid, key, nil
 }
 
 // loadTrustedCerts is used to load all the trusted certificates from the backend
@@ -468,6 +641,42 @@ func (b *backend) checkForChainInCRLs(chain []*x509.Certificate) bool {
 	return badChain
 }
 
+// checkRevocation checks the chain against the OCSP responders and CRL
+// distribution points if the entry enables them. The chain starts with the
+// client certificate, followed by its issuers.
+func (b *backend) checkRevocation(ctx context.Context, entry *CertEntry, chain []*x509.Certificate) error {
+	if entry.OCSPEnabled {
+		var issuer *x509.Certificate
+		if len(chain) > 1 {
+			issuer = chain[1]
+		}
+		if err := b.checkOCSP(ctx, entry, chain[0], issuer); err != nil {
+			return err
+		}
+	}
+
+	if entry.CRLDistributionPointsEnabled {
+		if err := b.checkCRLDistributionPoints(ctx, entry, chain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nonCAChain builds the chain of a trusted non-CA client certificate from the
+// certificates presented by the client, as it isn't verified against a pool.
+func nonCAChain(clientCert *x509.Certificate, peerCerts []*x509.Certificate) []*x509.Certificate {
+	chain := []*x509.Certificate{clientCert}
+	for _, cert := range peerCerts[1:] {
+		if clientCert.CheckSignatureFrom(cert) == nil {
+			chain = append(chain, cert)
+			break
+		}
+	}
+	return chain
+}
+
 func (b *backend) checkForValidChain(chains [][]*x509.Certificate) bool {
 	for _, chain := range chains {
 		if !b.checkForChainInCRLs(chain) {
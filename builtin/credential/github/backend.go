@@ -2,8 +2,11 @@ package github
 
 import (
 	"context"
+	"net/url"
+	"sync"
 
 	"github.com/google/go-github/github"
+	"github.com/hashicorp/errwrap"
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/vault/helper/mfa"
 	"github.com/hashicorp/vault/sdk/framework"
@@ -50,9 +53,12 @@ func Backend() *backend {
 			pathConfig(&b),
 		}, append(allPaths, mfa.MFAPaths(b.Backend, pathLogin(&b))...)...),
 		AuthRenew:   b.pathLoginRenew,
+		Invalidate:  b.invalidate,
 		BackendType: logical.TypeCredential,
 	}
 
+	b.membershipCache = make(map[string]*membershipCacheEntry)
+
 	return &b
 }
 
@@ -62,6 +68,18 @@ type backend struct {
 	TeamMap *framework.PolicyMap
 
 	UserMap *framework.PolicyMap
+
+	// membershipCache holds the GitHub user, organization and teams looked
+	// up for a token, keyed by the hash of the token
+	membershipCache     map[string]*membershipCacheEntry
+	membershipCacheLock sync.RWMutex
+}
+
+func (b *backend) invalidate(_ context.Context, key string) {
+	switch key {
+	case "config":
+		b.resetMembershipCache()
+	}
 }
 
 // Client returns the GitHub client to communicate to GitHub via the
@@ -76,6 +94,25 @@ func (b *backend) Client(token string) (*github.Client, error) {
 	return github.NewClient(tc), nil
 }
 
+// configuredClient returns a GitHub client for the token that talks to the
+// API endpoint of the given configuration.
+func (b *backend) configuredClient(token string, config *config) (*github.Client, error) {
+	client, err := b.Client(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.BaseURL != "" {
+		parsedURL, err := url.Parse(config.BaseURL)
+		if err != nil {
+			return nil, errwrap.Wrapf("successfully parsed base_url when set but failing to parse now: {{err}}", err)
+		}
+		client.BaseURL = parsedURL
+	}
+
+	return client, nil
+}
+
 // tokenSource is an oauth2.TokenSource implementation.
 type tokenSource struct {
 	Value string
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// membership is the user, organization and organization teams looked up
+// from GitHub for a token
+type membership struct {
+	User  *github.User
+	Org   *github.Organization
+	Teams []*github.Team
+}
+
+type membershipCacheEntry struct {
+	membership *membership
+	expiration time.Time
+}
+
+// membershipCacheKey hashes the token so that it isn't kept in memory
+func membershipCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedMembership returns the membership cached for the token, or nil if
+// there is none or it has expired
+func (b *backend) cachedMembership(token string) *membership {
+	b.membershipCacheLock.RLock()
+	defer b.membershipCacheLock.RUnlock()
+
+	entry, ok := b.membershipCache[membershipCacheKey(token)]
+	if !ok || time.Now().After(entry.expiration) {
+		return nil
+	}
+	return entry.membership
+}
+
+// cacheMembership caches the membership of the token for the given TTL.
+// Expired entries are dropped at the same time.
+func (b *backend) cacheMembership(token string, m *membership, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	b.membershipCacheLock.Lock()
+	defer b.membershipCacheLock.Unlock()
+
+	now := time.Now()
+	for key, entry := range b.membershipCache {
+		if now.After(entry.expiration) {
+			delete(b.membershipCache, key)
+		}
+	}
+
+	b.membershipCache[membershipCacheKey(token)] = &membershipCacheEntry{
+		membership: m,
+		expiration: now.Add(ttl),
+	}
+}
+
+func (b *backend) resetMembershipCache() {
+	b.membershipCacheLock.Lock()
+	defer b.membershipCacheLock.Unlock()
+
+	b.membershipCache = make(map[string]*membershipCacheEntry)
+}
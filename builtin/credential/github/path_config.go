@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/errwrap"
@@ -20,6 +21,41 @@ func pathConfig(b *backend) *framework.Path {
 				Description: "The organization users must be part of",
 			},
 
+			"organization_id": &framework.FieldSchema{
+				Type: framework.TypeInt,
+				Description: `The ID of the organization users must be part of.
+Looked up from the organization name if not set. Users are matched
+against the ID so that renaming the organization doesn't grant access
+to an organization that takes over its name.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name:  "Organization ID",
+					Group: "GitHub Options",
+				},
+			},
+
+			"team_mapping_by": &framework.FieldSchema{
+				Type:    framework.TypeString,
+				Default: teamMappingByName,
+				Description: `How teams are identified in "map/teams" and in
+group aliases. "name" matches both the team name and slug, "slug" only
+the slug and "id" only the numeric team ID. Defaults to "name".`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name:  "Team Mapping By",
+					Group: "GitHub Options",
+				},
+			},
+
+			"membership_cache_ttl": &framework.FieldSchema{
+				Type: framework.TypeDurationSecond,
+				Description: `Duration for which the user, organization and
+team memberships looked up for a token are cached, to avoid GitHub API
+rate limits. Defaults to 0, which disables caching.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name:  "Membership Cache TTL",
+					Group: "GitHub Options",
+				},
+			},
+
 			"base_url": &framework.FieldSchema{
 				Type: framework.TypeString,
 				Description: `The API endpoint to use. Useful if you
@@ -54,13 +90,31 @@ API-compatible authentication server.`,
 }
 
 func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	var resp logical.Response
+
 	organization := data.Get("organization").(string)
 	baseURL := data.Get("base_url").(string)
 	if len(baseURL) != 0 {
-		_, err := url.Parse(baseURL)
+		parsedURL, err := parseBaseURL(baseURL)
 		if err != nil {
 			return logical.ErrorResponse(fmt.Sprintf("Error parsing given base_url: %s", err)), nil
 		}
+		if parsedURL.Host != "api.github.com" && !strings.HasSuffix(parsedURL.Path, "/api/v3/") {
+			resp.AddWarning(`GitHub Enterprise API endpoints usually end with "/api/v3/"`)
+		}
+		baseURL = parsedURL.String()
+	}
+
+	teamMappingBy := data.Get("team_mapping_by").(string)
+	switch teamMappingBy {
+	case teamMappingByName, teamMappingBySlug, teamMappingByID:
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("invalid team_mapping_by %q", teamMappingBy)), nil
+	}
+
+	membershipCacheTTL := time.Duration(data.Get("membership_cache_ttl").(int)) * time.Second
+	if membershipCacheTTL < 0 {
+		return logical.ErrorResponse("membership_cache_ttl cannot be negative"), nil
 	}
 
 	var ttl time.Duration
@@ -86,12 +140,31 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, dat
 		}
 	}
 
-	entry, err := logical.StorageEntryJSON("config", config{
-		Organization: organization,
-		BaseURL:      baseURL,
-		TTL:          ttl,
-		MaxTTL:       maxTTL,
-	})
+	c := &config{
+		Organization:       organization,
+		OrganizationID:     int64(data.Get("organization_id").(int)),
+		BaseURL:            baseURL,
+		TTL:                ttl,
+		MaxTTL:             maxTTL,
+		TeamMappingBy:      teamMappingBy,
+		MembershipCacheTTL: membershipCacheTTL,
+	}
+
+	// Pin the organization by its ID, which doesn't change when the
+	// organization is renamed
+	if c.OrganizationID == 0 && c.Organization != "" {
+		client, err := b.configuredClient("", c)
+		if err != nil {
+			return nil, err
+		}
+		org, _, err := client.Organizations.Get(ctx, c.Organization)
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("unable to fetch the organization_id, you must manually set it in the config: %s", err)), nil
+		}
+		c.OrganizationID = org.GetID()
+	}
+
+	entry, err := logical.StorageEntryJSON("config", c)
 
 	if err != nil {
 		return nil, err
@@ -101,7 +174,13 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, dat
 		return nil, err
 	}
 
-	return nil, nil
+	b.resetMembershipCache()
+
+	if len(resp.Warnings) == 0 {
+		return nil, nil
+	}
+
+	return &resp, nil
 }
 
 func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
@@ -116,13 +195,17 @@ func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, data
 
 	config.TTL /= time.Second
 	config.MaxTTL /= time.Second
+	config.MembershipCacheTTL /= time.Second
 
 	resp := &logical.Response{
 		Data: map[string]interface{}{
-			"organization": config.Organization,
-			"base_url":     config.BaseURL,
-			"ttl":          config.TTL,
-			"max_ttl":      config.MaxTTL,
+			"organization":         config.Organization,
+			"organization_id":      config.OrganizationID,
+			"base_url":             config.BaseURL,
+			"ttl":                  config.TTL,
+			"max_ttl":              config.MaxTTL,
+			"team_mapping_by":      config.TeamMappingBy,
+			"membership_cache_ttl": config.MembershipCacheTTL,
 		},
 	}
 	return resp, nil
@@ -142,12 +225,51 @@ func (b *backend) Config(ctx context.Context, s logical.Storage) (*config, error
 		}
 	}
 
+	if result.TeamMappingBy == "" {
+		result.TeamMappingBy = teamMappingByName
+	}
+
 	return &result, nil
 }
 
+// parseBaseURL validates the given API endpoint and adds the trailing slash
+// that the GitHub client relies on to resolve API paths against it.
+func parseBaseURL(baseURL string) (*url.URL, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case parsedURL.Scheme != "https" && parsedURL.Scheme != "http":
+		return nil, fmt.Errorf("scheme must be https or http")
+	case parsedURL.Host == "":
+		return nil, fmt.Errorf("host is missing")
+	case parsedURL.User != nil:
+		return nil, fmt.Errorf("credentials are not allowed")
+	case parsedURL.RawQuery != "" || parsedURL.Fragment != "":
+		return nil, fmt.Errorf("query and fragment are not allowed")
+	}
+
+	if !strings.HasSuffix(parsedURL.Path, "/") {
+		parsedURL.Path += "/"
+	}
+
+	return parsedURL, nil
+}
+
+const (
+	teamMappingByName = "name"
+	teamMappingBySlug = "slug"
+	teamMappingByID   = "id"
+)
+
 type config struct {
-	Organization string        `json:"organization" structs:"organization" mapstructure:"organization"`
-	BaseURL      string        `json:"base_url" structs:"base_url" mapstructure:"base_url"`
-	TTL          time.Duration `json:"ttl" structs:"ttl" mapstructure:"ttl"`
-	MaxTTL       time.Duration `json:"max_ttl" structs:"max_ttl" mapstructure:"max_ttl"`
+	Organization       string        `json:"organization" structs:"organization" mapstructure:"organization"`
+	OrganizationID     int64         `json:"organization_id" structs:"organization_id" mapstructure:"organization_id"`
+	BaseURL            string        `json:"base_url" structs:"base_url" mapstructure:"base_url"`
+	TTL                time.Duration `json:"ttl" structs:"ttl" mapstructure:"ttl"`
+	MaxTTL             time.Duration `json:"max_ttl" structs:"max_ttl" mapstructure:"max_ttl"`
+	TeamMappingBy      string        `json:"team_mapping_by" structs:"team_mapping_by" mapstructure:"team_mapping_by"`
+	MembershipCacheTTL time.Duration `json:"membership_cache_ttl" structs:"membership_cache_ttl" mapstructure:"membership_cache_ttl"`
 }
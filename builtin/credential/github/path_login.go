@@ -3,11 +3,10 @@ package github
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/google/go-github/github"
-	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/policyutil"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -156,17 +155,61 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, t
 			"configure the github credential backend first"), nil
 	}
 
-	client, err := b.Client(token)
+	membership := b.cachedMembership(token)
+	if membership == nil {
+		var resp *logical.Response
+		membership, resp, err = b.fetchMembership(ctx, config, token)
+		if err != nil || resp != nil {
+			return nil, resp, err
+		}
+		b.cacheMembership(token, membership, config.MembershipCacheTTL)
+	}
+
+	// Get the identifiers of the teams that this user is part of to
+	// determine the policies
+	var teamNames []string
+	for _, t := range membership.Teams {
+		switch config.TeamMappingBy {
+		case teamMappingBySlug:
+			teamNames = append(teamNames, t.GetSlug())
+		case teamMappingByID:
+			teamNames = append(teamNames, strconv.FormatInt(t.GetID(), 10))
+		default:
+			// Append the names so we can get the policies
+			teamNames = append(teamNames, t.GetName())
+			if t.GetName() != t.GetSlug() {
+				teamNames = append(teamNames, t.GetSlug())
+			}
+		}
+	}
+
+	groupPoliciesList, err := b.TeamMap.Policies(ctx, req.Storage, teamNames...)
+
 	if err != nil {
 		return nil, nil, err
 	}
 
-	if config.BaseURL != "" {
-		parsedURL, err := url.Parse(config.BaseURL)
-		if err != nil {
-			return nil, nil, errwrap.Wrapf("successfully parsed base_url when set but failing to parse now: {{err}}", err)
-		}
-		client.BaseURL = parsedURL
+	userPoliciesList, err := b.UserMap.Policies(ctx, req.Storage, []string{*membership.User.Login}...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &verifyCredentialsResp{
+		User:      membership.User,
+		Org:       membership.Org,
+		Policies:  append(groupPoliciesList, userPoliciesList...),
+		TeamNames: teamNames,
+	}, nil, nil
+}
+
+// fetchMembership looks up the user owning the token, verifies that it is
+// part of the configured organization and lists its teams in that
+// organization.
+func (b *backend) fetchMembership(ctx context.Context, config *config, token string) (*membership, *logical.Response, error) {
+	client, err := b.configuredClient(token, config)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Get the user
@@ -196,6 +239,15 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, t
 	}
 
 	for _, o := range allOrgs {
+		// Configurations written before the organization ID was pinned can
+		// only be matched by name
+		if config.OrganizationID != 0 {
+			if o.GetID() == config.OrganizationID {
+				org = o
+				break
+			}
+			continue
+		}
 		if strings.EqualFold(*o.Login, config.Organization) {
 			org = o
 			break
@@ -205,9 +257,6 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, t
 		return nil, logical.ErrorResponse("user is not part of required org"), nil
 	}
 
-	// Get the teams that this user is part of to determine the policies
-	var teamNames []string
-
 	teamOpt := &github.ListOptions{
 		PerPage: 100,
 	}
@@ -225,36 +274,19 @@ func (b *backend) verifyCredentials(ctx context.Context, req *logical.Request, t
 		teamOpt.Page = resp.NextPage
 	}
 
+	var orgTeams []*github.Team
 	for _, t := range allTeams {
 		// We only care about teams that are part of the organization we use
-		if *t.Organization.ID != *org.ID {
+		if t.GetOrganization().GetID() != org.GetID() {
 			continue
 		}
-
-		// Append the names so we can get the policies
-		teamNames = append(teamNames, *t.Name)
-		if *t.Name != *t.Slug {
-			teamNames = append(teamNames, *t.Slug)
-		}
-	}
-
-	groupPoliciesList, err := b.TeamMap.Policies(ctx, req.Storage, teamNames...)
-
-	if err != nil {
-		return nil, nil, err
+		orgTeams = append(orgTeams, t)
 	}
 
-	userPoliciesList, err := b.UserMap.Policies(ctx, req.Storage, []string{*user.Login}...)
-
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return &verifyCredentialsResp{
-		User:      user,
-		Org:       org,
-		Policies:  append(groupPoliciesList, userPoliciesList...),
-		TeamNames: teamNames,
+	return &membership{
+		User:  user,
+		Org:   org,
+		Teams: orgTeams,
 	}, nil, nil
 }
 
@@ -1,11 +1,15 @@
 package radius
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
 	"fmt"
+	"net"
 	"os"
 	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +17,8 @@ import (
 	logicaltest "github.com/hashicorp/vault/helper/testhelpers/logical"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/ory/dockertest"
+	"layeh.com/radius"
+	. "layeh.com/radius/rfc2865"
 )
 
 const (
@@ -322,3 +328,293 @@ func testAccUserLoginPolicy(t *testing.T, user string, data map[string]interface
 		},
 	}
 }
+
+type testRadiusServer struct {
+	conn   net.PacketConn
+	secret []byte
+
+	// l guards the fields below, which tests change between logins
+	l sync.Mutex
+
+	username string
+	password string
+
+	// replyAttributes are added to every Access-Accept
+	replyAttributes radius.Attributes
+
+	// messageAuthenticator controls the Message-Authenticator of responses;
+	// one of "valid", "none" or "invalid"
+	messageAuthenticator string
+
+	// badAuthenticatorResponse makes MS-CHAPv2 accepts carry a wrong
+	// authenticator response
+	badAuthenticatorResponse bool
+}
+
+func newTestRadiusServer(t *testing.T, secret, username, password string) *testRadiusServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testRadiusServer{
+		conn:                 conn,
+		secret:               []byte(secret),
+		username:             username,
+		password:             password,
+		replyAttributes:      make(radius.Attributes),
+		messageAuthenticator: "valid",
+	}
+	go s.serve(t)
+	return s
+}
+
+func (s *testRadiusServer) update(f func()) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	f()
+}
+
+func (s *testRadiusServer) Close() {
+	s.conn.Close()
+}
+
+func (s *testRadiusServer) hostPort() (string, int) {
+	addr := s.conn.LocalAddr().(*net.UDPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func (s *testRadiusServer) serve(t *testing.T) {
+	var buf [radius.MaxPacketLength]byte
+	for {
+		n, addr, err := s.conn.ReadFrom(buf[:])
+		if err != nil {
+			return
+		}
+		raw := buf[:n]
+
+		request, err := radius.Parse(raw, s.secret)
+		if err != nil {
+			t.Errorf("error parsing request: %s", err)
+			continue
+		}
+		// Requests must always be signed
+		if messageAuthenticatorOffset(raw) == -1 {
+			t.Error("request has no Message-Authenticator")
+			continue
+		}
+		if err := verifyMessageAuthenticator(raw, raw[4:20], s.secret, true); err != nil {
+			t.Errorf("request has a bad Message-Authenticator: %s", err)
+			continue
+		}
+
+		s.l.Lock()
+		response := s.handle(request)
+		if s.messageAuthenticator != "none" {
+			response.Set(attributeTypeMessageAuthenticator, make(radius.Attribute, md5.Size))
+			wire, err := response.Encode()
+			if err != nil {
+				s.l.Unlock()
+				t.Error(err)
+				continue
+			}
+			mac := messageAuthenticator(wire, messageAuthenticatorOffset(wire), request.Authenticator[:], s.secret)
+			if s.messageAuthenticator == "invalid" {
+				mac[0] ^= 0xff
+			}
+			response.Set(attributeTypeMessageAuthenticator, mac)
+		}
+		s.l.Unlock()
+		wire, err := response.Encode()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		s.conn.WriteTo(wire, addr)
+	}
+}
+
+func (s *testRadiusServer) handle(request *radius.Packet) *radius.Packet {
+	reject := request.Response(radius.CodeAccessReject)
+	if UserName_GetString(request) != s.username {
+		return reject
+	}
+
+	accept := request.Response(radius.CodeAccessAccept)
+	for typ, attrs := range s.replyAttributes {
+		for _, attr := range attrs {
+			accept.Add(typ, attr)
+		}
+	}
+
+	switch {
+	case request.Get(attributeTypeCHAPPassword) != nil:
+		chapPassword := request.Get(attributeTypeCHAPPassword)
+		challenge := request.Get(attributeTypeCHAPChallenge)
+		if len(chapPassword) != 17 || !bytes.Equal(chapPassword[1:], chapResponse(chapPassword[0], s.password, challenge)) {
+			return reject
+		}
+		return accept
+
+	case request.Get(attributeTypeVendorSpecific) != nil:
+		authenticatorChallenge, _ := lookupMicrosoftAttribute(request, msCHAPChallenge)
+		response, ok := lookupMicrosoftAttribute(request, msCHAP2Response)
+		if !ok || len(response) != 50 {
+			return reject
+		}
+		peerChallenge, ntResponse := response[2:18], response[26:50]
+		expected, err := mschapV2NTResponse(authenticatorChallenge, peerChallenge, s.username, s.password)
+		if err != nil || !bytes.Equal(expected, ntResponse) {
+			return reject
+		}
+		authResponse := mschapV2AuthenticatorResponse(authenticatorChallenge, peerChallenge, ntResponse, s.username, s.password)
+		if s.badAuthenticatorResponse {
+			authResponse = mschapV2AuthenticatorResponse(authenticatorChallenge, peerChallenge, ntResponse, s.username, "wrong")
+		}
+		accept.Add(attributeTypeVendorSpecific, microsoftAttribute(msCHAP2Success, append([]byte{response[0]}, authResponse...)))
+		return accept
+
+	default:
+		if UserPassword_GetString(request) != s.password {
+			return reject
+		}
+		return accept
+	}
+}
+
+func TestBackend_login(t *testing.T) {
+	storage := &logical.InmemStorage{}
+	b, err := Factory(context.Background(), &logical.BackendConfig{
+		Logger: nil,
+		System: &logical.StaticSystemView{
+			DefaultLeaseTTLVal: testSysTTL,
+			MaxLeaseTTLVal:     testSysMaxTTL,
+		},
+		StorageView: storage,
+	})
+	if err != nil {
+		t.Fatalf("Unable to create backend: %s", err)
+	}
+
+	server := newTestRadiusServer(t, "test-secret", "alice", "clientPass")
+	defer server.Close()
+	server.update(func() {
+		server.replyAttributes.Add(mappableAttributes["Class"], radius.Attribute("ops, dev"))
+		server.replyAttributes.Add(mappableAttributes["Class"], radius.Attribute("root"))
+		server.replyAttributes.Add(mappableAttributes["Filter-Id"], radius.Attribute("vpn-users"))
+	})
+	host, port := server.hostPort()
+
+	// Find a port nothing listens on for failover
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.LocalAddr().(*net.UDPAddr).Port
+	closed.Close()
+
+	writeConfig := func(data map[string]interface{}) {
+		t.Helper()
+		config := map[string]interface{}{
+			"host":                       host,
+			"port":                       port,
+			"secret":                     "test-secret",
+			"unregistered_user_policies": "unregistered",
+			"read_timeout":               2,
+		}
+		for k, v := range data {
+			config[k] = v
+		}
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "config",
+			Storage:   storage,
+			Data:      config,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+		}
+	}
+
+	login := func(username, password string) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "login/" + username,
+			Storage:   storage,
+			Data: map[string]interface{}{
+				"password": password,
+			},
+			Connection: &logical.Connection{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	for _, authType := range []string{authTypePAP, authTypeCHAP, authTypeMSCHAPv2} {
+		writeConfig(map[string]interface{}{
+			"auth_type": authType,
+		})
+
+		if resp := login("alice", "clientPass"); resp == nil || resp.IsError() || resp.Auth == nil {
+			t.Fatalf("%s: expected successful login, got %#v", authType, resp)
+		}
+		if resp := login("alice", "wrong"); resp == nil || !resp.IsError() {
+			t.Fatalf("%s: expected login with wrong password to fail, got %#v", authType, resp)
+		}
+	}
+
+	// An MS-CHAPv2 accept must prove the server knows the password
+	server.update(func() { server.badAuthenticatorResponse = true })
+	if resp := login("alice", "clientPass"); resp == nil || !resp.IsError() {
+		t.Fatalf("expected login with bad authenticator response to fail, got %#v", resp)
+	}
+	server.update(func() { server.badAuthenticatorResponse = false })
+
+	// Attribute mapping
+	writeConfig(map[string]interface{}{
+		"policy_attributes":         "Class",
+		"alias_metadata_attributes": "Class,Filter-Id",
+	})
+	resp := login("alice", "clientPass")
+	if resp == nil || resp.IsError() || resp.Auth == nil {
+		t.Fatalf("expected successful login, got %#v", resp)
+	}
+	if expected := []string{"dev", "ops", "unregistered"}; !reflect.DeepEqual(resp.Auth.Policies, expected) {
+		t.Fatalf("expected policies %v, got %v", expected, resp.Auth.Policies)
+	}
+	expectedMetadata := map[string]string{
+		"class":     "ops, dev,root",
+		"filter_id": "vpn-users",
+	}
+	if !reflect.DeepEqual(resp.Auth.Alias.Metadata, expectedMetadata) {
+		t.Fatalf("expected alias metadata %v, got %v", expectedMetadata, resp.Auth.Alias.Metadata)
+	}
+
+	// Message-Authenticator validation
+	server.update(func() { server.messageAuthenticator = "invalid" })
+	if resp := login("alice", "clientPass"); resp == nil || !resp.IsError() {
+		t.Fatalf("expected login with invalid Message-Authenticator to fail, got %#v", resp)
+	}
+	server.update(func() { server.messageAuthenticator = "none" })
+	if resp := login("alice", "clientPass"); resp == nil || resp.IsError() {
+		t.Fatalf("expected login without Message-Authenticator to succeed, got %#v", resp)
+	}
+	writeConfig(map[string]interface{}{
+		"require_message_authenticator": true,
+	})
+	if resp := login("alice", "clientPass"); resp == nil || !resp.IsError() {
+		t.Fatalf("expected login without required Message-Authenticator to fail, got %#v", resp)
+	}
+	server.update(func() { server.messageAuthenticator = "valid" })
+
+	// Failover to the next server when the primary can't be reached
+	writeConfig(map[string]interface{}{
+		"port":           closedPort,
+		"failover_hosts": fmt.Sprintf("%s:%d", host, port),
+	})
+	if resp := login("alice", "clientPass"); resp == nil || resp.IsError() {
+		t.Fatalf("expected login to fail over, got %#v", resp)
+	}
+}
@@ -0,0 +1,215 @@
+package radius
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"layeh.com/radius"
+)
+
+const (
+	// attributeTypeMessageAuthenticator is the Message-Authenticator
+	// attribute of RFC 3579
+	attributeTypeMessageAuthenticator radius.Type = 80
+
+	attributeTypeCHAPPassword   radius.Type = 3
+	attributeTypeCHAPChallenge  radius.Type = 60
+	attributeTypeVendorSpecific radius.Type = 26
+	vendorIDMicrosoft                       = 311
+
+	// Microsoft vendor-specific attributes of RFC 2548
+	msCHAPChallenge byte = 11
+	msCHAP2Response byte = 25
+	msCHAP2Success  byte = 26
+)
+
+// mappableAttributes are the response attributes with textual values that
+// can be mapped to policies and alias metadata
+var mappableAttributes = map[string]radius.Type{
+	"Class":         25,
+	"Filter-Id":     11,
+	"Reply-Message": 18,
+	"Callback-Id":   20,
+}
+
+func mappableAttributeNames() string {
+	names := make([]string, 0, len(mappableAttributes))
+	for name := range mappableAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// messageAuthenticatorError is returned when a response fails the
+// Message-Authenticator check. Unlike transport errors it doesn't cause
+// failover to the next server.
+type messageAuthenticatorError struct {
+	reason string
+}
+
+func (e *messageAuthenticatorError) Error() string {
+	return e.reason
+}
+
+// exchange sends the request to the RADIUS server at addr and waits for an
+// authentic response to it. The request is signed with a Message-Authenticator,
+// which is also verified on the response if present.
+func exchange(ctx context.Context, dialTimeout time.Duration, packet *radius.Packet, addr string, requireMessageAuthenticator bool) (*radius.Packet, error) {
+	packet.Set(attributeTypeMessageAuthenticator, make(radius.Attribute, md5.Size))
+	wire, err := packet.Encode()
+	if err != nil {
+		return nil, err
+	}
+	if err := signMessageAuthenticator(wire, packet.Authenticator[:], packet.Secret); err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{
+		Timeout: dialTimeout,
+	}
+	conn, err := dialer.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(wire); err != nil {
+		return nil, err
+	}
+
+	var incoming [radius.MaxPacketLength]byte
+	for {
+		n, err := conn.Read(incoming[:])
+		if err != nil {
+			return nil, err
+		}
+		raw := incoming[:n]
+
+		// Drop anything that isn't a response to this request
+		if len(raw) < 20 || raw[1] != wire[1] || !radius.IsAuthenticResponse(raw, wire, packet.Secret) {
+			continue
+		}
+
+		received, err := radius.Parse(raw, packet.Secret)
+		if err != nil {
+			continue
+		}
+
+		if err := verifyMessageAuthenticator(raw, packet.Authenticator[:], packet.Secret, requireMessageAuthenticator); err != nil {
+			return nil, err
+		}
+
+		return received, nil
+	}
+}
+
+// messageAuthenticatorOffset returns the offset of the Message-Authenticator
+// value in the encoded packet, or -1 if it has none
+func messageAuthenticatorOffset(wire []byte) int {
+	for offset := 20; offset+2 <= len(wire); {
+		length := int(wire[offset+1])
+		if length < 2 || offset+length > len(wire) {
+			return -1
+		}
+		if radius.Type(wire[offset]) == attributeTypeMessageAuthenticator && length == 2+md5.Size {
+			return offset + 2
+		}
+		offset += length
+	}
+	return -1
+}
+
+// messageAuthenticator computes the HMAC-MD5 of the packet with the
+// authenticator field set to the request authenticator and the
+// Message-Authenticator value zeroed.
+func messageAuthenticator(wire []byte, offset int, requestAuthenticator, secret []byte) []byte {
+	buf := make([]byte, len(wire))
+	copy(buf, wire)
+	copy(buf[4:20], requestAuthenticator)
+	copy(buf[offset:offset+md5.Size], make([]byte, md5.Size))
+
+	mac := hmac.New(md5.New, secret)
+	mac.Write(buf)
+	return mac.Sum(nil)
+}
+
+// signMessageAuthenticator fills in the Message-Authenticator of an encoded
+// request
+func signMessageAuthenticator(wire, requestAuthenticator, secret []byte) error {
+	offset := messageAuthenticatorOffset(wire)
+	if offset == -1 {
+		return errors.New("request has no Message-Authenticator attribute")
+	}
+	copy(wire[offset:], messageAuthenticator(wire, offset, requestAuthenticator, secret))
+	return nil
+}
+
+// verifyMessageAuthenticator checks the Message-Authenticator of an encoded
+// response against the request authenticator
+func verifyMessageAuthenticator(wire, requestAuthenticator, secret []byte, required bool) error {
+	offset := messageAuthenticatorOffset(wire)
+	if offset == -1 {
+		if required {
+			return &messageAuthenticatorError{"response is missing the required Message-Authenticator attribute"}
+		}
+		return nil
+	}
+
+	expected := messageAuthenticator(wire, offset, requestAuthenticator, secret)
+	if !hmac.Equal(expected, wire[offset:offset+md5.Size]) {
+		return &messageAuthenticatorError{"response has an invalid Message-Authenticator"}
+	}
+	return nil
+}
+
+// microsoftAttribute encodes a Microsoft vendor-specific attribute
+func microsoftAttribute(vendorType byte, value []byte) radius.Attribute {
+	attr := make(radius.Attribute, 6+len(value))
+	binary.BigEndian.PutUint32(attr, vendorIDMicrosoft)
+	attr[4] = vendorType
+	attr[5] = byte(2 + len(value))
+	copy(attr[6:], value)
+	return attr
+}
+
+// lookupMicrosoftAttribute returns the value of the first Microsoft
+// vendor-specific attribute of the given type in the packet
+func lookupMicrosoftAttribute(packet *radius.Packet, vendorType byte) ([]byte, bool) {
+	for _, attr := range packet.Attributes[attributeTypeVendorSpecific] {
+		if len(attr) < 6 || binary.BigEndian.Uint32(attr) != vendorIDMicrosoft {
+			continue
+		}
+		for sub := attr[4:]; len(sub) >= 2; {
+			length := int(sub[1])
+			if length < 2 || length > len(sub) {
+				break
+			}
+			if sub[0] == vendorType {
+				return sub[2:length], true
+			}
+			sub = sub[length:]
+		}
+	}
+	return nil, false
+}
+
+// hostPort returns the address of a server given as "host" or "host:port"
+func hostPort(server string, defaultPort int) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(server, fmt.Sprintf("%d", defaultPort))
+}
@@ -0,0 +1,116 @@
+package radius
+
+import (
+	"crypto/des"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// The functions in this file implement the CHAP (RFC 1994) and MS-CHAPv2
+// (RFC 2759) computations performed by the peer.
+
+var (
+	mschapMagic1 = []byte("Magic server to client signing constant")
+	mschapMagic2 = []byte("Pad to make it do more than one iteration")
+)
+
+// chapResponse computes the CHAP response for the given identifier, password
+// and challenge
+func chapResponse(ident byte, password string, challenge []byte) []byte {
+	hash := md5.New()
+	hash.Write([]byte{ident})
+	hash.Write([]byte(password))
+	hash.Write(challenge)
+	return hash.Sum(nil)
+}
+
+// mschapUsername strips any domain prefix from the username, as only the
+// user name takes part in the challenge hash
+func mschapUsername(username string) string {
+	if idx := strings.LastIndex(username, `\`); idx != -1 {
+		return username[idx+1:]
+	}
+	return username
+}
+
+func mschapChallengeHash(peerChallenge, authenticatorChallenge []byte, username string) []byte {
+	hash := sha1.New()
+	hash.Write(peerChallenge)
+	hash.Write(authenticatorChallenge)
+	hash.Write([]byte(mschapUsername(username)))
+	return hash.Sum(nil)[:8]
+}
+
+func ntPasswordHash(password string) []byte {
+	encoded := utf16.Encode([]rune(password))
+	unicodePassword := make([]byte, 2*len(encoded))
+	for i, r := range encoded {
+		unicodePassword[2*i] = byte(r)
+		unicodePassword[2*i+1] = byte(r >> 8)
+	}
+
+	hash := md4.New()
+	hash.Write(unicodePassword)
+	return hash.Sum(nil)
+}
+
+// desKey expands a 7 byte key into the 8 byte form expected by DES, with
+// the least significant bit of each byte left for parity
+func desKey(key []byte) []byte {
+	expanded := make([]byte, 8)
+	expanded[0] = key[0] & 0xfe
+	expanded[1] = (key[0]<<7 | key[1]>>1) & 0xfe
+	expanded[2] = (key[1]<<6 | key[2]>>2) & 0xfe
+	expanded[3] = (key[2]<<5 | key[3]>>3) & 0xfe
+	expanded[4] = (key[3]<<4 | key[4]>>4) & 0xfe
+	expanded[5] = (key[4]<<3 | key[5]>>5) & 0xfe
+	expanded[6] = (key[5]<<2 | key[6]>>6) & 0xfe
+	expanded[7] = key[6] << 1
+	return expanded
+}
+
+func mschapChallengeResponse(challenge, passwordHash []byte) ([]byte, error) {
+	zPasswordHash := make([]byte, 21)
+	copy(zPasswordHash, passwordHash)
+
+	response := make([]byte, 24)
+	for i := 0; i < 3; i++ {
+		block, err := des.NewCipher(desKey(zPasswordHash[7*i : 7*i+7]))
+		if err != nil {
+			return nil, err
+		}
+		block.Encrypt(response[8*i:8*i+8], challenge)
+	}
+	return response, nil
+}
+
+// mschapV2NTResponse computes the NT-Response sent by the peer
+func mschapV2NTResponse(authenticatorChallenge, peerChallenge []byte, username, password string) ([]byte, error) {
+	challenge := mschapChallengeHash(peerChallenge, authenticatorChallenge, username)
+	return mschapChallengeResponse(challenge, ntPasswordHash(password))
+}
+
+// mschapV2AuthenticatorResponse computes the "S=" authenticator response the
+// server must return to prove it knows the password
+func mschapV2AuthenticatorResponse(authenticatorChallenge, peerChallenge, ntResponse []byte, username, password string) string {
+	passwordHashHash := md4.New()
+	passwordHashHash.Write(ntPasswordHash(password))
+
+	hash := sha1.New()
+	hash.Write(passwordHashHash.Sum(nil))
+	hash.Write(ntResponse)
+	hash.Write(mschapMagic1)
+	digest := hash.Sum(nil)
+
+	hash = sha1.New()
+	hash.Write(digest)
+	hash.Write(mschapChallengeHash(peerChallenge, authenticatorChallenge, username))
+	hash.Write(mschapMagic2)
+
+	return "S=" + strings.ToUpper(hex.EncodeToString(hash.Sum(nil)))
+}
@@ -0,0 +1,50 @@
+package radius
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func testDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from RFC 2759 section 9.2
+func TestMSCHAPv2_RFC2759(t *testing.T) {
+	username := "User"
+	password := "clientPass"
+	authenticatorChallenge := testDecodeHex(t, "5B5D7C7D7B3F2F3E3C2C602132262628")
+	peerChallenge := testDecodeHex(t, "21402324255E262A28295F2B3A337C7E")
+
+	if challenge := mschapChallengeHash(peerChallenge, authenticatorChallenge, username); !bytes.Equal(challenge, testDecodeHex(t, "D02E4386BCE91226")) {
+		t.Fatalf("bad challenge hash: %X", challenge)
+	}
+
+	if passwordHash := ntPasswordHash(password); !bytes.Equal(passwordHash, testDecodeHex(t, "44EBBA8D5312B8D611474411F56989AE")) {
+		t.Fatalf("bad password hash: %X", passwordHash)
+	}
+
+	ntResponse, err := mschapV2NTResponse(authenticatorChallenge, peerChallenge, username, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ntResponse, testDecodeHex(t, "82309ECD8D708B5EA08FAA3981CD83544233114A3D85D6DF")) {
+		t.Fatalf("bad NT-Response: %X", ntResponse)
+	}
+
+	authResponse := mschapV2AuthenticatorResponse(authenticatorChallenge, peerChallenge, ntResponse, username, password)
+	if authResponse != "S=407A5589115FD0D6209F510FE9C04566932CDA56" {
+		t.Fatalf("bad authenticator response: %s", authResponse)
+	}
+
+	// Domain prefixes don't take part in the challenge hash
+	if challenge := mschapChallengeHash(peerChallenge, authenticatorChallenge, `DOMAIN\`+username); !bytes.Equal(challenge, testDecodeHex(t, "D02E4386BCE91226")) {
+		t.Fatalf("bad challenge hash with domain: %X", challenge)
+	}
+}
@@ -2,12 +2,21 @@ package radius
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
+const (
+	authTypePAP      = "pap"
+	authTypeCHAP     = "chap"
+	authTypeMSCHAPv2 = "mschapv2"
+)
+
 func pathConfig(b *backend) *framework.Path {
 	return &framework.Path{
 		Pattern: "config",
@@ -73,6 +82,35 @@ func pathConfig(b *backend) *framework.Path {
 					Name: "NAS Identifier",
 				},
 			},
+			"failover_hosts": &framework.FieldSchema{
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Comma-separated list of RADIUS servers, as host or host:port, to try in order when the primary host can't be reached (optional)",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Failover hosts",
+				},
+			},
+			"auth_type": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Default:     authTypePAP,
+				Description: "Authentication protocol used in Access-Requests; one of \"pap\", \"chap\" or \"mschapv2\" (default: pap)",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name:  "Authentication type",
+					Value: authTypePAP,
+				},
+			},
+			"require_message_authenticator": &framework.FieldSchema{
+				Type:        framework.TypeBool,
+				Default:     false,
+				Description: "If set, responses without a Message-Authenticator attribute are rejected (default: false)",
+			},
+			"policy_attributes": &framework.FieldSchema{
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Comma-separated list of response attributes, such as Class or Filter-Id, whose values are granted as additional policies (optional)",
+			},
+			"alias_metadata_attributes": &framework.FieldSchema{
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Comma-separated list of response attributes, such as Class or Filter-Id, whose values are set as alias metadata (optional)",
+			},
 		},
 
 		ExistenceCheck: b.configExistenceCheck,
@@ -131,13 +169,18 @@ func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, d *f
 
 	resp := &logical.Response{
 		Data: map[string]interface{}{
-			"host":                       cfg.Host,
-			"port":                       cfg.Port,
-			"unregistered_user_policies": cfg.UnregisteredUserPolicies,
-			"dial_timeout":               cfg.DialTimeout,
-			"read_timeout":               cfg.ReadTimeout,
-			"nas_port":                   cfg.NasPort,
-			"nas_identifier":             cfg.NasIdentifier,
+			"host":                          cfg.Host,
+			"port":                          cfg.Port,
+			"unregistered_user_policies":    cfg.UnregisteredUserPolicies,
+			"dial_timeout":                  cfg.DialTimeout,
+			"read_timeout":                  cfg.ReadTimeout,
+			"nas_port":                      cfg.NasPort,
+			"nas_identifier":                cfg.NasIdentifier,
+			"failover_hosts":                cfg.FailoverHosts,
+			"auth_type":                     cfg.authType(),
+			"require_message_authenticator": cfg.RequireMessageAuthenticator,
+			"policy_attributes":             cfg.PolicyAttributes,
+			"alias_metadata_attributes":     cfg.AliasMetadataAttributes,
 		},
 	}
 	return resp, nil
@@ -225,6 +268,59 @@ func (b *backend) pathConfigCreateUpdate(ctx context.Context, req *logical.Reque
 		cfg.NasIdentifier = d.Get("nas_identifier").(string)
 	}
 
+	failoverHosts, ok := d.GetOk("failover_hosts")
+	if ok {
+		cfg.FailoverHosts = failoverHosts.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		cfg.FailoverHosts = d.Get("failover_hosts").([]string)
+	}
+	for i, host := range cfg.FailoverHosts {
+		cfg.FailoverHosts[i] = strings.ToLower(host)
+	}
+
+	authType, ok := d.GetOk("auth_type")
+	if ok {
+		cfg.AuthType = strings.ToLower(authType.(string))
+	} else if req.Operation == logical.CreateOperation {
+		cfg.AuthType = d.Get("auth_type").(string)
+	}
+	switch cfg.authType() {
+	case authTypePAP, authTypeCHAP, authTypeMSCHAPv2:
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("invalid auth_type %q; must be one of %q, %q or %q", cfg.AuthType, authTypePAP, authTypeCHAP, authTypeMSCHAPv2)), nil
+	}
+
+	requireMessageAuthenticator, ok := d.GetOk("require_message_authenticator")
+	if ok {
+		cfg.RequireMessageAuthenticator = requireMessageAuthenticator.(bool)
+	} else if req.Operation == logical.CreateOperation {
+		cfg.RequireMessageAuthenticator = d.Get("require_message_authenticator").(bool)
+	}
+
+	policyAttributes, ok := d.GetOk("policy_attributes")
+	if ok {
+		cfg.PolicyAttributes = policyAttributes.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		cfg.PolicyAttributes = d.Get("policy_attributes").([]string)
+	}
+	for _, name := range cfg.PolicyAttributes {
+		if _, ok := mappableAttributes[name]; !ok {
+			return logical.ErrorResponse(fmt.Sprintf("unsupported attribute %q in policy_attributes; must be one of %s", name, mappableAttributeNames())), nil
+		}
+	}
+
+	aliasMetadataAttributes, ok := d.GetOk("alias_metadata_attributes")
+	if ok {
+		cfg.AliasMetadataAttributes = aliasMetadataAttributes.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		cfg.AliasMetadataAttributes = d.Get("alias_metadata_attributes").([]string)
+	}
+	for _, name := range cfg.AliasMetadataAttributes {
+		if _, ok := mappableAttributes[name]; !ok {
+			return logical.ErrorResponse(fmt.Sprintf("unsupported attribute %q in alias_metadata_attributes; must be one of %s", name, mappableAttributeNames())), nil
+		}
+	}
+
 	entry, err := logical.StorageEntryJSON("config", cfg)
 	if err != nil {
 		return nil, err
@@ -237,14 +333,38 @@ func (b *backend) pathConfigCreateUpdate(ctx context.Context, req *logical.Reque
 }
 
 type ConfigEntry struct {
-	Host                     string   `json:"host" structs:"host" mapstructure:"host"`
-	Port                     int      `json:"port" structs:"port" mapstructure:"port"`
-	Secret                   string   `json:"secret" structs:"secret" mapstructure:"secret"`
-	UnregisteredUserPolicies []string `json:"unregistered_user_policies" structs:"unregistered_user_policies" mapstructure:"unregistered_user_policies"`
-	DialTimeout              int      `json:"dial_timeout" structs:"dial_timeout" mapstructure:"dial_timeout"`
-	ReadTimeout              int      `json:"read_timeout" structs:"read_timeout" mapstructure:"read_timeout"`
-	NasPort                  int      `json:"nas_port" structs:"nas_port" mapstructure:"nas_port"`
-	NasIdentifier            string   `json:"nas_identifier" structs:"nas_identifier" mapstructure:"nas_identifier"`
+	Host                        string   `json:"host" structs:"host" mapstructure:"host"`
+	Port                        int      `json:"port" structs:"port" mapstructure:"port"`
+	Secret                      string   `json:"secret" structs:"secret" mapstructure:"secret"`
+	UnregisteredUserPolicies    []string `json:"unregistered_user_policies" structs:"unregistered_user_policies" mapstructure:"unregistered_user_policies"`
+	DialTimeout                 int      `json:"dial_timeout" structs:"dial_timeout" mapstructure:"dial_timeout"`
+	ReadTimeout                 int      `json:"read_timeout" structs:"read_timeout" mapstructure:"read_timeout"`
+	NasPort                     int      `json:"nas_port" structs:"nas_port" mapstructure:"nas_port"`
+	NasIdentifier               string   `json:"nas_identifier" structs:"nas_identifier" mapstructure:"nas_identifier"`
+	FailoverHosts               []string `json:"failover_hosts" structs:"failover_hosts" mapstructure:"failover_hosts"`
+	AuthType                    string   `json:"auth_type" structs:"auth_type" mapstructure:"auth_type"`
+	RequireMessageAuthenticator bool     `json:"require_message_authenticator" structs:"require_message_authenticator" mapstructure:"require_message_authenticator"`
+	PolicyAttributes            []string `json:"policy_attributes" structs:"policy_attributes" mapstructure:"policy_attributes"`
+	AliasMetadataAttributes     []string `json:"alias_metadata_attributes" structs:"alias_metadata_attributes" mapstructure:"alias_metadata_attributes"`
+}
+
+// authType returns the configured authentication protocol. Entries stored
+// before the protocol was configurable use PAP.
+func (c *ConfigEntry) authType() string {
+	if c.AuthType == "" {
+		return authTypePAP
+	}
+	return c.AuthType
+}
+
+// servers returns the addresses of the configured RADIUS servers in the
+// order they should be tried
+func (c *ConfigEntry) servers() []string {
+	servers := []string{net.JoinHostPort(c.Host, strconv.Itoa(c.Port))}
+	for _, host := range c.FailoverHosts {
+		servers = append(servers, hostPort(host, c.Port))
+	}
+	return servers
 }
 
 const pathConfigHelpSyn = `
@@ -254,4 +374,13 @@ Configure the RADIUS server to connect to, along with its options.
 const pathConfigHelpDesc = `
 This endpoint allows you to configure the RADIUS server to connect to and its
 configuration options.
+
+Servers listed in failover_hosts are tried in order when the previous server
+can't be reached or doesn't answer within the read timeout. Failover doesn't
+happen when a server rejects the credentials.
+
+The values of the response attributes listed in policy_attributes are added
+to the policies of the user, split on commas. The values of the attributes
+listed in alias_metadata_attributes are set as alias metadata, keyed by the
+lowercased attribute name with dashes replaced by underscores, e.g. filter_id.
 `
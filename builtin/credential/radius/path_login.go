@@ -2,9 +2,10 @@ package radius
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
 	"fmt"
-	"net"
-	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
@@ -76,7 +78,7 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framew
 		return logical.ErrorResponse("password cannot be empty"), nil
 	}
 
-	policies, resp, err := b.RadiusLogin(ctx, req, username, password)
+	policies, aliasMetadata, resp, err := b.RadiusLogin(ctx, req, username, password)
 	// Handle an internal error
 	if err != nil {
 		return nil, err
@@ -102,7 +104,8 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framew
 			Renewable: true,
 		},
 		Alias: &logical.Alias{
-			Name: username,
+			Name:     username,
+			Metadata: aliasMetadata,
 		},
 	}
 	return resp, nil
@@ -117,7 +120,7 @@ func (b *backend) pathLoginRenew(ctx context.Context, req *logical.Request, d *f
 	var resp *logical.Response
 	var loginPolicies []string
 
-	loginPolicies, resp, err = b.RadiusLogin(ctx, req, username, password)
+	loginPolicies, _, resp, err = b.RadiusLogin(ctx, req, username, password)
 	if err != nil || (resp != nil && resp.IsError()) {
 		return resp, err
 	}
@@ -129,39 +132,64 @@ func (b *backend) pathLoginRenew(ctx context.Context, req *logical.Request, d *f
 	return &logical.Response{Auth: req.Auth}, nil
 }
 
-func (b *backend) RadiusLogin(ctx context.Context, req *logical.Request, username string, password string) ([]string, *logical.Response, error) {
+// RadiusLogin authenticates the user against the configured RADIUS servers
+// and returns the policies to grant along with the alias metadata mapped from
+// the response attributes.
+func (b *backend) RadiusLogin(ctx context.Context, req *logical.Request, username string, password string) ([]string, map[string]string, *logical.Response, error) {
 
 	cfg, err := b.Config(ctx, req)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	if cfg == nil || cfg.Host == "" || cfg.Secret == "" {
-		return nil, logical.ErrorResponse("radius backend not configured"), nil
+		return nil, nil, logical.ErrorResponse("radius backend not configured"), nil
 	}
 
-	hostport := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
-
 	packet := radius.New(radius.CodeAccessRequest, []byte(cfg.Secret))
 	UserName_SetString(packet, username)
-	UserPassword_SetString(packet, password)
 	if cfg.NasIdentifier != "" {
 		NASIdentifier_AddString(packet, cfg.NasIdentifier)
 	}
 	packet.Add(5, radius.NewInteger(uint32(cfg.NasPort)))
 
-	client := radius.Client{
-		Dialer: net.Dialer{
-			Timeout: time.Duration(cfg.DialTimeout) * time.Second,
-		},
+	var verifyAccept func(*radius.Packet) error
+	switch cfg.authType() {
+	case authTypeCHAP:
+		err = setCHAPPassword(packet, password)
+	case authTypeMSCHAPv2:
+		verifyAccept, err = setMSCHAPv2Response(packet, username, password)
+	default:
+		err = setUserPassword(packet, password)
 	}
-	clientCtx, cancelFunc := context.WithTimeout(ctx, time.Duration(cfg.ReadTimeout)*time.Second)
-	received, err := client.Exchange(clientCtx, packet, hostport)
-	cancelFunc()
 	if err != nil {
-		return nil, logical.ErrorResponse(err.Error()), nil
+		return nil, nil, nil, err
+	}
+
+	var received *radius.Packet
+	for _, server := range cfg.servers() {
+		clientCtx, cancelFunc := context.WithTimeout(ctx, time.Duration(cfg.ReadTimeout)*time.Second)
+		received, err = exchange(clientCtx, time.Duration(cfg.DialTimeout)*time.Second, packet, server, cfg.RequireMessageAuthenticator)
+		cancelFunc()
+		if err == nil {
+			break
+		}
+		if _, ok := err.(*messageAuthenticatorError); ok {
+			// The server answered, so another one wouldn't be any more
+			// trustworthy
+			return nil, nil, logical.ErrorResponse(err.Error()), nil
+		}
+		b.Logger().Warn("error exchanging packet with RADIUS server", "server", server, "error", err)
+	}
+	if err != nil {
+		return nil, nil, logical.ErrorResponse(err.Error()), nil
 	}
 	if received.Code != radius.CodeAccessAccept {
-		return nil, logical.ErrorResponse("access denied by the authentication server"), nil
+		return nil, nil, logical.ErrorResponse("access denied by the authentication server"), nil
+	}
+	if verifyAccept != nil {
+		if err := verifyAccept(received); err != nil {
+			return nil, nil, logical.ErrorResponse(err.Error()), nil
+		}
 	}
 
 	policies := cfg.UnregisteredUserPolicies
@@ -169,13 +197,123 @@ func (b *backend) RadiusLogin(ctx context.Context, req *logical.Request, usernam
 	// Retrieve user entry from storage
 	user, err := b.user(ctx, req.Storage, username)
 	if err != nil {
-		return nil, logical.ErrorResponse("could not retrieve user entry from storage"), err
+		return nil, nil, logical.ErrorResponse("could not retrieve user entry from storage"), err
 	}
 	if user != nil {
 		policies = user.Policies
 	}
 
-	return policies, &logical.Response{}, nil
+	if len(cfg.PolicyAttributes) > 0 {
+		// Copy so the stored slices are never appended to
+		policies = append([]string(nil), policies...)
+		for _, name := range cfg.PolicyAttributes {
+			for _, value := range attributeValues(received, name) {
+				for _, policy := range strutil.ParseDedupLowercaseAndSortStrings(value, ",") {
+					if policy == "root" {
+						b.Logger().Warn("ignoring root policy returned by the RADIUS server", "attribute", name)
+						continue
+					}
+					policies = append(policies, policy)
+				}
+			}
+		}
+		policies = strutil.RemoveDuplicates(policies, false)
+	}
+
+	var aliasMetadata map[string]string
+	for _, name := range cfg.AliasMetadataAttributes {
+		values := attributeValues(received, name)
+		if len(values) == 0 {
+			continue
+		}
+		if aliasMetadata == nil {
+			aliasMetadata = make(map[string]string)
+		}
+		aliasMetadata[attributeMetadataKey(name)] = strings.Join(values, ",")
+	}
+
+	return policies, aliasMetadata, &logical.Response{}, nil
+}
+
+// setUserPassword sets the User-Password attribute of the request. The
+// plaintext is given capacity for the null padding of RFC 2865 section 5.2,
+// which radius.NewUserPassword reads without growing the slice.
+func setUserPassword(packet *radius.Packet, password string) error {
+	plaintext := make([]byte, len(password), (len(password)/16+1)*16)
+	copy(plaintext, password)
+	return UserPassword_Set(packet, plaintext)
+}
+
+// setCHAPPassword sets the CHAP-Password and CHAP-Challenge attributes of the
+// request
+func setCHAPPassword(packet *radius.Packet, password string) error {
+	var buf [17]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return err
+	}
+	ident, challenge := buf[0], buf[1:]
+
+	packet.Set(attributeTypeCHAPPassword, append([]byte{ident}, chapResponse(ident, password, challenge)...))
+	packet.Set(attributeTypeCHAPChallenge, radius.Attribute(challenge))
+	return nil
+}
+
+// setMSCHAPv2Response sets the MS-CHAP-Challenge and MS-CHAP2-Response
+// attributes of the request. The returned function verifies that an
+// Access-Accept carries the authenticator response proving the server knows
+// the password.
+func setMSCHAPv2Response(packet *radius.Packet, username, password string) (func(*radius.Packet) error, error) {
+	var buf [33]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, err
+	}
+	ident, authenticatorChallenge, peerChallenge := buf[0], buf[1:17], buf[17:33]
+
+	ntResponse, err := mschapV2NTResponse(authenticatorChallenge, peerChallenge, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ident, Flags, Peer-Challenge, Reserved and NT-Response, as laid out in
+	// RFC 2548 section 2.3.2
+	response := make([]byte, 0, 50)
+	response = append(response, ident, 0)
+	response = append(response, peerChallenge...)
+	response = append(response, make([]byte, 8)...)
+	response = append(response, ntResponse...)
+
+	packet.Add(attributeTypeVendorSpecific, microsoftAttribute(msCHAPChallenge, authenticatorChallenge))
+	packet.Add(attributeTypeVendorSpecific, microsoftAttribute(msCHAP2Response, response))
+
+	expected := mschapV2AuthenticatorResponse(authenticatorChallenge, peerChallenge, ntResponse, username, password)
+	return func(received *radius.Packet) error {
+		success, ok := lookupMicrosoftAttribute(received, msCHAP2Success)
+		if !ok {
+			return errors.New("MS-CHAP2-Success attribute missing from the response")
+		}
+		if len(success) < 1+len(expected) || success[0] != ident || subtle.ConstantTimeCompare(success[1:1+len(expected)], []byte(expected)) != 1 {
+			return errors.New("invalid MS-CHAPv2 authenticator response from the authentication server")
+		}
+		return nil
+	}, nil
+}
+
+// attributeValues returns the textual values of the named attribute in the
+// response
+func attributeValues(received *radius.Packet, name string) []string {
+	var values []string
+	for _, value := range received.Attributes[mappableAttributes[name]] {
+		if trimmed := strings.TrimSpace(string(value)); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+// attributeMetadataKey returns the alias metadata key of the named attribute,
+// which is usable in templated policies
+func attributeMetadataKey(name string) string {
+	return strings.Replace(strings.ToLower(name), "-", "_", -1)
 }
 
 const pathLoginSyn = `
@@ -185,4 +323,7 @@ Log in with a username and password.
 const pathLoginDesc = `
 This endpoint authenticates using a username and password. Please be sure to
 read the note on escaping from the path-help for the 'config' endpoint.
+
+Depending on the configured auth_type, the password is sent using PAP, or
+proven using CHAP or MS-CHAPv2.
 `
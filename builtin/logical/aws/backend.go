@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/queue"
 )
 
 func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
@@ -17,6 +18,13 @@ func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend,
 	if err := b.Setup(ctx, conf); err != nil {
 		return nil, err
 	}
+
+	b.credRotationQueue = queue.New()
+	// Create a context with a cancel method for populating the queue and
+	// rotating the access keys of static roles
+	ictx, cancel := context.WithCancel(context.Background())
+	b.cancelQueue = cancel
+	go b.initQueue(ictx, conf)
 	return b, nil
 }
 
@@ -31,6 +39,7 @@ func Backend() *backend {
 			},
 			SealWrapStorage: []string{
 				"config/root",
+				staticCredsPrefix,
 			},
 		},
 
@@ -41,12 +50,16 @@ func Backend() *backend {
 			pathRoles(&b),
 			pathListRoles(&b),
 			pathUser(&b),
+			pathStaticRoles(&b),
+			pathListStaticRoles(&b),
+			pathStaticCreds(&b),
 		},
 
 		Secrets: []*framework.Secret{
 			secretAccessKeys(&b),
 		},
 
+		Clean:             b.clean,
 		WALRollback:       b.walRollback,
 		WALRollbackMinAge: minAwsUserRollbackAge,
 		BackendType:       logical.TypeLogical,
@@ -68,6 +81,17 @@ type backend struct {
 	// to enable mocking with AWS iface for tests
 	iamClient iamiface.IAMAPI
 	stsClient stsiface.STSAPI
+
+	// Mutex to protect access to static roles and the rotation of their
+	// access keys
+	staticRoleMutex sync.Mutex
+
+	// credRotationQueue is an in-memory priority queue used to track static
+	// roles whose access keys need rotation, cancelQueue terminates its
+	// background loading and rotation, and queueMutex protects access to both
+	credRotationQueue *queue.PriorityQueue
+	cancelQueue       context.CancelFunc
+	queueMutex        sync.RWMutex
 }
 
 const backendHelp = `
@@ -78,6 +102,10 @@ are automatically revoked at the end of the lease.
 After mounting this backend, credentials to generate IAM keys must
 be configured with the "root" path and policies must be written using
 the "roles/" endpoints before any access keys can be generated.
+
+The access keys of existing IAM users can be rotated periodically by
+writing static roles with the "static-roles/" endpoints, and read from
+the "static-creds/" endpoints.
 `
 
 // clientIAM returns the configured IAM client. If nil, it constructs a new one
@@ -136,4 +164,9 @@ func (b *backend) clientSTS(ctx context.Context, s logical.Storage) (stsiface.ST
 	return b.stsClient, nil
 }
 
+// clean cancels the rotation of the access keys of static roles
+func (b *backend) clean(ctx context.Context) {
+	b.invalidateQueue()
+}
+
 const minAwsUserRollbackAge = 5 * time.Minute
@@ -39,7 +39,9 @@ func (m *mockIAMClient) CreateUser(input *iam.CreateUserInput) (*iam.CreateUserO
 }
 
 func getBackend(t *testing.T) logical.Backend {
-	be, _ := Factory(context.Background(), logical.TestBackendConfig())
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	be, _ := Factory(context.Background(), config)
 	return be
 }
 
@@ -669,13 +671,16 @@ func testAccStepReadPolicy(t *testing.T, name string, value string) logicaltest.
 			}
 
 			expected := map[string]interface{}{
-				"policy_arns":     []string(nil),
-				"role_arns":       []string(nil),
-				"policy_document": value,
-				"credential_type": strings.Join([]string{iamUserCred, federationTokenCred}, ","),
-				"default_sts_ttl": int64(0),
-				"max_sts_ttl":     int64(0),
-				"user_path":       "",
+				"policy_arns":              []string(nil),
+				"role_arns":                []string(nil),
+				"policy_document":          value,
+				"credential_type":          strings.Join([]string{iamUserCred, federationTokenCred}, ","),
+				"default_sts_ttl":          int64(0),
+				"max_sts_ttl":              int64(0),
+				"user_path":                "",
+				"permissions_boundary_arn": "",
+				"session_tags":             map[string]string(nil),
+				"external_id":              "",
 			}
 			if !reflect.DeepEqual(resp.Data, expected) {
 				return fmt.Errorf("bad: got: %#v\nexpected: %#v", resp.Data, expected)
@@ -769,13 +774,16 @@ func TestBackend_iamUserManagedInlinePolicies(t *testing.T) {
 		"user_path":       "/path/",
 	}
 	expectedRoleData := map[string]interface{}{
-		"policy_document": compacted,
-		"policy_arns":     []string{ec2PolicyArn, iamPolicyArn},
-		"credential_type": iamUserCred,
-		"role_arns":       []string(nil),
-		"default_sts_ttl": int64(0),
-		"max_sts_ttl":     int64(0),
-		"user_path":       "/path/",
+		"policy_document":          compacted,
+		"policy_arns":              []string{ec2PolicyArn, iamPolicyArn},
+		"credential_type":          iamUserCred,
+		"role_arns":                []string(nil),
+		"default_sts_ttl":          int64(0),
+		"max_sts_ttl":              int64(0),
+		"user_path":                "/path/",
+		"permissions_boundary_arn": "",
+		"session_tags":             map[string]string(nil),
+		"external_id":              "",
 	}
 
 	logicaltest.Test(t, logicaltest.TestCase{
@@ -907,13 +915,16 @@ func testAccStepReadArnPolicy(t *testing.T, name string, value string) logicalte
 			}
 
 			expected := map[string]interface{}{
-				"policy_arns":     []string{value},
-				"role_arns":       []string(nil),
-				"policy_document": "",
-				"credential_type": iamUserCred,
-				"default_sts_ttl": int64(0),
-				"max_sts_ttl":     int64(0),
-				"user_path":       "",
+				"policy_arns":              []string{value},
+				"role_arns":                []string(nil),
+				"policy_document":          "",
+				"credential_type":          iamUserCred,
+				"default_sts_ttl":          int64(0),
+				"max_sts_ttl":              int64(0),
+				"user_path":                "",
+				"permissions_boundary_arn": "",
+				"session_tags":             map[string]string(nil),
+				"external_id":              "",
 			}
 			if !reflect.DeepEqual(resp.Data, expected) {
 				return fmt.Errorf("bad: got: %#v\nexpected: %#v", resp.Data, expected)
@@ -60,8 +60,11 @@ func pathRoles(b *backend) *framework.Path {
 			},
 
 			"policy_arns": &framework.FieldSchema{
-				Type:        framework.TypeCommaStringSlice,
-				Description: "ARNs of AWS policies to attach to IAM users. Only valid when credential_type is " + iamUserCred,
+				Type: framework.TypeCommaStringSlice,
+				Description: fmt.Sprintf(`ARNs of AWS policies. Behavior varies by credential_type. When credential_type is
+%s, then it will attach the specified policies to the generated IAM user.
+When credential_type is %s or %s, the policies will be passed as the
+PolicyArns parameter, acting as a filter on permissions available.`, iamUserCred, assumedRoleCred, federationTokenCred),
 				DisplayAttrs: &framework.DisplayAttributes{
 					Name: "Policy ARNs",
 				},
@@ -113,6 +116,30 @@ GetFederationToken API call, acting as a filter on permissions available.`,
 				},
 				Default: "/",
 			},
+
+			"permissions_boundary_arn": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "ARN of an AWS policy used as the permissions boundary of IAM users. Only valid when credential_type is " + iamUserCred,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Permissions Boundary ARN",
+				},
+			},
+
+			"session_tags": &framework.FieldSchema{
+				Type:        framework.TypeKVPairs,
+				Description: fmt.Sprintf("Session tags to set on the credentials, as key-value pairs. Only valid when credential_type is %s or %s", assumedRoleCred, federationTokenCred),
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Session Tags",
+				},
+			},
+
+			"external_id": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "External ID to pass to the AssumeRole call. Only valid when credential_type is " + assumedRoleCred,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "External ID",
+				},
+			},
 		},
 
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -283,6 +310,45 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 		roleEntry.UserPath = userPathRaw.(string)
 	}
 
+	if permissionsBoundaryARNRaw, ok := d.GetOk("permissions_boundary_arn"); ok {
+		if legacyRole != "" {
+			return logical.ErrorResponse("cannot supply deprecated role or policy parameters with permissions_boundary_arn"), nil
+		}
+		if !strutil.StrListContains(roleEntry.CredentialTypes, iamUserCred) {
+			return logical.ErrorResponse(fmt.Sprintf("permissions_boundary_arn parameter only valid for %s credential type", iamUserCred)), nil
+		}
+		permissionsBoundaryARN := permissionsBoundaryARNRaw.(string)
+		if permissionsBoundaryARN != "" {
+			if _, err := arn.Parse(permissionsBoundaryARN); err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("unable to parse permissions_boundary_arn %q: %v", permissionsBoundaryARN, err)), nil
+			}
+		}
+
+		roleEntry.PermissionsBoundaryARN = permissionsBoundaryARN
+	}
+
+	if sessionTagsRaw, ok := d.GetOk("session_tags"); ok {
+		if legacyRole != "" {
+			return logical.ErrorResponse("cannot supply deprecated role or policy parameters with session_tags"), nil
+		}
+		if !strutil.StrListContains(roleEntry.CredentialTypes, assumedRoleCred) && !strutil.StrListContains(roleEntry.CredentialTypes, federationTokenCred) {
+			return logical.ErrorResponse(fmt.Sprintf("session_tags parameter only valid for %s and %s credential types", assumedRoleCred, federationTokenCred)), nil
+		}
+
+		roleEntry.SessionTags = sessionTagsRaw.(map[string]string)
+	}
+
+	if externalIDRaw, ok := d.GetOk("external_id"); ok {
+		if legacyRole != "" {
+			return logical.ErrorResponse("cannot supply deprecated role or policy parameters with external_id"), nil
+		}
+		if !strutil.StrListContains(roleEntry.CredentialTypes, assumedRoleCred) {
+			return logical.ErrorResponse(fmt.Sprintf("external_id parameter only valid for %s credential type", assumedRoleCred)), nil
+		}
+
+		roleEntry.ExternalID = externalIDRaw.(string)
+	}
+
 	if roleEntry.MaxSTSTTL > 0 &&
 		roleEntry.DefaultSTSTTL > 0 &&
 		roleEntry.DefaultSTSTTL > roleEntry.MaxSTSTTL {
@@ -306,8 +372,14 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 	if len(roleEntry.RoleArns) > 0 && !strutil.StrListContains(roleEntry.CredentialTypes, assumedRoleCred) {
 		return logical.ErrorResponse(fmt.Sprintf("cannot supply role_arns when credential_type isn't %s", assumedRoleCred)), nil
 	}
-	if len(roleEntry.PolicyArns) > 0 && !strutil.StrListContains(roleEntry.CredentialTypes, iamUserCred) {
-		return logical.ErrorResponse(fmt.Sprintf("cannot supply policy_arns when credential_type isn't %s", iamUserCred)), nil
+	if roleEntry.PermissionsBoundaryARN != "" && !strutil.StrListContains(roleEntry.CredentialTypes, iamUserCred) {
+		return logical.ErrorResponse(fmt.Sprintf("cannot supply permissions_boundary_arn when credential_type isn't %s", iamUserCred)), nil
+	}
+	if len(roleEntry.SessionTags) > 0 && !strutil.StrListContains(roleEntry.CredentialTypes, assumedRoleCred) && !strutil.StrListContains(roleEntry.CredentialTypes, federationTokenCred) {
+		return logical.ErrorResponse(fmt.Sprintf("cannot supply session_tags when credential_type isn't %s or %s", assumedRoleCred, federationTokenCred)), nil
+	}
+	if roleEntry.ExternalID != "" && !strutil.StrListContains(roleEntry.CredentialTypes, assumedRoleCred) {
+		return logical.ErrorResponse(fmt.Sprintf("cannot supply external_id when credential_type isn't %s", assumedRoleCred)), nil
 	}
 
 	err = setAwsRole(ctx, req.Storage, roleName, roleEntry)
@@ -461,27 +533,33 @@ func setAwsRole(ctx context.Context, s logical.Storage, roleName string, roleEnt
 }
 
 type awsRoleEntry struct {
-	CredentialTypes          []string      `json:"credential_types"`                      // Entries must all be in the set of ("iam_user", "assumed_role", "federation_token")
-	PolicyArns               []string      `json:"policy_arns"`                           // ARNs of managed policies to attach to an IAM user
-	RoleArns                 []string      `json:"role_arns"`                             // ARNs of roles to assume for AssumedRole credentials
-	PolicyDocument           string        `json:"policy_document"`                       // JSON-serialized inline policy to attach to IAM users and/or to specify as the Policy parameter in AssumeRole calls
-	InvalidData              string        `json:"invalid_data,omitempty"`                // Invalid role data. Exists to support converting the legacy role data into the new format
-	ProhibitFlexibleCredPath bool          `json:"prohibit_flexible_cred_path,omitempty"` // Disallow accessing STS credentials via the creds path and vice verse
-	Version                  int           `json:"version"`                               // Version number of the role format
-	DefaultSTSTTL            time.Duration `json:"default_sts_ttl"`                       // Default TTL for STS credentials
-	MaxSTSTTL                time.Duration `json:"max_sts_ttl"`                           // Max allowed TTL for STS credentials
-	UserPath                 string        `json:"user_path"`                             // The path for the IAM user when using "iam_user" credential type
+	CredentialTypes          []string          `json:"credential_types"`                      // Entries must all be in the set of ("iam_user", "assumed_role", "federation_token")
+	PolicyArns               []string          `json:"policy_arns"`                           // ARNs of managed policies to attach to an IAM user or to specify as the PolicyArns parameter in STS calls
+	RoleArns                 []string          `json:"role_arns"`                             // ARNs of roles to assume for AssumedRole credentials
+	PolicyDocument           string            `json:"policy_document"`                       // JSON-serialized inline policy to attach to IAM users and/or to specify as the Policy parameter in AssumeRole calls
+	InvalidData              string            `json:"invalid_data,omitempty"`                // Invalid role data. Exists to support converting the legacy role data into the new format
+	ProhibitFlexibleCredPath bool              `json:"prohibit_flexible_cred_path,omitempty"` // Disallow accessing STS credentials via the creds path and vice verse
+	Version                  int               `json:"version"`                               // Version number of the role format
+	DefaultSTSTTL            time.Duration     `json:"default_sts_ttl"`                       // Default TTL for STS credentials
+	MaxSTSTTL                time.Duration     `json:"max_sts_ttl"`                           // Max allowed TTL for STS credentials
+	UserPath                 string            `json:"user_path"`                             // The path for the IAM user when using "iam_user" credential type
+	PermissionsBoundaryARN   string            `json:"permissions_boundary_arn"`              // ARN of the permissions boundary of IAM users when using "iam_user" credential type
+	SessionTags              map[string]string `json:"session_tags"`                          // Session tags of STS credentials
+	ExternalID               string            `json:"external_id"`                           // External ID passed to AssumeRole when using "assumed_role" credential type
 }
 
 func (r *awsRoleEntry) toResponseData() map[string]interface{} {
 	respData := map[string]interface{}{
-		"credential_type": strings.Join(r.CredentialTypes, ","),
-		"policy_arns":     r.PolicyArns,
-		"role_arns":       r.RoleArns,
-		"policy_document": r.PolicyDocument,
-		"default_sts_ttl": int64(r.DefaultSTSTTL.Seconds()),
-		"max_sts_ttl":     int64(r.MaxSTSTTL.Seconds()),
-		"user_path":       r.UserPath,
+		"credential_type":          strings.Join(r.CredentialTypes, ","),
+		"policy_arns":              r.PolicyArns,
+		"role_arns":                r.RoleArns,
+		"policy_document":          r.PolicyDocument,
+		"default_sts_ttl":          int64(r.DefaultSTSTTL.Seconds()),
+		"max_sts_ttl":              int64(r.MaxSTSTTL.Seconds()),
+		"user_path":                r.UserPath,
+		"permissions_boundary_arn": r.PermissionsBoundaryARN,
+		"session_tags":             r.SessionTags,
+		"external_id":              r.ExternalID,
 	}
 
 	if r.InvalidData != "" {
@@ -213,3 +213,105 @@ func TestUserPathValidity(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleCredentialTypeParameters(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		description string
+		roleData    map[string]interface{}
+		isValid     bool
+	}{
+		{
+			description: "Permissions boundary for IAM users",
+			roleData: map[string]interface{}{
+				"credential_type":          iamUserCred,
+				"permissions_boundary_arn": "arn:aws:iam::123456789012:policy/boundary",
+			},
+			isValid: true,
+		},
+		{
+			description: "Invalid permissions boundary",
+			roleData: map[string]interface{}{
+				"credential_type":          iamUserCred,
+				"permissions_boundary_arn": "boundary",
+			},
+			isValid: false,
+		},
+		{
+			description: "Permissions boundary for assumed roles",
+			roleData: map[string]interface{}{
+				"credential_type":          assumedRoleCred,
+				"permissions_boundary_arn": "arn:aws:iam::123456789012:policy/boundary",
+			},
+			isValid: false,
+		},
+		{
+			description: "Session tags for federation tokens",
+			roleData: map[string]interface{}{
+				"credential_type": federationTokenCred,
+				"session_tags":    "team=ops",
+			},
+			isValid: true,
+		},
+		{
+			description: "Session tags for IAM users",
+			roleData: map[string]interface{}{
+				"credential_type": iamUserCred,
+				"session_tags":    "team=ops",
+			},
+			isValid: false,
+		},
+		{
+			description: "External ID for assumed roles",
+			roleData: map[string]interface{}{
+				"credential_type": assumedRoleCred,
+				"external_id":     "ext-id",
+			},
+			isValid: true,
+		},
+		{
+			description: "External ID for federation tokens",
+			roleData: map[string]interface{}{
+				"credential_type": federationTokenCred,
+				"external_id":     "ext-id",
+			},
+			isValid: false,
+		},
+		{
+			description: "Policy ARNs for federation tokens",
+			roleData: map[string]interface{}{
+				"credential_type": federationTokenCred,
+				"policy_arns":     "arn:aws:iam::aws:policy/ReadOnlyAccess",
+			},
+			isValid: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			resp, err := b.HandleRequest(context.Background(), &logical.Request{
+				Operation: logical.UpdateOperation,
+				Path:      "roles/test",
+				Storage:   config.StorageView,
+				Data:      tc.roleData,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isValid := resp == nil || !resp.IsError(); isValid != tc.isValid {
+				t.Fatalf("bad: expected valid %t, got resp:%#v", tc.isValid, resp)
+			}
+
+			if err := config.StorageView.Delete(context.Background(), "role/test"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathStaticCreds(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-creds/" + framework.GenericNameRegex("name"),
+		Fields: map[string]*framework.FieldSchema{
+			"name": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "Name of the static role",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: b.pathStaticCredsRead,
+		},
+
+		HelpSynopsis:    pathStaticCredsHelpSyn,
+		HelpDescription: pathStaticCredsHelpDesc,
+	}
+}
+
+func (b *backend) pathStaticCredsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	role, err := b.staticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("Static role '%s' not found", name)), nil
+	}
+
+	creds, err := b.staticCreds(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil {
+		return nil, fmt.Errorf("no access key found for static role %q", name)
+	}
+
+	ttl := creds.LastVaultRotation.Add(role.RotationPeriod).Sub(time.Now())
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"access_key":          creds.AccessKeyID,
+			"secret_key":          creds.SecretAccessKey,
+			"username":            role.Username,
+			"ttl":                 int64(ttl.Seconds()),
+			"rotation_period":     int64(role.RotationPeriod.Seconds()),
+			"last_vault_rotation": creds.LastVaultRotation,
+		},
+	}, nil
+}
+
+const pathStaticCredsHelpSyn = `
+Read the current access key of a static role.
+`
+
+const pathStaticCredsHelpDesc = `
+This path reads the current access key of the IAM user of a static role. The
+access key is rotated every rotation_period, "ttl" is the time left until the
+next rotation. The access key has no lease, clients should read it again after
+it is rotated.
+`
@@ -0,0 +1,389 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/hashicorp/errwrap"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/vault/helper/awsutil"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/queue"
+)
+
+const (
+	staticRolePrefix  = "static-roles/"
+	staticCredsPrefix = "static-creds/"
+)
+
+func pathListStaticRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-roles/?$",
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathStaticRoleList,
+		},
+
+		HelpSynopsis:    pathListStaticRolesHelpSyn,
+		HelpDescription: pathListStaticRolesHelpDesc,
+	}
+}
+
+func pathStaticRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-roles/" + framework.GenericNameRegex("name"),
+		Fields: map[string]*framework.FieldSchema{
+			"name": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "Name of the static role",
+			},
+
+			"username": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "Name of the existing IAM user whose access keys are managed. Can't be changed after the role is created.",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "IAM User Name",
+				},
+			},
+
+			"rotation_period": &framework.FieldSchema{
+				Type:        framework.TypeDurationSecond,
+				Description: fmt.Sprintf("Period between rotations of the access keys, %d seconds or more", queueTickSeconds),
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Rotation Period",
+				},
+			},
+		},
+
+		ExistenceCheck: b.pathStaticRoleExistenceCheck,
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: b.pathStaticRolesWrite,
+			logical.UpdateOperation: b.pathStaticRolesWrite,
+			logical.ReadOperation:   b.pathStaticRolesRead,
+			logical.DeleteOperation: b.pathStaticRolesDelete,
+		},
+
+		HelpSynopsis:    pathStaticRolesHelpSyn,
+		HelpDescription: pathStaticRolesHelpDesc,
+	}
+}
+
+func (b *backend) pathStaticRoleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.staticRole(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+	return role != nil, nil
+}
+
+func (b *backend) pathStaticRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	entries, err := req.Storage.List(ctx, staticRolePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(entries), nil
+}
+
+func (b *backend) pathStaticRolesRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	role, err := b.staticRole(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"username":        role.Username,
+			"rotation_period": int64(role.RotationPeriod.Seconds()),
+		},
+	}, nil
+}
+
+func (b *backend) pathStaticRolesDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	b.staticRoleMutex.Lock()
+	defer b.staticRoleMutex.Unlock()
+
+	// There is no queue on performance secondaries
+	if _, err := b.popFromRotationQueueByKey(name); err != nil && err != queue.ErrEmpty {
+		return nil, err
+	}
+
+	// The access keys of the IAM user are left in place, they may still be in
+	// use by clients
+	for _, prefix := range []string{staticRolePrefix, staticCredsPrefix} {
+		if err := req.Storage.Delete(ctx, prefix+name); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathStaticRolesWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	b.staticRoleMutex.Lock()
+	defer b.staticRoleMutex.Unlock()
+
+	role, err := b.staticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		if req.Operation == logical.UpdateOperation {
+			return logical.ErrorResponse(fmt.Sprintf("static role %q does not exist", name)), nil
+		}
+		role = &awsStaticRoleEntry{}
+	}
+
+	if usernameRaw, ok := d.GetOk("username"); ok {
+		username := usernameRaw.(string)
+		if req.Operation == logical.UpdateOperation && username != role.Username {
+			return logical.ErrorResponse("cannot update static role username"), nil
+		}
+		role.Username = username
+	} else if req.Operation == logical.CreateOperation {
+		return logical.ErrorResponse("username is a required field to create a static role"), nil
+	}
+
+	rotationPeriodChanged := false
+	if rotationPeriodRaw, ok := d.GetOk("rotation_period"); ok {
+		rotationPeriodSeconds := rotationPeriodRaw.(int)
+		if rotationPeriodSeconds < queueTickSeconds {
+			// If rotation frequency is specified, and this is an update, the value
+			// must be at least that of the constant queueTickSeconds (5 seconds at
+			// time of writing), otherwise we wont be able to rotate in time
+			return logical.ErrorResponse(fmt.Sprintf("rotation_period must be %d seconds or more", queueTickSeconds)), nil
+		}
+		rotationPeriod := time.Duration(rotationPeriodSeconds) * time.Second
+		rotationPeriodChanged = rotationPeriod != role.RotationPeriod
+		role.RotationPeriod = rotationPeriod
+	} else if req.Operation == logical.CreateOperation {
+		return logical.ErrorResponse("rotation_period is a required field to create a static role"), nil
+	}
+
+	if req.Operation == logical.CreateOperation {
+		iamClient, err := b.clientIAM(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := iamClient.GetUser(&iam.GetUserInput{
+			UserName: aws.String(role.Username),
+		}); err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("unable to find IAM user %q: %s", role.Username, err)), awsutil.CheckAWSError(err)
+		}
+	}
+
+	if err := b.setStaticRole(ctx, req.Storage, name, role); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.Operation == logical.CreateOperation:
+		// Rotate right away, so the access keys are only known to Vault and the
+		// clients reading them
+		if err := b.rotateStaticRole(ctx, req.Storage, name, role); err != nil {
+			b.popFromRotationQueueByKey(name)
+			if deleteErr := req.Storage.Delete(ctx, staticRolePrefix+name); deleteErr != nil {
+				return nil, multierror.Append(err, deleteErr)
+			}
+			return logical.ErrorResponse(fmt.Sprintf("error rotating the access keys of IAM user %q: %s", role.Username, err)), awsutil.CheckAWSError(err)
+		}
+	case rotationPeriodChanged:
+		creds, err := b.staticCreds(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		item, err := b.popFromRotationQueueByKey(name)
+		if err != nil || item == nil {
+			item = &queue.Item{
+				Key: name,
+			}
+		}
+		if creds != nil {
+			item.Priority = creds.LastVaultRotation.Add(role.RotationPeriod).Unix()
+		} else {
+			item.Priority = time.Now().Unix()
+		}
+		if err := b.pushItem(item); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// rotateStaticRole immediately rotates the access keys of a static role and
+// reschedules its next rotation on the queue. If the rotation fails, the queue
+// retries it shortly after. The caller must hold b.staticRoleMutex.
+func (b *backend) rotateStaticRole(ctx context.Context, s logical.Storage, name string, role *awsStaticRoleEntry) error {
+	item, err := b.popFromRotationQueueByKey(name)
+	if err != nil || item == nil {
+		item = &queue.Item{
+			Key: name,
+		}
+	}
+
+	rotationTime, err := b.rotateAccessKeys(ctx, s, name, role)
+	if err != nil {
+		item.Priority = time.Now().Add(10 * time.Second).Unix()
+	} else {
+		item.Priority = rotationTime.Add(role.RotationPeriod).Unix()
+	}
+
+	if pushErr := b.pushItem(item); pushErr != nil {
+		if err != nil {
+			return multierror.Append(err, pushErr)
+		}
+		return pushErr
+	}
+
+	return err
+}
+
+// rotateAccessKeys creates a new access key for the IAM user of a static
+// role and stores it as the current credentials of the role. IAM users have
+// at most two access keys, so the oldest one is deleted first if necessary.
+// The previous access key remains valid until the next rotation.
+func (b *backend) rotateAccessKeys(ctx context.Context, s logical.Storage, name string, role *awsStaticRoleEntry) (time.Time, error) {
+	iamClient, err := b.clientIAM(ctx, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	keysResp, err := iamClient.ListAccessKeys(&iam.ListAccessKeysInput{
+		UserName: aws.String(role.Username),
+	})
+	if err != nil {
+		return time.Time{}, errwrap.Wrapf("error listing access keys: {{err}}", err)
+	}
+	if len(keysResp.AccessKeyMetadata) >= maxAccessKeysPerUser {
+		oldest := keysResp.AccessKeyMetadata[0]
+		for _, k := range keysResp.AccessKeyMetadata[1:] {
+			if k.CreateDate != nil && (oldest.CreateDate == nil || k.CreateDate.Before(*oldest.CreateDate)) {
+				oldest = k
+			}
+		}
+		if _, err := iamClient.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			AccessKeyId: oldest.AccessKeyId,
+			UserName:    aws.String(role.Username),
+		}); err != nil {
+			return time.Time{}, errwrap.Wrapf("error deleting the oldest access key: {{err}}", err)
+		}
+	}
+
+	keyResp, err := iamClient.CreateAccessKey(&iam.CreateAccessKeyInput{
+		UserName: aws.String(role.Username),
+	})
+	if err != nil {
+		return time.Time{}, errwrap.Wrapf("error creating access key: {{err}}", err)
+	}
+	if keyResp.AccessKey == nil || keyResp.AccessKey.AccessKeyId == nil || keyResp.AccessKey.SecretAccessKey == nil {
+		return time.Time{}, fmt.Errorf("nil AccessKeyId or SecretAccessKey returned from CreateAccessKey")
+	}
+
+	creds := &awsStaticCredsEntry{
+		AccessKeyID:       *keyResp.AccessKey.AccessKeyId,
+		SecretAccessKey:   *keyResp.AccessKey.SecretAccessKey,
+		LastVaultRotation: time.Now(),
+	}
+	entry, err := logical.StorageEntryJSON(staticCredsPrefix+name, creds)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		// Don't leave an access key behind that nobody knows about
+		if _, deleteErr := iamClient.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			AccessKeyId: keyResp.AccessKey.AccessKeyId,
+			UserName:    aws.String(role.Username),
+		}); deleteErr != nil {
+			b.Logger().Error("unable to delete access key after failing to store it", "role", name, "access_key", *keyResp.AccessKey.AccessKeyId, "error", deleteErr)
+		}
+		return time.Time{}, errwrap.Wrapf("error storing access key: {{err}}", err)
+	}
+
+	return creds.LastVaultRotation, nil
+}
+
+func (b *backend) staticRole(ctx context.Context, s logical.Storage, name string) (*awsStaticRoleEntry, error) {
+	entry, err := s.Get(ctx, staticRolePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var role awsStaticRoleEntry
+	if err := entry.DecodeJSON(&role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (b *backend) setStaticRole(ctx context.Context, s logical.Storage, name string, role *awsStaticRoleEntry) error {
+	entry, err := logical.StorageEntryJSON(staticRolePrefix+name, role)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+func (b *backend) staticCreds(ctx context.Context, s logical.Storage, name string) (*awsStaticCredsEntry, error) {
+	entry, err := s.Get(ctx, staticCredsPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var creds awsStaticCredsEntry
+	if err := entry.DecodeJSON(&creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+type awsStaticRoleEntry struct {
+	Username       string        `json:"username"`        // Name of the existing IAM user
+	RotationPeriod time.Duration `json:"rotation_period"` // Period between rotations of the access keys
+}
+
+type awsStaticCredsEntry struct {
+	AccessKeyID       string    `json:"access_key_id"`
+	SecretAccessKey   string    `json:"secret_access_key"`
+	LastVaultRotation time.Time `json:"last_vault_rotation"`
+}
+
+// maxAccessKeysPerUser is the number of access keys IAM allows per user
+const maxAccessKeysPerUser = 2
+
+const pathListStaticRolesHelpSyn = `List the existing static roles in this backend`
+
+const pathListStaticRolesHelpDesc = `Static roles will be listed by the role name.`
+
+const pathStaticRolesHelpSyn = `
+Manage the access keys of existing IAM users.
+`
+
+const pathStaticRolesHelpDesc = `
+This path lets you manage static roles, which rotate the access keys of an
+existing IAM user every rotation_period. Vault creates a new access key when
+the role is created and on every rotation, deleting the oldest access key of
+the user if it already has two. The previous access key remains valid until
+the next rotation. The current access key is read from the "static-creds/"
+endpoint.
+
+Deleting a static role leaves the access keys of the IAM user in place.
+`
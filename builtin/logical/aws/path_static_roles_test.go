@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/queue"
+)
+
+func TestBackend_staticRoles(t *testing.T) {
+	b, storage, stub, cleanup := getStubbedBackend(t)
+	defer cleanup()
+	b.credRotationQueue = queue.New()
+	ctx := context.Background()
+
+	stub.Lock()
+	stub.users["third-party"] = []string{"AKIAPREEXISTINGKEY01"}
+	stub.Unlock()
+
+	writeRole := func(operation logical.Operation, name string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: operation,
+			Path:      "static-roles/" + name,
+			Storage:   storage,
+			Data:      data,
+		})
+		if err != nil && (resp == nil || !resp.IsError()) {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	readCreds := func() map[string]interface{} {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      "static-creds/tool",
+			Storage:   storage,
+		})
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("bad: resp:%#v err:%v", resp, err)
+		}
+		return resp.Data
+	}
+
+	if resp := writeRole(logical.CreateOperation, "tool", map[string]interface{}{"username": "third-party"}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error without rotation_period")
+	}
+	if resp := writeRole(logical.CreateOperation, "tool", map[string]interface{}{"username": "missing", "rotation_period": "1h"}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error for a missing IAM user")
+	}
+
+	// Creating the role rotates the access key right away
+	if resp := writeRole(logical.CreateOperation, "tool", map[string]interface{}{"username": "third-party", "rotation_period": "1h"}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	creds := readCreds()
+	if creds["access_key"] != "AKIA0000000000000001" || creds["secret_key"] != "secret-AKIA0000000000000001" || creds["username"] != "third-party" {
+		t.Fatalf("bad creds %#v", creds)
+	}
+	if ttl := creds["ttl"].(int64); ttl <= 3500 || ttl > 3600 {
+		t.Fatalf("bad ttl %d", ttl)
+	}
+	if keys := stub.accessKeys("third-party"); !reflect.DeepEqual(keys, []string{"AKIAPREEXISTINGKEY01", "AKIA0000000000000001"}) {
+		t.Fatalf("bad access keys %v", keys)
+	}
+
+	if resp := writeRole(logical.UpdateOperation, "tool", map[string]interface{}{"username": "other"}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error changing the username")
+	}
+
+	// Rotations are scheduled on the queue; make the next one due
+	item, err := b.popFromRotationQueueByKey("tool")
+	if err != nil || item == nil {
+		t.Fatalf("expected the role on the queue: item:%#v err:%v", item, err)
+	}
+	if next := time.Unix(item.Priority, 0); next.Before(time.Now().Add(59*time.Minute)) || next.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("bad next rotation %s", next)
+	}
+	item.Priority = time.Now().Unix()
+	if err := b.pushItem(item); err != nil {
+		t.Fatal(err)
+	}
+	b.rotateCredentials(ctx, storage)
+
+	// The oldest access key is deleted, the previous one remains valid
+	if creds := readCreds(); creds["access_key"] != "AKIA0000000000000002" {
+		t.Fatalf("bad creds after rotation %#v", creds)
+	}
+	if keys := stub.accessKeys("third-party"); !reflect.DeepEqual(keys, []string{"AKIA0000000000000001", "AKIA0000000000000002"}) {
+		t.Fatalf("bad access keys after rotation %v", keys)
+	}
+
+	// Changing the rotation period reschedules the next rotation
+	if resp := writeRole(logical.UpdateOperation, "tool", map[string]interface{}{"rotation_period": "2h"}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	resp, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "static-roles/tool",
+		Storage:   storage,
+	})
+	if err != nil || resp.Data["rotation_period"] != int64(7200) || resp.Data["username"] != "third-party" {
+		t.Fatalf("bad: resp:%#v err:%v", resp, err)
+	}
+	if item, _ := b.popFromRotationQueueByKey("tool"); item == nil || time.Unix(item.Priority, 0).Before(time.Now().Add(119*time.Minute)) {
+		t.Fatalf("bad rescheduled item %#v", item)
+	}
+
+	// Deleting the role leaves the access keys in place
+	if resp := writeRole(logical.DeleteOperation, "tool", nil); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if creds, err := b.staticCreds(ctx, storage, "tool"); err != nil || creds != nil {
+		t.Fatalf("expected the access key to be removed from storage: creds:%#v err:%v", creds, err)
+	}
+	if keys := stub.accessKeys("third-party"); len(keys) != 2 {
+		t.Fatalf("bad access keys after deletion %v", keys)
+	}
+}
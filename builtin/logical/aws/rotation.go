@@ -0,0 +1,201 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/queue"
+)
+
+const (
+	// Interval to check the queue for static roles needing rotation
+	queueTickSeconds  = 5
+	queueTickInterval = queueTickSeconds * time.Second
+)
+
+// initQueue populates the rotation queue with the existing static roles and
+// launches a go-routine to periodically rotate their access keys. Rotations
+// only happen on the primary server or on local mounts.
+//
+// initQueue is invoked by the Factory method in a go-routine, to avoid
+// blocking the mount process while loading the existing static roles.
+func (b *backend) initQueue(ctx context.Context, conf *logical.BackendConfig) {
+	replicationState := conf.System.ReplicationState()
+	if (!conf.System.LocalMount() && replicationState.HasState(consts.ReplicationPerformanceSecondary)) ||
+		replicationState.HasState(consts.ReplicationDRSecondary) ||
+		replicationState.HasState(consts.ReplicationPerformanceStandby) {
+		return
+	}
+
+	b.Logger().Info("initializing static role rotation queue")
+	b.populateQueue(ctx, conf.StorageView)
+
+	go b.runTicker(ctx, conf.StorageView)
+}
+
+// populateQueue adds the existing static roles to the rotation queue, with
+// the priority of their next rotation
+func (b *backend) populateQueue(ctx context.Context, s logical.Storage) {
+	log := b.Logger()
+
+	roles, err := s.List(ctx, staticRolePrefix)
+	if err != nil {
+		log.Warn("unable to list static roles for enqueueing", "error", err)
+		return
+	}
+
+	for _, name := range roles {
+		select {
+		case <-ctx.Done():
+			log.Info("rotation queue restore cancelled")
+			return
+		default:
+		}
+
+		role, err := b.staticRole(ctx, s, name)
+		if err != nil || role == nil {
+			log.Warn("unable to read static role", "error", err, "role", name)
+			continue
+		}
+		creds, err := b.staticCreds(ctx, s, name)
+		if err != nil {
+			log.Warn("unable to read static role access key", "error", err, "role", name)
+			continue
+		}
+
+		// Roles without an access key are rotated right away
+		item := &queue.Item{
+			Key:      name,
+			Priority: time.Now().Unix(),
+		}
+		if creds != nil {
+			item.Priority = creds.LastVaultRotation.Add(role.RotationPeriod).Unix()
+		}
+		if err := b.pushItem(item); err != nil {
+			log.Warn("unable to enqueue item", "error", err, "role", name)
+		}
+	}
+}
+
+// runTicker periodically rotates the access keys of the static roles that are
+// due, until the context is cancelled
+func (b *backend) runTicker(ctx context.Context, s logical.Storage) {
+	tick := time.NewTicker(queueTickInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			b.rotateCredentials(ctx, s)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// rotateCredentials pops static roles off the rotation queue and rotates
+// their access keys, until it encounters the first role that does not yet
+// need rotation
+func (b *backend) rotateCredentials(ctx context.Context, s logical.Storage) {
+	for {
+		// Quit rotating credentials if shutdown has started
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		item, err := b.popFromRotationQueue()
+		if err != nil || item == nil {
+			return
+		}
+
+		// If "now" is less than the item priority, then this item does not need
+		// to be rotated
+		if time.Now().Unix() < item.Priority {
+			if err := b.pushItem(item); err != nil {
+				b.Logger().Error("unable to push item on to queue", "error", err)
+			}
+			return
+		}
+
+		b.rotateItem(ctx, s, item)
+	}
+}
+
+// rotateItem rotates the access keys of the static role of a queue item,
+// unless the role has been deleted in the meantime
+func (b *backend) rotateItem(ctx context.Context, s logical.Storage, item *queue.Item) {
+	b.staticRoleMutex.Lock()
+	defer b.staticRoleMutex.Unlock()
+
+	role, err := b.staticRole(ctx, s, item.Key)
+	if err != nil {
+		b.Logger().Error("unable to load static role", "role", item.Key, "error", err)
+		item.Priority = time.Now().Add(10 * time.Second).Unix()
+		if err := b.pushItem(item); err != nil {
+			b.Logger().Error("unable to push item on to queue", "error", err)
+		}
+		return
+	}
+	if role == nil {
+		return
+	}
+
+	if err := b.rotateStaticRole(ctx, s, item.Key, role); err != nil {
+		b.Logger().Error("unable to rotate access keys of static role", "role", item.Key, "error", err)
+	}
+}
+
+// pushItem wraps the internal queue's Push call, to make sure a queue is
+// actually available. This is needed because both runTicker and initQueue
+// operate in go-routines, and could be accessing the queue concurrently
+func (b *backend) pushItem(item *queue.Item) error {
+	b.queueMutex.RLock()
+	defer b.queueMutex.RUnlock()
+
+	if b.credRotationQueue != nil {
+		return b.credRotationQueue.Push(item)
+	}
+
+	b.Logger().Warn("no queue found during push item")
+	return nil
+}
+
+// popFromRotationQueue wraps the internal queue's Pop call, to make sure a
+// queue is actually available
+func (b *backend) popFromRotationQueue() (*queue.Item, error) {
+	b.queueMutex.RLock()
+	defer b.queueMutex.RUnlock()
+
+	if b.credRotationQueue != nil {
+		return b.credRotationQueue.Pop()
+	}
+	return nil, queue.ErrEmpty
+}
+
+// popFromRotationQueueByKey wraps the internal queue's PopByKey call, to make
+// sure a queue is actually available
+func (b *backend) popFromRotationQueueByKey(name string) (*queue.Item, error) {
+	b.queueMutex.RLock()
+	defer b.queueMutex.RUnlock()
+
+	if b.credRotationQueue != nil {
+		return b.credRotationQueue.PopByKey(name)
+	}
+	return nil, queue.ErrEmpty
+}
+
+// invalidateQueue cancels any background queue loading and rotation, and
+// destroys the queue
+func (b *backend) invalidateQueue() {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+
+	if b.cancelQueue != nil {
+		b.cancelQueue()
+	}
+	b.credRotationQueue = nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"regexp"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -66,8 +67,8 @@ func genUsername(displayName, policyName, userType string) (ret string, warning
 }
 
 func (b *backend) secretTokenCreate(ctx context.Context, s logical.Storage,
-	displayName, policyName, policy string,
-	lifeTimeInSeconds int64) (*logical.Response, error) {
+	displayName, policyName, policy string, policyARNs []string,
+	sessionTags map[string]string, lifeTimeInSeconds int64) (*logical.Response, error) {
 	stsClient, err := b.clientSTS(ctx, s)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), nil
@@ -75,12 +76,20 @@ func (b *backend) secretTokenCreate(ctx context.Context, s logical.Storage,
 
 	username, usernameWarning := genUsername(displayName, policyName, "sts")
 
-	tokenResp, err := stsClient.GetFederationToken(
-		&sts.GetFederationTokenInput{
-			Name:            aws.String(username),
-			Policy:          aws.String(policy),
-			DurationSeconds: &lifeTimeInSeconds,
-		})
+	getTokenInput := &sts.GetFederationTokenInput{
+		Name:            aws.String(username),
+		DurationSeconds: &lifeTimeInSeconds,
+	}
+	if policy != "" {
+		getTokenInput.SetPolicy(policy)
+	}
+	if len(policyARNs) > 0 {
+		getTokenInput.SetPolicyArns(convertPolicyARNs(policyARNs))
+	}
+	if len(sessionTags) > 0 {
+		getTokenInput.SetTags(convertSessionTags(sessionTags))
+	}
+	tokenResp, err := stsClient.GetFederationToken(getTokenInput)
 
 	if err != nil {
 		return logical.ErrorResponse(fmt.Sprintf(
@@ -111,7 +120,8 @@ func (b *backend) secretTokenCreate(ctx context.Context, s logical.Storage,
 }
 
 func (b *backend) assumeRole(ctx context.Context, s logical.Storage,
-	displayName, roleName, roleArn, policy string,
+	displayName, roleName, roleArn, policy string, policyARNs []string,
+	sessionTags map[string]string, externalID string,
 	lifeTimeInSeconds int64) (*logical.Response, error) {
 	stsClient, err := b.clientSTS(ctx, s)
 	if err != nil {
@@ -128,6 +138,15 @@ func (b *backend) assumeRole(ctx context.Context, s logical.Storage,
 	if policy != "" {
 		assumeRoleInput.SetPolicy(policy)
 	}
+	if len(policyARNs) > 0 {
+		assumeRoleInput.SetPolicyArns(convertPolicyARNs(policyARNs))
+	}
+	if len(sessionTags) > 0 {
+		assumeRoleInput.SetTags(convertSessionTags(sessionTags))
+	}
+	if externalID != "" {
+		assumeRoleInput.SetExternalId(externalID)
+	}
 	tokenResp, err := stsClient.AssumeRole(assumeRoleInput)
 
 	if err != nil {
@@ -185,11 +204,16 @@ func (b *backend) secretAccessKeysCreate(
 		userPath = "/"
 	}
 
-	// Create the user
-	_, err = iamClient.CreateUser(&iam.CreateUserInput{
+	createUserInput := &iam.CreateUserInput{
 		UserName: aws.String(username),
 		Path:     aws.String(userPath),
-	})
+	}
+	if role.PermissionsBoundaryARN != "" {
+		createUserInput.SetPermissionsBoundary(role.PermissionsBoundaryARN)
+	}
+
+	// Create the user
+	_, err = iamClient.CreateUser(createUserInput)
 	if err != nil {
 		if walErr := framework.DeleteWAL(ctx, s, walID); walErr != nil {
 			iamErr := errwrap.Wrapf("error creating IAM user: {{err}}", err)
@@ -330,6 +354,38 @@ func (b *backend) secretAccessKeysRevoke(ctx context.Context, req *logical.Reque
 	return nil, nil
 }
 
+// convertPolicyARNs returns the policy ARNs as the session policies of STS
+// calls
+func convertPolicyARNs(policyARNs []string) []*sts.PolicyDescriptorType {
+	size := len(policyARNs)
+	retval := make([]*sts.PolicyDescriptorType, size, size)
+	for i, arn := range policyARNs {
+		retval[i] = &sts.PolicyDescriptorType{
+			Arn: aws.String(arn),
+		}
+	}
+	return retval
+}
+
+// convertSessionTags returns the session tags of STS calls, sorted by key so
+// the requests are deterministic
+func convertSessionTags(sessionTags map[string]string) []*sts.Tag {
+	keys := make([]string, 0, len(sessionTags))
+	for k := range sessionTags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	retval := make([]*sts.Tag, 0, len(keys))
+	for _, k := range keys {
+		retval = append(retval, &sts.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(sessionTags[k]),
+		})
+	}
+	return retval
+}
+
 func normalizeDisplayName(displayName string) string {
 	re := regexp.MustCompile("[^a-zA-Z0-9+=,.@_-]")
 	return re.ReplaceAllString(displayName, "_")
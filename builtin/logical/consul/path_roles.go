@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
@@ -41,6 +44,32 @@ for 'client' tokens. Required for Consul pre-1.4.`,
 for Consul 1.4 or above.`,
 			},
 
+			"consul_roles": &framework.FieldSchema{
+				Type: framework.TypeCommaStringSlice,
+				Description: `List of Consul roles to attach to the token.
+Available in Consul 1.5 and above.`,
+			},
+
+			"service_identities": &framework.FieldSchema{
+				Type: framework.TypeStringSlice,
+				Description: `List of service identities to attach to the token,
+in the form "service-name" or "service-name:dc1,dc2", separated by
+semicolons. Available in Consul 1.5 and above.`,
+			},
+
+			"node_identities": &framework.FieldSchema{
+				Type: framework.TypeStringSlice,
+				Description: `List of node identities to attach to the token,
+in the form "node-name:datacenter", separated by semicolons. Available
+in Consul 1.8.1 and above.`,
+			},
+
+			"consul_namespace": &framework.FieldSchema{
+				Type: framework.TypeString,
+				Description: `Consul Enterprise namespace to create the token in.
+Available in Consul 1.7 and above.`,
+			},
+
 			"local": &framework.FieldSchema{
 				Type: framework.TypeBool,
 				Description: `Indicates that the token should not be replicated globally 
@@ -126,6 +155,18 @@ func (b *backend) pathRolesRead(ctx context.Context, req *logical.Request, d *fr
 	if len(result.Policies) > 0 {
 		resp.Data["policies"] = result.Policies
 	}
+	if len(result.ConsulRoles) > 0 {
+		resp.Data["consul_roles"] = result.ConsulRoles
+	}
+	if len(result.ServiceIdentities) > 0 {
+		resp.Data["service_identities"] = formatServiceIdentities(result.ServiceIdentities)
+	}
+	if len(result.NodeIdentities) > 0 {
+		resp.Data["node_identities"] = formatNodeIdentities(result.NodeIdentities)
+	}
+	if result.ConsulNamespace != "" {
+		resp.Data["consul_namespace"] = result.ConsulNamespace
+	}
 	return resp, nil
 }
 
@@ -134,14 +175,34 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 	policy := d.Get("policy").(string)
 	name := d.Get("name").(string)
 	policies := d.Get("policies").([]string)
+	consulRoles := d.Get("consul_roles").([]string)
 	local := d.Get("local").(bool)
+	consulNamespace := d.Get("consul_namespace").(string)
+
+	serviceIdentities, err := parseServiceIdentities(d.Get("service_identities").([]string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	nodeIdentities, err := parseNodeIdentities(d.Get("node_identities").([]string))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	// Roles and identities are only supported by tokens of Consul 1.4 and
+	// above, like policies
+	hasACLs := len(policies) > 0 || len(consulRoles) > 0 ||
+		len(serviceIdentities) > 0 || len(nodeIdentities) > 0
+	if !hasACLs && consulNamespace != "" {
+		return logical.ErrorResponse(
+			"consul_namespace requires policies, consul_roles, service_identities or node_identities"), nil
+	}
 
-	if len(policies) == 0 {
+	if !hasACLs {
 		switch tokenType {
 		case "client":
 			if policy == "" {
 				return logical.ErrorResponse(
-					"Use either a policy document, or a list of policies, depending on your Consul version"), nil
+					"Use either a policy document, or a list of policies, roles or identities, depending on your Consul version"), nil
 			}
 		case "management":
 		default:
@@ -174,12 +235,16 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 	}
 
 	entry, err := logical.StorageEntryJSON("policy/"+name, roleConfig{
-		Policy:    string(policyRaw),
-		Policies:  policies,
-		TokenType: tokenType,
-		TTL:       ttl,
-		MaxTTL:    maxTTL,
-		Local:     local,
+		Policy:            string(policyRaw),
+		Policies:          policies,
+		ConsulRoles:       consulRoles,
+		ServiceIdentities: serviceIdentities,
+		NodeIdentities:    nodeIdentities,
+		ConsulNamespace:   consulNamespace,
+		TokenType:         tokenType,
+		TTL:               ttl,
+		MaxTTL:            maxTTL,
+		Local:             local,
 	})
 	if err != nil {
 		return nil, err
@@ -201,10 +266,95 @@ func (b *backend) pathRolesDelete(ctx context.Context, req *logical.Request, d *
 }
 
 type roleConfig struct {
-	Policy    string        `json:"policy"`
-	Policies  []string      `json:"policies"`
-	TTL       time.Duration `json:"lease"`
-	MaxTTL    time.Duration `json:"max_ttl"`
-	TokenType string        `json:"token_type"`
-	Local     bool          `json:"local"`
+	Policy            string                    `json:"policy"`
+	Policies          []string                  `json:"policies"`
+	ConsulRoles       []string                  `json:"consul_roles"`
+	ServiceIdentities []*api.ACLServiceIdentity `json:"service_identities"`
+	NodeIdentities    []*aclNodeIdentity        `json:"node_identities"`
+	ConsulNamespace   string                    `json:"consul_namespace"`
+	TTL               time.Duration             `json:"lease"`
+	MaxTTL            time.Duration             `json:"max_ttl"`
+	TokenType         string                    `json:"token_type"`
+	Local             bool                      `json:"local"`
+}
+
+// aclNodeIdentity is a node identity of a Consul ACL token. The vendored
+// Consul API client predates node identities.
+type aclNodeIdentity struct {
+	NodeName   string
+	Datacenter string
+}
+
+// splitIdentities splits the identities of a role, which may be given as a
+// list or as a single string separated by semicolons
+func splitIdentities(raw []string) []string {
+	var identities []string
+	for _, entry := range raw {
+		for _, identity := range strings.Split(entry, ";") {
+			if identity = strings.TrimSpace(identity); identity != "" {
+				identities = append(identities, identity)
+			}
+		}
+	}
+	return identities
+}
+
+// parseServiceIdentities parses service identities in the form
+// "service-name" or "service-name:dc1,dc2"
+func parseServiceIdentities(raw []string) ([]*api.ACLServiceIdentity, error) {
+	var identities []*api.ACLServiceIdentity
+	for _, identity := range splitIdentities(raw) {
+		parts := strings.SplitN(identity, ":", 2)
+		serviceName := strings.TrimSpace(parts[0])
+		if serviceName == "" {
+			return nil, fmt.Errorf("invalid service identity %q: missing service name", identity)
+		}
+
+		serviceIdentity := &api.ACLServiceIdentity{
+			ServiceName: serviceName,
+		}
+		if len(parts) == 2 {
+			serviceIdentity.Datacenters = strutil.ParseDedupAndSortStrings(parts[1], ",")
+		}
+		identities = append(identities, serviceIdentity)
+	}
+	return identities, nil
+}
+
+// parseNodeIdentities parses node identities in the form
+// "node-name:datacenter"
+func parseNodeIdentities(raw []string) ([]*aclNodeIdentity, error) {
+	var identities []*aclNodeIdentity
+	for _, identity := range splitIdentities(raw) {
+		parts := strings.SplitN(identity, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid node identity %q: expected the form \"node-name:datacenter\"", identity)
+		}
+
+		identities = append(identities, &aclNodeIdentity{
+			NodeName:   strings.TrimSpace(parts[0]),
+			Datacenter: strings.TrimSpace(parts[1]),
+		})
+	}
+	return identities, nil
+}
+
+func formatServiceIdentities(identities []*api.ACLServiceIdentity) []string {
+	var formatted []string
+	for _, identity := range identities {
+		if len(identity.Datacenters) == 0 {
+			formatted = append(formatted, identity.ServiceName)
+			continue
+		}
+		formatted = append(formatted, identity.ServiceName+":"+strings.Join(identity.Datacenters, ","))
+	}
+	return formatted
+}
+
+func formatNodeIdentities(identities []*aclNodeIdentity) []string {
+	var formatted []string
+	for _, identity := range identities {
+		formatted = append(formatted, identity.NodeName+":"+identity.Datacenter)
+	}
+	return formatted
 }
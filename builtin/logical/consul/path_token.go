@@ -97,27 +97,53 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 			Name: policyName,
 		})
 	}
-	token, _, err := c.ACL().TokenCreate(&api.ACLToken{
-		Description: tokenName,
-		Policies:    policyLink,
-		Local:       result.Local,
-	}, writeOpts)
-	if err != nil {
+	var roleLink = []*api.ACLTokenRoleLink{}
+	for _, roleName := range result.ConsulRoles {
+		roleLink = append(roleLink, &api.ACLTokenRoleLink{
+			Name: roleName,
+		})
+	}
+	writeOpts.Namespace = result.ConsulNamespace
+
+	// The token is written raw, as the vendored Consul API client predates
+	// node identities
+	var token api.ACLToken
+	if _, err := c.Raw().Write("/v1/acl/token", &aclToken{
+		ACLToken: api.ACLToken{
+			Description:       tokenName,
+			Policies:          policyLink,
+			Roles:             roleLink,
+			ServiceIdentities: result.ServiceIdentities,
+			Local:             result.Local,
+			Namespace:         result.ConsulNamespace,
+		},
+		NodeIdentities: result.NodeIdentities,
+	}, &token, writeOpts); err != nil {
 		return logical.ErrorResponse(err.Error()), nil
 	}
 
 	// Use the helper to create the secret
 	s := b.Secret(SecretTokenType).Response(map[string]interface{}{
-		"token":    token.SecretID,
-		"accessor": token.AccessorID,
-		"local":    token.Local,
+		"token":            token.SecretID,
+		"accessor":         token.AccessorID,
+		"local":            token.Local,
+		"consul_namespace": token.Namespace,
 	}, map[string]interface{}{
-		"token":   token.AccessorID,
-		"role":    role,
-		"version": tokenPolicyType,
+		"token":            token.AccessorID,
+		"accessor":         token.AccessorID,
+		"role":             role,
+		"version":          tokenPolicyType,
+		"consul_namespace": result.ConsulNamespace,
 	})
 	s.Secret.TTL = result.TTL
 	s.Secret.MaxTTL = result.MaxTTL
 
 	return s, nil
 }
+
+// aclToken is an ACL token of Consul 1.4 and above, with the node identities
+// the vendored Consul API client does not know about
+type aclToken struct {
+	api.ACLToken
+	NodeIdentities []*aclNodeIdentity `json:",omitempty"`
+}
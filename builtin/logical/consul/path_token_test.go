@@ -0,0 +1,189 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// stubConsul is a stub of the Consul ACL token API recording the tokens it
+// creates and deletes
+type stubConsul struct {
+	sync.Mutex
+	created    []map[string]interface{}
+	namespaces []string
+	deleted    []string
+}
+
+func (s *stubConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if r.Header.Get("X-Consul-Token") != "root" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.namespaces = append(s.namespaces, r.URL.Query().Get("ns"))
+
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/acl/token":
+		var token map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&token); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.created = append(s.created, token)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"AccessorID": "6a1253d2-1785-24fd-91c2-f8e78c745511",
+			"SecretID":   "45a3bd52-07c7-47a4-52fd-0745e0cfe967",
+			"Local":      token["Local"],
+			"Namespace":  token["Namespace"],
+		})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/acl/token/"):
+		s.deleted = append(s.deleted, strings.TrimPrefix(r.URL.Path, "/v1/acl/token/"))
+		w.Write([]byte("true"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestBackend_identitiesStubbed(t *testing.T) {
+	stub := &stubConsul{}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b, err := Factory(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      path,
+			Storage:   config.StorageView,
+			Data:      data,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	write("config/access", map[string]interface{}{
+		"address": strings.TrimPrefix(srv.URL, "http://"),
+		"token":   "root",
+	})
+
+	if resp := write("roles/web", map[string]interface{}{
+		"node_identities": "node-1",
+	}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error for a node identity without a datacenter")
+	}
+	if resp := write("roles/web", map[string]interface{}{
+		"policy":           "a2V5ICIiIHsgcG9saWN5ID0gInJlYWQiIH0=",
+		"consul_namespace": "team-a",
+	}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error for a namespaced legacy token")
+	}
+
+	if resp := write("roles/web", map[string]interface{}{
+		"consul_roles":       "web-role,monitoring",
+		"service_identities": []string{"web:dc1, dc2", "api"},
+		"node_identities":    "node-1:dc1;node-2:dc2",
+		"consul_namespace":   "team-a",
+		"local":              true,
+	}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "roles/web",
+		Storage:   config.StorageView,
+	})
+	if err != nil || resp == nil {
+		t.Fatalf("bad: resp:%#v err:%v", resp, err)
+	}
+	expectedRole := map[string]interface{}{
+		"consul_roles":       []string{"web-role", "monitoring"},
+		"service_identities": []string{"web:dc1,dc2", "api"},
+		"node_identities":    []string{"node-1:dc1", "node-2:dc2"},
+		"consul_namespace":   "team-a",
+		"local":              true,
+	}
+	for k, v := range expectedRole {
+		if !reflect.DeepEqual(resp.Data[k], v) {
+			t.Fatalf("bad %s: expected %#v, got %#v", k, v, resp.Data[k])
+		}
+	}
+
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        "creds/web",
+		Storage:     config.StorageView,
+		DisplayName: "token",
+	})
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("bad: resp:%#v err:%v", resp, err)
+	}
+	if resp.Data["accessor"] != "6a1253d2-1785-24fd-91c2-f8e78c745511" || resp.Data["consul_namespace"] != "team-a" {
+		t.Fatalf("bad secret data %#v", resp.Data)
+	}
+	if resp.Secret.InternalData["accessor"] != "6a1253d2-1785-24fd-91c2-f8e78c745511" {
+		t.Fatalf("bad secret internal data %#v", resp.Secret.InternalData)
+	}
+
+	stub.Lock()
+	created := stub.created[0]
+	namespace := stub.namespaces[0]
+	stub.Unlock()
+	if namespace != "team-a" || created["Namespace"] != "team-a" || created["Local"] != true {
+		t.Fatalf("bad namespace %q in token %#v", namespace, created)
+	}
+	expectedToken := map[string]interface{}{
+		"Roles": []interface{}{
+			map[string]interface{}{"ID": "", "Name": "web-role"},
+			map[string]interface{}{"ID": "", "Name": "monitoring"},
+		},
+		"ServiceIdentities": []interface{}{
+			map[string]interface{}{"ServiceName": "web", "Datacenters": []interface{}{"dc1", "dc2"}},
+			map[string]interface{}{"ServiceName": "api"},
+		},
+		"NodeIdentities": []interface{}{
+			map[string]interface{}{"NodeName": "node-1", "Datacenter": "dc1"},
+			map[string]interface{}{"NodeName": "node-2", "Datacenter": "dc2"},
+		},
+	}
+	for k, v := range expectedToken {
+		if !reflect.DeepEqual(created[k], v) {
+			t.Fatalf("bad %s: expected %#v, got %#v", k, v, created[k])
+		}
+	}
+
+	// Tokens are revoked by accessor in their namespace
+	secret := resp.Secret
+	secret.InternalData["secret_type"] = SecretTokenType
+	if _, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.RevokeOperation,
+		Storage:   config.StorageView,
+		Secret:    secret,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	stub.Lock()
+	defer stub.Unlock()
+	if !reflect.DeepEqual(stub.deleted, []string{"6a1253d2-1785-24fd-91c2-f8e78c745511"}) || stub.namespaces[len(stub.namespaces)-1] != "team-a" {
+		t.Fatalf("bad revocation: deleted:%v namespaces:%v", stub.deleted, stub.namespaces)
+	}
+}
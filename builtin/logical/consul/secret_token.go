@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -21,6 +22,10 @@ func secretToken(b *backend) *framework.Secret {
 				Type:        framework.TypeString,
 				Description: "Request token",
 			},
+			"accessor": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "Accessor of the request token",
+			},
 		},
 
 		Renew:  b.secretTokenRenew,
@@ -90,7 +95,11 @@ func (b *backend) secretTokenRevoke(ctx context.Context, req *logical.Request, d
 			return nil, err
 		}
 	case tokenPolicyType:
-		_, err := c.ACL().TokenDelete(tokenRaw.(string), nil)
+		writeOpts := &api.WriteOptions{}
+		if namespaceRaw, ok := req.Secret.InternalData["consul_namespace"]; ok {
+			writeOpts.Namespace = namespaceRaw.(string)
+		}
+		_, err := c.ACL().TokenDelete(tokenRaw.(string), writeOpts)
 		if err != nil {
 			return nil, err
 		}
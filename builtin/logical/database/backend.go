@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/errwrap"
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/database/helper/dbutil"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/locksutil"
@@ -28,7 +29,7 @@ const (
 
 type dbPluginInstance struct {
 	sync.RWMutex
-	dbplugin.Database
+	databaseVersionWrapper
 
 	id     string
 	name   string
@@ -44,7 +45,7 @@ func (dbi *dbPluginInstance) Close() error {
 	}
 	dbi.closed = true
 
-	return dbi.Database.Close()
+	return dbi.databaseVersionWrapper.Close()
 }
 
 func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
@@ -86,12 +87,16 @@ func Backend(conf *logical.BackendConfig) *databaseBackend {
 			},
 			pathListRoles(&b),
 			pathRoles(&b),
+			pathTestRole(&b),
 			pathCredsCreate(&b),
 			pathRotateCredentials(&b),
+			pathListLibrary(&b),
+			pathLibrary(&b),
 		),
 
 		Secrets: []*framework.Secret{
 			secretCreds(&b),
+			secretCheckOut(&b),
 		},
 		Clean:       b.clean,
 		Invalidate:  b.invalidate,
@@ -126,6 +131,10 @@ type databaseBackend struct {
 	// concurrent requests are not modifying the same role and possibly causing
 	// issues with the priority queue.
 	roleLocks []*locksutil.LockEntry
+
+	// checkOutLock serializes changes to library sets and the check-out
+	// status of their static roles
+	checkOutLock sync.Mutex
 }
 
 func (b *databaseBackend) DatabaseConfig(ctx context.Context, s logical.Storage, name string) (*DatabaseConfig, error) {
@@ -247,14 +256,18 @@ func (b *databaseBackend) GetConnection(ctx context.Context, s logical.Storage,
 		return nil, err
 	}
 
-	dbp, err := dbplugin.PluginFactory(ctx, config.PluginName, b.System(), b.logger)
+	dbw, err := newDatabaseWrapper(ctx, config.PluginName, b.System(), b.logger)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = dbp.Init(ctx, config.ConnectionDetails, true)
+	initReq := v5.InitializeRequest{
+		Config:           config.ConnectionDetails,
+		VerifyConnection: true,
+	}
+	_, err = dbw.Initialize(ctx, initReq)
 	if err != nil {
-		dbp.Close()
+		dbw.Close()
 		return nil, err
 	}
 
@@ -264,9 +277,9 @@ func (b *databaseBackend) GetConnection(ctx context.Context, s logical.Storage,
 	}
 
 	db = &dbPluginInstance{
-		Database: dbp,
-		name:     name,
-		id:       id,
+		databaseVersionWrapper: dbw,
+		name:                   name,
+		id:                     id,
 	}
 
 	b.connections[name] = db
@@ -305,7 +318,7 @@ func (b *databaseBackend) clearConnection(name string) error {
 func (b *databaseBackend) CloseIfShutdown(db *dbPluginInstance, err error) {
 	// Plugin has shutdown, close it so next call can reconnect.
 	switch err {
-	case rpc.ErrShutdown, dbplugin.ErrPluginShutdown:
+	case rpc.ErrShutdown, dbplugin.ErrPluginShutdown, v5.ErrPluginShutdown:
 		// Put this in a goroutine so that requests can run with the read or write lock
 		// and simply defer the unlock.  Since we are attaching the instance and matching
 		// the id in the connection map, we can safely do this.
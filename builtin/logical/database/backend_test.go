@@ -241,6 +241,8 @@ func TestBackend_config_connection(t *testing.T) {
 			},
 			"allowed_roles":                      []string{"*"},
 			"root_credentials_rotate_statements": []string{},
+			"password_policy":                    "",
+			"username_template":                  "",
 		}
 		configReq.Operation = logical.ReadOperation
 		resp, err = b.HandleRequest(namespace.RootContext(nil), configReq)
@@ -254,6 +256,55 @@ func TestBackend_config_connection(t *testing.T) {
 		}
 	}
 
+	// Test password policies, which version 4 plugins can't apply to the
+	// passwords they generate
+	{
+		_, err := cluster.Cores[0].Client.Logical().Write("sys/policies/password/digits", map[string]interface{}{
+			"policy": `length = 24 rule "charset" { charset = "0123456789" }`,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		configReq := &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "config/plugin-test",
+			Storage:   config.StorageView,
+			Data: map[string]interface{}{
+				"password_policy": "digits",
+			},
+		}
+		resp, err = b.HandleRequest(namespace.RootContext(nil), configReq)
+		if err != nil || resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "version 5") {
+			t.Fatalf("expected error response, got err:%v resp:%#v\n", err, resp)
+		}
+	}
+
+	// Test invalid username templates
+	{
+		configReq := &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "config/plugin-test",
+			Storage:   config.StorageView,
+			Data: map[string]interface{}{
+				"connection_url":    "sample_connection_url",
+				"plugin_name":       "postgresql-database-plugin",
+				"verify_connection": false,
+				"username_template": "{{.RoleName",
+			},
+		}
+		resp, err = b.HandleRequest(namespace.RootContext(nil), configReq)
+		if err != nil || resp == nil || !resp.IsError() {
+			t.Fatalf("expected error response, got err:%v resp:%#v\n", err, resp)
+		}
+
+		configReq.Data["username_template"] = "{{.Missing}}"
+		resp, err = b.HandleRequest(namespace.RootContext(nil), configReq)
+		if err != nil || resp == nil || !resp.IsError() {
+			t.Fatalf("expected error response, got err:%v resp:%#v\n", err, resp)
+		}
+	}
+
 	// Test existence check and an update to a single connection detail parameter
 	{
 		configData := map[string]interface{}{
@@ -293,6 +344,8 @@ func TestBackend_config_connection(t *testing.T) {
 			},
 			"allowed_roles":                      []string{"*"},
 			"root_credentials_rotate_statements": []string{},
+			"password_policy":                    "",
+			"username_template":                  "",
 		}
 		configReq.Operation = logical.ReadOperation
 		resp, err = b.HandleRequest(namespace.RootContext(nil), configReq)
@@ -334,6 +387,8 @@ func TestBackend_config_connection(t *testing.T) {
 			},
 			"allowed_roles":                      []string{"flu", "barre"},
 			"root_credentials_rotate_statements": []string{},
+			"password_policy":                    "",
+			"username_template":                  "",
 		}
 		configReq.Operation = logical.ReadOperation
 		resp, err = b.HandleRequest(namespace.RootContext(nil), configReq)
@@ -721,6 +776,8 @@ func TestBackend_connectionCrud(t *testing.T) {
 		},
 		"allowed_roles":                      []string{"plugin-role-test"},
 		"root_credentials_rotate_statements": []string(nil),
+		"password_policy":                    "",
+		"username_template":                  "",
 	}
 	req.Operation = logical.ReadOperation
 	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/helper/certutil"
+	"github.com/hashicorp/vault/sdk/helper/random"
+	"github.com/hashicorp/vault/sdk/helper/template"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	defaultRSAKeyBits            = 2048
+	defaultCommonNameTemplate    = "v-{{.RoleName}}-{{random 20}}"
+	defaultCertificateKeyType    = "rsa"
+	defaultCertificateRSAKeyBits = 2048
+	defaultCertificateECKeyBits  = 256
+)
+
+// defaultPasswordGenerator generates the passwords of users of version 5
+// plugins when no password policy is configured
+var defaultPasswordGenerator = mustParsePolicy(`
+length = 20
+rule "charset" {
+  charset = "abcdefghijklmnopqrstuvwxyz"
+  min-chars = 1
+}
+rule "charset" {
+  charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+  min-chars = 1
+}
+rule "charset" {
+  charset = "0123456789"
+  min-chars = 1
+}
+`)
+
+func mustParsePolicy(raw string) *random.StringGenerator {
+	g, err := random.ParsePolicy(raw)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// generatePassword generates a password for a user of db, using the password
+// policy if one is set
+func (b *databaseBackend) generatePassword(ctx context.Context, db databaseVersionWrapper, passwordPolicy string) (string, error) {
+	if passwordPolicy != "" {
+		return b.System().GeneratePasswordFromPolicy(ctx, passwordPolicy)
+	}
+	if db.isV4() {
+		return db.GeneratePassword(ctx)
+	}
+	return defaultPasswordGenerator.Generate(ctx, rand.Reader)
+}
+
+// rsaKeyConfig is the credential_config of roles issuing RSA key pairs
+type rsaKeyConfig struct {
+	KeyBits int `mapstructure:"key_bits"`
+}
+
+// clientCertificateConfig is the credential_config of roles issuing client
+// certificates
+type clientCertificateConfig struct {
+	CACert             string `mapstructure:"ca_cert"`
+	CAPrivateKey       string `mapstructure:"ca_private_key"`
+	CommonNameTemplate string `mapstructure:"common_name_template"`
+	KeyType            string `mapstructure:"key_type"`
+	KeyBits            int    `mapstructure:"key_bits"`
+}
+
+// parseCredentialConfig decodes and validates the credential_config of a
+// role, returning a *rsaKeyConfig or *clientCertificateConfig depending on
+// the credential type. Password credentials take no configuration.
+func parseCredentialConfig(credentialType v5.CredentialType, raw map[string]interface{}) (interface{}, error) {
+	switch credentialType {
+	case v5.CredentialTypePassword:
+		if len(raw) > 0 {
+			return nil, errors.New("credential_config is not supported for password credentials")
+		}
+		return nil, nil
+
+	case v5.CredentialTypeRSAPrivateKey:
+		config := &rsaKeyConfig{}
+		if err := decodeCredentialConfig(raw, config); err != nil {
+			return nil, err
+		}
+		if config.KeyBits == 0 {
+			config.KeyBits = defaultRSAKeyBits
+		}
+		if err := certutil.ValidateKeyTypeLength("rsa", config.KeyBits); err != nil {
+			return nil, err
+		}
+		return config, nil
+
+	case v5.CredentialTypeClientCertificate:
+		config := &clientCertificateConfig{}
+		if err := decodeCredentialConfig(raw, config); err != nil {
+			return nil, err
+		}
+		if config.CACert == "" || config.CAPrivateKey == "" {
+			return nil, errors.New("ca_cert and ca_private_key are required for client certificate credentials")
+		}
+		if _, err := config.signingBundle(); err != nil {
+			return nil, err
+		}
+		if config.CommonNameTemplate == "" {
+			config.CommonNameTemplate = defaultCommonNameTemplate
+		}
+		if _, err := template.NewTemplate(config.CommonNameTemplate); err != nil {
+			return nil, errwrap.Wrapf("invalid common_name_template: {{err}}", err)
+		}
+		if config.KeyType == "" {
+			config.KeyType = defaultCertificateKeyType
+		}
+		if config.KeyBits == 0 {
+			config.KeyBits = defaultCertificateRSAKeyBits
+			if config.KeyType == "ec" {
+				config.KeyBits = defaultCertificateECKeyBits
+			}
+		}
+		if err := certutil.ValidateKeyTypeLength(config.KeyType, config.KeyBits); err != nil {
+			return nil, err
+		}
+		return config, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported credential type %q", credentialType)
+	}
+}
+
+func decodeCredentialConfig(raw map[string]interface{}, config interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           config,
+		WeaklyTypedInput: true,
+		ErrorUnused:      true,
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return errwrap.Wrapf("invalid credential_config: {{err}}", err)
+	}
+	return nil
+}
+
+// generateRSAKey generates an RSA key pair, returning the PEM encoded PKCS#1
+// private key and PKIX public key
+func (c *rsaKeyConfig) generateRSAKey() (privateKey string, publicKey []byte, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, c.KeyBits)
+	if err != nil {
+		return "", nil, errwrap.Wrapf("error generating RSA key: {{err}}", err)
+	}
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return "", nil, errwrap.Wrapf("error marshalling public key: {{err}}", err)
+	}
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicKeyDER,
+	})
+	return string(privateKeyPEM), publicKeyPEM, nil
+}
+
+func (c *clientCertificateConfig) signingBundle() (*certutil.CAInfoBundle, error) {
+	parsed, err := certutil.ParsePEMBundle(c.CACert + "\n" + c.CAPrivateKey)
+	if err != nil {
+		return nil, errwrap.Wrapf("unable to parse CA certificate and private key: {{err}}", err)
+	}
+	if parsed.Certificate == nil || parsed.PrivateKey == nil {
+		return nil, errors.New("ca_cert and ca_private_key must contain a certificate and its private key")
+	}
+	return &certutil.CAInfoBundle{ParsedCertBundle: *parsed}, nil
+}
+
+// subject renders the subject of a client certificate for a new user
+func (c *clientCertificateConfig) subject(usernameConfig v5.UsernameMetadata) (pkix.Name, error) {
+	tmpl, err := template.NewTemplate(c.CommonNameTemplate)
+	if err != nil {
+		return pkix.Name{}, err
+	}
+	commonName, err := tmpl.Generate(usernameTemplateData{
+		DisplayName: usernameConfig.DisplayName,
+		RoleName:    usernameConfig.RoleName,
+	})
+	if err != nil {
+		return pkix.Name{}, err
+	}
+	if commonName == "" {
+		return pkix.Name{}, errors.New("common_name_template renders an empty common name")
+	}
+	return pkix.Name{CommonName: commonName}, nil
+}
+
+// issueCertificate issues a client certificate valid until expiration,
+// returning its bundle with the PEM encoded certificate and private key
+func (c *clientCertificateConfig) issueCertificate(subject pkix.Name, expiration time.Time) (*certutil.CertBundle, error) {
+	signingBundle, err := c.signingBundle()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := certutil.CreateCertificate(&certutil.CreationBundle{
+		Params: &certutil.CreationParameters{
+			Subject:     subject,
+			KeyType:     c.KeyType,
+			KeyBits:     c.KeyBits,
+			NotAfter:    expiration,
+			KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			ExtKeyUsage: certutil.ClientAuthExtKeyUsage,
+			URLs:        &certutil.URLEntries{},
+		},
+		SigningBundle: signingBundle,
+	})
+	if err != nil {
+		return nil, errwrap.Wrapf("error issuing client certificate: {{err}}", err)
+	}
+
+	return parsed.ToCertBundle()
+}
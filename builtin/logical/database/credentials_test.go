@@ -0,0 +1,185 @@
+package database
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/helper/certutil"
+)
+
+func testCA(t *testing.T) (caCert, caKey string) {
+	t.Helper()
+
+	parsed, err := certutil.CreateCertificate(&certutil.CreationBundle{
+		Params: &certutil.CreationParameters{
+			Subject:  pkix.Name{CommonName: "database CA"},
+			IsCA:     true,
+			KeyType:  "ec",
+			KeyBits:  256,
+			NotAfter: time.Now().Add(24 * time.Hour),
+			URLs:     &certutil.URLEntries{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := parsed.ToCertBundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bundle.Certificate, bundle.PrivateKey
+}
+
+func TestParseCredentialConfig(t *testing.T) {
+	caCert, caKey := testCA(t)
+
+	cases := map[string]struct {
+		credentialType v5.CredentialType
+		raw            map[string]interface{}
+		wantErr        bool
+	}{
+		"password": {
+			credentialType: v5.CredentialTypePassword,
+		},
+		"password with config": {
+			credentialType: v5.CredentialTypePassword,
+			raw:            map[string]interface{}{"key_bits": 2048},
+			wantErr:        true,
+		},
+		"rsa default": {
+			credentialType: v5.CredentialTypeRSAPrivateKey,
+		},
+		"rsa key bits": {
+			credentialType: v5.CredentialTypeRSAPrivateKey,
+			raw:            map[string]interface{}{"key_bits": "4096"},
+		},
+		"rsa bad key bits": {
+			credentialType: v5.CredentialTypeRSAPrivateKey,
+			raw:            map[string]interface{}{"key_bits": 1024},
+			wantErr:        true,
+		},
+		"rsa unknown key": {
+			credentialType: v5.CredentialTypeRSAPrivateKey,
+			raw:            map[string]interface{}{"bits": 2048},
+			wantErr:        true,
+		},
+		"certificate": {
+			credentialType: v5.CredentialTypeClientCertificate,
+			raw:            map[string]interface{}{"ca_cert": caCert, "ca_private_key": caKey},
+		},
+		"certificate missing CA key": {
+			credentialType: v5.CredentialTypeClientCertificate,
+			raw:            map[string]interface{}{"ca_cert": caCert},
+			wantErr:        true,
+		},
+		"certificate bad template": {
+			credentialType: v5.CredentialTypeClientCertificate,
+			raw:            map[string]interface{}{"ca_cert": caCert, "ca_private_key": caKey, "common_name_template": "{{"},
+			wantErr:        true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCredentialConfig(tc.credentialType, tc.raw)
+			if tc.wantErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRSAKeyConfig_generateRSAKey(t *testing.T) {
+	raw, err := parseCredentialConfig(v5.CredentialTypeRSAPrivateKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey, publicKey, err := raw.(*rsaKeyConfig).generateRSAKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("bad private key %q", privateKey)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.N.BitLen() != defaultRSAKeyBits {
+		t.Fatalf("expected %d bits, got %d", defaultRSAKeyBits, key.N.BitLen())
+	}
+
+	parsedPublicKey, err := certutil.ParsePublicKeyPEM(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := certutil.ComparePublicKeys(parsedPublicKey, key.Public()); !ok {
+		t.Fatal("public key does not match the private key")
+	}
+}
+
+func TestClientCertificateConfig_issueCertificate(t *testing.T) {
+	caCert, caKey := testCA(t)
+	raw, err := parseCredentialConfig(v5.CredentialTypeClientCertificate, map[string]interface{}{
+		"ca_cert":              caCert,
+		"ca_private_key":       caKey,
+		"common_name_template": "{{.DisplayName}}-{{.RoleName}}",
+		"key_type":             "ec",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := raw.(*clientCertificateConfig)
+
+	subject, err := config.subject(v5.UsernameMetadata{DisplayName: "token", RoleName: "ro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject.String() != "CN=token-ro" {
+		t.Fatalf("bad subject %q", subject.String())
+	}
+
+	expiration := time.Now().Add(time.Hour)
+	bundle, err := config.issueCertificate(subject, expiration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(bundle.PrivateKey, "EC PRIVATE KEY") {
+		t.Fatalf("expected an EC private key, got %q", bundle.PrivateKey)
+	}
+
+	parsed, err := certutil.ParsePEMBundle(bundle.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := parsed.Certificate
+	if cert.Subject.CommonName != "token-ro" {
+		t.Fatalf("bad common name %q", cert.Subject.CommonName)
+	}
+	if len(cert.ExtKeyUsage) != 1 || cert.ExtKeyUsage[0] != x509.ExtKeyUsageClientAuth {
+		t.Fatalf("expected client auth usage, got %v", cert.ExtKeyUsage)
+	}
+	if cert.NotAfter.After(expiration) {
+		t.Fatalf("certificate outlives the credential: %s", cert.NotAfter)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM([]byte(caCert))
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
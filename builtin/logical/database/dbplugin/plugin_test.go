@@ -31,13 +31,18 @@ func (m *mockPlugin) CreateUser(_ context.Context, statements dbplugin.Statement
 		return "", "", err
 	}
 
-	if _, ok := m.users[usernameConf.DisplayName]; ok {
+	username = usernameConf.DisplayName
+	if usernameConf.Username != "" {
+		username = usernameConf.Username
+	}
+
+	if _, ok := m.users[username]; ok {
 		return "", "", err
 	}
 
-	m.users[usernameConf.DisplayName] = []string{password}
+	m.users[username] = []string{password}
 
-	return usernameConf.DisplayName, "test", nil
+	return username, "test", nil
 }
 func (m *mockPlugin) RenewUser(_ context.Context, statements dbplugin.Statements, username string, expiration time.Time) error {
 	err := errors.New("err")
@@ -191,6 +196,16 @@ func TestPlugin_CreateUser(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected an error, user wasn't created correctly")
 	}
+
+	// A username rendered by Vault is passed through to the plugin
+	usernameConf.Username = "rendered_test"
+	us, _, err = db.CreateUser(context.Background(), dbplugin.Statements{}, usernameConf, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if us != "rendered_test" {
+		t.Fatalf("expected rendered username, got %q", us)
+	}
 }
 
 func TestPlugin_RenewUser(t *testing.T) {
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/fatih/structs"
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -29,6 +31,14 @@ type DatabaseConfig struct {
 	AllowedRoles      []string               `json:"allowed_roles" structs:"allowed_roles" mapstructure:"allowed_roles"`
 
 	RootCredentialsRotateStatements []string `json:"root_credentials_rotate_statements" structs:"root_credentials_rotate_statements" mapstructure:"root_credentials_rotate_statements"`
+
+	// PasswordPolicy is the name of the password policy used to generate
+	// passwords for the static roles of this connection
+	PasswordPolicy string `json:"password_policy" structs:"password_policy" mapstructure:"password_policy"`
+
+	// UsernameTemplate is the template usernames of dynamic credentials are
+	// rendered from. Plugins generate usernames themselves if it is empty.
+	UsernameTemplate string `json:"username_template" structs:"username_template" mapstructure:"username_template"`
 }
 
 // pathResetConnection configures a path to reset a plugin.
@@ -113,6 +123,22 @@ func pathConfigurePluginConnection(b *databaseBackend) *framework.Path {
 				page for more information on support and formatting for this 
 				parameter.`,
 			},
+
+			"password_policy": &framework.FieldSchema{
+				Type: framework.TypeString,
+				Description: `The name of the password policy used to generate
+				passwords for static roles of this connection. Roles may override
+				it with their own password policy.`,
+			},
+
+			"username_template": &framework.FieldSchema{
+				Type: framework.TypeString,
+				Description: `Template describing how usernames of dynamic
+				credentials are generated, for example
+				"{{.RoleName | truncate 8}}_{{random 8}}". Templates have access to
+				.DisplayName and .RoleName, and the functions truncate, random,
+				unix_time, uppercase, lowercase, replace and sha256.`,
+			},
 		},
 
 		ExistenceCheck: b.connectionExistenceCheck(),
@@ -273,6 +299,35 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 			config.RootCredentialsRotateStatements = data.Get("root_rotation_statements").([]string)
 		}
 
+		if passwordPolicyRaw, ok := data.GetOk("password_policy"); ok {
+			config.PasswordPolicy = passwordPolicyRaw.(string)
+		} else if req.Operation == logical.CreateOperation {
+			config.PasswordPolicy = data.Get("password_policy").(string)
+		}
+		if config.PasswordPolicy != "" {
+			if _, err := b.System().GeneratePasswordFromPolicy(ctx, config.PasswordPolicy); err != nil {
+				return logical.ErrorResponse("unable to use password policy %q: %s", config.PasswordPolicy, err), nil
+			}
+		}
+
+		if usernameTemplateRaw, ok := data.GetOk("username_template"); ok {
+			config.UsernameTemplate = usernameTemplateRaw.(string)
+		} else if req.Operation == logical.CreateOperation {
+			config.UsernameTemplate = data.Get("username_template").(string)
+		}
+		if config.UsernameTemplate != "" {
+			username, err := renderUsername(config.UsernameTemplate, dbplugin.UsernameConfig{
+				DisplayName: "token",
+				RoleName:    "role",
+			})
+			if err != nil {
+				return logical.ErrorResponse("invalid username template: %s", err), nil
+			}
+			if username == "" {
+				return logical.ErrorResponse("invalid username template: renders an empty username"), nil
+			}
+		}
+
 		// Remove these entries from the data before we store it keyed under
 		// ConnectionDetails.
 		delete(data.Raw, "name")
@@ -280,13 +335,23 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 		delete(data.Raw, "allowed_roles")
 		delete(data.Raw, "verify_connection")
 		delete(data.Raw, "root_rotation_statements")
+		delete(data.Raw, "password_policy")
+		delete(data.Raw, "username_template")
 
 		// Create a database plugin and initialize it.
-		db, err := dbplugin.PluginFactory(ctx, config.PluginName, b.System(), b.logger)
+		db, err := newDatabaseWrapper(ctx, config.PluginName, b.System(), b.logger)
 		if err != nil {
 			return logical.ErrorResponse(fmt.Sprintf("error creating database object: %s", err)), nil
 		}
 
+		// Version 4 plugins generate the passwords of dynamic credentials and
+		// root credential rotations themselves, so a password policy of the
+		// connection would not apply to them
+		if db.isV4() && config.PasswordPolicy != "" {
+			db.Close()
+			return logical.ErrorResponse("password_policy requires a plugin implementing version 5 of the database plugin interface, set it on the static roles of the connection instead"), nil
+		}
+
 		// If this is an update, take any new values, overwrite what was there
 		// before, and pass that in as the "new" set of values to the plugin,
 		// then save what results
@@ -301,11 +366,18 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 			}
 		}
 
-		config.ConnectionDetails, err = db.Init(ctx, config.ConnectionDetails, verifyConnection)
+		initReq := v5.InitializeRequest{
+			Config:           config.ConnectionDetails,
+			VerifyConnection: verifyConnection,
+		}
+		start := time.Now()
+		initResp, err := db.Initialize(ctx, initReq)
 		if err != nil {
 			db.Close()
 			return logical.ErrorResponse(fmt.Sprintf("error creating database object: %s", err)), nil
 		}
+		latency := time.Since(start)
+		config.ConnectionDetails = initResp.Config
 
 		b.Lock()
 		defer b.Unlock()
@@ -319,9 +391,9 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 		}
 
 		b.connections[name] = &dbPluginInstance{
-			Database: db,
-			name:     name,
-			id:       id,
+			databaseVersionWrapper: db,
+			name:                   name,
+			id:                     id,
 		}
 
 		// Store it
@@ -335,6 +407,14 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 
 		resp := &logical.Response{}
 
+		// Report the result of the verification, so connectivity and the
+		// server version can be checked when configuring a connection
+		if verifyConnection {
+			resp.Data = map[string]interface{}{
+				"verify_connection": connectionVerificationReport(initResp, latency),
+			}
+		}
+
 		// This is a simple test to to check for passwords in the connection_url paramater. If one exists,
 		// warn the user to use templated url string
 		if connURLRaw, ok := config.ConnectionDetails["connection_url"]; ok {
@@ -349,6 +429,19 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 	}
 }
 
+// connectionVerificationReport describes the verification of a connection.
+// The server version is only known to plugins implementing version 5 of the
+// database plugin interface, as version 4 has no way to report it.
+func connectionVerificationReport(initResp v5.InitializeResponse, latency time.Duration) map[string]interface{} {
+	report := map[string]interface{}{
+		"latency": latency.String(),
+	}
+	if initResp.ServerVersion != "" {
+		report["server_version"] = initResp.ServerVersion
+	}
+	return report
+}
+
 const pathConfigConnectionHelpSyn = `
 Configure connection details to a database plugin.
 `
@@ -368,7 +461,12 @@ accepts:
 
 	* "verify_connection" (default: true) - A boolean value denoting if the plugin should verify
 	   it is able to connect to the database using the provided connection
-       details.
+       details. When verified, the response reports the latency of the
+	   verification and the version of the database server, if the plugin
+	   reports it.
+
+	* "username_template" - A Go template usernames of dynamic credentials are
+	   rendered from. If not set, the plugin generates usernames.
 `
 
 const pathResetConnectionHelpSyn = `
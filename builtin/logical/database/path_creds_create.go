@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/helper/template"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
@@ -90,23 +92,25 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 		// to ensure the database credential does not expire before the lease
 		expiration = expiration.Add(5 * time.Second)
 
-		usernameConfig := dbplugin.UsernameConfig{
-			DisplayName: req.DisplayName,
-			RoleName:    name,
+		newUserReq, respData, err := b.newUserRequest(ctx, db, dbConfig, role, name, req.DisplayName, expiration)
+		if err != nil {
+			return nil, err
 		}
 
 		// Create the user
-		username, password, err := db.CreateUser(ctx, role.Statements, usernameConfig, expiration)
+		newUserResp, password, err := db.NewUser(ctx, newUserReq)
 		if err != nil {
 			b.CloseIfShutdown(db, err)
 			return nil, err
 		}
 
-		resp := b.Secret(SecretCredsType).Response(map[string]interface{}{
-			"username": username,
-			"password": password,
-		}, map[string]interface{}{
-			"username":              username,
+		respData["username"] = newUserResp.Username
+		if role.CredentialType == v5.CredentialTypePassword {
+			respData["password"] = password
+		}
+
+		resp := b.Secret(SecretCredsType).Response(respData, map[string]interface{}{
+			"username":              newUserResp.Username,
 			"role":                  name,
 			"db_name":               role.DBName,
 			"revocation_statements": role.Statements.Revocation,
@@ -117,6 +121,97 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 	}
 }
 
+// newUserRequest builds the request creating a user of the role, generating
+// the credential of the user. Anything besides the username the client needs
+// to authenticate is returned in respData.
+func (b *databaseBackend) newUserRequest(ctx context.Context, db *dbPluginInstance, dbConfig *DatabaseConfig, role *roleEntry, roleName, displayName string, expiration time.Time) (newUserReq v5.NewUserRequest, respData map[string]interface{}, err error) {
+	usernameConfig := dbplugin.UsernameConfig{
+		DisplayName: displayName,
+		RoleName:    roleName,
+	}
+	if dbConfig.UsernameTemplate != "" {
+		usernameConfig.Username, err = renderUsername(dbConfig.UsernameTemplate, usernameConfig)
+		if err != nil {
+			return v5.NewUserRequest{}, nil, err
+		}
+	}
+
+	newUserReq = v5.NewUserRequest{
+		UsernameConfig: v5.UsernameMetadata{
+			DisplayName: usernameConfig.DisplayName,
+			RoleName:    usernameConfig.RoleName,
+			Username:    usernameConfig.Username,
+		},
+		CredentialType: role.CredentialType,
+		Statements: v5.Statements{
+			Commands: role.Statements.Creation,
+		},
+		RollbackStatements: v5.Statements{
+			Commands: role.Statements.Rollback,
+		},
+		Expiration: expiration,
+	}
+
+	// Generate the credential
+	respData = make(map[string]interface{})
+	credentialConfig, err := parseCredentialConfig(role.CredentialType, role.CredentialConfig)
+	if err != nil {
+		return v5.NewUserRequest{}, nil, err
+	}
+	switch config := credentialConfig.(type) {
+	case *rsaKeyConfig:
+		privateKey, publicKey, err := config.generateRSAKey()
+		if err != nil {
+			return v5.NewUserRequest{}, nil, err
+		}
+		newUserReq.PublicKey = publicKey
+		respData["private_key"] = privateKey
+
+	case *clientCertificateConfig:
+		subject, err := config.subject(newUserReq.UsernameConfig)
+		if err != nil {
+			return v5.NewUserRequest{}, nil, err
+		}
+		certBundle, err := config.issueCertificate(subject, expiration)
+		if err != nil {
+			return v5.NewUserRequest{}, nil, err
+		}
+		newUserReq.Subject = subject.String()
+		respData["client_certificate"] = certBundle.Certificate
+		respData["private_key"] = certBundle.PrivateKey
+		respData["private_key_type"] = string(certBundle.PrivateKeyType)
+		respData["serial_number"] = certBundle.SerialNumber
+
+	default:
+		if db.isV5() {
+			newUserReq.Password, err = b.generatePassword(ctx, db.databaseVersionWrapper, dbConfig.PasswordPolicy)
+			if err != nil {
+				return v5.NewUserRequest{}, nil, err
+			}
+		}
+	}
+
+	return newUserReq, respData, nil
+}
+
+// usernameTemplateData is the data username templates are rendered with
+type usernameTemplateData struct {
+	DisplayName string
+	RoleName    string
+}
+
+// renderUsername renders a connection's username template for a new user
+func renderUsername(usernameTemplate string, usernameConfig dbplugin.UsernameConfig) (string, error) {
+	tmpl, err := template.NewTemplate(usernameTemplate)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Generate(usernameTemplateData{
+		DisplayName: usernameConfig.DisplayName,
+		RoleName:    usernameConfig.RoleName,
+	})
+}
+
 func (b *databaseBackend) pathStaticCredsRead() framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 		name := data.Get("name").(string)
@@ -129,6 +224,15 @@ func (b *databaseBackend) pathStaticCredsRead() framework.OperationFunc {
 			return logical.ErrorResponse("unknown role: %s", name), nil
 		}
 
+		// Accounts of library sets are only handed out by check-outs
+		status, err := b.checkOutStatus(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			return logical.ErrorResponse("static role %q is a member of library set %q, check it out instead", name, status.SetName), nil
+		}
+
 		dbConfig, err := b.DatabaseConfig(ctx, req.Storage, role.DBName)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,674 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	databaseLibraryPath  = "library/"
+	databaseCheckOutPath = "checkout/"
+
+	defaultCheckOutTTL = 24 * time.Hour
+)
+
+// libraryEntry is a set of static roles whose accounts are checked out by
+// one caller at a time
+type libraryEntry struct {
+	StaticRoles               []string      `json:"static_roles"`
+	TTL                       time.Duration `json:"ttl"`
+	MaxTTL                    time.Duration `json:"max_ttl"`
+	DisableCheckInEnforcement bool          `json:"disable_check_in_enforcement"`
+}
+
+// checkOut is the check-out status of a static role in a library set. It is
+// stored for every member of a set, roles that have one can not be read
+// through static-creds.
+type checkOut struct {
+	SetName     string `json:"set_name"`
+	IsAvailable bool   `json:"is_available"`
+
+	// CheckOutID identifies the current check-out, so revoking the lease of
+	// an earlier check-out does not check in the role
+	CheckOutID                  string `json:"check_out_id,omitempty"`
+	BorrowerEntityID            string `json:"borrower_entity_id,omitempty"`
+	BorrowerClientTokenAccessor string `json:"borrower_client_token_accessor,omitempty"`
+}
+
+func pathListLibrary(b *databaseBackend) []*framework.Path {
+	return []*framework.Path{
+		&framework.Path{
+			Pattern: "library/?$",
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.pathLibraryList,
+			},
+
+			HelpSynopsis:    pathLibraryHelpSyn,
+			HelpDescription: pathLibraryHelpDesc,
+		},
+	}
+}
+
+func pathLibrary(b *databaseBackend) []*framework.Path {
+	return []*framework.Path{
+		&framework.Path{
+			Pattern: "library/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the library set.",
+				},
+				"static_roles": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The static roles whose accounts are checked out from this set.",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Default duration of a check-out. Defaults to 24 hours.",
+					Default:     int(defaultCheckOutTTL.Seconds()),
+				},
+				"max_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Maximum duration a check-out can be renewed to. Defaults to 24 hours.",
+					Default:     int(defaultCheckOutTTL.Seconds()),
+				},
+				"disable_check_in_enforcement": {
+					Type:        framework.TypeBool,
+					Description: "Allow any caller to check in accounts, not only the one that checked them out.",
+				},
+			},
+			ExistenceCheck: b.pathLibraryExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathLibraryRead,
+				logical.CreateOperation: b.pathLibraryCreateUpdate,
+				logical.UpdateOperation: b.pathLibraryCreateUpdate,
+				logical.DeleteOperation: b.pathLibraryDelete,
+			},
+
+			HelpSynopsis:    pathLibraryHelpSyn,
+			HelpDescription: pathLibraryHelpDesc,
+		},
+		&framework.Path{
+			Pattern: "library/" + framework.GenericNameRegex("name") + "/check-out$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the library set.",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Duration of the check-out. Defaults to, and can not exceed, the ttl of the set.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: b.pathLibraryCheckOut,
+			},
+
+			HelpSynopsis:    pathLibraryCheckOutHelpSyn,
+			HelpDescription: pathLibraryCheckOutHelpDesc,
+		},
+		&framework.Path{
+			Pattern: "library/" + framework.GenericNameRegex("name") + "/check-in$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the library set.",
+				},
+				"static_roles": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The static roles to check in. Optional if the caller has checked out a single role of the set.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: b.pathLibraryCheckIn(false),
+			},
+
+			HelpSynopsis:    pathLibraryCheckInHelpSyn,
+			HelpDescription: pathLibraryCheckInHelpDesc,
+		},
+		&framework.Path{
+			Pattern: "library/manage/" + framework.GenericNameRegex("name") + "/check-in$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the library set.",
+				},
+				"static_roles": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The static roles to check in. Defaults to all checked out roles of the set.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: b.pathLibraryCheckIn(true),
+			},
+
+			HelpSynopsis:    pathLibraryManageCheckInHelpSyn,
+			HelpDescription: pathLibraryManageCheckInHelpDesc,
+		},
+		&framework.Path{
+			Pattern: "library/" + framework.GenericNameRegex("name") + "/status$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the library set.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.pathLibraryStatus,
+			},
+
+			HelpSynopsis:    pathLibraryStatusHelpSyn,
+			HelpDescription: pathLibraryStatusHelpDesc,
+		},
+	}
+}
+
+func (b *databaseBackend) library(ctx context.Context, s logical.Storage, name string) (*libraryEntry, error) {
+	entry, err := s.Get(ctx, databaseLibraryPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var set libraryEntry
+	if err := entry.DecodeJSON(&set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+func (b *databaseBackend) checkOutStatus(ctx context.Context, s logical.Storage, roleName string) (*checkOut, error) {
+	entry, err := s.Get(ctx, databaseCheckOutPath+roleName)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var status checkOut
+	if err := entry.DecodeJSON(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func storeCheckOutStatus(ctx context.Context, s logical.Storage, roleName string, status *checkOut) error {
+	entry, err := logical.StorageEntryJSON(databaseCheckOutPath+roleName, status)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+func (b *databaseBackend) pathLibraryExistenceCheck(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+	set, err := b.library(ctx, req.Storage, data.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+	return set != nil, nil
+}
+
+func (b *databaseBackend) pathLibraryList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	entries, err := req.Storage.List(ctx, databaseLibraryPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(entries), nil
+}
+
+func (b *databaseBackend) pathLibraryRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	set, err := b.library(ctx, req.Storage, data.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"static_roles":                 set.StaticRoles,
+			"ttl":                          set.TTL.Seconds(),
+			"max_ttl":                      set.MaxTTL.Seconds(),
+			"disable_check_in_enforcement": set.DisableCheckInEnforcement,
+		},
+	}, nil
+}
+
+func (b *databaseBackend) pathLibraryCreateUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	b.checkOutLock.Lock()
+	defer b.checkOutLock.Unlock()
+
+	set, err := b.library(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	createOperation := set == nil
+	if set == nil {
+		set = &libraryEntry{}
+	}
+
+	previousRoles := set.StaticRoles
+	if staticRolesRaw, ok := data.GetOk("static_roles"); ok {
+		set.StaticRoles = strutil.RemoveDuplicates(staticRolesRaw.([]string), false)
+	}
+	if len(set.StaticRoles) == 0 {
+		return logical.ErrorResponse("static_roles must contain at least one static role"), nil
+	}
+
+	if ttlRaw, ok := data.GetOk("ttl"); ok {
+		set.TTL = time.Duration(ttlRaw.(int)) * time.Second
+	} else if createOperation {
+		set.TTL = time.Duration(data.Get("ttl").(int)) * time.Second
+	}
+	if maxTTLRaw, ok := data.GetOk("max_ttl"); ok {
+		set.MaxTTL = time.Duration(maxTTLRaw.(int)) * time.Second
+	} else if createOperation {
+		set.MaxTTL = time.Duration(data.Get("max_ttl").(int)) * time.Second
+	}
+	if set.MaxTTL > 0 && set.TTL > set.MaxTTL {
+		return logical.ErrorResponse("ttl cannot be greater than max_ttl"), nil
+	}
+	if disableRaw, ok := data.GetOk("disable_check_in_enforcement"); ok {
+		set.DisableCheckInEnforcement = disableRaw.(bool)
+	}
+
+	// Validate the members of the set. Each static role can only be part of
+	// one set.
+	for _, roleName := range set.StaticRoles {
+		role, err := b.StaticRole(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			return logical.ErrorResponse("static role %q does not exist", roleName), nil
+		}
+
+		status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil && status.SetName != name {
+			return logical.ErrorResponse("static role %q is already a member of library set %q", roleName, status.SetName), nil
+		}
+	}
+
+	// Roles removed from the set must not be checked out
+	var removedRoles []string
+	for _, roleName := range previousRoles {
+		if strutil.StrListContains(set.StaticRoles, roleName) {
+			continue
+		}
+		status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil && !status.IsAvailable {
+			return logical.ErrorResponse("static role %q is checked out and cannot be removed from the set", roleName), nil
+		}
+		removedRoles = append(removedRoles, roleName)
+	}
+
+	for _, roleName := range set.StaticRoles {
+		status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			continue
+		}
+		if err := storeCheckOutStatus(ctx, req.Storage, roleName, &checkOut{
+			SetName:     name,
+			IsAvailable: true,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, roleName := range removedRoles {
+		if err := req.Storage.Delete(ctx, databaseCheckOutPath+roleName); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(databaseLibraryPath+name, set)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *databaseBackend) pathLibraryDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	b.checkOutLock.Lock()
+	defer b.checkOutLock.Unlock()
+
+	set, err := b.library(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, nil
+	}
+
+	for _, roleName := range set.StaticRoles {
+		status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil && !status.IsAvailable {
+			return logical.ErrorResponse("static role %q is checked out, check it in before deleting the set", roleName), nil
+		}
+	}
+
+	for _, roleName := range set.StaticRoles {
+		if err := req.Storage.Delete(ctx, databaseCheckOutPath+roleName); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.Storage.Delete(ctx, databaseLibraryPath+name); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *databaseBackend) pathLibraryCheckOut(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	b.checkOutLock.Lock()
+	defer b.checkOutLock.Unlock()
+
+	set, err := b.library(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return logical.ErrorResponse("unknown library set: %s", name), nil
+	}
+
+	ttl := set.TTL
+	if ttlRaw, ok := data.GetOk("ttl"); ok {
+		requestedTTL := time.Duration(ttlRaw.(int)) * time.Second
+		if requestedTTL < ttl {
+			ttl = requestedTTL
+		}
+	}
+
+	for _, roleName := range set.StaticRoles {
+		status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if status == nil || !status.IsAvailable {
+			continue
+		}
+
+		role, err := b.StaticRole(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+
+		dbConfig, err := b.DatabaseConfig(ctx, req.Storage, role.DBName)
+		if err != nil {
+			return nil, err
+		}
+		if !strutil.StrListContains(dbConfig.AllowedRoles, "*") && !strutil.StrListContainsGlob(dbConfig.AllowedRoles, roleName) {
+			continue
+		}
+
+		checkOutID, err := uuid.GenerateUUID()
+		if err != nil {
+			return nil, err
+		}
+		status.IsAvailable = false
+		status.CheckOutID = checkOutID
+		status.BorrowerEntityID = req.EntityID
+		status.BorrowerClientTokenAccessor = req.ClientTokenAccessor
+		if err := storeCheckOutStatus(ctx, req.Storage, roleName, status); err != nil {
+			return nil, err
+		}
+
+		resp := b.Secret(SecretCheckOutType).Response(map[string]interface{}{
+			"static_role": roleName,
+			"username":    role.StaticAccount.Username,
+			"password":    role.StaticAccount.Password,
+		}, map[string]interface{}{
+			"set_name":     name,
+			"static_role":  roleName,
+			"check_out_id": checkOutID,
+		})
+		resp.Secret.TTL = ttl
+		resp.Secret.MaxTTL = set.MaxTTL
+		return resp, nil
+	}
+
+	return logical.ErrorResponse("no static roles available for check-out"), nil
+}
+
+// pathLibraryCheckIn returns the handler checking in static roles. Unless
+// force is set and the set enforces it, callers can only check in the roles
+// they checked out.
+func (b *databaseBackend) pathLibraryCheckIn(force bool) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := data.Get("name").(string)
+
+		b.checkOutLock.Lock()
+		defer b.checkOutLock.Unlock()
+
+		set, err := b.library(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if set == nil {
+			return logical.ErrorResponse("unknown library set: %s", name), nil
+		}
+		enforce := !force && !set.DisableCheckInEnforcement
+
+		// Find the roles the caller may check in
+		var candidates []string
+		for _, roleName := range set.StaticRoles {
+			status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+			if err != nil {
+				return nil, err
+			}
+			if status == nil || status.IsAvailable {
+				continue
+			}
+			if enforce && !status.borrowedBy(req) {
+				continue
+			}
+			candidates = append(candidates, roleName)
+		}
+
+		roleNames := data.Get("static_roles").([]string)
+		switch {
+		case len(roleNames) > 0:
+			for _, roleName := range roleNames {
+				if !strutil.StrListContains(set.StaticRoles, roleName) {
+					return logical.ErrorResponse("static role %q is not a member of library set %q", roleName, name), nil
+				}
+				if !strutil.StrListContains(candidates, roleName) {
+					return logical.ErrorResponse("static role %q is not checked out by the caller", roleName), nil
+				}
+			}
+		case force:
+			roleNames = candidates
+		case len(candidates) > 1:
+			return logical.ErrorResponse("multiple static roles are checked out, specify static_roles to check in"), nil
+		default:
+			roleNames = candidates
+		}
+
+		var checkedIn []string
+		for _, roleName := range roleNames {
+			if err := b.checkIn(ctx, req.Storage, roleName); err != nil {
+				return nil, err
+			}
+			checkedIn = append(checkedIn, roleName)
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"check_ins": checkedIn,
+			},
+		}, nil
+	}
+}
+
+func (b *databaseBackend) pathLibraryStatus(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	set, err := b.library(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, nil
+	}
+
+	respData := make(map[string]interface{}, len(set.StaticRoles))
+	for _, roleName := range set.StaticRoles {
+		status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if status == nil {
+			continue
+		}
+
+		roleStatus := map[string]interface{}{
+			"available": status.IsAvailable,
+		}
+		if !status.IsAvailable {
+			if status.BorrowerEntityID != "" {
+				roleStatus["borrower_entity_id"] = status.BorrowerEntityID
+			}
+			if status.BorrowerClientTokenAccessor != "" {
+				roleStatus["borrower_client_token_accessor"] = status.BorrowerClientTokenAccessor
+			}
+		}
+		respData[roleName] = roleStatus
+	}
+
+	return &logical.Response{
+		Data: respData,
+	}, nil
+}
+
+// checkIn rotates the password of a checked out static role and makes it
+// available again. The caller must hold the checkOutLock.
+func (b *databaseBackend) checkIn(ctx context.Context, s logical.Storage, roleName string) error {
+	lock := locksutil.LockForKey(b.roleLocks, roleName)
+	lock.Lock()
+	defer lock.Unlock()
+
+	status, err := b.checkOutStatus(ctx, s, roleName)
+	if err != nil {
+		return err
+	}
+	if status == nil || status.IsAvailable {
+		return nil
+	}
+
+	role, err := b.StaticRole(ctx, s, roleName)
+	if err != nil {
+		return err
+	}
+	if role != nil {
+		if err := b.rotateStaticRole(ctx, s, roleName, role); err != nil {
+			return fmt.Errorf("unable to rotate the password of static role %q on check-in: %s", roleName, err)
+		}
+	}
+
+	return storeCheckOutStatus(ctx, s, roleName, &checkOut{
+		SetName:     status.SetName,
+		IsAvailable: true,
+	})
+}
+
+// borrowedBy reports whether the caller of req checked out the role
+func (c *checkOut) borrowedBy(req *logical.Request) bool {
+	if c.BorrowerEntityID != "" {
+		return c.BorrowerEntityID == req.EntityID
+	}
+	return c.BorrowerClientTokenAccessor != "" && c.BorrowerClientTokenAccessor == req.ClientTokenAccessor
+}
+
+const pathLibraryHelpSyn = `
+Manage sets of static roles that are checked out by one caller at a time.
+`
+
+const pathLibraryHelpDesc = `
+This path lets you manage library sets. A library set is a pool of static
+roles whose accounts are checked out exclusively, instead of being shared by
+every reader of "static-creds/". Each static role can be a member of one set.
+
+The "static_roles" parameter lists the static roles of the set. The "ttl"
+parameter is the default duration of a check-out, "max_ttl" the duration a
+check-out can be renewed to. Check-outs are checked in, and their password
+rotated, when their lease expires or is revoked.
+
+Unless "disable_check_in_enforcement" is set, only the entity or token that
+checked out an account can check it in through "library/:name/check-in".
+`
+
+const pathLibraryCheckOutHelpSyn = `
+Check out an account of a library set.
+`
+
+const pathLibraryCheckOutHelpDesc = `
+This path checks out an available static role of the set and returns its
+credentials as a lease. The account is unavailable to other callers until it
+is checked in, or its lease expires or is revoked. The password is rotated on
+check-in.
+`
+
+const pathLibraryCheckInHelpSyn = `
+Check in accounts checked out by the caller.
+`
+
+const pathLibraryCheckInHelpDesc = `
+This path checks in accounts of the set, rotating their passwords. If the
+caller has checked out a single account of the set, "static_roles" may be
+omitted.
+`
+
+const pathLibraryManageCheckInHelpSyn = `
+Check in any checked out accounts of a library set.
+`
+
+const pathLibraryManageCheckInHelpDesc = `
+This path lets operators check in accounts regardless of who checked them out,
+rotating their passwords. If "static_roles" is omitted, all checked out
+accounts of the set are checked in.
+`
+
+const pathLibraryStatusHelpSyn = `
+Read the check-out status of the accounts of a library set.
+`
+
+const pathLibraryStatusHelpDesc = `
+This path returns, for each static role of the set, whether it is available
+and, if it is checked out, the entity ID or token accessor of its borrower.
+`
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/queue"
+)
+
+// getLibraryBackend returns a backend with a connection "db" to a fake
+// version 5 plugin and the static roles "one" and "two"
+func getLibraryBackend(t *testing.T) (*databaseBackend, logical.Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend(config)
+	if err := b.Setup(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	b.credRotationQueue = queue.New()
+
+	entry, err := logical.StorageEntryJSON("config/db", &DatabaseConfig{
+		PluginName:   "fake",
+		AllowedRoles: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.StorageView.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	b.connections["db"] = &dbPluginInstance{
+		databaseVersionWrapper: databaseVersionWrapper{v5: fakeV5Database{}},
+		name:                   "db",
+		id:                     "db-id",
+	}
+
+	for _, name := range []string{"one", "two"} {
+		resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "static-roles/" + name,
+			Storage:   config.StorageView,
+			Data: map[string]interface{}{
+				"db_name":         "db",
+				"username":        "user-" + name,
+				"rotation_period": "1h",
+			},
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%v resp:%#v", err, resp)
+		}
+	}
+
+	return b, config.StorageView
+}
+
+func libraryRequest(t *testing.T, b *databaseBackend, s logical.Storage, req *logical.Request, wantErr bool) *logical.Response {
+	t.Helper()
+
+	req.Storage = s
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if wantErr {
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected error for %s %s, got %#v", req.Operation, req.Path, resp)
+		}
+		return resp
+	}
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("%s %s: err:%v resp:%#v", req.Operation, req.Path, err, resp)
+	}
+	return resp
+}
+
+func staticPassword(t *testing.T, b *databaseBackend, s logical.Storage, name string) string {
+	t.Helper()
+
+	role, err := b.StaticRole(context.Background(), s, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return role.StaticAccount.Password
+}
+
+func TestBackend_library_checkOut(t *testing.T) {
+	b, s := getLibraryBackend(t)
+
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "library/pool",
+		Data: map[string]interface{}{
+			"static_roles": "one,two",
+			"ttl":          "1h",
+			"max_ttl":      "2h",
+		},
+	}, false)
+
+	// A role can only be a member of one set
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "library/other",
+		Data:      map[string]interface{}{"static_roles": "one"},
+	}, true)
+
+	// Members are not readable through static-creds
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "static-creds/one",
+	}, true)
+
+	// Each caller checks out a different account
+	first := libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/pool/check-out",
+		EntityID:  "entity-1",
+	}, false)
+	second := libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/pool/check-out",
+		EntityID:  "entity-2",
+		Data:      map[string]interface{}{"ttl": "3h"},
+	}, false)
+	firstRole := first.Data["static_role"].(string)
+	secondRole := second.Data["static_role"].(string)
+	if firstRole == secondRole {
+		t.Fatalf("both callers checked out %q", firstRole)
+	}
+	if first.Data["password"] != staticPassword(t, b, s, firstRole) {
+		t.Fatal("check-out did not return the current password")
+	}
+	if first.Secret.TTL.Hours() != 1 || second.Secret.TTL.Hours() != 1 {
+		t.Fatalf("expected check-outs capped at the ttl of the set, got %s and %s", first.Secret.TTL, second.Secret.TTL)
+	}
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/pool/check-out",
+		EntityID:  "entity-3",
+	}, true)
+
+	status := libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "library/pool/status",
+	}, false)
+	expectedStatus := map[string]interface{}{
+		"available":          false,
+		"borrower_entity_id": "entity-1",
+	}
+	if !reflect.DeepEqual(status.Data[firstRole], expectedStatus) {
+		t.Fatalf("expected status %#v, got %#v", expectedStatus, status.Data[firstRole])
+	}
+
+	// Only the borrower can check in an account
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/pool/check-in",
+		EntityID:  "entity-2",
+		Data:      map[string]interface{}{"static_roles": firstRole},
+	}, true)
+
+	// Checking in rotates the password
+	firstPassword := first.Data["password"].(string)
+	checkIn := libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/pool/check-in",
+		EntityID:  "entity-1",
+	}, false)
+	if !reflect.DeepEqual(checkIn.Data["check_ins"], []string{firstRole}) {
+		t.Fatalf("bad check-ins %#v", checkIn.Data["check_ins"])
+	}
+	if staticPassword(t, b, s, firstRole) == firstPassword {
+		t.Fatal("expected the password to be rotated on check-in")
+	}
+
+	// Leases of checked in accounts can not be renewed, and revoking them
+	// does not check in a later check-out
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.RenewOperation,
+		Secret:    first.Secret,
+	}, true)
+	third := libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/pool/check-out",
+		EntityID:  "entity-3",
+	}, false)
+	if third.Data["static_role"] != firstRole {
+		t.Fatalf("expected %q to be checked out, got %q", firstRole, third.Data["static_role"])
+	}
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.RevokeOperation,
+		Secret:    first.Secret,
+	}, false)
+	status = libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "library/pool/status",
+	}, false)
+	if status.Data[firstRole].(map[string]interface{})["available"] != false {
+		t.Fatal("revoking a stale lease checked in the account")
+	}
+
+	// Revoking the current lease reclaims the account
+	secondPassword := second.Data["password"].(string)
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.RenewOperation,
+		Secret:    second.Secret,
+	}, false)
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.RevokeOperation,
+		Secret:    second.Secret,
+	}, false)
+	if staticPassword(t, b, s, secondRole) == secondPassword {
+		t.Fatal("expected the password to be rotated when the lease is revoked")
+	}
+
+	// Checked out members can not be removed, and the set not deleted
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "static-roles/" + firstRole,
+	}, true)
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "library/pool",
+	}, true)
+
+	// Operators can check in any account
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "library/manage/pool/check-in",
+	}, false)
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "library/pool",
+	}, false)
+	libraryRequest(t, b, s, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "static-creds/one",
+	}, false)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/locksutil"
 	"github.com/hashicorp/vault/sdk/helper/strutil"
@@ -144,6 +145,19 @@ func dynamicFields() map[string]*framework.FieldSchema {
 	type will support this functionality. See the plugin's API page for
 	more information on support and formatting for this parameter.`,
 		},
+		"credential_type": {
+			Type:    framework.TypeString,
+			Default: "password",
+			Description: `The type of credential to issue. One of "password",
+	"rsa_private_key" or "client_certificate". Types other than "password"
+	require a plugin implementing version 5 of the database plugin interface.`,
+		},
+		"credential_config": {
+			Type: framework.TypeMap,
+			Description: `Configuration of the credential type. For
+	"rsa_private_key", "key_bits". For "client_certificate", "ca_cert",
+	"ca_private_key", "common_name_template", "key_type" and "key_bits".`,
+		},
 	}
 	return fields
 }
@@ -170,6 +184,12 @@ func staticFields() map[string]*framework.FieldSchema {
 	this functionality. See the plugin's API page for more information on
 	support and formatting for this parameter.`,
 		},
+		"password_policy": {
+			Type: framework.TypeString,
+			Description: `The name of the password policy used to generate the
+	account's passwords. Overrides the password policy of the database
+	connection.`,
+		},
 	}
 	return fields
 }
@@ -207,10 +227,18 @@ func (b *databaseBackend) pathStaticRoleDelete(ctx context.Context, req *logical
 	lock.Lock()
 	defer lock.Unlock()
 
+	status, err := b.checkOutStatus(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil {
+		return logical.ErrorResponse("static role %q is a member of library set %q, remove it from the set first", name, status.SetName), nil
+	}
+
 	// Remove the item from the queue
 	_, _ = b.popFromRotationQueueByKey(name)
 
-	err := req.Storage.Delete(ctx, databaseStaticRolePath+name)
+	err = req.Storage.Delete(ctx, databaseStaticRolePath+name)
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +258,7 @@ func (b *databaseBackend) pathStaticRoleRead(ctx context.Context, req *logical.R
 	data := map[string]interface{}{
 		"db_name":             role.DBName,
 		"rotation_statements": role.Statements.Rotation,
+		"password_policy":     role.PasswordPolicy,
 	}
 
 	// guard against nil StaticAccount; shouldn't happen but we'll be safe
@@ -268,6 +297,17 @@ func (b *databaseBackend) pathRoleRead(ctx context.Context, req *logical.Request
 		"renew_statements":      role.Statements.Renewal,
 		"default_ttl":           role.DefaultTTL.Seconds(),
 		"max_ttl":               role.MaxTTL.Seconds(),
+		"credential_type":       role.CredentialType.String(),
+	}
+	if len(role.CredentialConfig) > 0 {
+		// Never return the private key of the CA issuing client certificates
+		credentialConfig := make(map[string]interface{}, len(role.CredentialConfig))
+		for k, v := range role.CredentialConfig {
+			if k != "ca_private_key" {
+				credentialConfig[k] = v
+			}
+		}
+		data["credential_config"] = credentialConfig
 	}
 	if len(role.Statements.Creation) == 0 {
 		data["creation_statements"] = []string{}
@@ -371,6 +411,27 @@ func (b *databaseBackend) pathRoleCreateUpdate(ctx context.Context, req *logical
 
 	role.Statements.Revocation = strutil.RemoveEmpty(role.Statements.Revocation)
 
+	// Credentials
+	{
+		if _, ok := data.GetOk("credential_type"); ok || createOperation {
+			credentialType, err := v5.ParseCredentialType(data.Get("credential_type").(string))
+			if err != nil {
+				return logical.ErrorResponse(err.Error()), nil
+			}
+			role.CredentialType = credentialType
+		}
+
+		if credentialConfigRaw, ok := data.GetOk("credential_config"); ok {
+			role.CredentialConfig = credentialConfigRaw.(map[string]interface{})
+		} else if createOperation {
+			role.CredentialConfig = nil
+		}
+
+		if _, err := parseCredentialConfig(role.CredentialType, role.CredentialConfig); err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+	}
+
 	// TTLs
 	{
 		if defaultTTLRaw, ok := data.GetOk("default_ttl"); ok {
@@ -477,6 +538,17 @@ func (b *databaseBackend) pathStaticRoleCreateUpdate(ctx context.Context, req *l
 		role.Statements.Rotation = data.Get("rotation_statements").([]string)
 	}
 
+	if passwordPolicyRaw, ok := data.GetOk("password_policy"); ok {
+		role.PasswordPolicy = passwordPolicyRaw.(string)
+	} else if createRole {
+		role.PasswordPolicy = data.Get("password_policy").(string)
+	}
+	if role.PasswordPolicy != "" {
+		if _, err := b.System().GeneratePasswordFromPolicy(ctx, role.PasswordPolicy); err != nil {
+			return logical.ErrorResponse("unable to use password policy %q: %s", role.PasswordPolicy, err), nil
+		}
+	}
+
 	// lvr represents the roles' LastVaultRotation
 	lvr := role.StaticAccount.LastVaultRotation
 
@@ -527,6 +599,15 @@ type roleEntry struct {
 	DefaultTTL    time.Duration       `json:"default_ttl"`
 	MaxTTL        time.Duration       `json:"max_ttl"`
 	StaticAccount *staticAccount      `json:"static_account" mapstructure:"static_account"`
+
+	// PasswordPolicy is the name of the password policy used to generate
+	// passwords for static accounts
+	PasswordPolicy string `json:"password_policy,omitempty"`
+
+	// CredentialType is the type of credential issued by dynamic roles, and
+	// CredentialConfig its configuration
+	CredentialType   v5.CredentialType      `json:"credential_type"`
+	CredentialConfig map[string]interface{} `json:"credential_config,omitempty"`
 }
 
 type staticAccount struct {
@@ -621,6 +702,15 @@ The "renew_statements" parameter customizes the statement string used to renew a
 user.
 The "rollback_statements' parameter customizes the statement string used to
 rollback a change if needed.
+
+The "credential_type" parameter selects the credential issued to new users:
+"password" (the default), "rsa_private_key" or "client_certificate". RSA
+private keys and client certificates are generated by Vault, only the public
+key or certificate subject is passed to the database. They require a plugin
+implementing version 5 of the database plugin interface. The
+"credential_config" parameter configures the key size of RSA keys, or the CA
+("ca_cert" and "ca_private_key"), "common_name_template", "key_type" and
+"key_bits" of client certificates.
 `
 
 const pathStaticRoleHelpDesc = `
@@ -3,11 +3,10 @@ package database
 import (
 	"context"
 	"fmt"
-	"time"
 
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
-	"github.com/hashicorp/vault/sdk/queue"
 )
 
 func pathRotateCredentials(b *databaseBackend) []*framework.Path {
@@ -72,12 +71,39 @@ func (b *databaseBackend) pathRotateCredentialsUpdate() framework.OperationFunc
 		db.Lock()
 		defer db.Unlock()
 
-		connectionDetails, err := db.RotateRootCredentials(ctx, config.RootCredentialsRotateStatements)
+		// Version 4 plugins generate the new root password themselves and
+		// return the updated connection details
+		var newPassword string
+		if db.isV5() {
+			newPassword, err = b.generatePassword(ctx, db.databaseVersionWrapper, config.PasswordPolicy)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rootUsername, _ := config.ConnectionDetails["username"].(string)
+		if db.isV5() && rootUsername == "" {
+			return nil, fmt.Errorf("unable to rotate root credentials: no username in configuration")
+		}
+		updateReq := v5.UpdateUserRequest{
+			Username: rootUsername,
+			Password: &v5.ChangePassword{
+				NewPassword: newPassword,
+				Statements: v5.Statements{
+					Commands: config.RootCredentialsRotateStatements,
+				},
+			},
+		}
+		connectionDetails, err := db.UpdateUser(ctx, updateReq, true)
 		if err != nil {
 			return nil, err
 		}
 
-		config.ConnectionDetails = connectionDetails
+		if db.isV5() {
+			config.ConnectionDetails["password"] = newPassword
+		} else {
+			config.ConnectionDetails = connectionDetails
+		}
 		entry, err := logical.StorageEntryJSON(fmt.Sprintf("config/%s", name), config)
 		if err != nil {
 			return nil, err
@@ -88,7 +114,7 @@ func (b *databaseBackend) pathRotateCredentialsUpdate() framework.OperationFunc
 
 		// Close the plugin
 		db.closed = true
-		if err := db.Database.Close(); err != nil {
+		if err := db.databaseVersionWrapper.Close(); err != nil {
 			b.Logger().Error("error closing the database plugin connection", "err", err)
 		}
 		// Even on error, still remove the connection
@@ -114,34 +140,8 @@ func (b *databaseBackend) pathRotateRoleCredentialsUpdate() framework.OperationF
 
 		// In create/update of static accounts, we only care if the operation
 		// err'd , and this call does not return credentials
-		item, err := b.popFromRotationQueueByKey(name)
-		if err != nil {
-			item = &queue.Item{
-				Key: name,
-			}
-		}
-
-		resp, err := b.setStaticAccount(ctx, req.Storage, &setStaticAccountInput{
-			RoleName: name,
-			Role:     role,
-		})
-		if err != nil {
+		if err := b.rotateStaticRole(ctx, req.Storage, name, role); err != nil {
 			b.logger.Warn("unable to rotate credentials in rotate-role", "error", err)
-			// Update the priority to re-try this rotation and re-add the item to
-			// the queue
-			item.Priority = time.Now().Add(10 * time.Second).Unix()
-
-			// Preserve the WALID if it was returned
-			if resp.WALID != "" {
-				item.Value = resp.WALID
-			}
-		} else {
-			item.Priority = resp.RotationTime.Add(role.StaticAccount.RotationPeriod).Unix()
-		}
-
-		// Add their rotation to the queue
-		if err := b.pushItem(item); err != nil {
-			return nil, err
 		}
 
 		return nil, nil
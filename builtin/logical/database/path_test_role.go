@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// testUserTTL is the expiration of the users created by role tests, so
+// databases that support it expire users whose revocation fails
+const testUserTTL = time.Minute
+
+func pathTestRole(b *databaseBackend) []*framework.Path {
+	return []*framework.Path{
+		&framework.Path{
+			Pattern: "roles/" + framework.GenericNameRegex("name") + "/test$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: "Name of the role.",
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: b.pathTestRoleUpdate(),
+			},
+
+			HelpSynopsis:    pathTestRoleHelpSyn,
+			HelpDescription: pathTestRoleHelpDesc,
+		},
+	}
+}
+
+func (b *databaseBackend) pathTestRoleUpdate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := data.Get("name").(string)
+
+		role, err := b.Role(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", name)), nil
+		}
+
+		dbConfig, err := b.DatabaseConfig(ctx, req.Storage, role.DBName)
+		if err != nil {
+			return nil, err
+		}
+		if !strutil.StrListContains(dbConfig.AllowedRoles, "*") && !strutil.StrListContainsGlob(dbConfig.AllowedRoles, name) {
+			return nil, fmt.Errorf("%q is not an allowed role", name)
+		}
+
+		db, err := b.GetConnection(ctx, req.Storage, role.DBName)
+		if err != nil {
+			return nil, err
+		}
+
+		db.RLock()
+		defer db.RUnlock()
+
+		newUserReq, _, err := b.newUserRequest(ctx, db, dbConfig, role, name, req.DisplayName, time.Now().Add(testUserTTL))
+		if err != nil {
+			return nil, err
+		}
+
+		newUserResp, _, err := db.NewUser(ctx, newUserReq)
+		if err != nil {
+			b.CloseIfShutdown(db, err)
+			return testRoleFailure("create", err, role.Statements.Creation), nil
+		}
+
+		_, err = db.DeleteUser(ctx, v5.DeleteUserRequest{
+			Username: newUserResp.Username,
+			Statements: v5.Statements{
+				Commands: role.Statements.Revocation,
+			},
+		})
+		if err != nil {
+			b.CloseIfShutdown(db, err)
+			resp := testRoleFailure("revoke", err, role.Statements.Revocation)
+			resp.Data["username"] = newUserResp.Username
+			resp.AddWarning(fmt.Sprintf("The test user %q could not be revoked and must be removed from the database manually.", newUserResp.Username))
+			return resp, nil
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"success":  true,
+				"username": newUserResp.Username,
+			},
+		}, nil
+	}
+}
+
+// testRoleFailure reports the failure of an operation of a role test,
+// including the statement that failed if the plugin reported it
+func testRoleFailure(operation string, err error, statements []string) *logical.Response {
+	data := map[string]interface{}{
+		"success":          false,
+		"failed_operation": operation,
+		"error":            err.Error(),
+	}
+	if statement := failedStatement(err, statements); statement != "" {
+		data["failed_statement"] = statement
+	}
+	return &logical.Response{
+		Data: data,
+	}
+}
+
+// failedStatement returns the statement of statements an error of a plugin
+// reports as failed, or an empty string if there is none. Plugins report the
+// statement as given, before any values are substituted into it.
+func failedStatement(err error, statements []string) string {
+	for _, stmt := range statements {
+		for _, query := range strutil.ParseArbitraryStringSlice(stmt, ";") {
+			query = strings.TrimSpace(query)
+			if len(query) == 0 {
+				continue
+			}
+			if strings.Contains(err.Error(), fmt.Sprintf("%q", query)) {
+				return query
+			}
+		}
+	}
+	return ""
+}
+
+const pathTestRoleHelpSyn = `
+Test the statements of a role by creating and revoking a user.
+`
+
+const pathTestRoleHelpDesc = `
+This path creates a user with the creation statements of the role and
+immediately revokes it with its revocation statements, reporting whether both
+succeeded. If an operation fails, the error of the database is returned along
+with the statement that failed, if the plugin reports it. Users whose
+revocation fails must be removed from the database manually.
+`
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/helper/dbtxn"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// failingV5Database is a version 5 plugin whose user creation and
+// revocation fail with the configured errors
+type failingV5Database struct {
+	fakeV5Database
+	newUserErr    error
+	deleteUserErr error
+}
+
+func (f failingV5Database) NewUser(ctx context.Context, req v5.NewUserRequest) (v5.NewUserResponse, error) {
+	if f.newUserErr != nil {
+		return v5.NewUserResponse{}, f.newUserErr
+	}
+	return f.fakeV5Database.NewUser(ctx, req)
+}
+
+func (f failingV5Database) DeleteUser(ctx context.Context, req v5.DeleteUserRequest) (v5.DeleteUserResponse, error) {
+	return v5.DeleteUserResponse{}, f.deleteUserErr
+}
+
+func TestBackend_testRole(t *testing.T) {
+	ctx := context.Background()
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend(config)
+	if err := b.Setup(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := logical.StorageEntryJSON("config/db", &DatabaseConfig{
+		PluginName:   "fake",
+		AllowedRoles: []string{"ro"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.StorageView.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"ro", "rw"} {
+		resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "roles/" + name,
+			Storage:   config.StorageView,
+			Data: map[string]interface{}{
+				"db_name":               "db",
+				"creation_statements":   "CREATE USER '{{name}}'; GRANT SELECT TO '{{name}}';",
+				"revocation_statements": "DROP USER '{{name}}';",
+			},
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%v resp:%#v", err, resp)
+		}
+	}
+
+	testRole := func(role string, db v5.Database) (*logical.Response, error) {
+		b.connections["db"] = &dbPluginInstance{
+			databaseVersionWrapper: databaseVersionWrapper{v5: db},
+			name:                   "db",
+			id:                     "db-id",
+		}
+		return b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "roles/" + role + "/test",
+			Storage:   config.StorageView,
+		})
+	}
+
+	resp, err := testRole("ro", fakeV5Database{})
+	if err != nil || resp.Data["success"] != true || resp.Data["username"] != "v-ro" {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	// Failed statements are reported as given in the role
+	resp, err = testRole("ro", failingV5Database{
+		newUserErr: dbtxn.StatementError("GRANT SELECT TO '{{name}}'", errors.New("permission denied")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["success"] != false || resp.Data["failed_operation"] != "create" {
+		t.Fatalf("bad response %#v", resp.Data)
+	}
+	if resp.Data["failed_statement"] != "GRANT SELECT TO '{{name}}'" {
+		t.Fatalf("bad failed statement %#v", resp.Data["failed_statement"])
+	}
+
+	// Users whose revocation fails are reported for manual removal
+	resp, err = testRole("ro", failingV5Database{
+		deleteUserErr: errors.New("connection reset"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["failed_operation"] != "revoke" || resp.Data["username"] != "v-ro" || len(resp.Warnings) != 1 {
+		t.Fatalf("bad response %#v", resp)
+	}
+	if _, ok := resp.Data["failed_statement"]; ok {
+		t.Fatalf("unexpected failed statement %#v", resp.Data["failed_statement"])
+	}
+
+	// Roles must be allowed by the connection
+	if _, err := testRole("rw", fakeV5Database{}); err == nil {
+		t.Fatal("expected error testing a role not allowed by the connection")
+	}
+
+	resp, err = testRole("missing", fakeV5Database{})
+	if err != nil || !resp.IsError() {
+		t.Fatalf("expected error response for an unknown role, err:%v resp:%#v", err, resp)
+	}
+}
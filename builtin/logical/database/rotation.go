@@ -8,7 +8,7 @@ import (
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/go-multierror"
-	"github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/locksutil"
@@ -179,6 +179,19 @@ func (b *databaseBackend) rotateCredentials(ctx context.Context, s logical.Stora
 			break
 		}
 
+		// Checked out accounts are rotated when they are checked in
+		status, err := b.checkOutStatus(ctx, s, item.Key)
+		if err != nil {
+			b.logger.Error("unable to load check-out status", "role", item.Key, "error", err)
+		}
+		if status != nil && !status.IsAvailable {
+			item.Priority = time.Now().Add(role.StaticAccount.RotationPeriod).Unix()
+			if err := b.pushItem(item); err != nil {
+				b.logger.Error("unable to push item on to queue", "error", err)
+			}
+			continue
+		}
+
 		input := &setStaticAccountInput{
 			RoleName: item.Key,
 			Role:     role,
@@ -235,6 +248,42 @@ func (b *databaseBackend) rotateCredentials(ctx context.Context, s logical.Stora
 	return nil
 }
 
+// rotateStaticRole immediately rotates the password of a static role and
+// reschedules its next rotation on the queue. If the rotation fails, the queue
+// retries it shortly after.
+func (b *databaseBackend) rotateStaticRole(ctx context.Context, s logical.Storage, name string, role *roleEntry) error {
+	item, err := b.popFromRotationQueueByKey(name)
+	if err != nil {
+		item = &queue.Item{
+			Key: name,
+		}
+	}
+
+	resp, err := b.setStaticAccount(ctx, s, &setStaticAccountInput{
+		RoleName: name,
+		Role:     role,
+	})
+	if err != nil {
+		// Update the priority to re-try this rotation and re-add the item to
+		// the queue
+		item.Priority = time.Now().Add(10 * time.Second).Unix()
+
+		// Preserve the WALID if it was returned
+		if resp != nil && resp.WALID != "" {
+			item.Value = resp.WALID
+		}
+	} else {
+		item.Priority = resp.RotationTime.Add(role.StaticAccount.RotationPeriod).Unix()
+	}
+
+	// Add their rotation to the queue
+	if pushErr := b.pushItem(item); pushErr != nil {
+		return pushErr
+	}
+
+	return err
+}
+
 // findStaticWAL loads a WAL entry by ID. If found, only return the WAL if it
 // is of type staticWALKey, otherwise return nil
 func (b *databaseBackend) findStaticWAL(ctx context.Context, s logical.Storage, id string) (*setCredentialsWAL, error) {
@@ -326,24 +375,24 @@ func (b *databaseBackend) setStaticAccount(ctx context.Context, s logical.Storag
 	// associated with it
 	newPassword := input.Password
 	if newPassword == "" {
-		// Generate a new password
-		newPassword, err = db.GenerateCredentials(ctx)
+		// Generate a new password, using the role's or the connection's
+		// password policy if there is one
+		passwordPolicy := input.Role.PasswordPolicy
+		if passwordPolicy == "" {
+			passwordPolicy = dbConfig.PasswordPolicy
+		}
+		newPassword, err = b.generatePassword(ctx, db.databaseVersionWrapper, passwordPolicy)
 		if err != nil {
 			return output, err
 		}
 	}
 	output.Password = newPassword
 
-	config := dbplugin.StaticUserConfig{
-		Username: input.Role.StaticAccount.Username,
-		Password: newPassword,
-	}
-
 	if output.WALID == "" {
 		output.WALID, err = framework.PutWAL(ctx, s, staticWALKey, &setCredentialsWAL{
 			RoleName:          input.RoleName,
-			Username:          config.Username,
-			NewPassword:       config.Password,
+			Username:          input.Role.StaticAccount.Username,
+			NewPassword:       newPassword,
 			OldPassword:       input.Role.StaticAccount.Password,
 			LastVaultRotation: input.Role.StaticAccount.LastVaultRotation,
 		})
@@ -352,21 +401,27 @@ func (b *databaseBackend) setStaticAccount(ctx context.Context, s logical.Storag
 		}
 	}
 
-	_, password, err := db.SetCredentials(ctx, input.Role.Statements, config)
+	updateReq := v5.UpdateUserRequest{
+		Username:       input.Role.StaticAccount.Username,
+		CredentialType: v5.CredentialTypePassword,
+		Password: &v5.ChangePassword{
+			NewPassword: newPassword,
+			Statements: v5.Statements{
+				Commands: input.Role.Statements.Rotation,
+			},
+		},
+	}
+	_, err = db.UpdateUser(ctx, updateReq, false)
 	if err != nil {
 		b.CloseIfShutdown(db, err)
 		return output, errwrap.Wrapf("error setting credentials: {{err}}", err)
 	}
 
-	if newPassword != password {
-		return output, errors.New("mismatch passwords returned")
-	}
-
 	// Store updated role information
 	// lvr is the known LastVaultRotation
 	lvr := time.Now()
 	input.Role.StaticAccount.LastVaultRotation = lvr
-	input.Role.StaticAccount.Password = password
+	input.Role.StaticAccount.Password = newPassword
 	output.RotationTime = lvr
 
 	entry, err := logical.StorageEntryJSON(databaseStaticRolePath+input.RoleName, input.Role)
@@ -164,6 +164,104 @@ func TestBackend_StaticRole_Rotate_basic(t *testing.T) {
 	verifyPgConn(t, username, newPassword, connURL)
 }
 
+func TestBackend_StaticRole_Rotate_PasswordPolicy(t *testing.T) {
+	cluster, sys := getCluster(t)
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+	_, err := client.Logical().Write("sys/policies/password/digits", map[string]interface{}{
+		"policy": `length = 24 rule "charset" { charset = "0123456789" }`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	config.System = sys
+
+	lb, err := Factory(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := lb.(*databaseBackend)
+	if !ok {
+		t.Fatal("could not convert to db backend")
+	}
+	defer b.Cleanup(context.Background())
+
+	cleanup, connURL := preparePostgresTestContainer(t, config.StorageView, b)
+	defer cleanup()
+
+	createTestPGUser(t, connURL, dbUser, "password", testRoleStaticCreate)
+
+	// Unknown password policies are rejected
+	data := map[string]interface{}{
+		"connection_url":    connURL,
+		"plugin_name":       "postgresql-database-plugin",
+		"verify_connection": false,
+		"allowed_roles":     []string{"*"},
+		"password_policy":   "missing",
+	}
+	req := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config/plugin-test",
+		Storage:   config.StorageView,
+		Data:      data,
+	}
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected error response, got err:%s resp:%#v\n", err, resp)
+	}
+
+	// Version 4 plugins generate the passwords of dynamic credentials
+	// themselves, so the policy is set on the static role instead
+	data["password_policy"] = "digits"
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected error response, got err:%s resp:%#v\n", err, resp)
+	}
+	delete(data, "password_policy")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+
+	data = map[string]interface{}{
+		"db_name":             "plugin-test",
+		"rotation_statements": testRoleStaticUpdate,
+		"username":            dbUser,
+		"rotation_period":     "5400s",
+		"password_policy":     "digits",
+	}
+	req = &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "static-roles/plugin-role-test",
+		Storage:   config.StorageView,
+		Data:      data,
+	}
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+
+	req = &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "static-creds/plugin-role-test",
+		Storage:   config.StorageView,
+	}
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+
+	password := resp.Data["password"].(string)
+	if len(password) != 24 || strings.Trim(password, "0123456789") != "" {
+		t.Fatalf("expected password generated from the policy, got %q", password)
+	}
+	verifyPgConn(t, dbUser, password, connURL)
+}
+
 // Sanity check to make sure we don't allow an attempt of rotating credentials
 // for non-static accounts, which doesn't make sense anyway, but doesn't hurt to
 // verify we return an error
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const SecretCheckOutType = "check_out"
+
+func secretCheckOut(b *databaseBackend) *framework.Secret {
+	return &framework.Secret{
+		Type:   SecretCheckOutType,
+		Fields: map[string]*framework.FieldSchema{},
+
+		Renew:  b.secretCheckOutRenew,
+		Revoke: b.secretCheckOutRevoke,
+	}
+}
+
+// currentCheckOut returns the library set of the check-out of a lease, or nil
+// if the static role has been checked in since
+func (b *databaseBackend) currentCheckOut(ctx context.Context, req *logical.Request) (*libraryEntry, string, error) {
+	roleName, ok := req.Secret.InternalData["static_role"].(string)
+	if !ok {
+		return nil, "", errors.New("secret is missing static role internal data")
+	}
+	checkOutID, ok := req.Secret.InternalData["check_out_id"].(string)
+	if !ok {
+		return nil, "", errors.New("secret is missing check-out ID internal data")
+	}
+
+	status, err := b.checkOutStatus(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, "", err
+	}
+	if status == nil || status.IsAvailable || status.CheckOutID != checkOutID {
+		return nil, roleName, nil
+	}
+
+	set, err := b.library(ctx, req.Storage, status.SetName)
+	if err != nil {
+		return nil, "", err
+	}
+	return set, roleName, nil
+}
+
+func (b *databaseBackend) secretCheckOutRenew(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.checkOutLock.Lock()
+	defer b.checkOutLock.Unlock()
+
+	set, _, err := b.currentCheckOut(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, errors.New("the account is no longer checked out by this lease")
+	}
+
+	resp := &logical.Response{Secret: req.Secret}
+	resp.Secret.TTL = set.TTL
+	resp.Secret.MaxTTL = set.MaxTTL
+	return resp, nil
+}
+
+func (b *databaseBackend) secretCheckOutRevoke(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.checkOutLock.Lock()
+	defer b.checkOutLock.Unlock()
+
+	set, roleName, err := b.currentCheckOut(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// The account was checked in already, possibly checked out again
+	if set == nil {
+		return nil, nil
+	}
+
+	return nil, b.checkIn(ctx, req.Storage, roleName)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -63,7 +64,16 @@ func (b *databaseBackend) secretCredsRenew() framework.OperationFunc {
 			// Adding a small buffer since the TTL will be calculated again after this call
 			// to ensure the database credential does not expire before the lease
 			expireTime = expireTime.Add(5 * time.Second)
-			err := db.RenewUser(ctx, role.Statements, username, expireTime)
+			updateReq := v5.UpdateUserRequest{
+				Username: username,
+				Expiration: &v5.ChangeExpiration{
+					NewExpiration: expireTime,
+					Statements: v5.Statements{
+						Commands: role.Statements.Renewal,
+					},
+				},
+			}
+			_, err := db.UpdateUser(ctx, updateReq, false)
 			if err != nil {
 				b.CloseIfShutdown(db, err)
 				return nil, err
@@ -136,7 +146,13 @@ func (b *databaseBackend) secretCredsRevoke() framework.OperationFunc {
 		db.RLock()
 		defer db.RUnlock()
 
-		if err := db.RevokeUser(ctx, statements, username); err != nil {
+		deleteReq := v5.DeleteUserRequest{
+			Username: username,
+			Statements: v5.Statements{
+				Commands: statements.Revocation,
+			},
+		}
+		if _, err := db.DeleteUser(ctx, deleteReq); err != nil {
 			b.CloseIfShutdown(db, err)
 			return nil, err
 		}
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	v4 "github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+)
+
+// databaseVersionWrapper hides whether the plugin of a connection implements
+// version 4 or version 5 of the database plugin interface. Version 4 plugins
+// generate credentials themselves, so they only support passwords and ignore
+// the password Vault generates for new users. Exactly one of v4 and v5 is
+// set.
+type databaseVersionWrapper struct {
+	v4 v4.Database
+	v5 v5.Database
+}
+
+// newDatabaseWrapper loads a plugin, preferring version 5 of the interface
+// and falling back to version 4
+func newDatabaseWrapper(ctx context.Context, pluginName string, sys pluginutil.LookRunnerUtil, logger log.Logger) (databaseVersionWrapper, error) {
+	newDB, err := v5.PluginFactory(ctx, pluginName, sys, logger)
+	if err == nil {
+		return databaseVersionWrapper{
+			v5: newDB,
+		}, nil
+	}
+
+	legacyDB, legacyErr := v4.PluginFactory(ctx, pluginName, sys, logger)
+	if legacyErr == nil {
+		return databaseVersionWrapper{
+			v4: legacyDB,
+		}, nil
+	}
+
+	if err == v5.ErrNotV5 {
+		return databaseVersionWrapper{}, legacyErr
+	}
+	return databaseVersionWrapper{}, fmt.Errorf("unable to load plugin as version 5 (%s) or version 4 (%s)", err, legacyErr)
+}
+
+// Initialize configures the plugin and returns the configuration to store
+func (d databaseVersionWrapper) Initialize(ctx context.Context, req v5.InitializeRequest) (v5.InitializeResponse, error) {
+	if d.isV5() {
+		return d.v5.Initialize(ctx, req)
+	}
+
+	config, err := d.v4.Init(ctx, req.Config, req.VerifyConnection)
+	if err != nil {
+		return v5.InitializeResponse{}, err
+	}
+	return v5.InitializeResponse{
+		Config: config,
+	}, nil
+}
+
+// NewUser creates a user and returns the password the user authenticates
+// with. For version 5 plugins that is the password of the request, version
+// 4 plugins generate their own.
+func (d databaseVersionWrapper) NewUser(ctx context.Context, req v5.NewUserRequest) (resp v5.NewUserResponse, password string, err error) {
+	if d.isV5() {
+		resp, err = d.v5.NewUser(ctx, req)
+		return resp, req.Password, err
+	}
+
+	if req.CredentialType != v5.CredentialTypePassword {
+		return v5.NewUserResponse{}, "", fmt.Errorf("credential type %q requires a plugin implementing version 5 of the database plugin interface", req.CredentialType)
+	}
+
+	statements := v4.Statements{
+		Creation: req.Statements.Commands,
+		Rollback: req.RollbackStatements.Commands,
+	}
+	usernameConfig := v4.UsernameConfig{
+		DisplayName: req.UsernameConfig.DisplayName,
+		RoleName:    req.UsernameConfig.RoleName,
+		Username:    req.UsernameConfig.Username,
+	}
+
+	username, password, err := d.v4.CreateUser(ctx, statements, usernameConfig, req.Expiration)
+	if err != nil {
+		return v5.NewUserResponse{}, "", err
+	}
+	return v5.NewUserResponse{
+		Username: username,
+	}, password, nil
+}
+
+// UpdateUser changes the password and/or expiration of a user. Version 4
+// plugins rotate root credentials with a password they generate, in which
+// case the configuration to store is returned.
+func (d databaseVersionWrapper) UpdateUser(ctx context.Context, req v5.UpdateUserRequest, isRootUser bool) (saveConfig map[string]interface{}, err error) {
+	if d.isV5() {
+		_, err := d.v5.UpdateUser(ctx, req)
+		return nil, err
+	}
+
+	if req.PublicKey != nil {
+		return nil, errors.New("changing public keys requires a plugin implementing version 5 of the database plugin interface")
+	}
+
+	if req.Password != nil {
+		if isRootUser {
+			return d.v4.RotateRootCredentials(ctx, req.Password.Statements.Commands)
+		}
+
+		statements := v4.Statements{
+			Rotation: req.Password.Statements.Commands,
+		}
+		staticConfig := v4.StaticUserConfig{
+			Username: req.Username,
+			Password: req.Password.NewPassword,
+		}
+		_, password, err := d.v4.SetCredentials(ctx, statements, staticConfig)
+		if err != nil {
+			return nil, err
+		}
+		if password != req.Password.NewPassword {
+			return nil, errors.New("mismatch passwords returned")
+		}
+	}
+
+	if req.Expiration != nil {
+		statements := v4.Statements{
+			Renewal: req.Expiration.Statements.Commands,
+		}
+		if err := d.v4.RenewUser(ctx, statements, req.Username, req.Expiration.NewExpiration); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteUser removes a user
+func (d databaseVersionWrapper) DeleteUser(ctx context.Context, req v5.DeleteUserRequest) (v5.DeleteUserResponse, error) {
+	if d.isV5() {
+		return d.v5.DeleteUser(ctx, req)
+	}
+
+	statements := v4.Statements{
+		Revocation: req.Statements.Commands,
+	}
+	return v5.DeleteUserResponse{}, d.v4.RevokeUser(ctx, statements, req.Username)
+}
+
+// GeneratePassword returns a password generated by a version 4 plugin. The
+// passwords of users of version 5 plugins are always generated by Vault.
+func (d databaseVersionWrapper) GeneratePassword(ctx context.Context) (string, error) {
+	if d.isV5() {
+		return "", errors.New("version 5 plugins do not generate passwords")
+	}
+	return d.v4.GenerateCredentials(ctx)
+}
+
+// Type returns the type of the database
+func (d databaseVersionWrapper) Type() (string, error) {
+	if d.isV5() {
+		return d.v5.Type()
+	}
+	return d.v4.Type()
+}
+
+// Close closes the plugin
+func (d databaseVersionWrapper) Close() error {
+	if d.isV5() {
+		return d.v5.Close()
+	}
+	return d.v4.Close()
+}
+
+func (d databaseVersionWrapper) isV5() bool {
+	return d.v5 != nil
+}
+
+func (d databaseVersionWrapper) isV4() bool {
+	return d.v4 != nil
+}
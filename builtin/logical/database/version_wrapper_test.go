@@ -0,0 +1,258 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v4 "github.com/hashicorp/vault/sdk/database/dbplugin"
+	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+)
+
+// recordingV4Database records the calls made to a version 4 plugin
+type recordingV4Database struct {
+	calls      []string
+	statements v4.Statements
+	username   string
+	expiration time.Time
+}
+
+func (r *recordingV4Database) Type() (string, error) { return "recording", nil }
+
+func (r *recordingV4Database) CreateUser(_ context.Context, statements v4.Statements, usernameConfig v4.UsernameConfig, expiration time.Time) (string, string, error) {
+	r.calls = append(r.calls, "CreateUser")
+	r.statements = statements
+	r.expiration = expiration
+	return "v-" + usernameConfig.RoleName, "plugin-password", nil
+}
+
+func (r *recordingV4Database) RenewUser(_ context.Context, statements v4.Statements, username string, expiration time.Time) error {
+	r.calls = append(r.calls, "RenewUser")
+	r.statements = statements
+	r.username = username
+	r.expiration = expiration
+	return nil
+}
+
+func (r *recordingV4Database) RevokeUser(_ context.Context, statements v4.Statements, username string) error {
+	r.calls = append(r.calls, "RevokeUser")
+	r.statements = statements
+	r.username = username
+	return nil
+}
+
+func (r *recordingV4Database) RotateRootCredentials(_ context.Context, statements []string) (map[string]interface{}, error) {
+	r.calls = append(r.calls, "RotateRootCredentials")
+	r.statements = v4.Statements{Rotation: statements}
+	return map[string]interface{}{"username": "root", "password": "rotated"}, nil
+}
+
+func (r *recordingV4Database) GenerateCredentials(_ context.Context) (string, error) {
+	r.calls = append(r.calls, "GenerateCredentials")
+	return "generated", nil
+}
+
+func (r *recordingV4Database) SetCredentials(_ context.Context, statements v4.Statements, staticConfig v4.StaticUserConfig) (string, string, error) {
+	r.calls = append(r.calls, "SetCredentials")
+	r.statements = statements
+	r.username = staticConfig.Username
+	return staticConfig.Username, staticConfig.Password, nil
+}
+
+func (r *recordingV4Database) Init(_ context.Context, config map[string]interface{}, _ bool) (map[string]interface{}, error) {
+	r.calls = append(r.calls, "Init")
+	return config, nil
+}
+
+func (r *recordingV4Database) Initialize(ctx context.Context, config map[string]interface{}, verifyConnection bool) error {
+	_, err := r.Init(ctx, config, verifyConnection)
+	return err
+}
+
+func (r *recordingV4Database) Close() error {
+	r.calls = append(r.calls, "Close")
+	return nil
+}
+
+// fakeV5Database is a version 5 plugin that accepts all requests
+type fakeV5Database struct{}
+
+func (fakeV5Database) Initialize(_ context.Context, req v5.InitializeRequest) (v5.InitializeResponse, error) {
+	return v5.InitializeResponse{Config: req.Config}, nil
+}
+
+func (fakeV5Database) NewUser(_ context.Context, req v5.NewUserRequest) (v5.NewUserResponse, error) {
+	return v5.NewUserResponse{Username: "v-" + req.UsernameConfig.RoleName}, nil
+}
+
+func (fakeV5Database) UpdateUser(_ context.Context, _ v5.UpdateUserRequest) (v5.UpdateUserResponse, error) {
+	return v5.UpdateUserResponse{}, nil
+}
+
+func (fakeV5Database) DeleteUser(_ context.Context, _ v5.DeleteUserRequest) (v5.DeleteUserResponse, error) {
+	return v5.DeleteUserResponse{}, nil
+}
+
+func (fakeV5Database) Type() (string, error) { return "fake", nil }
+
+func (fakeV5Database) Close() error { return nil }
+
+func TestDatabaseVersionWrapper_v4(t *testing.T) {
+	ctx := context.Background()
+	legacy := &recordingV4Database{}
+	db := databaseVersionWrapper{v4: legacy}
+	expiration := time.Now().Add(time.Hour)
+
+	// NewUser returns the password generated by the plugin
+	resp, password, err := db.NewUser(ctx, v5.NewUserRequest{
+		UsernameConfig:     v5.UsernameMetadata{RoleName: "ro"},
+		CredentialType:     v5.CredentialTypePassword,
+		Password:           "ignored",
+		Statements:         v5.Statements{Commands: []string{"CREATE"}},
+		RollbackStatements: v5.Statements{Commands: []string{"ROLLBACK"}},
+		Expiration:         expiration,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Username != "v-ro" || password != "plugin-password" {
+		t.Fatalf("bad username %q or password %q", resp.Username, password)
+	}
+	expectedStatements := v4.Statements{Creation: []string{"CREATE"}, Rollback: []string{"ROLLBACK"}}
+	if !reflect.DeepEqual(legacy.statements, expectedStatements) || !legacy.expiration.Equal(expiration) {
+		t.Fatalf("bad statements %#v or expiration %s", legacy.statements, legacy.expiration)
+	}
+
+	// Only passwords are supported
+	if _, _, err := db.NewUser(ctx, v5.NewUserRequest{CredentialType: v5.CredentialTypeRSAPrivateKey}); err == nil {
+		t.Fatal("expected error for RSA key credentials")
+	}
+
+	// Renewal
+	_, err = db.UpdateUser(ctx, v5.UpdateUserRequest{
+		Username: "v-ro",
+		Expiration: &v5.ChangeExpiration{
+			NewExpiration: expiration,
+			Statements:    v5.Statements{Commands: []string{"RENEW"}},
+		},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(legacy.statements, v4.Statements{Renewal: []string{"RENEW"}}) || legacy.username != "v-ro" {
+		t.Fatalf("bad renewal of %q with %#v", legacy.username, legacy.statements)
+	}
+
+	// Static account rotation
+	_, err = db.UpdateUser(ctx, v5.UpdateUserRequest{
+		Username: "static",
+		Password: &v5.ChangePassword{
+			NewPassword: "new-password",
+			Statements:  v5.Statements{Commands: []string{"ROTATE"}},
+		},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(legacy.statements, v4.Statements{Rotation: []string{"ROTATE"}}) || legacy.username != "static" {
+		t.Fatalf("bad rotation of %q with %#v", legacy.username, legacy.statements)
+	}
+
+	// Root rotation returns the connection details to store
+	saveConfig, err := db.UpdateUser(ctx, v5.UpdateUserRequest{
+		Username: "root",
+		Password: &v5.ChangePassword{
+			Statements: v5.Statements{Commands: []string{"ROTATE ROOT"}},
+		},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saveConfig["password"] != "rotated" {
+		t.Fatalf("bad connection details %#v", saveConfig)
+	}
+
+	// Revocation
+	if _, err := db.DeleteUser(ctx, v5.DeleteUserRequest{
+		Username:   "v-ro",
+		Statements: v5.Statements{Commands: []string{"DROP"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(legacy.statements, v4.Statements{Revocation: []string{"DROP"}}) {
+		t.Fatalf("bad revocation statements %#v", legacy.statements)
+	}
+
+	expectedCalls := []string{"CreateUser", "RenewUser", "SetCredentials", "RotateRootCredentials", "RevokeUser"}
+	if !reflect.DeepEqual(legacy.calls, expectedCalls) {
+		t.Fatalf("expected calls %v, got %v", expectedCalls, legacy.calls)
+	}
+}
+
+func TestDatabaseVersionWrapper_v5(t *testing.T) {
+	ctx := context.Background()
+	db := databaseVersionWrapper{v5: fakeV5Database{}}
+
+	// NewUser returns the password of the request
+	resp, password, err := db.NewUser(ctx, v5.NewUserRequest{
+		UsernameConfig: v5.UsernameMetadata{RoleName: "ro"},
+		CredentialType: v5.CredentialTypePassword,
+		Password:       "vault-password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Username != "v-ro" || password != "vault-password" {
+		t.Fatalf("bad username %q or password %q", resp.Username, password)
+	}
+
+	// Passwords are generated by Vault
+	if _, err := db.GeneratePassword(ctx); err == nil {
+		t.Fatal("expected error generating a password with a version 5 plugin")
+	}
+	b := &databaseBackend{}
+	generated, err := b.generatePassword(ctx, db, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generated) != 20 {
+		t.Fatalf("expected a password of 20 characters, got %q", generated)
+	}
+}
+
+func TestConnectionVerificationReport(t *testing.T) {
+	ctx := context.Background()
+	config := map[string]interface{}{"connection_url": "db"}
+
+	// Version 4 plugins can't report the server version
+	initResp, err := databaseVersionWrapper{v4: &recordingV4Database{}}.Initialize(ctx, v5.InitializeRequest{Config: config, VerifyConnection: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := connectionVerificationReport(initResp, time.Millisecond)
+	if !reflect.DeepEqual(report, map[string]interface{}{"latency": "1ms"}) {
+		t.Fatalf("bad: %#v", report)
+	}
+
+	initResp, err = databaseVersionWrapper{v5: versionedV5Database{version: "6.0.5"}}.Initialize(ctx, v5.InitializeRequest{Config: config, VerifyConnection: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report = connectionVerificationReport(initResp, time.Millisecond)
+	if !reflect.DeepEqual(report, map[string]interface{}{"latency": "1ms", "server_version": "6.0.5"}) {
+		t.Fatalf("bad: %#v", report)
+	}
+}
+
+// versionedV5Database is a version 5 plugin reporting a server version
+type versionedV5Database struct {
+	fakeV5Database
+	version string
+}
+
+func (v versionedV5Database) Initialize(ctx context.Context, req v5.InitializeRequest) (v5.InitializeResponse, error) {
+	resp, err := v.fakeV5Database.Initialize(ctx, req)
+	resp.ServerVersion = v.version
+	return resp, err
+}
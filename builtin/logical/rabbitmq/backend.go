@@ -73,19 +73,14 @@ func (b *backend) Client(ctx context.Context, s logical.Storage) (*rabbithole.Cl
 	b.lock.RUnlock()
 
 	// Otherwise, attempt to make connection
-	entry, err := s.Get(ctx, "config/connection")
+	connConfig, err := b.connectionConfig(ctx, s)
 	if err != nil {
 		return nil, err
 	}
-	if entry == nil {
+	if connConfig == nil {
 		return nil, fmt.Errorf("configure the client connection with config/connection first")
 	}
 
-	var connConfig connectionConfig
-	if err := entry.DecodeJSON(&connConfig); err != nil {
-		return nil, err
-	}
-
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
@@ -104,6 +99,24 @@ func (b *backend) Client(ctx context.Context, s logical.Storage) (*rabbithole.Cl
 	return b.client, nil
 }
 
+// connectionConfig reads the connection configuration from the storage
+func (b *backend) connectionConfig(ctx context.Context, s logical.Storage) (*connectionConfig, error) {
+	entry, err := s.Get(ctx, "config/connection")
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result connectionConfig
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // resetClient forces a connection next time Client() is called.
 func (b *backend) resetClient(_ context.Context) {
 	b.lock.Lock()
@@ -2,9 +2,13 @@ package rabbitmq
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/helper/template"
 	"github.com/hashicorp/vault/sdk/logical"
 	rabbithole "github.com/michaelklishin/rabbit-hole"
 )
@@ -30,6 +34,14 @@ func pathConfigConnection(b *backend) *framework.Path {
 				Default:     true,
 				Description: `If set, connection_uri is verified by actually connecting to the RabbitMQ management API`,
 			},
+			"username_template": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: `Go template used to generate the usernames of dynamic credentials. Defaults to the display name followed by a UUID.`,
+			},
+			"allowed_tags": &framework.FieldSchema{
+				Type:        framework.TypeCommaStringSlice,
+				Description: `Comma-separated list of user tags roles may assign. Defaults to the built-in RabbitMQ tags.`,
+			},
 		},
 
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -57,6 +69,15 @@ func (b *backend) pathConnectionUpdate(ctx context.Context, req *logical.Request
 		return logical.ErrorResponse("missing password"), nil
 	}
 
+	usernameTemplate := data.Get("username_template").(string)
+	if usernameTemplate != "" {
+		if _, err := template.NewTemplate(usernameTemplate); err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("invalid username_template: %s", err)), nil
+		}
+	}
+
+	allowedTags := data.Get("allowed_tags").([]string)
+
 	// Don't check the connection_url if verification is disabled
 	verifyConnection := data.Get("verify_connection").(bool)
 	if verifyConnection {
@@ -74,9 +95,11 @@ func (b *backend) pathConnectionUpdate(ctx context.Context, req *logical.Request
 
 	// Store it
 	entry, err := logical.StorageEntryJSON("config/connection", connectionConfig{
-		URI:      uri,
-		Username: username,
-		Password: password,
+		URI:              uri,
+		Username:         username,
+		Password:         password,
+		UsernameTemplate: usernameTemplate,
+		AllowedTags:      allowedTags,
 	})
 	if err != nil {
 		return nil, err
@@ -101,6 +124,42 @@ type connectionConfig struct {
 
 	// Password for the Username
 	Password string `json:"password"`
+
+	// UsernameTemplate generates the usernames of dynamic credentials
+	UsernameTemplate string `json:"username_template"`
+
+	// AllowedTags restricts the user tags roles may assign
+	AllowedTags []string `json:"allowed_tags"`
+}
+
+// defaultAllowedTags are the user tags built into RabbitMQ, which roles may
+// assign unless allowed_tags is configured
+var defaultAllowedTags = []string{
+	"administrator",
+	"impersonator",
+	"management",
+	"monitoring",
+	"policymaker",
+}
+
+// allowedTags returns the user tags roles may assign
+func (c *connectionConfig) allowedTags() []string {
+	if c == nil || len(c.AllowedTags) == 0 {
+		return defaultAllowedTags
+	}
+	return c.AllowedTags
+}
+
+// validateTags checks a comma-separated list of user tags against the tags
+// allowed by the connection
+func (c *connectionConfig) validateTags(tags string) error {
+	allowed := c.allowedTags()
+	for _, tag := range strutil.ParseDedupAndSortStrings(tags, ",") {
+		if !strutil.StrListContains(allowed, tag) {
+			return fmt.Errorf("tag %q is not allowed, allowed tags are: %s", tag, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
 }
 
 const pathConfigConnectionHelpSyn = `
@@ -112,7 +171,9 @@ This path configures the connection properties used to connect to RabbitMQ manag
 The "connection_uri" parameter is a string that is used to connect to the API. The "username"
 and "password" parameters are strings that are used as credentials to the API. The "verify_connection"
 parameter is a boolean that is used to verify whether the provided connection URI, username, and password
-are valid.
+are valid. The "username_template" parameter is a Go template that generates the usernames of dynamic
+credentials, rendered with the .DisplayName and .RoleName of the request. The "allowed_tags" parameter
+restricts the user tags roles may assign; it defaults to the tags built into RabbitMQ.
 
 The URI looks like:
 "http://localhost:15672"
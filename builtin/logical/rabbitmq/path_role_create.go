@@ -8,6 +8,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/template"
 	"github.com/hashicorp/vault/sdk/logical"
 	rabbithole "github.com/michaelklishin/rabbit-hole"
 )
@@ -47,12 +48,21 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 		return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", name)), nil
 	}
 
-	// Ensure username is unique
-	uuidVal, err := uuid.GenerateUUID()
+	connConfig, err := b.connectionConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	// The allowed tags may have been narrowed since the role was written,
+	// which revokes the roles relying on the tags removed
+	if err := connConfig.validateTags(role.Tags); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	username, err := generateUsername(connConfig, req.DisplayName, name)
 	if err != nil {
 		return nil, err
 	}
-	username := fmt.Sprintf("%s-%s", req.DisplayName, uuidVal)
 
 	password, err := uuid.GenerateUUID()
 	if err != nil {
@@ -93,6 +103,25 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 		}
 	}
 
+	// If the role had topic permissions specified, assign those permissions
+	// to the created username for respective exchanges of vhosts.
+	for vhost, exchanges := range role.VHostTopics {
+		for exchange, permission := range exchanges {
+			if err := updateTopicPermissionsIn(client, vhost, username, topicPermissions{
+				Exchange: exchange,
+				Write:    permission.Write,
+				Read:     permission.Read,
+			}); err != nil {
+				outerErr := errwrap.Wrapf(fmt.Sprintf("failed to update topic permissions to the %q user: {{err}}", username), err)
+				// Delete the user because it's in an unknown state
+				if _, rmErr := client.DeleteUser(username); rmErr != nil {
+					return nil, multierror.Append(errwrap.Wrapf("failed to delete user: {{err}}", rmErr), outerErr)
+				}
+				return nil, outerErr
+			}
+		}
+	}
+
 	// Return the secret
 	resp := b.Secret(SecretCredsType).Response(map[string]interface{}{
 		"username": username,
@@ -115,6 +144,41 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 	return resp, nil
 }
 
+// usernameTemplateData is the data username templates are rendered with
+type usernameTemplateData struct {
+	DisplayName string
+	RoleName    string
+}
+
+// generateUsername generates a username with the connection's username
+// template, or the display name followed by a UUID when none is configured
+func generateUsername(connConfig *connectionConfig, displayName, roleName string) (string, error) {
+	if connConfig == nil || connConfig.UsernameTemplate == "" {
+		// Ensure username is unique
+		uuidVal, err := uuid.GenerateUUID()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s-%s", displayName, uuidVal), nil
+	}
+
+	tmpl, err := template.NewTemplate(connConfig.UsernameTemplate)
+	if err != nil {
+		return "", err
+	}
+	username, err := tmpl.Generate(usernameTemplateData{
+		DisplayName: displayName,
+		RoleName:    roleName,
+	})
+	if err != nil {
+		return "", err
+	}
+	if username == "" {
+		return "", fmt.Errorf("username template rendered an empty username")
+	}
+	return username, nil
+}
+
 const pathRoleCreateReadHelpSyn = `
 Request RabbitMQ credentials for a certain role.
 `
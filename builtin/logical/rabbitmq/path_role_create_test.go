@@ -0,0 +1,240 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// stubRequest is a request received by the stub management API
+type stubRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// stubManagementAPI is a stub of the RabbitMQ management API recording the
+// requests it receives
+type stubManagementAPI struct {
+	sync.Mutex
+	requests []stubRequest
+	users    map[string]bool
+}
+
+func (s *stubManagementAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	req := stubRequest{
+		Method: r.Method,
+		Path:   r.URL.EscapedPath(),
+	}
+	if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+		if err := json.Unmarshal(body, &req.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	s.requests = append(s.requests, req)
+
+	switch {
+	case r.Method == http.MethodGet && req.Path == "/api/users/":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	case r.Method == http.MethodPut && strings.HasPrefix(req.Path, "/api/users/"):
+		s.users[strings.TrimPrefix(req.Path, "/api/users/")] = true
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete && strings.HasPrefix(req.Path, "/api/users/"):
+		delete(s.users, strings.TrimPrefix(req.Path, "/api/users/"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && strings.HasPrefix(req.Path, "/api/permissions/"):
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && strings.HasPrefix(req.Path, "/api/topic-permissions/"):
+		if req.Body["exchange"] == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_request","reason":"exchange not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// takeRequests returns and clears the requests received so far
+func (s *stubManagementAPI) takeRequests() []stubRequest {
+	s.Lock()
+	defer s.Unlock()
+
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+func TestBackend_credsStubbed(t *testing.T) {
+	stub := &stubManagementAPI{users: map[string]bool{}}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      path,
+			Storage:   config.StorageView,
+			Data:      data,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	readCreds := func(role string) (*logical.Response, error) {
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Operation:   logical.ReadOperation,
+			Path:        "creds/" + role,
+			Storage:     config.StorageView,
+			DisplayName: "token",
+		})
+	}
+
+	// Tags are validated against the built-in tags without a connection
+	if resp := write("roles/web", map[string]interface{}{"tags": "management,superuser"}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error for a tag that is not built into RabbitMQ")
+	}
+
+	if resp := write("config/connection", map[string]interface{}{
+		"connection_uri":    srv.URL,
+		"username":          "admin",
+		"password":          "secret",
+		"username_template": "{{ .DisplayName }}",
+	}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if resp := write("config/connection", map[string]interface{}{
+		"connection_uri":    srv.URL,
+		"username":          "admin",
+		"password":          "secret",
+		"username_template": "{{ .DisplayName",
+	}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error for an invalid username template")
+	}
+	if resp := write("config/connection", map[string]interface{}{
+		"connection_uri":    srv.URL,
+		"username":          "admin",
+		"password":          "secret",
+		"username_template": `{{ printf "v-%s-%s" .DisplayName .RoleName }}`,
+		"allowed_tags":      "management,custom",
+	}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if requests := stub.takeRequests(); len(requests) != 2 || requests[0].Path != "/api/users/" {
+		t.Fatalf("expected the connections to be verified, got %#v", requests)
+	}
+
+	if resp := write("roles/web", map[string]interface{}{"tags": "administrator"}); resp == nil || !resp.IsError() {
+		t.Fatal("expected error for a tag that is not allowed")
+	}
+	if resp := write("roles/web", map[string]interface{}{
+		"tags":         "management, custom",
+		"vhosts":       `{"/": {"configure": ".*", "write": ".*", "read": ".*"}}`,
+		"vhost_topics": `{"/": {"amq.topic": {"write": "^orders\\.", "read": ".*"}}}`,
+	}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "roles/web",
+		Storage:   config.StorageView,
+	})
+	if err != nil || resp == nil {
+		t.Fatalf("bad: resp:%#v err:%v", resp, err)
+	}
+	expectedTopics := map[string]map[string]vhostTopicPermission{
+		"/": {
+			"amq.topic": {Write: `^orders\.`, Read: ".*"},
+		},
+	}
+	if !reflect.DeepEqual(resp.Data["vhost_topics"], expectedTopics) {
+		t.Fatalf("bad vhost_topics %#v", resp.Data["vhost_topics"])
+	}
+
+	resp, err = readCreds("web")
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("bad: resp:%#v err:%v", resp, err)
+	}
+	if resp.Data["username"] != "v-token-web" {
+		t.Fatalf("bad username %#v", resp.Data["username"])
+	}
+
+	requests := stub.takeRequests()
+	if len(requests) != 3 {
+		t.Fatalf("bad requests %#v", requests)
+	}
+	if requests[0].Path != "/api/users/v-token-web" || requests[0].Body["tags"] != "management, custom" {
+		t.Fatalf("bad user request %#v", requests[0])
+	}
+	if requests[1].Path != "/api/permissions/%2F/v-token-web" {
+		t.Fatalf("bad permissions request %#v", requests[1])
+	}
+	expected := stubRequest{
+		Method: http.MethodPut,
+		Path:   "/api/topic-permissions/%2F/v-token-web",
+		Body:   map[string]interface{}{"exchange": "amq.topic", "write": `^orders\.`, "read": ".*"},
+	}
+	if !reflect.DeepEqual(requests[2], expected) {
+		t.Fatalf("bad topic permissions request %#v", requests[2])
+	}
+
+	// Users are deleted when their topic permissions can't be set
+	if resp := write("roles/broken", map[string]interface{}{
+		"vhost_topics": `{"/": {"missing": {"write": ".*", "read": ".*"}}}`,
+	}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if _, err := readCreds("broken"); err == nil || !strings.Contains(err.Error(), "exchange not found") {
+		t.Fatalf("expected error setting topic permissions, got %v", err)
+	}
+	if stub.users["v-token-broken"] {
+		t.Fatal("expected the user to be deleted")
+	}
+
+	// Roles written before the allowed tags changed are checked again
+	if resp := write("config/connection", map[string]interface{}{
+		"connection_uri":    srv.URL,
+		"username":          "admin",
+		"password":          "secret",
+		"verify_connection": false,
+	}); resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	stub.takeRequests()
+	resp, err = readCreds("web")
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected error response for a tag that is no longer allowed, resp:%#v err:%v", resp, err)
+	}
+	if requests = stub.takeRequests(); len(requests) != 0 {
+		t.Fatalf("expected no user to be created, got requests %#v", requests)
+	}
+}
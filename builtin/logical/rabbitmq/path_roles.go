@@ -37,6 +37,10 @@ func pathRoles(b *backend) *framework.Path {
 				Type:        framework.TypeString,
 				Description: "A map of virtual hosts to permissions.",
 			},
+			"vhost_topics": &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "A nested map of virtual hosts and exchanges to topic permissions.",
+			},
 		},
 		Callbacks: map[logical.Operation]framework.OperationFunc{
 			logical.ReadOperation:   b.pathRoleRead,
@@ -115,9 +119,18 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 
 	tags := d.Get("tags").(string)
 	rawVHosts := d.Get("vhosts").(string)
+	rawVHostTopics := d.Get("vhost_topics").(string)
 
-	if tags == "" && rawVHosts == "" {
-		return logical.ErrorResponse("both tags and vhosts not specified"), nil
+	if tags == "" && rawVHosts == "" && rawVHostTopics == "" {
+		return logical.ErrorResponse("tags, vhosts and vhost_topics not specified"), nil
+	}
+
+	connConfig, err := b.connectionConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := connConfig.validateTags(tags); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
 	}
 
 	var vhosts map[string]vhostPermission
@@ -127,10 +140,18 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 		}
 	}
 
+	var vhostTopics map[string]map[string]vhostTopicPermission
+	if len(rawVHostTopics) > 0 {
+		if err := jsonutil.DecodeJSON([]byte(rawVHostTopics), &vhostTopics); err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("failed to unmarshal vhost_topics: %s", err)), nil
+		}
+	}
+
 	// Store it
 	entry, err := logical.StorageEntryJSON("role/"+name, &roleEntry{
-		Tags:   tags,
-		VHosts: vhosts,
+		Tags:        tags,
+		VHosts:      vhosts,
+		VHostTopics: vhostTopics,
 	})
 	if err != nil {
 		return nil, err
@@ -144,8 +165,9 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 
 // Role that defines the capabilities of the credentials issued against it
 type roleEntry struct {
-	Tags   string                     `json:"tags" structs:"tags" mapstructure:"tags"`
-	VHosts map[string]vhostPermission `json:"vhosts" structs:"vhosts" mapstructure:"vhosts"`
+	Tags        string                                     `json:"tags" structs:"tags" mapstructure:"tags"`
+	VHosts      map[string]vhostPermission                 `json:"vhosts" structs:"vhosts" mapstructure:"vhosts"`
+	VHostTopics map[string]map[string]vhostTopicPermission `json:"vhost_topics" structs:"vhost_topics" mapstructure:"vhost_topics"`
 }
 
 // Structure representing the permissions of a vhost
@@ -155,6 +177,12 @@ type vhostPermission struct {
 	Read      string `json:"read" structs:"read" mapstructure:"read"`
 }
 
+// Structure representing the topic permissions of an exchange
+type vhostTopicPermission struct {
+	Write string `json:"write" structs:"write" mapstructure:"write"`
+	Read  string `json:"read" structs:"read" mapstructure:"read"`
+}
+
 const pathRoleHelpSyn = `
 Manage the roles that can be created with this backend.
 `
@@ -163,7 +191,8 @@ const pathRoleHelpDesc = `
 This path lets you manage the roles that can be created with this backend.
 
 The "tags" parameter customizes the tags used to create the role.
-This is a comma separated list of strings. The "vhosts" parameter customizes
+This is a comma separated list of strings, each of which must be allowed
+by the "allowed_tags" of config/connection. The "vhosts" parameter customizes
 the virtual hosts that this user will be associated with. This is a JSON object
 passed as a string in the form:
 {
@@ -178,4 +207,16 @@ passed as a string in the form:
 		"read": ".*"
 	}
 }
+
+The "vhost_topics" parameter customizes the topic permissions of the user on
+the exchanges of virtual hosts. This is a JSON object passed as a string in
+the form:
+{
+	"vhostOne": {
+		"exchangeOne": {
+			"write": ".*",
+			"read": ".*"
+		}
+	}
+}
 `
@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	rabbithole "github.com/michaelklishin/rabbit-hole"
+)
+
+// topicPermissions is the body of a topic permissions request to the
+// RabbitMQ management API
+type topicPermissions struct {
+	Exchange string `json:"exchange"`
+	Write    string `json:"write"`
+	Read     string `json:"read"`
+}
+
+// updateTopicPermissionsIn sets the topic permissions of a user on an
+// exchange of a vhost. The vendored rabbit-hole client predates topic
+// permissions, so the management API is called directly with the client's
+// endpoint and credentials.
+func updateTopicPermissionsIn(client *rabbithole.Client, vhost, username string, permissions topicPermissions) error {
+	body, err := json.Marshal(permissions)
+	if err != nil {
+		return err
+	}
+
+	url := client.Endpoint + "/api/topic-permissions/" + rabbithole.PathEscape(vhost) + "/" + rabbithole.PathEscape(username)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Close = true
+	req.SetBasicAuth(client.Username, client.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := cleanhttp.DefaultClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		resBody, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("unexpected status code %d setting topic permissions on exchange %q: %s", res.StatusCode, permissions.Exchange, bytes.TrimSpace(resBody))
+	}
+	return nil
+}
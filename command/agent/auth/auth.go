@@ -186,6 +186,23 @@ func (ah *AuthHandler) Run(ctx context.Context, am AuthMethod) {
 				backoffOrQuit(ctx, backoff)
 				continue
 			}
+			if secret.Auth.BoundCertFingerprint != "" {
+				// The token is only usable over connections presenting the
+				// certificate it is bound to, so don't hand out a token the
+				// agent itself can't use
+				fingerprint, err := ah.client.ClientCertFingerprint()
+				if err != nil {
+					ah.logger.Error("error reading client certificate", "error", err, "backoff", backoff.Seconds())
+					backoffOrQuit(ctx, backoff)
+					continue
+				}
+				if fingerprint != secret.Auth.BoundCertFingerprint {
+					ah.logger.Error("authentication returned a token bound to a client certificate the agent does not present", "fingerprint", secret.Auth.BoundCertFingerprint, "backoff", backoff.Seconds())
+					backoffOrQuit(ctx, backoff)
+					continue
+				}
+				ah.logger.Info("token is bound to the agent client certificate", "fingerprint", fingerprint)
+			}
 			ah.logger.Info("authentication successful, sending token to sinks")
 			ah.OutputCh <- secret.Auth.ClientToken
 
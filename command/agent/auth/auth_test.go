@@ -98,3 +98,106 @@ consumption:
 		}
 	}
 }
+
+func TestAuthHandler_BoundClientCert(t *testing.T) {
+	logger := logging.NewVaultLogger(hclog.Trace)
+	coreConfig := &vault.CoreConfig{
+		Logger: logger,
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": userpass.Factory,
+		},
+	}
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+	client := cluster.Cores[0].Client
+
+	if err := client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{
+		Type: "userpass",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("auth/userpass/users/foo", map[string]interface{}{
+		"password":               "bar",
+		"token_bind_client_cert": true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ah := NewAuthHandler(&AuthHandlerConfig{
+		Logger: logger.Named("auth.handler"),
+		Client: client,
+	})
+	go ah.Run(ctx, &boundUserpassTestMethod{})
+
+	var token string
+	select {
+	case token = <-ah.OutputCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a token")
+	}
+
+	// The token is bound to the certificate the agent client presents
+	boundClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	boundClient.SetToken(token)
+	secret, err := boundClient.Auth().Token().LookupSelf()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := secret.TokenBoundCertFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := client.ClientCertFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected == "" || fingerprint != expected {
+		t.Fatalf("expected the token to be bound to %q, got %q", expected, fingerprint)
+	}
+
+	// Requests without the certificate are denied
+	config := api.DefaultConfig()
+	config.Address = client.Address()
+	if err := config.ConfigureTLS(&api.TLSConfig{
+		CACert: cluster.CACertPEMFile,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	unboundClient, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unboundClient.SetToken(token)
+	if _, err := unboundClient.Auth().Token().LookupSelf(); err == nil {
+		t.Fatal("expected the token to be rejected without the client certificate")
+	}
+}
+
+type boundUserpassTestMethod struct{}
+
+func (u *boundUserpassTestMethod) Authenticate(_ context.Context, client *api.Client) (string, map[string]interface{}, error) {
+	return "auth/userpass/login/foo", map[string]interface{}{
+		"password": "bar",
+	}, nil
+}
+
+func (u *boundUserpassTestMethod) NewCreds() chan struct{} {
+	return nil
+}
+
+func (u *boundUserpassTestMethod) CredSuccess() {
+}
+
+func (u *boundUserpassTestMethod) Shutdown() {
+}
@@ -363,6 +363,8 @@ func TestPredict_Plugins(t *testing.T) {
 				"postgresql-database-plugin",
 				"rabbitmq",
 				"radius",
+				"redis-database-plugin",
+				"sql-database-plugin",
 				"ssh",
 				"totp",
 				"transit",
@@ -417,11 +417,21 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				BaseCommand: getBaseCommand(),
 			}, nil
 		},
+		"policy explain": func() (cli.Command, error) {
+			return &PolicyExplainCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
 		"policy fmt": func() (cli.Command, error) {
 			return &PolicyFmtCommand{
 				BaseCommand: getBaseCommand(),
 			}, nil
 		},
+		"policy lint": func() (cli.Command, error) {
+			return &PolicyLintCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
 		"policy list": func() (cli.Command, error) {
 			return &PolicyListCommand{
 				BaseCommand: getBaseCommand(),
@@ -36,6 +36,14 @@ Usage: vault policy <subcommand> [options] [args]
 
       $ vault policy delete my-policy
 
+  Explain whether the local token can read "secret/foo":
+
+      $ vault policy explain secret/foo
+
+  Report likely mistakes in a policy file:
+
+      $ vault policy lint /tmp/policy.hcl
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,201 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*PolicyExplainCommand)(nil)
+var _ cli.CommandAutocomplete = (*PolicyExplainCommand)(nil)
+
+type PolicyExplainCommand struct {
+	*BaseCommand
+
+	flagToken     string
+	flagPolicies  []string
+	flagOperation string
+
+	testStdin io.Reader // for tests
+}
+
+func (c *PolicyExplainCommand) Synopsis() string {
+	return "Explains why a request is allowed or denied"
+}
+
+func (c *PolicyExplainCommand) Help() string {
+	helpText := `
+Usage: vault policy explain [options] PATH [K=V...]
+
+  Evaluates a request on PATH against the policies of a token, or against a
+  set of policies, and explains why it is allowed or denied. The request is
+  not run. The explanation lists the rules matching the path in order of
+  priority, the rule each policy contributes and the result of the parameter
+  constraints for the given K=V parameters.
+
+  Explain whether the local token can read "secret/foo":
+
+      $ vault policy explain secret/foo
+
+  Explain whether a token can update "secret/foo" with a parameter:
+
+      $ vault policy explain -token=s.abcd -operation=update secret/foo ttl=1h
+
+  Explain whether the "dev" and "ops" policies allow listing "secret/":
+
+      $ vault policy explain -policy=dev -policy=ops -operation=list secret/
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PolicyExplainCommand) Flags() *FlagSets {
+	set := c.flagSet(FlagSetHTTP | FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&StringVar{
+		Name:       "token",
+		Target:     &c.flagToken,
+		Completion: complete.PredictAnything,
+		Usage: "Token whose policies the request is evaluated against. By " +
+			"default, the locally authenticated token is used.",
+	})
+
+	f.StringSliceVar(&StringSliceVar{
+		Name:       "policy",
+		Target:     &c.flagPolicies,
+		Completion: c.PredictVaultPolicies(),
+		Usage: "Name of a policy to evaluate the request against instead of " +
+			"the policies of a token. This can be specified multiple times.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:       "operation",
+		Target:     &c.flagOperation,
+		Default:    "read",
+		Completion: complete.PredictSet("create", "read", "update", "delete", "list"),
+		Usage: "Operation of the request. Can be \"create\", \"read\", " +
+			"\"update\", \"delete\" or \"list\".",
+	})
+
+	return set
+}
+
+func (c *PolicyExplainCommand) AutocompleteArgs() complete.Predictor {
+	return c.PredictVaultFiles()
+}
+
+func (c *PolicyExplainCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *PolicyExplainCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	if len(args) < 1 {
+		c.UI.Error(fmt.Sprintf("Not enough arguments (expected 1+, got %d)", len(args)))
+		return 1
+	}
+	if c.flagToken != "" && len(c.flagPolicies) > 0 {
+		c.UI.Error("Only one of -token and -policy can be specified")
+		return 1
+	}
+
+	path := sanitizePath(args[0])
+
+	// Pull our fake stdin if needed
+	stdin := (io.Reader)(os.Stdin)
+	if c.testStdin != nil {
+		stdin = c.testStdin
+	}
+
+	parameters, err := parseArgsData(stdin, args[1:])
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to parse K=V data: %s", err))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	explanation, err := client.Sys().PolicyExplain(&api.PolicyExplainInput{
+		Token:      c.flagToken,
+		Policies:   c.flagPolicies,
+		Path:       path,
+		Operation:  c.flagOperation,
+		Parameters: parameters,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error explaining policies: %s", err))
+		return 2
+	}
+
+	if Format(c.UI) != "table" {
+		return OutputData(c.UI, explanation)
+	}
+
+	for _, warning := range explanation.Warnings {
+		c.UI.Warn(fmt.Sprintf("WARNING! %s", warning))
+	}
+
+	verdict := "denied"
+	if explanation.Allowed {
+		verdict = "allowed"
+	}
+	out := []string{
+		"Key | Value",
+		fmt.Sprintf("Path | %s", explanation.Path),
+		fmt.Sprintf("Operation | %s", explanation.Operation),
+		fmt.Sprintf("Verdict | %s", verdict),
+		fmt.Sprintf("Reason | %s", explanation.Reason),
+		fmt.Sprintf("Capabilities | %s", strings.Join(explanation.Capabilities, ", ")),
+	}
+	c.UI.Output(tableOutput(out, nil))
+
+	if len(explanation.MatchedRules) > 0 {
+		out = []string{"Rule | Type | Capabilities | Selected | Reason"}
+		for _, rule := range explanation.MatchedRules {
+			out = append(out, fmt.Sprintf("%s | %s | %s | %t | %s",
+				rule.Path, rule.Type, strings.Join(rule.Capabilities, ", "), rule.Selected, rule.Reason))
+		}
+		c.UI.Output("")
+		c.UI.Output(tableOutput(out, nil))
+	}
+
+	if len(explanation.Policies) > 0 {
+		out = []string{"Policy | Namespace | Matched Rule | Capabilities | Allowed"}
+		for _, policy := range explanation.Policies {
+			out = append(out, fmt.Sprintf("%s | %s | %s | %s | %t",
+				policy.Name, policy.Namespace, policy.MatchedRule, strings.Join(policy.Capabilities, ", "), policy.Allowed))
+		}
+		c.UI.Output("")
+		c.UI.Output(tableOutput(out, nil))
+	}
+
+	if len(explanation.Parameters) > 0 {
+		out = []string{"Parameter | Allowed | Reason"}
+		for _, parameter := range explanation.Parameters {
+			out = append(out, fmt.Sprintf("%s | %t | %s", parameter.Name, parameter.Allowed, parameter.Reason))
+		}
+		c.UI.Output("")
+		c.UI.Output(tableOutput(out, nil))
+	}
+
+	return 0
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*PolicyLintCommand)(nil)
+var _ cli.CommandAutocomplete = (*PolicyLintCommand)(nil)
+
+type PolicyLintCommand struct {
+	*BaseCommand
+
+	flagName string
+
+	testStdin io.Reader // for tests
+}
+
+func (c *PolicyLintCommand) Synopsis() string {
+	return "Reports likely mistakes in a policy"
+}
+
+func (c *PolicyLintCommand) Help() string {
+	helpText := `
+Usage: vault policy lint [options] [PATH]
+
+  Parses the policy from the contents of a local file PATH or stdin, or the
+  existing policy named by -name, and reports likely mistakes: repeated or
+  shadowed rules, overlapping rules where the rule taking priority drops
+  capabilities of the other, paths matching no mounted secrets engine or auth
+  method, the deprecated "policy" field, and globs or segment wildcards
+  granting broader access than likely intended. If PATH is "-", the policy is
+  read from stdin. The policy is not stored.
+
+  The exit code is 0 when no issue is found and 2 otherwise.
+
+  Lint the policy in "/tmp/policy.hcl" on the local disk:
+
+      $ vault policy lint /tmp/policy.hcl
+
+  Lint a policy from stdin:
+
+      $ cat my-policy.hcl | vault policy lint -
+
+  Lint the existing policy named "my-policy":
+
+      $ vault policy lint -name=my-policy
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PolicyLintCommand) Flags() *FlagSets {
+	set := c.flagSet(FlagSetHTTP | FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&StringVar{
+		Name:       "name",
+		Target:     &c.flagName,
+		Completion: c.PredictVaultPolicies(),
+		Usage:      "Name of an existing policy to lint instead of a local policy.",
+	})
+
+	return set
+}
+
+func (c *PolicyLintCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*.hcl")
+}
+
+func (c *PolicyLintCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *PolicyLintCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	switch {
+	case c.flagName != "" && len(args) > 0:
+		c.UI.Error("Only one of PATH and -name can be specified")
+		return 1
+	case c.flagName == "" && len(args) < 1:
+		c.UI.Error(fmt.Sprintf("Not enough arguments (expected 1, got %d)", len(args)))
+		return 1
+	case len(args) > 1:
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 1, got %d)", len(args)))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	var result *api.PolicyLintOutput
+	if c.flagName != "" {
+		result, err = client.Sys().LintPolicyByName(strings.TrimSpace(strings.ToLower(c.flagName)))
+	} else {
+		// Get the policy contents, either from stdin of a file
+		var reader io.Reader
+		path := strings.TrimSpace(args[0])
+		if path == "-" {
+			reader = os.Stdin
+			if c.testStdin != nil {
+				reader = c.testStdin
+			}
+		} else {
+			file, err := os.Open(path)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error opening policy file: %s", err))
+				return 2
+			}
+			defer file.Close()
+			reader = file
+		}
+
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, reader); err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading policy: %s", err))
+			return 2
+		}
+		result, err = client.Sys().LintPolicy(buf.String())
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error linting policy: %s", err))
+		return 2
+	}
+
+	code := 0
+	if len(result.Findings) > 0 {
+		code = 2
+	}
+
+	if Format(c.UI) != "table" {
+		if ret := OutputData(c.UI, result.Findings); ret != 0 {
+			return ret
+		}
+		return code
+	}
+
+	if len(result.Findings) == 0 {
+		c.UI.Output("No issues found")
+		return code
+	}
+
+	out := []string{"Line | Path | Check | Message"}
+	for _, finding := range result.Findings {
+		line := "-"
+		if finding.Line > 0 {
+			line = fmt.Sprintf("%d", finding.Line)
+		}
+		out = append(out, fmt.Sprintf("%s | %s | %s | %s", line, finding.Path, finding.Check, finding.Message))
+	}
+	c.UI.Output(tableOutput(out, nil))
+	return code
+}
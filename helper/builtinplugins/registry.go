@@ -30,6 +30,8 @@ import (
 	dbMssql "github.com/hashicorp/vault/plugins/database/mssql"
 	dbMysql "github.com/hashicorp/vault/plugins/database/mysql"
 	dbPostgres "github.com/hashicorp/vault/plugins/database/postgresql"
+	dbRedis "github.com/hashicorp/vault/plugins/database/redis"
+	dbSQL "github.com/hashicorp/vault/plugins/database/sqldb"
 
 	logicalAd "github.com/hashicorp/vault-plugin-secrets-ad/plugin"
 	logicalAlicloud "github.com/hashicorp/vault-plugin-secrets-alicloud"
@@ -96,6 +98,8 @@ func newRegistry() *registry {
 			"hana-database-plugin":          dbHana.New,
 			"influxdb-database-plugin":      dbInflux.New,
 			"elasticsearch-database-plugin": dbElastic.New,
+			"redis-database-plugin":         dbRedis.New,
+			"sql-database-plugin":           dbSQL.New,
 		},
 		logicalBackends: map[string]logical.Factory{
 			"ad":         logicalAd.Factory,
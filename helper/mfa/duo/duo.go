@@ -145,5 +145,9 @@ func duoHandler(duoConfig *DuoConfig, duoAuthClient AuthClient, request *duoAuth
 		return logical.ErrorResponse(result.Response.Status_Msg), nil
 	}
 
+	// Only a completed second factor validates MFA; users Duo lets through
+	// at preauth haven't presented one
+	request.successResp.Auth.MFAValidated = true
+
 	return request.successResp, nil
 }
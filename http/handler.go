@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"net/textproto"
@@ -487,6 +488,63 @@ func parseRequest(core *vault.Core, r *http.Request, w http.ResponseWriter, out
 	return nil, err
 }
 
+// isFormRequest reports whether the request body is form-encoded, as it is
+// for OAuth 2.0 clients of the OIDC provider
+func isFormRequest(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/x-www-form-urlencoded"
+}
+
+// parseFormRequest parses a form-encoded request body into a map. Fields with
+// a single value are stored as strings, others as string slices.
+func parseFormRequest(core *vault.Core, r *http.Request, w http.ResponseWriter) (map[string]interface{}, io.ReadCloser, error) {
+	maxRequestSize := r.Context().Value("max_request_size")
+	if maxRequestSize != nil {
+		max, ok := maxRequestSize.(int64)
+		if !ok {
+			return nil, nil, errors.New("could not parse max_request_size from request context")
+		}
+		if max > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+		}
+	}
+	var origBody io.ReadWriter
+	if core.PerfStandby() {
+		origBody = new(bytes.Buffer)
+		r.Body = ioutil.NopCloser(io.TeeReader(r.Body, origBody))
+	}
+
+	// Only the body is parsed, so that query parameters can't be confused
+	// with form fields
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, errwrap.Wrapf("failed to parse form input: {{err}}", err)
+	}
+
+	var data map[string]interface{}
+	if len(r.PostForm) > 0 {
+		data = make(map[string]interface{}, len(r.PostForm))
+		for k, v := range r.PostForm {
+			if len(v) == 1 {
+				data[k] = v[0]
+			} else {
+				data[k] = v
+			}
+		}
+	}
+
+	if origBody != nil {
+		return data, ioutil.NopCloser(origBody), nil
+	}
+	return data, nil, nil
+}
+
 // handleRequestForwarding determines whether to forward a request or not,
 // falling back on the older behavior of redirecting the client
 func handleRequestForwarding(core *vault.Core, handler http.Handler) http.Handler {
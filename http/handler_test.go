@@ -309,9 +309,10 @@ func TestSysMounts_headerAuth(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -359,9 +360,10 @@ func TestSysMounts_headerAuth(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -91,6 +91,11 @@ func buildLogicalRequest(core *vault.Core, w http.ResponseWriter, r *http.Reques
 			if path == "sys/storage/raft/snapshot" || path == "sys/storage/raft/snapshot-force" {
 				requestReader = r.Body
 				origBody = r.Body
+			} else if isFormRequest(r) {
+				data, origBody, err = parseFormRequest(core, r, w)
+				if err != nil {
+					return nil, nil, http.StatusBadRequest, err
+				}
 			} else {
 				origBody, err = parseRequest(core, r, w, &data)
 				if err == io.EOF {
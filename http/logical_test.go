@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/go-test/deep"
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	log "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/vault/helper/namespace"
@@ -259,6 +261,41 @@ func TestLogical_RawHTTP(t *testing.T) {
 	}
 }
 
+func TestLogical_FormRequest(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	form := url.Values{
+		"data":  {"bar"},
+		"multi": {"a", "b"},
+	}
+	req, err := http.NewRequest("POST", addr+"/v1/secret/foo", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(consts.AuthHeaderName, token)
+	resp, err := cleanhttp.DefaultClient().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testResponseStatus(t, resp, 204)
+
+	resp = testHttpGet(t, token, addr+"/v1/secret/foo")
+	testResponseStatus(t, resp, 200)
+	var actual map[string]interface{}
+	testResponseBody(t, resp, &actual)
+	expected := map[string]interface{}{
+		"data":  "bar",
+		"multi": []interface{}{"a", "b"},
+	}
+	if diff := deep.Equal(actual["data"], expected); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
 func TestLogical_RequestSizeLimit(t *testing.T) {
 	core, _, token := vault.TestCoreUnsealed(t)
 	ln, addr := TestServer(t, core)
@@ -69,9 +69,10 @@ func TestSysMounts(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -119,9 +120,10 @@ func TestSysMounts(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -228,9 +230,10 @@ func TestSysMount(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -290,9 +293,10 @@ func TestSysMount(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -419,9 +423,10 @@ func TestSysRemount(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -481,9 +486,10 @@ func TestSysRemount(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -578,9 +584,10 @@ func TestSysUnmount(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -628,9 +635,10 @@ func TestSysUnmount(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -819,9 +827,10 @@ func TestSysTuneMount(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -881,9 +890,10 @@ func TestSysTuneMount(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -1016,9 +1026,10 @@ func TestSysTuneMount(t *testing.T) {
 				"description": "identity store",
 				"type":        "identity",
 				"config": map[string]interface{}{
-					"default_lease_ttl": json.Number("0"),
-					"max_lease_ttl":     json.Number("0"),
-					"force_no_cache":    false,
+					"default_lease_ttl":           json.Number("0"),
+					"max_lease_ttl":               json.Number("0"),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []interface{}{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -1078,9 +1089,10 @@ func TestSysTuneMount(t *testing.T) {
 			"description": "identity store",
 			"type":        "identity",
 			"config": map[string]interface{}{
-				"default_lease_ttl": json.Number("0"),
-				"max_lease_ttl":     json.Number("0"),
-				"force_no_cache":    false,
+				"default_lease_ttl":           json.Number("0"),
+				"max_lease_ttl":               json.Number("0"),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []interface{}{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/vault"
+)
+
+func TestSysNamespaces_Routing(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+
+	config := api.DefaultConfig()
+	config.Address = addr
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken(token)
+
+	if _, err := client.Logical().Write("sys/namespaces/ns1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mount through the namespace header
+	client.SetNamespace("ns1")
+	if err := client.Sys().Mount("kv", &api.MountInput{Type: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("kv/foo", map[string]interface{}{"value": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read back through the path prefix
+	client.SetNamespace("")
+	secret, err := client.Logical().Read("ns1/kv/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == nil || secret.Data["value"] != "bar" {
+		t.Fatalf("bad: %#v", secret)
+	}
+
+	// The header and the path prefix can be combined
+	if _, err := client.Logical().Write("sys/namespaces/ns1/team", nil); err != nil {
+		t.Fatal(err)
+	}
+	client.SetNamespace("ns1")
+	secret, err = client.Logical().Read("team/sys/mounts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == nil || secret.Data["sys/"] == nil {
+		t.Fatalf("bad: %#v", secret)
+	}
+
+	// Root-only handlers ignore the namespace header
+	if _, err := client.Sys().Health(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown namespaces are not found
+	client.SetNamespace("missing")
+	resp, err := client.RawRequest(client.NewRequest("GET", "/v1/sys/mounts"))
+	if err == nil || resp == nil || resp.StatusCode != 404 {
+		t.Fatalf("expected not found for an unknown namespace, got %#v, %v", resp, err)
+	}
+}
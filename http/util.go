@@ -2,14 +2,61 @@ package http
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/vault"
 )
 
+// rootOnlyHandlerPaths are served by dedicated handlers outside of the
+// logical request flow and always act on the root namespace, so the namespace
+// header is ignored for them
+var rootOnlyHandlerPaths = map[string]bool{
+	"/v1/sys/init":                      true,
+	"/v1/sys/seal-status":               true,
+	"/v1/sys/seal":                      true,
+	"/v1/sys/step-down":                 true,
+	"/v1/sys/unseal":                    true,
+	"/v1/sys/leader":                    true,
+	"/v1/sys/health":                    true,
+	"/v1/sys/generate-root/attempt":     true,
+	"/v1/sys/generate-root/update":      true,
+	"/v1/sys/rekey/init":                true,
+	"/v1/sys/rekey/update":              true,
+	"/v1/sys/rekey/verify":              true,
+	"/v1/sys/rekey-recovery-key/init":   true,
+	"/v1/sys/rekey-recovery-key/update": true,
+	"/v1/sys/rekey-recovery-key/verify": true,
+	"/v1/sys/storage/raft/join":         true,
+}
+
 var (
 	adjustRequest = func(c *vault.Core, r *http.Request) (*http.Request, int) {
-		return r.WithContext(namespace.ContextWithNamespace(r.Context(), namespace.RootNamespace)), 0
+		if rootOnlyHandlerPaths[r.URL.Path] {
+			return r.WithContext(namespace.ContextWithNamespace(r.Context(), namespace.RootNamespace)), 0
+		}
+
+		// Combine the namespace header with the request path so that the
+		// namespace can be given either way, or split across both
+		nsHeader := namespace.Canonicalize(r.Header.Get(consts.NamespaceHeaderName))
+		path := nsHeader + strings.TrimPrefix(r.URL.Path, "/v1/")
+
+		// Namespaces can't be resolved while sealed or in standby; requests
+		// that are forwarded keep their original path and headers
+		ns, ok := c.NamespaceByPath(path)
+		if !ok {
+			return r.WithContext(namespace.ContextWithNamespace(r.Context(), namespace.RootNamespace)), 0
+		}
+		if nsHeader != "" && !strings.HasPrefix(ns.Path, nsHeader) {
+			return nil, http.StatusNotFound
+		}
+
+		if nsHeader != "" {
+			r.URL.Path = "/v1/" + path
+			r.URL.RawPath = ""
+		}
+		return r.WithContext(namespace.ContextWithNamespace(r.Context(), ns)), 0
 	}
 
 	genericWrapping = func(core *vault.Core, in http.Handler, props *vault.HandlerProperties) http.Handler {
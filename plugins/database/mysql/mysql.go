@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/vault/sdk/database/helper/connutil"
 	"github.com/hashicorp/vault/sdk/database/helper/credsutil"
 	"github.com/hashicorp/vault/sdk/database/helper/dbutil"
+	"github.com/hashicorp/vault/sdk/helper/dbtxn"
 	"github.com/hashicorp/vault/sdk/helper/strutil"
 )
 
@@ -157,13 +158,13 @@ func (m *MySQL) CreateUser(ctx context.Context, statements dbplugin.Statements,
 			if len(query) == 0 {
 				continue
 			}
-			query = dbutil.QueryHelper(query, map[string]string{
+			parsedQuery := dbutil.QueryHelper(query, map[string]string{
 				"name":       username,
 				"password":   password,
 				"expiration": expirationStr,
 			})
 
-			stmt, err := tx.PrepareContext(ctx, query)
+			stmt, err := tx.PrepareContext(ctx, parsedQuery)
 			if err != nil {
 				// If the error code we get back is Error 1295: This command is not
 				// supported in the prepared statement protocol yet, we will execute
@@ -172,18 +173,18 @@ func (m *MySQL) CreateUser(ctx context.Context, statements dbplugin.Statements,
 				// prepare supported commands. If there is no error when running we
 				// will continue to the next statement.
 				if e, ok := err.(*stdmysql.MySQLError); ok && e.Number == 1295 {
-					_, err = tx.ExecContext(ctx, query)
+					_, err = tx.ExecContext(ctx, parsedQuery)
 					if err != nil {
-						return "", "", err
+						return "", "", dbtxn.StatementError(query, err)
 					}
 					continue
 				}
 
-				return "", "", err
+				return "", "", dbtxn.StatementError(query, err)
 			}
 			if _, err := stmt.ExecContext(ctx); err != nil {
 				stmt.Close()
-				return "", "", err
+				return "", "", dbtxn.StatementError(query, err)
 			}
 			stmt.Close()
 		}
@@ -238,10 +239,10 @@ func (m *MySQL) RevokeUser(ctx context.Context, statements dbplugin.Statements,
 			// This is not a prepared statement because not all commands are supported
 			// 1295: This command is not supported in the prepared statement protocol yet
 			// Reference https://mariadb.com/kb/en/mariadb/prepare-statement/
-			query = strings.Replace(query, "{{name}}", username, -1)
-			_, err = tx.ExecContext(ctx, query)
+			parsedQuery := strings.Replace(query, "{{name}}", username, -1)
+			_, err = tx.ExecContext(ctx, parsedQuery)
 			if err != nil {
-				return err
+				return dbtxn.StatementError(query, err)
 			}
 		}
 	}
@@ -295,11 +296,11 @@ func (m *MySQL) RotateRootCredentials(ctx context.Context, statements []string)
 			// This is not a prepared statement because not all commands are supported
 			// 1295: This command is not supported in the prepared statement protocol yet
 			// Reference https://mariadb.com/kb/en/mariadb/prepare-statement/
-			query = strings.Replace(query, "{{username}}", m.Username, -1)
-			query = strings.Replace(query, "{{password}}", password, -1)
+			parsedQuery := strings.Replace(query, "{{username}}", m.Username, -1)
+			parsedQuery = strings.Replace(parsedQuery, "{{password}}", password, -1)
 
-			if _, err := tx.ExecContext(ctx, query); err != nil {
-				return nil, err
+			if _, err := tx.ExecContext(ctx, parsedQuery); err != nil {
+				return nil, dbtxn.StatementError(query, err)
 			}
 		}
 	}
@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxBulkLen bounds the length of the bulk strings accepted from the
+	// server. Replies to the commands of the plugin are far smaller.
+	maxBulkLen = 1024 * 1024
+
+	// maxArrayLen bounds the number of elements of the arrays accepted from
+	// the server
+	maxArrayLen = 1024 * 1024
+)
+
+// redisError is an error reply of the Redis server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// client is a minimal client of the Redis serialization protocol (RESP),
+// which is all the plugin needs to manage ACL users
+type client struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+}
+
+func newClient(conn net.Conn, timeout time.Duration) *client {
+	return &client{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: timeout,
+	}
+}
+
+// do sends a command and returns its reply, which is a string, an int64, a
+// []interface{} of replies or nil. Error replies are returned as a
+// redisError.
+func (c *client) do(args ...string) (interface{}, error) {
+	if c.timeout > 0 {
+		if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		buf = append(buf, fmt.Sprintf("$%d\r\n", len(arg))...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, err
+	}
+
+	return c.readReply()
+}
+
+func (c *client) readReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+
+	case '-':
+		return nil, redisError(line[1:])
+
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk string length %q", line[1:])
+		}
+		switch {
+		case n == -1:
+			return nil, nil
+		case n < -1 || n > maxBulkLen:
+			return nil, fmt.Errorf("invalid bulk string length %d", n)
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid array length %q", line[1:])
+		}
+		switch {
+		case n == -1:
+			return nil, nil
+		case n < -1 || n > maxArrayLen:
+			return nil, fmt.Errorf("invalid array length %d", n)
+		}
+		reply := make([]interface{}, n)
+		for i := range reply {
+			// Error replies nested in arrays are values, not failures
+			v, err := c.readReply()
+			if replyErr, ok := err.(redisError); ok {
+				v = replyErr
+			} else if err != nil {
+				return nil, err
+			}
+			reply[i] = v
+		}
+		return reply, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected reply %q", line)
+	}
+}
+
+func (c *client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("malformed reply %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+func (c *client) Close() error {
+	return c.conn.Close()
+}
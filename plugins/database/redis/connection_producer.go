@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/sdk/database/helper/connutil"
+	"github.com/hashicorp/vault/sdk/helper/parseutil"
+	"github.com/mitchellh/mapstructure"
+)
+
+// redisConnectionProducer manages the connection of the plugin to Redis. It
+// authenticates as an ACL user, so Redis 6.0 or later is required.
+type redisConnectionProducer struct {
+	Host              string      `json:"host" structs:"host" mapstructure:"host"`
+	Port              int         `json:"port" structs:"port" mapstructure:"port"`
+	Username          string      `json:"username" structs:"username" mapstructure:"username"`
+	Password          string      `json:"password" structs:"password" mapstructure:"password"`
+	TLS               bool        `json:"tls" structs:"tls" mapstructure:"tls"`
+	InsecureTLS       bool        `json:"insecure_tls" structs:"insecure_tls" mapstructure:"insecure_tls"`
+	CACert            string      `json:"ca_cert" structs:"ca_cert" mapstructure:"ca_cert"`
+	ConnectTimeoutRaw interface{} `json:"connect_timeout" structs:"connect_timeout" mapstructure:"connect_timeout"`
+
+	connectTimeout time.Duration
+	tlsConfig      *tls.Config
+
+	Initialized bool
+	client      *client
+	sync.Mutex
+}
+
+func (c *redisConnectionProducer) init(ctx context.Context, conf map[string]interface{}, verifyConnection bool) error {
+	c.Lock()
+	defer c.Unlock()
+
+	err := mapstructure.WeakDecode(conf, c)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(c.Host) == 0:
+		return errors.New("host cannot be empty")
+	case len(c.Username) == 0:
+		return errors.New("username cannot be empty")
+	case len(c.Password) == 0:
+		return errors.New("password cannot be empty")
+	}
+	if c.Port == 0 {
+		c.Port = 6379
+	}
+
+	if c.ConnectTimeoutRaw == nil {
+		c.ConnectTimeoutRaw = "5s"
+	}
+	c.connectTimeout, err = parseutil.ParseDurationSecond(c.ConnectTimeoutRaw)
+	if err != nil {
+		return errwrap.Wrapf("invalid connect_timeout: {{err}}", err)
+	}
+
+	c.tlsConfig = nil
+	if c.TLS {
+		c.tlsConfig = &tls.Config{
+			ServerName:         c.Host,
+			InsecureSkipVerify: c.InsecureTLS,
+		}
+		if c.CACert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(c.CACert)) {
+				return errors.New("unable to parse ca_cert")
+			}
+			c.tlsConfig.RootCAs = pool
+		}
+	}
+
+	// Drop the connection of a previous configuration
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+
+	// Set initialized to true at this point since all fields are set,
+	// and the connection can be established at a later time.
+	c.Initialized = true
+
+	if verifyConnection {
+		if _, err := c.connection(ctx); err != nil {
+			return errwrap.Wrapf("error verifying connection: {{err}}", err)
+		}
+	}
+
+	return nil
+}
+
+// connection returns an authenticated connection, reusing the existing one
+// if it is still alive. The caller must hold the lock.
+func (c *redisConnectionProducer) connection(ctx context.Context) (*client, error) {
+	if !c.Initialized {
+		return nil, connutil.ErrNotInitialized
+	}
+
+	if c.client != nil {
+		if _, err := c.client.do("PING"); err == nil {
+			return c.client, nil
+		}
+		// If the ping was unsuccessful, close it and ignore errors as we'll be
+		// reestablishing anyways
+		c.client.Close()
+		c.client = nil
+	}
+
+	dialer := &net.Dialer{Timeout: c.connectTimeout}
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+
+	var conn net.Conn
+	var err error
+	if c.tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, c.tlsConfig)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cli := newClient(conn, c.connectTimeout)
+	if _, err := cli.do("AUTH", c.Username, c.Password); err != nil {
+		cli.Close()
+		return nil, errwrap.Wrapf(fmt.Sprintf("error authenticating as %q: {{err}}", c.Username), err)
+	}
+
+	c.client = cli
+	return cli, nil
+}
+
+// Close attempts to close the connection
+func (c *redisConnectionProducer) Close() error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.client != nil {
+		c.client.Close()
+	}
+	c.client = nil
+
+	return nil
+}
+
+func (c *redisConnectionProducer) secretValues() map[string]interface{} {
+	return map[string]interface{}{
+		c.Password: "[password]",
+	}
+}
@@ -0,0 +1,284 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/api"
+	v4 "github.com/hashicorp/vault/sdk/database/dbplugin"
+	dbplugin "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/database/helper/credsutil"
+)
+
+const redisTypeName = "redis"
+
+// defaultACLRules are the ACL rules of dynamic users when the role has no
+// creation statements: read-only access to all keys
+var defaultACLRules = []string{"~*", "+@read"}
+
+// errNoACLFile is the start of the error ACL SAVE returns when the server
+// keeps its users in its configuration file rather than in an ACL file
+const errNoACLFile = "ERR This Redis instance is not configured to use an ACL file"
+
+var _ dbplugin.Database = &Redis{}
+
+// Redis is an implementation of Database interface that manages ACL users of
+// Redis 6.0 or later
+type Redis struct {
+	*redisConnectionProducer
+	credsProducer *credsutil.SQLCredentialsProducer
+}
+
+// New returns a new Redis instance
+func New() (interface{}, error) {
+	db := new()
+	dbType := dbplugin.NewDatabaseErrorSanitizerMiddleware(db, db.secretValues)
+
+	return dbType, nil
+}
+
+func new() *Redis {
+	return &Redis{
+		redisConnectionProducer: &redisConnectionProducer{},
+		credsProducer: &credsutil.SQLCredentialsProducer{
+			DisplayNameLen: 15,
+			RoleNameLen:    15,
+			UsernameLen:    100,
+			Separator:      "_",
+		},
+	}
+}
+
+// Run instantiates a Redis object, and runs the RPC server for the plugin
+func Run(apiTLSConfig *api.TLSConfig) error {
+	dbType, err := New()
+	if err != nil {
+		return err
+	}
+
+	dbplugin.Serve(dbType.(dbplugin.Database), api.VaultPluginTLSProvider(apiTLSConfig))
+
+	return nil
+}
+
+// Type returns the TypeName for this backend
+func (r *Redis) Type() (string, error) {
+	return redisTypeName, nil
+}
+
+// Initialize configures the connection to Redis. When verifying the
+// connection it reports the version of the server.
+func (r *Redis) Initialize(ctx context.Context, req dbplugin.InitializeRequest) (dbplugin.InitializeResponse, error) {
+	if err := r.init(ctx, req.Config, req.VerifyConnection); err != nil {
+		return dbplugin.InitializeResponse{}, err
+	}
+
+	resp := dbplugin.InitializeResponse{Config: req.Config}
+	if req.VerifyConnection {
+		version, err := r.serverVersion(ctx)
+		if err != nil {
+			return dbplugin.InitializeResponse{}, err
+		}
+		resp.ServerVersion = version
+	}
+	return resp, nil
+}
+
+// serverVersion returns the redis_version of the server info
+func (r *Redis) serverVersion(ctx context.Context) (string, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	cli, err := r.connection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := cli.do("INFO", "server")
+	if err != nil {
+		return "", errwrap.Wrapf("error reading server info: {{err}}", err)
+	}
+	infoStr, _ := info.(string)
+	for _, line := range strings.Split(infoStr, "\n") {
+		if strings.HasPrefix(line, "redis_version:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "redis_version:")), nil
+		}
+	}
+	return "", nil
+}
+
+// NewUser creates an ACL user with the ACL rules of the creation statements.
+// Each statement is a JSON array of rules or a space separated list of
+// rules, e.g. `["~cache:*", "+get"]` or `~cache:* +get`.
+func (r *Redis) NewUser(ctx context.Context, req dbplugin.NewUserRequest) (dbplugin.NewUserResponse, error) {
+	if req.CredentialType != dbplugin.CredentialTypePassword {
+		return dbplugin.NewUserResponse{}, fmt.Errorf("unsupported credential type %q", req.CredentialType)
+	}
+
+	rules, err := parseACLRules(req.Statements.Commands)
+	if err != nil {
+		return dbplugin.NewUserResponse{}, err
+	}
+	if len(rules) == 0 {
+		rules = defaultACLRules
+	}
+
+	username, err := r.credsProducer.GenerateUsername(v4.UsernameConfig{
+		DisplayName: req.UsernameConfig.DisplayName,
+		RoleName:    req.UsernameConfig.RoleName,
+		Username:    req.UsernameConfig.Username,
+	})
+	if err != nil {
+		return dbplugin.NewUserResponse{}, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	cli, err := r.connection(ctx)
+	if err != nil {
+		return dbplugin.NewUserResponse{}, err
+	}
+
+	// Never take over an existing user
+	existing, err := cli.do("ACL", "GETUSER", username)
+	if err != nil {
+		return dbplugin.NewUserResponse{}, err
+	}
+	if existing != nil {
+		return dbplugin.NewUserResponse{}, fmt.Errorf("user %q already exists", username)
+	}
+
+	args := append([]string{"ACL", "SETUSER", username, "reset", "on", ">" + req.Password}, rules...)
+	if _, err := cli.do(args...); err != nil {
+		return dbplugin.NewUserResponse{}, errwrap.Wrapf("error creating user: {{err}}", err)
+	}
+
+	// A user that can't be saved would be lost on restart, so it is not
+	// handed out
+	if err := saveACL(cli); err != nil {
+		cli.do("ACL", "DELUSER", username)
+		return dbplugin.NewUserResponse{}, err
+	}
+
+	return dbplugin.NewUserResponse{Username: username}, nil
+}
+
+// UpdateUser changes the password of an existing ACL user, applying the ACL
+// rules of the rotation statements if any. ACL users do not expire, so
+// changing the expiration is a no-op.
+func (r *Redis) UpdateUser(ctx context.Context, req dbplugin.UpdateUserRequest) (dbplugin.UpdateUserResponse, error) {
+	if req.PublicKey != nil {
+		return dbplugin.UpdateUserResponse{}, errors.New("public keys are not supported")
+	}
+	if req.Password == nil {
+		return dbplugin.UpdateUserResponse{}, nil
+	}
+	if req.Username == "" {
+		return dbplugin.UpdateUserResponse{}, errors.New("missing username")
+	}
+
+	rules, err := parseACLRules(req.Password.Statements.Commands)
+	if err != nil {
+		return dbplugin.UpdateUserResponse{}, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	cli, err := r.connection(ctx)
+	if err != nil {
+		return dbplugin.UpdateUserResponse{}, err
+	}
+
+	// ACL SETUSER creates missing users, which rotation must not do
+	existing, err := cli.do("ACL", "GETUSER", req.Username)
+	if err != nil {
+		return dbplugin.UpdateUserResponse{}, err
+	}
+	if existing == nil {
+		return dbplugin.UpdateUserResponse{}, fmt.Errorf("user %q does not exist", req.Username)
+	}
+
+	// The password can't be changed back once changed, so make sure the ACL
+	// file can be saved first rather than fail with a new password Vault
+	// doesn't know about, or one a restart would undo
+	if err := saveACL(cli); err != nil {
+		return dbplugin.UpdateUserResponse{}, err
+	}
+
+	args := append([]string{"ACL", "SETUSER", req.Username, "resetpass", ">" + req.Password.NewPassword}, rules...)
+	if _, err := cli.do(args...); err != nil {
+		return dbplugin.UpdateUserResponse{}, errwrap.Wrapf("error changing the password: {{err}}", err)
+	}
+	if err := saveACL(cli); err != nil {
+		return dbplugin.UpdateUserResponse{}, err
+	}
+
+	return dbplugin.UpdateUserResponse{}, nil
+}
+
+// DeleteUser deletes an ACL user. Deleting a user that does not exist is
+// not an error.
+func (r *Redis) DeleteUser(ctx context.Context, req dbplugin.DeleteUserRequest) (dbplugin.DeleteUserResponse, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	cli, err := r.connection(ctx)
+	if err != nil {
+		return dbplugin.DeleteUserResponse{}, err
+	}
+
+	if _, err := cli.do("ACL", "DELUSER", req.Username); err != nil {
+		return dbplugin.DeleteUserResponse{}, errwrap.Wrapf("error deleting user: {{err}}", err)
+	}
+	if err := saveACL(cli); err != nil {
+		return dbplugin.DeleteUserResponse{}, err
+	}
+
+	return dbplugin.DeleteUserResponse{}, nil
+}
+
+// saveACL saves the ACL users of the server to its ACL file, so that they
+// survive a restart. Servers without an ACL file keep the users in memory
+// only, unless they are written to the configuration file by an operator.
+func saveACL(cli *client) error {
+	_, err := cli.do("ACL", "SAVE")
+	if err != nil && !strings.HasPrefix(err.Error(), errNoACLFile) {
+		return errwrap.Wrapf("error saving the ACL file: {{err}}", err)
+	}
+	return nil
+}
+
+// parseACLRules returns the ACL rules of statements, each of which is either
+// a JSON array of rules or a space separated list of rules
+func parseACLRules(statements []string) ([]string, error) {
+	var rules []string
+	for _, stmt := range statements {
+		stmt = strings.TrimSpace(stmt)
+		if !strings.HasPrefix(stmt, "[") {
+			rules = append(rules, strings.Fields(stmt)...)
+			continue
+		}
+
+		var parsed []string
+		if err := json.Unmarshal([]byte(stmt), &parsed); err != nil {
+			return nil, errwrap.Wrapf("error parsing ACL rules: {{err}}", err)
+		}
+		rules = append(rules, parsed...)
+	}
+
+	// Passwords are managed by Vault
+	for _, rule := range rules {
+		switch {
+		case strings.HasPrefix(rule, ">"), strings.HasPrefix(rule, "#"), rule == "nopass", rule == "resetpass", rule == "reset":
+			return nil, fmt.Errorf("ACL rule %q is not allowed, passwords are managed by Vault", rule)
+		}
+	}
+
+	return rules, nil
+}
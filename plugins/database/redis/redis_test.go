@@ -0,0 +1,450 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	dbplugin "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+)
+
+type fakeUser struct {
+	password string
+	rules    []string
+}
+
+// fakeRedis serves the subset of the Redis ACL commands the plugin uses
+type fakeRedis struct {
+	sync.Mutex
+	listener net.Listener
+	users    map[string]*fakeUser
+
+	// aclFile enables ACL SAVE, which copies the users to saved unless
+	// saveErr is set
+	aclFile bool
+	saveErr string
+	saved   map[string]fakeUser
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{
+		listener: l,
+		users: map[string]*fakeUser{
+			"vault": {password: "root-password"},
+		},
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) config() map[string]interface{} {
+	host, port, _ := net.SplitHostPort(f.listener.Addr().String())
+	return map[string]interface{}{
+		"host":     host,
+		"port":     port,
+		"username": "vault",
+		"password": "root-password",
+	}
+}
+
+func (f *fakeRedis) user(name string) *fakeUser {
+	f.Lock()
+	defer f.Unlock()
+	return f.users[name]
+}
+
+// savedUser returns the user as last saved to the ACL file, or nil
+func (f *fakeRedis) savedUser(name string) *fakeUser {
+	f.Lock()
+	defer f.Unlock()
+	u, ok := f.saved[name]
+	if !ok {
+		return nil
+	}
+	return &u
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authenticated := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		reply := f.handle(args, &authenticated)
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) handle(args []string, authenticated *bool) string {
+	f.Lock()
+	defer f.Unlock()
+
+	cmd := strings.ToUpper(args[0])
+	if cmd == "AUTH" {
+		if u, ok := f.users[args[1]]; ok && u.password == args[2] {
+			*authenticated = true
+			return "+OK\r\n"
+		}
+		return "-WRONGPASS invalid username-password pair\r\n"
+	}
+	if !*authenticated {
+		return "-NOAUTH Authentication required.\r\n"
+	}
+
+	switch {
+	case cmd == "PING":
+		return "+PONG\r\n"
+	case cmd == "INFO":
+		info := "# Server\r\nredis_version:6.0.5\r\nredis_mode:standalone\r\n"
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(info), info)
+	case cmd == "ACL" && strings.ToUpper(args[1]) == "GETUSER":
+		u, ok := f.users[args[2]]
+		if !ok {
+			return "*-1\r\n"
+		}
+		return fmt.Sprintf("*2\r\n$5\r\nrules\r\n$%d\r\n%s\r\n", len(strings.Join(u.rules, " ")), strings.Join(u.rules, " "))
+	case cmd == "ACL" && strings.ToUpper(args[1]) == "SETUSER":
+		u, ok := f.users[args[2]]
+		if !ok {
+			u = &fakeUser{}
+			f.users[args[2]] = u
+		}
+		for _, rule := range args[3:] {
+			switch {
+			case rule == "reset":
+				u.rules = nil
+			case rule == "resetpass":
+				u.password = ""
+			case strings.HasPrefix(rule, ">"):
+				u.password = rule[1:]
+			default:
+				u.rules = append(u.rules, rule)
+			}
+		}
+		return "+OK\r\n"
+	case cmd == "ACL" && strings.ToUpper(args[1]) == "DELUSER":
+		if _, ok := f.users[args[2]]; !ok {
+			return ":0\r\n"
+		}
+		delete(f.users, args[2])
+		return ":1\r\n"
+	case cmd == "ACL" && strings.ToUpper(args[1]) == "SAVE":
+		switch {
+		case !f.aclFile:
+			return "-ERR This Redis instance is not configured to use an ACL file. You may want to specify users via the ACL SETUSER command and then issue a CONFIG REWRITE (assuming you have a Redis configuration file set) in order to store users in the Redis configuration.\r\n"
+		case f.saveErr != "":
+			return "-ERR " + f.saveErr + "\r\n"
+		}
+		f.saved = make(map[string]fakeUser, len(f.users))
+		for name, u := range f.users {
+			f.saved[name] = fakeUser{password: u.password, rules: append([]string(nil), u.rules...)}
+		}
+		return "+OK\r\n"
+	}
+	return "-ERR unknown command\r\n"
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedis_Initialize(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.listener.Close()
+
+	db := new()
+	defer db.Close()
+	resp, err := db.Initialize(context.Background(), dbplugin.InitializeRequest{
+		Config:           f.config(),
+		VerifyConnection: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ServerVersion != "6.0.5" {
+		t.Fatalf("bad server version %q", resp.ServerVersion)
+	}
+
+	config := f.config()
+	config["password"] = "wrong"
+	sanitized, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = sanitized.(dbplugin.Database).Initialize(context.Background(), dbplugin.InitializeRequest{
+		Config:           config,
+		VerifyConnection: true,
+	})
+	if err == nil {
+		t.Fatal("expected error authenticating with a wrong password")
+	}
+	if strings.Contains(err.Error(), "wrong") {
+		t.Fatalf("error contains the password: %s", err)
+	}
+}
+
+func TestRedis_users(t *testing.T) {
+	f := newFakeRedis(t)
+	f.aclFile = true
+	defer f.listener.Close()
+	ctx := context.Background()
+
+	db := new()
+	defer db.Close()
+	if _, err := db.Initialize(ctx, dbplugin.InitializeRequest{Config: f.config()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dynamic users get the default rules unless the role has statements
+	resp, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{DisplayName: "token", RoleName: "cache"},
+		Password:       "user-password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.Username, "v_token_cache_") {
+		t.Fatalf("bad username %q", resp.Username)
+	}
+	user := f.user(resp.Username)
+	if user == nil || user.password != "user-password" {
+		t.Fatalf("bad user %#v", user)
+	}
+	if !reflect.DeepEqual(user.rules, []string{"on", "~*", "+@read"}) {
+		t.Fatalf("bad rules %v", user.rules)
+	}
+	if saved := f.savedUser(resp.Username); saved == nil || !reflect.DeepEqual(saved, user) {
+		t.Fatalf("expected the user to be saved, got %#v", saved)
+	}
+
+	resp, err = db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{Username: "templated"},
+		Password:       "user-password",
+		Statements:     dbplugin.Statements{Commands: []string{`["~cache:*", "+get"]`, "+set"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Username != "templated" {
+		t.Fatalf("expected the templated username, got %q", resp.Username)
+	}
+	if rules := f.user("templated").rules; !reflect.DeepEqual(rules, []string{"on", "~cache:*", "+get", "+set"}) {
+		t.Fatalf("bad rules %v", rules)
+	}
+	if _, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{Username: "templated"},
+		Password:       "other-password",
+	}); err == nil {
+		t.Fatal("expected error creating an existing user")
+	}
+	if _, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		Password:   "user-password",
+		Statements: dbplugin.Statements{Commands: []string{"+@all nopass"}},
+	}); err == nil {
+		t.Fatal("expected error for rules changing the password")
+	}
+	if _, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		CredentialType: dbplugin.CredentialTypeRSAPrivateKey,
+	}); err == nil {
+		t.Fatal("expected error for RSA key credentials")
+	}
+
+	// Static account rotation
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: "templated",
+		Password: &dbplugin.ChangePassword{NewPassword: "rotated"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if password := f.user("templated").password; password != "rotated" {
+		t.Fatalf("expected rotated password, got %q", password)
+	}
+	if saved := f.savedUser("templated"); saved == nil || saved.password != "rotated" {
+		t.Fatalf("expected the rotated password to be saved, got %#v", saved)
+	}
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: "missing",
+		Password: &dbplugin.ChangePassword{NewPassword: "rotated"},
+	}); err == nil {
+		t.Fatal("expected error rotating a missing user")
+	}
+
+	// Revocation
+	if _, err := db.DeleteUser(ctx, dbplugin.DeleteUserRequest{Username: "templated"}); err != nil {
+		t.Fatal(err)
+	}
+	if f.user("templated") != nil {
+		t.Fatal("expected user to be deleted")
+	}
+	if f.savedUser("templated") != nil {
+		t.Fatal("expected the deletion to be saved")
+	}
+	if _, err := db.DeleteUser(ctx, dbplugin.DeleteUserRequest{Username: "templated"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedis_rotateRoot(t *testing.T) {
+	f := newFakeRedis(t)
+	f.aclFile = true
+	defer f.listener.Close()
+	ctx := context.Background()
+
+	db := new()
+	if _, err := db.Initialize(ctx, dbplugin.InitializeRequest{Config: f.config(), VerifyConnection: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: "vault",
+		Password: &dbplugin.ChangePassword{NewPassword: "new-root-password"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if saved := f.savedUser("vault"); saved == nil || saved.password != "new-root-password" {
+		t.Fatalf("expected the new root password to be saved, got %#v", saved)
+	}
+
+	// Vault reinitializes the connection with the new password
+	config := f.config()
+	config["password"] = "new-root-password"
+	db = new()
+	defer db.Close()
+	if _, err := db.Initialize(ctx, dbplugin.InitializeRequest{Config: config, VerifyConnection: true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedis_saveACL(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.listener.Close()
+	ctx := context.Background()
+
+	db := new()
+	defer db.Close()
+	if _, err := db.Initialize(ctx, dbplugin.InitializeRequest{Config: f.config()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Servers without an ACL file keep their users in memory
+	if _, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{Username: "memory"},
+		Password:       "user-password",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: "memory",
+		Password: &dbplugin.ChangePassword{NewPassword: "rotated"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DeleteUser(ctx, dbplugin.DeleteUserRequest{Username: "memory"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes that can't be saved fail without being left behind
+	f.Lock()
+	f.aclFile = true
+	f.saveErr = "There was an error trying to save the ACLs"
+	f.Unlock()
+	if _, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{Username: "unsaved"},
+		Password:       "user-password",
+	}); err == nil || !strings.Contains(err.Error(), "error saving the ACL file") {
+		t.Fatalf("expected error saving the user, got %v", err)
+	}
+	if f.user("unsaved") != nil {
+		t.Fatal("expected the unsaved user to be deleted")
+	}
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: "vault",
+		Password: &dbplugin.ChangePassword{NewPassword: "new-root-password"},
+	}); err == nil {
+		t.Fatal("expected error rotating the root password")
+	}
+	if password := f.user("vault").password; password != "root-password" {
+		t.Fatalf("expected the root password to be unchanged, got %q", password)
+	}
+}
+
+func TestClient_readReply(t *testing.T) {
+	cases := map[string]struct {
+		reply    string
+		expected interface{}
+		err      bool
+	}{
+		"bulk string":            {reply: "$3\r\nfoo\r\n", expected: "foo"},
+		"nil bulk string":        {reply: "$-1\r\n", expected: nil},
+		"negative bulk length":   {reply: "$-2\r\n", err: true},
+		"oversized bulk length":  {reply: fmt.Sprintf("$%d\r\n", maxBulkLen+1), err: true},
+		"array":                  {reply: "*2\r\n+OK\r\n:1\r\n", expected: []interface{}{"OK", int64(1)}},
+		"nil array":              {reply: "*-1\r\n", expected: nil},
+		"negative array length":  {reply: "*-5\r\n", err: true},
+		"oversized array length": {reply: fmt.Sprintf("*%d\r\n", maxArrayLen+1), err: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &client{reader: bufio.NewReader(strings.NewReader(tc.reply))}
+			reply, err := c.readReply()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %#v", reply)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(reply, tc.expected) {
+				t.Fatalf("bad: %#v", reply)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package sqldb
+
+// The drivers available to connections. Further drivers, such as the
+// Snowflake driver, can be added to a custom build of the plugin with a blank
+// import of their package.
+import (
+	_ "github.com/SAP/go-hdb/driver"
+	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
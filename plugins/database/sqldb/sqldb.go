@@ -0,0 +1,284 @@
+// Package sqldb is a database plugin for databases without a dedicated
+// plugin. It connects through any database/sql driver compiled into the
+// plugin binary and runs the statements of the role verbatim, so every
+// statement must be provided by the role or the connection.
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	v4 "github.com/hashicorp/vault/sdk/database/dbplugin"
+	dbplugin "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	"github.com/hashicorp/vault/sdk/database/helper/connutil"
+	"github.com/hashicorp/vault/sdk/database/helper/credsutil"
+	"github.com/hashicorp/vault/sdk/helper/dbtxn"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	sqlTypeName      = "sql"
+	expirationFormat = "2006-01-02 15:04:05-0700"
+)
+
+var _ dbplugin.Database = &SQL{}
+
+// SQL is an implementation of Database interface for any database/sql driver
+type SQL struct {
+	*connutil.SQLConnectionProducer
+	credsProducer *credsutil.SQLCredentialsProducer
+}
+
+// New implements builtinplugins.BuiltinFactory
+func New() (interface{}, error) {
+	db := new()
+	// Wrap the plugin with middleware to sanitize errors
+	dbType := dbplugin.NewDatabaseErrorSanitizerMiddleware(db, db.SecretValues)
+	return dbType, nil
+}
+
+func new() *SQL {
+	return &SQL{
+		SQLConnectionProducer: &connutil.SQLConnectionProducer{},
+		credsProducer: &credsutil.SQLCredentialsProducer{
+			DisplayNameLen: 8,
+			RoleNameLen:    8,
+			UsernameLen:    63,
+			Separator:      "_",
+		},
+	}
+}
+
+// Run instantiates a SQL object, and runs the RPC server for the plugin
+func Run(apiTLSConfig *api.TLSConfig) error {
+	dbType, err := New()
+	if err != nil {
+		return err
+	}
+
+	dbplugin.Serve(dbType.(dbplugin.Database), api.VaultPluginTLSProvider(apiTLSConfig))
+
+	return nil
+}
+
+// Type returns the TypeName for this backend
+func (s *SQL) Type() (string, error) {
+	return sqlTypeName, nil
+}
+
+// Initialize configures the connection. The driver_name field selects the
+// database/sql driver the connection_url is opened with.
+func (s *SQL) Initialize(ctx context.Context, req dbplugin.InitializeRequest) (dbplugin.InitializeResponse, error) {
+	var config struct {
+		DriverName string `mapstructure:"driver_name"`
+	}
+	if err := mapstructure.WeakDecode(req.Config, &config); err != nil {
+		return dbplugin.InitializeResponse{}, err
+	}
+	if config.DriverName == "" {
+		return dbplugin.InitializeResponse{}, errors.New("driver_name cannot be empty")
+	}
+	if !strutil.StrListContains(sql.Drivers(), config.DriverName) {
+		return dbplugin.InitializeResponse{}, fmt.Errorf("driver %q is not compiled into the plugin, available drivers: %s", config.DriverName, strings.Join(sql.Drivers(), ", "))
+	}
+
+	s.Lock()
+	s.SQLConnectionProducer.Type = config.DriverName
+	s.Unlock()
+
+	if _, err := s.Init(ctx, req.Config, req.VerifyConnection); err != nil {
+		return dbplugin.InitializeResponse{}, err
+	}
+	return dbplugin.InitializeResponse{Config: req.Config}, nil
+}
+
+func (s *SQL) getConnection(ctx context.Context) (*sql.DB, error) {
+	db, err := s.Connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.(*sql.DB), nil
+}
+
+// NewUser runs the creation statements, which can use the {{name}},
+// {{password}}, {{public_key}} and {{expiration}} template variables. If
+// they fail, the rollback statements are run.
+func (s *SQL) NewUser(ctx context.Context, req dbplugin.NewUserRequest) (dbplugin.NewUserResponse, error) {
+	if len(req.Statements.Commands) == 0 {
+		return dbplugin.NewUserResponse{}, errors.New("creation statements are required")
+	}
+
+	params := map[string]string{
+		"expiration": req.Expiration.Format(expirationFormat),
+	}
+	switch req.CredentialType {
+	case dbplugin.CredentialTypePassword:
+		params["password"] = req.Password
+	case dbplugin.CredentialTypeRSAPrivateKey:
+		publicKey, err := encodePublicKey(req.PublicKey)
+		if err != nil {
+			return dbplugin.NewUserResponse{}, err
+		}
+		params["public_key"] = publicKey
+	default:
+		return dbplugin.NewUserResponse{}, fmt.Errorf("unsupported credential type %q", req.CredentialType)
+	}
+
+	username, err := s.credsProducer.GenerateUsername(v4.UsernameConfig{
+		DisplayName: req.UsernameConfig.DisplayName,
+		RoleName:    req.UsernameConfig.RoleName,
+		Username:    req.UsernameConfig.Username,
+	})
+	if err != nil {
+		return dbplugin.NewUserResponse{}, err
+	}
+	params["name"] = username
+	params["username"] = username
+
+	// Grab the lock
+	s.Lock()
+	defer s.Unlock()
+
+	db, err := s.getConnection(ctx)
+	if err != nil {
+		return dbplugin.NewUserResponse{}, err
+	}
+
+	if err := executeStatements(ctx, db, req.Statements.Commands, params); err != nil {
+		if len(req.RollbackStatements.Commands) > 0 {
+			if rollbackErr := executeStatements(ctx, db, req.RollbackStatements.Commands, params); rollbackErr != nil {
+				err = fmt.Errorf("%s; rollback failed: %s", err, rollbackErr)
+			}
+		}
+		return dbplugin.NewUserResponse{}, err
+	}
+
+	return dbplugin.NewUserResponse{Username: username}, nil
+}
+
+// UpdateUser runs the statements of each requested change. Changing the
+// password or public key requires statements; changing the expiration
+// without renewal statements is a no-op.
+func (s *SQL) UpdateUser(ctx context.Context, req dbplugin.UpdateUserRequest) (dbplugin.UpdateUserResponse, error) {
+	if req.Username == "" {
+		return dbplugin.UpdateUserResponse{}, errors.New("missing username")
+	}
+
+	// Grab the lock
+	s.Lock()
+	defer s.Unlock()
+
+	db, err := s.getConnection(ctx)
+	if err != nil {
+		return dbplugin.UpdateUserResponse{}, err
+	}
+
+	params := map[string]string{
+		"name":     req.Username,
+		"username": req.Username,
+	}
+
+	if req.Password != nil {
+		if len(req.Password.Statements.Commands) == 0 {
+			return dbplugin.UpdateUserResponse{}, errors.New("rotation statements are required to change the password")
+		}
+		params["password"] = req.Password.NewPassword
+		if err := executeStatements(ctx, db, req.Password.Statements.Commands, params); err != nil {
+			return dbplugin.UpdateUserResponse{}, err
+		}
+	}
+
+	if req.PublicKey != nil {
+		if len(req.PublicKey.Statements.Commands) == 0 {
+			return dbplugin.UpdateUserResponse{}, errors.New("rotation statements are required to change the public key")
+		}
+		publicKey, err := encodePublicKey(req.PublicKey.NewPublicKey)
+		if err != nil {
+			return dbplugin.UpdateUserResponse{}, err
+		}
+		params["public_key"] = publicKey
+		if err := executeStatements(ctx, db, req.PublicKey.Statements.Commands, params); err != nil {
+			return dbplugin.UpdateUserResponse{}, err
+		}
+	}
+
+	if req.Expiration != nil && len(req.Expiration.Statements.Commands) > 0 {
+		params["expiration"] = req.Expiration.NewExpiration.Format(expirationFormat)
+		if err := executeStatements(ctx, db, req.Expiration.Statements.Commands, params); err != nil {
+			return dbplugin.UpdateUserResponse{}, err
+		}
+	}
+
+	return dbplugin.UpdateUserResponse{}, nil
+}
+
+// DeleteUser runs the revocation statements
+func (s *SQL) DeleteUser(ctx context.Context, req dbplugin.DeleteUserRequest) (dbplugin.DeleteUserResponse, error) {
+	if len(req.Statements.Commands) == 0 {
+		return dbplugin.DeleteUserResponse{}, errors.New("revocation statements are required")
+	}
+
+	// Grab the lock
+	s.Lock()
+	defer s.Unlock()
+
+	db, err := s.getConnection(ctx)
+	if err != nil {
+		return dbplugin.DeleteUserResponse{}, err
+	}
+
+	params := map[string]string{
+		"name":     req.Username,
+		"username": req.Username,
+	}
+	if err := executeStatements(ctx, db, req.Statements.Commands, params); err != nil {
+		return dbplugin.DeleteUserResponse{}, err
+	}
+
+	return dbplugin.DeleteUserResponse{}, nil
+}
+
+// executeStatements runs the queries of statements in a transaction
+func executeStatements(ctx context.Context, db *sql.DB, statements []string, params map[string]string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, stmt := range statements {
+		for _, query := range strutil.ParseArbitraryStringSlice(stmt, ";") {
+			query = strings.TrimSpace(query)
+			if len(query) == 0 {
+				continue
+			}
+
+			if err := dbtxn.ExecuteTxQuery(ctx, tx, params, query); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// encodePublicKey returns the base64 encoded DER of a PEM encoded public
+// key, without the PEM header and line breaks, which is the format
+// databases such as Snowflake expect
+func encodePublicKey(publicKey []byte) (string, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return "", errors.New("unable to decode the public key")
+	}
+	return base64.StdEncoding.EncodeToString(block.Bytes), nil
+}
@@ -0,0 +1,215 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbplugin "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLite has no users, so the statements of the tests manage the rows of a
+// users table instead
+const (
+	testCreationSQL   = `INSERT INTO users (name, password, expiration) VALUES ('{{name}}', '{{password}}', '{{expiration}}');`
+	testRSACreation   = `INSERT INTO users (name, public_key) VALUES ('{{name}}', '{{public_key}}');`
+	testRotationSQL   = `UPDATE users SET password = '{{password}}' WHERE name = '{{name}}';`
+	testRenewSQL      = `UPDATE users SET expiration = '{{expiration}}' WHERE name = '{{name}}';`
+	testRevocationSQL = `DELETE FROM users WHERE name = '{{name}}';`
+)
+
+func prepareSQLiteTestDatabase(t *testing.T) (cleanup func(), config map[string]interface{}) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "sqldb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connURL := "file:" + filepath.Join(dir, "test.db")
+
+	db, err := sql.Open("sqlite3", connURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE users (name TEXT PRIMARY KEY, password TEXT, public_key TEXT, expiration TEXT);`); err != nil {
+		t.Fatal(err)
+	}
+
+	return func() { os.RemoveAll(dir) }, map[string]interface{}{
+		"driver_name":    "sqlite3",
+		"connection_url": connURL,
+	}
+}
+
+func testUser(t *testing.T, db *SQL, name string) (password, publicKey, expiration string, exists bool) {
+	t.Helper()
+
+	conn, err := db.getConnection(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pw, pk, exp sql.NullString
+	err = conn.QueryRow(`SELECT password, public_key, expiration FROM users WHERE name = ?`, name).Scan(&pw, &pk, &exp)
+	if err == sql.ErrNoRows {
+		return "", "", "", false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pw.String, pk.String, exp.String, true
+}
+
+func TestSQL_Initialize(t *testing.T) {
+	cleanup, config := prepareSQLiteTestDatabase(t)
+	defer cleanup()
+
+	db := new()
+	defer db.Close()
+	if _, err := db.Initialize(context.Background(), dbplugin.InitializeRequest{
+		Config:           config,
+		VerifyConnection: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !db.Initialized {
+		t.Fatal("database should be initialized")
+	}
+
+	for name, badConfig := range map[string]map[string]interface{}{
+		"missing driver": {"connection_url": config["connection_url"]},
+		"unknown driver": {"driver_name": "unknown", "connection_url": config["connection_url"]},
+		"missing url":    {"driver_name": "sqlite3"},
+	} {
+		if _, err := new().Initialize(context.Background(), dbplugin.InitializeRequest{Config: badConfig}); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSQL_users(t *testing.T) {
+	cleanup, config := prepareSQLiteTestDatabase(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	db := new()
+	defer db.Close()
+	if _, err := db.Initialize(ctx, dbplugin.InitializeRequest{Config: config}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.NewUser(ctx, dbplugin.NewUserRequest{Password: "password"}); err == nil {
+		t.Fatal("expected error without creation statements")
+	}
+
+	expiration := time.Now().Add(time.Hour)
+	resp, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{DisplayName: "token", RoleName: "reader"},
+		Password:       "password",
+		Expiration:     expiration,
+		Statements:     dbplugin.Statements{Commands: []string{testCreationSQL}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, _, exp, exists := testUser(t, db, resp.Username)
+	if !exists || password != "password" || exp != expiration.Format(expirationFormat) {
+		t.Fatalf("bad user %q: password %q, expiration %q", resp.Username, password, exp)
+	}
+
+	// Failed creations are rolled back
+	_, err = db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{Username: "rolled-back"},
+		Password:       "password",
+		Statements:     dbplugin.Statements{Commands: []string{testCreationSQL + "INSERT INTO missing VALUES (1);"}},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if _, _, _, exists := testUser(t, db, "rolled-back"); exists {
+		t.Fatal("expected the creation to be rolled back")
+	}
+
+	// Renewal
+	newExpiration := expiration.Add(time.Hour)
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: resp.Username,
+		Expiration: &dbplugin.ChangeExpiration{
+			NewExpiration: newExpiration,
+			Statements:    dbplugin.Statements{Commands: []string{testRenewSQL}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, exp, _ := testUser(t, db, resp.Username); exp != newExpiration.Format(expirationFormat) {
+		t.Fatalf("expected renewed expiration, got %q", exp)
+	}
+
+	// Rotation
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: resp.Username,
+		Password: &dbplugin.ChangePassword{NewPassword: "rotated"},
+	}); err == nil {
+		t.Fatal("expected error without rotation statements")
+	}
+	if _, err := db.UpdateUser(ctx, dbplugin.UpdateUserRequest{
+		Username: resp.Username,
+		Password: &dbplugin.ChangePassword{
+			NewPassword: "rotated",
+			Statements:  dbplugin.Statements{Commands: []string{testRotationSQL}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if password, _, _, _ := testUser(t, db, resp.Username); password != "rotated" {
+		t.Fatalf("expected rotated password, got %q", password)
+	}
+
+	// Revocation
+	if _, err := db.DeleteUser(ctx, dbplugin.DeleteUserRequest{
+		Username:   resp.Username,
+		Statements: dbplugin.Statements{Commands: []string{testRevocationSQL}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, exists := testUser(t, db, resp.Username); exists {
+		t.Fatal("expected user to be deleted")
+	}
+}
+
+func TestSQL_publicKey(t *testing.T) {
+	cleanup, config := prepareSQLiteTestDatabase(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	db := new()
+	defer db.Close()
+	if _, err := db.Initialize(ctx, dbplugin.InitializeRequest{Config: config}); err != nil {
+		t.Fatal(err)
+	}
+
+	der := []byte("public key bytes")
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	resp, err := db.NewUser(ctx, dbplugin.NewUserRequest{
+		UsernameConfig: dbplugin.UsernameMetadata{RoleName: "svc"},
+		CredentialType: dbplugin.CredentialTypeRSAPrivateKey,
+		PublicKey:      publicKey,
+		Statements:     dbplugin.Statements{Commands: []string{testRSACreation}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, stored, _, _ := testUser(t, db, resp.Username)
+	if stored != base64.StdEncoding.EncodeToString(der) || strings.Contains(stored, "BEGIN") {
+		t.Fatalf("expected the base64 encoded key without PEM armor, got %q", stored)
+	}
+}
@@ -428,8 +428,11 @@ func (m *Statements) GetRotation() []string {
 }
 
 type UsernameConfig struct {
-	DisplayName          string   `protobuf:"bytes,1,opt,name=DisplayName,proto3" json:"DisplayName,omitempty"`
-	RoleName             string   `protobuf:"bytes,2,opt,name=RoleName,proto3" json:"RoleName,omitempty"`
+	DisplayName string `protobuf:"bytes,1,opt,name=DisplayName,proto3" json:"DisplayName,omitempty"`
+	RoleName    string `protobuf:"bytes,2,opt,name=RoleName,proto3" json:"RoleName,omitempty"`
+	// Username, if set, is a username rendered by Vault from the
+	// connection's username template that plugins should use as is
+	Username             string   `protobuf:"bytes,3,opt,name=Username,proto3" json:"Username,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -474,6 +477,13 @@ func (m *UsernameConfig) GetRoleName() string {
 	return ""
 }
 
+func (m *UsernameConfig) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
 type InitResponse struct {
 	Config               []byte   `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -882,60 +892,60 @@ func init() {
 }
 
 var fileDescriptor_cfa445f4444c6876 = []byte{
-	// 839 bytes of a gzipped FileDescriptorProto
+	// 844 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xdd, 0x8e, 0xdb, 0x44,
-	0x14, 0x96, 0xf3, 0xb3, 0x9b, 0x9c, 0x5d, 0xed, 0x26, 0xd3, 0x66, 0x65, 0xb9, 0x85, 0x46, 0x23,
-	0x28, 0x8b, 0x10, 0x31, 0xda, 0x82, 0x0a, 0xbd, 0x00, 0xd1, 0x14, 0x15, 0x24, 0x58, 0xa1, 0x49,
-	0x7b, 0x83, 0x90, 0xa2, 0x89, 0x33, 0x9b, 0x58, 0xeb, 0x78, 0x8c, 0x67, 0x92, 0x12, 0x9e, 0x80,
-	0x37, 0xe0, 0x96, 0x7b, 0x5e, 0x84, 0x87, 0xe1, 0x21, 0x90, 0xc7, 0x1e, 0x7b, 0xfc, 0xb3, 0xad,
-	0xd4, 0x85, 0x3b, 0x9f, 0x39, 0xe7, 0x3b, 0xf3, 0x9d, 0x5f, 0x0f, 0xbc, 0x27, 0x96, 0xd7, 0xee,
-	0x92, 0x4a, 0xba, 0xa0, 0x82, 0xb9, 0xcb, 0x45, 0x14, 0x6c, 0x57, 0x7e, 0x98, 0x9f, 0x4c, 0xa2,
-	0x98, 0x4b, 0x8e, 0x7a, 0x5a, 0xe1, 0x3c, 0x58, 0x71, 0xbe, 0x0a, 0x98, 0xab, 0xce, 0x17, 0xdb,
-	0x2b, 0x57, 0xfa, 0x1b, 0x26, 0x24, 0xdd, 0x44, 0xa9, 0x29, 0xfe, 0x19, 0x86, 0xdf, 0x85, 0xbe,
-	0xf4, 0x69, 0xe0, 0xff, 0xc6, 0x08, 0xfb, 0x65, 0xcb, 0x84, 0x44, 0x67, 0x70, 0xe0, 0xf1, 0xf0,
-	0xca, 0x5f, 0xd9, 0xd6, 0xd8, 0x3a, 0x3f, 0x26, 0x99, 0x84, 0x3e, 0x82, 0xe1, 0x8e, 0xc5, 0xfe,
-	0xd5, 0x7e, 0xee, 0xf1, 0x30, 0x64, 0x9e, 0xf4, 0x79, 0x68, 0xb7, 0xc6, 0xd6, 0x79, 0x8f, 0x0c,
-	0x52, 0xc5, 0x34, 0x3f, 0x7f, 0xd2, 0xb2, 0x2d, 0x4c, 0xe0, 0x28, 0xf1, 0xfe, 0x5f, 0xfa, 0xc5,
-	0x7f, 0x5b, 0x30, 0x9c, 0xc6, 0x8c, 0x4a, 0xf6, 0x52, 0xb0, 0x58, 0xbb, 0xfe, 0x14, 0x40, 0x48,
-	0x2a, 0xd9, 0x86, 0x85, 0x52, 0x28, 0xf7, 0x47, 0x17, 0x77, 0x27, 0x3a, 0x0f, 0x93, 0x59, 0xae,
-	0x23, 0x86, 0x1d, 0xfa, 0x1a, 0x4e, 0xb7, 0x82, 0xc5, 0x21, 0xdd, 0xb0, 0x79, 0xc6, 0xac, 0xa5,
-	0xa0, 0x76, 0x01, 0x7d, 0x99, 0x19, 0x4c, 0x95, 0x9e, 0x9c, 0x6c, 0x4b, 0x32, 0x7a, 0x02, 0xc0,
-	0x7e, 0x8d, 0xfc, 0x98, 0x2a, 0xd2, 0x6d, 0x85, 0x76, 0x26, 0x69, 0xda, 0x27, 0x3a, 0xed, 0x93,
-	0x17, 0x3a, 0xed, 0xc4, 0xb0, 0xc6, 0x7f, 0x5a, 0x30, 0x20, 0x2c, 0x64, 0xaf, 0x6e, 0x1f, 0x89,
-	0x03, 0x3d, 0x4d, 0x4c, 0x85, 0xd0, 0x27, 0xb9, 0x7c, 0x2b, 0x8a, 0x0c, 0x86, 0x84, 0xed, 0xf8,
-	0x35, 0xfb, 0x5f, 0x29, 0xe2, 0x2f, 0xe1, 0x3e, 0xe1, 0x89, 0x29, 0xe1, 0x5c, 0x4e, 0x63, 0xb6,
-	0x64, 0x61, 0xd2, 0x93, 0x42, 0xdf, 0xf8, 0x6e, 0xe5, 0xc6, 0xf6, 0x79, 0xdf, 0xf4, 0x8d, 0xff,
-	0x69, 0x01, 0x14, 0xd7, 0xa2, 0x47, 0x70, 0xc7, 0x4b, 0x5a, 0xc4, 0xe7, 0xe1, 0xbc, 0xc2, 0xb4,
-	0xff, 0xb4, 0x65, 0x5b, 0x04, 0x69, 0xb5, 0x01, 0x7a, 0x0c, 0xa3, 0x98, 0xed, 0xb8, 0x57, 0x83,
-	0xb5, 0x72, 0xd8, 0xdd, 0xc2, 0xa0, 0x7c, 0x5b, 0xcc, 0x83, 0x60, 0x41, 0xbd, 0x6b, 0x13, 0xd6,
-	0x2e, 0x6e, 0xd3, 0x6a, 0x03, 0xf4, 0x31, 0x0c, 0xe2, 0xa4, 0xf4, 0x26, 0xa2, 0x93, 0x23, 0x4e,
-	0x95, 0x6e, 0x56, 0x4a, 0x9e, 0xa6, 0x6c, 0x77, 0x55, 0xf8, 0xb9, 0x9c, 0x24, 0xa7, 0xe0, 0x65,
-	0x1f, 0xa4, 0xc9, 0x29, 0x4e, 0x12, 0xac, 0x26, 0x60, 0x1f, 0xa6, 0x58, 0x2d, 0x23, 0x1b, 0x0e,
-	0xd5, 0x55, 0x34, 0xb0, 0x7b, 0x4a, 0xa5, 0xc5, 0x14, 0x25, 0x53, 0x9f, 0x7d, 0x8d, 0x4a, 0x65,
-	0x7c, 0x09, 0x27, 0xe5, 0xb1, 0x40, 0x63, 0x38, 0x7a, 0xe6, 0x8b, 0x28, 0xa0, 0xfb, 0xcb, 0xa4,
-	0xbe, 0x2a, 0xd3, 0xc4, 0x3c, 0x4a, 0xfc, 0x11, 0x1e, 0xb0, 0x4b, 0xa3, 0xfc, 0x5a, 0xc6, 0x0f,
-	0xe1, 0x38, 0xdd, 0x13, 0x22, 0xe2, 0xa1, 0x60, 0x37, 0x2d, 0x0a, 0xfc, 0x3d, 0x20, 0x73, 0xf4,
-	0x33, 0x6b, 0xb3, 0xb1, 0xac, 0x4a, 0xef, 0x3b, 0xd0, 0x8b, 0xa8, 0x10, 0xaf, 0x78, 0xbc, 0xd4,
-	0xb7, 0x6a, 0x19, 0x63, 0x38, 0x7e, 0xb1, 0x8f, 0x58, 0xee, 0x07, 0x41, 0x47, 0xee, 0x23, 0xed,
-	0x43, 0x7d, 0xe3, 0xc7, 0xf0, 0xce, 0x0d, 0x8d, 0xf9, 0x06, 0xaa, 0x87, 0xd0, 0xfd, 0x66, 0x13,
-	0xc9, 0x3d, 0xfe, 0x02, 0xee, 0x3d, 0x67, 0x21, 0x8b, 0xa9, 0x64, 0x4d, 0x78, 0x93, 0xa0, 0x55,
-	0x21, 0xb8, 0x80, 0x41, 0xd2, 0x02, 0xbe, 0x97, 0x84, 0x9b, 0x25, 0xfa, 0x2d, 0x83, 0x55, 0x3c,
-	0x55, 0xea, 0x54, 0x5f, 0xf6, 0x48, 0x26, 0xe1, 0x3f, 0x2c, 0x18, 0xcd, 0x58, 0xd3, 0xcc, 0xbd,
-	0xdd, 0x94, 0x7f, 0x0b, 0x48, 0x28, 0xce, 0xf3, 0x84, 0x56, 0x79, 0xab, 0x3a, 0x65, 0xb4, 0x19,
-	0x17, 0x19, 0x88, 0xca, 0x09, 0xfe, 0x11, 0xce, 0xaa, 0xc4, 0x6e, 0x57, 0xf0, 0x8b, 0xbf, 0xba,
-	0xd0, 0x7b, 0x96, 0xfd, 0x2a, 0x91, 0x0b, 0x9d, 0xa4, 0xfa, 0xe8, 0xb4, 0x20, 0xa5, 0x0a, 0xe6,
-	0x9c, 0x15, 0x07, 0xa5, 0xf6, 0x78, 0x0e, 0x50, 0x34, 0x1f, 0xba, 0x57, 0x58, 0xd5, 0xfe, 0x46,
-	0xce, 0xfd, 0x66, 0x65, 0xe6, 0xe8, 0x73, 0xe8, 0xe7, 0x5b, 0x1f, 0x19, 0x39, 0xa9, 0xfe, 0x0a,
-	0x9c, 0x2a, 0xb5, 0x64, 0x93, 0x17, 0xdb, 0xd8, 0xa4, 0x50, 0xdb, 0xd1, 0x75, 0xec, 0x1a, 0x46,
-	0x8d, 0x9d, 0x8c, 0x1e, 0x1a, 0x6e, 0x5e, 0xb3, 0x83, 0x9d, 0x0f, 0xde, 0x68, 0x97, 0xc5, 0xf7,
-	0x19, 0x74, 0x92, 0x69, 0x46, 0xa3, 0x02, 0x60, 0xbc, 0x02, 0xcc, 0xfc, 0x96, 0x86, 0xfe, 0x43,
-	0xe8, 0x4e, 0x03, 0x2e, 0x1a, 0x2a, 0x52, 0x8b, 0x65, 0x06, 0x27, 0xe5, 0xd6, 0x40, 0x0f, 0x8c,
-	0xd6, 0x6a, 0xea, 0x66, 0x67, 0x7c, 0xb3, 0x41, 0x76, 0xff, 0x0f, 0x70, 0xa7, 0x61, 0x50, 0xeb,
-	0x6c, 0xde, 0x2f, 0x0e, 0x5e, 0x37, 0xd8, 0x5f, 0x01, 0x14, 0x2f, 0x2b, 0xb3, 0x56, 0xb5, 0xf7,
-	0x56, 0x2d, 0x3e, 0xdc, 0xfe, 0xbd, 0x65, 0x3d, 0xbd, 0xf8, 0xe9, 0x93, 0x95, 0x2f, 0xd7, 0xdb,
-	0xc5, 0xc4, 0xe3, 0x1b, 0x77, 0x4d, 0xc5, 0xda, 0xf7, 0x78, 0x1c, 0xb9, 0x3b, 0xba, 0x0d, 0xa4,
-	0xdb, 0xf8, 0x10, 0x5c, 0x1c, 0xa8, 0xdf, 0xf9, 0xa3, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xf7,
-	0xf5, 0x87, 0x73, 0x28, 0x0a, 0x00, 0x00,
+	0x14, 0x96, 0xb3, 0x7f, 0xc9, 0xd9, 0xd5, 0x6e, 0x76, 0xda, 0x8d, 0x2c, 0xb7, 0xd0, 0x68, 0x04,
+	0x65, 0x11, 0x22, 0x46, 0x5b, 0x50, 0xa1, 0x17, 0x20, 0x9a, 0xa2, 0x82, 0x04, 0x15, 0x9a, 0xb4,
+	0x37, 0x08, 0x29, 0x9a, 0x38, 0xb3, 0x89, 0x59, 0xc7, 0x63, 0x3c, 0x93, 0x94, 0xf0, 0x04, 0xbc,
+	0x01, 0xb7, 0xdc, 0xf3, 0x22, 0x3c, 0x0c, 0x0f, 0x81, 0x66, 0xec, 0xb1, 0xc7, 0x3f, 0xdb, 0x4a,
+	0x5d, 0x7a, 0xe7, 0xf3, 0xf3, 0x9d, 0xf9, 0xce, 0xcf, 0x9c, 0x31, 0xbc, 0x27, 0xe6, 0x57, 0xfe,
+	0x9c, 0x4a, 0x3a, 0xa3, 0x82, 0xf9, 0xf3, 0x59, 0x12, 0xad, 0x17, 0x61, 0x5c, 0x68, 0x46, 0x49,
+	0xca, 0x25, 0x47, 0x5d, 0x63, 0xf0, 0xee, 0x2d, 0x38, 0x5f, 0x44, 0xcc, 0xd7, 0xfa, 0xd9, 0xfa,
+	0xd2, 0x97, 0xe1, 0x8a, 0x09, 0x49, 0x57, 0x49, 0xe6, 0x8a, 0x7f, 0x86, 0xd3, 0xef, 0xe2, 0x50,
+	0x86, 0x34, 0x0a, 0x7f, 0x67, 0x84, 0xfd, 0xba, 0x66, 0x42, 0xa2, 0x01, 0xec, 0x07, 0x3c, 0xbe,
+	0x0c, 0x17, 0xae, 0x33, 0x74, 0xce, 0x8f, 0x48, 0x2e, 0xa1, 0x8f, 0xe0, 0x74, 0xc3, 0xd2, 0xf0,
+	0x72, 0x3b, 0x0d, 0x78, 0x1c, 0xb3, 0x40, 0x86, 0x3c, 0x76, 0x3b, 0x43, 0xe7, 0xbc, 0x4b, 0xfa,
+	0x99, 0x61, 0x5c, 0xe8, 0x1f, 0x75, 0x5c, 0x07, 0x13, 0x38, 0x54, 0xd1, 0xff, 0xcf, 0xb8, 0xf8,
+	0x1f, 0x07, 0x4e, 0xc7, 0x29, 0xa3, 0x92, 0xbd, 0x10, 0x2c, 0x35, 0xa1, 0x3f, 0x05, 0x10, 0x92,
+	0x4a, 0xb6, 0x62, 0xb1, 0x14, 0x3a, 0xfc, 0xe1, 0xc5, 0xed, 0x91, 0xa9, 0xc3, 0x68, 0x52, 0xd8,
+	0x88, 0xe5, 0x87, 0xbe, 0x86, 0x93, 0xb5, 0x60, 0x69, 0x4c, 0x57, 0x6c, 0x9a, 0x33, 0xeb, 0x68,
+	0xa8, 0x5b, 0x42, 0x5f, 0xe4, 0x0e, 0x63, 0x6d, 0x27, 0xc7, 0xeb, 0x8a, 0x8c, 0x1e, 0x01, 0xb0,
+	0xdf, 0x92, 0x30, 0xa5, 0x9a, 0xf4, 0x8e, 0x46, 0x7b, 0xa3, 0xac, 0xec, 0x23, 0x53, 0xf6, 0xd1,
+	0x73, 0x53, 0x76, 0x62, 0x79, 0xe3, 0xbf, 0x1c, 0xe8, 0x13, 0x16, 0xb3, 0x97, 0x37, 0xcf, 0xc4,
+	0x83, 0xae, 0x21, 0xa6, 0x53, 0xe8, 0x91, 0x42, 0xbe, 0x11, 0x45, 0x06, 0xa7, 0x84, 0x6d, 0xf8,
+	0x15, 0x7b, 0xab, 0x14, 0xf1, 0x97, 0x70, 0x97, 0x70, 0xe5, 0x4a, 0x38, 0x97, 0xe3, 0x94, 0xcd,
+	0x59, 0xac, 0x66, 0x52, 0x98, 0x13, 0xdf, 0xad, 0x9d, 0xb8, 0x73, 0xde, 0xb3, 0x63, 0xe3, 0x7f,
+	0x3b, 0x00, 0xe5, 0xb1, 0xe8, 0x01, 0xdc, 0x0a, 0xd4, 0x88, 0x84, 0x3c, 0x9e, 0xd6, 0x98, 0xf6,
+	0x1e, 0x77, 0x5c, 0x87, 0x20, 0x63, 0xb6, 0x40, 0x0f, 0xe1, 0x2c, 0x65, 0x1b, 0x1e, 0x34, 0x60,
+	0x9d, 0x02, 0x76, 0xbb, 0x74, 0xa8, 0x9e, 0x96, 0xf2, 0x28, 0x9a, 0xd1, 0xe0, 0xca, 0x86, 0xed,
+	0x94, 0xa7, 0x19, 0xb3, 0x05, 0xfa, 0x18, 0xfa, 0xa9, 0x6a, 0xbd, 0x8d, 0xd8, 0x2d, 0x10, 0x27,
+	0xda, 0x36, 0xa9, 0x14, 0xcf, 0x50, 0x76, 0xf7, 0x74, 0xfa, 0x85, 0xac, 0x8a, 0x53, 0xf2, 0x72,
+	0xf7, 0xb3, 0xe2, 0x94, 0x1a, 0x85, 0x35, 0x04, 0xdc, 0x83, 0x0c, 0x6b, 0x64, 0xe4, 0xc2, 0x81,
+	0x3e, 0x8a, 0x46, 0x6e, 0x57, 0x9b, 0x8c, 0x98, 0xa1, 0x64, 0x16, 0xb3, 0x67, 0x50, 0x99, 0x8c,
+	0x7f, 0x81, 0xe3, 0xea, 0xb5, 0x40, 0x43, 0x38, 0x7c, 0x12, 0x8a, 0x24, 0xa2, 0xdb, 0x67, 0xaa,
+	0xbf, 0xba, 0xd2, 0xc4, 0x56, 0xa9, 0x78, 0x84, 0x47, 0xec, 0x99, 0xd5, 0x7e, 0x23, 0x2b, 0x9b,
+	0x89, 0x97, 0x95, 0x8d, 0x14, 0x32, 0xbe, 0x0f, 0x47, 0xd9, 0x0e, 0x11, 0x09, 0x8f, 0x05, 0xbb,
+	0x6e, 0x89, 0xe0, 0xef, 0x01, 0xd9, 0x6b, 0x21, 0xf7, 0xb6, 0x87, 0xce, 0xa9, 0xdd, 0x0b, 0x0f,
+	0xba, 0x09, 0x15, 0xe2, 0x25, 0x4f, 0xe7, 0x86, 0x91, 0x91, 0x31, 0x86, 0xa3, 0xe7, 0xdb, 0x84,
+	0x15, 0x71, 0x10, 0xec, 0xca, 0x6d, 0x62, 0x62, 0xe8, 0x6f, 0xfc, 0x10, 0xde, 0xb9, 0x66, 0x68,
+	0x5f, 0x43, 0xf5, 0x00, 0xf6, 0xbe, 0x59, 0x25, 0x72, 0x8b, 0xbf, 0x80, 0x3b, 0x4f, 0x59, 0xcc,
+	0x52, 0x2a, 0x59, 0x1b, 0xde, 0x26, 0xe8, 0xd4, 0x08, 0xce, 0xa0, 0xaf, 0xc6, 0x23, 0x0c, 0x54,
+	0xba, 0x79, 0x13, 0xde, 0x30, 0x59, 0xcd, 0x53, 0x97, 0x4e, 0x17, 0xbf, 0x4b, 0x72, 0x09, 0xff,
+	0xe9, 0xc0, 0xd9, 0x84, 0xb5, 0xdd, 0xc7, 0x37, 0xdb, 0x00, 0xdf, 0x02, 0x12, 0x9a, 0xf3, 0x54,
+	0xd1, 0xaa, 0x6e, 0x5c, 0xaf, 0x8a, 0xb6, 0xf3, 0x22, 0x7d, 0x51, 0xd3, 0xe0, 0x1f, 0x61, 0x50,
+	0x27, 0x76, 0xb3, 0x86, 0x5f, 0xfc, 0xbd, 0x07, 0xdd, 0x27, 0xf9, 0x33, 0x8a, 0x7c, 0xd8, 0x55,
+	0xdd, 0x47, 0x27, 0x25, 0x29, 0xdd, 0x30, 0x6f, 0x50, 0x2a, 0x2a, 0xe3, 0xf1, 0x14, 0xa0, 0x1c,
+	0x3e, 0x74, 0xa7, 0xf4, 0x6a, 0xbc, 0x54, 0xde, 0xdd, 0x76, 0x63, 0x1e, 0xe8, 0x73, 0xe8, 0x15,
+	0x2f, 0x02, 0xb2, 0x6a, 0x52, 0x7f, 0x26, 0xbc, 0x3a, 0x35, 0xb5, 0xe5, 0xcb, 0x4d, 0x6d, 0x53,
+	0x68, 0xec, 0xef, 0x26, 0x76, 0x09, 0x67, 0xad, 0x93, 0x8c, 0xee, 0x5b, 0x61, 0x5e, 0xb1, 0x9f,
+	0xbd, 0x0f, 0x5e, 0xeb, 0x97, 0xe7, 0xf7, 0x19, 0xec, 0xaa, 0xdb, 0x8c, 0xce, 0x4a, 0x80, 0xf5,
+	0x87, 0xe0, 0x0d, 0xea, 0xea, 0x1c, 0xf6, 0x21, 0xec, 0x8d, 0x23, 0x2e, 0x5a, 0x3a, 0xd2, 0xc8,
+	0x65, 0x02, 0xc7, 0xd5, 0xd1, 0x40, 0xf7, 0xac, 0xd1, 0x6a, 0x9b, 0x66, 0x6f, 0x78, 0xbd, 0x43,
+	0x7e, 0xfe, 0x0f, 0x70, 0xab, 0xe5, 0xa2, 0x36, 0xd9, 0xbc, 0x5f, 0x2a, 0x5e, 0x75, 0xb1, 0xbf,
+	0x02, 0x28, 0xff, 0xba, 0xec, 0x5e, 0x35, 0xfe, 0xc5, 0x1a, 0xf9, 0xe1, 0x9d, 0x3f, 0x3a, 0xce,
+	0xe3, 0x8b, 0x9f, 0x3e, 0x59, 0x84, 0x72, 0xb9, 0x9e, 0x8d, 0x02, 0xbe, 0xf2, 0x97, 0x54, 0x2c,
+	0xc3, 0x80, 0xa7, 0x89, 0xbf, 0xa1, 0xeb, 0x48, 0xfa, 0xad, 0x3f, 0x89, 0xb3, 0x7d, 0xfd, 0xd4,
+	0x3f, 0xf8, 0x6f, 0x00, 0x26, 0x3a, 0x13, 0x55, 0x44, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,205 @@
+// Package dbplugin is version 5 of the database plugin interface. Unlike
+// version 4, Vault generates the credentials of users and hands them to the
+// plugin, and plugins manage users with a single request type per operation
+// that carries its own statements.
+package dbplugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Database is the interface that all database plugins must implement.
+type Database interface {
+	// Initialize the database plugin. This is the equivalent of a constructor
+	// for the database object itself. It is called when the database
+	// connection is configured, and when Vault restarts. The config returned
+	// is stored, which persists it across shutdowns.
+	Initialize(ctx context.Context, req InitializeRequest) (InitializeResponse, error)
+
+	// NewUser creates a new user within the database with the credential
+	// generated by Vault. This is called when dynamic credentials are
+	// requested.
+	NewUser(ctx context.Context, req NewUserRequest) (NewUserResponse, error)
+
+	// UpdateUser changes the credential and/or the expiration of an existing
+	// user. This is called when leases are renewed, when static accounts are
+	// rotated, and when the root credentials are rotated.
+	UpdateUser(ctx context.Context, req UpdateUserRequest) (UpdateUserResponse, error)
+
+	// DeleteUser removes a user from the database. This is called when leases
+	// expire or are revoked.
+	DeleteUser(ctx context.Context, req DeleteUserRequest) (DeleteUserResponse, error)
+
+	// Type returns the name of the type of database, e.g. "postgres".
+	Type() (string, error)
+
+	// Close attempts to close the underlying database connection that was
+	// established by the plugin.
+	Close() error
+}
+
+// InitializeRequest contains the configuration of the database connection
+type InitializeRequest struct {
+	// Config is the connection configuration provided by the user, without
+	// the fields consumed by Vault itself
+	Config map[string]interface{}
+
+	// VerifyConnection indicates whether the plugin should verify that it can
+	// connect to the database
+	VerifyConnection bool
+}
+
+// InitializeResponse contains the configuration Vault stores for the
+// connection
+type InitializeResponse struct {
+	// Config is the configuration to store. It may differ from the request,
+	// for example if the plugin fills in defaults.
+	Config map[string]interface{}
+
+	// ServerVersion is the version of the database server, if the plugin
+	// verified the connection and is able to report it
+	ServerVersion string
+}
+
+// CredentialType is the type of credential a database user authenticates with
+type CredentialType int
+
+const (
+	// CredentialTypePassword is a password generated by Vault
+	CredentialTypePassword CredentialType = iota
+
+	// CredentialTypeRSAPrivateKey is an RSA key pair generated by Vault. The
+	// plugin receives the public key and the user receives the private key.
+	CredentialTypeRSAPrivateKey
+
+	// CredentialTypeClientCertificate is a client certificate issued by Vault.
+	// The plugin receives the subject of the certificate and the user
+	// receives the certificate and its private key.
+	CredentialTypeClientCertificate
+)
+
+func (c CredentialType) String() string {
+	switch c {
+	case CredentialTypePassword:
+		return "password"
+	case CredentialTypeRSAPrivateKey:
+		return "rsa_private_key"
+	case CredentialTypeClientCertificate:
+		return "client_certificate"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseCredentialType parses the name of a credential type as returned by
+// CredentialType.String
+func ParseCredentialType(name string) (CredentialType, error) {
+	for _, c := range []CredentialType{CredentialTypePassword, CredentialTypeRSAPrivateKey, CredentialTypeClientCertificate} {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown credential type %q", name)
+}
+
+// NewUserRequest describes the user to create
+type NewUserRequest struct {
+	// UsernameConfig is the metadata usernames are generated from
+	UsernameConfig UsernameMetadata
+
+	// CredentialType is the type of credential the user authenticates with.
+	// Exactly one of Password, PublicKey and Subject is set, according to it.
+	CredentialType CredentialType
+
+	// Password is the password of the user
+	Password string
+
+	// PublicKey is the PEM encoded PKIX public key of the user
+	PublicKey []byte
+
+	// Subject is the subject distinguished name of the user's client
+	// certificate
+	Subject string
+
+	// Expiration is when the user should expire
+	Expiration time.Time
+
+	// Statements creates the user. If empty, plugins use their defaults.
+	Statements Statements
+
+	// RollbackStatements undo the creation of the user if it fails
+	RollbackStatements Statements
+}
+
+// UsernameMetadata is the metadata usernames are generated from
+type UsernameMetadata struct {
+	DisplayName string
+	RoleName    string
+
+	// Username, if set, is a username rendered by Vault from the
+	// connection's username template that plugins should use as is
+	Username string
+}
+
+// NewUserResponse contains the name of the created user
+type NewUserResponse struct {
+	Username string
+}
+
+// UpdateUserRequest describes changes to an existing user. At least one of
+// Password, PublicKey and Expiration is set.
+type UpdateUserRequest struct {
+	// Username is the name of the user to change
+	Username string
+
+	// CredentialType is the type of credential the user authenticates with
+	CredentialType CredentialType
+
+	// Password, if set, changes the password of the user
+	Password *ChangePassword
+
+	// PublicKey, if set, changes the public key of the user
+	PublicKey *ChangePublicKey
+
+	// Expiration, if set, changes when the user expires
+	Expiration *ChangeExpiration
+}
+
+// ChangePassword sets a new password
+type ChangePassword struct {
+	NewPassword string
+	Statements  Statements
+}
+
+// ChangePublicKey sets a new public key
+type ChangePublicKey struct {
+	NewPublicKey []byte
+	Statements   Statements
+}
+
+// ChangeExpiration sets a new expiration
+type ChangeExpiration struct {
+	NewExpiration time.Time
+	Statements    Statements
+}
+
+// UpdateUserResponse is the result of UpdateUser
+type UpdateUserResponse struct{}
+
+// DeleteUserRequest describes the user to delete
+type DeleteUserRequest struct {
+	Username string
+
+	// Statements deletes the user. If empty, plugins use their defaults.
+	Statements Statements
+}
+
+// DeleteUserResponse is the result of DeleteUser
+type DeleteUserResponse struct{}
+
+// Statements are the database commands to run for an operation
+type Statements struct {
+	Commands []string
+}
@@ -0,0 +1,188 @@
+package dbplugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hashicorp/vault/sdk/database/dbplugin/v5/proto"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+)
+
+// ErrPluginShutdown is returned when the plugin process has exited
+var ErrPluginShutdown = errors.New("plugin shutdown")
+
+var _ Database = gRPCClient{}
+
+// gRPCClient implements Database by calling a plugin over gRPC
+type gRPCClient struct {
+	client proto.DatabaseClient
+
+	doneCtx context.Context
+}
+
+func (c gRPCClient) Initialize(ctx context.Context, req InitializeRequest) (InitializeResponse, error) {
+	configData, err := json.Marshal(req.Config)
+	if err != nil {
+		return InitializeResponse{}, fmt.Errorf("unable to marshal config: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	quitCh := pluginutil.CtxCancelIfCanceled(cancel, c.doneCtx)
+	defer close(quitCh)
+	defer cancel()
+
+	resp, err := c.client.Initialize(ctx, &proto.InitializeRequest{
+		ConfigData:       configData,
+		VerifyConnection: req.VerifyConnection,
+	})
+	if err != nil {
+		return InitializeResponse{}, c.translateError(err)
+	}
+
+	config := map[string]interface{}{}
+	if len(resp.GetConfigData()) > 0 {
+		if err := json.Unmarshal(resp.GetConfigData(), &config); err != nil {
+			return InitializeResponse{}, fmt.Errorf("unable to unmarshal config: %s", err)
+		}
+	}
+
+	return InitializeResponse{
+		Config:        config,
+		ServerVersion: resp.GetServerVersion(),
+	}, nil
+}
+
+func (c gRPCClient) NewUser(ctx context.Context, req NewUserRequest) (NewUserResponse, error) {
+	expiration, err := timeToProto(req.Expiration)
+	if err != nil {
+		return NewUserResponse{}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	quitCh := pluginutil.CtxCancelIfCanceled(cancel, c.doneCtx)
+	defer close(quitCh)
+	defer cancel()
+
+	resp, err := c.client.NewUser(ctx, &proto.NewUserRequest{
+		UsernameConfig: &proto.UsernameConfig{
+			DisplayName: req.UsernameConfig.DisplayName,
+			RoleName:    req.UsernameConfig.RoleName,
+			Username:    req.UsernameConfig.Username,
+		},
+		CredentialType:     proto.CredentialType(req.CredentialType),
+		Password:           req.Password,
+		PublicKey:          req.PublicKey,
+		Subject:            req.Subject,
+		Expiration:         expiration,
+		Statements:         statementsToProto(req.Statements),
+		RollbackStatements: statementsToProto(req.RollbackStatements),
+	})
+	if err != nil {
+		return NewUserResponse{}, c.translateError(err)
+	}
+
+	return NewUserResponse{
+		Username: resp.GetUsername(),
+	}, nil
+}
+
+func (c gRPCClient) UpdateUser(ctx context.Context, req UpdateUserRequest) (UpdateUserResponse, error) {
+	if req.Password == nil && req.PublicKey == nil && req.Expiration == nil {
+		return UpdateUserResponse{}, errors.New("no changes requested")
+	}
+
+	rpcReq := &proto.UpdateUserRequest{
+		Username:       req.Username,
+		CredentialType: proto.CredentialType(req.CredentialType),
+	}
+	if req.Password != nil {
+		rpcReq.Password = &proto.ChangePassword{
+			NewPassword: req.Password.NewPassword,
+			Statements:  statementsToProto(req.Password.Statements),
+		}
+	}
+	if req.PublicKey != nil {
+		rpcReq.PublicKey = &proto.ChangePublicKey{
+			NewPublicKey: req.PublicKey.NewPublicKey,
+			Statements:   statementsToProto(req.PublicKey.Statements),
+		}
+	}
+	if req.Expiration != nil {
+		expiration, err := timeToProto(req.Expiration.NewExpiration)
+		if err != nil {
+			return UpdateUserResponse{}, err
+		}
+		rpcReq.Expiration = &proto.ChangeExpiration{
+			NewExpiration: expiration,
+			Statements:    statementsToProto(req.Expiration.Statements),
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	quitCh := pluginutil.CtxCancelIfCanceled(cancel, c.doneCtx)
+	defer close(quitCh)
+	defer cancel()
+
+	if _, err := c.client.UpdateUser(ctx, rpcReq); err != nil {
+		return UpdateUserResponse{}, c.translateError(err)
+	}
+	return UpdateUserResponse{}, nil
+}
+
+func (c gRPCClient) DeleteUser(ctx context.Context, req DeleteUserRequest) (DeleteUserResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	quitCh := pluginutil.CtxCancelIfCanceled(cancel, c.doneCtx)
+	defer close(quitCh)
+	defer cancel()
+
+	_, err := c.client.DeleteUser(ctx, &proto.DeleteUserRequest{
+		Username:   req.Username,
+		Statements: statementsToProto(req.Statements),
+	})
+	if err != nil {
+		return DeleteUserResponse{}, c.translateError(err)
+	}
+	return DeleteUserResponse{}, nil
+}
+
+func (c gRPCClient) Type() (string, error) {
+	resp, err := c.client.Type(c.doneCtx, &proto.Empty{})
+	if err != nil {
+		return "", c.translateError(err)
+	}
+	return resp.GetType(), nil
+}
+
+func (c gRPCClient) Close() error {
+	_, err := c.client.Close(c.doneCtx, &proto.Empty{})
+	if err != nil {
+		return c.translateError(err)
+	}
+	return nil
+}
+
+// translateError returns ErrPluginShutdown if the plugin has exited
+func (c gRPCClient) translateError(err error) error {
+	if c.doneCtx.Err() != nil {
+		return ErrPluginShutdown
+	}
+	return err
+}
+
+func timeToProto(t time.Time) (*timestamp.Timestamp, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return ptypes.TimestampProto(t)
+}
+
+func statementsToProto(s Statements) *proto.Statements {
+	return &proto.Statements{
+		Commands: s.Commands,
+	}
+}
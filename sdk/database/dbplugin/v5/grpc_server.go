@@ -0,0 +1,167 @@
+package dbplugin
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hashicorp/vault/sdk/database/dbplugin/v5/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ proto.DatabaseServer = gRPCServer{}
+
+// gRPCServer serves a Database implementation over gRPC
+type gRPCServer struct {
+	impl Database
+}
+
+func (g gRPCServer) Initialize(ctx context.Context, req *proto.InitializeRequest) (*proto.InitializeResponse, error) {
+	config := map[string]interface{}{}
+	if len(req.GetConfigData()) > 0 {
+		if err := json.Unmarshal(req.GetConfigData(), &config); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal config: %s", err)
+		}
+	}
+
+	resp, err := g.impl.Initialize(ctx, InitializeRequest{
+		Config:           config,
+		VerifyConnection: req.GetVerifyConnection(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configData, err := json.Marshal(resp.Config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal config: %s", err)
+	}
+
+	return &proto.InitializeResponse{
+		ConfigData:    configData,
+		ServerVersion: resp.ServerVersion,
+	}, nil
+}
+
+func (g gRPCServer) NewUser(ctx context.Context, req *proto.NewUserRequest) (*proto.NewUserResponse, error) {
+	if req.GetUsernameConfig() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "missing username config")
+	}
+
+	expiration, err := timeFromProto(req.GetExpiration())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid expiration: %s", err)
+	}
+
+	resp, err := g.impl.NewUser(ctx, NewUserRequest{
+		UsernameConfig: UsernameMetadata{
+			DisplayName: req.GetUsernameConfig().GetDisplayName(),
+			RoleName:    req.GetUsernameConfig().GetRoleName(),
+			Username:    req.GetUsernameConfig().GetUsername(),
+		},
+		CredentialType:     CredentialType(req.GetCredentialType()),
+		Password:           req.GetPassword(),
+		PublicKey:          req.GetPublicKey(),
+		Subject:            req.GetSubject(),
+		Expiration:         expiration,
+		Statements:         statementsFromProto(req.GetStatements()),
+		RollbackStatements: statementsFromProto(req.GetRollbackStatements()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.NewUserResponse{
+		Username: resp.Username,
+	}, nil
+}
+
+func (g gRPCServer) UpdateUser(ctx context.Context, req *proto.UpdateUserRequest) (*proto.UpdateUserResponse, error) {
+	if req.GetUsername() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no username provided")
+	}
+
+	dbReq := UpdateUserRequest{
+		Username:       req.GetUsername(),
+		CredentialType: CredentialType(req.GetCredentialType()),
+	}
+	if req.GetPassword() != nil {
+		dbReq.Password = &ChangePassword{
+			NewPassword: req.GetPassword().GetNewPassword(),
+			Statements:  statementsFromProto(req.GetPassword().GetStatements()),
+		}
+	}
+	if req.GetPublicKey() != nil {
+		dbReq.PublicKey = &ChangePublicKey{
+			NewPublicKey: req.GetPublicKey().GetNewPublicKey(),
+			Statements:   statementsFromProto(req.GetPublicKey().GetStatements()),
+		}
+	}
+	if req.GetExpiration() != nil {
+		expiration, err := timeFromProto(req.GetExpiration().GetNewExpiration())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expiration: %s", err)
+		}
+		dbReq.Expiration = &ChangeExpiration{
+			NewExpiration: expiration,
+			Statements:    statementsFromProto(req.GetExpiration().GetStatements()),
+		}
+	}
+
+	if _, err := g.impl.UpdateUser(ctx, dbReq); err != nil {
+		return nil, err
+	}
+	return &proto.UpdateUserResponse{}, nil
+}
+
+func (g gRPCServer) DeleteUser(ctx context.Context, req *proto.DeleteUserRequest) (*proto.DeleteUserResponse, error) {
+	if req.GetUsername() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no username provided")
+	}
+
+	_, err := g.impl.DeleteUser(ctx, DeleteUserRequest{
+		Username:   req.GetUsername(),
+		Statements: statementsFromProto(req.GetStatements()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.DeleteUserResponse{}, nil
+}
+
+func (g gRPCServer) Type(ctx context.Context, _ *proto.Empty) (*proto.TypeResponse, error) {
+	t, err := g.impl.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TypeResponse{
+		Type: t,
+	}, nil
+}
+
+func (g gRPCServer) Close(ctx context.Context, _ *proto.Empty) (*proto.Empty, error) {
+	if err := g.impl.Close(); err != nil {
+		return nil, err
+	}
+	return &proto.Empty{}, nil
+}
+
+func timeFromProto(t *timestamp.Timestamp) (time.Time, error) {
+	if t == nil {
+		return time.Time{}, nil
+	}
+	return ptypes.Timestamp(t)
+}
+
+func statementsFromProto(s *proto.Statements) Statements {
+	if s == nil {
+		return Statements{}
+	}
+	return Statements{
+		Commands: s.GetCommands(),
+	}
+}
@@ -0,0 +1,220 @@
+package dbplugin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+// recordingDatabase records the requests it receives
+type recordingDatabase struct {
+	initialize InitializeRequest
+	newUser    NewUserRequest
+	updateUser UpdateUserRequest
+	deleteUser DeleteUserRequest
+	closed     bool
+}
+
+func (r *recordingDatabase) Initialize(_ context.Context, req InitializeRequest) (InitializeResponse, error) {
+	r.initialize = req
+	config := map[string]interface{}{"saved": true}
+	for k, v := range req.Config {
+		config[k] = v
+	}
+	return InitializeResponse{Config: config, ServerVersion: "1.2.3"}, nil
+}
+
+func (r *recordingDatabase) NewUser(_ context.Context, req NewUserRequest) (NewUserResponse, error) {
+	r.newUser = req
+	if req.UsernameConfig.Username != "" {
+		return NewUserResponse{Username: req.UsernameConfig.Username}, nil
+	}
+	return NewUserResponse{Username: "v_" + req.UsernameConfig.RoleName}, nil
+}
+
+func (r *recordingDatabase) UpdateUser(_ context.Context, req UpdateUserRequest) (UpdateUserResponse, error) {
+	r.updateUser = req
+	return UpdateUserResponse{}, nil
+}
+
+func (r *recordingDatabase) DeleteUser(_ context.Context, req DeleteUserRequest) (DeleteUserResponse, error) {
+	r.deleteUser = req
+	if req.Username == "missing" {
+		return DeleteUserResponse{}, errors.New("user not found")
+	}
+	return DeleteUserResponse{}, nil
+}
+
+func (r *recordingDatabase) Type() (string, error) { return "recording", nil }
+
+func (r *recordingDatabase) Close() error {
+	r.closed = true
+	return nil
+}
+
+func testGRPCClient(t *testing.T, impl Database) (Database, func()) {
+	t.Helper()
+
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		"database": &GRPCDatabasePlugin{Impl: impl},
+	})
+
+	raw, err := client.Dispense("database")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw.(Database), func() { client.Close() }
+}
+
+func TestGRPC_roundTrip(t *testing.T) {
+	impl := &recordingDatabase{}
+	db, cleanup := testGRPCClient(t, impl)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Initialize
+	initResp, err := db.Initialize(ctx, InitializeRequest{
+		Config:           map[string]interface{}{"connection_url": "localhost"},
+		VerifyConnection: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !impl.initialize.VerifyConnection || impl.initialize.Config["connection_url"] != "localhost" {
+		t.Fatalf("bad initialize request: %#v", impl.initialize)
+	}
+	expectedConfig := map[string]interface{}{"connection_url": "localhost", "saved": true}
+	if !reflect.DeepEqual(initResp.Config, expectedConfig) {
+		t.Fatalf("expected config %#v, got %#v", expectedConfig, initResp.Config)
+	}
+	if initResp.ServerVersion != "1.2.3" {
+		t.Fatalf("bad server version %q", initResp.ServerVersion)
+	}
+
+	// NewUser with each credential type
+	expiration := time.Now().Add(time.Hour).Round(time.Second).UTC()
+	newUserReqs := []NewUserRequest{
+		{
+			UsernameConfig: UsernameMetadata{DisplayName: "token", RoleName: "ro"},
+			CredentialType: CredentialTypePassword,
+			Password:       "secret",
+		},
+		{
+			UsernameConfig: UsernameMetadata{DisplayName: "token", RoleName: "ro", Username: "rendered"},
+			CredentialType: CredentialTypeRSAPrivateKey,
+			PublicKey:      []byte("-----BEGIN PUBLIC KEY-----"),
+		},
+		{
+			UsernameConfig: UsernameMetadata{DisplayName: "token", RoleName: "ro"},
+			CredentialType: CredentialTypeClientCertificate,
+			Subject:        "CN=ro",
+		},
+	}
+	for _, req := range newUserReqs {
+		req.Expiration = expiration
+		req.Statements = Statements{Commands: []string{"CREATE USER"}}
+		req.RollbackStatements = Statements{Commands: []string{"DROP USER"}}
+
+		resp, err := db.NewUser(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(impl.newUser, req) {
+			t.Fatalf("expected request %#v, got %#v", req, impl.newUser)
+		}
+		expectedUsername := "v_ro"
+		if req.UsernameConfig.Username != "" {
+			expectedUsername = req.UsernameConfig.Username
+		}
+		if resp.Username != expectedUsername {
+			t.Fatalf("expected username %q, got %q", expectedUsername, resp.Username)
+		}
+	}
+
+	// UpdateUser
+	updateReq := UpdateUserRequest{
+		Username:       "v_ro",
+		CredentialType: CredentialTypePassword,
+		Password: &ChangePassword{
+			NewPassword: "new-secret",
+			Statements:  Statements{Commands: []string{"ALTER USER"}},
+		},
+		Expiration: &ChangeExpiration{
+			NewExpiration: expiration,
+			Statements:    Statements{Commands: []string{"ALTER USER VALID UNTIL"}},
+		},
+	}
+	if _, err := db.UpdateUser(ctx, updateReq); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(impl.updateUser, updateReq) {
+		t.Fatalf("expected request %#v, got %#v", updateReq, impl.updateUser)
+	}
+	if _, err := db.UpdateUser(ctx, UpdateUserRequest{Username: "v_ro"}); err == nil {
+		t.Fatal("expected error for update without changes")
+	}
+
+	// DeleteUser
+	deleteReq := DeleteUserRequest{
+		Username:   "v_ro",
+		Statements: Statements{Commands: []string{"DROP USER"}},
+	}
+	if _, err := db.DeleteUser(ctx, deleteReq); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(impl.deleteUser, deleteReq) {
+		t.Fatalf("expected request %#v, got %#v", deleteReq, impl.deleteUser)
+	}
+	if _, err := db.DeleteUser(ctx, DeleteUserRequest{Username: "missing"}); err == nil {
+		t.Fatal("expected error from plugin")
+	}
+
+	// Type and Close
+	typ, err := db.Type()
+	if err != nil || typ != "recording" {
+		t.Fatalf("bad type %q: %v", typ, err)
+	}
+	if err := db.Close(); err != nil || !impl.closed {
+		t.Fatalf("expected plugin to be closed: %v", err)
+	}
+}
+
+func TestParseCredentialType(t *testing.T) {
+	for _, c := range []CredentialType{CredentialTypePassword, CredentialTypeRSAPrivateKey, CredentialTypeClientCertificate} {
+		parsed, err := ParseCredentialType(c.String())
+		if err != nil || parsed != c {
+			t.Fatalf("failed to round trip %v: %v", c, err)
+		}
+	}
+	if _, err := ParseCredentialType("bogus"); err == nil {
+		t.Fatal("expected error for unknown credential type")
+	}
+}
+
+// erroringDatabase fails to close with the given error
+type erroringDatabase struct {
+	recordingDatabase
+	err error
+}
+
+func (e *erroringDatabase) Close() error {
+	return e.err
+}
+
+func TestErrorSanitizerMiddleware(t *testing.T) {
+	db := NewDatabaseErrorSanitizerMiddleware(&erroringDatabase{err: errors.New("failed to connect as admin:hunter2 on port 5432")}, func() map[string]interface{} {
+		return map[string]interface{}{
+			"hunter2": "[password]",
+			"5432":    5432,
+		}
+	})
+
+	err := db.Close()
+	if err == nil || err.Error() != "failed to connect as admin:[password] on port 5432" {
+		t.Fatalf("bad: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package dbplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/errwrap"
+	log "github.com/hashicorp/go-hclog"
+)
+
+// ---- Tracing Middleware Domain ----
+
+// databaseTracingMiddleware wraps a implementation of Database and executes
+// trace logging on function call.
+type databaseTracingMiddleware struct {
+	next   Database
+	logger log.Logger
+}
+
+func (mw *databaseTracingMiddleware) Initialize(ctx context.Context, req InitializeRequest) (resp InitializeResponse, err error) {
+	defer func(then time.Time) {
+		mw.logger.Trace("initialize", "status", "finished", "verify", req.VerifyConnection, "err", err, "took", time.Since(then))
+	}(time.Now())
+
+	mw.logger.Trace("initialize", "status", "started")
+	return mw.next.Initialize(ctx, req)
+}
+
+func (mw *databaseTracingMiddleware) NewUser(ctx context.Context, req NewUserRequest) (resp NewUserResponse, err error) {
+	defer func(then time.Time) {
+		mw.logger.Trace("new user", "status", "finished", "credential_type", req.CredentialType.String(), "err", err, "took", time.Since(then))
+	}(time.Now())
+
+	mw.logger.Trace("new user", "status", "started")
+	return mw.next.NewUser(ctx, req)
+}
+
+func (mw *databaseTracingMiddleware) UpdateUser(ctx context.Context, req UpdateUserRequest) (resp UpdateUserResponse, err error) {
+	defer func(then time.Time) {
+		mw.logger.Trace("update user", "status", "finished", "err", err, "took", time.Since(then))
+	}(time.Now())
+
+	mw.logger.Trace("update user", "status", "started")
+	return mw.next.UpdateUser(ctx, req)
+}
+
+func (mw *databaseTracingMiddleware) DeleteUser(ctx context.Context, req DeleteUserRequest) (resp DeleteUserResponse, err error) {
+	defer func(then time.Time) {
+		mw.logger.Trace("delete user", "status", "finished", "err", err, "took", time.Since(then))
+	}(time.Now())
+
+	mw.logger.Trace("delete user", "status", "started")
+	return mw.next.DeleteUser(ctx, req)
+}
+
+func (mw *databaseTracingMiddleware) Type() (string, error) {
+	return mw.next.Type()
+}
+
+func (mw *databaseTracingMiddleware) Close() (err error) {
+	defer func(then time.Time) {
+		mw.logger.Trace("close", "status", "finished", "err", err, "took", time.Since(then))
+	}(time.Now())
+
+	mw.logger.Trace("close", "status", "started")
+	return mw.next.Close()
+}
+
+// ---- Metrics Middleware Domain ----
+
+// databaseMetricsMiddleware wraps an implementation of Databases and on
+// function call logs metrics about this instance.
+type databaseMetricsMiddleware struct {
+	next Database
+
+	typeStr string
+}
+
+// measure records the count, duration and errors of a call to method
+func (mw *databaseMetricsMiddleware) measure(method string) func(now time.Time, err *error) {
+	metrics.IncrCounter([]string{"database", method}, 1)
+	metrics.IncrCounter([]string{"database", mw.typeStr, method}, 1)
+
+	return func(now time.Time, err *error) {
+		metrics.MeasureSince([]string{"database", method}, now)
+		metrics.MeasureSince([]string{"database", mw.typeStr, method}, now)
+
+		if *err != nil {
+			metrics.IncrCounter([]string{"database", method, "error"}, 1)
+			metrics.IncrCounter([]string{"database", mw.typeStr, method, "error"}, 1)
+		}
+	}
+}
+
+func (mw *databaseMetricsMiddleware) Initialize(ctx context.Context, req InitializeRequest) (resp InitializeResponse, err error) {
+	defer mw.measure("Initialize")(time.Now(), &err)
+	return mw.next.Initialize(ctx, req)
+}
+
+func (mw *databaseMetricsMiddleware) NewUser(ctx context.Context, req NewUserRequest) (resp NewUserResponse, err error) {
+	defer mw.measure("NewUser")(time.Now(), &err)
+	return mw.next.NewUser(ctx, req)
+}
+
+func (mw *databaseMetricsMiddleware) UpdateUser(ctx context.Context, req UpdateUserRequest) (resp UpdateUserResponse, err error) {
+	defer mw.measure("UpdateUser")(time.Now(), &err)
+	return mw.next.UpdateUser(ctx, req)
+}
+
+func (mw *databaseMetricsMiddleware) DeleteUser(ctx context.Context, req DeleteUserRequest) (resp DeleteUserResponse, err error) {
+	defer mw.measure("DeleteUser")(time.Now(), &err)
+	return mw.next.DeleteUser(ctx, req)
+}
+
+func (mw *databaseMetricsMiddleware) Type() (string, error) {
+	return mw.next.Type()
+}
+
+func (mw *databaseMetricsMiddleware) Close() (err error) {
+	defer mw.measure("Close")(time.Now(), &err)
+	return mw.next.Close()
+}
+
+// ---- Error Sanitizer Middleware Domain ----
+
+// errorSanitizerMiddleware wraps an implementation of Databases and
+// sanitizes returned error messages so connection URLs, which may contain
+// credentials, are not returned to Vault
+type errorSanitizerMiddleware struct {
+	next      Database
+	secretsFn func() map[string]interface{}
+}
+
+// NewDatabaseErrorSanitizerMiddleware returns a Database that replaces the
+// keys of the map returned by secretsFn with their values in the errors of
+// next. Builtin plugins use it to keep secrets out of error messages.
+func NewDatabaseErrorSanitizerMiddleware(next Database, secretsFn func() map[string]interface{}) Database {
+	return &errorSanitizerMiddleware{
+		next:      next,
+		secretsFn: secretsFn,
+	}
+}
+
+func (mw *errorSanitizerMiddleware) Initialize(ctx context.Context, req InitializeRequest) (InitializeResponse, error) {
+	resp, err := mw.next.Initialize(ctx, req)
+	return resp, mw.sanitize(err)
+}
+
+func (mw *errorSanitizerMiddleware) NewUser(ctx context.Context, req NewUserRequest) (NewUserResponse, error) {
+	resp, err := mw.next.NewUser(ctx, req)
+	return resp, mw.sanitize(err)
+}
+
+func (mw *errorSanitizerMiddleware) UpdateUser(ctx context.Context, req UpdateUserRequest) (UpdateUserResponse, error) {
+	resp, err := mw.next.UpdateUser(ctx, req)
+	return resp, mw.sanitize(err)
+}
+
+func (mw *errorSanitizerMiddleware) DeleteUser(ctx context.Context, req DeleteUserRequest) (DeleteUserResponse, error) {
+	resp, err := mw.next.DeleteUser(ctx, req)
+	return resp, mw.sanitize(err)
+}
+
+func (mw *errorSanitizerMiddleware) Type() (string, error) {
+	dbType, err := mw.next.Type()
+	return dbType, mw.sanitize(err)
+}
+
+func (mw *errorSanitizerMiddleware) Close() error {
+	return mw.sanitize(mw.next.Close())
+}
+
+func (mw *errorSanitizerMiddleware) sanitize(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errwrap.ContainsType(err, new(url.Error)) {
+		return errors.New("unable to parse connection url")
+	}
+	if mw.secretsFn != nil {
+		for k, v := range mw.secretsFn() {
+			if k == "" {
+				continue
+			}
+			// Config values aren't necessarily strings
+			err = errors.New(strings.Replace(err.Error(), k, fmt.Sprint(v), -1))
+		}
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package dbplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/errwrap"
+	log "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/vault/sdk/database/dbplugin/v5/proto"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"google.golang.org/grpc"
+)
+
+// ErrNotV5 is returned by PluginFactory for plugins that do not implement
+// version 5 of the interface. Callers may fall back to the version 4
+// interface for them.
+var ErrNotV5 = errors.New("database plugin does not implement version 5 of the plugin interface")
+
+// handshakeConfig is used to just do a basic handshake between a plugin and
+// host. The magic cookie is shared with earlier versions of the interface so
+// the protocol version can be negotiated.
+var handshakeConfig = plugin.HandshakeConfig{
+	ProtocolVersion:  5,
+	MagicCookieKey:   "VAULT_DATABASE_PLUGIN",
+	MagicCookieValue: "926a0820-aea2-be28-51d6-83cdf00e8edb",
+}
+
+var _ plugin.Plugin = &GRPCDatabasePlugin{}
+var _ plugin.GRPCPlugin = &GRPCDatabasePlugin{}
+
+// GRPCDatabasePlugin is the plugin.Plugin implementation that serves and
+// dispenses version 5 database plugins over gRPC
+type GRPCDatabasePlugin struct {
+	Impl Database
+
+	// Embeding this will disable the netRPC protocol
+	plugin.NetRPCUnsupportedPlugin
+}
+
+func (d GRPCDatabasePlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterDatabaseServer(s, gRPCServer{
+		impl: &errorSanitizerMiddleware{next: d.Impl},
+	})
+	return nil
+}
+
+func (GRPCDatabasePlugin) GRPCClient(doneCtx context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return gRPCClient{
+		client:  proto.NewDatabaseClient(c),
+		doneCtx: doneCtx,
+	}, nil
+}
+
+// DatabasePluginClient embeds a gRPC client and wraps its Close method to
+// also kill the plugin process.
+type DatabasePluginClient struct {
+	client *plugin.Client
+	sync.Mutex
+
+	Database
+}
+
+// Close closes the database connection and kills the plugin.
+func (dc *DatabasePluginClient) Close() error {
+	err := dc.Database.Close()
+	dc.client.Kill()
+
+	return err
+}
+
+// NewPluginClient starts a version 5 plugin and returns a client connected
+// to it. Closing the client kills the plugin.
+func NewPluginClient(ctx context.Context, sys pluginutil.RunnerUtil, pluginRunner *pluginutil.PluginRunner, logger log.Logger, isMetadataMode bool) (Database, error) {
+	pluginSets := map[int]plugin.PluginSet{
+		5: plugin.PluginSet{
+			"database": new(GRPCDatabasePlugin),
+		},
+	}
+
+	var client *plugin.Client
+	var err error
+	if isMetadataMode {
+		client, err = pluginRunner.RunMetadataMode(ctx, sys, pluginSets, handshakeConfig, []string{}, logger)
+	} else {
+		client, err = pluginRunner.Run(ctx, sys, pluginSets, handshakeConfig, []string{}, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	raw, err := rpcClient.Dispense("database")
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	db, ok := raw.(gRPCClient)
+	if !ok {
+		client.Kill()
+		return nil, errors.New("unsupported client type")
+	}
+
+	return &DatabasePluginClient{
+		client:   client,
+		Database: db,
+	}, nil
+}
+
+// PluginFactory is used to build version 5 plugin database types. It wraps
+// the database object in a logging and metrics middleware. It returns
+// ErrNotV5 for builtin plugins that implement an earlier version.
+func PluginFactory(ctx context.Context, pluginName string, sys pluginutil.LookRunnerUtil, logger log.Logger) (Database, error) {
+	pluginRunner, err := sys.LookupPlugin(ctx, pluginName, consts.PluginTypeDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	namedLogger := logger.Named(pluginName)
+
+	var transport string
+	var db Database
+	if pluginRunner.Builtin {
+		dbRaw, err := pluginRunner.BuiltinFactory()
+		if err != nil {
+			return nil, errwrap.Wrapf("error initializing plugin: {{err}}", err)
+		}
+
+		var ok bool
+		db, ok = dbRaw.(Database)
+		if !ok {
+			return nil, ErrNotV5
+		}
+
+		transport = "builtin"
+	} else {
+		db, err = NewPluginClient(ctx, sys, pluginRunner, namedLogger, false)
+		if err != nil {
+			return nil, err
+		}
+
+		transport = "gRPC"
+	}
+
+	typeStr, err := db.Type()
+	if err != nil {
+		db.Close()
+		return nil, errwrap.Wrapf(fmt.Sprintf("error getting plugin type of %q: {{err}}", pluginName), err)
+	}
+
+	db = &databaseMetricsMiddleware{
+		next:    db,
+		typeStr: typeStr,
+	}
+
+	if namedLogger.IsTrace() {
+		db = &databaseTracingMiddleware{
+			next:   db,
+			logger: namedLogger.With("transport", transport),
+		}
+	}
+
+	return db, nil
+}
@@ -0,0 +1,1109 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: sdk/database/dbplugin/v5/proto/database.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// CredentialType is the type of credential a user authenticates with
+type CredentialType int32
+
+const (
+	CredentialType_PASSWORD           CredentialType = 0
+	CredentialType_RSA_PRIVATE_KEY    CredentialType = 1
+	CredentialType_CLIENT_CERTIFICATE CredentialType = 2
+)
+
+var CredentialType_name = map[int32]string{
+	0: "PASSWORD",
+	1: "RSA_PRIVATE_KEY",
+	2: "CLIENT_CERTIFICATE",
+}
+
+var CredentialType_value = map[string]int32{
+	"PASSWORD":           0,
+	"RSA_PRIVATE_KEY":    1,
+	"CLIENT_CERTIFICATE": 2,
+}
+
+func (x CredentialType) String() string {
+	return proto.EnumName(CredentialType_name, int32(x))
+}
+
+func (CredentialType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{0}
+}
+
+type InitializeRequest struct {
+	ConfigData           []byte   `protobuf:"bytes,1,opt,name=config_data,json=configData,proto3" json:"config_data,omitempty"`
+	VerifyConnection     bool     `protobuf:"varint,2,opt,name=verify_connection,json=verifyConnection,proto3" json:"verify_connection,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InitializeRequest) Reset()         { *m = InitializeRequest{} }
+func (m *InitializeRequest) String() string { return proto.CompactTextString(m) }
+func (*InitializeRequest) ProtoMessage()    {}
+func (*InitializeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{0}
+}
+
+func (m *InitializeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitializeRequest.Unmarshal(m, b)
+}
+func (m *InitializeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitializeRequest.Marshal(b, m, deterministic)
+}
+func (m *InitializeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitializeRequest.Merge(m, src)
+}
+func (m *InitializeRequest) XXX_Size() int {
+	return xxx_messageInfo_InitializeRequest.Size(m)
+}
+func (m *InitializeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitializeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitializeRequest proto.InternalMessageInfo
+
+func (m *InitializeRequest) GetConfigData() []byte {
+	if m != nil {
+		return m.ConfigData
+	}
+	return nil
+}
+
+func (m *InitializeRequest) GetVerifyConnection() bool {
+	if m != nil {
+		return m.VerifyConnection
+	}
+	return false
+}
+
+type InitializeResponse struct {
+	ConfigData           []byte   `protobuf:"bytes,1,opt,name=config_data,json=configData,proto3" json:"config_data,omitempty"`
+	ServerVersion        string   `protobuf:"bytes,2,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InitializeResponse) Reset()         { *m = InitializeResponse{} }
+func (m *InitializeResponse) String() string { return proto.CompactTextString(m) }
+func (*InitializeResponse) ProtoMessage()    {}
+func (*InitializeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{1}
+}
+
+func (m *InitializeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitializeResponse.Unmarshal(m, b)
+}
+func (m *InitializeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitializeResponse.Marshal(b, m, deterministic)
+}
+func (m *InitializeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitializeResponse.Merge(m, src)
+}
+func (m *InitializeResponse) XXX_Size() int {
+	return xxx_messageInfo_InitializeResponse.Size(m)
+}
+func (m *InitializeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitializeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitializeResponse proto.InternalMessageInfo
+
+func (m *InitializeResponse) GetConfigData() []byte {
+	if m != nil {
+		return m.ConfigData
+	}
+	return nil
+}
+
+func (m *InitializeResponse) GetServerVersion() string {
+	if m != nil {
+		return m.ServerVersion
+	}
+	return ""
+}
+
+type NewUserRequest struct {
+	UsernameConfig       *UsernameConfig      `protobuf:"bytes,1,opt,name=username_config,json=usernameConfig,proto3" json:"username_config,omitempty"`
+	CredentialType       CredentialType       `protobuf:"varint,2,opt,name=credential_type,json=credentialType,proto3,enum=dbplugin.v5.CredentialType" json:"credential_type,omitempty"`
+	Password             string               `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	PublicKey            []byte               `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Subject              string               `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	Expiration           *timestamp.Timestamp `protobuf:"bytes,6,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	Statements           *Statements          `protobuf:"bytes,7,opt,name=statements,proto3" json:"statements,omitempty"`
+	RollbackStatements   *Statements          `protobuf:"bytes,8,opt,name=rollback_statements,json=rollbackStatements,proto3" json:"rollback_statements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *NewUserRequest) Reset()         { *m = NewUserRequest{} }
+func (m *NewUserRequest) String() string { return proto.CompactTextString(m) }
+func (*NewUserRequest) ProtoMessage()    {}
+func (*NewUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{2}
+}
+
+func (m *NewUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewUserRequest.Unmarshal(m, b)
+}
+func (m *NewUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewUserRequest.Marshal(b, m, deterministic)
+}
+func (m *NewUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewUserRequest.Merge(m, src)
+}
+func (m *NewUserRequest) XXX_Size() int {
+	return xxx_messageInfo_NewUserRequest.Size(m)
+}
+func (m *NewUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewUserRequest proto.InternalMessageInfo
+
+func (m *NewUserRequest) GetUsernameConfig() *UsernameConfig {
+	if m != nil {
+		return m.UsernameConfig
+	}
+	return nil
+}
+
+func (m *NewUserRequest) GetCredentialType() CredentialType {
+	if m != nil {
+		return m.CredentialType
+	}
+	return CredentialType_PASSWORD
+}
+
+func (m *NewUserRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *NewUserRequest) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *NewUserRequest) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *NewUserRequest) GetExpiration() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expiration
+	}
+	return nil
+}
+
+func (m *NewUserRequest) GetStatements() *Statements {
+	if m != nil {
+		return m.Statements
+	}
+	return nil
+}
+
+func (m *NewUserRequest) GetRollbackStatements() *Statements {
+	if m != nil {
+		return m.RollbackStatements
+	}
+	return nil
+}
+
+type UsernameConfig struct {
+	DisplayName          string   `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	RoleName             string   `protobuf:"bytes,2,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"`
+	Username             string   `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsernameConfig) Reset()         { *m = UsernameConfig{} }
+func (m *UsernameConfig) String() string { return proto.CompactTextString(m) }
+func (*UsernameConfig) ProtoMessage()    {}
+func (*UsernameConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{3}
+}
+
+func (m *UsernameConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsernameConfig.Unmarshal(m, b)
+}
+func (m *UsernameConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsernameConfig.Marshal(b, m, deterministic)
+}
+func (m *UsernameConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsernameConfig.Merge(m, src)
+}
+func (m *UsernameConfig) XXX_Size() int {
+	return xxx_messageInfo_UsernameConfig.Size(m)
+}
+func (m *UsernameConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsernameConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsernameConfig proto.InternalMessageInfo
+
+func (m *UsernameConfig) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *UsernameConfig) GetRoleName() string {
+	if m != nil {
+		return m.RoleName
+	}
+	return ""
+}
+
+func (m *UsernameConfig) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type NewUserResponse struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewUserResponse) Reset()         { *m = NewUserResponse{} }
+func (m *NewUserResponse) String() string { return proto.CompactTextString(m) }
+func (*NewUserResponse) ProtoMessage()    {}
+func (*NewUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{4}
+}
+
+func (m *NewUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewUserResponse.Unmarshal(m, b)
+}
+func (m *NewUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewUserResponse.Marshal(b, m, deterministic)
+}
+func (m *NewUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewUserResponse.Merge(m, src)
+}
+func (m *NewUserResponse) XXX_Size() int {
+	return xxx_messageInfo_NewUserResponse.Size(m)
+}
+func (m *NewUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewUserResponse proto.InternalMessageInfo
+
+func (m *NewUserResponse) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type UpdateUserRequest struct {
+	Username             string            `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	CredentialType       CredentialType    `protobuf:"varint,2,opt,name=credential_type,json=credentialType,proto3,enum=dbplugin.v5.CredentialType" json:"credential_type,omitempty"`
+	Password             *ChangePassword   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	PublicKey            *ChangePublicKey  `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Expiration           *ChangeExpiration `protobuf:"bytes,5,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *UpdateUserRequest) Reset()         { *m = UpdateUserRequest{} }
+func (m *UpdateUserRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateUserRequest) ProtoMessage()    {}
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{5}
+}
+
+func (m *UpdateUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateUserRequest.Unmarshal(m, b)
+}
+func (m *UpdateUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateUserRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateUserRequest.Merge(m, src)
+}
+func (m *UpdateUserRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateUserRequest.Size(m)
+}
+func (m *UpdateUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateUserRequest proto.InternalMessageInfo
+
+func (m *UpdateUserRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *UpdateUserRequest) GetCredentialType() CredentialType {
+	if m != nil {
+		return m.CredentialType
+	}
+	return CredentialType_PASSWORD
+}
+
+func (m *UpdateUserRequest) GetPassword() *ChangePassword {
+	if m != nil {
+		return m.Password
+	}
+	return nil
+}
+
+func (m *UpdateUserRequest) GetPublicKey() *ChangePublicKey {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *UpdateUserRequest) GetExpiration() *ChangeExpiration {
+	if m != nil {
+		return m.Expiration
+	}
+	return nil
+}
+
+type ChangePassword struct {
+	NewPassword          string      `protobuf:"bytes,1,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	Statements           *Statements `protobuf:"bytes,2,opt,name=statements,proto3" json:"statements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ChangePassword) Reset()         { *m = ChangePassword{} }
+func (m *ChangePassword) String() string { return proto.CompactTextString(m) }
+func (*ChangePassword) ProtoMessage()    {}
+func (*ChangePassword) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{6}
+}
+
+func (m *ChangePassword) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangePassword.Unmarshal(m, b)
+}
+func (m *ChangePassword) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangePassword.Marshal(b, m, deterministic)
+}
+func (m *ChangePassword) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangePassword.Merge(m, src)
+}
+func (m *ChangePassword) XXX_Size() int {
+	return xxx_messageInfo_ChangePassword.Size(m)
+}
+func (m *ChangePassword) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangePassword.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangePassword proto.InternalMessageInfo
+
+func (m *ChangePassword) GetNewPassword() string {
+	if m != nil {
+		return m.NewPassword
+	}
+	return ""
+}
+
+func (m *ChangePassword) GetStatements() *Statements {
+	if m != nil {
+		return m.Statements
+	}
+	return nil
+}
+
+type ChangePublicKey struct {
+	NewPublicKey         []byte      `protobuf:"bytes,1,opt,name=new_public_key,json=newPublicKey,proto3" json:"new_public_key,omitempty"`
+	Statements           *Statements `protobuf:"bytes,2,opt,name=statements,proto3" json:"statements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ChangePublicKey) Reset()         { *m = ChangePublicKey{} }
+func (m *ChangePublicKey) String() string { return proto.CompactTextString(m) }
+func (*ChangePublicKey) ProtoMessage()    {}
+func (*ChangePublicKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{7}
+}
+
+func (m *ChangePublicKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangePublicKey.Unmarshal(m, b)
+}
+func (m *ChangePublicKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangePublicKey.Marshal(b, m, deterministic)
+}
+func (m *ChangePublicKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangePublicKey.Merge(m, src)
+}
+func (m *ChangePublicKey) XXX_Size() int {
+	return xxx_messageInfo_ChangePublicKey.Size(m)
+}
+func (m *ChangePublicKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangePublicKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangePublicKey proto.InternalMessageInfo
+
+func (m *ChangePublicKey) GetNewPublicKey() []byte {
+	if m != nil {
+		return m.NewPublicKey
+	}
+	return nil
+}
+
+func (m *ChangePublicKey) GetStatements() *Statements {
+	if m != nil {
+		return m.Statements
+	}
+	return nil
+}
+
+type ChangeExpiration struct {
+	NewExpiration        *timestamp.Timestamp `protobuf:"bytes,1,opt,name=new_expiration,json=newExpiration,proto3" json:"new_expiration,omitempty"`
+	Statements           *Statements          `protobuf:"bytes,2,opt,name=statements,proto3" json:"statements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ChangeExpiration) Reset()         { *m = ChangeExpiration{} }
+func (m *ChangeExpiration) String() string { return proto.CompactTextString(m) }
+func (*ChangeExpiration) ProtoMessage()    {}
+func (*ChangeExpiration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{8}
+}
+
+func (m *ChangeExpiration) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeExpiration.Unmarshal(m, b)
+}
+func (m *ChangeExpiration) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeExpiration.Marshal(b, m, deterministic)
+}
+func (m *ChangeExpiration) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeExpiration.Merge(m, src)
+}
+func (m *ChangeExpiration) XXX_Size() int {
+	return xxx_messageInfo_ChangeExpiration.Size(m)
+}
+func (m *ChangeExpiration) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeExpiration.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeExpiration proto.InternalMessageInfo
+
+func (m *ChangeExpiration) GetNewExpiration() *timestamp.Timestamp {
+	if m != nil {
+		return m.NewExpiration
+	}
+	return nil
+}
+
+func (m *ChangeExpiration) GetStatements() *Statements {
+	if m != nil {
+		return m.Statements
+	}
+	return nil
+}
+
+type UpdateUserResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateUserResponse) Reset()         { *m = UpdateUserResponse{} }
+func (m *UpdateUserResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateUserResponse) ProtoMessage()    {}
+func (*UpdateUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{9}
+}
+
+func (m *UpdateUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateUserResponse.Unmarshal(m, b)
+}
+func (m *UpdateUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateUserResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateUserResponse.Merge(m, src)
+}
+func (m *UpdateUserResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateUserResponse.Size(m)
+}
+func (m *UpdateUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateUserResponse proto.InternalMessageInfo
+
+type DeleteUserRequest struct {
+	Username             string      `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Statements           *Statements `protobuf:"bytes,2,opt,name=statements,proto3" json:"statements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *DeleteUserRequest) Reset()         { *m = DeleteUserRequest{} }
+func (m *DeleteUserRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteUserRequest) ProtoMessage()    {}
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{10}
+}
+
+func (m *DeleteUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteUserRequest.Unmarshal(m, b)
+}
+func (m *DeleteUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteUserRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteUserRequest.Merge(m, src)
+}
+func (m *DeleteUserRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteUserRequest.Size(m)
+}
+func (m *DeleteUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteUserRequest proto.InternalMessageInfo
+
+func (m *DeleteUserRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *DeleteUserRequest) GetStatements() *Statements {
+	if m != nil {
+		return m.Statements
+	}
+	return nil
+}
+
+type DeleteUserResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteUserResponse) Reset()         { *m = DeleteUserResponse{} }
+func (m *DeleteUserResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteUserResponse) ProtoMessage()    {}
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{11}
+}
+
+func (m *DeleteUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteUserResponse.Unmarshal(m, b)
+}
+func (m *DeleteUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteUserResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteUserResponse.Merge(m, src)
+}
+func (m *DeleteUserResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteUserResponse.Size(m)
+}
+func (m *DeleteUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteUserResponse proto.InternalMessageInfo
+
+type TypeResponse struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TypeResponse) Reset()         { *m = TypeResponse{} }
+func (m *TypeResponse) String() string { return proto.CompactTextString(m) }
+func (*TypeResponse) ProtoMessage()    {}
+func (*TypeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{12}
+}
+
+func (m *TypeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TypeResponse.Unmarshal(m, b)
+}
+func (m *TypeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TypeResponse.Marshal(b, m, deterministic)
+}
+func (m *TypeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TypeResponse.Merge(m, src)
+}
+func (m *TypeResponse) XXX_Size() int {
+	return xxx_messageInfo_TypeResponse.Size(m)
+}
+func (m *TypeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TypeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TypeResponse proto.InternalMessageInfo
+
+func (m *TypeResponse) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type Statements struct {
+	Commands             []string `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Statements) Reset()         { *m = Statements{} }
+func (m *Statements) String() string { return proto.CompactTextString(m) }
+func (*Statements) ProtoMessage()    {}
+func (*Statements) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{13}
+}
+
+func (m *Statements) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Statements.Unmarshal(m, b)
+}
+func (m *Statements) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Statements.Marshal(b, m, deterministic)
+}
+func (m *Statements) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Statements.Merge(m, src)
+}
+func (m *Statements) XXX_Size() int {
+	return xxx_messageInfo_Statements.Size(m)
+}
+func (m *Statements) XXX_DiscardUnknown() {
+	xxx_messageInfo_Statements.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Statements proto.InternalMessageInfo
+
+func (m *Statements) GetCommands() []string {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0412d9bf52f894bd, []int{14}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("dbplugin.v5.CredentialType", CredentialType_name, CredentialType_value)
+	proto.RegisterType((*InitializeRequest)(nil), "dbplugin.v5.InitializeRequest")
+	proto.RegisterType((*InitializeResponse)(nil), "dbplugin.v5.InitializeResponse")
+	proto.RegisterType((*NewUserRequest)(nil), "dbplugin.v5.NewUserRequest")
+	proto.RegisterType((*UsernameConfig)(nil), "dbplugin.v5.UsernameConfig")
+	proto.RegisterType((*NewUserResponse)(nil), "dbplugin.v5.NewUserResponse")
+	proto.RegisterType((*UpdateUserRequest)(nil), "dbplugin.v5.UpdateUserRequest")
+	proto.RegisterType((*ChangePassword)(nil), "dbplugin.v5.ChangePassword")
+	proto.RegisterType((*ChangePublicKey)(nil), "dbplugin.v5.ChangePublicKey")
+	proto.RegisterType((*ChangeExpiration)(nil), "dbplugin.v5.ChangeExpiration")
+	proto.RegisterType((*UpdateUserResponse)(nil), "dbplugin.v5.UpdateUserResponse")
+	proto.RegisterType((*DeleteUserRequest)(nil), "dbplugin.v5.DeleteUserRequest")
+	proto.RegisterType((*DeleteUserResponse)(nil), "dbplugin.v5.DeleteUserResponse")
+	proto.RegisterType((*TypeResponse)(nil), "dbplugin.v5.TypeResponse")
+	proto.RegisterType((*Statements)(nil), "dbplugin.v5.Statements")
+	proto.RegisterType((*Empty)(nil), "dbplugin.v5.Empty")
+}
+
+func init() {
+	proto.RegisterFile("sdk/database/dbplugin/v5/proto/database.proto", fileDescriptor_0412d9bf52f894bd)
+}
+
+var fileDescriptor_0412d9bf52f894bd = []byte{
+	// 855 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5b, 0x6f, 0xe3, 0x44,
+	0x14, 0x26, 0xbd, 0x6c, 0xd3, 0x93, 0x6e, 0x9a, 0xce, 0x22, 0x08, 0xde, 0x5d, 0x52, 0x2c, 0x90,
+	0x2a, 0xd0, 0xc6, 0x52, 0x51, 0xb5, 0x82, 0x15, 0x0f, 0xc1, 0x31, 0x22, 0x2a, 0x5b, 0x2a, 0x37,
+	0x5d, 0x04, 0x42, 0xb2, 0xc6, 0xf6, 0x69, 0x62, 0xea, 0x1b, 0x9e, 0x71, 0x42, 0xf8, 0x11, 0xfc,
+	0x0b, 0x5e, 0x78, 0xe2, 0x27, 0x22, 0x8f, 0xef, 0x6e, 0xd2, 0x5d, 0x60, 0x9f, 0xda, 0x73, 0xce,
+	0x77, 0x2e, 0x73, 0xbe, 0x6f, 0x3c, 0x81, 0x67, 0xcc, 0xbe, 0x55, 0x6c, 0xca, 0xa9, 0x49, 0x19,
+	0x2a, 0xb6, 0x19, 0xba, 0xf1, 0xcc, 0xf1, 0x95, 0xc5, 0x99, 0x12, 0x46, 0x01, 0x0f, 0x8a, 0xd0,
+	0x50, 0x98, 0xa4, 0x93, 0x23, 0x86, 0x8b, 0x33, 0x69, 0x30, 0x0b, 0x82, 0x99, 0x8b, 0x29, 0xd2,
+	0x8c, 0x6f, 0x14, 0xee, 0x78, 0xc8, 0x38, 0xf5, 0xc2, 0x14, 0x2d, 0x53, 0x38, 0x9a, 0xf8, 0x0e,
+	0x77, 0xa8, 0xeb, 0xfc, 0x8e, 0x3a, 0xfe, 0x1a, 0x23, 0xe3, 0x64, 0x00, 0x1d, 0x2b, 0xf0, 0x6f,
+	0x9c, 0x99, 0x91, 0xd4, 0xee, 0xb7, 0x8e, 0x5b, 0x27, 0x07, 0x3a, 0xa4, 0xae, 0x31, 0xe5, 0x94,
+	0x7c, 0x06, 0x47, 0x0b, 0x8c, 0x9c, 0x9b, 0x95, 0x61, 0x05, 0xbe, 0x8f, 0x16, 0x77, 0x02, 0xbf,
+	0xbf, 0x75, 0xdc, 0x3a, 0x69, 0xeb, 0xbd, 0x34, 0xa0, 0x16, 0x7e, 0xf9, 0x67, 0x20, 0xd5, 0x16,
+	0x2c, 0x0c, 0x7c, 0x86, 0xaf, 0xef, 0xf1, 0x09, 0x74, 0x19, 0x46, 0x0b, 0x8c, 0x8c, 0x05, 0x46,
+	0x2c, 0x6f, 0xb0, 0xaf, 0x3f, 0x4c, 0xbd, 0xaf, 0x52, 0xa7, 0xfc, 0xf7, 0x36, 0x74, 0x2f, 0x70,
+	0x79, 0xcd, 0x30, 0xca, 0xc7, 0x1f, 0xc3, 0x61, 0xcc, 0x30, 0xf2, 0xa9, 0x87, 0x46, 0x5a, 0x50,
+	0x94, 0xef, 0x9c, 0x3e, 0x1e, 0x56, 0x76, 0x33, 0xbc, 0xce, 0x30, 0xaa, 0x80, 0xe8, 0xdd, 0xb8,
+	0x66, 0x27, 0x55, 0xac, 0x08, 0x6d, 0xf4, 0x93, 0xc9, 0x0d, 0xbe, 0x0a, 0x51, 0x0c, 0xd0, 0x6d,
+	0x54, 0x51, 0x0b, 0xcc, 0x74, 0x15, 0xa2, 0xde, 0xb5, 0x6a, 0x36, 0x91, 0xa0, 0x1d, 0x52, 0xc6,
+	0x96, 0x41, 0x64, 0xf7, 0xb7, 0xc5, 0xfc, 0x85, 0x4d, 0x9e, 0x02, 0x84, 0xb1, 0xe9, 0x3a, 0x96,
+	0x71, 0x8b, 0xab, 0xfe, 0x8e, 0xd8, 0xc0, 0x7e, 0xea, 0x39, 0xc7, 0x15, 0xe9, 0xc3, 0x1e, 0x8b,
+	0xcd, 0x5f, 0xd0, 0xe2, 0xfd, 0x5d, 0x91, 0x99, 0x9b, 0xe4, 0x4b, 0x00, 0xfc, 0x2d, 0x74, 0x22,
+	0x2a, 0xf6, 0xfe, 0x40, 0x9c, 0x4d, 0x1a, 0xa6, 0x54, 0x0f, 0x73, 0xaa, 0x87, 0xd3, 0x9c, 0x6a,
+	0xbd, 0x82, 0x26, 0xcf, 0x01, 0x18, 0xa7, 0x1c, 0x3d, 0xf4, 0x39, 0xeb, 0xef, 0x89, 0xdc, 0xf7,
+	0x6b, 0x27, 0xba, 0x2a, 0xc2, 0x7a, 0x05, 0x4a, 0xbe, 0x85, 0x47, 0x51, 0xe0, 0xba, 0x26, 0xb5,
+	0x6e, 0x8d, 0x4a, 0x85, 0xf6, 0xfd, 0x15, 0x48, 0x9e, 0x53, 0xfa, 0x64, 0x17, 0xba, 0xf5, 0xdd,
+	0x93, 0x8f, 0xe0, 0xc0, 0x76, 0x58, 0xe8, 0xd2, 0x95, 0x91, 0x78, 0x05, 0x5d, 0xfb, 0x7a, 0x27,
+	0xf3, 0x5d, 0x50, 0x0f, 0xc9, 0x63, 0xd8, 0x8f, 0x02, 0x17, 0xd3, 0x78, 0xaa, 0x84, 0x76, 0xe2,
+	0x10, 0x41, 0x09, 0xda, 0x39, 0x7b, 0xf9, 0x96, 0x73, 0x5b, 0x7e, 0x06, 0x87, 0x85, 0x3e, 0x32,
+	0xed, 0x55, 0xe1, 0xad, 0x06, 0xfc, 0xaf, 0x2d, 0x38, 0xba, 0x0e, 0x6d, 0xca, 0xb1, 0x2a, 0xa9,
+	0x7b, 0x32, 0xde, 0x92, 0x50, 0x9e, 0x37, 0x84, 0xd2, 0x54, 0xab, 0x3a, 0xa7, 0xfe, 0x0c, 0x2f,
+	0x33, 0x48, 0x45, 0x45, 0x2f, 0xee, 0xa8, 0xa8, 0x73, 0xfa, 0x64, 0x5d, 0x6a, 0x2e, 0xac, 0xaa,
+	0xc6, 0xbe, 0xaa, 0x29, 0x69, 0x57, 0x24, 0x3f, 0x5d, 0x93, 0xac, 0x15, 0xa0, 0xaa, 0x98, 0x12,
+	0x26, 0xeb, 0x73, 0x25, 0x4c, 0xfa, 0xb8, 0x34, 0x8a, 0xa3, 0x64, 0x4c, 0xfa, 0xb8, 0x2c, 0x20,
+	0x75, 0x05, 0x6e, 0xbd, 0xb1, 0x02, 0xe5, 0x10, 0x0e, 0x1b, 0x47, 0x21, 0x1f, 0x43, 0x57, 0xb4,
+	0x2b, 0x17, 0x90, 0x7e, 0x48, 0x92, 0x21, 0x4a, 0xd4, 0x7f, 0xee, 0xf8, 0x47, 0x0b, 0x7a, 0xcd,
+	0x05, 0x90, 0x51, 0xda, 0xb3, 0xb2, 0xb7, 0xd6, 0x6b, 0x6f, 0xe0, 0x43, 0x1f, 0x97, 0xda, 0xa6,
+	0x4b, 0xf8, 0x2f, 0x06, 0x7a, 0x17, 0x48, 0x55, 0x9c, 0xa9, 0x9e, 0xe5, 0x39, 0x1c, 0x8d, 0xd1,
+	0xc5, 0x37, 0x97, 0xec, 0xff, 0xe9, 0x5f, 0xed, 0x94, 0xf5, 0x97, 0xe1, 0x40, 0x68, 0x3a, 0xbf,
+	0x5f, 0x04, 0x76, 0xc4, 0x35, 0x48, 0xdb, 0x8a, 0xff, 0xe5, 0x13, 0x80, 0xb2, 0x66, 0x32, 0x9c,
+	0x15, 0x78, 0x1e, 0xf5, 0x6d, 0xd6, 0x6f, 0x1d, 0x6f, 0x27, 0xc3, 0xe5, 0xb6, 0xbc, 0x07, 0xbb,
+	0x9a, 0x17, 0xf2, 0xd5, 0xa7, 0xe7, 0xd0, 0xad, 0x5f, 0x1a, 0x72, 0x00, 0xed, 0xcb, 0xd1, 0xd5,
+	0xd5, 0x0f, 0xdf, 0xeb, 0xe3, 0xde, 0x3b, 0xe4, 0x11, 0x1c, 0xea, 0x57, 0x23, 0xe3, 0x52, 0x9f,
+	0xbc, 0x1a, 0x4d, 0x35, 0xe3, 0x5c, 0xfb, 0xb1, 0xd7, 0x22, 0xef, 0x01, 0x51, 0xbf, 0x9b, 0x68,
+	0x17, 0x53, 0x43, 0xd5, 0xf4, 0xe9, 0xe4, 0x9b, 0x89, 0x3a, 0x9a, 0x6a, 0xbd, 0xad, 0xd3, 0x3f,
+	0xb7, 0xa1, 0x3d, 0xce, 0x5e, 0x4a, 0xf2, 0x12, 0xa0, 0x7c, 0x92, 0xc8, 0x87, 0xb5, 0x93, 0xdf,
+	0x79, 0x0e, 0xa5, 0xc1, 0xc6, 0x78, 0x76, 0xde, 0x31, 0xec, 0x65, 0x9f, 0x18, 0x52, 0xbf, 0xb4,
+	0xf5, 0x87, 0x49, 0x7a, 0xb2, 0x3e, 0x98, 0x55, 0x79, 0x09, 0x50, 0x72, 0xdb, 0x18, 0xea, 0xce,
+	0x17, 0x49, 0x1a, 0x6c, 0x8c, 0x97, 0xe5, 0x4a, 0xaa, 0x1a, 0xe5, 0xee, 0xa8, 0x45, 0x1a, 0x6c,
+	0x8c, 0x67, 0xe5, 0xce, 0x60, 0x47, 0x50, 0x40, 0x6a, 0x40, 0x41, 0x94, 0xf4, 0x41, 0xcd, 0x57,
+	0x93, 0x82, 0x02, 0xbb, 0xaa, 0x1b, 0xb0, 0xf5, 0x79, 0x6b, 0x7c, 0x5f, 0xbf, 0xf8, 0xe9, 0x8b,
+	0x99, 0xc3, 0xe7, 0xb1, 0x39, 0xb4, 0x02, 0x4f, 0x99, 0x53, 0x36, 0x77, 0xac, 0x20, 0x0a, 0x95,
+	0x05, 0x8d, 0x5d, 0xae, 0xdc, 0xff, 0x53, 0xc8, 0x7c, 0x20, 0xfe, 0x7c, 0xfe, 0xcf, 0x00, 0xbc,
+	0x2e, 0x14, 0x9e, 0x33, 0x09, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DatabaseClient is the client API for Database service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DatabaseClient interface {
+	Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error)
+	NewUser(ctx context.Context, in *NewUserRequest, opts ...grpc.CallOption) (*NewUserResponse, error)
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	Type(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypeResponse, error)
+	Close(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type databaseClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDatabaseClient(cc *grpc.ClientConn) DatabaseClient {
+	return &databaseClient{cc}
+}
+
+func (c *databaseClient) Initialize(ctx context.Context, in *InitializeRequest, opts ...grpc.CallOption) (*InitializeResponse, error) {
+	out := new(InitializeResponse)
+	err := c.cc.Invoke(ctx, "/dbplugin.v5.Database/Initialize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) NewUser(ctx context.Context, in *NewUserRequest, opts ...grpc.CallOption) (*NewUserResponse, error) {
+	out := new(NewUserResponse)
+	err := c.cc.Invoke(ctx, "/dbplugin.v5.Database/NewUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error) {
+	out := new(UpdateUserResponse)
+	err := c.cc.Invoke(ctx, "/dbplugin.v5.Database/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
+	out := new(DeleteUserResponse)
+	err := c.cc.Invoke(ctx, "/dbplugin.v5.Database/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) Type(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypeResponse, error) {
+	out := new(TypeResponse)
+	err := c.cc.Invoke(ctx, "/dbplugin.v5.Database/Type", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) Close(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/dbplugin.v5.Database/Close", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DatabaseServer is the server API for Database service.
+type DatabaseServer interface {
+	Initialize(context.Context, *InitializeRequest) (*InitializeResponse, error)
+	NewUser(context.Context, *NewUserRequest) (*NewUserResponse, error)
+	UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error)
+	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	Type(context.Context, *Empty) (*TypeResponse, error)
+	Close(context.Context, *Empty) (*Empty, error)
+}
+
+// UnimplementedDatabaseServer can be embedded to have forward compatible implementations.
+type UnimplementedDatabaseServer struct {
+}
+
+func (*UnimplementedDatabaseServer) Initialize(ctx context.Context, req *InitializeRequest) (*InitializeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Initialize not implemented")
+}
+func (*UnimplementedDatabaseServer) NewUser(ctx context.Context, req *NewUserRequest) (*NewUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewUser not implemented")
+}
+func (*UnimplementedDatabaseServer) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*UpdateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (*UnimplementedDatabaseServer) DeleteUser(ctx context.Context, req *DeleteUserRequest) (*DeleteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (*UnimplementedDatabaseServer) Type(ctx context.Context, req *Empty) (*TypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Type not implemented")
+}
+func (*UnimplementedDatabaseServer) Close(ctx context.Context, req *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Close not implemented")
+}
+
+func RegisterDatabaseServer(s *grpc.Server, srv DatabaseServer) {
+	s.RegisterService(&_Database_serviceDesc, srv)
+}
+
+func _Database_Initialize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitializeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).Initialize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dbplugin.v5.Database/Initialize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).Initialize(ctx, req.(*InitializeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_NewUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).NewUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dbplugin.v5.Database/NewUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).NewUser(ctx, req.(*NewUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dbplugin.v5.Database/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dbplugin.v5.Database/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_Type_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).Type(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dbplugin.v5.Database/Type",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).Type(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_Close_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).Close(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dbplugin.v5.Database/Close",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).Close(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Database_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dbplugin.v5.Database",
+	HandlerType: (*DatabaseServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Initialize",
+			Handler:    _Database_Initialize_Handler,
+		},
+		{
+			MethodName: "NewUser",
+			Handler:    _Database_NewUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _Database_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _Database_DeleteUser_Handler,
+		},
+		{
+			MethodName: "Type",
+			Handler:    _Database_Type_Handler,
+		},
+		{
+			MethodName: "Close",
+			Handler:    _Database_Close_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sdk/database/dbplugin/v5/proto/database.proto",
+}
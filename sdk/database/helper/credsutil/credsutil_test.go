@@ -3,6 +3,8 @@ package credsutil
 import (
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/vault/sdk/database/dbplugin"
 )
 
 func TestRandomAlphaNumeric(t *testing.T) {
@@ -38,3 +40,42 @@ func TestRandomAlphaNumeric(t *testing.T) {
 		t.Fatalf("Expected %s not to contain %s", s, reqStr)
 	}
 }
+
+func TestSQLCredentialsProducer_GenerateUsername(t *testing.T) {
+	scp := &SQLCredentialsProducer{
+		DisplayNameLen: 8,
+		RoleNameLen:    8,
+		UsernameLen:    16,
+		Separator:      "-",
+	}
+
+	username, err := scp.GenerateUsername(dbplugin.UsernameConfig{
+		DisplayName: "token",
+		RoleName:    "readonly",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(username, "v-token-readonly") {
+		t.Fatalf("unexpected username %q", username)
+	}
+
+	username, err = scp.GenerateUsername(dbplugin.UsernameConfig{
+		DisplayName: "token",
+		RoleName:    "readonly",
+		Username:    "app_readonly",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "app_readonly" {
+		t.Fatalf("expected rendered username, got %q", username)
+	}
+
+	_, err = scp.GenerateUsername(dbplugin.UsernameConfig{
+		Username: "a_username_that_is_too_long",
+	})
+	if err == nil {
+		t.Fatal("expected error for username exceeding the maximum length")
+	}
+}
@@ -19,7 +19,16 @@ type SQLCredentialsProducer struct {
 	Separator      string
 }
 
+// GenerateUsername returns the username rendered by Vault if there is one,
+// and generates one from the display and role names otherwise.
 func (scp *SQLCredentialsProducer) GenerateUsername(config dbplugin.UsernameConfig) (string, error) {
+	if config.Username != "" {
+		if scp.UsernameLen > 0 && len(config.Username) > scp.UsernameLen {
+			return "", fmt.Errorf("username %q exceeds the maximum length of %d", config.Username, scp.UsernameLen)
+		}
+		return config.Username, nil
+	}
+
 	username := "v"
 
 	displayName := config.DisplayName
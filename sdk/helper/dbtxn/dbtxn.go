@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+
+	"github.com/hashicorp/errwrap"
 )
 
 // ExecuteDBQuery handles executing one single statement, while properly releasing its resources.
@@ -18,11 +20,11 @@ func ExecuteDBQuery(ctx context.Context, db *sql.DB, params map[string]string, q
 
 	stmt, err := db.PrepareContext(ctx, parsedQuery)
 	if err != nil {
-		return err
+		return StatementError(query, err)
 	}
 	defer stmt.Close()
 
-	return execute(ctx, stmt)
+	return StatementError(query, execute(ctx, stmt))
 }
 
 // ExecuteTxQuery handles executing one single statement, while properly releasing its resources.
@@ -36,11 +38,21 @@ func ExecuteTxQuery(ctx context.Context, tx *sql.Tx, params map[string]string, q
 
 	stmt, err := tx.PrepareContext(ctx, parsedQuery)
 	if err != nil {
-		return err
+		return StatementError(query, err)
 	}
 	defer stmt.Close()
 
-	return execute(ctx, stmt)
+	return StatementError(query, execute(ctx, stmt))
+}
+
+// StatementError wraps the error of executing a statement with the statement
+// as given, before any values are substituted into it, so the statement can be
+// reported without disclosing passwords. It returns nil if err is nil.
+func StatementError(statement string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return errwrap.Wrapf(fmt.Sprintf("error executing statement %q: {{err}}", statement), err)
 }
 
 func execute(ctx context.Context, stmt *sql.Stmt) error {
@@ -0,0 +1,138 @@
+package random
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/vault/sdk/helper/hclutil"
+)
+
+const (
+	// MaxLength is the longest string a policy may generate
+	MaxLength = 2048
+
+	ruleTypeCharset = "charset"
+)
+
+// CharsetRule requires a minimum number of characters from a charset. The
+// charsets of all rules make up the characters a policy generates strings
+// from.
+type CharsetRule struct {
+	Charset  string `hcl:"charset"`
+	MinChars int    `hcl:"min-chars"`
+}
+
+// ParsePolicy parses the HCL rules of a password policy, for example:
+//
+//	length = 20
+//	rule "charset" {
+//	  charset = "abcdefghijklmnopqrstuvwxyz"
+//	  min-chars = 1
+//	}
+//	rule "charset" {
+//	  charset = "0123456789"
+//	  min-chars = 1
+//	}
+func ParsePolicy(raw string) (*StringGenerator, error) {
+	root, err := hcl.Parse(raw)
+	if err != nil {
+		return nil, errwrap.Wrapf("failed to parse policy: {{err}}", err)
+	}
+
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse policy: does not contain a root object")
+	}
+
+	if err := hclutil.CheckHCLKeys(list, []string{"length", "rule"}); err != nil {
+		return nil, errwrap.Wrapf("failed to parse policy: {{err}}", err)
+	}
+
+	var policy struct {
+		Length int `hcl:"length"`
+	}
+	if err := hcl.DecodeObject(&policy, list); err != nil {
+		return nil, errwrap.Wrapf("failed to parse policy: {{err}}", err)
+	}
+
+	g := &StringGenerator{
+		Length: policy.Length,
+	}
+
+	for _, item := range list.Filter("rule").Items {
+		if len(item.Keys) != 1 {
+			return nil, fmt.Errorf("failed to parse policy: rule must have exactly one type")
+		}
+
+		ruleType := item.Keys[0].Token.Value().(string)
+		switch ruleType {
+		case ruleTypeCharset:
+			if err := hclutil.CheckHCLKeys(item.Val, []string{"charset", "min-chars"}); err != nil {
+				return nil, errwrap.Wrapf(fmt.Sprintf("failed to parse %q rule: {{err}}", ruleType), err)
+			}
+
+			var rule CharsetRule
+			if err := hcl.DecodeObject(&rule, item.Val); err != nil {
+				return nil, errwrap.Wrapf(fmt.Sprintf("failed to parse %q rule: {{err}}", ruleType), err)
+			}
+			g.Rules = append(g.Rules, rule)
+
+		default:
+			return nil, fmt.Errorf("failed to parse policy: unknown rule type %q", ruleType)
+		}
+	}
+
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// validate checks that the policy can generate strings and builds the
+// charset strings are generated from
+func (g *StringGenerator) validate() error {
+	if g.Length < 1 || g.Length > MaxLength {
+		return fmt.Errorf("length must be between 1 and %d", MaxLength)
+	}
+
+	if len(g.Rules) == 0 {
+		return fmt.Errorf("at least one charset rule is required")
+	}
+
+	seen := make(map[rune]bool)
+	g.charset = nil
+	minChars := 0
+	for _, rule := range g.Rules {
+		if rule.Charset == "" {
+			return fmt.Errorf("charset rules must have a non-empty charset")
+		}
+		if !utf8.ValidString(rule.Charset) {
+			return fmt.Errorf("charset %q is not valid UTF-8", rule.Charset)
+		}
+		if rule.MinChars < 0 {
+			return fmt.Errorf("min-chars of charset %q must not be negative", rule.Charset)
+		}
+		minChars += rule.MinChars
+
+		for _, r := range rule.Charset {
+			if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+				return fmt.Errorf("charset %q contains a non-printable or whitespace character", rule.Charset)
+			}
+			if !seen[r] {
+				seen[r] = true
+				g.charset = append(g.charset, r)
+			}
+		}
+	}
+
+	if minChars > g.Length {
+		return fmt.Errorf("the sum of min-chars (%d) exceeds the length (%d)", minChars, g.Length)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Package random generates random strings, such as passwords, according to
+// policies written in HCL.
+package random
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+// maxAttempts bounds the number of candidates generated for policies whose
+// rules are unlikely to be satisfied by chance
+const maxAttempts = 1000
+
+// StringGenerator generates random strings of a given length that satisfy
+// all of its rules
+type StringGenerator struct {
+	Length int
+	Rules  []CharsetRule
+
+	// charset is the union of the charsets of the rules
+	charset []rune
+}
+
+// Generate returns a random string that satisfies the policy. If rng is nil,
+// crypto/rand is used.
+func (g *StringGenerator) Generate(ctx context.Context, rng io.Reader) (string, error) {
+	if rng == nil {
+		rng = rand.Reader
+	}
+	if len(g.charset) == 0 {
+		if err := g.validate(); err != nil {
+			return "", err
+		}
+	}
+
+	candidate := make([]rune, g.Length)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		default:
+		}
+
+		for i := range candidate {
+			idx, err := randomIndex(rng, len(g.charset))
+			if err != nil {
+				return "", err
+			}
+			candidate[i] = g.charset[idx]
+		}
+
+		if g.satisfied(candidate) {
+			return string(candidate), nil
+		}
+	}
+
+	return "", errors.New("unable to generate a string that satisfies the policy")
+}
+
+// satisfied reports whether the value passes all rules
+func (g *StringGenerator) satisfied(value []rune) bool {
+	for _, rule := range g.Rules {
+		count := 0
+		for _, r := range value {
+			if strings.ContainsRune(rule.Charset, r) {
+				count++
+			}
+		}
+		if count < rule.MinChars {
+			return false
+		}
+	}
+	return true
+}
+
+// randomIndex returns a uniformly distributed index in [0, n)
+func randomIndex(rng io.Reader, n int) (int, error) {
+	// Avoid bias by rejecting values above the largest multiple of n
+	limit := uint32(1<<32 - (1<<32)%uint64(n))
+	var buf [4]byte
+	for {
+		if _, err := io.ReadFull(rng, buf[:]); err != nil {
+			return 0, err
+		}
+		v := binary.BigEndian.Uint32(buf[:])
+		if limit == 0 || v < limit {
+			return int(v % uint32(n)), nil
+		}
+	}
+}
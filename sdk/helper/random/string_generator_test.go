@@ -0,0 +1,117 @@
+package random
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	type testCase struct {
+		raw       string
+		expectErr bool
+	}
+
+	tests := map[string]testCase{
+		"valid": {
+			raw: `
+length = 20
+rule "charset" {
+  charset = "abcdefghijklmnopqrstuvwxyz"
+  min-chars = 1
+}
+rule "charset" {
+  charset = "0123456789"
+  min-chars = 2
+}`,
+		},
+		"no rules": {
+			raw:       `length = 20`,
+			expectErr: true,
+		},
+		"no length": {
+			raw:       `rule "charset" { charset = "abc" }`,
+			expectErr: true,
+		},
+		"too long": {
+			raw:       `length = 4096 rule "charset" { charset = "abc" }`,
+			expectErr: true,
+		},
+		"unknown rule": {
+			raw:       `length = 20 rule "foo" { charset = "abc" }`,
+			expectErr: true,
+		},
+		"unknown key": {
+			raw:       `length = 20 foo = "bar" rule "charset" { charset = "abc" }`,
+			expectErr: true,
+		},
+		"whitespace in charset": {
+			raw:       `length = 20 rule "charset" { charset = "a b" }`,
+			expectErr: true,
+		},
+		"min-chars exceed length": {
+			raw:       `length = 2 rule "charset" { charset = "abc" min-chars = 3 }`,
+			expectErr: true,
+		},
+		"invalid hcl": {
+			raw:       `length = `,
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePolicy(test.raw)
+			if test.expectErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !test.expectErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestStringGenerator_Generate(t *testing.T) {
+	g, err := ParsePolicy(`
+length = 12
+rule "charset" {
+  charset = "abcdefghijklmnopqrstuvwxyz"
+  min-chars = 2
+}
+rule "charset" {
+  charset = "!@#$%"
+  min-chars = 3
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		value, err := g.Generate(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(value) != 12 {
+			t.Fatalf("expected length 12, got %q", value)
+		}
+		if strings.Trim(value, "abcdefghijklmnopqrstuvwxyz!@#$%") != "" {
+			t.Fatalf("unexpected characters in %q", value)
+		}
+		symbols := 0
+		for _, r := range value {
+			if strings.ContainsRune("!@#$%", r) {
+				symbols++
+			}
+		}
+		if symbols < 3 {
+			t.Fatalf("expected at least 3 symbols in %q", value)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.Generate(ctx, nil); err == nil {
+		t.Fatal("expected error from canceled context")
+	}
+}
@@ -0,0 +1,92 @@
+// Package template renders Go templates with helper functions suited to
+// generating names, such as usernames of dynamic database credentials.
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+)
+
+// StringTemplate is a parsed template that renders to a string
+type StringTemplate struct {
+	tmpl *template.Template
+}
+
+// NewTemplate parses a template. Besides the standard functions of
+// text/template, templates may use:
+//
+//	truncate N VALUE - the first N characters of VALUE
+//	random N         - N random alphanumeric characters
+//	unix_time        - the current time in seconds since the epoch
+//	uppercase VALUE  - VALUE in upper case
+//	lowercase VALUE  - VALUE in lower case
+//	replace OLD NEW VALUE - VALUE with all occurrences of OLD replaced by NEW
+//	sha256 VALUE     - the hex encoded SHA-256 hash of VALUE
+func NewTemplate(raw string) (*StringTemplate, error) {
+	if raw == "" {
+		return nil, errors.New("missing template")
+	}
+
+	tmpl, err := template.New("template").
+		Funcs(funcs).
+		Option("missingkey=error").
+		Parse(raw)
+	if err != nil {
+		return nil, errwrap.Wrapf("unable to parse template: {{err}}", err)
+	}
+
+	return &StringTemplate{
+		tmpl: tmpl,
+	}, nil
+}
+
+// Generate renders the template with the given data
+func (t *StringTemplate) Generate(data interface{}) (string, error) {
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", errwrap.Wrapf("unable to render template: {{err}}", err)
+	}
+	return sb.String(), nil
+}
+
+var funcs = template.FuncMap{
+	"truncate":  truncate,
+	"random":    base62.Random,
+	"unix_time": unixTime,
+	"uppercase": strings.ToUpper,
+	"lowercase": strings.ToLower,
+	"replace":   replace,
+	"sha256":    hashSHA256,
+}
+
+func truncate(maxLen int, value string) (string, error) {
+	if maxLen < 0 {
+		return "", fmt.Errorf("truncate length must not be negative, got %d", maxLen)
+	}
+	if len(value) > maxLen {
+		return value[:maxLen], nil
+	}
+	return value, nil
+}
+
+func unixTime() string {
+	return strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+func replace(old, new, value string) string {
+	return strings.Replace(value, old, new, -1)
+}
+
+func hashSHA256(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package template
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestStringTemplate_Generate(t *testing.T) {
+	type testCase struct {
+		raw       string
+		data      interface{}
+		expected  string
+		pattern   string
+		expectErr bool
+	}
+
+	data := map[string]string{
+		"DisplayName": "token-my.user",
+		"RoleName":    "readonly",
+	}
+
+	tests := map[string]testCase{
+		"plain": {
+			raw:      "v_{{.RoleName}}",
+			data:     data,
+			expected: "v_readonly",
+		},
+		"truncate and uppercase": {
+			raw:      "{{.DisplayName | truncate 8 | uppercase}}_{{.RoleName | truncate 4}}",
+			data:     data,
+			expected: "TOKEN-MY_read",
+		},
+		"replace and lowercase": {
+			raw:      `{{.DisplayName | replace "." "_" | replace "-" "_" | lowercase}}`,
+			data:     data,
+			expected: "token_my_user",
+		},
+		"sha256": {
+			raw:      "{{.RoleName | sha256 | truncate 10}}",
+			data:     data,
+			expected: "8171bacf32",
+		},
+		"random and unix_time": {
+			raw:     "{{random 12}}-{{unix_time}}",
+			data:    data,
+			pattern: `^[a-zA-Z0-9]{12}-[0-9]+$`,
+		},
+		"missing field": {
+			raw:       "{{.Missing}}",
+			data:      data,
+			expectErr: true,
+		},
+		"negative truncate": {
+			raw:       "{{.RoleName | truncate -1}}",
+			data:      data,
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := NewTemplate(test.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := tmpl.Generate(test.data)
+			if test.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.pattern != "" {
+				if !regexp.MustCompile(test.pattern).MatchString(actual) {
+					t.Fatalf("%q does not match %q", actual, test.pattern)
+				}
+				return
+			}
+			if actual != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewTemplate_invalid(t *testing.T) {
+	for _, raw := range []string{"", "{{.RoleName", "{{unknown_func}}"} {
+		if _, err := NewTemplate(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
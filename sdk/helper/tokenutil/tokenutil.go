@@ -18,6 +18,10 @@ type TokenParams struct {
 	// The set of CIDRs that tokens generated using this role will be bound to
 	TokenBoundCIDRs []*sockaddr.SockAddrMarshaler `json:"token_bound_cidrs"`
 
+	// If set, tokens generated using this role will be bound to the TLS
+	// client certificate presented when they are issued
+	TokenBindClientCert bool `json:"token_bind_client_cert" mapstructure:"token_bind_client_cert"`
+
 	// If set, the token entry will have an explicit maximum TTL set, rather
 	// than deferring to role/mount values
 	TokenExplicitMaxTTL time.Duration `json:"token_explicit_max_ttl" mapstructure:"token_explicit_max_ttl"`
@@ -75,6 +79,11 @@ func TokenFields() map[string]*framework.FieldSchema {
 			Description: `Comma separated string or JSON list of CIDR blocks. If set, specifies the blocks of IP addresses which are allowed to use the generated token.`,
 		},
 
+		"token_bind_client_cert": &framework.FieldSchema{
+			Type:        framework.TypeBool,
+			Description: "If true, the generated token can only be used over TLS connections presenting the client certificate it was issued to",
+		},
+
 		"token_explicit_max_ttl": &framework.FieldSchema{
 			Type:        framework.TypeDurationSecond,
 			Description: tokenExplicitMaxTTLHelp,
@@ -128,6 +137,10 @@ func (t *TokenParams) ParseTokenFields(req *logical.Request, d *framework.FieldD
 		t.TokenBoundCIDRs = boundCIDRs
 	}
 
+	if bindClientCertRaw, ok := d.GetOk("token_bind_client_cert"); ok {
+		t.TokenBindClientCert = bindClientCertRaw.(bool)
+	}
+
 	if explicitMaxTTLRaw, ok := d.GetOk("token_explicit_max_ttl"); ok {
 		t.TokenExplicitMaxTTL = time.Duration(explicitMaxTTLRaw.(int)) * time.Second
 	}
@@ -179,6 +192,9 @@ func (t *TokenParams) ParseTokenFields(req *logical.Request, d *framework.FieldD
 		if t.TokenNumUses != 0 {
 			return errors.New("'token_type' cannot be 'batch' or 'default_batch' when set to generate tokens with limited use count")
 		}
+		if t.TokenBindClientCert {
+			return errors.New("'token_type' cannot be 'batch' or 'default_batch' when set to bind tokens to client certificates")
+		}
 	}
 
 	if ttlRaw, ok := d.GetOk("token_ttl"); ok {
@@ -204,6 +220,7 @@ func (t *TokenParams) ParseTokenFields(req *logical.Request, d *framework.FieldD
 // PopulateTokenData adds information from TokenParams into the map
 func (t *TokenParams) PopulateTokenData(m map[string]interface{}) {
 	m["token_bound_cidrs"] = t.TokenBoundCIDRs
+	m["token_bind_client_cert"] = t.TokenBindClientCert
 	m["token_explicit_max_ttl"] = int64(t.TokenExplicitMaxTTL.Seconds())
 	m["token_max_ttl"] = int64(t.TokenMaxTTL.Seconds())
 	m["token_no_default_policy"] = t.TokenNoDefaultPolicy
@@ -225,6 +242,7 @@ func (t *TokenParams) PopulateTokenData(m map[string]interface{}) {
 // PopulateTokenAuth populates Auth with parameters
 func (t *TokenParams) PopulateTokenAuth(auth *logical.Auth) {
 	auth.BoundCIDRs = t.TokenBoundCIDRs
+	auth.BindClientCert = t.TokenBindClientCert
 	auth.ExplicitMaxTTL = t.TokenExplicitMaxTTL
 	auth.MaxTTL = t.TokenMaxTTL
 	auth.NoDefaultPolicy = t.TokenNoDefaultPolicy
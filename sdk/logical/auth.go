@@ -89,6 +89,14 @@ type Auth struct {
 	// The set of CIDRs that this token can be used with
 	BoundCIDRs []*sockaddr.SockAddrMarshaler `json:"bound_cidrs"`
 
+	// BindClientCert, if set, binds the token to the TLS client certificate
+	// presented on the login connection
+	BindClientCert bool `json:"bind_client_cert"`
+
+	// BoundCertFingerprint is the SHA-256 fingerprint of the client
+	// certificate the token is bound to. This is set by core on login.
+	BoundCertFingerprint string `json:"bound_cert_fingerprint"`
+
 	// CreationPath is a path that the backend can return to use in the lease.
 	// This is currently only supported for the token store where roles may
 	// change the perceived path of the lease, even though they don't change
@@ -100,6 +108,10 @@ type Auth struct {
 
 	// Orphan is set if the token does not have a parent
 	Orphan bool `json:"orphan"`
+
+	// MFAValidated is set when the login that produced this Auth was
+	// completed with a second factor
+	MFAValidated bool `json:"mfa_validated"`
 }
 
 func (a *Auth) GoString() string {
@@ -25,6 +25,14 @@ var (
 	// response from an upstream
 	ErrUpstreamRateLimited = errors.New("upstream rate limited")
 
+	// ErrLeaseCountQuotaExceeded is returned when a request would create a
+	// lease beyond a configured lease count quota
+	ErrLeaseCountQuotaExceeded = errors.New("lease count quota exceeded")
+
+	// ErrRateLimitQuotaExceeded is returned when a request is rejected by a
+	// configured rate limit quota
+	ErrRateLimitQuotaExceeded = errors.New("rate limit quota exceeded")
+
 	// ErrPerfStandbyForward is returned when Vault is in a state such that a
 	// perf standby cannot satisfy a request
 	ErrPerfStandbyPleaseForward = errors.New("please forward to the active node")
@@ -113,6 +113,7 @@ type Paths struct {
 	Root []string
 
 	// Unauthenticated are the paths that can be accessed without any auth.
+	// A "+" path segment matches any single segment, e.g. "role/+/login".
 	Unauthenticated []string
 
 	// LocalStorage are paths (prefixes) that are local to this instance; this
@@ -3,6 +3,7 @@ package logical
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/vault/sdk/helper/consts"
@@ -68,6 +69,10 @@ type SystemView interface {
 
 	// PluginEnv returns Vault environment information used by plugins
 	PluginEnv(context.Context) (*PluginEnvironment, error)
+
+	// GeneratePasswordFromPolicy generates a password using the password
+	// policy with the given name
+	GeneratePasswordFromPolicy(ctx context.Context, policyName string) (string, error)
 }
 
 type ExtendedSystemView interface {
@@ -89,8 +94,13 @@ type StaticSystemView struct {
 	Features            license.Features
 	VaultVersion        string
 	PluginEnvironment   *PluginEnvironment
+	PasswordPolicies    map[string]PasswordGenerator
 }
 
+// PasswordGenerator generates a password for a password policy of a
+// StaticSystemView
+type PasswordGenerator func() (string, error)
+
 type noopAuditor struct{}
 
 func (a noopAuditor) AuditRequest(ctx context.Context, input *LogInput) error {
@@ -160,3 +170,11 @@ func (d StaticSystemView) HasFeature(feature license.Features) bool {
 func (d StaticSystemView) PluginEnv(_ context.Context) (*PluginEnvironment, error) {
 	return d.PluginEnvironment, nil
 }
+
+func (d StaticSystemView) GeneratePasswordFromPolicy(_ context.Context, policyName string) (string, error) {
+	generator, ok := d.PasswordPolicies[policyName]
+	if !ok {
+		return "", fmt.Errorf("password policy %q not found", policyName)
+	}
+	return generator()
+}
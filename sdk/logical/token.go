@@ -107,6 +107,10 @@ type TokenEntry struct {
 	// The set of CIDRs that this token can be used with
 	BoundCIDRs []*sockaddr.SockAddrMarshaler `json:"bound_cidrs" sentinel:""`
 
+	// BoundCertFingerprint is the hex-encoded SHA-256 fingerprint of the TLS
+	// client certificate that must be presented along with this token
+	BoundCertFingerprint string `json:"bound_cert_fingerprint" mapstructure:"bound_cert_fingerprint" structs:"bound_cert_fingerprint" sentinel:""`
+
 	// NamespaceID is the identifier of the namespace to which this token is
 	// confined to. Do not return this value over the API when the token is
 	// being looked up.
@@ -115,6 +119,10 @@ type TokenEntry struct {
 	// CubbyholeID is the identifier of the cubbyhole storage belonging to this
 	// token
 	CubbyholeID string `json:"cubbyhole_id" mapstructure:"cubbyhole_id" structs:"cubbyhole_id" sentinel:""`
+
+	// MFAValidated is set when the token was issued by a login completed
+	// with a second factor
+	MFAValidated bool `json:"mfa_validated" mapstructure:"mfa_validated" structs:"mfa_validated" sentinel:""`
 }
 
 func (te *TokenEntry) SentinelGet(key string) (interface{}, error) {
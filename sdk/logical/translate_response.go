@@ -28,17 +28,18 @@ func LogicalResponseToHTTPResponse(input *Response) *HTTPResponse {
 	// set up the result structure.
 	if input.Auth != nil {
 		httpResp.Auth = &HTTPAuth{
-			ClientToken:      input.Auth.ClientToken,
-			Accessor:         input.Auth.Accessor,
-			Policies:         input.Auth.Policies,
-			TokenPolicies:    input.Auth.TokenPolicies,
-			IdentityPolicies: input.Auth.IdentityPolicies,
-			Metadata:         input.Auth.Metadata,
-			LeaseDuration:    int(input.Auth.TTL.Seconds()),
-			Renewable:        input.Auth.Renewable,
-			EntityID:         input.Auth.EntityID,
-			TokenType:        input.Auth.TokenType.String(),
-			Orphan:           input.Auth.Orphan,
+			ClientToken:          input.Auth.ClientToken,
+			Accessor:             input.Auth.Accessor,
+			Policies:             input.Auth.Policies,
+			TokenPolicies:        input.Auth.TokenPolicies,
+			IdentityPolicies:     input.Auth.IdentityPolicies,
+			Metadata:             input.Auth.Metadata,
+			LeaseDuration:        int(input.Auth.TTL.Seconds()),
+			Renewable:            input.Auth.Renewable,
+			EntityID:             input.Auth.EntityID,
+			TokenType:            input.Auth.TokenType.String(),
+			Orphan:               input.Auth.Orphan,
+			BoundCertFingerprint: input.Auth.BoundCertFingerprint,
 		}
 	}
 
@@ -62,14 +63,15 @@ func HTTPResponseToLogicalResponse(input *HTTPResponse) *Response {
 
 	if input.Auth != nil {
 		logicalResp.Auth = &Auth{
-			ClientToken:      input.Auth.ClientToken,
-			Accessor:         input.Auth.Accessor,
-			Policies:         input.Auth.Policies,
-			TokenPolicies:    input.Auth.TokenPolicies,
-			IdentityPolicies: input.Auth.IdentityPolicies,
-			Metadata:         input.Auth.Metadata,
-			EntityID:         input.Auth.EntityID,
-			Orphan:           input.Auth.Orphan,
+			ClientToken:          input.Auth.ClientToken,
+			Accessor:             input.Auth.Accessor,
+			Policies:             input.Auth.Policies,
+			TokenPolicies:        input.Auth.TokenPolicies,
+			IdentityPolicies:     input.Auth.IdentityPolicies,
+			Metadata:             input.Auth.Metadata,
+			EntityID:             input.Auth.EntityID,
+			Orphan:               input.Auth.Orphan,
+			BoundCertFingerprint: input.Auth.BoundCertFingerprint,
 		}
 		logicalResp.Auth.Renewable = input.Auth.Renewable
 		logicalResp.Auth.TTL = time.Second * time.Duration(input.Auth.LeaseDuration)
@@ -97,17 +99,18 @@ type HTTPResponse struct {
 }
 
 type HTTPAuth struct {
-	ClientToken      string            `json:"client_token"`
-	Accessor         string            `json:"accessor"`
-	Policies         []string          `json:"policies"`
-	TokenPolicies    []string          `json:"token_policies,omitempty"`
-	IdentityPolicies []string          `json:"identity_policies,omitempty"`
-	Metadata         map[string]string `json:"metadata"`
-	LeaseDuration    int               `json:"lease_duration"`
-	Renewable        bool              `json:"renewable"`
-	EntityID         string            `json:"entity_id"`
-	TokenType        string            `json:"token_type"`
-	Orphan           bool              `json:"orphan"`
+	ClientToken          string            `json:"client_token"`
+	Accessor             string            `json:"accessor"`
+	Policies             []string          `json:"policies"`
+	TokenPolicies        []string          `json:"token_policies,omitempty"`
+	IdentityPolicies     []string          `json:"identity_policies,omitempty"`
+	Metadata             map[string]string `json:"metadata"`
+	LeaseDuration        int               `json:"lease_duration"`
+	Renewable            bool              `json:"renewable"`
+	EntityID             string            `json:"entity_id"`
+	TokenType            string            `json:"token_type"`
+	Orphan               bool              `json:"orphan"`
+	BoundCertFingerprint string            `json:"bound_cert_fingerprint,omitempty"`
 }
 
 type HTTPWrapInfo struct {
@@ -159,6 +159,20 @@ func (s *gRPCSystemViewClient) PluginEnv(ctx context.Context) (*logical.PluginEn
 	return reply.PluginEnvironment, nil
 }
 
+func (s *gRPCSystemViewClient) GeneratePasswordFromPolicy(ctx context.Context, policyName string) (string, error) {
+	reply, err := s.client.GeneratePasswordFromPolicy(ctx, &pb.GeneratePasswordFromPolicyRequest{
+		PolicyName: policyName,
+	})
+	if err != nil {
+		return "", err
+	}
+	if reply.Err != "" {
+		return "", errors.New(reply.Err)
+	}
+
+	return reply.Password, nil
+}
+
 type gRPCSystemViewServer struct {
 	impl logical.SystemView
 }
@@ -267,3 +281,15 @@ func (s *gRPCSystemViewServer) PluginEnv(ctx context.Context, _ *pb.Empty) (*pb.
 		PluginEnvironment: pluginEnv,
 	}, nil
 }
+
+func (s *gRPCSystemViewServer) GeneratePasswordFromPolicy(ctx context.Context, args *pb.GeneratePasswordFromPolicyRequest) (*pb.GeneratePasswordFromPolicyReply, error) {
+	password, err := s.impl.GeneratePasswordFromPolicy(ctx, args.PolicyName)
+	if err != nil {
+		return &pb.GeneratePasswordFromPolicyReply{
+			Err: pb.ErrToString(err),
+		}, nil
+	}
+	return &pb.GeneratePasswordFromPolicyReply{
+		Password: password,
+	}, nil
+}
@@ -231,3 +231,32 @@ func TestSystem_GRPC_pluginEnv(t *testing.T) {
 		t.Fatalf("expected: %v, got: %v", expected, actual)
 	}
 }
+
+func TestSystem_GRPC_generatePasswordFromPolicy(t *testing.T) {
+	sys := logical.TestSystemView()
+	sys.PasswordPolicies = map[string]logical.PasswordGenerator{
+		"testpolicy": func() (string, error) {
+			return "testpassword", nil
+		},
+	}
+	client, _ := plugin.TestGRPCConn(t, func(s *grpc.Server) {
+		pb.RegisterSystemViewServer(s, &gRPCSystemViewServer{
+			impl: sys,
+		})
+	})
+	defer client.Close()
+
+	testSystemView := newGRPCSystemView(client)
+
+	actual, err := testSystemView.GeneratePasswordFromPolicy(context.Background(), "testpolicy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != "testpassword" {
+		t.Fatalf("expected: testpassword, got: %s", actual)
+	}
+
+	if _, err := testSystemView.GeneratePasswordFromPolicy(context.Background(), "missing"); err == nil {
+		t.Fatal("expected error for missing policy")
+	}
+}
@@ -530,7 +530,10 @@ type Auth struct {
 	// TokenType is the type of token being requested
 	TokenType uint32 `sentinel:"" protobuf:"varint,17,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
 	// Whether the default policy should be added automatically by core
-	NoDefaultPolicy      bool     `sentinel:"" protobuf:"varint,18,opt,name=no_default_policy,json=noDefaultPolicy,proto3" json:"no_default_policy,omitempty"`
+	NoDefaultPolicy bool `sentinel:"" protobuf:"varint,18,opt,name=no_default_policy,json=noDefaultPolicy,proto3" json:"no_default_policy,omitempty"`
+	// BindClientCert, if set, binds the token to the TLS client certificate
+	// presented on login
+	BindClientCert       bool     `sentinel:"" protobuf:"varint,19,opt,name=bind_client_cert,json=bindClientCert,proto3" json:"bind_client_cert,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -687,6 +690,13 @@ func (m *Auth) GetNoDefaultPolicy() bool {
 	return false
 }
 
+func (m *Auth) GetBindClientCert() bool {
+	if m != nil {
+		return m.BindClientCert
+	}
+	return false
+}
+
 type TokenEntry struct {
 	ID                   string            `sentinel:"" protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Accessor             string            `sentinel:"" protobuf:"bytes,2,opt,name=accessor,proto3" json:"accessor,omitempty"`
@@ -2629,6 +2639,92 @@ func (m *PluginEnvReply) GetErr() string {
 	return ""
 }
 
+type GeneratePasswordFromPolicyRequest struct {
+	PolicyName           string   `sentinel:"" protobuf:"bytes,1,opt,name=policy_name,json=policyName,proto3" json:"policy_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeneratePasswordFromPolicyRequest) Reset()         { *m = GeneratePasswordFromPolicyRequest{} }
+func (m *GeneratePasswordFromPolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*GeneratePasswordFromPolicyRequest) ProtoMessage()    {}
+func (*GeneratePasswordFromPolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dbf1dfe0c11846b, []int{43}
+}
+
+func (m *GeneratePasswordFromPolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeneratePasswordFromPolicyRequest.Unmarshal(m, b)
+}
+func (m *GeneratePasswordFromPolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeneratePasswordFromPolicyRequest.Marshal(b, m, deterministic)
+}
+func (m *GeneratePasswordFromPolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeneratePasswordFromPolicyRequest.Merge(m, src)
+}
+func (m *GeneratePasswordFromPolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_GeneratePasswordFromPolicyRequest.Size(m)
+}
+func (m *GeneratePasswordFromPolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeneratePasswordFromPolicyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeneratePasswordFromPolicyRequest proto.InternalMessageInfo
+
+func (m *GeneratePasswordFromPolicyRequest) GetPolicyName() string {
+	if m != nil {
+		return m.PolicyName
+	}
+	return ""
+}
+
+type GeneratePasswordFromPolicyReply struct {
+	Password             string   `sentinel:"" protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	Err                  string   `sentinel:"" protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeneratePasswordFromPolicyReply) Reset()         { *m = GeneratePasswordFromPolicyReply{} }
+func (m *GeneratePasswordFromPolicyReply) String() string { return proto.CompactTextString(m) }
+func (*GeneratePasswordFromPolicyReply) ProtoMessage()    {}
+func (*GeneratePasswordFromPolicyReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dbf1dfe0c11846b, []int{44}
+}
+
+func (m *GeneratePasswordFromPolicyReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeneratePasswordFromPolicyReply.Unmarshal(m, b)
+}
+func (m *GeneratePasswordFromPolicyReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeneratePasswordFromPolicyReply.Marshal(b, m, deterministic)
+}
+func (m *GeneratePasswordFromPolicyReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeneratePasswordFromPolicyReply.Merge(m, src)
+}
+func (m *GeneratePasswordFromPolicyReply) XXX_Size() int {
+	return xxx_messageInfo_GeneratePasswordFromPolicyReply.Size(m)
+}
+func (m *GeneratePasswordFromPolicyReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeneratePasswordFromPolicyReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeneratePasswordFromPolicyReply proto.InternalMessageInfo
+
+func (m *GeneratePasswordFromPolicyReply) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *GeneratePasswordFromPolicyReply) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
 type Connection struct {
 	// RemoteAddr is the network address that sent the request.
 	RemoteAddr           string   `sentinel:"" protobuf:"bytes,1,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
@@ -2641,7 +2737,7 @@ func (m *Connection) Reset()         { *m = Connection{} }
 func (m *Connection) String() string { return proto.CompactTextString(m) }
 func (*Connection) ProtoMessage()    {}
 func (*Connection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4dbf1dfe0c11846b, []int{43}
+	return fileDescriptor_4dbf1dfe0c11846b, []int{45}
 }
 
 func (m *Connection) XXX_Unmarshal(b []byte) error {
@@ -2718,171 +2814,178 @@ func init() {
 	proto.RegisterType((*EntityInfoArgs)(nil), "pb.EntityInfoArgs")
 	proto.RegisterType((*EntityInfoReply)(nil), "pb.EntityInfoReply")
 	proto.RegisterType((*PluginEnvReply)(nil), "pb.PluginEnvReply")
+	proto.RegisterType((*GeneratePasswordFromPolicyRequest)(nil), "pb.GeneratePasswordFromPolicyRequest")
+	proto.RegisterType((*GeneratePasswordFromPolicyReply)(nil), "pb.GeneratePasswordFromPolicyReply")
 	proto.RegisterType((*Connection)(nil), "pb.Connection")
 }
 
 func init() { proto.RegisterFile("sdk/plugin/pb/backend.proto", fileDescriptor_4dbf1dfe0c11846b) }
 
 var fileDescriptor_4dbf1dfe0c11846b = []byte{
-	// 2519 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0xdb, 0x72, 0x1b, 0xc7,
-	0xd1, 0x2e, 0x00, 0xc4, 0xa9, 0x71, 0x22, 0x46, 0xb4, 0xfe, 0x15, 0x24, 0xff, 0x82, 0xd7, 0x91,
-	0x0c, 0x33, 0x36, 0x68, 0xd1, 0x71, 0x2c, 0x27, 0x65, 0xa7, 0x68, 0x8a, 0x96, 0x19, 0x93, 0x36,
-	0x6b, 0x09, 0xc7, 0x39, 0x55, 0xc1, 0x83, 0xdd, 0x21, 0xb8, 0xc5, 0xc5, 0xee, 0x66, 0x76, 0x96,
-	0x22, 0xae, 0xf2, 0x16, 0x79, 0x8d, 0xdc, 0xe6, 0x2e, 0x77, 0x29, 0x57, 0xee, 0xf3, 0x0a, 0xb9,
-	0xcc, 0x33, 0xa4, 0xa6, 0x67, 0xf6, 0x04, 0x80, 0x96, 0x5c, 0xe5, 0xdc, 0xcd, 0x74, 0xf7, 0x9c,
-	0x7a, 0xbe, 0xfe, 0xba, 0x67, 0x17, 0xee, 0x47, 0xce, 0xd5, 0x5e, 0xe8, 0xc5, 0x73, 0xd7, 0xdf,
-	0x0b, 0x67, 0x7b, 0x33, 0x6a, 0x5f, 0x31, 0xdf, 0x19, 0x87, 0x3c, 0x10, 0x01, 0x29, 0x87, 0xb3,
-	0xc1, 0xc3, 0x79, 0x10, 0xcc, 0x3d, 0xb6, 0x87, 0x92, 0x59, 0x7c, 0xb1, 0x27, 0xdc, 0x05, 0x8b,
-	0x04, 0x5d, 0x84, 0xca, 0x68, 0x30, 0x90, 0x33, 0x78, 0xc1, 0xdc, 0xb5, 0xa9, 0xb7, 0xe7, 0x3a,
-	0xcc, 0x17, 0xae, 0x58, 0x6a, 0x9d, 0x91, 0xd7, 0xa9, 0x55, 0x94, 0xc6, 0xac, 0x43, 0xf5, 0x68,
-	0x11, 0x8a, 0xa5, 0x39, 0x84, 0xda, 0xe7, 0x8c, 0x3a, 0x8c, 0x93, 0xbb, 0x50, 0xbb, 0xc4, 0x96,
-	0x51, 0x1a, 0x56, 0x46, 0x4d, 0x4b, 0xf7, 0xcc, 0x3f, 0x00, 0x9c, 0xc9, 0x31, 0x47, 0x9c, 0x07,
-	0x9c, 0xdc, 0x83, 0x06, 0xe3, 0x7c, 0x2a, 0x96, 0x21, 0x33, 0x4a, 0xc3, 0xd2, 0xa8, 0x63, 0xd5,
-	0x19, 0xe7, 0x93, 0x65, 0xc8, 0xc8, 0xff, 0x81, 0x6c, 0x4e, 0x17, 0xd1, 0xdc, 0x28, 0x0f, 0x4b,
-	0x72, 0x06, 0xc6, 0xf9, 0x69, 0x34, 0x4f, 0xc6, 0xd8, 0x81, 0xc3, 0x8c, 0xca, 0xb0, 0x34, 0xaa,
-	0xe0, 0x98, 0xc3, 0xc0, 0x61, 0xe6, 0x5f, 0x4a, 0x50, 0x3d, 0xa3, 0xe2, 0x32, 0x22, 0x04, 0xb6,
-	0x78, 0x10, 0x08, 0xbd, 0x38, 0xb6, 0xc9, 0x08, 0x7a, 0xb1, 0x4f, 0x63, 0x71, 0x29, 0x4f, 0x65,
-	0x53, 0xc1, 0x1c, 0xa3, 0x8c, 0xea, 0x55, 0x31, 0x79, 0x13, 0x3a, 0x5e, 0x60, 0x53, 0x6f, 0x1a,
-	0x89, 0x80, 0xd3, 0xb9, 0x5c, 0x47, 0xda, 0xb5, 0x51, 0x78, 0xae, 0x64, 0x64, 0x17, 0xfa, 0x11,
-	0xa3, 0xde, 0xf4, 0x05, 0xa7, 0x61, 0x6a, 0xb8, 0xa5, 0x26, 0x94, 0x8a, 0x6f, 0x38, 0x0d, 0xb5,
-	0xad, 0xf9, 0xf7, 0x1a, 0xd4, 0x2d, 0xf6, 0xa7, 0x98, 0x45, 0x82, 0x74, 0xa1, 0xec, 0x3a, 0x78,
-	0xda, 0xa6, 0x55, 0x76, 0x1d, 0x32, 0x06, 0x62, 0xb1, 0xd0, 0x93, 0x4b, 0xbb, 0x81, 0x7f, 0xe8,
-	0xc5, 0x91, 0x60, 0x5c, 0x9f, 0x79, 0x83, 0x86, 0x3c, 0x80, 0x66, 0x10, 0x32, 0x8e, 0x32, 0x74,
-	0x40, 0xd3, 0xca, 0x04, 0xf2, 0xe0, 0x21, 0x15, 0x97, 0xc6, 0x16, 0x2a, 0xb0, 0x2d, 0x65, 0x0e,
-	0x15, 0xd4, 0xa8, 0x2a, 0x99, 0x6c, 0x13, 0x13, 0x6a, 0x11, 0xb3, 0x39, 0x13, 0x46, 0x6d, 0x58,
-	0x1a, 0xb5, 0xf6, 0x61, 0x1c, 0xce, 0xc6, 0xe7, 0x28, 0xb1, 0xb4, 0x86, 0x3c, 0x80, 0x2d, 0xe9,
-	0x17, 0xa3, 0x8e, 0x16, 0x0d, 0x69, 0x71, 0x10, 0x8b, 0x4b, 0x0b, 0xa5, 0x64, 0x1f, 0xea, 0xea,
-	0x4e, 0x23, 0xa3, 0x31, 0xac, 0x8c, 0x5a, 0xfb, 0x86, 0x34, 0xd0, 0xa7, 0x1c, 0x2b, 0x18, 0x44,
-	0x47, 0xbe, 0xe0, 0x4b, 0x2b, 0x31, 0x24, 0x6f, 0x40, 0xdb, 0xf6, 0x5c, 0xe6, 0x8b, 0xa9, 0x08,
-	0xae, 0x98, 0x6f, 0x34, 0x71, 0x47, 0x2d, 0x25, 0x9b, 0x48, 0x11, 0xd9, 0x87, 0xd7, 0xf2, 0x26,
-	0x53, 0x6a, 0xdb, 0x2c, 0x8a, 0x02, 0x6e, 0x00, 0xda, 0xde, 0xc9, 0xd9, 0x1e, 0x68, 0x95, 0x9c,
-	0xd6, 0x71, 0xa3, 0xd0, 0xa3, 0xcb, 0xa9, 0x4f, 0x17, 0xcc, 0x68, 0xa9, 0x69, 0xb5, 0xec, 0x4b,
-	0xba, 0x60, 0xe4, 0x21, 0xb4, 0x16, 0x41, 0xec, 0x8b, 0x69, 0x18, 0xb8, 0xbe, 0x30, 0xda, 0x68,
-	0x01, 0x28, 0x3a, 0x93, 0x12, 0xf2, 0x3a, 0xa8, 0x9e, 0x02, 0x63, 0x47, 0xf9, 0x15, 0x25, 0x08,
-	0xc7, 0x47, 0xd0, 0x55, 0xea, 0x74, 0x3f, 0x5d, 0x34, 0xe9, 0xa0, 0x34, 0xdd, 0xc9, 0x7b, 0xd0,
-	0x44, 0x3c, 0xb8, 0xfe, 0x45, 0x60, 0xf4, 0xd0, 0x6f, 0x77, 0x72, 0x6e, 0x91, 0x98, 0x38, 0xf6,
-	0x2f, 0x02, 0xab, 0xf1, 0x42, 0xb7, 0xc8, 0xc7, 0x70, 0xbf, 0x70, 0x5e, 0xce, 0x16, 0xd4, 0xf5,
-	0x5d, 0x7f, 0x3e, 0x8d, 0x23, 0x16, 0x19, 0xdb, 0x88, 0x70, 0x23, 0x77, 0x6a, 0x2b, 0x31, 0xf8,
-	0x3a, 0x62, 0x11, 0xb9, 0x0f, 0x4d, 0x15, 0xa4, 0x53, 0xd7, 0x31, 0xfa, 0xb8, 0xa5, 0x86, 0x12,
-	0x1c, 0x3b, 0xe4, 0x2d, 0xe8, 0x85, 0x81, 0xe7, 0xda, 0xcb, 0x69, 0x70, 0xcd, 0x38, 0x77, 0x1d,
-	0x66, 0x90, 0x61, 0x69, 0xd4, 0xb0, 0xba, 0x4a, 0xfc, 0x95, 0x96, 0x6e, 0x0a, 0x8d, 0x3b, 0x68,
-	0xb8, 0x16, 0x1a, 0x63, 0x00, 0x3b, 0xf0, 0x7d, 0x66, 0x23, 0xfc, 0x76, 0xf0, 0x84, 0x5d, 0x79,
-	0xc2, 0xc3, 0x54, 0x6a, 0xe5, 0x2c, 0x06, 0x9f, 0x41, 0x3b, 0x0f, 0x05, 0xb2, 0x0d, 0x95, 0x2b,
-	0xb6, 0xd4, 0xf0, 0x97, 0x4d, 0x32, 0x84, 0xea, 0x35, 0xf5, 0x62, 0x86, 0x90, 0xd7, 0x40, 0x54,
-	0x43, 0x2c, 0xa5, 0xf8, 0x45, 0xf9, 0x69, 0xc9, 0xfc, 0x77, 0x15, 0xb6, 0x24, 0xf8, 0xc8, 0x07,
-	0xd0, 0xf1, 0x18, 0x8d, 0xd8, 0x34, 0x08, 0xe5, 0x02, 0x11, 0x4e, 0xd5, 0xda, 0xdf, 0x96, 0xc3,
-	0x4e, 0xa4, 0xe2, 0x2b, 0x25, 0xb7, 0xda, 0x5e, 0xae, 0x27, 0x43, 0xda, 0xf5, 0x05, 0xe3, 0x3e,
-	0xf5, 0xa6, 0x18, 0x0c, 0x2a, 0xc0, 0xda, 0x89, 0xf0, 0x99, 0x0c, 0x8a, 0x55, 0x1c, 0x55, 0xd6,
-	0x71, 0x34, 0x80, 0x06, 0xfa, 0xce, 0x65, 0x91, 0x0e, 0xf6, 0xb4, 0x4f, 0xf6, 0xa1, 0xb1, 0x60,
-	0x82, 0xea, 0x58, 0x93, 0x21, 0x71, 0x37, 0x89, 0x99, 0xf1, 0xa9, 0x56, 0xa8, 0x80, 0x48, 0xed,
-	0xd6, 0x22, 0xa2, 0xb6, 0x1e, 0x11, 0x03, 0x68, 0xa4, 0xa0, 0xab, 0xab, 0x1b, 0x4e, 0xfa, 0x92,
-	0x66, 0x43, 0xc6, 0xdd, 0xc0, 0x31, 0x1a, 0x08, 0x14, 0xdd, 0x93, 0x24, 0xe9, 0xc7, 0x0b, 0x05,
-	0xa1, 0xa6, 0x22, 0x49, 0x3f, 0x5e, 0xac, 0x23, 0x06, 0x56, 0x10, 0xf3, 0x13, 0xa8, 0x52, 0xcf,
-	0xa5, 0x11, 0x86, 0x90, 0xbc, 0x59, 0xcd, 0xf7, 0xe3, 0x03, 0x29, 0xb5, 0x94, 0x92, 0xbc, 0x0f,
-	0x9d, 0x39, 0x0f, 0xe2, 0x70, 0x8a, 0x5d, 0x16, 0x19, 0x6d, 0x3c, 0xed, 0xaa, 0x75, 0x1b, 0x8d,
-	0x0e, 0x94, 0x8d, 0x8c, 0xc0, 0x59, 0x10, 0xfb, 0xce, 0xd4, 0x76, 0x1d, 0x1e, 0x19, 0x1d, 0x74,
-	0x1e, 0xa0, 0xe8, 0x50, 0x4a, 0x64, 0x88, 0xa9, 0x10, 0x48, 0x1d, 0xdc, 0x45, 0x9b, 0x0e, 0x4a,
-	0xcf, 0x12, 0x2f, 0xff, 0x14, 0xfa, 0x49, 0x62, 0xca, 0x2c, 0x7b, 0x68, 0xb9, 0x9d, 0x28, 0x52,
-	0xe3, 0x11, 0x6c, 0xb3, 0x1b, 0x49, 0xa1, 0xae, 0x98, 0x2e, 0xe8, 0xcd, 0x54, 0x08, 0x4f, 0x87,
-	0x54, 0x37, 0x91, 0x9f, 0xd2, 0x9b, 0x89, 0xf0, 0x64, 0xfc, 0xab, 0xd5, 0x31, 0xfe, 0xfb, 0x98,
-	0x8c, 0x9a, 0x28, 0xc1, 0xf8, 0xdf, 0x85, 0xbe, 0x1f, 0x4c, 0x1d, 0x76, 0x41, 0x63, 0x4f, 0xa8,
-	0x75, 0x97, 0x3a, 0x98, 0x7a, 0x7e, 0xf0, 0x4c, 0xc9, 0x71, 0xd9, 0xe5, 0xe0, 0x97, 0xd0, 0x29,
-	0x5c, 0xf7, 0x06, 0xd0, 0xef, 0xe4, 0x41, 0xdf, 0xcc, 0x03, 0xfd, 0x9f, 0x5b, 0x00, 0x78, 0xef,
-	0x6a, 0xe8, 0x6a, 0xb6, 0xc8, 0x83, 0xa1, 0xbc, 0x01, 0x0c, 0x94, 0x33, 0x5f, 0x68, 0xe0, 0xea,
-	0xde, 0xf7, 0x62, 0x36, 0xc9, 0x17, 0xd5, 0x5c, 0xbe, 0x78, 0x07, 0xb6, 0x24, 0x3e, 0x8d, 0x5a,
-	0x46, 0xeb, 0xd9, 0x8e, 0x10, 0xc9, 0x0a, 0xc5, 0x68, 0xb5, 0x16, 0x34, 0xf5, 0xf5, 0xa0, 0xc9,
-	0xa3, 0xb1, 0x51, 0x44, 0xe3, 0x9b, 0xd0, 0xb1, 0x39, 0xc3, 0xdc, 0x35, 0x95, 0xc5, 0x88, 0x46,
-	0x6b, 0x3b, 0x11, 0x4e, 0xdc, 0x05, 0x93, 0xfe, 0x93, 0x17, 0x07, 0xa8, 0x92, 0xcd, 0x8d, 0xf7,
-	0xda, 0xda, 0x78, 0xaf, 0x58, 0x09, 0x78, 0x4c, 0x33, 0x3e, 0xb6, 0x73, 0x51, 0xd3, 0x29, 0x44,
-	0x4d, 0x21, 0x34, 0xba, 0x2b, 0xa1, 0xb1, 0x82, 0xdf, 0xde, 0x1a, 0x7e, 0xdf, 0x80, 0xb6, 0x74,
-	0x40, 0x14, 0x52, 0x9b, 0xc9, 0x09, 0xb6, 0x95, 0x23, 0x52, 0xd9, 0xb1, 0x83, 0xd1, 0x1e, 0xcf,
-	0x66, 0xcb, 0xcb, 0xc0, 0x63, 0x19, 0x61, 0xb7, 0x52, 0xd9, 0xb1, 0x23, 0xf7, 0x8b, 0x08, 0x24,
-	0x88, 0x40, 0x6c, 0x0f, 0x3e, 0x84, 0x66, 0xea, 0xf5, 0x1f, 0x04, 0xa6, 0xbf, 0x96, 0xa0, 0x9d,
-	0x27, 0x45, 0x39, 0x78, 0x32, 0x39, 0xc1, 0xc1, 0x15, 0x4b, 0x36, 0x65, 0x39, 0xc1, 0x99, 0xcf,
-	0x5e, 0xd0, 0x99, 0xa7, 0x26, 0x68, 0x58, 0x99, 0x40, 0x6a, 0x5d, 0xdf, 0xe6, 0x6c, 0x91, 0xa0,
-	0xaa, 0x62, 0x65, 0x02, 0xf2, 0x11, 0x80, 0x1b, 0x45, 0x31, 0x53, 0x37, 0xb7, 0x85, 0x94, 0x31,
-	0x18, 0xab, 0x1a, 0x73, 0x9c, 0xd4, 0x98, 0xe3, 0x49, 0x52, 0x63, 0x5a, 0x4d, 0xb4, 0xc6, 0x2b,
-	0xbd, 0x0b, 0x35, 0x79, 0x41, 0x93, 0x13, 0x44, 0x5e, 0xc5, 0xd2, 0x3d, 0xf3, 0xcf, 0x50, 0x53,
-	0x55, 0xc8, 0xff, 0x94, 0xe8, 0xef, 0x41, 0x43, 0xcd, 0xed, 0x3a, 0x3a, 0x56, 0xea, 0xd8, 0x3f,
-	0x76, 0xcc, 0xef, 0xca, 0xd0, 0xb0, 0x58, 0x14, 0x06, 0x7e, 0xc4, 0x72, 0x55, 0x52, 0xe9, 0xa5,
-	0x55, 0x52, 0x79, 0x63, 0x95, 0x94, 0xd4, 0x5e, 0x95, 0x5c, 0xed, 0x35, 0x80, 0x06, 0x67, 0x8e,
-	0xcb, 0x99, 0x2d, 0x74, 0x9d, 0x96, 0xf6, 0xa5, 0xee, 0x05, 0xe5, 0x32, 0xbd, 0x47, 0x98, 0x43,
-	0x9a, 0x56, 0xda, 0x27, 0x4f, 0xf2, 0xc5, 0x85, 0x2a, 0xdb, 0x76, 0x54, 0x71, 0xa1, 0xb6, 0xbb,
-	0xa1, 0xba, 0x78, 0x3f, 0x2b, 0xd2, 0xea, 0x18, 0xcd, 0xf7, 0xf2, 0x03, 0x36, 0x57, 0x69, 0x3f,
-	0x5a, 0xce, 0xfe, 0xae, 0x0c, 0xdb, 0xab, 0x7b, 0xdb, 0x80, 0xc0, 0x1d, 0xa8, 0xaa, 0xdc, 0xa7,
-	0xe1, 0x2b, 0xd6, 0xb2, 0x5e, 0x65, 0x85, 0xe8, 0x7e, 0xb5, 0x4a, 0x1a, 0x2f, 0x87, 0x5e, 0x91,
-	0x50, 0xde, 0x86, 0x6d, 0xe9, 0xa2, 0x90, 0x39, 0x59, 0x3d, 0xa7, 0x18, 0xb0, 0xa7, 0xe5, 0x69,
-	0x45, 0xb7, 0x0b, 0xfd, 0xc4, 0x34, 0xe3, 0x86, 0x5a, 0xc1, 0xf6, 0x28, 0xa1, 0x88, 0xbb, 0x50,
-	0xbb, 0x08, 0xf8, 0x82, 0x0a, 0x4d, 0x82, 0xba, 0x57, 0x20, 0x39, 0x64, 0xdb, 0x86, 0xc2, 0x64,
-	0x22, 0x94, 0x6f, 0x16, 0x49, 0x3e, 0xe9, 0x7b, 0x02, 0x59, 0xb0, 0x61, 0x35, 0x92, 0x77, 0x84,
-	0xf9, 0x5b, 0xe8, 0xad, 0x94, 0x90, 0x1b, 0x1c, 0x99, 0x2d, 0x5f, 0x2e, 0x2c, 0x5f, 0x98, 0xb9,
-	0xb2, 0x32, 0xf3, 0xef, 0xa0, 0xff, 0x39, 0xf5, 0x1d, 0x8f, 0xe9, 0xf9, 0x0f, 0xf8, 0x3c, 0x92,
-	0xc9, 0x50, 0xbf, 0x68, 0xa6, 0x3a, 0xfb, 0x74, 0xac, 0xa6, 0x96, 0x1c, 0x3b, 0xe4, 0x11, 0xd4,
-	0xb9, 0xb2, 0xd6, 0x00, 0x68, 0xe5, 0x6a, 0x5c, 0x2b, 0xd1, 0x99, 0xdf, 0x02, 0x29, 0x4c, 0x2d,
-	0x1f, 0x33, 0x4b, 0x32, 0x92, 0xe8, 0x57, 0xa0, 0xd0, 0x51, 0xd5, 0xce, 0x63, 0xd2, 0x4a, 0xb5,
-	0x64, 0x08, 0x15, 0xc6, 0xb9, 0x5e, 0x02, 0x8b, 0xcc, 0xec, 0xe9, 0x68, 0x49, 0x95, 0xf9, 0x33,
-	0xe8, 0x9f, 0x87, 0xcc, 0x76, 0xa9, 0x87, 0xcf, 0x3e, 0xb5, 0xc0, 0x43, 0xa8, 0x4a, 0x27, 0x27,
-	0x84, 0xd1, 0xc4, 0x81, 0xa8, 0x56, 0x72, 0xf3, 0x5b, 0x30, 0xd4, 0xbe, 0x8e, 0x6e, 0xdc, 0x48,
-	0x30, 0xdf, 0x66, 0x87, 0x97, 0xcc, 0xbe, 0xfa, 0x11, 0x4f, 0x7e, 0x0d, 0xf7, 0x36, 0xad, 0x90,
-	0xec, 0xaf, 0x65, 0xcb, 0xde, 0xf4, 0x42, 0xe6, 0x0e, 0x5c, 0xa3, 0x61, 0x01, 0x8a, 0x3e, 0x93,
-	0x12, 0x79, 0x8f, 0x4c, 0x8e, 0x8b, 0x34, 0x1f, 0xeb, 0x5e, 0xe2, 0x8f, 0xca, 0xed, 0xfe, 0xf8,
-	0x5b, 0x09, 0x9a, 0xe7, 0x4c, 0xc4, 0x21, 0x9e, 0xe5, 0x3e, 0x34, 0x67, 0x3c, 0xb8, 0x62, 0x3c,
-	0x3b, 0x4a, 0x43, 0x09, 0x8e, 0x1d, 0xf2, 0x04, 0x6a, 0x87, 0x81, 0x7f, 0xe1, 0xce, 0xf1, 0x11,
-	0xac, 0x89, 0x21, 0x1d, 0x3b, 0x56, 0x3a, 0x45, 0x0c, 0xda, 0x90, 0x0c, 0xa1, 0xa5, 0x3f, 0x29,
-	0x7c, 0xfd, 0xf5, 0xf1, 0xb3, 0xa4, 0x3a, 0xce, 0x89, 0x06, 0x1f, 0x41, 0x2b, 0x37, 0xf0, 0x07,
-	0xa5, 0xaa, 0xff, 0x07, 0xc0, 0xd5, 0x95, 0x8f, 0xb6, 0xd5, 0x51, 0xf5, 0x48, 0x79, 0xb4, 0x87,
-	0xd0, 0x94, 0x85, 0x98, 0x52, 0x27, 0x49, 0xb2, 0x94, 0x25, 0x49, 0xf3, 0x11, 0xf4, 0x8f, 0xfd,
-	0x6b, 0xea, 0xb9, 0x0e, 0x15, 0xec, 0x0b, 0xb6, 0x44, 0x17, 0xac, 0xed, 0xc0, 0x3c, 0x87, 0xb6,
-	0x7e, 0x95, 0xbf, 0xd2, 0x1e, 0xdb, 0x7a, 0x8f, 0xdf, 0x1f, 0x44, 0x6f, 0x43, 0x4f, 0x4f, 0x7a,
-	0xe2, 0xea, 0x10, 0x92, 0x35, 0x06, 0x67, 0x17, 0xee, 0x8d, 0x9e, 0x5a, 0xf7, 0xcc, 0xa7, 0xb0,
-	0x9d, 0x33, 0x4d, 0x8f, 0x73, 0xc5, 0x96, 0x51, 0xf2, 0xb5, 0x42, 0xb6, 0x13, 0x0f, 0x94, 0x33,
-	0x0f, 0x98, 0xd0, 0xd5, 0x23, 0x9f, 0x33, 0x71, 0xcb, 0xe9, 0xbe, 0x48, 0x37, 0xf2, 0x9c, 0xe9,
-	0xc9, 0x1f, 0x43, 0x95, 0xc9, 0x93, 0xe6, 0xf3, 0x67, 0xde, 0x03, 0x96, 0x52, 0x6f, 0x58, 0xf0,
-	0x69, 0xba, 0xe0, 0x59, 0xac, 0x16, 0x7c, 0xc5, 0xb9, 0xcc, 0x37, 0xd3, 0x6d, 0x9c, 0xc5, 0xe2,
-	0xb6, 0x1b, 0x7d, 0x04, 0x7d, 0x6d, 0xf4, 0x8c, 0x79, 0x4c, 0xb0, 0x5b, 0x8e, 0xf4, 0x18, 0x48,
-	0xc1, 0xec, 0xb6, 0xe9, 0x1e, 0x40, 0x63, 0x32, 0x39, 0x49, 0xb5, 0x45, 0x6e, 0x34, 0x3f, 0x86,
-	0xfe, 0x79, 0xec, 0x04, 0x67, 0xdc, 0xbd, 0x76, 0x3d, 0x36, 0x57, 0x8b, 0x25, 0xc5, 0x6f, 0x29,
-	0x57, 0xfc, 0x6e, 0xcc, 0x46, 0xe6, 0x08, 0x48, 0x61, 0x78, 0x7a, 0x6f, 0x51, 0xec, 0x04, 0x3a,
-	0x84, 0xb1, 0x6d, 0x8e, 0xa0, 0x3d, 0xa1, 0xb2, 0xd8, 0x70, 0x94, 0x8d, 0x01, 0x75, 0xa1, 0xfa,
-	0xda, 0x2c, 0xe9, 0x9a, 0xfb, 0xb0, 0x73, 0x48, 0xed, 0x4b, 0xd7, 0x9f, 0x3f, 0x73, 0x23, 0x59,
-	0x6d, 0xe9, 0x11, 0x03, 0x68, 0x38, 0x5a, 0xa0, 0x87, 0xa4, 0x7d, 0xf3, 0x5d, 0x78, 0x2d, 0xf7,
-	0x49, 0xe8, 0x5c, 0xd0, 0xc4, 0x1f, 0x3b, 0x50, 0x8d, 0x64, 0x0f, 0x47, 0x54, 0x2d, 0xd5, 0x31,
-	0xbf, 0x84, 0x9d, 0x7c, 0x02, 0x96, 0xb5, 0x4f, 0x72, 0x70, 0xac, 0x4a, 0x4a, 0xb9, 0xaa, 0x44,
-	0xfb, 0xac, 0x9c, 0xe5, 0x93, 0x6d, 0xa8, 0xfc, 0xfa, 0x9b, 0x89, 0x06, 0xbb, 0x6c, 0x9a, 0x7f,
-	0x94, 0xcb, 0x17, 0xe7, 0x53, 0xcb, 0x17, 0x4a, 0x93, 0xd2, 0x2b, 0x95, 0x26, 0xeb, 0x78, 0x7b,
-	0x17, 0xfa, 0xa7, 0x5e, 0x60, 0x5f, 0x1d, 0xf9, 0x39, 0x6f, 0x18, 0x50, 0x67, 0x7e, 0xde, 0x19,
-	0x49, 0xd7, 0x7c, 0x0b, 0x7a, 0x27, 0x81, 0x4d, 0xbd, 0xd3, 0x20, 0xf6, 0x45, 0xea, 0x05, 0xfc,
-	0x46, 0xa7, 0x4d, 0x55, 0xc7, 0x7c, 0x17, 0xba, 0x3a, 0x45, 0xfb, 0x17, 0x41, 0xc2, 0x8c, 0x59,
-	0x32, 0x2f, 0x15, 0x0b, 0x7d, 0xf3, 0x04, 0x7a, 0x99, 0xb9, 0x9a, 0xf7, 0x2d, 0xa8, 0x29, 0xb5,
-	0x3e, 0x5b, 0x2f, 0x7d, 0xe9, 0x2a, 0x4b, 0x4b, 0xab, 0x37, 0x1c, 0x6a, 0x01, 0xdd, 0x33, 0xfc,
-	0x56, 0x7a, 0xe4, 0x5f, 0xab, 0xc9, 0x8e, 0x81, 0xa8, 0xaf, 0xa7, 0x53, 0xe6, 0x5f, 0xbb, 0x3c,
-	0xf0, 0xb1, 0xb8, 0x2e, 0xe9, 0x12, 0x26, 0x99, 0x38, 0x1d, 0x94, 0x58, 0x58, 0xfd, 0x70, 0x55,
-	0xb4, 0xd1, 0x87, 0x90, 0x7d, 0x89, 0x91, 0xa9, 0x86, 0xb3, 0x45, 0x20, 0xd8, 0x94, 0x3a, 0x4e,
-	0x12, 0x2d, 0xa0, 0x44, 0x07, 0x8e, 0xc3, 0xf7, 0xff, 0x53, 0x86, 0xfa, 0xa7, 0x8a, 0xc0, 0xc9,
-	0x27, 0xd0, 0x29, 0xa4, 0x6b, 0xf2, 0x1a, 0x96, 0x75, 0xab, 0xc5, 0xc1, 0xe0, 0xee, 0x9a, 0x58,
-	0x9d, 0xeb, 0x3d, 0x68, 0xe7, 0x93, 0x31, 0xc1, 0xc4, 0x8b, 0xdf, 0x85, 0x07, 0x38, 0xd3, 0x7a,
-	0xa6, 0x3e, 0x87, 0x9d, 0x4d, 0x69, 0x92, 0x3c, 0xc8, 0x56, 0x58, 0x4f, 0xd1, 0x83, 0xd7, 0x6f,
-	0xd3, 0x26, 0xe9, 0xb5, 0x7e, 0xe8, 0x31, 0xea, 0xc7, 0x61, 0x7e, 0x07, 0x59, 0x93, 0x3c, 0x81,
-	0x4e, 0x21, 0x51, 0xa8, 0x73, 0xae, 0xe5, 0x8e, 0xfc, 0x90, 0xc7, 0x50, 0xc5, 0xe4, 0x44, 0x3a,
-	0x85, 0x2c, 0x39, 0xe8, 0xa6, 0x5d, 0xb5, 0xf6, 0x10, 0xb6, 0xf0, 0x6b, 0x41, 0x6e, 0x61, 0x1c,
-	0x91, 0x66, 0xae, 0xfd, 0x7f, 0x95, 0xa0, 0x9e, 0x7c, 0x41, 0x7e, 0x02, 0x5b, 0x32, 0x07, 0x90,
-	0x3b, 0x39, 0x1a, 0x4d, 0xf2, 0xc7, 0x60, 0x67, 0x45, 0xa8, 0x16, 0x18, 0x43, 0xe5, 0x39, 0x13,
-	0x84, 0xe4, 0x94, 0x3a, 0x19, 0x0c, 0xee, 0x14, 0x65, 0xa9, 0xfd, 0x59, 0x5c, 0xb4, 0xd7, 0x5c,
-	0x5e, 0xb0, 0x4f, 0x59, 0xfa, 0x43, 0xa8, 0x29, 0x96, 0x55, 0x4e, 0x59, 0xe3, 0x67, 0x75, 0xf9,
-	0xeb, 0x7c, 0xbc, 0xff, 0x8f, 0x2d, 0x80, 0xf3, 0x65, 0x24, 0xd8, 0xe2, 0x37, 0x2e, 0x7b, 0x41,
-	0x76, 0xa1, 0xa7, 0xbf, 0x89, 0xe0, 0x53, 0x4d, 0xb2, 0x49, 0xce, 0x27, 0x58, 0xf0, 0xa5, 0x64,
-	0xfd, 0x18, 0x5a, 0xa7, 0xf4, 0xe6, 0xe5, 0x76, 0x9f, 0x40, 0xa7, 0xc0, 0xc1, 0x7a, 0x8b, 0xab,
-	0xac, 0xae, 0xb7, 0xb8, 0xce, 0xd6, 0x8f, 0xa1, 0xae, 0x99, 0x39, 0xbf, 0x06, 0xe6, 0xb0, 0x02,
-	0x63, 0xff, 0x1c, 0x7a, 0x2b, 0xbc, 0x9c, 0xb7, 0xc7, 0xcf, 0x21, 0x1b, 0x79, 0xfb, 0xa9, 0x7c,
-	0xed, 0x14, 0xb9, 0x39, 0x3f, 0x50, 0xbf, 0xbc, 0x36, 0x91, 0xf7, 0xf3, 0xe2, 0x3b, 0x09, 0x9f,
-	0xa8, 0xc6, 0x2a, 0x7d, 0x26, 0xe4, 0x3d, 0xb8, 0xb7, 0x49, 0x93, 0x86, 0x60, 0x9e, 0x41, 0xd7,
-	0x42, 0x70, 0x9d, 0x5e, 0xdf, 0x01, 0xc8, 0x48, 0x34, 0x6f, 0x8f, 0xf0, 0x58, 0xe5, 0xd7, 0x0f,
-	0x00, 0x32, 0x6a, 0x54, 0xa8, 0x2a, 0x32, 0xab, 0x1a, 0xb6, 0x4a, 0x9f, 0xbb, 0xd0, 0x4c, 0xe9,
-	0x2c, 0xbf, 0x06, 0x4e, 0x50, 0x64, 0xc7, 0x4f, 0x77, 0x7f, 0x3f, 0x9a, 0xbb, 0xe2, 0x32, 0x9e,
-	0x8d, 0xed, 0x60, 0xb1, 0x77, 0x49, 0xa3, 0x4b, 0xd7, 0x0e, 0x78, 0xb8, 0x77, 0x2d, 0xc1, 0xb4,
-	0x57, 0xf8, 0xc1, 0x35, 0xab, 0xe1, 0x43, 0xef, 0xfd, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0xbd,
-	0xc6, 0x6e, 0xfa, 0xf8, 0x1a, 0x00, 0x00,
+	// 2605 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x5b, 0x73, 0xdb, 0xc6,
+	0xf5, 0x1f, 0x92, 0xe2, 0xed, 0xf0, 0xbe, 0x96, 0xf5, 0x87, 0x61, 0xe7, 0x6f, 0x06, 0xae, 0x1d,
+	0xc5, 0x4d, 0xa8, 0x58, 0x69, 0x1a, 0xa7, 0x9d, 0xa4, 0xe3, 0x48, 0x8a, 0xa3, 0x46, 0x4a, 0x34,
+	0x10, 0xd3, 0xf4, 0x36, 0xc3, 0x80, 0xc0, 0x8a, 0xc4, 0x08, 0x04, 0xd0, 0xc5, 0x42, 0x12, 0x9f,
+	0xfa, 0x15, 0xfa, 0xd4, 0xaf, 0xd1, 0x3e, 0xf6, 0xad, 0xaf, 0x99, 0xbe, 0xf7, 0x6b, 0xf4, 0x33,
+	0x74, 0xf6, 0xec, 0xe2, 0x46, 0x52, 0xb1, 0x33, 0x93, 0xbe, 0xed, 0xfe, 0xce, 0xd9, 0x73, 0x76,
+	0x0f, 0xce, 0x6d, 0x17, 0x70, 0x3f, 0x72, 0x2e, 0xf7, 0x42, 0x2f, 0x9e, 0xb9, 0xfe, 0x5e, 0x38,
+	0xdd, 0x9b, 0x5a, 0xf6, 0x25, 0xf5, 0x9d, 0x51, 0xc8, 0x02, 0x1e, 0x90, 0x72, 0x38, 0xd5, 0x1f,
+	0xce, 0x82, 0x60, 0xe6, 0xd1, 0x3d, 0x44, 0xa6, 0xf1, 0xc5, 0x1e, 0x77, 0x17, 0x34, 0xe2, 0xd6,
+	0x22, 0x94, 0x4c, 0xba, 0x2e, 0x24, 0x78, 0xc1, 0xcc, 0xb5, 0x2d, 0x6f, 0xcf, 0x75, 0xa8, 0xcf,
+	0x5d, 0xbe, 0x54, 0x34, 0x2d, 0x4f, 0x93, 0x5a, 0x24, 0xc5, 0xa8, 0x43, 0xf5, 0x68, 0x11, 0xf2,
+	0xa5, 0x31, 0x84, 0xda, 0xe7, 0xd4, 0x72, 0x28, 0x23, 0x3b, 0x50, 0x9b, 0xe3, 0x48, 0x2b, 0x0d,
+	0x2b, 0xbb, 0x4d, 0x53, 0xcd, 0x8c, 0x3f, 0x00, 0x9c, 0x89, 0x35, 0x47, 0x8c, 0x05, 0x8c, 0xdc,
+	0x83, 0x06, 0x65, 0x6c, 0xc2, 0x97, 0x21, 0xd5, 0x4a, 0xc3, 0xd2, 0x6e, 0xc7, 0xac, 0x53, 0xc6,
+	0xc6, 0xcb, 0x90, 0x92, 0xff, 0x03, 0x31, 0x9c, 0x2c, 0xa2, 0x99, 0x56, 0x1e, 0x96, 0x84, 0x04,
+	0xca, 0xd8, 0x69, 0x34, 0x4b, 0xd6, 0xd8, 0x81, 0x43, 0xb5, 0xca, 0xb0, 0xb4, 0x5b, 0xc1, 0x35,
+	0x07, 0x81, 0x43, 0x8d, 0xbf, 0x96, 0xa0, 0x7a, 0x66, 0xf1, 0x79, 0x44, 0x08, 0x6c, 0xb1, 0x20,
+	0xe0, 0x4a, 0x39, 0x8e, 0xc9, 0x2e, 0xf4, 0x62, 0xdf, 0x8a, 0xf9, 0x5c, 0x9c, 0xca, 0xb6, 0x38,
+	0x75, 0xb4, 0x32, 0x92, 0x57, 0x61, 0xf2, 0x08, 0x3a, 0x5e, 0x60, 0x5b, 0xde, 0x24, 0xe2, 0x01,
+	0xb3, 0x66, 0x42, 0x8f, 0xe0, 0x6b, 0x23, 0x78, 0x2e, 0x31, 0xf2, 0x14, 0x06, 0x11, 0xb5, 0xbc,
+	0xc9, 0x35, 0xb3, 0xc2, 0x94, 0x71, 0x4b, 0x0a, 0x14, 0x84, 0x6f, 0x98, 0x15, 0x2a, 0x5e, 0xe3,
+	0x9f, 0x35, 0xa8, 0x9b, 0xf4, 0x4f, 0x31, 0x8d, 0x38, 0xe9, 0x42, 0xd9, 0x75, 0xf0, 0xb4, 0x4d,
+	0xb3, 0xec, 0x3a, 0x64, 0x04, 0xc4, 0xa4, 0xa1, 0x27, 0x54, 0xbb, 0x81, 0x7f, 0xe0, 0xc5, 0x11,
+	0xa7, 0x4c, 0x9d, 0x79, 0x03, 0x85, 0x3c, 0x80, 0x66, 0x10, 0x52, 0x86, 0x18, 0x1a, 0xa0, 0x69,
+	0x66, 0x80, 0x38, 0x78, 0x68, 0xf1, 0xb9, 0xb6, 0x85, 0x04, 0x1c, 0x0b, 0xcc, 0xb1, 0xb8, 0xa5,
+	0x55, 0x25, 0x26, 0xc6, 0xc4, 0x80, 0x5a, 0x44, 0x6d, 0x46, 0xb9, 0x56, 0x1b, 0x96, 0x76, 0x5b,
+	0xfb, 0x30, 0x0a, 0xa7, 0xa3, 0x73, 0x44, 0x4c, 0x45, 0x21, 0x0f, 0x60, 0x4b, 0xd8, 0x45, 0xab,
+	0x23, 0x47, 0x43, 0x70, 0xbc, 0x88, 0xf9, 0xdc, 0x44, 0x94, 0xec, 0x43, 0x5d, 0x7e, 0xd3, 0x48,
+	0x6b, 0x0c, 0x2b, 0xbb, 0xad, 0x7d, 0x4d, 0x30, 0xa8, 0x53, 0x8e, 0xa4, 0x1b, 0x44, 0x47, 0x3e,
+	0x67, 0x4b, 0x33, 0x61, 0x24, 0x6f, 0x42, 0xdb, 0xf6, 0x5c, 0xea, 0xf3, 0x09, 0x0f, 0x2e, 0xa9,
+	0xaf, 0x35, 0x71, 0x47, 0x2d, 0x89, 0x8d, 0x05, 0x44, 0xf6, 0xe1, 0x6e, 0x9e, 0x65, 0x62, 0xd9,
+	0x36, 0x8d, 0xa2, 0x80, 0x69, 0x80, 0xbc, 0x77, 0x72, 0xbc, 0x2f, 0x14, 0x49, 0x88, 0x75, 0xdc,
+	0x28, 0xf4, 0xac, 0xe5, 0xc4, 0xb7, 0x16, 0x54, 0x6b, 0x49, 0xb1, 0x0a, 0xfb, 0xd2, 0x5a, 0x50,
+	0xf2, 0x10, 0x5a, 0x8b, 0x20, 0xf6, 0xf9, 0x24, 0x0c, 0x5c, 0x9f, 0x6b, 0x6d, 0xe4, 0x00, 0x84,
+	0xce, 0x04, 0x42, 0xde, 0x00, 0x39, 0x93, 0xce, 0xd8, 0x91, 0x76, 0x45, 0x04, 0xdd, 0xf1, 0x31,
+	0x74, 0x25, 0x39, 0xdd, 0x4f, 0x17, 0x59, 0x3a, 0x88, 0xa6, 0x3b, 0x79, 0x0f, 0x9a, 0xe8, 0x0f,
+	0xae, 0x7f, 0x11, 0x68, 0x3d, 0xb4, 0xdb, 0x9d, 0x9c, 0x59, 0x84, 0x4f, 0x1c, 0xfb, 0x17, 0x81,
+	0xd9, 0xb8, 0x56, 0x23, 0xf2, 0x31, 0xdc, 0x2f, 0x9c, 0x97, 0xd1, 0x85, 0xe5, 0xfa, 0xae, 0x3f,
+	0x9b, 0xc4, 0x11, 0x8d, 0xb4, 0x3e, 0x7a, 0xb8, 0x96, 0x3b, 0xb5, 0x99, 0x30, 0x7c, 0x1d, 0xd1,
+	0x88, 0xdc, 0x87, 0xa6, 0x0c, 0xd2, 0x89, 0xeb, 0x68, 0x03, 0xdc, 0x52, 0x43, 0x02, 0xc7, 0x0e,
+	0x79, 0x0b, 0x7a, 0x61, 0xe0, 0xb9, 0xf6, 0x72, 0x12, 0x5c, 0x51, 0xc6, 0x5c, 0x87, 0x6a, 0x64,
+	0x58, 0xda, 0x6d, 0x98, 0x5d, 0x09, 0x7f, 0xa5, 0xd0, 0x4d, 0xa1, 0x71, 0x07, 0x19, 0x57, 0x61,
+	0x32, 0x02, 0xb0, 0x03, 0xdf, 0xa7, 0x36, 0xba, 0xdf, 0x36, 0x9e, 0xb0, 0x2b, 0x4e, 0x78, 0x90,
+	0xa2, 0x66, 0x8e, 0x43, 0xff, 0x0c, 0xda, 0x79, 0x57, 0x20, 0x7d, 0xa8, 0x5c, 0xd2, 0xa5, 0x72,
+	0x7f, 0x31, 0x24, 0x43, 0xa8, 0x5e, 0x59, 0x5e, 0x4c, 0xb5, 0x72, 0xe6, 0x88, 0x72, 0x89, 0x29,
+	0x09, 0xbf, 0x28, 0x3f, 0x2f, 0x19, 0x7f, 0xa9, 0xc1, 0x96, 0x70, 0x3e, 0xf2, 0x01, 0x74, 0x3c,
+	0x6a, 0x45, 0x74, 0x12, 0x84, 0x42, 0x41, 0x84, 0xa2, 0x5a, 0xfb, 0x7d, 0xb1, 0xec, 0x44, 0x10,
+	0xbe, 0x92, 0xb8, 0xd9, 0xf6, 0x72, 0x33, 0x11, 0xd2, 0xae, 0xcf, 0x29, 0xf3, 0x2d, 0x6f, 0x82,
+	0xc1, 0x20, 0x03, 0xac, 0x9d, 0x80, 0x87, 0x22, 0x28, 0x56, 0xfd, 0xa8, 0xb2, 0xee, 0x47, 0x3a,
+	0x34, 0xd0, 0x76, 0x2e, 0x8d, 0x54, 0xb0, 0xa7, 0x73, 0xb2, 0x0f, 0x8d, 0x05, 0xe5, 0x96, 0x8a,
+	0x35, 0x11, 0x12, 0x3b, 0x49, 0xcc, 0x8c, 0x4e, 0x15, 0x41, 0x06, 0x44, 0xca, 0xb7, 0x16, 0x11,
+	0xb5, 0xf5, 0x88, 0xd0, 0xa1, 0x91, 0x3a, 0x5d, 0x5d, 0x7e, 0xe1, 0x64, 0x2e, 0xd2, 0x6c, 0x48,
+	0x99, 0x1b, 0x38, 0x5a, 0x03, 0x1d, 0x45, 0xcd, 0x44, 0x92, 0xf4, 0xe3, 0x85, 0x74, 0xa1, 0xa6,
+	0x4c, 0x92, 0x7e, 0xbc, 0x58, 0xf7, 0x18, 0x58, 0xf1, 0x98, 0x9f, 0x40, 0xd5, 0xf2, 0x5c, 0x2b,
+	0xd2, 0x5a, 0xea, 0xcb, 0xaa, 0x7c, 0x3f, 0x7a, 0x21, 0x50, 0x53, 0x12, 0xc9, 0xfb, 0xd0, 0x99,
+	0xb1, 0x20, 0x0e, 0x27, 0x38, 0xa5, 0x91, 0xd6, 0x1e, 0x56, 0x36, 0x70, 0xb7, 0x91, 0xe9, 0x85,
+	0xe4, 0x11, 0x11, 0x38, 0x0d, 0x62, 0xdf, 0x99, 0xd8, 0xae, 0xc3, 0x22, 0xad, 0x83, 0xc6, 0x03,
+	0x84, 0x0e, 0x04, 0x22, 0x42, 0x4c, 0x86, 0x40, 0x6a, 0xe0, 0x2e, 0xf2, 0x74, 0x10, 0x3d, 0x4b,
+	0xac, 0xfc, 0x53, 0x18, 0x24, 0x85, 0x29, 0xe3, 0xec, 0x21, 0x67, 0x3f, 0x21, 0xa4, 0xcc, 0xbb,
+	0xd0, 0xa7, 0x37, 0x22, 0x85, 0xba, 0x7c, 0xb2, 0xb0, 0x6e, 0x26, 0x9c, 0x7b, 0x2a, 0xa4, 0xba,
+	0x09, 0x7e, 0x6a, 0xdd, 0x8c, 0xb9, 0x27, 0xe2, 0x5f, 0x6a, 0xc7, 0xf8, 0x1f, 0x60, 0x31, 0x6a,
+	0x22, 0x82, 0xf1, 0xff, 0x14, 0x06, 0x7e, 0x30, 0x71, 0xe8, 0x85, 0x15, 0x7b, 0x5c, 0xea, 0x5d,
+	0xaa, 0x60, 0xea, 0xf9, 0xc1, 0xa1, 0xc4, 0x51, 0xed, 0x52, 0x28, 0x9d, 0xba, 0xe2, 0xa0, 0xf2,
+	0xc3, 0xda, 0x94, 0x71, 0x15, 0x4e, 0x5d, 0x81, 0x1f, 0x20, 0x7c, 0x40, 0x19, 0xd7, 0x7f, 0x09,
+	0x9d, 0x82, 0x63, 0x6c, 0x08, 0x8f, 0xed, 0x7c, 0x78, 0x34, 0xf3, 0x21, 0xf1, 0xaf, 0x2d, 0x00,
+	0xf4, 0x10, 0xb9, 0x74, 0xb5, 0xae, 0xe4, 0xdd, 0xa6, 0xbc, 0xc1, 0x6d, 0x2c, 0x46, 0x7d, 0xae,
+	0x5c, 0x5c, 0xcd, 0xbe, 0xd7, 0xbb, 0x93, 0xca, 0x52, 0xcd, 0x55, 0x96, 0x77, 0x60, 0x4b, 0x78,
+	0xb2, 0x56, 0xcb, 0x0a, 0x40, 0xb6, 0x23, 0xf4, 0x79, 0x1c, 0x99, 0xc8, 0xb5, 0x16, 0x5e, 0xf5,
+	0xf5, 0xf0, 0xca, 0xfb, 0x6d, 0xa3, 0xe8, 0xb7, 0x8f, 0xa0, 0x63, 0x33, 0x8a, 0x55, 0x6e, 0x22,
+	0xda, 0x16, 0xe5, 0xd7, 0xed, 0x04, 0x1c, 0xbb, 0x0b, 0x2a, 0xec, 0x27, 0x3e, 0x31, 0x20, 0x49,
+	0x0c, 0x37, 0x7a, 0x40, 0x6b, 0xa3, 0x07, 0x60, 0xcf, 0xe0, 0x51, 0x55, 0x1b, 0x70, 0x9c, 0x8b,
+	0xaf, 0x4e, 0x21, 0xbe, 0x0a, 0x41, 0xd4, 0x5d, 0x09, 0xa2, 0x15, 0x4f, 0xef, 0xad, 0x79, 0xfa,
+	0x9b, 0xd0, 0x16, 0x06, 0x88, 0x42, 0xcb, 0xa6, 0x42, 0x40, 0x5f, 0x1a, 0x22, 0xc5, 0x8e, 0x1d,
+	0xcc, 0x0b, 0xf1, 0x74, 0xba, 0x9c, 0x07, 0x1e, 0xcd, 0x52, 0x7b, 0x2b, 0xc5, 0x8e, 0x1d, 0xb1,
+	0x5f, 0xf4, 0x55, 0x82, 0xbe, 0x8a, 0x63, 0xfd, 0x43, 0x68, 0xa6, 0x56, 0xff, 0x41, 0xce, 0xf4,
+	0xb7, 0x12, 0xb4, 0xf3, 0xe9, 0x53, 0x2c, 0x1e, 0x8f, 0x4f, 0x70, 0x71, 0xc5, 0x14, 0x43, 0xd1,
+	0x78, 0x30, 0xea, 0xd3, 0x6b, 0x6b, 0xea, 0x49, 0x01, 0x0d, 0x33, 0x03, 0x04, 0xd5, 0xf5, 0x6d,
+	0x46, 0x17, 0x89, 0x57, 0x55, 0xcc, 0x0c, 0x20, 0x1f, 0x01, 0xb8, 0x51, 0x14, 0x53, 0xf9, 0xe5,
+	0xb6, 0x30, 0xb9, 0xe8, 0x23, 0xd9, 0x8d, 0x8e, 0x92, 0x6e, 0x74, 0x34, 0x4e, 0xba, 0x51, 0xb3,
+	0x89, 0xdc, 0xf8, 0x49, 0x77, 0xa0, 0x26, 0x3e, 0xd0, 0xf8, 0x04, 0x3d, 0xaf, 0x62, 0xaa, 0x99,
+	0xf1, 0x67, 0xa8, 0xc9, 0x7e, 0xe5, 0x7f, 0x5a, 0x12, 0xee, 0x41, 0x43, 0xca, 0x76, 0x1d, 0x15,
+	0x2b, 0x75, 0x9c, 0x1f, 0x3b, 0xc6, 0x77, 0x65, 0x68, 0x98, 0x34, 0x0a, 0x03, 0x3f, 0xa2, 0xb9,
+	0x7e, 0xaa, 0xf4, 0xca, 0x7e, 0xaa, 0xbc, 0xb1, 0x9f, 0x4a, 0xba, 0xb4, 0x4a, 0xae, 0x4b, 0xd3,
+	0xa1, 0xc1, 0xa8, 0xe3, 0x32, 0x6a, 0x73, 0xd5, 0xd1, 0xa5, 0x73, 0x41, 0xbb, 0xb6, 0x98, 0x68,
+	0x04, 0x22, 0xac, 0x36, 0x4d, 0x33, 0x9d, 0x93, 0x67, 0xf9, 0x36, 0x44, 0x36, 0x78, 0xdb, 0xb2,
+	0x0d, 0x91, 0xdb, 0xdd, 0xd0, 0x87, 0xbc, 0x9f, 0xb5, 0x73, 0x75, 0x8c, 0xe6, 0x7b, 0xf9, 0x05,
+	0x9b, 0xfb, 0xb9, 0x1f, 0xad, 0xba, 0x7f, 0x57, 0x86, 0xfe, 0xea, 0xde, 0x36, 0x78, 0xe0, 0x36,
+	0x54, 0x65, 0x95, 0x54, 0xee, 0xcb, 0xd7, 0xea, 0x63, 0x65, 0x25, 0xd1, 0xfd, 0x6a, 0x35, 0x69,
+	0xbc, 0xda, 0xf5, 0x8a, 0x09, 0xe5, 0x6d, 0xe8, 0x0b, 0x13, 0x85, 0xd4, 0xc9, 0x3a, 0x3f, 0x99,
+	0x01, 0x7b, 0x0a, 0x4f, 0x7b, 0xbf, 0xa7, 0x30, 0x48, 0x58, 0xb3, 0xdc, 0x50, 0x2b, 0xf0, 0x1e,
+	0x25, 0x29, 0x62, 0x07, 0x6a, 0x17, 0x01, 0x5b, 0x58, 0x5c, 0x25, 0x41, 0x35, 0x2b, 0x24, 0x39,
+	0xcc, 0xb6, 0x0d, 0xe9, 0x93, 0x09, 0x28, 0x6e, 0x37, 0x22, 0xf9, 0xa4, 0x37, 0x0f, 0xcc, 0x82,
+	0x0d, 0xb3, 0x91, 0xdc, 0x38, 0x8c, 0xdf, 0x42, 0x6f, 0xa5, 0xd9, 0xdc, 0x60, 0xc8, 0x4c, 0x7d,
+	0xb9, 0xa0, 0xbe, 0x20, 0xb9, 0xb2, 0x22, 0xf9, 0x77, 0x30, 0xf8, 0xdc, 0xf2, 0x1d, 0x8f, 0x2a,
+	0xf9, 0x2f, 0xd8, 0x2c, 0x12, 0x65, 0x53, 0xdd, 0x7d, 0x26, 0xaa, 0xfa, 0x74, 0xcc, 0xa6, 0x42,
+	0x8e, 0x1d, 0xf2, 0x18, 0xea, 0x4c, 0x72, 0x2b, 0x07, 0x68, 0xe5, 0xba, 0x61, 0x33, 0xa1, 0x19,
+	0xdf, 0x02, 0x29, 0x88, 0x16, 0xd7, 0x1e, 0x51, 0x47, 0x1b, 0x4c, 0x39, 0x85, 0x8a, 0xaa, 0x76,
+	0xde, 0x27, 0xcd, 0x94, 0x4a, 0x86, 0x50, 0xa1, 0x8c, 0x69, 0xe5, 0xac, 0x1d, 0xcd, 0x2e, 0x99,
+	0xa6, 0x20, 0x19, 0x3f, 0x83, 0xc1, 0x79, 0x48, 0x6d, 0xd7, 0xf2, 0xf0, 0x82, 0x28, 0x15, 0x3c,
+	0x84, 0xaa, 0x30, 0x72, 0x92, 0x30, 0x9a, 0xb8, 0x10, 0xc9, 0x12, 0x37, 0xbe, 0x05, 0x4d, 0xee,
+	0xeb, 0xe8, 0xc6, 0x8d, 0x38, 0xf5, 0x6d, 0x7a, 0x30, 0xa7, 0xf6, 0xe5, 0x8f, 0x78, 0xf2, 0x2b,
+	0xb8, 0xb7, 0x49, 0x43, 0xb2, 0xbf, 0x96, 0x2d, 0x66, 0x93, 0x0b, 0x51, 0x3b, 0x50, 0x47, 0xc3,
+	0x04, 0x84, 0x3e, 0x13, 0x88, 0xf8, 0x8e, 0x54, 0xac, 0x8b, 0x54, 0x3e, 0x56, 0xb3, 0xc4, 0x1e,
+	0x95, 0xdb, 0xed, 0xf1, 0x8f, 0x12, 0x34, 0xcf, 0x29, 0x8f, 0x43, 0x3c, 0xcb, 0x7d, 0x68, 0x4e,
+	0x59, 0x70, 0x49, 0x59, 0x76, 0x94, 0x86, 0x04, 0x8e, 0x1d, 0xf2, 0x0c, 0x6a, 0x07, 0x81, 0x7f,
+	0xe1, 0xce, 0xb4, 0x72, 0x96, 0x18, 0xd2, 0xb5, 0x23, 0x49, 0x93, 0x89, 0x41, 0x31, 0x92, 0x21,
+	0xb4, 0xd4, 0xe3, 0xc3, 0xd7, 0x5f, 0x1f, 0x1f, 0x26, 0x7d, 0x74, 0x0e, 0xd2, 0x3f, 0x82, 0x56,
+	0x6e, 0xe1, 0x0f, 0x2a, 0x55, 0xff, 0x0f, 0x80, 0xda, 0xa5, 0x8d, 0xfa, 0xf2, 0xa8, 0x6a, 0xa5,
+	0x38, 0xda, 0x43, 0x68, 0x8a, 0x96, 0x4d, 0x92, 0x93, 0x22, 0x59, 0xca, 0x8a, 0xa4, 0xf1, 0x18,
+	0x06, 0xc7, 0xfe, 0x95, 0xe5, 0xb9, 0x8e, 0xc5, 0xe9, 0x17, 0x74, 0x89, 0x26, 0x58, 0xdb, 0x81,
+	0x71, 0x0e, 0x6d, 0x75, 0x7f, 0x7f, 0xad, 0x3d, 0xb6, 0xd5, 0x1e, 0xbf, 0x3f, 0x88, 0xde, 0x86,
+	0x9e, 0x12, 0x7a, 0xe2, 0xaa, 0x10, 0x12, 0x3d, 0x06, 0xa3, 0x17, 0xee, 0x8d, 0x12, 0xad, 0x66,
+	0xc6, 0x73, 0xe8, 0xe7, 0x58, 0xd3, 0xe3, 0x5c, 0xd2, 0x65, 0x94, 0xbc, 0x6b, 0x88, 0x71, 0x62,
+	0x81, 0x72, 0x66, 0x01, 0x03, 0xba, 0x6a, 0xe5, 0x4b, 0xca, 0x6f, 0x39, 0xdd, 0x17, 0xe9, 0x46,
+	0x5e, 0x52, 0x25, 0xfc, 0x09, 0x54, 0xa9, 0x38, 0x69, 0xbe, 0x7e, 0xe6, 0x2d, 0x60, 0x4a, 0xf2,
+	0x06, 0x85, 0xcf, 0x53, 0x85, 0x67, 0xb1, 0x54, 0xf8, 0x9a, 0xb2, 0x8c, 0x47, 0xe9, 0x36, 0xce,
+	0x62, 0x7e, 0xdb, 0x17, 0x7d, 0x0c, 0x03, 0xc5, 0x74, 0x48, 0x3d, 0xca, 0xe9, 0x2d, 0x47, 0x7a,
+	0x02, 0xa4, 0xc0, 0x76, 0x9b, 0xb8, 0x07, 0xd0, 0x18, 0x8f, 0x4f, 0x52, 0x6a, 0x31, 0x37, 0x1a,
+	0x1f, 0xc3, 0xe0, 0x3c, 0x76, 0x82, 0x33, 0xe6, 0x5e, 0xb9, 0x1e, 0x9d, 0x49, 0x65, 0x49, 0xf3,
+	0x5b, 0xca, 0x35, 0xbf, 0x1b, 0xab, 0x91, 0xb1, 0x0b, 0xa4, 0xb0, 0x3c, 0xfd, 0x6e, 0x51, 0xec,
+	0x04, 0x2a, 0x84, 0x71, 0x6c, 0xec, 0x42, 0x7b, 0x6c, 0x89, 0x66, 0xc3, 0x91, 0x3c, 0x1a, 0xd4,
+	0xb9, 0x9c, 0x2b, 0xb6, 0x64, 0x6a, 0xec, 0xc3, 0xf6, 0x81, 0x65, 0xcf, 0x5d, 0x7f, 0x76, 0xe8,
+	0x46, 0xa2, 0xdb, 0x52, 0x2b, 0x74, 0x68, 0x38, 0x0a, 0x50, 0x4b, 0xd2, 0xb9, 0xf1, 0x2e, 0xdc,
+	0xcd, 0x3d, 0x1e, 0x9d, 0x73, 0x2b, 0xb1, 0xc7, 0x36, 0x54, 0x23, 0x31, 0xc3, 0x15, 0x55, 0x53,
+	0x4e, 0x8c, 0x2f, 0x61, 0x3b, 0x5f, 0x80, 0x45, 0xef, 0x93, 0x1c, 0x1c, 0xbb, 0x92, 0x52, 0xae,
+	0x2b, 0x51, 0x36, 0x2b, 0x67, 0xf5, 0xa4, 0x0f, 0x95, 0x5f, 0x7f, 0x33, 0x56, 0xce, 0x2e, 0x86,
+	0xc6, 0x1f, 0xe1, 0xee, 0xaa, 0x3c, 0xa9, 0xbe, 0xd0, 0x9a, 0x94, 0x5e, 0xab, 0x35, 0x59, 0xf7,
+	0xb7, 0x77, 0x61, 0x70, 0xea, 0x05, 0xf6, 0xe5, 0x91, 0x9f, 0xb3, 0x86, 0x06, 0x75, 0xea, 0xe7,
+	0x8d, 0x91, 0x4c, 0x8d, 0xb7, 0xa0, 0x77, 0x22, 0x9e, 0xee, 0x4e, 0xc5, 0x5b, 0x4d, 0x6a, 0x05,
+	0x7c, 0xcd, 0x53, 0xac, 0x72, 0x62, 0xbc, 0x0b, 0x5d, 0x55, 0xa2, 0xfd, 0x8b, 0x20, 0xc9, 0x8c,
+	0x59, 0x31, 0x2f, 0x15, 0x1b, 0x7d, 0xe3, 0x04, 0x7a, 0x19, 0xbb, 0x94, 0xfb, 0x16, 0xd4, 0x24,
+	0x59, 0x9d, 0xad, 0x97, 0xde, 0x89, 0x25, 0xa7, 0xa9, 0xc8, 0x1b, 0x0e, 0xb5, 0x80, 0xee, 0x19,
+	0xbe, 0xaa, 0x1e, 0xf9, 0x57, 0x52, 0xd8, 0x31, 0x10, 0xf9, 0xce, 0x3a, 0xa1, 0xfe, 0x95, 0xcb,
+	0x02, 0x1f, 0x9b, 0xeb, 0x92, 0x6a, 0x61, 0x12, 0xc1, 0xe9, 0xa2, 0x84, 0xc3, 0x1c, 0x84, 0xab,
+	0xd0, 0x06, 0x75, 0x87, 0xf0, 0xe6, 0x4b, 0xea, 0x53, 0x66, 0x71, 0x7a, 0x66, 0x45, 0xd1, 0x75,
+	0xc0, 0x9c, 0xcf, 0x58, 0xb0, 0x90, 0x77, 0xd8, 0xe4, 0xb1, 0xf2, 0x21, 0xb4, 0xd4, 0x0b, 0x12,
+	0xde, 0xd8, 0xa4, 0x01, 0x40, 0x42, 0xe2, 0xc2, 0x66, 0x7c, 0x05, 0x0f, 0xbf, 0x4f, 0x8a, 0xf2,
+	0xd2, 0x50, 0x91, 0x12, 0x0b, 0x26, 0xf3, 0x8d, 0x9f, 0x16, 0xb2, 0xa7, 0x24, 0xa1, 0x9f, 0xd1,
+	0x45, 0xc0, 0xe9, 0xc4, 0x72, 0x9c, 0x24, 0x88, 0x41, 0x42, 0x2f, 0x1c, 0x87, 0xed, 0xff, 0xa7,
+	0x0c, 0xf5, 0x4f, 0x65, 0x5d, 0x21, 0x9f, 0x40, 0xa7, 0xd0, 0x45, 0x90, 0xbb, 0xd8, 0x6d, 0xae,
+	0xf6, 0x2c, 0xfa, 0xce, 0x1a, 0x2c, 0x37, 0xfa, 0x1e, 0xb4, 0xf3, 0x3d, 0x02, 0xc1, 0x7e, 0x00,
+	0x1f, 0xb6, 0x75, 0x94, 0xb4, 0xde, 0x40, 0x9c, 0xc3, 0xf6, 0xa6, 0xea, 0x4d, 0x1e, 0x64, 0x1a,
+	0xd6, 0x3b, 0x07, 0xfd, 0x8d, 0xdb, 0xa8, 0x49, 0xd5, 0xaf, 0x1f, 0x78, 0xd4, 0xf2, 0xe3, 0x30,
+	0xbf, 0x83, 0x6c, 0x48, 0x9e, 0x41, 0xa7, 0x50, 0xbf, 0xe4, 0x39, 0xd7, 0x4a, 0x5a, 0x7e, 0xc9,
+	0x13, 0xa8, 0x62, 0xcd, 0x24, 0x9d, 0x42, 0xf1, 0xd6, 0xbb, 0xe9, 0x54, 0xea, 0x1e, 0xc2, 0x16,
+	0x3e, 0x77, 0xe4, 0x14, 0xe3, 0x8a, 0xb4, 0xa0, 0xee, 0xff, 0xbb, 0x04, 0xf5, 0xe4, 0x09, 0xfc,
+	0x19, 0x6c, 0x89, 0xd2, 0x44, 0xee, 0xe4, 0xb2, 0x7b, 0x52, 0xd6, 0xf4, 0xed, 0x15, 0x50, 0x2a,
+	0x18, 0x41, 0xe5, 0x25, 0xe5, 0x84, 0xe4, 0x88, 0xaa, 0x46, 0xe9, 0x77, 0x8a, 0x58, 0xca, 0x7f,
+	0x16, 0x17, 0xf9, 0xcf, 0xe2, 0x75, 0xfe, 0xb4, 0x78, 0x7c, 0x08, 0x35, 0x99, 0xfc, 0xc9, 0xdd,
+	0x1c, 0x39, 0x2b, 0x1b, 0xfa, 0xce, 0x1a, 0x2c, 0xcf, 0xf5, 0xf7, 0x2a, 0xc0, 0xf9, 0x32, 0xe2,
+	0x74, 0xf1, 0x1b, 0x97, 0x5e, 0x93, 0xa7, 0xd0, 0x53, 0x8f, 0x3a, 0x78, 0x83, 0x14, 0x49, 0x2e,
+	0x67, 0x13, 0xec, 0x43, 0xd3, 0x1a, 0xf2, 0x04, 0x5a, 0xa7, 0xd6, 0xcd, 0xab, 0xf9, 0x3e, 0x81,
+	0x4e, 0xa1, 0x34, 0xa8, 0x2d, 0xae, 0x16, 0x1b, 0x7d, 0x67, 0x0d, 0x4e, 0xf4, 0xd4, 0x55, 0xc1,
+	0xc8, 0xeb, 0xc0, 0xd2, 0x5a, 0x28, 0x24, 0x3f, 0x87, 0xde, 0x4a, 0xb9, 0xc8, 0xf3, 0xe3, 0x2b,
+	0xcd, 0xc6, 0x72, 0xf2, 0x1c, 0xfa, 0xab, 0x25, 0x23, 0xbf, 0x50, 0x5d, 0x08, 0x37, 0xd5, 0x94,
+	0x97, 0xd0, 0x5f, 0xcd, 0xf6, 0x44, 0x5b, 0xcd, 0xea, 0x49, 0x4d, 0xd1, 0xef, 0x6d, 0xa2, 0xa4,
+	0x21, 0x98, 0x4f, 0xec, 0x6b, 0x21, 0xb8, 0x9e, 0xf5, 0xdf, 0x01, 0xc8, 0x72, 0x7b, 0x9e, 0x1f,
+	0xdd, 0x63, 0x35, 0xed, 0x7f, 0x00, 0x90, 0x65, 0x6c, 0xe9, 0x55, 0xc5, 0x84, 0xaf, 0xdf, 0x29,
+	0x62, 0x72, 0xd9, 0x53, 0x68, 0xa6, 0x59, 0x36, 0xaf, 0x03, 0x05, 0xac, 0x24, 0xed, 0x39, 0xe8,
+	0xb7, 0x67, 0x44, 0xf2, 0x58, 0xac, 0x78, 0x65, 0xde, 0xd5, 0x1f, 0xbd, 0x8a, 0x2d, 0xf4, 0x96,
+	0x9f, 0x3e, 0xfd, 0xfd, 0xee, 0xcc, 0xe5, 0xf3, 0x78, 0x3a, 0xb2, 0x83, 0xc5, 0xde, 0xdc, 0x8a,
+	0xe6, 0xae, 0x1d, 0xb0, 0x70, 0xef, 0x4a, 0xb8, 0xed, 0x5e, 0xe1, 0x5f, 0xe0, 0xb4, 0x86, 0x37,
+	0xdd, 0xf7, 0xff, 0x3b, 0x00, 0xa7, 0xc9, 0xf4, 0xd4, 0x23, 0x1c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3451,6 +3554,7 @@ type SystemViewClient interface {
 	EntityInfo(ctx context.Context, in *EntityInfoArgs, opts ...grpc.CallOption) (*EntityInfoReply, error)
 	// PluginEnv returns Vault environment information used by plugins
 	PluginEnv(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PluginEnvReply, error)
+	GeneratePasswordFromPolicy(ctx context.Context, in *GeneratePasswordFromPolicyRequest, opts ...grpc.CallOption) (*GeneratePasswordFromPolicyReply, error)
 }
 
 type systemViewClient struct {
@@ -3560,6 +3664,15 @@ func (c *systemViewClient) PluginEnv(ctx context.Context, in *Empty, opts ...grp
 	return out, nil
 }
 
+func (c *systemViewClient) GeneratePasswordFromPolicy(ctx context.Context, in *GeneratePasswordFromPolicyRequest, opts ...grpc.CallOption) (*GeneratePasswordFromPolicyReply, error) {
+	out := new(GeneratePasswordFromPolicyReply)
+	err := c.cc.Invoke(ctx, "/pb.SystemView/GeneratePasswordFromPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SystemViewServer is the server API for SystemView service.
 type SystemViewServer interface {
 	// DefaultLeaseTTL returns the default lease TTL set in Vault configuration
@@ -3598,6 +3711,7 @@ type SystemViewServer interface {
 	EntityInfo(context.Context, *EntityInfoArgs) (*EntityInfoReply, error)
 	// PluginEnv returns Vault environment information used by plugins
 	PluginEnv(context.Context, *Empty) (*PluginEnvReply, error)
+	GeneratePasswordFromPolicy(context.Context, *GeneratePasswordFromPolicyRequest) (*GeneratePasswordFromPolicyReply, error)
 }
 
 // UnimplementedSystemViewServer can be embedded to have forward compatible implementations.
@@ -3637,6 +3751,9 @@ func (*UnimplementedSystemViewServer) EntityInfo(ctx context.Context, req *Entit
 func (*UnimplementedSystemViewServer) PluginEnv(ctx context.Context, req *Empty) (*PluginEnvReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PluginEnv not implemented")
 }
+func (*UnimplementedSystemViewServer) GeneratePasswordFromPolicy(ctx context.Context, req *GeneratePasswordFromPolicyRequest) (*GeneratePasswordFromPolicyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePasswordFromPolicy not implemented")
+}
 
 func RegisterSystemViewServer(s *grpc.Server, srv SystemViewServer) {
 	s.RegisterService(&_SystemView_serviceDesc, srv)
@@ -3840,6 +3957,24 @@ func _SystemView_PluginEnv_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SystemView_GeneratePasswordFromPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeneratePasswordFromPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemViewServer).GeneratePasswordFromPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SystemView/GeneratePasswordFromPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemViewServer).GeneratePasswordFromPolicy(ctx, req.(*GeneratePasswordFromPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SystemView_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.SystemView",
 	HandlerType: (*SystemViewServer)(nil),
@@ -3888,6 +4023,10 @@ var _SystemView_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PluginEnv",
 			Handler:    _SystemView_PluginEnv_Handler,
 		},
+		{
+			MethodName: "GeneratePasswordFromPolicy",
+			Handler:    _SystemView_GeneratePasswordFromPolicy_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sdk/plugin/pb/backend.proto",
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/vault/sdk/helper/errutil"
 	"github.com/hashicorp/vault/sdk/helper/wrapping"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -220,11 +221,12 @@ func TestTranslation_Response(t *testing.T) {
 				Metadata: map[string]string{
 					"test": "test",
 				},
-				ClientToken: "token",
-				Accessor:    "accessor",
-				Period:      5 * time.Second,
-				NumUses:     1,
-				EntityID:    "id",
+				ClientToken:    "token",
+				Accessor:       "accessor",
+				Period:         5 * time.Second,
+				NumUses:        1,
+				EntityID:       "id",
+				BindClientCert: true,
 				Alias: &logical.Alias{
 					MountType:     "type",
 					MountAccessor: "accessor",
@@ -267,3 +269,31 @@ func TestTranslation_Response(t *testing.T) {
 		}
 	}
 }
+
+func TestTranslation_AuthBindClientCert(t *testing.T) {
+	p, err := LogicalAuthToProtoAuth(&logical.Auth{
+		ClientToken:    "token",
+		BindClientCert: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Send the auth through the wire format plugins use
+	buf, err := proto.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Auth
+	if err := proto.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := ProtoAuthToLogicalAuth(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.BindClientCert {
+		t.Fatal("expected the token to remain bound to the client certificate")
+	}
+}
@@ -33,6 +33,11 @@ type ACL struct {
 
 	// Stores policies that are actually RGPs for later fetching
 	rgpPolicies []*Policy
+
+	// entity and groups are the identity of the token the ACL was built for,
+	// against which the policy conditions are evaluated
+	entity *identity.Entity
+	groups []*identity.Group
 }
 
 type PolicyCheckOpts struct {
@@ -122,8 +127,34 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 				raw, ok = tree.Get(pc.Path)
 			}
 
+			perms := pc.Permissions
+			if len(perms.Conditions) > 0 {
+				// The capabilities and parameter constraints of a conditional
+				// stanza only apply to the requests matching its conditions,
+				// so they are held apart from the unconditional ones
+				conditionalPerms, err := perms.Clone()
+				if err != nil {
+					return nil, errwrap.Wrapf("error cloning ACL permissions: {{err}}", err)
+				}
+				conditionalPerms.ConditionalGrants = []*conditionalGrant{
+					{
+						Conditions:         perms.Conditions,
+						CapabilitiesBitmap: perms.CapabilitiesBitmap,
+						AllowedParameters:  conditionalPerms.AllowedParameters,
+						DeniedParameters:   conditionalPerms.DeniedParameters,
+						RequiredParameters: conditionalPerms.RequiredParameters,
+					},
+				}
+				conditionalPerms.Conditions = nil
+				conditionalPerms.CapabilitiesBitmap = 0
+				conditionalPerms.AllowedParameters = nil
+				conditionalPerms.DeniedParameters = nil
+				conditionalPerms.RequiredParameters = nil
+				perms = conditionalPerms
+			}
+
 			if !ok {
-				clonedPerms, err := pc.Permissions.Clone()
+				clonedPerms, err := perms.Clone()
 				if err != nil {
 					return nil, errwrap.Wrapf("error cloning ACL permissions: {{err}}", err)
 				}
@@ -145,17 +176,19 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 				// don't save anything else
 				continue
 
-			case pc.Permissions.CapabilitiesBitmap&DenyCapabilityInt > 0:
+			case perms.CapabilitiesBitmap&DenyCapabilityInt > 0:
 				// If this new policy explicitly denies, only save the deny value
 				existingPerms.CapabilitiesBitmap = DenyCapabilityInt
 				existingPerms.AllowedParameters = nil
 				existingPerms.DeniedParameters = nil
+				existingPerms.ConditionalGrants = nil
 				goto INSERT
 
 			default:
 				// Insert the capabilities in this new policy into the existing
 				// value
-				existingPerms.CapabilitiesBitmap = existingPerms.CapabilitiesBitmap | pc.Permissions.CapabilitiesBitmap
+				existingPerms.CapabilitiesBitmap = existingPerms.CapabilitiesBitmap | perms.CapabilitiesBitmap
+				existingPerms.ConditionalGrants = append(existingPerms.ConditionalGrants, perms.ConditionalGrants...)
 			}
 
 			// Note: In these stanzas, we're preferring minimum lifetimes. So
@@ -166,81 +199,29 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 			// If we have an existing max, and we either don't have a current
 			// max, or the current is greater than the previous, use the
 			// existing.
-			if pc.Permissions.MaxWrappingTTL > 0 &&
+			if perms.MaxWrappingTTL > 0 &&
 				(existingPerms.MaxWrappingTTL == 0 ||
-					pc.Permissions.MaxWrappingTTL < existingPerms.MaxWrappingTTL) {
-				existingPerms.MaxWrappingTTL = pc.Permissions.MaxWrappingTTL
+					perms.MaxWrappingTTL < existingPerms.MaxWrappingTTL) {
+				existingPerms.MaxWrappingTTL = perms.MaxWrappingTTL
 			}
 			// If we have an existing min, and we either don't have a current
 			// min, or the current is greater than the previous, use the
 			// existing
-			if pc.Permissions.MinWrappingTTL > 0 &&
+			if perms.MinWrappingTTL > 0 &&
 				(existingPerms.MinWrappingTTL == 0 ||
-					pc.Permissions.MinWrappingTTL < existingPerms.MinWrappingTTL) {
-				existingPerms.MinWrappingTTL = pc.Permissions.MinWrappingTTL
-			}
-
-			if len(pc.Permissions.AllowedParameters) > 0 {
-				if existingPerms.AllowedParameters == nil {
-					clonedAllowed, err := copystructure.Copy(pc.Permissions.AllowedParameters)
-					if err != nil {
-						return nil, err
-					}
-					existingPerms.AllowedParameters = clonedAllowed.(map[string][]interface{})
-				} else {
-					for key, value := range pc.Permissions.AllowedParameters {
-						pcValue, ok := existingPerms.AllowedParameters[key]
-						// If an empty array exist it should overwrite any other
-						// value.
-						if len(value) == 0 || (ok && len(pcValue) == 0) {
-							existingPerms.AllowedParameters[key] = []interface{}{}
-						} else {
-							// Merge the two maps, appending values on key conflict.
-							existingPerms.AllowedParameters[key] = append(value, existingPerms.AllowedParameters[key]...)
-						}
-					}
-				}
+					perms.MinWrappingTTL < existingPerms.MinWrappingTTL) {
+				existingPerms.MinWrappingTTL = perms.MinWrappingTTL
 			}
 
-			if len(pc.Permissions.DeniedParameters) > 0 {
-				if existingPerms.DeniedParameters == nil {
-					clonedDenied, err := copystructure.Copy(pc.Permissions.DeniedParameters)
-					if err != nil {
-						return nil, err
-					}
-					existingPerms.DeniedParameters = clonedDenied.(map[string][]interface{})
-				} else {
-					for key, value := range pc.Permissions.DeniedParameters {
-						pcValue, ok := existingPerms.DeniedParameters[key]
-						// If an empty array exist it should overwrite any other
-						// value.
-						if len(value) == 0 || (ok && len(pcValue) == 0) {
-							existingPerms.DeniedParameters[key] = []interface{}{}
-						} else {
-							// Merge the two maps, appending values on key conflict.
-							existingPerms.DeniedParameters[key] = append(value, existingPerms.DeniedParameters[key]...)
-						}
-					}
-				}
+			if err := mergeACLParameters(existingPerms, perms.AllowedParameters, perms.DeniedParameters, perms.RequiredParameters); err != nil {
+				return nil, err
 			}
 
-			if len(pc.Permissions.RequiredParameters) > 0 {
-				if len(existingPerms.RequiredParameters) == 0 {
-					existingPerms.RequiredParameters = pc.Permissions.RequiredParameters
-				} else {
-					for _, v := range pc.Permissions.RequiredParameters {
-						if !strutil.StrListContains(existingPerms.RequiredParameters, v) {
-							existingPerms.RequiredParameters = append(existingPerms.RequiredParameters, v)
-						}
-					}
-				}
-			}
-
-			if len(pc.Permissions.MFAMethods) > 0 {
+			if len(perms.MFAMethods) > 0 {
 				if existingPerms.MFAMethods == nil {
-					existingPerms.MFAMethods = pc.Permissions.MFAMethods
+					existingPerms.MFAMethods = perms.MFAMethods
 				} else {
-					for _, method := range pc.Permissions.MFAMethods {
+					for _, method := range perms.MFAMethods {
 						existingPerms.MFAMethods = append(existingPerms.MFAMethods, method)
 					}
 				}
@@ -248,12 +229,12 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 			}
 
 			// No need to dedupe this list since any authorization can satisfy any factor
-			if pc.Permissions.ControlGroup != nil {
-				if len(pc.Permissions.ControlGroup.Factors) > 0 {
+			if perms.ControlGroup != nil {
+				if len(perms.ControlGroup.Factors) > 0 {
 					if existingPerms.ControlGroup == nil {
-						existingPerms.ControlGroup = pc.Permissions.ControlGroup
+						existingPerms.ControlGroup = perms.ControlGroup
 					} else {
-						for _, authz := range pc.Permissions.ControlGroup.Factors {
+						for _, authz := range perms.ControlGroup.Factors {
 							existingPerms.ControlGroup.Factors = append(existingPerms.ControlGroup.Factors, authz)
 						}
 					}
@@ -272,6 +253,67 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 	return a, nil
 }
 
+// mergeACLParameters merges the given parameter constraints into the
+// permissions. The maps and slices of the permissions are replaced rather
+// than appended to in place, as they may be shared.
+func mergeACLParameters(existingPerms *ACLPermissions, allowed, denied map[string][]interface{}, required []string) error {
+	if len(allowed) > 0 {
+		if existingPerms.AllowedParameters == nil {
+			clonedAllowed, err := copystructure.Copy(allowed)
+			if err != nil {
+				return err
+			}
+			existingPerms.AllowedParameters = clonedAllowed.(map[string][]interface{})
+		} else {
+			for key, value := range allowed {
+				pcValue, ok := existingPerms.AllowedParameters[key]
+				// If an empty array exist it should overwrite any other
+				// value.
+				if len(value) == 0 || (ok && len(pcValue) == 0) {
+					existingPerms.AllowedParameters[key] = []interface{}{}
+				} else {
+					// Merge the two maps, appending values on key conflict.
+					existingPerms.AllowedParameters[key] = append(append([]interface{}{}, value...), pcValue...)
+				}
+			}
+		}
+	}
+
+	if len(denied) > 0 {
+		if existingPerms.DeniedParameters == nil {
+			clonedDenied, err := copystructure.Copy(denied)
+			if err != nil {
+				return err
+			}
+			existingPerms.DeniedParameters = clonedDenied.(map[string][]interface{})
+		} else {
+			for key, value := range denied {
+				pcValue, ok := existingPerms.DeniedParameters[key]
+				// If an empty array exist it should overwrite any other
+				// value.
+				if len(value) == 0 || (ok && len(pcValue) == 0) {
+					existingPerms.DeniedParameters[key] = []interface{}{}
+				} else {
+					// Merge the two maps, appending values on key conflict.
+					existingPerms.DeniedParameters[key] = append(append([]interface{}{}, value...), pcValue...)
+				}
+			}
+		}
+	}
+
+	if len(required) > 0 {
+		merged := append([]string(nil), existingPerms.RequiredParameters...)
+		for _, v := range required {
+			if !strutil.StrListContains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+		existingPerms.RequiredParameters = merged
+	}
+
+	return nil
+}
+
 func (a *ACL) Capabilities(ctx context.Context, path string) (pathCapabilities []string) {
 	req := &logical.Request{
 		Path: path,
@@ -280,6 +322,12 @@ func (a *ACL) Capabilities(ctx context.Context, path string) (pathCapabilities [
 		Operation: logical.ListOperation,
 	}
 
+	return a.requestCapabilities(ctx, req)
+}
+
+// requestCapabilities returns the capabilities on the path of the request,
+// evaluating the policy conditions against its connection and token
+func (a *ACL) requestCapabilities(ctx context.Context, req *logical.Request) (pathCapabilities []string) {
 	res := a.AllowOperation(ctx, req, true)
 	if res.IsRoot {
 		return []string{RootCapability}
@@ -379,6 +427,12 @@ func (a *ACL) AllowOperation(ctx context.Context, req *logical.Request, capCheck
 	return
 
 CHECK:
+	// Add the capabilities of the conditional stanzas whose conditions are
+	// met by the request
+	if len(permissions.ConditionalGrants) > 0 {
+		permissions, capabilities = a.applyConditionalGrants(req, permissions, capabilities)
+	}
+
 	// Check if the minimum permissions are met
 	// If "deny" has been explicitly set, only deny will be in the map, so we
 	// only need to check for the existence of other values
@@ -512,88 +566,100 @@ type wcPathDescr struct {
 	perms         *ACLPermissions
 }
 
+// wcPathDescrLess returns whether pdi has a lower priority than pdj when both
+// match a path
+func wcPathDescrLess(pdi, pdj wcPathDescr) bool {
+	// In the case of multiple matches, we use this priority order,
+	// which tries to most closely match longest-prefix:
+	//
+	// * First glob or wildcard position (prefer foo/a* over foo/+,
+	//   foo/bar/+/baz over foo/+/bar/baz)
+	// * Whether it's a prefix (prefer foo/+/bar over foo/+/ba*,
+	//   foo/+ over foo/*)
+	// * Number of wildcard segments (prefer foo/bar/+/baz over foo/+/+/baz)
+	// * Length check (prefer foo/+/bar/ba* over foo/+/bar/b*)
+	// * Lexicographical ordering (preferring less, arbitrarily)
+	//
+	// That final case (lexigraphical) should never really come up. It's more
+	// of a throwing-up-hands scenario akin to panic("should not be here")
+	// statements, but less panicky.
+
+	// If the first wildcard (+) or glob (*) occurs earlier in pdi,
+	// pdi is lower priority
+	if pdi.firstWCOrGlob < pdj.firstWCOrGlob {
+		return true
+	} else if pdi.firstWCOrGlob > pdj.firstWCOrGlob {
+		return false
+	}
+
+	// If pdi ends in * and pdj doesn't, pdi is lower priority
+	if pdi.isPrefix && !pdj.isPrefix {
+		return true
+	} else if !pdi.isPrefix && pdj.isPrefix {
+		return false
+	}
+
+	// If pdi has more wc segs, pdi is lower priority
+	if pdi.wildcards > pdj.wildcards {
+		return true
+	} else if pdi.wildcards < pdj.wildcards {
+		return false
+	}
+
+	// If pdi is shorter, it is lower priority
+	if len(pdi.wcPath) < len(pdj.wcPath) {
+		return true
+	} else if len(pdi.wcPath) > len(pdj.wcPath) {
+		return false
+	}
+
+	// If pdi is smaller lexicographically, it is lower priority
+	if pdi.wcPath < pdj.wcPath {
+		return true
+	} else if pdi.wcPath > pdj.wcPath {
+		return false
+	}
+	return false
+}
+
 // CheckAllowedFromNonExactPaths returns permissions corresponding to a
 // matching path with wildcards/globs. If bareMount is true, the path should
 // correspond to a mount prefix, and what is returned is either a non-nil set
 // of permissions from some allowed path underneath the mount (for use in mount
 // access checks), or nil indicating no non-deny permissions were found.
 func (a *ACL) CheckAllowedFromNonExactPaths(path string, bareMount bool) *ACLPermissions {
+	permissions, _ := a.nonExactPathMatches(path, bareMount)
+	return permissions
+}
+
+// nonExactPathMatches returns the permissions as CheckAllowedFromNonExactPaths
+// does, along with the prefix and segment wildcard paths matching the path in
+// increasing order of priority when bareMount is false.
+func (a *ACL) nonExactPathMatches(path string, bareMount bool) (*ACLPermissions, []wcPathDescr) {
 	wcPathDescrs := make([]wcPathDescr, 0, len(a.segmentWildcardPaths)+1)
 
 	less := func(i, j int) bool {
-		// In the case of multiple matches, we use this priority order,
-		// which tries to most closely match longest-prefix:
-		//
-		// * First glob or wildcard position (prefer foo/a* over foo/+,
-		//   foo/bar/+/baz over foo/+/bar/baz)
-		// * Whether it's a prefix (prefer foo/+/bar over foo/+/ba*,
-		//   foo/+ over foo/*)
-		// * Number of wildcard segments (prefer foo/bar/+/baz over foo/+/+/baz)
-		// * Length check (prefer foo/+/bar/ba* over foo/+/bar/b*)
-		// * Lexicographical ordering (preferring less, arbitrarily)
-		//
-		// That final case (lexigraphical) should never really come up. It's more
-		// of a throwing-up-hands scenario akin to panic("should not be here")
-		// statements, but less panicky.
-
-		pdi, pdj := wcPathDescrs[i], wcPathDescrs[j]
-
-		// If the first wildcard (+) or glob (*) occurs earlier in pdi,
-		// pdi is lower priority
-		if pdi.firstWCOrGlob < pdj.firstWCOrGlob {
-			return true
-		} else if pdi.firstWCOrGlob > pdj.firstWCOrGlob {
-			return false
-		}
-
-		// If pdi ends in * and pdj doesn't, pdi is lower priority
-		if pdi.isPrefix && !pdj.isPrefix {
-			return true
-		} else if !pdi.isPrefix && pdj.isPrefix {
-			return false
-		}
-
-		// If pdi has more wc segs, pdi is lower priority
-		if pdi.wildcards > pdj.wildcards {
-			return true
-		} else if pdi.wildcards < pdj.wildcards {
-			return false
-		}
-
-		// If pdi is shorter, it is lower priority
-		if len(pdi.wcPath) < len(pdj.wcPath) {
-			return true
-		} else if len(pdi.wcPath) > len(pdj.wcPath) {
-			return false
-		}
-
-		// If pdi is smaller lexicographically, it is lower priority
-		if pdi.wcPath < pdj.wcPath {
-			return true
-		} else if pdi.wcPath > pdj.wcPath {
-			return false
-		}
-		return false
+		return wcPathDescrLess(wcPathDescrs[i], wcPathDescrs[j])
 	}
 
 	// Find a prefix rule if any.
 	{
 		prefix, raw, ok := a.prefixRules.LongestPrefix(path)
 		if ok {
-			if len(a.segmentWildcardPaths) == 0 {
-				return raw.(*ACLPermissions)
-			}
 			wcPathDescrs = append(wcPathDescrs, wcPathDescr{
 				firstWCOrGlob: len(prefix),
 				wcPath:        prefix,
 				isPrefix:      true,
 				perms:         raw.(*ACLPermissions),
 			})
+			if len(a.segmentWildcardPaths) == 0 {
+				return raw.(*ACLPermissions), wcPathDescrs
+			}
 		}
 	}
 
 	if len(a.segmentWildcardPaths) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	pathParts := strings.Split(path, "/")
@@ -648,8 +714,8 @@ SWCPATH:
 				// check permissions. If they're defined but not deny, success.
 				if strings.HasPrefix(joinedPath, path) {
 					permissions := a.segmentWildcardPaths[fullWCPath].(*ACLPermissions)
-					if permissions.CapabilitiesBitmap&DenyCapabilityInt == 0 && permissions.CapabilitiesBitmap > 0 {
-						return permissions
+					if permissions.CapabilitiesBitmap&DenyCapabilityInt == 0 && (permissions.CapabilitiesBitmap > 0 || permissions.hasConditionalAccess()) {
+						return permissions, nil
 					}
 				}
 				continue SWCPATH
@@ -660,14 +726,14 @@ SWCPATH:
 	}
 
 	if bareMount || len(wcPathDescrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// We don't do this in the bare mount check because we don't care about
 	// priority, we only care about any capability at all.
 	sort.Slice(wcPathDescrs, less)
 
-	return wcPathDescrs[len(wcPathDescrs)-1].perms
+	return wcPathDescrs[len(wcPathDescrs)-1].perms, wcPathDescrs
 }
 
 func (c *Core) performPolicyChecks(ctx context.Context, acl *ACL, te *logical.TokenEntry, req *logical.Request, inEntity *identity.Entity, opts *PolicyCheckOpts) *AuthResults {
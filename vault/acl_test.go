@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -97,6 +98,194 @@ path "secret/split/definition" {
 	}
 }
 
+func TestACL_Conditions(t *testing.T) {
+	t.Run("root-ns", func(t *testing.T) {
+		t.Parallel()
+		testACLConditions(t, namespace.RootNamespace)
+	})
+}
+
+func testACLConditions(t *testing.T, ns *namespace.Namespace) {
+	rules := `
+path "secret/office" {
+	capabilities = ["read"]
+	condition {
+		source_cidrs = ["10.0.0.0/8"]
+	}
+}
+path "secret/office" {
+	capabilities = ["update"]
+	condition {
+		source_cidrs = ["10.0.0.0/8"]
+		mfa_validated = true
+	}
+}
+path "secret/team" {
+	capabilities = ["read"]
+	condition {
+		entity_metadata = {
+			team = "ops-*"
+		}
+	}
+	condition {
+		group_metadata = {
+			clearance = "high"
+		}
+	}
+}
+path "secret/shared" {
+	capabilities = ["read"]
+}
+path "secret/shared" {
+	capabilities = ["deny"]
+	condition {
+		source_cidrs = ["192.168.0.0/16"]
+	}
+}
+`
+
+	policy, err := ParseACLPolicy(ns, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := namespace.ContextWithNamespace(context.Background(), ns)
+	acl, err := NewACL(ctx, []*Policy{policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type tcase struct {
+		path         string
+		remoteAddr   string
+		mfaValidated bool
+		entity       *identity.Entity
+		groups       []*identity.Group
+		expected     []string
+	}
+
+	opsEntity := &identity.Entity{Metadata: map[string]string{"team": "ops-east"}}
+	devEntity := &identity.Entity{Metadata: map[string]string{"team": "dev"}}
+	highGroup := &identity.Group{Metadata: map[string]string{"clearance": "high"}}
+	lowGroup := &identity.Group{Metadata: map[string]string{"clearance": "low"}}
+
+	tcases := []tcase{
+		{"secret/office", "10.1.2.3", false, nil, nil, []string{"read"}},
+		{"secret/office", "10.1.2.3", true, nil, nil, []string{"read", "update"}},
+		{"secret/office", "172.16.0.1", true, nil, nil, []string{"deny"}},
+		{"secret/office", "", true, nil, nil, []string{"deny"}},
+		{"secret/team", "", false, opsEntity, []*identity.Group{lowGroup, highGroup}, []string{"read"}},
+		{"secret/team", "", false, opsEntity, []*identity.Group{lowGroup}, []string{"deny"}},
+		{"secret/team", "", false, devEntity, []*identity.Group{highGroup}, []string{"deny"}},
+		{"secret/team", "", false, nil, nil, []string{"deny"}},
+		{"secret/shared", "10.1.2.3", false, nil, nil, []string{"read"}},
+		{"secret/shared", "192.168.1.1", false, nil, nil, []string{"deny"}},
+	}
+
+	for _, tc := range tcases {
+		acl.entity = tc.entity
+		acl.groups = tc.groups
+
+		req := &logical.Request{
+			Operation: logical.ListOperation,
+			Path:      tc.path,
+		}
+		if tc.remoteAddr != "" {
+			req.Connection = &logical.Connection{RemoteAddr: tc.remoteAddr}
+		}
+		req.SetTokenEntry(&logical.TokenEntry{MFAValidated: tc.mfaValidated})
+
+		actual := acl.requestCapabilities(ctx, req)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("bad: %#v\ngot\n%#v\nexpected\n%#v\n", tc, actual, tc.expected)
+		}
+
+		req.Operation = logical.ReadOperation
+		allowed := acl.AllowOperation(ctx, req, false).Allowed
+		if allowed != (tc.expected[0] == "read") {
+			t.Fatalf("bad: %#v, allowed: %t", tc, allowed)
+		}
+	}
+}
+
+func TestACL_ConditionalParameters(t *testing.T) {
+	ns := namespace.RootNamespace
+	rules := `
+path "secret/config" {
+	capabilities = ["update"]
+}
+path "secret/config" {
+	capabilities = ["update"]
+	allowed_parameters = {
+		"ttl" = []
+	}
+	required_parameters = ["ttl"]
+	condition {
+		source_cidrs = ["10.0.0.0/8"]
+	}
+}
+path "+/office/*" {
+	capabilities = ["read"]
+	condition {
+		source_cidrs = ["10.0.0.0/8"]
+	}
+}
+`
+
+	policy, err := ParseACLPolicy(ns, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := namespace.ContextWithNamespace(context.Background(), ns)
+	acl, err := NewACL(ctx, []*Policy{policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type tcase struct {
+		remoteAddr string
+		data       map[string]interface{}
+		allowed    bool
+	}
+
+	tcases := []tcase{
+		{"172.16.0.1", map[string]interface{}{"max_ttl": "1h"}, true},
+		{"172.16.0.1", map[string]interface{}{"ttl": "1h"}, true},
+		{"10.1.2.3", map[string]interface{}{"ttl": "1h"}, true},
+		{"10.1.2.3", map[string]interface{}{"max_ttl": "1h"}, false},
+		{"10.1.2.3", map[string]interface{}{"ttl": "1h", "max_ttl": "1h"}, false},
+	}
+
+	for _, tc := range tcases {
+		req := &logical.Request{
+			Operation:  logical.UpdateOperation,
+			Path:       "secret/config",
+			Data:       tc.data,
+			Connection: &logical.Connection{RemoteAddr: tc.remoteAddr},
+		}
+		allowed := acl.AllowOperation(ctx, req, false).Allowed
+		if allowed != tc.allowed {
+			t.Fatalf("bad: %#v, allowed: %t", tc, allowed)
+		}
+	}
+
+	// The constraints of the conditional stanza must not have been merged into
+	// the permissions shared by every request
+	raw, ok := acl.exactRules.Get("secret/config")
+	if !ok {
+		t.Fatal("missing rule")
+	}
+	perms := raw.(*ACLPermissions)
+	if len(perms.AllowedParameters) != 0 || len(perms.RequiredParameters) != 0 {
+		t.Fatalf("bad: %#v", perms)
+	}
+
+	if acl.CheckAllowedFromNonExactPaths("kv/office/", true) == nil {
+		t.Fatal("expected conditional grant to give access to the mount")
+	}
+}
+
 func TestACL_Capabilities(t *testing.T) {
 	t.Run("root-ns", func(t *testing.T) {
 		t.Parallel()
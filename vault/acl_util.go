@@ -5,10 +5,22 @@ package vault
 import (
 	"context"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
 func (c *Core) performEntPolicyChecks(ctx context.Context, acl *ACL, te *logical.TokenEntry, req *logical.Request, inEntity *identity.Entity, opts *PolicyCheckOpts, ret *AuthResults) {
+	// Requests on paths protected by a control group are held until they are
+	// approved, after which they are run again with the authorizations
+	// attached
+	if ret.ACLResults != nil && ret.ACLResults.ControlGroup != nil &&
+		(req.ControlGroup == nil || !req.ControlGroup.Approved) {
+		ret.Error = multierror.Append(ret.Error, &controlGroupError{
+			controlGroup: ret.ACLResults.ControlGroup,
+		})
+		return
+	}
+
 	ret.Allowed = true
 }
@@ -48,6 +48,11 @@ var (
 
 // enableCredential is used to enable a new credential backend
 func (c *Core) enableCredential(ctx context.Context, entry *MountEntry) error {
+	// Ensure the token backend is a singleton
+	if entry.Type == "token" {
+		return fmt.Errorf("token credential backend cannot be instantiated")
+	}
+
 	return c.enableCredentialInternal(ctx, entry, MountTableUpdateStorage)
 }
 
@@ -90,11 +95,6 @@ func (c *Core) enableCredentialInternal(ctx context.Context, entry *MountEntry,
 		}
 	}
 
-	// Ensure the token backend is a singleton
-	if entry.Type == "token" {
-		return fmt.Errorf("token credential backend cannot be instantiated")
-	}
-
 	// Check for conflicts according to the router
 	if conflict := c.router.MountConflict(ctx, credentialRoutePrefix+entry.Path); conflict != "" {
 		return logical.CodedError(409, fmt.Sprintf("existing mount at %s", conflict))
@@ -661,8 +661,9 @@ func (c *Core) setupCredentials(ctx context.Context) error {
 			c.router.Taint(ctx, path)
 		}
 
-		// Check if this is the token store
-		if entry.Type == "token" {
+		// Check if this is the token store of the root namespace; the other
+		// namespaces share it
+		if entry.Type == "token" && entry.NamespaceID == namespace.RootNamespaceID {
 			c.tokenStore = backend.(*TokenStore)
 
 			// At some point when this isn't beta we may persist this but for
@@ -719,6 +720,14 @@ func (c *Core) newCredentialBackend(ctx context.Context, entry *MountEntry, sysV
 		t = alias
 	}
 
+	// Namespaces share the token store of the root namespace
+	if t == "token" && entry.NamespaceID != "" && entry.NamespaceID != namespace.RootNamespaceID {
+		if c.tokenStore == nil {
+			return nil, errors.New("token store is not set up")
+		}
+		return &sharedBackend{c.tokenStore}, nil
+	}
+
 	f, ok := c.credentialBackends[t]
 	if !ok {
 		f = plugin.Factory
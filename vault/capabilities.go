@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 
+	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -11,6 +12,12 @@ import (
 // Capabilities is used to fetch the capabilities of the given token on the
 // given path
 func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string, error) {
+	return c.capabilities(ctx, token, path, nil)
+}
+
+// capabilities fetches the capabilities of the given token on the given path,
+// evaluating the conditions of its policies against the given connection
+func (c *Core) capabilities(ctx context.Context, token, path string, conn *logical.Connection) ([]string, error) {
 	if path == "" {
 		return nil, &logical.StatusBadRequest{Err: "missing path"}
 	}
@@ -27,12 +34,46 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 		return nil, &logical.StatusBadRequest{Err: "invalid token"}
 	}
 
-	tokenNS, err := NamespaceByID(ctx, te.NamespaceID, c)
+	tokenNS, entity, policyNames, err := c.tokenPolicyNames(ctx, te)
+	if err != nil {
+		return nil, err
+	}
+	if len(policyNames) == 0 {
+		return []string{DenyCapability}, nil
+	}
+
+	// Construct the corresponding ACL object. ACL construction should be
+	// performed on the token's namespace.
+	tokenCtx := namespace.ContextWithNamespace(ctx, tokenNS)
+	acl, err := c.policyStore.ACL(tokenCtx, entity, policyNames)
 	if err != nil {
 		return nil, err
 	}
+
+	req := &logical.Request{
+		Path: path,
+		// doesn't matter, but use List to trigger fallback behavior so we can
+		// model real behavior
+		Operation:  logical.ListOperation,
+		Connection: conn,
+	}
+	req.SetTokenEntry(te)
+
+	capabilities := acl.requestCapabilities(ctx, req)
+	sort.Strings(capabilities)
+	return capabilities, nil
+}
+
+// tokenPolicyNames returns the namespace and entity of the token, along with
+// the names of the policies, per namespace ID, it is entitled to. No policy
+// names are returned if the token has no policies.
+func (c *Core) tokenPolicyNames(ctx context.Context, te *logical.TokenEntry) (*namespace.Namespace, *identity.Entity, map[string][]string, error) {
+	tokenNS, err := NamespaceByID(ctx, te.NamespaceID, c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	if tokenNS == nil {
-		return nil, namespace.ErrNoNamespace
+		return nil, nil, nil, namespace.ErrNoNamespace
 	}
 
 	var policyCount int
@@ -42,15 +83,15 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 
 	entity, identityPolicies, err := c.fetchEntityAndDerivedPolicies(ctx, tokenNS, te.EntityID)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	if entity != nil && entity.Disabled {
 		c.logger.Warn("permission denied as the entity on the token is disabled")
-		return nil, logical.ErrPermissionDenied
+		return nil, nil, nil, logical.ErrPermissionDenied
 	}
 	if te.EntityID != "" && entity == nil {
 		c.logger.Warn("permission denied as the entity on the token is invalid")
-		return nil, logical.ErrPermissionDenied
+		return nil, nil, nil, logical.ErrPermissionDenied
 	}
 
 	for nsID, nsPolicies := range identityPolicies {
@@ -59,18 +100,7 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 	}
 
 	if policyCount == 0 {
-		return []string{DenyCapability}, nil
-	}
-
-	// Construct the corresponding ACL object. ACL construction should be
-	// performed on the token's namespace.
-	tokenCtx := namespace.ContextWithNamespace(ctx, tokenNS)
-	acl, err := c.policyStore.ACL(tokenCtx, entity, policyNames)
-	if err != nil {
-		return nil, err
+		return tokenNS, entity, nil, nil
 	}
-
-	capabilities := acl.Capabilities(ctx, path)
-	sort.Strings(capabilities)
-	return capabilities, nil
+	return tokenNS, entity, policyNames, nil
 }
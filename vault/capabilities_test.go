@@ -226,3 +226,93 @@ func TestCapabilities(t *testing.T) {
 		t.Fatalf("bad: got\n%#v\nexpected\n%#v\n", actual, expected)
 	}
 }
+
+func TestCapabilities_Conditions(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	policy, err := ParseACLPolicy(namespace.RootNamespace, `
+name = "conditional"
+path "secret/office" {
+	capabilities = ["read"]
+	condition {
+		source_cidrs = ["10.0.0.0/8"]
+	}
+}
+path "secret/cleared" {
+	capabilities = ["read"]
+	condition {
+		group_metadata = {
+			clearance = "high"
+		}
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.policyStore.SetPolicy(namespace.RootContext(nil), policy); err != nil {
+		t.Fatal(err)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "identity/entity")
+	req.ClientToken = root
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	entityID := resp.Data["id"].(string)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "identity/group")
+	req.ClientToken = root
+	req.Data["member_entity_ids"] = []string{entityID}
+	req.Data["metadata"] = map[string]string{"clearance": "high"}
+	resp, err = c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	ent := &logical.TokenEntry{
+		ID:       "capabilitiestoken",
+		Path:     "auth/token/create",
+		Policies: []string{"default", "conditional"},
+		EntityID: entityID,
+		TTL:      time.Hour,
+	}
+	testMakeTokenDirectly(t, c.tokenStore, ent)
+
+	capabilitiesSelf := func(path, remoteAddr string) []string {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/capabilities-self")
+		req.ClientToken = "capabilitiestoken"
+		req.Connection = &logical.Connection{RemoteAddr: remoteAddr}
+		req.Data["paths"] = []string{path}
+		resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+		}
+		return resp.Data[path].([]string)
+	}
+
+	for _, tc := range []struct {
+		path       string
+		remoteAddr string
+		expected   []string
+	}{
+		{"secret/office", "10.1.2.3", []string{"read"}},
+		{"secret/office", "192.168.1.1", []string{"deny"}},
+		{"secret/cleared", "192.168.1.1", []string{"read"}},
+	} {
+		actual := capabilitiesSelf(tc.path, tc.remoteAddr)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("bad: %s from %s: got\n%#v\nexpected\n%#v\n", tc.path, tc.remoteAddr, actual, tc.expected)
+		}
+	}
+
+	// Without a connection, source CIDR conditions are never met
+	actual, err := c.Capabilities(namespace.RootContext(nil), "capabilitiestoken", "secret/office")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, []string{"deny"}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
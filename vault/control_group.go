@@ -0,0 +1,574 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// controlGroupCubbyholePath is where the pending request is stored in the
+	// cubbyhole of its control group token
+	controlGroupCubbyholePath = "cubbyhole/control-group"
+)
+
+// controlGroupError is returned by the policy checks when a request matches a
+// path protected by a control group and hasn't been approved yet
+type controlGroupError struct {
+	controlGroup *ControlGroup
+}
+
+func (e *controlGroupError) Error() string {
+	return "request requires control group authorization"
+}
+
+// controlGroupRequest is a request pending the approval of a control group
+type controlGroupRequest struct {
+	ID             string                       `json:"id"`
+	Path           string                       `json:"path"`
+	Operation      logical.Operation            `json:"operation"`
+	Data           map[string]interface{}       `json:"data"`
+	Accessor       string                       `json:"accessor"`
+	EntityID       string                       `json:"entity_id"`
+	RemoteAddr     string                       `json:"remote_addr"`
+	NamespaceID    string                       `json:"namespace_id"`
+	RequestTime    time.Time                    `json:"request_time"`
+	Factors        []*ControlGroupFactor        `json:"factors"`
+	Authorizations []*controlGroupAuthorization `json:"authorizations"`
+}
+
+// controlGroupAuthorization records the approval of a control group request
+// by an authorizer
+type controlGroupAuthorization struct {
+	EntityID          string    `json:"entity_id"`
+	Accessor          string    `json:"accessor"`
+	AuthorizationTime time.Time `json:"authorization_time"`
+}
+
+// createControlGroupRequest stores the given request in the cubbyhole of a new
+// control group token and returns a response holding the token as wrapping
+// information. The requester unwraps it to run the request once the control
+// group approved it.
+func (c *Core) createControlGroupRequest(ctx context.Context, req *logical.Request, auth *logical.Auth, cg *ControlGroup) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the accessor of the requester's token is stored, so that its ID
+	// can't be read back from the cubbyhole; the token is looked up again
+	// when the request runs
+	requesterTE := req.TokenEntry()
+	if requesterTE == nil {
+		return nil, logical.ErrPermissionDenied
+	}
+
+	ttl := cg.TTL
+	if ttl == 0 || ttl > c.maxLeaseTTL {
+		ttl = c.maxLeaseTTL
+	}
+
+	creationTime := time.Now()
+	te := &logical.TokenEntry{
+		Path:           req.Path,
+		Policies:       []string{controlGroupPolicyName},
+		CreationTime:   creationTime.Unix(),
+		TTL:            ttl,
+		NumUses:        1,
+		ExplicitMaxTTL: ttl,
+		NamespaceID:    ns.ID,
+	}
+	if err := c.tokenStore.create(ctx, te); err != nil {
+		c.logger.Error("failed to create control group token", "error", err)
+		return nil, ErrInternalError
+	}
+
+	// The request runs again later as the original requester, so keep the
+	// address it came from for the token's CIDR checks
+	var remoteAddr string
+	if req.Connection != nil {
+		remoteAddr = req.Connection.RemoteAddr
+	}
+	cgReq := &controlGroupRequest{
+		ID:          req.ID,
+		Path:        req.Path,
+		Operation:   req.Operation,
+		Data:        req.Data,
+		Accessor:    requesterTE.Accessor,
+		EntityID:    auth.EntityID,
+		RemoteAddr:  remoteAddr,
+		NamespaceID: ns.ID,
+		RequestTime: creationTime,
+		Factors:     cg.Factors,
+	}
+	if err := c.storeControlGroupRequest(ctx, te, cgReq); err != nil {
+		c.tokenStore.revokeOrphan(ctx, te.ID)
+		c.logger.Error("failed to store control group request", "error", err)
+		return nil, ErrInternalError
+	}
+
+	// Store the same information as response wrapping so that the token can
+	// be looked up through sys/wrapping/lookup
+	cubbyReq := &logical.Request{
+		Operation:   logical.CreateOperation,
+		Path:        "cubbyhole/wrapinfo",
+		ClientToken: te.ID,
+		Data: map[string]interface{}{
+			"creation_ttl":  ttl,
+			"creation_time": creationTime,
+			"creation_path": req.Path,
+		},
+	}
+	cubbyReq.SetTokenEntry(te)
+	if _, err := c.router.Route(ctx, cubbyReq); err != nil {
+		c.tokenStore.revokeOrphan(ctx, te.ID)
+		c.logger.Error("failed to store control group token information", "error", err)
+		return nil, ErrInternalError
+	}
+
+	cgAuth := &logical.Auth{
+		ClientToken: te.ID,
+		Policies:    te.Policies,
+		LeaseOptions: logical.LeaseOptions{
+			TTL:       ttl,
+			Renewable: false,
+		},
+	}
+	if err := c.expiration.RegisterAuth(ctx, te, cgAuth); err != nil {
+		c.tokenStore.revokeOrphan(ctx, te.ID)
+		c.logger.Error("failed to register control group token lease", "request_path", req.Path, "error", err)
+		return nil, ErrInternalError
+	}
+
+	return &logical.Response{
+		WrapInfo: &wrapping.ResponseWrapInfo{
+			Token:           te.ID,
+			Accessor:        te.Accessor,
+			TTL:             ttl,
+			CreationTime:    creationTime,
+			CreationPath:    req.Path,
+			WrappedEntityID: auth.EntityID,
+		},
+	}, nil
+}
+
+func (c *Core) storeControlGroupRequest(ctx context.Context, te *logical.TokenEntry, cgReq *controlGroupRequest) error {
+	encoded, err := json.Marshal(cgReq)
+	if err != nil {
+		return err
+	}
+
+	cubbyReq := &logical.Request{
+		Operation:   logical.UpdateOperation,
+		Path:        controlGroupCubbyholePath,
+		ClientToken: te.ID,
+		Data: map[string]interface{}{
+			"request": string(encoded),
+		},
+	}
+	cubbyReq.SetTokenEntry(te)
+	resp, err := c.router.Route(ctx, cubbyReq)
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.IsError() {
+		return resp.Error()
+	}
+	return nil
+}
+
+func (c *Core) controlGroupRequestByToken(ctx context.Context, te *logical.TokenEntry) (*controlGroupRequest, error) {
+	cubbyReq := &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        controlGroupCubbyholePath,
+		ClientToken: te.ID,
+	}
+	cubbyReq.SetTokenEntry(te)
+	resp, err := c.router.Route(ctx, cubbyReq)
+	if err != nil {
+		return nil, errwrap.Wrapf("error looking up control group request: {{err}}", err)
+	}
+	if resp == nil || resp.Data == nil {
+		return nil, nil
+	}
+	if resp.IsError() {
+		return nil, resp.Error()
+	}
+
+	raw, ok := resp.Data["request"].(string)
+	if !ok {
+		return nil, errors.New("could not decode control group request")
+	}
+	var cgReq controlGroupRequest
+	if err := jsonutil.DecodeJSON([]byte(raw), &cgReq); err != nil {
+		return nil, errwrap.Wrapf("error decoding control group request: {{err}}", err)
+	}
+	return &cgReq, nil
+}
+
+// controlGroupTokenByAccessor returns the control group token with the given
+// accessor, or nil if it doesn't exist or isn't a control group token
+func (c *Core) controlGroupTokenByAccessor(ctx context.Context, accessor string) (*logical.TokenEntry, error) {
+	aEntry, err := c.tokenStore.lookupByAccessor(ctx, accessor, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if aEntry.TokenID == "" {
+		return nil, nil
+	}
+	te, err := c.tokenStore.Lookup(ctx, aEntry.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if te == nil || len(te.Policies) != 1 || te.Policies[0] != controlGroupPolicyName {
+		return nil, nil
+	}
+	return te, nil
+}
+
+// controlGroupFactorGroupIDs returns the IDs of the identity groups allowed
+// to authorize the given factor
+func (c *Core) controlGroupFactorGroupIDs(ctx context.Context, cgReq *controlGroupRequest, factor *ControlGroupFactor) (map[string]bool, error) {
+	ret := make(map[string]bool)
+	if factor.Identity == nil {
+		return ret, nil
+	}
+	for _, id := range factor.Identity.GroupIDs {
+		ret[id] = true
+	}
+	if len(factor.Identity.GroupNames) == 0 {
+		return ret, nil
+	}
+
+	// Group names are resolved in the namespace of the policy that required
+	// the control group
+	ns, err := NamespaceByID(ctx, cgReq.NamespaceID, c)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		return nil, namespace.ErrNoNamespace
+	}
+	nsCtx := namespace.ContextWithNamespace(ctx, ns)
+	for _, name := range factor.Identity.GroupNames {
+		group, err := c.identityStore.MemDBGroupByName(nsCtx, name, false)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			ret[group.ID] = true
+		}
+	}
+	return ret, nil
+}
+
+// entityInGroups returns whether the entity is a member, directly or through
+// nested groups, of any of the given groups
+func (c *Core) entityInGroups(entityID string, groupIDs map[string]bool) (bool, error) {
+	direct, inherited, err := c.identityStore.groupsByEntityID(entityID)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range append(direct, inherited...) {
+		if groupIDs[group.ID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// controlGroupAuthorizer returns whether the entity can authorize the given
+// control group request
+func (c *Core) controlGroupAuthorizer(ctx context.Context, cgReq *controlGroupRequest, entityID string) (bool, error) {
+	for _, factor := range cgReq.Factors {
+		groupIDs, err := c.controlGroupFactorGroupIDs(ctx, cgReq, factor)
+		if err != nil {
+			return false, err
+		}
+		member, err := c.entityInGroups(entityID, groupIDs)
+		if err != nil {
+			return false, err
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// controlGroupApproved returns whether every factor of the control group
+// request has gathered its required number of authorizations
+func (c *Core) controlGroupApproved(ctx context.Context, cgReq *controlGroupRequest) (bool, error) {
+	// A request without factors, or with factors no one can authorize, is
+	// never approved
+	if len(cgReq.Factors) == 0 {
+		return false, nil
+	}
+	for _, factor := range cgReq.Factors {
+		if factor.Identity == nil {
+			return false, nil
+		}
+		groupIDs, err := c.controlGroupFactorGroupIDs(ctx, cgReq, factor)
+		if err != nil {
+			return false, err
+		}
+
+		var approvals int
+		for _, authz := range cgReq.Authorizations {
+			member, err := c.entityInGroups(authz.EntityID, groupIDs)
+			if err != nil {
+				return false, err
+			}
+			if member {
+				approvals++
+			}
+		}
+		if approvals < factor.Identity.ApprovalsRequired {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// authorizeControlGroupRequest records the authorization of the control group
+// request whose token has the given accessor by the entity of the request
+func (c *Core) authorizeControlGroupRequest(ctx context.Context, req *logical.Request, accessor string) (*controlGroupRequest, bool, error) {
+	if req.EntityID == "" {
+		return nil, false, logical.CodedError(400, "authorizing token must be tied to an entity")
+	}
+
+	c.controlGroupLock.Lock()
+	defer c.controlGroupLock.Unlock()
+
+	te, err := c.controlGroupTokenByAccessor(ctx, accessor)
+	if err != nil {
+		return nil, false, err
+	}
+	if te == nil {
+		return nil, false, logical.CodedError(400, "invalid accessor")
+	}
+	cgReq, err := c.controlGroupRequestByToken(ctx, te)
+	if err != nil {
+		return nil, false, err
+	}
+	if cgReq == nil {
+		return nil, false, logical.CodedError(400, "no control group request found for accessor")
+	}
+
+	if cgReq.EntityID != "" && cgReq.EntityID == req.EntityID {
+		return nil, false, logical.CodedError(403, "requesters cannot authorize their own request")
+	}
+	authorizer, err := c.controlGroupAuthorizer(ctx, cgReq, req.EntityID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !authorizer {
+		return nil, false, logical.CodedError(403, "entity is not a member of the groups authorizing this request")
+	}
+
+	var found bool
+	for _, authz := range cgReq.Authorizations {
+		if authz.EntityID == req.EntityID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		cgReq.Authorizations = append(cgReq.Authorizations, &controlGroupAuthorization{
+			EntityID:          req.EntityID,
+			Accessor:          req.ClientTokenAccessor,
+			AuthorizationTime: time.Now(),
+		})
+		if err := c.storeControlGroupRequest(ctx, te, cgReq); err != nil {
+			return nil, false, err
+		}
+	}
+
+	approved, err := c.controlGroupApproved(ctx, cgReq)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if c.logger.IsInfo() {
+		c.logger.Info("control group request authorized", "request_path", cgReq.Path, "authorizer_entity_id", req.EntityID, "approved", approved)
+	}
+	return cgReq, approved, nil
+}
+
+// claimControlGroupRequest returns the approved control group request held in
+// the cubbyhole of the given token and revokes the token, so that the request
+// runs exactly once
+func (c *Core) claimControlGroupRequest(ctx context.Context, token string) (*controlGroupRequest, string, error) {
+	c.controlGroupLock.Lock()
+	defer c.controlGroupLock.Unlock()
+
+	te, err := c.tokenStore.lookupTainted(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+	if te == nil {
+		return nil, "", logical.ErrPermissionDenied
+	}
+	cgReq, err := c.controlGroupRequestByToken(ctx, te)
+	if err != nil {
+		return nil, "", err
+	}
+	if cgReq == nil {
+		return nil, "no control group request found", ErrInternalError
+	}
+
+	approved, err := c.controlGroupApproved(ctx, cgReq)
+	if err != nil {
+		return nil, "", err
+	}
+	if !approved {
+		return nil, "request needs further approval", logical.ErrInvalidRequest
+	}
+
+	if err := c.tokenStore.revokeOrphan(ctx, te.ID); err != nil {
+		return nil, "", errwrap.Wrapf("error revoking control group token: {{err}}", err)
+	}
+	return cgReq, "", nil
+}
+
+// controlGroupRun runs the approved control group request held in the
+// cubbyhole of the given token and returns the marshaled HTTP response
+func (c *Core) controlGroupRun(ctx context.Context, token string) (string, error) {
+	cgReq, msg, err := c.claimControlGroupRequest(ctx, token)
+	if err != nil {
+		return msg, err
+	}
+
+	ns, err := NamespaceByID(ctx, cgReq.NamespaceID, c)
+	if err != nil {
+		return "", err
+	}
+	if ns == nil {
+		return "", namespace.ErrNoNamespace
+	}
+
+	// The request runs as the requester, whose token must still be valid
+	aEntry, err := c.tokenStore.lookupByAccessor(ctx, cgReq.Accessor, false, false)
+	if err != nil {
+		return "", err
+	}
+	if aEntry.TokenID == "" {
+		return "the token that made the request is no longer valid", logical.ErrPermissionDenied
+	}
+
+	authzs := make([]*logical.Authz, 0, len(cgReq.Authorizations))
+	for _, authz := range cgReq.Authorizations {
+		authzs = append(authzs, &logical.Authz{
+			Token:             authz.Accessor,
+			AuthorizationTime: authz.AuthorizationTime,
+		})
+	}
+	runReq := &logical.Request{
+		ID:          cgReq.ID,
+		Operation:   cgReq.Operation,
+		Path:        cgReq.Path,
+		Data:        cgReq.Data,
+		ClientToken: aEntry.TokenID,
+		Connection: &logical.Connection{
+			RemoteAddr: cgReq.RemoteAddr,
+		},
+		ControlGroup: &logical.ControlGroup{
+			Authorizations: authzs,
+			RequestTime:    cgReq.RequestTime,
+			Approved:       true,
+			NamespaceID:    cgReq.NamespaceID,
+		},
+	}
+
+	resp, err := c.handleCancelableRequest(namespace.ContextWithNamespace(ctx, ns), ns, runReq)
+	if err != nil {
+		if resp != nil && resp.IsError() {
+			return resp.Error().Error(), err
+		}
+		return "", err
+	}
+	if resp == nil {
+		return "", nil
+	}
+
+	httpResp := logical.LogicalResponseToHTTPResponse(resp)
+	httpResp.RequestID = cgReq.ID
+	encoded, err := json.Marshal(httpResp)
+	if err != nil {
+		return "", errwrap.Wrapf("failed to marshal control group response: {{err}}", err)
+	}
+
+	if c.logger.IsInfo() {
+		c.logger.Info("control group request completed", "request_path", cgReq.Path, "entity_id", cgReq.EntityID)
+	}
+	return string(encoded), nil
+}
+
+// controlGroupRequestInfo returns the details of a control group request
+// shown to its authorizers
+func (c *Core) controlGroupRequestInfo(ctx context.Context, accessor string) (map[string]interface{}, error) {
+	te, err := c.controlGroupTokenByAccessor(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if te == nil {
+		return nil, logical.CodedError(400, "invalid accessor")
+	}
+	cgReq, err := c.controlGroupRequestByToken(ctx, te)
+	if err != nil {
+		return nil, err
+	}
+	if cgReq == nil {
+		return nil, logical.CodedError(400, "no control group request found for accessor")
+	}
+
+	approved, err := c.controlGroupApproved(ctx, cgReq)
+	if err != nil {
+		return nil, err
+	}
+
+	entityInfo := func(entityID string) map[string]interface{} {
+		ret := map[string]interface{}{
+			"id": entityID,
+		}
+		entity, err := c.identityStore.MemDBEntityByID(entityID, false)
+		if err == nil && entity != nil {
+			ret["name"] = entity.Name
+		}
+		return ret
+	}
+
+	authorizations := make([]map[string]interface{}, 0, len(cgReq.Authorizations))
+	for _, authz := range cgReq.Authorizations {
+		info := entityInfo(authz.EntityID)
+		authorizations = append(authorizations, map[string]interface{}{
+			"entity_id":   info["id"],
+			"entity_name": info["name"],
+		})
+	}
+
+	ret := map[string]interface{}{
+		"approved":       approved,
+		"request_path":   cgReq.Path,
+		"request_time":   cgReq.RequestTime.Format(time.RFC3339Nano),
+		"authorizations": authorizations,
+	}
+	if cgReq.EntityID != "" {
+		ret["request_entity"] = entityInfo(cgReq.EntityID)
+	}
+	return ret, nil
+}
+
+// controlGroupErrorFrom returns the control group error contained in the
+// given error, if any
+func controlGroupErrorFrom(err error) *controlGroupError {
+	cgErr, _ := errwrap.GetType(err, new(controlGroupError)).(*controlGroupError)
+	return cgErr
+}
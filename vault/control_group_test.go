@@ -0,0 +1,229 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/audit"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const testControlGroupPolicy = `
+path "secret/foo" {
+    capabilities = ["read"]
+    control_group = {
+        factor "ops_manager" {
+            identity {
+                group_names = ["managers"]
+                approvals = 1
+            }
+        }
+    }
+}
+`
+
+const testControlGroupAuthorizerPolicy = `
+path "sys/control-group/authorize" {
+    capabilities = ["update"]
+}
+
+path "sys/control-group/request" {
+    capabilities = ["update"]
+}
+`
+
+func testControlGroupRequest(t *testing.T, c *Core, req *logical.Request) *logical.Response {
+	t.Helper()
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: path: %s, resp: %#v, err: %v", req.Path, resp, err)
+	}
+	return resp
+}
+
+func testControlGroupEntity(t *testing.T, c *Core, root, name string) string {
+	req := logical.TestRequest(t, logical.UpdateOperation, "identity/entity")
+	req.ClientToken = root
+	req.Data["name"] = name
+	resp := testControlGroupRequest(t, c, req)
+	return resp.Data["id"].(string)
+}
+
+func TestControlGroup_Workflow(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "secret/foo")
+	req.ClientToken = root
+	req.Data["value"] = "bar"
+	testControlGroupRequest(t, c, req)
+
+	for name, policy := range map[string]string{
+		"cg":         testControlGroupPolicy,
+		"authorizer": testControlGroupAuthorizerPolicy,
+	} {
+		req = logical.TestRequest(t, logical.UpdateOperation, "sys/policy/"+name)
+		req.ClientToken = root
+		req.Data["policy"] = policy
+		testControlGroupRequest(t, c, req)
+	}
+
+	requesterID := testControlGroupEntity(t, c, root, "requester")
+	managerID := testControlGroupEntity(t, c, root, "manager")
+	otherID := testControlGroupEntity(t, c, root, "other")
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "identity/group")
+	req.ClientToken = root
+	req.Data["name"] = "managers"
+	req.Data["member_entity_ids"] = []string{managerID}
+	testControlGroupRequest(t, c, req)
+
+	requester := &logical.TokenEntry{
+		Path:     "auth/token/create",
+		Policies: []string{"cg", "authorizer"},
+		EntityID: requesterID,
+		TTL:      time.Hour,
+	}
+	manager := &logical.TokenEntry{
+		Path:     "auth/token/create",
+		Policies: []string{"authorizer"},
+		EntityID: managerID,
+		TTL:      time.Hour,
+	}
+	other := &logical.TokenEntry{
+		Path:     "auth/token/create",
+		Policies: []string{"authorizer"},
+		EntityID: otherID,
+		TTL:      time.Hour,
+	}
+	for _, te := range []*logical.TokenEntry{requester, manager, other} {
+		testMakeTokenDirectly(t, c.tokenStore, te)
+	}
+
+	noop := &NoopAudit{}
+	c.auditBackends["noop"] = func(ctx context.Context, config *audit.BackendConfig) (audit.Backend, error) {
+		noop = &NoopAudit{
+			Config: config,
+		}
+		return noop, nil
+	}
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/audit/noop")
+	req.ClientToken = root
+	req.Data["type"] = "noop"
+	testControlGroupRequest(t, c, req)
+
+	// The request is held and a control group token returned instead
+	req = logical.TestRequest(t, logical.ReadOperation, "secret/foo")
+	req.ClientToken = requester.ID
+	resp := testControlGroupRequest(t, c, req)
+	if resp == nil || resp.WrapInfo == nil || resp.WrapInfo.Token == "" || resp.Data != nil {
+		t.Fatalf("expected a control group token, got %#v", resp)
+	}
+
+	// Both the held request and the control group token are audited
+	if last := noop.Req[len(noop.Req)-1]; last.Path != "secret/foo" {
+		t.Fatalf("expected the held request to be audited, got %#v", last)
+	}
+	if last := noop.Resp[len(noop.Resp)-1]; last.WrapInfo == nil || last.WrapInfo.Accessor != resp.WrapInfo.Accessor {
+		t.Fatalf("expected the control group token to be audited, got %#v", last)
+	}
+	cgToken, cgAccessor := resp.WrapInfo.Token, resp.WrapInfo.Accessor
+
+	// The held request refers to the requester's token by its accessor only
+	cgTE, err := c.tokenStore.Lookup(namespace.RootContext(nil), cgToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cgReq, err := c.controlGroupRequestByToken(namespace.RootContext(nil), cgTE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cgReq.Accessor == "" || cgReq.Accessor != requester.Accessor {
+		t.Fatalf("bad: accessor: %q", cgReq.Accessor)
+	}
+	if encoded, _ := json.Marshal(cgReq); strings.Contains(string(encoded), requester.ID) {
+		t.Fatalf("expected the requester's token not to be stored, got %s", encoded)
+	}
+
+	unwrap := func() (*logical.Response, error) {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/wrapping/unwrap")
+		req.ClientToken = cgToken
+		return c.HandleRequest(namespace.RootContext(nil), req)
+	}
+
+	resp, err = unwrap()
+	if err == nil || !strings.Contains(resp.Error().Error(), "needs further approval") {
+		t.Fatalf("expected unapproved error, got %#v, %v", resp, err)
+	}
+
+	// Requesters and entities outside the groups can't authorize
+	for _, token := range []string{requester.ID, other.ID} {
+		req = logical.TestRequest(t, logical.UpdateOperation, "sys/control-group/authorize")
+		req.ClientToken = token
+		req.Data["accessor"] = cgAccessor
+		if _, err := c.HandleRequest(namespace.RootContext(nil), req); err == nil {
+			t.Fatal("expected error authorizing the request")
+		}
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/control-group/authorize")
+	req.ClientToken = manager.ID
+	req.Data["accessor"] = cgAccessor
+	resp = testControlGroupRequest(t, c, req)
+	if resp.Data["approved"] != true {
+		t.Fatalf("expected the request to be approved, got %#v", resp.Data)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/control-group/request")
+	req.ClientToken = manager.ID
+	req.Data["accessor"] = cgAccessor
+	resp = testControlGroupRequest(t, c, req)
+	if resp.Data["approved"] != true || resp.Data["request_path"] != "secret/foo" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	authzs := resp.Data["authorizations"].([]map[string]interface{})
+	if len(authzs) != 1 || authzs[0]["entity_id"] != managerID || authzs[0]["entity_name"] != "manager" {
+		t.Fatalf("bad: %#v", authzs)
+	}
+	if entity := resp.Data["request_entity"].(map[string]interface{}); entity["id"] != requesterID {
+		t.Fatalf("bad: %#v", entity)
+	}
+
+	// Once approved, unwrapping runs the original request
+	resp, err = unwrap()
+	if err != nil || resp.IsError() {
+		t.Fatalf("bad: %#v, %v", resp, err)
+	}
+	if body := string(resp.Data[logical.HTTPRawBody].([]byte)); !strings.Contains(body, `"value":"bar"`) {
+		t.Fatalf("unexpected unwrapped response: %s", body)
+	}
+
+	// The control group token can only be used once
+	if _, err := unwrap(); err == nil {
+		t.Fatal("expected error unwrapping twice")
+	}
+}
+
+func TestControlGroup_ApprovedWithoutIdentity(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	for _, factors := range [][]*ControlGroupFactor{
+		nil,
+		{{Name: "none"}},
+	} {
+		cgReq := &controlGroupRequest{
+			NamespaceID: namespace.RootNamespaceID,
+			Factors:     factors,
+		}
+		approved, err := c.controlGroupApproved(namespace.RootContext(nil), cgReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if approved {
+			t.Fatalf("expected factors %#v not to approve the request", factors)
+		}
+	}
+}
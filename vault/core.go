@@ -295,6 +295,15 @@ type Core struct {
 	// policy store is used to manage named ACL policies
 	policyStore *PolicyStore
 
+	// namespace store is used to manage the namespaces
+	namespaceStore *NamespaceStore
+
+	// quota store is used to manage and enforce quotas
+	quotaStore *QuotaStore
+
+	// controlGroupLock serializes the updates of control group requests
+	controlGroupLock sync.Mutex
+
 	// token store is used to manage authentication tokens
 	tokenStore *TokenStore
 
@@ -1569,6 +1578,9 @@ func (s standardUnsealStrategy) unseal(ctx context.Context, logger log.Logger, c
 	if err := c.setupPluginCatalog(ctx); err != nil {
 		return err
 	}
+	if err := c.setupNamespaceStore(ctx); err != nil {
+		return err
+	}
 	if err := c.loadMounts(ctx); err != nil {
 		return err
 	}
@@ -1594,6 +1606,9 @@ func (s standardUnsealStrategy) unseal(ctx context.Context, logger log.Logger, c
 		if err := c.startRollback(); err != nil {
 			return err
 		}
+		if err := c.setupQuotaStore(ctx); err != nil {
+			return err
+		}
 		if err := c.setupExpiration(expireLeaseStrategyRevoke); err != nil {
 			return err
 		}
@@ -1721,6 +1736,7 @@ func (c *Core) preSeal() error {
 	if err := c.stopExpiration(); err != nil {
 		result = multierror.Append(result, errwrap.Wrapf("error stopping expiration: {{err}}", err))
 	}
+	c.teardownQuotaStore()
 	if err := c.teardownCredentials(context.Background()); err != nil {
 		result = multierror.Append(result, errwrap.Wrapf("error tearing down credentials: {{err}}", err))
 	}
@@ -1733,6 +1749,7 @@ func (c *Core) preSeal() error {
 	if err := c.unloadMounts(context.Background()); err != nil {
 		result = multierror.Append(result, errwrap.Wrapf("error unloading mounts: {{err}}", err))
 	}
+	c.teardownNamespaceStore()
 	if err := enterprisePreSeal(c); err != nil {
 		result = multierror.Append(result, err)
 	}
@@ -81,7 +81,7 @@ func loadMFAConfigs(context.Context, *Core) error { return nil }
 
 func shouldStartClusterListener(*Core) bool { return true }
 
-func hasNamespaces(*Core) bool { return false }
+func hasNamespaces(*Core) bool { return true }
 
 func (c *Core) Features() license.Features {
 	return license.FeatureNone
@@ -91,8 +91,12 @@ func (c *Core) HasFeature(license.Features) bool {
 	return false
 }
 
-func (c *Core) namepaceByPath(string) *namespace.Namespace {
-	return namespace.RootNamespace
+func (c *Core) namepaceByPath(path string) *namespace.Namespace {
+	ns, ok := c.NamespaceByPath(path)
+	if !ok {
+		return namespace.RootNamespace
+	}
+	return ns
 }
 
 func (c *Core) setupReplicatedClusterPrimary(*replication.Cluster) error { return nil }
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/license"
 	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"github.com/hashicorp/vault/sdk/helper/random"
 	"github.com/hashicorp/vault/sdk/helper/wrapping"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/sdk/version"
@@ -283,3 +284,30 @@ func (d dynamicSystemView) PluginEnv(_ context.Context) (*logical.PluginEnvironm
 		VaultVersion: version.GetVersion().Version,
 	}, nil
 }
+
+// GeneratePasswordFromPolicy generates a password from the password policy
+// with the given name
+func (d dynamicSystemView) GeneratePasswordFromPolicy(ctx context.Context, policyName string) (string, error) {
+	if d.core == nil {
+		return "", fmt.Errorf("system view core is nil")
+	}
+
+	if d.core.systemBarrierView == nil {
+		return "", fmt.Errorf("system barrier view is nil")
+	}
+
+	policy, err := retrievePasswordPolicy(ctx, d.core.systemBarrierView, policyName)
+	if err != nil {
+		return "", errwrap.Wrapf("unable to retrieve password policy: {{err}}", err)
+	}
+	if policy == nil {
+		return "", fmt.Errorf("password policy %q not found", policyName)
+	}
+
+	generator, err := random.ParsePolicy(policy.HCLPolicy)
+	if err != nil {
+		return "", errwrap.Wrapf("stored password policy is invalid: {{err}}", err)
+	}
+
+	return generator.Generate(ctx, nil)
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -36,6 +39,14 @@ const (
 	// tokenViewPrefix is the prefix used for the token based lookup of leases.
 	tokenViewPrefix = "token/"
 
+	// timeViewPrefix is the prefix used for the time index of leases, which
+	// groups them into buckets by expiration time.
+	timeViewPrefix = "time/"
+
+	// timeIndexBuiltPath records that the time index has been built for all
+	// the leases, so that restores can rely on it.
+	timeIndexBuiltPath = "time-index-built"
+
 	// maxRevokeAttempts limits how many revoke attempts are made
 	maxRevokeAttempts = 6
 
@@ -52,6 +63,22 @@ const (
 	maxLeaseThreshold = 256000
 )
 
+var (
+	// leaseTimeBucketWidth is the span of expiration times grouped into a
+	// single bucket of the time index. The timer horizon advances a bucket at
+	// a time.
+	leaseTimeBucketWidth = 5 * time.Minute
+
+	// leaseTimerHorizon is how long before their expiration leases are given
+	// a timer. Leases expiring later are armed from the time index once the
+	// horizon reaches them, so that they don't hold a timer until then.
+	leaseTimerHorizon = time.Hour
+
+	// errExpirationStopped is returned when the expiration manager is
+	// stopped while walking through leases
+	errExpirationStopped = errors.New("expiration manager stopped")
+)
+
 type pendingInfo struct {
 	exportLeaseTimes *leaseEntry
 	timer            *time.Timer
@@ -64,14 +91,21 @@ type pendingInfo struct {
 type ExpirationManager struct {
 	core       *Core
 	router     *Router
+	view       *BarrierView
 	idView     *BarrierView
 	tokenView  *BarrierView
+	timeView   *BarrierView
 	tokenStore *TokenStore
+	quotaStore *QuotaStore
 	logger     log.Logger
 
 	pending     map[string]pendingInfo
 	pendingLock sync.RWMutex
 
+	// timerHorizon is the expiration time up to which leases hold a timer.
+	// It is protected by pendingLock.
+	timerHorizon time.Time
+
 	tidyLock *int32
 
 	restoreMode        *int32
@@ -81,6 +115,14 @@ type ExpirationManager struct {
 	restoreLoaded      sync.Map
 	quitCh             chan struct{}
 
+	// deleteLocks serialize the deletion of leases, so that each lease is
+	// only uncounted once
+	deleteLocks []*locksutil.LockEntry
+
+	// mountLeases holds the number of leases of each mount as an *int64,
+	// keyed by the mount path relative to the root namespace
+	mountLeases sync.Map
+
 	coreStateLock     *sync.RWMutex
 	quitContext       context.Context
 	leaseCheckCounter *uint32
@@ -138,18 +180,24 @@ func NewExpirationManager(c *Core, view *BarrierView, e ExpireLeaseStrategy, log
 	exp := &ExpirationManager{
 		core:       c,
 		router:     c.router,
+		view:       view,
 		idView:     view.SubView(leaseViewPrefix),
 		tokenView:  view.SubView(tokenViewPrefix),
+		timeView:   view.SubView(timeViewPrefix),
 		tokenStore: c.tokenStore,
+		quotaStore: c.quotaStore,
 		logger:     logger,
 		pending:    make(map[string]pendingInfo),
 		tidyLock:   new(int32),
 
+		timerHorizon: timerHorizonAt(time.Now()),
+
 		// new instances of the expiration manager will go immediately into
 		// restore mode
 		restoreMode:  new(int32),
 		restoreLocks: locksutil.CreateLocks(),
 		quitCh:       make(chan struct{}),
+		deleteLocks:  locksutil.CreateLocks(),
 
 		coreStateLock:     &c.stateLock,
 		quitContext:       c.activeContext,
@@ -388,129 +436,135 @@ func (m *ExpirationManager) Restore(errorFunc func()) (retErr error) {
 		}
 	}()
 
-	// Accumulate existing leases
-	m.logger.Debug("collecting leases")
-	existing, leaseCount, err := m.collectLeases()
+	built, err := m.timeIndexBuilt(m.quitContext)
 	if err != nil {
 		return err
 	}
-	m.logger.Debug("leases collected", "num_existing", leaseCount)
 
-	// Make the channels used for the worker pool
+	// Arm the timers of the leases expiring within the timer horizon first,
+	// in order of expiration, so that they are revoked on time regardless of
+	// how long restoring the rest of the leases takes. The timers of the other
+	// leases of the index are armed once the horizon reaches them.
+	if built {
+		m.pendingLock.RLock()
+		horizon := m.timerHorizon
+		m.pendingLock.RUnlock()
+
+		m.logger.Debug("arming lease timers", "horizon", horizon)
+		armed, err := m.restoreLeases(func(emit func(*namespace.Namespace, string) error) error {
+			return m.walkTimeIndex(m.quitContext, time.Time{}, horizon, emit)
+		}, m.armIndexedLease)
+		switch {
+		case err == errExpirationStopped:
+			return nil
+		case err != nil:
+			return err
+		}
+		m.logger.Debug("lease timers armed", "num_indexed", armed)
+	}
+
+	go m.scheduleTimers()
+
+	// Walk the rest of the leases, a storage directory at a time, so that
+	// they are counted. Leases missing from the time index, having been
+	// persisted before it was built or by a node that doesn't maintain it,
+	// are loaded and indexed along the way.
+	m.logger.Debug("restoring leases")
+	restored, err := m.restoreLeases(func(emit func(*namespace.Namespace, string) error) error {
+		return m.walkLeases("", emit)
+	}, m.processRestore)
+	switch {
+	case err == errExpirationStopped:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if !built {
+		if err := m.view.Put(m.quitContext, &logical.StorageEntry{Key: timeIndexBuiltPath, Value: []byte("1")}); err != nil {
+			return errwrap.Wrapf("failed to persist time index state: {{err}}", err)
+		}
+	}
+
+	m.restoreModeLock.Lock()
+	atomic.StoreInt32(m.restoreMode, 0)
+	m.restoreLoaded.Range(func(k, v interface{}) bool {
+		m.restoreLoaded.Delete(k)
+		return true
+	})
+	m.restoreLocks = nil
+	m.restoreModeLock.Unlock()
+
+	m.logger.Info("lease restore complete", "num_leases", restored)
+	return nil
+}
+
+// restoreLeases hands the leases emitted by produce to process on a pool of
+// workers, returning the number of leases processed. Leases are handed over
+// as they are produced so that they never need to be held all at once.
+func (m *ExpirationManager) restoreLeases(produce func(emit func(*namespace.Namespace, string) error) error, process func(context.Context, string) error) (int, error) {
 	type lease struct {
 		namespace *namespace.Namespace
 		id        string
 	}
 	broker := make(chan *lease)
-	quit := make(chan bool)
-	// Buffer these channels to prevent deadlocks
-	errs := make(chan error, len(existing))
-	result := make(chan struct{}, len(existing))
+	quit := make(chan struct{})
+
+	// The first error stops the restore
+	var failOnce sync.Once
+	var failErr error
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failErr = err
+			close(quit)
+		})
+	}
 
-	// Use a wait group
 	wg := &sync.WaitGroup{}
-
-	// Create 64 workers to distribute work to
 	for i := 0; i < consts.ExpirationRestoreWorkerCount; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 
-			for {
-				select {
-				case lease, ok := <-broker:
-					// broker has been closed, we are done
-					if !ok {
-						return
-					}
-
-					ctx := namespace.ContextWithNamespace(m.quitContext, lease.namespace)
-					err := m.processRestore(ctx, lease.id)
-					if err != nil {
-						errs <- err
-						continue
-					}
-
-					// Send message that lease is done
-					result <- struct{}{}
-
-				// quit early
-				case <-quit:
-					return
-
-				case <-m.quitCh:
+			for lease := range broker {
+				ctx := namespace.ContextWithNamespace(m.quitContext, lease.namespace)
+				if err := process(ctx, lease.id); err != nil {
+					fail(err)
 					return
 				}
 			}
 		}()
 	}
 
-	// Distribute the collected keys to the workers in a go routine
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		i := 0
-		for ns := range existing {
-			for _, leaseID := range existing[ns] {
-				i++
-				if i%500 == 0 {
-					m.logger.Debug("leases loading", "progress", i)
-				}
-
-				select {
-				case <-quit:
-					return
-
-				case <-m.quitCh:
-					return
-
-				default:
-					broker <- &lease{
-						namespace: ns,
-						id:        leaseID,
-					}
-				}
-			}
-		}
-
-		// Close the broker, causing worker routines to exit
-		close(broker)
-	}()
-
-	// Ensure all keys on the chan are processed
-	for i := 0; i < leaseCount; i++ {
+	count := 0
+	err := produce(func(ns *namespace.Namespace, id string) error {
 		select {
-		case err := <-errs:
-			// Close all go routines
-			close(quit)
-			return err
-
+		case <-quit:
+			return failErr
 		case <-m.quitCh:
-			close(quit)
-			return nil
+			return errExpirationStopped
+		case broker <- &lease{namespace: ns, id: id}:
+		}
 
-		case <-result:
+		count++
+		if count%500 == 0 {
+			m.logger.Debug("leases loading", "progress", count)
 		}
-	}
+		return nil
+	})
 
 	// Let all go routines finish
+	close(broker)
 	wg.Wait()
 
-	m.restoreModeLock.Lock()
-	atomic.StoreInt32(m.restoreMode, 0)
-	m.restoreLoaded.Range(func(k, v interface{}) bool {
-		m.restoreLoaded.Delete(k)
-		return true
-	})
-	m.restoreLocks = nil
-	m.restoreModeLock.Unlock()
-
-	m.logger.Info("lease restore complete")
-	return nil
+	if failErr != nil {
+		return count, failErr
+	}
+	return count, err
 }
 
 // processRestore takes a lease and restores it in the expiration manager if it has
-// not already been seen
+// not already been seen. Leases missing from the time index are added to it.
 func (m *ExpirationManager) processRestore(ctx context.Context, leaseID string) error {
 	m.restoreRequestLock.RLock()
 	defer m.restoreRequestLock.RUnlock()
@@ -528,11 +582,34 @@ func (m *ExpirationManager) processRestore(ctx context.Context, leaseID string)
 		return nil
 	}
 
-	// Load lease and restore expiration timer
-	_, err := m.loadEntryInternal(ctx, leaseID, true, false)
+	// Load lease and restore expiration timer, which is only armed here if
+	// the lease expires within the timer horizon
+	le, err := m.loadEntryInternal(ctx, leaseID, true, false)
+	if err != nil || le == nil || le.ExpireTime.IsZero() {
+		return err
+	}
+
+	// Check the time index entry of the lease itself rather than holding the
+	// entries of the whole index, which is as large as the lease count
+	key, err := m.timeIndexKey(ctx, le)
 	if err != nil {
 		return err
 	}
+	entry, err := m.timeIndexView(le.namespace).Get(ctx, key)
+	if err != nil {
+		return errwrap.Wrapf("failed to read lease time index entry: {{err}}", err)
+	}
+	if entry != nil {
+		return nil
+	}
+
+	if err := m.indexByTime(ctx, le); err != nil {
+		return err
+	}
+
+	// The timer horizon may have passed the lease before it was indexed, in
+	// which case the scheduler won't have seen it
+	m.updatePending(le, le.ExpireTime.Sub(time.Now()))
 	return nil
 }
 
@@ -1076,6 +1153,8 @@ func (m *ExpirationManager) Register(ctx context.Context, req *logical.Request,
 		Secret:          resp.Secret,
 		IssueTime:       time.Now(),
 		ExpireTime:      resp.Secret.ExpirationTime(),
+		EntityID:        te.EntityID,
+		Role:            quotaRoleName(te.Path, te.Role, te.Meta),
 		namespace:       ns,
 	}
 
@@ -1116,7 +1195,7 @@ func (m *ExpirationManager) Register(ctx context.Context, req *logical.Request,
 	}
 
 	// Encode the entry
-	if err := m.persistEntry(ctx, le); err != nil {
+	if err := m.persistNewEntry(ctx, le); err != nil {
 		return "", err
 	}
 
@@ -1186,11 +1265,13 @@ func (m *ExpirationManager) RegisterAuth(ctx context.Context, te *logical.TokenE
 		Path:        te.Path,
 		IssueTime:   time.Now(),
 		ExpireTime:  auth.ExpirationTime(),
+		EntityID:    auth.EntityID,
+		Role:        quotaRoleName(te.Path, te.Role, auth.Metadata),
 		namespace:   tokenNS,
 	}
 
 	// Encode the entry
-	if err := m.persistEntry(ctx, &le); err != nil {
+	if err := m.persistNewEntry(ctx, &le); err != nil {
 		return err
 	}
 
@@ -1257,8 +1338,9 @@ func (m *ExpirationManager) FetchLeaseTimes(ctx context.Context, leaseID string)
 		return val.exportLeaseTimes, nil
 	}
 
-	// Load the entry
-	le, err := m.loadEntryInternal(ctx, leaseID, true, false)
+	// Load the entry. Leases without a timer are either yet to be restored
+	// or expire beyond the timer horizon, and only the former need restoring.
+	le, err := m.loadEntry(ctx, leaseID)
 	if err != nil {
 		return nil, err
 	}
@@ -1315,12 +1397,22 @@ func (m *ExpirationManager) updatePendingInternal(le *leaseEntry, leaseTotal tim
 		return
 	}
 
+	// Leases expiring beyond the timer horizon are armed by the scheduler
+	// once the horizon reaches their bucket of the time index
+	if !le.ExpireTime.Before(m.timerHorizon) {
+		if ok {
+			pending.timer.Stop()
+			delete(m.pending, le.LeaseID)
+		}
+		return
+	}
+
 	// Create entry if it does not exist or reset if it does
 	if ok {
 		pending.timer.Reset(leaseTotal)
 	} else {
 		timer := time.AfterFunc(leaseTotal, func() {
-			m.expireFunc(m.quitContext, m, le)
+			m.expireLease(le)
 		})
 		pending = pendingInfo{
 			timer: timer,
@@ -1333,6 +1425,39 @@ func (m *ExpirationManager) updatePendingInternal(le *leaseEntry, leaseTotal tim
 	m.pending[le.LeaseID] = pending
 }
 
+// expireLease is invoked when the timer of a lease fires. The lease is read
+// back first, since a timer armed from the time index can race with a renewal
+// that moves the expiration of the lease beyond the timer horizon.
+func (m *ExpirationManager) expireLease(le *leaseEntry) {
+	m.coreStateLock.RLock()
+	select {
+	case <-m.quitCh:
+		m.coreStateLock.RUnlock()
+		return
+	default:
+	}
+	current, err := m.loadEntry(m.quitContext, le.LeaseID)
+	m.coreStateLock.RUnlock()
+
+	switch {
+	case err != nil:
+		// Revocation reads the lease again and retries on failure
+		m.logger.Error("failed to read expiring lease", "lease_id", le.LeaseID, "error", err)
+	case current == nil:
+		m.pendingLock.Lock()
+		delete(m.pending, le.LeaseID)
+		m.pendingLock.Unlock()
+		return
+	case current.ExpireTime.After(time.Now()):
+		m.updatePending(current, current.ExpireTime.Sub(time.Now()))
+		return
+	default:
+		le = current
+	}
+
+	m.expireFunc(m.quitContext, m, le)
+}
+
 // revokeEntry is used to attempt revocation of an internal entry
 func (m *ExpirationManager) revokeEntry(ctx context.Context, le *leaseEntry) error {
 	// Revocation of login tokens is special since we can by-pass the
@@ -1479,6 +1604,8 @@ func (m *ExpirationManager) loadEntryInternal(ctx context.Context, leaseID strin
 		// the lazy loaded restore process
 		m.restoreLoaded.Store(le.LeaseID, struct{}{})
 
+		m.trackLease(m.leaseQuotaAttrs(ctx, le))
+
 		// Setup revocation timer
 		m.updatePending(le, le.ExpireTime.Sub(time.Now()))
 	}
@@ -1487,6 +1614,41 @@ func (m *ExpirationManager) loadEntryInternal(ctx context.Context, leaseID strin
 
 // persistEntry is used to persist a lease entry
 func (m *ExpirationManager) persistEntry(ctx context.Context, le *leaseEntry) error {
+	if err := m.writeEntry(ctx, le); err != nil {
+		return err
+	}
+
+	// The lease is indexed after it is persisted so that the scheduler never
+	// finds an index entry it can't load. Index entries left behind in other
+	// buckets by renewals are removed when the scheduler comes across them.
+	return m.indexByTime(ctx, le)
+}
+
+// persistNewEntry is used to persist the entry of a lease being registered,
+// which is counted against the lease count quotas first, failing if any of
+// them is full, and counted in the leases of its mount once written
+func (m *ExpirationManager) persistNewEntry(ctx context.Context, le *leaseEntry) error {
+	attrs := m.leaseQuotaAttrs(ctx, le)
+	if err := m.quotaStore.reserveLease(attrs); err != nil {
+		return err
+	}
+
+	// The restore must not count the lease again if it comes across it
+	if m.inRestoreMode() {
+		m.restoreLoaded.Store(le.LeaseID, struct{}{})
+	}
+
+	if err := m.writeEntry(ctx, le); err != nil {
+		m.quotaStore.releaseLease(attrs)
+		return err
+	}
+	m.countMountLease(attrs.mountPath, 1)
+
+	return m.indexByTime(ctx, le)
+}
+
+// writeEntry writes out a lease entry
+func (m *ExpirationManager) writeEntry(ctx context.Context, le *leaseEntry) error {
 	// Encode the entry
 	buf, err := le.encode()
 	if err != nil {
@@ -1509,13 +1671,117 @@ func (m *ExpirationManager) persistEntry(ctx context.Context, le *leaseEntry) er
 	return nil
 }
 
-// deleteEntry is used to delete a lease entry
+// deleteEntry is used to delete a lease entry. The lease is uncounted if its
+// entry still existed, so that concurrent revocations only uncount it once.
 func (m *ExpirationManager) deleteEntry(ctx context.Context, le *leaseEntry) error {
+	lock := locksutil.LockForKey(m.deleteLocks, le.LeaseID)
+	lock.Lock()
+	defer lock.Unlock()
+
 	view := m.leaseView(le.namespace)
+	existing, err := view.Get(ctx, le.LeaseID)
+	if err != nil {
+		return errwrap.Wrapf("failed to read lease entry: {{err}}", err)
+	}
 	if err := view.Delete(ctx, le.LeaseID); err != nil {
 		return errwrap.Wrapf("failed to delete lease entry: {{err}}", err)
 	}
-	return nil
+	if existing != nil {
+		attrs := m.leaseQuotaAttrs(ctx, le)
+		m.quotaStore.releaseLease(attrs)
+		m.countMountLease(attrs.mountPath, -1)
+	}
+
+	return m.removeIndexByTime(ctx, le)
+}
+
+// leaseMountPath returns the path of the mount a lease was issued by,
+// relative to the root namespace. It only depends on the lease ID, so that
+// leases can be counted without being loaded.
+func (m *ExpirationManager) leaseMountPath(ctx context.Context, ns *namespace.Namespace, leaseID string) string {
+	return m.router.MatchingMount(namespace.ContextWithNamespace(ctx, ns), leaseID)
+}
+
+// leaseQuotaAttrs returns the attributes lease count quotas are matched
+// against for the given lease. Leases persisted before they recorded their
+// entity and role fall back to those of their auth information, if any.
+func (m *ExpirationManager) leaseQuotaAttrs(ctx context.Context, le *leaseEntry) leaseQuotaAttrs {
+	attrs := leaseQuotaAttrs{
+		mountPath: m.leaseMountPath(ctx, le.namespace, le.LeaseID),
+		role:      le.Role,
+		entityID:  le.EntityID,
+	}
+	if le.Auth != nil {
+		if attrs.entityID == "" {
+			attrs.entityID = le.Auth.EntityID
+		}
+		if attrs.role == "" {
+			attrs.role = quotaRoleName(le.Path, "", le.Auth.Metadata)
+		}
+	}
+	return attrs
+}
+
+// trackLease counts an existing lease in the leases of its mount and
+// against the lease count quotas it matches
+func (m *ExpirationManager) trackLease(attrs leaseQuotaAttrs) {
+	m.quotaStore.trackLease(attrs)
+	m.countMountLease(attrs.mountPath, 1)
+}
+
+// countMountLease adds delta to the number of leases of a mount
+func (m *ExpirationManager) countMountLease(mountPath string, delta int64) {
+	count, ok := m.mountLeases.Load(mountPath)
+	if !ok {
+		count, _ = m.mountLeases.LoadOrStore(mountPath, new(int64))
+	}
+	atomic.AddInt64(count.(*int64), delta)
+}
+
+// leaseCounts returns the number of leases of the mounts under the given
+// path prefix, both in total and by mount
+func (m *ExpirationManager) leaseCounts(prefix string) (int, map[string]int) {
+	total := 0
+	counts := make(map[string]int)
+	m.mountLeases.Range(func(k, v interface{}) bool {
+		mountPath := k.(string)
+		count := int(atomic.LoadInt64(v.(*int64)))
+		if count > 0 && strings.HasPrefix(mountPath, prefix) {
+			counts[mountPath] = count
+			total += count
+		}
+		return true
+	})
+	return total, counts
+}
+
+// countQuotaLeases returns the number of existing leases matching a lease
+// count quota. Quotas restricted to a role or an entity require loading the
+// leases under their path, the others are counted from the leases of their
+// mounts.
+func (m *ExpirationManager) countQuotaLeases(ctx context.Context, quota *LeaseCountQuota) (int, error) {
+	if m.inRestoreMode() {
+		return 0, logical.CodedError(http.StatusServiceUnavailable, "cannot count the leases of a quota while leases are being restored")
+	}
+
+	if quota.Role == "" && quota.EntityID == "" {
+		count, _ := m.leaseCounts(quota.Path)
+		return count, nil
+	}
+
+	count := 0
+	err := m.walkLeases(quota.Path, func(ns *namespace.Namespace, leaseID string) error {
+		nsCtx := namespace.ContextWithNamespace(ctx, ns)
+		le, err := m.loadEntryInternal(nsCtx, leaseID, false, false)
+		if err != nil {
+			return err
+		}
+		if le != nil && quota.matches(m.leaseQuotaAttrs(nsCtx, le)) {
+			count++
+		}
+		return nil
+	})
+	return count, err
 }
 
 // createIndexByToken creates a secondary index from the token to a lease entry
@@ -1621,6 +1887,218 @@ func (m *ExpirationManager) removeIndexByToken(ctx context.Context, le *leaseEnt
 	return nil
 }
 
+// timerHorizonAt returns the timer horizon as of the given time, which is
+// rounded up to the end of a bucket of the time index
+func timerHorizonAt(now time.Time) time.Time {
+	return now.Add(leaseTimerHorizon).Truncate(leaseTimeBucketWidth).Add(leaseTimeBucketWidth)
+}
+
+// timeIndexBucket returns the bucket of the time index holding the leases
+// expiring at the given time. Buckets are named after the time they start at
+// and padded so that they sort in time order.
+func timeIndexBucket(expireTime time.Time) string {
+	return fmt.Sprintf("%012d", expireTime.Truncate(leaseTimeBucketWidth).Unix())
+}
+
+// timeIndexKey returns the key of the time index entry of a lease
+func (m *ExpirationManager) timeIndexKey(ctx context.Context, le *leaseEntry) (string, error) {
+	saltCtx := namespace.ContextWithNamespace(ctx, le.namespace)
+	leaseSaltedID, err := m.tokenStore.SaltID(saltCtx, le.LeaseID)
+	if err != nil {
+		return "", err
+	}
+	return timeIndexBucket(le.ExpireTime) + "/" + leaseSaltedID, nil
+}
+
+// indexByTime adds a lease to the bucket of the time index matching its
+// expiration time. Leases that don't expire aren't indexed.
+func (m *ExpirationManager) indexByTime(ctx context.Context, le *leaseEntry) error {
+	if le.ExpireTime.IsZero() {
+		return nil
+	}
+
+	key, err := m.timeIndexKey(ctx, le)
+	if err != nil {
+		return err
+	}
+
+	ent := logical.StorageEntry{
+		Key:   key,
+		Value: []byte(le.LeaseID),
+	}
+	if err := m.timeIndexView(le.namespace).Put(ctx, &ent); err != nil {
+		return errwrap.Wrapf("failed to persist lease time index entry: {{err}}", err)
+	}
+	return nil
+}
+
+// removeIndexByTime removes a lease from the bucket of the time index
+// matching its expiration time
+func (m *ExpirationManager) removeIndexByTime(ctx context.Context, le *leaseEntry) error {
+	if le.ExpireTime.IsZero() {
+		return nil
+	}
+
+	key, err := m.timeIndexKey(ctx, le)
+	if err != nil {
+		return err
+	}
+
+	if err := m.timeIndexView(le.namespace).Delete(ctx, key); err != nil {
+		return errwrap.Wrapf("failed to delete lease time index entry: {{err}}", err)
+	}
+	return nil
+}
+
+// timeIndexBuilt returns whether the time index holds every lease, which is
+// not the case for leases persisted before it was introduced until a restore
+// has indexed them
+func (m *ExpirationManager) timeIndexBuilt(ctx context.Context) (bool, error) {
+	entry, err := m.view.Get(ctx, timeIndexBuiltPath)
+	if err != nil {
+		return false, errwrap.Wrapf("failed to read time index state: {{err}}", err)
+	}
+	return entry != nil, nil
+}
+
+// timeIndexBucketStart returns the time the bucket of the given time index
+// entry or bucket starts at
+func timeIndexBucketStart(key string) (time.Time, bool) {
+	bucket := strings.SplitN(key, "/", 2)[0]
+	start, err := strconv.ParseInt(bucket, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(start, 0), true
+}
+
+// walkTimeIndex hands the time index entries of the buckets starting within
+// [from, to) to fn, in time order within each namespace. A zero to walks the
+// buckets up to the end of the index.
+func (m *ExpirationManager) walkTimeIndex(ctx context.Context, from, to time.Time, fn func(*namespace.Namespace, string) error) error {
+	for _, ns := range m.leaseNamespaces() {
+		view := m.timeIndexView(ns)
+		buckets, err := view.List(ctx, "")
+		if err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to list lease time index buckets in namespace %q: {{err}}", ns.Path), err)
+		}
+		sort.Strings(buckets)
+
+		for _, bucket := range buckets {
+			bucketStart, ok := timeIndexBucketStart(bucket)
+			if !ok || bucketStart.Before(from) || (!to.IsZero() && !bucketStart.Before(to)) {
+				continue
+			}
+
+			keys, err := view.List(ctx, bucket)
+			if err != nil {
+				return errwrap.Wrapf(fmt.Sprintf("failed to list lease time index bucket %q: {{err}}", bucket), err)
+			}
+			for _, key := range keys {
+				if err := fn(ns, bucket+key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// armIndexedLease gives a timer to the lease referenced by the given time
+// index entry. Entries that no longer match the expiration time of their
+// lease, having been left behind by a renewal or revocation, are removed, and
+// the lease is indexed again in case indexing it at its current expiration
+// time failed.
+func (m *ExpirationManager) armIndexedLease(ctx context.Context, key string) error {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	view := m.timeIndexView(ns)
+	entry, err := view.Get(ctx, key)
+	if err != nil {
+		return errwrap.Wrapf("failed to read lease time index entry: {{err}}", err)
+	}
+	if entry == nil {
+		return nil
+	}
+
+	le, err := m.loadEntry(ctx, string(entry.Value))
+	if err != nil {
+		return err
+	}
+	if le == nil || le.ExpireTime.IsZero() || timeIndexBucket(le.ExpireTime) != path.Dir(key) {
+		if le != nil {
+			if err := m.indexByTime(ctx, le); err != nil {
+				return err
+			}
+		}
+		if err := view.Delete(ctx, key); err != nil {
+			return errwrap.Wrapf("failed to delete lease time index entry: {{err}}", err)
+		}
+		if le == nil {
+			return nil
+		}
+	}
+
+	m.updatePending(le, le.ExpireTime.Sub(time.Now()))
+	return nil
+}
+
+// scheduleTimers advances the timer horizon as time passes until the
+// expiration manager is stopped
+func (m *ExpirationManager) scheduleTimers() {
+	ticker := time.NewTicker(leaseTimeBucketWidth)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.quitCh:
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.advanceTimerHorizon(time.Now()); err != nil && err != errExpirationStopped {
+			m.logger.Error("failed to advance lease timer horizon", "error", err)
+		}
+	}
+}
+
+// advanceTimerHorizon moves the timer horizon up to where it stands at the
+// given time, arming the leases of the buckets of the time index it passes
+func (m *ExpirationManager) advanceTimerHorizon(now time.Time) error {
+	horizon := timerHorizonAt(now)
+
+	// The horizon moves before the buckets are read, so that leases indexed
+	// concurrently are either seen here or armed when they are persisted
+	m.pendingLock.Lock()
+	from := m.timerHorizon
+	if !horizon.After(from) {
+		m.pendingLock.Unlock()
+		return nil
+	}
+	m.timerHorizon = horizon
+	m.pendingLock.Unlock()
+
+	return m.walkTimeIndex(m.quitContext, from, horizon, func(ns *namespace.Namespace, key string) error {
+		m.coreStateLock.RLock()
+		defer m.coreStateLock.RUnlock()
+
+		select {
+		case <-m.quitCh:
+			return errExpirationStopped
+		default:
+		}
+
+		err := m.armIndexedLease(namespace.ContextWithNamespace(m.quitContext, ns), key)
+		if err != nil {
+			m.logger.Error("failed to arm lease timer", "key", key, "error", err)
+		}
+		return nil
+	})
+}
+
 // CreateOrFetchRevocationLeaseByToken is used to create or fetch the matching
 // leaseID for a particular token. The lease is set to expire immediately after
 // it's created.
@@ -1677,7 +2155,7 @@ func (m *ExpirationManager) CreateOrFetchRevocationLeaseByToken(ctx context.Cont
 		}
 
 		// Encode the entry
-		if err := m.persistEntry(ctx, le); err != nil {
+		if err := m.persistNewEntry(ctx, le); err != nil {
 			return "", err
 		}
 	}
@@ -1728,9 +2206,13 @@ func (m *ExpirationManager) lookupLeasesByToken(ctx context.Context, te *logical
 // emitMetrics is invoked periodically to emit statistics
 func (m *ExpirationManager) emitMetrics() {
 	m.pendingLock.RLock()
-	num := len(m.pending)
+	numPending := len(m.pending)
 	m.pendingLock.RUnlock()
+	metrics.SetGauge([]string{"expire", "num_pending"}, float32(numPending))
+
+	num, _ := m.leaseCounts("")
 	metrics.SetGauge([]string{"expire", "num_leases"}, float32(num))
+	m.quotaStore.emitMetrics()
 	// Check if lease count is greater than the threshold
 	if num > maxLeaseThreshold {
 		if atomic.LoadUint32(m.leaseCheckCounter) > 59 {
@@ -1756,6 +2238,11 @@ type leaseEntry struct {
 	ExpireTime      time.Time              `json:"expire_time"`
 	LastRenewalTime time.Time              `json:"last_renewal_time"`
 
+	// EntityID and Role identify the client the lease was issued to, for the
+	// lease count quotas
+	EntityID string `json:"entity_id,omitempty"`
+	Role     string `json:"role,omitempty"`
+
 	namespace *namespace.Namespace
 }
 
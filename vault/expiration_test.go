@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"reflect"
 	"sort"
 	"strings"
@@ -1953,3 +1954,264 @@ func badRenewFactory(ctx context.Context, conf *logical.BackendConfig) (logical.
 
 	return be, nil
 }
+
+func testCreateTokenLease(t *testing.T, c *Core, root, ttl string) string {
+	t.Helper()
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+	req.ClientToken = root
+	req.Data["ttl"] = ttl
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	te, err := c.tokenStore.Lookup(namespace.RootContext(nil), resp.Auth.ClientToken)
+	if err != nil || te == nil {
+		t.Fatalf("err: %v\nentry: %#v", err, te)
+	}
+	saltedID, err := c.tokenStore.SaltID(namespace.RootContext(nil), te.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path.Join(te.Path, saltedID)
+}
+
+func testTimeIndexLeases(t *testing.T, exp *ExpirationManager) []string {
+	t.Helper()
+	var leaseIDs []string
+	err := exp.walkTimeIndex(namespace.RootContext(nil), time.Time{}, time.Now().Add(365*24*time.Hour), func(ns *namespace.Namespace, key string) error {
+		entry, err := exp.timeIndexView(ns).Get(namespace.RootContext(nil), key)
+		if err != nil {
+			return err
+		}
+		leaseIDs = append(leaseIDs, string(entry.Value))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(leaseIDs)
+	return leaseIDs
+}
+
+func testHasTimer(exp *ExpirationManager, leaseID string) bool {
+	exp.pendingLock.RLock()
+	defer exp.pendingLock.RUnlock()
+	_, ok := exp.pending[leaseID]
+	return ok
+}
+
+func TestExpiration_TimerHorizon(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	exp := c.expiration
+
+	near := testCreateTokenLease(t, c, root, "10m")
+	far := testCreateTokenLease(t, c, root, "48h")
+
+	// Both leases are indexed, but only the one expiring within the horizon
+	// holds a timer
+	expected := []string{near, far}
+	sort.Strings(expected)
+	if leaseIDs := testTimeIndexLeases(t, exp); !reflect.DeepEqual(leaseIDs, expected) {
+		t.Fatalf("bad: %v", leaseIDs)
+	}
+	if !testHasTimer(exp, near) {
+		t.Fatal("expected a timer for the lease within the horizon")
+	}
+	if testHasTimer(exp, far) {
+		t.Fatal("expected no timer for the lease beyond the horizon")
+	}
+
+	// Lease times are still available without a timer
+	le, err := exp.FetchLeaseTimes(namespace.RootContext(nil), far)
+	if err != nil || le == nil {
+		t.Fatalf("err: %v\nentry: %#v", err, le)
+	}
+	if le.ExpireTime.Before(time.Now().Add(47 * time.Hour)) {
+		t.Fatalf("bad: %v", le.ExpireTime)
+	}
+
+	// The scheduler arms the lease once the horizon reaches it
+	if err := exp.advanceTimerHorizon(time.Now().Add(48 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if !testHasTimer(exp, far) {
+		t.Fatal("expected a timer once the horizon reached the lease")
+	}
+
+	// Revocation removes the lease from the index
+	if err := exp.Revoke(namespace.RootContext(nil), far); err != nil {
+		t.Fatal(err)
+	}
+	if leaseIDs := testTimeIndexLeases(t, exp); !reflect.DeepEqual(leaseIDs, []string{near}) {
+		t.Fatalf("bad: %v", leaseIDs)
+	}
+	if testHasTimer(exp, far) {
+		t.Fatal("expected the timer of the revoked lease to be removed")
+	}
+}
+
+func TestExpiration_TimerHorizon_StaleTimer(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	exp := c.expiration
+
+	leaseID := testCreateTokenLease(t, c, root, "48h")
+	le, err := exp.loadEntry(namespace.RootContext(nil), leaseID)
+	if err != nil || le == nil {
+		t.Fatalf("err: %v\nentry: %#v", err, le)
+	}
+
+	// A timer armed with an expiration time the lease has since moved past
+	// doesn't revoke the lease
+	stale := *le
+	stale.ExpireTime = time.Now()
+	exp.updatePending(&stale, 0)
+
+	for start := time.Now(); testHasTimer(exp, leaseID); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("stale timer was not dropped")
+		}
+	}
+	le, err = exp.loadEntry(namespace.RootContext(nil), leaseID)
+	if err != nil || le == nil {
+		t.Fatalf("lease was revoked by a stale timer: %v", err)
+	}
+}
+
+func TestExpiration_TimerHorizon_StaleIndexEntry(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	exp := c.expiration
+
+	leaseID := testCreateTokenLease(t, c, root, "48h")
+	le, err := exp.loadEntry(namespace.RootContext(nil), leaseID)
+	if err != nil || le == nil {
+		t.Fatalf("err: %v\nentry: %#v", err, le)
+	}
+
+	// Leave the lease with nothing but an entry in a bucket it no longer
+	// expires in, as when indexing a renewed lease fails
+	if err := exp.removeIndexByTime(namespace.RootContext(nil), le); err != nil {
+		t.Fatal(err)
+	}
+	stale := *le
+	stale.ExpireTime = time.Now()
+	if err := exp.indexByTime(namespace.RootContext(nil), &stale); err != nil {
+		t.Fatal(err)
+	}
+	staleKey, err := exp.timeIndexKey(namespace.RootContext(nil), &stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The lease is indexed again at its expiration time when the stale entry
+	// is reached, rather than dropped from the index
+	if err := exp.armIndexedLease(namespace.RootContext(nil), staleKey); err != nil {
+		t.Fatal(err)
+	}
+	if testHasTimer(exp, leaseID) {
+		t.Fatal("expected no timer for the lease beyond the horizon")
+	}
+	key, err := exp.timeIndexKey(namespace.RootContext(nil), le)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := exp.timeView.Get(namespace.RootContext(nil), key); err != nil || entry == nil {
+		t.Fatalf("expected the lease to be indexed again, err: %v", err)
+	}
+	if entry, err := exp.timeView.Get(namespace.RootContext(nil), staleKey); err != nil || entry != nil {
+		t.Fatalf("expected the stale entry to be removed, err: %v", err)
+	}
+}
+
+func TestExpiration_Restore_TimeIndex(t *testing.T) {
+	c, keys, root := TestCoreUnsealed(t)
+
+	near := testCreateTokenLease(t, c, root, "10m")
+	far := testCreateTokenLease(t, c, root, "48h")
+
+	restore := func() {
+		t.Helper()
+		if err := c.Seal(root); err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range keys {
+			if _, err := TestCoreUnseal(c, TestKeyCopy(key)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for start := time.Now(); c.expiration.inRestoreMode(); time.Sleep(10 * time.Millisecond) {
+			if time.Since(start) > 10*time.Second {
+				t.Fatal("leases were not restored")
+			}
+		}
+	}
+
+	check := func() {
+		t.Helper()
+		exp := c.expiration
+		if !testHasTimer(exp, near) {
+			t.Fatal("expected a timer for the lease within the horizon")
+		}
+		if testHasTimer(exp, far) {
+			t.Fatal("expected no timer for the lease beyond the horizon")
+		}
+		built, err := exp.timeIndexBuilt(namespace.RootContext(nil))
+		if err != nil || !built {
+			t.Fatalf("expected the time index to be built, err: %v", err)
+		}
+		expected := []string{near, far}
+		sort.Strings(expected)
+		if leaseIDs := testTimeIndexLeases(t, exp); !reflect.DeepEqual(leaseIDs, expected) {
+			t.Fatalf("bad: %v", leaseIDs)
+		}
+		if total, _ := c.expiration.leaseCounts(""); total != 2 {
+			t.Fatalf("bad: %d", total)
+		}
+	}
+
+	restore()
+	check()
+
+	// Leases missing from the time index once it is built, e.g. persisted by
+	// a node that doesn't maintain it, are indexed on restore
+	if err := logical.ClearView(namespace.RootContext(nil), c.expiration.timeView); err != nil {
+		t.Fatal(err)
+	}
+	restore()
+	check()
+
+	// Leases persisted before the time index existed are indexed on restore
+	if err := logical.ClearView(namespace.RootContext(nil), c.expiration.timeView); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.expiration.view.Delete(namespace.RootContext(nil), timeIndexBuiltPath); err != nil {
+		t.Fatal(err)
+	}
+	restore()
+	check()
+
+	// A lease left with nothing but an entry in a bucket beyond the horizon it
+	// no longer expires in is indexed again at its expiration time
+	le, err := c.expiration.loadEntry(namespace.RootContext(nil), far)
+	if err != nil || le == nil {
+		t.Fatalf("err: %v\nentry: %#v", err, le)
+	}
+	if err := c.expiration.removeIndexByTime(namespace.RootContext(nil), le); err != nil {
+		t.Fatal(err)
+	}
+	stale := *le
+	stale.ExpireTime = le.ExpireTime.Add(48 * time.Hour)
+	if err := c.expiration.indexByTime(namespace.RootContext(nil), &stale); err != nil {
+		t.Fatal(err)
+	}
+	restore()
+	key, err := c.expiration.timeIndexKey(namespace.RootContext(nil), le)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := c.expiration.timeView.Get(namespace.RootContext(nil), key); err != nil || entry == nil {
+		t.Fatalf("expected the lease to be indexed again, err: %v", err)
+	}
+	if testHasTimer(c.expiration, far) {
+		t.Fatal("expected no timer for the lease beyond the horizon")
+	}
+}
@@ -3,27 +3,73 @@
 package vault
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/vault/helper/namespace"
-	"github.com/hashicorp/vault/sdk/logical"
 )
 
-func (m *ExpirationManager) leaseView(*namespace.Namespace) *BarrierView {
-	return m.idView
+func (m *ExpirationManager) leaseView(ns *namespace.Namespace) *BarrierView {
+	if ns.ID == namespace.RootNamespaceID {
+		return m.idView
+	}
+	return m.core.namespaceView(ns, systemBarrierPrefix+expirationSubPath+leaseViewPrefix)
+}
+
+func (m *ExpirationManager) tokenIndexView(ns *namespace.Namespace) *BarrierView {
+	if ns.ID == namespace.RootNamespaceID {
+		return m.tokenView
+	}
+	return m.core.namespaceView(ns, systemBarrierPrefix+expirationSubPath+tokenViewPrefix)
+}
+
+func (m *ExpirationManager) timeIndexView(ns *namespace.Namespace) *BarrierView {
+	if ns.ID == namespace.RootNamespaceID {
+		return m.timeView
+	}
+	return m.core.namespaceView(ns, systemBarrierPrefix+expirationSubPath+timeViewPrefix)
 }
 
-func (m *ExpirationManager) tokenIndexView(*namespace.Namespace) *BarrierView {
-	return m.tokenView
+// leaseNamespaces returns the namespaces that may hold leases
+func (m *ExpirationManager) leaseNamespaces() []*namespace.Namespace {
+	return append([]*namespace.Namespace{namespace.RootNamespace}, m.core.ListNamespaces(namespace.RootNamespace, true)...)
 }
 
-func (m *ExpirationManager) collectLeases() (map[*namespace.Namespace][]string, int, error) {
-	leaseCount := 0
-	existing := make(map[*namespace.Namespace][]string)
-	keys, err := logical.CollectKeys(m.quitContext, m.leaseView(namespace.RootNamespace))
-	if err != nil {
-		return nil, 0, errwrap.Wrapf("failed to scan for leases: {{err}}", err)
+// walkLeases hands the ID of every lease under the given path prefix,
+// relative to the root namespace, to fn, listing a single storage directory
+// at a time
+func (m *ExpirationManager) walkLeases(prefix string, fn func(*namespace.Namespace, string) error) error {
+	for _, ns := range m.leaseNamespaces() {
+		var start string
+		switch {
+		case strings.HasPrefix(ns.Path, prefix):
+		case strings.HasPrefix(prefix, ns.Path):
+			start = strings.TrimPrefix(prefix, ns.Path)
+		default:
+			continue
+		}
+
+		view := m.leaseView(ns)
+		frontier := []string{start}
+		for len(frontier) > 0 {
+			current := frontier[len(frontier)-1]
+			frontier = frontier[:len(frontier)-1]
+
+			contents, err := view.List(m.quitContext, current)
+			if err != nil {
+				return errwrap.Wrapf(fmt.Sprintf("failed to scan for leases in namespace %q: {{err}}", ns.Path), err)
+			}
+			for _, c := range contents {
+				if strings.HasSuffix(c, "/") {
+					frontier = append(frontier, current+c)
+					continue
+				}
+				if err := fn(ns, current+c); err != nil {
+					return err
+				}
+			}
+		}
 	}
-	existing[namespace.RootNamespace] = keys
-	leaseCount += len(keys)
-	return existing, leaseCount, nil
+	return nil
 }
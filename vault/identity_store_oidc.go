@@ -497,6 +497,25 @@ func (i *IdentityStore) pathOIDCDeleteKey(ctx context.Context, req *logical.Requ
 		return logical.ErrorResponse(errorMessage), logical.ErrInvalidRequest
 	}
 
+	// it is also an error to delete a key that is referenced by a provider client
+	clients, err := i.listOIDCClients(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	clientsReferencingTargetKeyName := make([]string, 0)
+	for _, c := range clients {
+		if c.Key == targetKeyName {
+			clientsReferencingTargetKeyName = append(clientsReferencingTargetKeyName, c.Name)
+		}
+	}
+
+	if len(clientsReferencingTargetKeyName) > 0 {
+		errorMessage := fmt.Sprintf("unable to delete key %q because it is currently referenced by these clients: %s",
+			targetKeyName, strings.Join(clientsReferencingTargetKeyName, ", "))
+		return logical.ErrorResponse(errorMessage), logical.ErrInvalidRequest
+	}
+
 	// key can safely be deleted now
 	err = req.Storage.Delete(ctx, namedKeyConfigPath+targetKeyName)
 	if err != nil {
@@ -580,21 +599,12 @@ func (i *IdentityStore) pathOIDCGenerateToken(ctx context.Context, req *logical.
 
 	}
 
-	var key *namedKey
-
-	if keyRaw, found := i.oidcCache.Get("namedKeys/" + role.Key); found {
-		key = keyRaw.(*namedKey)
-	} else {
-		entry, _ := req.Storage.Get(ctx, namedKeyConfigPath+role.Key)
-		if entry == nil {
-			return logical.ErrorResponse("key %q not found", role.Key), nil
-		}
-
-		if err := entry.DecodeJSON(&key); err != nil {
-			return nil, err
-		}
-
-		i.oidcCache.SetDefault("namedKeys/"+role.Key, key)
+	key, err := i.getOIDCNamedKey(ctx, req.Storage, role.Key)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return logical.ErrorResponse("key %q not found", role.Key), nil
 	}
 
 	// generate an OIDC token from entity data
@@ -697,9 +707,44 @@ func (tok *idToken) generatePayload(logger hclog.Logger, template string, entity
 	return payload, nil
 }
 
+// getOIDCNamedKey returns the named key from the cache or storage, or nil if
+// it doesn't exist
+func (i *IdentityStore) getOIDCNamedKey(ctx context.Context, s logical.Storage, name string) (*namedKey, error) {
+	if keyRaw, found := i.oidcCache.Get("namedKeys/" + name); found {
+		return keyRaw.(*namedKey), nil
+	}
+
+	entry, err := s.Get(ctx, namedKeyConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var key namedKey
+	if err := entry.DecodeJSON(&key); err != nil {
+		return nil, err
+	}
+
+	i.oidcCache.SetDefault("namedKeys/"+name, &key)
+
+	return &key, nil
+}
+
 func (k *namedKey) signPayload(payload []byte) (string, error) {
+	return k.signPayloadWithType(payload, "")
+}
+
+// signPayloadWithType signs the payload, setting the "typ" header if tokenType
+// isn't empty
+func (k *namedKey) signPayloadWithType(payload []byte, tokenType string) (string, error) {
 	signingKey := jose.SigningKey{Key: k.SigningKey, Algorithm: jose.SignatureAlgorithm(k.Algorithm)}
-	signer, err := jose.NewSigner(signingKey, &jose.SignerOptions{})
+	opts := &jose.SignerOptions{}
+	if tokenType != "" {
+		opts = opts.WithType(jose.ContentType(tokenType))
+	}
+	signer, err := jose.NewSigner(signingKey, opts)
 	if err != nil {
 		return "", err
 	}
@@ -1159,6 +1204,9 @@ func loadOIDCPublicKey(ctx context.Context, s logical.Storage, keyID string) (*j
 	if err != nil {
 		return nil, err
 	}
+	if entry == nil {
+		return nil, nil
+	}
 
 	var key jose.JSONWebKey
 	if err := entry.DecodeJSON(&key); err != nil {
@@ -1368,6 +1416,10 @@ func (i *IdentityStore) oidcPeriodicFunc(ctx context.Context, s logical.Storage)
 			i.Logger().Warn("error expiring OIDC public keys", "err", err)
 		}
 
+		if err := i.expireOIDCAuthCodes(ctx, s); err != nil {
+			i.Logger().Warn("error expiring OIDC authorization codes", "err", err)
+		}
+
 		i.oidcCache.Flush()
 
 		// re-run at the soonest expiration or rotation time
@@ -0,0 +1,1784 @@
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+type assignment struct {
+	Name      string   `json:"name"`
+	EntityIDs []string `json:"entity_ids"`
+	GroupIDs  []string `json:"group_ids"`
+}
+
+type scope struct {
+	Name        string `json:"name"`
+	Template    string `json:"template"`
+	Description string `json:"description"`
+}
+
+type client struct {
+	Name           string        `json:"name"`
+	Key            string        `json:"key"`
+	RedirectURIs   []string      `json:"redirect_uris"`
+	Assignments    []string      `json:"assignments"`
+	ClientType     string        `json:"client_type"`
+	ClientID       string        `json:"client_id"`
+	ClientSecret   string        `json:"client_secret"`
+	IDTokenTTL     time.Duration `json:"id_token_ttl"`
+	AccessTokenTTL time.Duration `json:"access_token_ttl"`
+}
+
+type provider struct {
+	Name             string   `json:"name"`
+	Issuer           string   `json:"issuer"`
+	AllowedClientIDs []string `json:"allowed_client_ids"`
+	ScopesSupported  []string `json:"scopes_supported"`
+
+	// effectiveIssuer is a calculated field and will be either Issuer (if
+	// that's set) or the Vault instance's api_addr, followed by the path of
+	// the provider.
+	effectiveIssuer string
+}
+
+// authCode is the state of an authorization request that is kept until the
+// client exchanges the code at the token endpoint.
+type authCode struct {
+	Provider            string    `json:"provider"`
+	ClientID            string    `json:"client_id"`
+	EntityID            string    `json:"entity_id"`
+	RedirectURI         string    `json:"redirect_uri"`
+	Scopes              []string  `json:"scopes"`
+	Nonce               string    `json:"nonce"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	AuthTime            time.Time `json:"auth_time"`
+	ExpireAt            time.Time `json:"expire_at"`
+}
+
+type providerDiscovery struct {
+	Issuer               string   `json:"issuer"`
+	Keys                 string   `json:"jwks_uri"`
+	Authorization        string   `json:"authorization_endpoint"`
+	Token                string   `json:"token_endpoint"`
+	UserInfo             string   `json:"userinfo_endpoint"`
+	ResponseTypes        []string `json:"response_types_supported"`
+	GrantTypes           []string `json:"grant_types_supported"`
+	Subjects             []string `json:"subject_types_supported"`
+	IDTokenAlgs          []string `json:"id_token_signing_alg_values_supported"`
+	Scopes               []string `json:"scopes_supported"`
+	AuthMethods          []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethods []string `json:"code_challenge_methods_supported"`
+	RequestURIParameter  bool     `json:"request_uri_parameter_supported"`
+}
+
+const (
+	oidcProviderPrefix     = "oidc_provider/"
+	assignmentConfigPath   = oidcProviderPrefix + "assignments/"
+	scopeConfigPath        = oidcProviderPrefix + "scopes/"
+	clientConfigPath       = oidcProviderPrefix + "clients/"
+	providerConfigPath     = oidcProviderPrefix + "providers/"
+	authCodePath           = oidcProviderPrefix + "auth_codes/"
+	providerPathPrefix     = "/v1/identity/oidc/provider/"
+	clientTypeConfidential = "confidential"
+	clientTypePublic       = "public"
+	codeChallengePlain     = "plain"
+	codeChallengeS256      = "S256"
+	accessTokenType        = "at+jwt"
+	openIDScope            = "openid"
+	authCodeTTL            = 5 * time.Minute
+	clientSecretPrefix     = "hvo_secret_"
+)
+
+// reservedProviderClaims may not be set by scope templates
+var reservedProviderClaims = append([]string{"nonce", "auth_time", "scope"}, requiredClaims...)
+
+func oidcProviderPaths(i *IdentityStore) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "oidc/assignment/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the assignment",
+				},
+				"entity_ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Comma separated string or array of identity entity IDs",
+				},
+				"group_ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Comma separated string or array of identity group IDs",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: i.pathOIDCCreateUpdateAssignment,
+				logical.UpdateOperation: i.pathOIDCCreateUpdateAssignment,
+				logical.ReadOperation:   i.pathOIDCReadAssignment,
+				logical.DeleteOperation: i.pathOIDCDeleteAssignment,
+			},
+			ExistenceCheck:  i.pathOIDCAssignmentExistenceCheck,
+			HelpSynopsis:    "CRUD operations for OIDC assignments.",
+			HelpDescription: "Create, Read, Update, and Delete OIDC assignments. Assignments determine which entities and groups may authenticate with a client.",
+		},
+		{
+			Pattern: "oidc/assignment/?$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: i.pathOIDCListAssignment,
+			},
+			HelpSynopsis:    "List OIDC assignments",
+			HelpDescription: "List all configured OIDC assignments in the identity backend.",
+		},
+		{
+			Pattern: "oidc/scope/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the scope",
+				},
+				"template": {
+					Type:        framework.TypeString,
+					Description: "The template string to use for the scope. This may be in string-ified JSON or base64 format.",
+				},
+				"description": {
+					Type:        framework.TypeString,
+					Description: "The description of the scope",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: i.pathOIDCCreateUpdateScope,
+				logical.UpdateOperation: i.pathOIDCCreateUpdateScope,
+				logical.ReadOperation:   i.pathOIDCReadScope,
+				logical.DeleteOperation: i.pathOIDCDeleteScope,
+			},
+			ExistenceCheck:  i.pathOIDCScopeExistenceCheck,
+			HelpSynopsis:    "CRUD operations for OIDC scopes.",
+			HelpDescription: "Create, Read, Update, and Delete OIDC scopes. The template of a scope determines the claims added to ID tokens and userinfo responses when the scope is requested.",
+		},
+		{
+			Pattern: "oidc/scope/?$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: i.pathOIDCListScope,
+			},
+			HelpSynopsis:    "List OIDC scopes",
+			HelpDescription: "List all configured OIDC scopes in the identity backend.",
+		},
+		{
+			Pattern: "oidc/client/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the client",
+				},
+				"key": {
+					Type:        framework.TypeString,
+					Description: "The OIDC key to use for signing tokens issued to the client. The specified key must already exist.",
+				},
+				"redirect_uris": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Comma separated string or array of redirect URIs the client may use in authorization requests",
+				},
+				"assignments": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Comma separated string or array of assignment names whose entities and groups may authenticate with the client",
+				},
+				"client_type": {
+					Type:        framework.TypeString,
+					Description: `The client type, either "confidential" or "public". Public clients don't have a client secret and must use PKCE. This can't be changed after creation.`,
+					Default:     clientTypeConfidential,
+				},
+				"id_token_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "TTL of the ID tokens issued to the client.",
+					Default:     "24h",
+				},
+				"access_token_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "TTL of the access tokens issued to the client.",
+					Default:     "24h",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: i.pathOIDCCreateUpdateClient,
+				logical.UpdateOperation: i.pathOIDCCreateUpdateClient,
+				logical.ReadOperation:   i.pathOIDCReadClient,
+				logical.DeleteOperation: i.pathOIDCDeleteClient,
+			},
+			ExistenceCheck:  i.pathOIDCClientExistenceCheck,
+			HelpSynopsis:    "CRUD operations for OIDC clients.",
+			HelpDescription: "Create, Read, Update, and Delete OIDC clients. Clients are the relying parties that authenticate Vault identities through an OIDC provider.",
+		},
+		{
+			Pattern: "oidc/client/?$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: i.pathOIDCListClient,
+			},
+			HelpSynopsis:    "List OIDC clients",
+			HelpDescription: "List all configured OIDC clients in the identity backend.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"issuer": {
+					Type:        framework.TypeString,
+					Description: "Scheme, host and optional port of the issuer of the provider's tokens. If not set, Vault's api_addr will be used.",
+				},
+				"allowed_client_ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: `Comma separated string or array of client IDs allowed to use the provider. If "*", all clients are allowed.`,
+				},
+				"scopes_supported": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: `Comma separated string or array of scopes, besides "openid", available to clients of the provider`,
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: i.pathOIDCCreateUpdateProvider,
+				logical.UpdateOperation: i.pathOIDCCreateUpdateProvider,
+				logical.ReadOperation:   i.pathOIDCReadProvider,
+				logical.DeleteOperation: i.pathOIDCDeleteProvider,
+			},
+			ExistenceCheck:  i.pathOIDCProviderExistenceCheck,
+			HelpSynopsis:    "CRUD operations for OIDC providers.",
+			HelpDescription: "Create, Read, Update, and Delete OIDC providers. A provider lets the allowed clients authenticate Vault identities using the authorization code flow.",
+		},
+		{
+			Pattern: "oidc/provider/?$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: i.pathOIDCListProvider,
+			},
+			HelpSynopsis:    "List OIDC providers",
+			HelpDescription: "List all configured OIDC providers in the identity backend.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/.well-known/openid-configuration/?$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: i.pathOIDCProviderDiscovery,
+			},
+			HelpSynopsis:    "Query OIDC provider configuration",
+			HelpDescription: "Query this path to retrieve the discovery document of an OIDC provider.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/.well-known/keys/?$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: i.pathOIDCProviderReadPublicKeys,
+			},
+			HelpSynopsis:    "Retrieve public keys of an OIDC provider",
+			HelpDescription: "Query this path to retrieve the public portion of the keys used to sign the tokens issued to the clients of an OIDC provider.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/authorize/?$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"client_id": {
+					Type:        framework.TypeString,
+					Description: "The ID of the requesting client.",
+				},
+				"scope": {
+					Type:        framework.TypeString,
+					Description: `A space-delimited, case-sensitive list of scopes to be requested. The "openid" scope is required.`,
+				},
+				"redirect_uri": {
+					Type:        framework.TypeString,
+					Description: "The redirection URI to which the response will be sent.",
+				},
+				"response_type": {
+					Type:        framework.TypeString,
+					Description: `The OIDC authentication flow to be used. The only supported value is "code".`,
+				},
+				"state": {
+					Type:        framework.TypeString,
+					Description: "A value used to maintain state between the authentication request and client callback.",
+				},
+				"nonce": {
+					Type:        framework.TypeString,
+					Description: "A value that is returned in the ID token nonce claim. It is used to mitigate replay attacks.",
+				},
+				"code_challenge": {
+					Type:        framework.TypeString,
+					Description: "The PKCE code challenge derived from the client's code verifier.",
+				},
+				"code_challenge_method": {
+					Type:        framework.TypeString,
+					Description: `The method used to derive the PKCE code challenge, either "plain" or "S256".`,
+					Default:     codeChallengePlain,
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   i.pathOIDCAuthorize,
+				logical.UpdateOperation: i.pathOIDCAuthorize,
+			},
+			HelpSynopsis:    "Provides the OIDC authorization endpoint.",
+			HelpDescription: "The authorization endpoint of an OIDC provider. It issues an authorization code for the entity of the Vault token used to call it.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/token/?$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"grant_type": {
+					Type:        framework.TypeString,
+					Description: `The authorization grant type. The only supported value is "authorization_code".`,
+				},
+				"code": {
+					Type:        framework.TypeString,
+					Description: "The authorization code received from the provider's authorization endpoint.",
+				},
+				"redirect_uri": {
+					Type:        framework.TypeString,
+					Description: "The redirection URI used in the authorization request.",
+				},
+				"code_verifier": {
+					Type:        framework.TypeString,
+					Description: "The PKCE code verifier, if a code challenge was sent in the authorization request.",
+				},
+				"client_id": {
+					Type:        framework.TypeString,
+					Description: "The ID of the requesting client, if it isn't authenticating with HTTP basic authentication.",
+				},
+				"client_secret": {
+					Type:        framework.TypeString,
+					Description: "The secret of the requesting client, if it isn't authenticating with HTTP basic authentication.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: i.pathOIDCToken,
+			},
+			HelpSynopsis:    "Provides the OIDC token endpoint.",
+			HelpDescription: "The token endpoint of an OIDC provider. Clients exchange authorization codes for ID and access tokens here.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/userinfo/?$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"access_token": {
+					Type:        framework.TypeString,
+					Description: "The access token, if it isn't sent as a bearer token in the Authorization header.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   i.pathOIDCUserInfo,
+				logical.UpdateOperation: i.pathOIDCUserInfo,
+			},
+			HelpSynopsis:    "Provides the OIDC userinfo endpoint.",
+			HelpDescription: "The userinfo endpoint of an OIDC provider. It returns the claims of the requested scopes for the entity an access token was issued to.",
+		},
+	}
+}
+
+func (i *IdentityStore) pathOIDCAssignmentExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	a, err := i.getOIDCAssignment(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return a != nil, nil
+}
+
+func (i *IdentityStore) pathOIDCCreateUpdateAssignment(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	a, err := i.getOIDCAssignment(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		a = &assignment{
+			Name: name,
+		}
+	}
+
+	if entityIDs, ok := d.GetOk("entity_ids"); ok {
+		a.EntityIDs = entityIDs.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		a.EntityIDs = d.Get("entity_ids").([]string)
+	}
+
+	if groupIDs, ok := d.GetOk("group_ids"); ok {
+		a.GroupIDs = groupIDs.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		a.GroupIDs = d.Get("group_ids").([]string)
+	}
+
+	for _, entityID := range a.EntityIDs {
+		entity, err := i.MemDBEntityByID(entityID, false)
+		if err != nil {
+			return nil, err
+		}
+		if entity == nil {
+			return logical.ErrorResponse("entity %q does not exist", entityID), nil
+		}
+	}
+
+	for _, groupID := range a.GroupIDs {
+		group, err := i.MemDBGroupByID(groupID, false)
+		if err != nil {
+			return nil, err
+		}
+		if group == nil {
+			return logical.ErrorResponse("group %q does not exist", groupID), nil
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(assignmentConfigPath+name, a)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCReadAssignment(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	a, err := i.getOIDCAssignment(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"entity_ids": a.EntityIDs,
+			"group_ids":  a.GroupIDs,
+		},
+	}, nil
+}
+
+// pathOIDCDeleteAssignment deletes an assignment that isn't referenced by
+// any client
+func (i *IdentityStore) pathOIDCDeleteAssignment(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	clients, err := i.listOIDCClients(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var referencingClients []string
+	for _, c := range clients {
+		if strutil.StrListContains(c.Assignments, name) {
+			referencingClients = append(referencingClients, c.Name)
+		}
+	}
+	if len(referencingClients) > 0 {
+		return logical.ErrorResponse("unable to delete assignment %q because it is currently referenced by these clients: %s",
+			name, strings.Join(referencingClients, ", ")), logical.ErrInvalidRequest
+	}
+
+	if err := req.Storage.Delete(ctx, assignmentConfigPath+name); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCListAssignment(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	assignments, err := req.Storage.List(ctx, assignmentConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(assignments), nil
+}
+
+func (i *IdentityStore) getOIDCAssignment(ctx context.Context, s logical.Storage, name string) (*assignment, error) {
+	entry, err := s.Get(ctx, assignmentConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var a assignment
+	if err := entry.DecodeJSON(&a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (i *IdentityStore) pathOIDCScopeExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	s, err := i.getOIDCScope(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return s != nil, nil
+}
+
+func (i *IdentityStore) pathOIDCCreateUpdateScope(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == openIDScope {
+		return logical.ErrorResponse("the %q scope name is reserved", openIDScope), nil
+	}
+
+	s, err := i.getOIDCScope(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		s = &scope{
+			Name: name,
+		}
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		s.Description = description.(string)
+	} else if req.Operation == logical.CreateOperation {
+		s.Description = d.Get("description").(string)
+	}
+
+	if template, ok := d.GetOk("template"); ok {
+		s.Template = template.(string)
+	} else if req.Operation == logical.CreateOperation {
+		s.Template = d.Get("template").(string)
+	}
+
+	// Attempt to decode as base64 and use that if it works
+	if decoded, err := base64.StdEncoding.DecodeString(s.Template); err == nil {
+		s.Template = string(decoded)
+	}
+
+	// Validate that template can be parsed and results in valid JSON
+	if s.Template != "" {
+		_, populatedTemplate, err := identity.PopulateString(identity.PopulateStringInput{
+			Mode:   identity.JSONTemplating,
+			String: s.Template,
+			Entity: new(identity.Entity),
+			Groups: make([]*identity.Group, 0),
+		})
+		if err != nil {
+			return logical.ErrorResponse("error parsing template: %s", err.Error()), nil
+		}
+
+		var tmp map[string]interface{}
+		if err := json.Unmarshal([]byte(populatedTemplate), &tmp); err != nil {
+			return logical.ErrorResponse("error parsing template JSON: %s", err.Error()), nil
+		}
+
+		for key := range tmp {
+			if strutil.StrListContains(reservedProviderClaims, key) {
+				return logical.ErrorResponse(`top level key %q not allowed. Restricted keys: %s`,
+					key, strings.Join(reservedProviderClaims, ", ")), nil
+			}
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(scopeConfigPath+name, s)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCReadScope(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	s, err := i.getOIDCScope(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"template":    s.Template,
+			"description": s.Description,
+		},
+	}, nil
+}
+
+// pathOIDCDeleteScope deletes a scope that isn't referenced by any provider
+func (i *IdentityStore) pathOIDCDeleteScope(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	providerNames, err := req.Storage.List(ctx, providerConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var referencingProviders []string
+	for _, providerName := range providerNames {
+		p, err := i.getOIDCProvider(ctx, req.Storage, providerName)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil && strutil.StrListContains(p.ScopesSupported, name) {
+			referencingProviders = append(referencingProviders, p.Name)
+		}
+	}
+	if len(referencingProviders) > 0 {
+		return logical.ErrorResponse("unable to delete scope %q because it is currently referenced by these providers: %s",
+			name, strings.Join(referencingProviders, ", ")), logical.ErrInvalidRequest
+	}
+
+	if err := req.Storage.Delete(ctx, scopeConfigPath+name); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCListScope(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	scopes, err := req.Storage.List(ctx, scopeConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(scopes), nil
+}
+
+func (i *IdentityStore) getOIDCScope(ctx context.Context, s logical.Storage, name string) (*scope, error) {
+	entry, err := s.Get(ctx, scopeConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var sc scope
+	if err := entry.DecodeJSON(&sc); err != nil {
+		return nil, err
+	}
+
+	return &sc, nil
+}
+
+func (i *IdentityStore) pathOIDCClientExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	c, err := i.getOIDCClient(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return c != nil, nil
+}
+
+func (i *IdentityStore) pathOIDCCreateUpdateClient(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	c, err := i.getOIDCClient(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		c = &client{
+			Name: name,
+		}
+	}
+
+	if key, ok := d.GetOk("key"); ok {
+		c.Key = key.(string)
+	} else if req.Operation == logical.CreateOperation {
+		c.Key = d.Get("key").(string)
+	}
+	if c.Key == "" {
+		return logical.ErrorResponse("key must be provided"), nil
+	}
+
+	// validate that key exists
+	entry, err := req.Storage.Get(ctx, namedKeyConfigPath+c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return logical.ErrorResponse("key %q does not exist", c.Key), nil
+	}
+
+	if redirectURIs, ok := d.GetOk("redirect_uris"); ok {
+		c.RedirectURIs = redirectURIs.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		c.RedirectURIs = d.Get("redirect_uris").([]string)
+	}
+	for _, redirectURI := range c.RedirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return logical.ErrorResponse("invalid redirect URI %q; redirect URIs must be absolute and may not contain a fragment", redirectURI), nil
+		}
+	}
+
+	if assignments, ok := d.GetOk("assignments"); ok {
+		c.Assignments = assignments.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		c.Assignments = d.Get("assignments").([]string)
+	}
+	for _, assignmentName := range c.Assignments {
+		a, err := i.getOIDCAssignment(ctx, req.Storage, assignmentName)
+		if err != nil {
+			return nil, err
+		}
+		if a == nil {
+			return logical.ErrorResponse("assignment %q does not exist", assignmentName), nil
+		}
+	}
+
+	if clientType, ok := d.GetOk("client_type"); ok {
+		if req.Operation == logical.UpdateOperation && clientType.(string) != c.ClientType {
+			return logical.ErrorResponse("client_type can't be changed after creation"), nil
+		}
+		c.ClientType = clientType.(string)
+	} else if req.Operation == logical.CreateOperation {
+		c.ClientType = d.Get("client_type").(string)
+	}
+	switch c.ClientType {
+	case clientTypeConfidential, clientTypePublic:
+	default:
+		return logical.ErrorResponse("invalid client_type %q; must be %q or %q", c.ClientType, clientTypeConfidential, clientTypePublic), nil
+	}
+
+	if ttl, ok := d.GetOk("id_token_ttl"); ok {
+		c.IDTokenTTL = time.Duration(ttl.(int)) * time.Second
+	} else if req.Operation == logical.CreateOperation {
+		c.IDTokenTTL = time.Duration(d.Get("id_token_ttl").(int)) * time.Second
+	}
+
+	if ttl, ok := d.GetOk("access_token_ttl"); ok {
+		c.AccessTokenTTL = time.Duration(ttl.(int)) * time.Second
+	} else if req.Operation == logical.CreateOperation {
+		c.AccessTokenTTL = time.Duration(d.Get("access_token_ttl").(int)) * time.Second
+	}
+
+	if c.ClientID == "" {
+		c.ClientID, err = base62.Random(32)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ClientType == clientTypeConfidential && c.ClientSecret == "" {
+		secret, err := base62.Random(64)
+		if err != nil {
+			return nil, err
+		}
+		c.ClientSecret = clientSecretPrefix + secret
+	}
+
+	entry, err = logical.StorageEntryJSON(clientConfigPath+name, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCReadClient(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	c, err := i.getOIDCClient(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, nil
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"key":              c.Key,
+			"redirect_uris":    c.RedirectURIs,
+			"assignments":      c.Assignments,
+			"client_type":      c.ClientType,
+			"client_id":        c.ClientID,
+			"id_token_ttl":     int64(c.IDTokenTTL.Seconds()),
+			"access_token_ttl": int64(c.AccessTokenTTL.Seconds()),
+		},
+	}
+	if c.ClientType == clientTypeConfidential {
+		resp.Data["client_secret"] = c.ClientSecret
+	}
+
+	return resp, nil
+}
+
+func (i *IdentityStore) pathOIDCDeleteClient(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, clientConfigPath+d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+
+	i.oidcCache.Flush()
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCListClient(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	clients, err := req.Storage.List(ctx, clientConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(clients), nil
+}
+
+func (i *IdentityStore) getOIDCClient(ctx context.Context, s logical.Storage, name string) (*client, error) {
+	entry, err := s.Get(ctx, clientConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var c client
+	if err := entry.DecodeJSON(&c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (i *IdentityStore) listOIDCClients(ctx context.Context, s logical.Storage) ([]*client, error) {
+	names, err := s.List(ctx, clientConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]*client, 0, len(names))
+	for _, name := range names {
+		c, err := i.getOIDCClient(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			clients = append(clients, c)
+		}
+	}
+
+	return clients, nil
+}
+
+// getOIDCClientByID returns the client with the given client ID, or nil if
+// there is none
+func (i *IdentityStore) getOIDCClientByID(ctx context.Context, s logical.Storage, clientID string) (*client, error) {
+	if clientID == "" {
+		return nil, nil
+	}
+
+	clients, err := i.listOIDCClients(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range clients {
+		if subtle.ConstantTimeCompare([]byte(c.ClientID), []byte(clientID)) == 1 {
+			return c, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// entityAssigned reports whether the entity, or one of its groups, is
+// assigned to the client
+func (i *IdentityStore) entityAssigned(ctx context.Context, s logical.Storage, c *client, entity *identity.Entity, groups []*identity.Group) (bool, error) {
+	for _, assignmentName := range c.Assignments {
+		a, err := i.getOIDCAssignment(ctx, s, assignmentName)
+		if err != nil {
+			return false, err
+		}
+		if a == nil {
+			continue
+		}
+
+		if strutil.StrListContains(a.EntityIDs, entity.ID) {
+			return true, nil
+		}
+		for _, group := range groups {
+			if strutil.StrListContains(a.GroupIDs, group.ID) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (i *IdentityStore) pathOIDCProviderExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	p, err := i.getOIDCProvider(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return p != nil, nil
+}
+
+func (i *IdentityStore) pathOIDCCreateUpdateProvider(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	defer i.oidcCache.Flush()
+
+	name := d.Get("name").(string)
+
+	p, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &provider{
+			Name: name,
+		}
+	}
+
+	if issuer, ok := d.GetOk("issuer"); ok {
+		p.Issuer = issuer.(string)
+	} else if req.Operation == logical.CreateOperation {
+		p.Issuer = d.Get("issuer").(string)
+	}
+	if p.Issuer != "" {
+		u, err := url.Parse(p.Issuer)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return logical.ErrorResponse("invalid issuer %q; must be a URL with a scheme and host", p.Issuer), nil
+		}
+		if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return logical.ErrorResponse("invalid issuer %q; may only contain a scheme, host and port", p.Issuer), nil
+		}
+		p.Issuer = u.Scheme + "://" + u.Host
+	}
+
+	if allowedClientIDs, ok := d.GetOk("allowed_client_ids"); ok {
+		p.AllowedClientIDs = allowedClientIDs.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		p.AllowedClientIDs = d.Get("allowed_client_ids").([]string)
+	}
+
+	if scopesSupported, ok := d.GetOk("scopes_supported"); ok {
+		p.ScopesSupported = scopesSupported.([]string)
+	} else if req.Operation == logical.CreateOperation {
+		p.ScopesSupported = d.Get("scopes_supported").([]string)
+	}
+	for _, scopeName := range p.ScopesSupported {
+		if scopeName == openIDScope {
+			return logical.ErrorResponse("the %q scope is always supported and may not be listed", openIDScope), nil
+		}
+		s, err := i.getOIDCScope(ctx, req.Storage, scopeName)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return logical.ErrorResponse("scope %q does not exist", scopeName), nil
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(providerConfigPath+name, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	var resp logical.Response
+	if i.core.redirectAddr == "" && p.Issuer == "" {
+		resp.AddWarning(`Both "issuer" and Vault's "api_addr" are empty. ` +
+			`The issuer of the provider will not be network reachable.`)
+		return &resp, nil
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCReadProvider(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	p, err := i.getOIDCProvider(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"issuer":             p.effectiveIssuer,
+			"allowed_client_ids": p.AllowedClientIDs,
+			"scopes_supported":   p.ScopesSupported,
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathOIDCDeleteProvider(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, providerConfigPath+d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+
+	i.oidcCache.Flush()
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathOIDCListProvider(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	providers, err := req.Storage.List(ctx, providerConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(providers), nil
+}
+
+func (i *IdentityStore) getOIDCProvider(ctx context.Context, s logical.Storage, name string) (*provider, error) {
+	entry, err := s.Get(ctx, providerConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var p provider
+	if err := entry.DecodeJSON(&p); err != nil {
+		return nil, err
+	}
+
+	p.effectiveIssuer = p.Issuer
+	if p.effectiveIssuer == "" {
+		p.effectiveIssuer = i.core.redirectAddr
+	}
+	p.effectiveIssuer += providerPathPrefix + p.Name
+
+	return &p, nil
+}
+
+// allowsClient reports whether the client may use the provider
+func (p *provider) allowsClient(clientID string) bool {
+	return strutil.StrListContains(p.AllowedClientIDs, "*") || strutil.StrListContains(p.AllowedClientIDs, clientID)
+}
+
+// allowedClients returns the existing clients allowed to use the provider
+func (i *IdentityStore) allowedClients(ctx context.Context, s logical.Storage, p *provider) ([]*client, error) {
+	clients, err := i.listOIDCClients(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]*client, 0, len(clients))
+	for _, c := range clients {
+		if p.allowsClient(c.ClientID) {
+			allowed = append(allowed, c)
+		}
+	}
+
+	return allowed, nil
+}
+
+// providerJWKS returns the public keys of the keys used by the clients of
+// the provider
+func (i *IdentityStore) providerJWKS(ctx context.Context, s logical.Storage, p *provider) (*jose.JSONWebKeySet, error) {
+	clients, err := i.allowedClients(ctx, s, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expire rotated keys first so only verifiable keys are published
+	if _, err := i.expireOIDCPublicKeys(ctx, s); err != nil {
+		return nil, err
+	}
+
+	jwks := &jose.JSONWebKeySet{
+		Keys: make([]jose.JSONWebKey, 0),
+	}
+
+	seenKeys := make(map[string]bool)
+	for _, c := range clients {
+		if seenKeys[c.Key] {
+			continue
+		}
+		seenKeys[c.Key] = true
+
+		key, err := i.getOIDCNamedKey(ctx, s, c.Key)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+
+		for _, k := range key.KeyRing {
+			publicKey, err := loadOIDCPublicKey(ctx, s, k.KeyID)
+			if err != nil {
+				return nil, err
+			}
+			if publicKey != nil {
+				jwks.Keys = append(jwks.Keys, *publicKey)
+			}
+		}
+	}
+
+	return jwks, nil
+}
+
+func (i *IdentityStore) pathOIDCProviderDiscovery(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	p, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	clients, err := i.allowedClients(ctx, req.Storage, p)
+	if err != nil {
+		return nil, err
+	}
+
+	algs := make([]string, 0)
+	for _, c := range clients {
+		key, err := i.getOIDCNamedKey(ctx, req.Storage, c.Key)
+		if err != nil {
+			return nil, err
+		}
+		if key != nil && !strutil.StrListContains(algs, key.Algorithm) {
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	sort.Strings(algs)
+
+	disc := providerDiscovery{
+		Issuer:               p.effectiveIssuer,
+		Keys:                 p.effectiveIssuer + "/.well-known/keys",
+		Authorization:        p.effectiveIssuer + "/authorize",
+		Token:                p.effectiveIssuer + "/token",
+		UserInfo:             p.effectiveIssuer + "/userinfo",
+		ResponseTypes:        []string{"code"},
+		GrantTypes:           []string{"authorization_code"},
+		Subjects:             []string{"public"},
+		IDTokenAlgs:          algs,
+		Scopes:               append([]string{openIDScope}, p.ScopesSupported...),
+		AuthMethods:          []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethods: []string{codeChallengePlain, codeChallengeS256},
+	}
+
+	data, err := json.Marshal(disc)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcRawResponse(http.StatusOK, data), nil
+}
+
+func (i *IdentityStore) pathOIDCProviderReadPublicKeys(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	p, err := i.getOIDCProvider(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	jwks, err := i.providerJWKS(ctx, req.Storage, p)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcRawResponse(http.StatusOK, data), nil
+}
+
+// pathOIDCAuthorize issues an authorization code for the entity of the
+// calling token. Errors after the client and redirect URI have been validated
+// carry the state, so that the caller can redirect the error to the client.
+func (i *IdentityStore) pathOIDCAuthorize(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	state := d.Get("state").(string)
+
+	p, err := i.getOIDCProvider(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "provider not found", "")
+	}
+
+	clientID := d.Get("client_id").(string)
+	c, err := i.getOIDCClientByID(ctx, req.Storage, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "client with client_id not found", "")
+	}
+	if !p.allowsClient(c.ClientID) {
+		return oidcErrorResponse(http.StatusBadRequest, "unauthorized_client", "client is not allowed to use the provider", "")
+	}
+
+	redirectURI := d.Get("redirect_uri").(string)
+	if !strutil.StrListContains(c.RedirectURIs, redirectURI) {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "redirect_uri is not allowed for the client", "")
+	}
+
+	if responseType := d.Get("response_type").(string); responseType != "code" {
+		return oidcErrorResponse(http.StatusBadRequest, "unsupported_response_type", `the only supported response_type is "code"`, state)
+	}
+
+	requestedScopes := strutil.RemoveDuplicatesStable(strings.Fields(d.Get("scope").(string)), false)
+	if !strutil.StrListContains(requestedScopes, openIDScope) {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_scope", `the "openid" scope is required`, state)
+	}
+
+	// Scopes the provider doesn't support are ignored
+	scopes := make([]string, 0, len(requestedScopes))
+	for _, s := range requestedScopes {
+		if s != openIDScope && strutil.StrListContains(p.ScopesSupported, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	codeChallenge := d.Get("code_challenge").(string)
+	codeChallengeMethod := d.Get("code_challenge_method").(string)
+	switch {
+	case codeChallenge == "" && c.ClientType == clientTypePublic:
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "public clients must use PKCE", state)
+	case codeChallengeMethod != codeChallengePlain && codeChallengeMethod != codeChallengeS256:
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", fmt.Sprintf("unsupported code_challenge_method %q", codeChallengeMethod), state)
+	case codeChallenge != "" && (len(codeChallenge) < 43 || len(codeChallenge) > 128):
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "code_challenge must be between 43 and 128 characters", state)
+	}
+
+	if req.EntityID == "" {
+		return oidcErrorResponse(http.StatusBadRequest, "access_denied", "no entity associated with the request's token", state)
+	}
+	entity, err := i.MemDBEntityByID(req.EntityID, false)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil || entity.Disabled {
+		return oidcErrorResponse(http.StatusBadRequest, "access_denied", "entity is not active", state)
+	}
+
+	groups, inheritedGroups, err := i.groupsByEntityID(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := i.entityAssigned(ctx, req.Storage, c, entity, append(groups, inheritedGroups...))
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return oidcErrorResponse(http.StatusForbidden, "access_denied", "identity is not assigned to the client", state)
+	}
+
+	code, err := base62.Random(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry, err := logical.StorageEntryJSON(authCodePath+authCodeStorageID(code), &authCode{
+		Provider:            p.Name,
+		ClientID:            c.ClientID,
+		EntityID:            entity.ID,
+		RedirectURI:         redirectURI,
+		Scopes:              scopes,
+		Nonce:               d.Get("nonce").(string),
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+		AuthTime:            now,
+		ExpireAt:            now.Add(authCodeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"code":  code,
+		"state": state,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcRawResponse(http.StatusOK, data), nil
+}
+
+// pathOIDCToken exchanges an authorization code for an ID token and an
+// access token
+func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	p, err := i.getOIDCProvider(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "provider not found", "")
+	}
+
+	// Authenticate the client
+	clientID, clientSecret, basicAuth := basicAuthCredentials(req.Headers)
+	if !basicAuth {
+		clientID = d.Get("client_id").(string)
+		clientSecret = d.Get("client_secret").(string)
+	}
+
+	c, err := i.getOIDCClientByID(ctx, req.Storage, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_client", "client failed to authenticate", "")
+	}
+	switch c.ClientType {
+	case clientTypePublic:
+		if clientSecret != "" {
+			return oidcErrorResponse(http.StatusUnauthorized, "invalid_client", "public clients may not authenticate with a secret", "")
+		}
+	default:
+		if subtle.ConstantTimeCompare([]byte(c.ClientSecret), []byte(clientSecret)) != 1 {
+			return oidcErrorResponse(http.StatusUnauthorized, "invalid_client", "client failed to authenticate", "")
+		}
+	}
+	if !p.allowsClient(c.ClientID) {
+		return oidcErrorResponse(http.StatusBadRequest, "unauthorized_client", "client is not allowed to use the provider", "")
+	}
+
+	if grantType := d.Get("grant_type").(string); grantType != "authorization_code" {
+		return oidcErrorResponse(http.StatusBadRequest, "unsupported_grant_type", `the only supported grant_type is "authorization_code"`, "")
+	}
+
+	code := d.Get("code").(string)
+	if code == "" {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "code is required", "")
+	}
+
+	// Codes may only be used once, so remove it before checking anything else
+	authCodeKey := authCodePath + authCodeStorageID(code)
+	entry, err := req.Storage.Get(ctx, authCodeKey)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_grant", "authorization code is invalid or has already been used", "")
+	}
+	if err := req.Storage.Delete(ctx, authCodeKey); err != nil {
+		return nil, err
+	}
+
+	var authCode authCode
+	if err := entry.DecodeJSON(&authCode); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case time.Now().After(authCode.ExpireAt):
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_grant", "authorization code has expired", "")
+	case authCode.Provider != p.Name || authCode.ClientID != c.ClientID:
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_grant", "authorization code was not issued to the client", "")
+	case authCode.RedirectURI != d.Get("redirect_uri").(string):
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_grant", "redirect_uri does not match the authorization request", "")
+	}
+
+	codeVerifier := d.Get("code_verifier").(string)
+	if authCode.CodeChallenge != "" {
+		if !verifyCodeChallenge(authCode.CodeChallenge, authCode.CodeChallengeMethod, codeVerifier) {
+			return oidcErrorResponse(http.StatusBadRequest, "invalid_grant", "code_verifier does not match the code challenge", "")
+		}
+	} else if codeVerifier != "" {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_grant", "no code challenge was sent in the authorization request", "")
+	}
+
+	entity, groups, resp, err := i.assignedEntity(ctx, req.Storage, c, authCode.EntityID, "invalid_grant")
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
+	key, err := i.getOIDCNamedKey(ctx, req.Storage, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("key %q of client %q not found", c.Key, c.Name)
+	}
+
+	claims, err := i.scopeClaims(ctx, req.Storage, authCode.Scopes, entity, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	idToken := map[string]interface{}{}
+	for k, v := range claims {
+		idToken[k] = v
+	}
+	idToken["iss"] = p.effectiveIssuer
+	idToken["sub"] = entity.ID
+	idToken["aud"] = c.ClientID
+	idToken["iat"] = now.Unix()
+	idToken["exp"] = now.Add(c.IDTokenTTL).Unix()
+	idToken["auth_time"] = authCode.AuthTime.Unix()
+	if authCode.Nonce != "" {
+		idToken["nonce"] = authCode.Nonce
+	}
+
+	payload, err := json.Marshal(idToken)
+	if err != nil {
+		return nil, err
+	}
+	signedIDToken, err := key.signPayload(payload)
+	if err != nil {
+		return nil, errwrap.Wrapf("error signing OIDC token: {{err}}", err)
+	}
+
+	accessToken := map[string]interface{}{
+		"iss":   p.effectiveIssuer,
+		"sub":   entity.ID,
+		"aud":   c.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(c.AccessTokenTTL).Unix(),
+		"scope": strings.Join(append([]string{openIDScope}, authCode.Scopes...), " "),
+	}
+	payload, err = json.Marshal(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	signedAccessToken, err := key.signPayloadWithType(payload, accessTokenType)
+	if err != nil {
+		return nil, errwrap.Wrapf("error signing access token: {{err}}", err)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"token_type":   "Bearer",
+		"access_token": signedAccessToken,
+		"id_token":     signedIDToken,
+		"expires_in":   int64(c.AccessTokenTTL.Seconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcRawResponse(http.StatusOK, data), nil
+}
+
+// pathOIDCUserInfo returns the claims of the scopes granted to an access
+// token
+func (i *IdentityStore) pathOIDCUserInfo(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	p, err := i.getOIDCProvider(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return oidcErrorResponse(http.StatusBadRequest, "invalid_request", "provider not found", "")
+	}
+
+	rawToken, ok := bearerToken(req.Headers)
+	if !ok {
+		rawToken = d.Get("access_token").(string)
+	}
+	if rawToken == "" {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", "access token is required", "")
+	}
+
+	parsedJWT, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", "access token is malformed", "")
+	}
+	if len(parsedJWT.Headers) != 1 || parsedJWT.Headers[0].ExtraHeaders[jose.HeaderType] != accessTokenType {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", "token is not an access token", "")
+	}
+
+	jwks, err := i.providerJWKS(ctx, req.Storage, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		jwt.Claims
+		Scope string `json:"scope"`
+	}
+	var valid bool
+	for _, key := range jwks.Keys {
+		if err := parsedJWT.Claims(key, &claims); err == nil {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", "unable to validate the token signature", "")
+	}
+
+	if err := claims.Validate(jwt.Expected{
+		Issuer: p.effectiveIssuer,
+		Time:   time.Now(),
+	}); err != nil {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", fmt.Sprintf("error validating claims: %s", err), "")
+	}
+	if len(claims.Audience) != 1 {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", "access token has an invalid audience", "")
+	}
+
+	c, err := i.getOIDCClientByID(ctx, req.Storage, claims.Audience[0])
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || !p.allowsClient(c.ClientID) {
+		return oidcErrorResponse(http.StatusUnauthorized, "invalid_token", "client of the access token is not allowed to use the provider", "")
+	}
+
+	entity, groups, resp, err := i.assignedEntity(ctx, req.Storage, c, claims.Subject, "invalid_token")
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
+	// Only scopes the provider still supports are honored
+	var scopes []string
+	for _, s := range strings.Fields(claims.Scope) {
+		if strutil.StrListContains(p.ScopesSupported, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	userInfo, err := i.scopeClaims(ctx, req.Storage, scopes, entity, groups)
+	if err != nil {
+		return nil, err
+	}
+	userInfo["sub"] = entity.ID
+
+	data, err := json.Marshal(userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcRawResponse(http.StatusOK, data), nil
+}
+
+// assignedEntity loads an active entity that is assigned to the client along
+// with its groups. If there is none, an error response with the given OAuth
+// error code is returned.
+func (i *IdentityStore) assignedEntity(ctx context.Context, s logical.Storage, c *client, entityID, errorCode string) (*identity.Entity, []*identity.Group, *logical.Response, error) {
+	status := http.StatusBadRequest
+	if errorCode == "invalid_token" {
+		status = http.StatusUnauthorized
+	}
+
+	entity, err := i.MemDBEntityByID(entityID, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if entity == nil || entity.Disabled {
+		resp, err := oidcErrorResponse(status, errorCode, "entity is not active", "")
+		return nil, nil, resp, err
+	}
+
+	groups, inheritedGroups, err := i.groupsByEntityID(entity.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	groups = append(groups, inheritedGroups...)
+
+	assigned, err := i.entityAssigned(ctx, s, c, entity, groups)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !assigned {
+		resp, err := oidcErrorResponse(status, errorCode, "identity is no longer assigned to the client", "")
+		return nil, nil, resp, err
+	}
+
+	return entity, groups, nil, nil
+}
+
+// scopeClaims populates the templates of the given scopes for the entity and
+// merges the resulting claims. Runtime template errors are logged but don't
+// prevent the other claims from being returned.
+func (i *IdentityStore) scopeClaims(ctx context.Context, s logical.Storage, scopes []string, entity *identity.Entity, groups []*identity.Group) (map[string]interface{}, error) {
+	claims := make(map[string]interface{})
+	for _, scopeName := range scopes {
+		sc, err := i.getOIDCScope(ctx, s, scopeName)
+		if err != nil {
+			return nil, err
+		}
+		if sc == nil || sc.Template == "" {
+			continue
+		}
+
+		_, populatedTemplate, err := identity.PopulateString(identity.PopulateStringInput{
+			Mode:   identity.JSONTemplating,
+			String: sc.Template,
+			Entity: entity,
+			Groups: groups,
+		})
+		if err != nil {
+			i.Logger().Warn("error populating OIDC scope template", "scope", scopeName, "error", err)
+			continue
+		}
+
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(populatedTemplate), &parsed); err != nil {
+			i.Logger().Warn("error parsing OIDC scope template", "scope", scopeName, "error", err)
+			continue
+		}
+
+		for k, v := range parsed {
+			if strutil.StrListContains(reservedProviderClaims, k) {
+				i.Logger().Warn("invalid top level OIDC scope template key", "scope", scopeName, "key", k)
+				continue
+			}
+			claims[k] = v
+		}
+	}
+
+	return claims, nil
+}
+
+// expireOIDCAuthCodes removes authorization codes that were never exchanged
+func (i *IdentityStore) expireOIDCAuthCodes(ctx context.Context, s logical.Storage) error {
+	codeIDs, err := s.List(ctx, authCodePath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, codeID := range codeIDs {
+		entry, err := s.Get(ctx, authCodePath+codeID)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			continue
+		}
+
+		var code authCode
+		if err := entry.DecodeJSON(&code); err != nil {
+			return err
+		}
+		if now.After(code.ExpireAt) {
+			if err := s.Delete(ctx, authCodePath+codeID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// authCodeStorageID returns the storage ID of an authorization code, so that
+// codes aren't stored in plaintext
+func authCodeStorageID(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyCodeChallenge checks the PKCE code verifier against the challenge
+// sent in the authorization request, as described in RFC 7636
+func verifyCodeChallenge(challenge, method, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	computed := verifier
+	if method == codeChallengeS256 {
+		sum := sha256.Sum256([]byte(verifier))
+		computed = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// basicAuthCredentials returns the client credentials of an HTTP basic
+// Authorization header, which are form-encoded as described in RFC 6749
+// section 2.3.1
+func basicAuthCredentials(headers map[string][]string) (string, string, bool) {
+	r := http.Request{Header: http.Header(headers)}
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return "", "", false
+	}
+
+	clientID, err := url.QueryUnescape(username)
+	if err != nil {
+		return "", "", false
+	}
+	clientSecret, err := url.QueryUnescape(password)
+	if err != nil {
+		return "", "", false
+	}
+
+	return clientID, clientSecret, true
+}
+
+// bearerToken returns the bearer token of an Authorization header
+func bearerToken(headers map[string][]string) (string, bool) {
+	for _, v := range http.Header(headers)["Authorization"] {
+		if strings.HasPrefix(v, "Bearer ") {
+			return strings.TrimSpace(v[len("Bearer "):]), true
+		}
+	}
+	return "", false
+}
+
+func oidcRawResponse(status int, data []byte) *logical.Response {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPStatusCode:  status,
+			logical.HTTPRawBody:     data,
+			logical.HTTPContentType: "application/json",
+		},
+	}
+}
+
+// oidcErrorResponse returns an OAuth 2.0 error response
+func oidcErrorResponse(status int, code, description, state string) (*logical.Response, error) {
+	body := map[string]interface{}{
+		"error":             code,
+		"error_description": description,
+	}
+	if state != "" {
+		body["state"] = state
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcRawResponse(status, data), nil
+}
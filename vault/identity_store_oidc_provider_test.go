@@ -0,0 +1,469 @@
+package vault
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// TestOIDC_Path_OIDCProvider tests CRUD operations and reference checks for
+// assignments, scopes, clients and providers
+func TestOIDC_Path_OIDCProvider(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	storage := &logical.InmemStorage{}
+
+	c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/key/test-key",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+	})
+
+	// Create an assignment with an entity that doesn't exist -- should fail
+	resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/assignment/test-assignment",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"entity_ids": "does-not-exist",
+		},
+		Storage: storage,
+	})
+	expectError(t, resp, err)
+
+	// Create an empty assignment -- should succeed
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/assignment/test-assignment",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+
+	// Create a scope named "openid" -- should fail
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/scope/openid",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+	})
+	expectError(t, resp, err)
+
+	// Create a scope with a reserved claim -- should fail
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/scope/test-scope",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"template": `{"nonce": "foo"}`,
+		},
+		Storage: storage,
+	})
+	expectError(t, resp, err)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/scope/test-scope",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"template":    `{"name": {{identity.entity.name}}}`,
+			"description": "entity name",
+		},
+		Storage: storage,
+	})
+	expectSuccess(t, resp, err)
+
+	// Create a client without a key -- should fail
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/client/test-client",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+	})
+	expectError(t, resp, err)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/client/test-client",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"key":           "test-key",
+			"redirect_uris": "https://example.com/callback",
+			"assignments":   "test-assignment",
+		},
+		Storage: storage,
+	})
+	expectSuccess(t, resp, err)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/client/test-client",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+	expected := map[string]interface{}{
+		"key":              "test-key",
+		"redirect_uris":    []string{"https://example.com/callback"},
+		"assignments":      []string{"test-assignment"},
+		"client_type":      "confidential",
+		"client_id":        resp.Data["client_id"],
+		"client_secret":    resp.Data["client_secret"],
+		"id_token_ttl":     int64(86400),
+		"access_token_ttl": int64(86400),
+	}
+	if diff := deep.Equal(expected, resp.Data); diff != nil {
+		t.Fatal(diff)
+	}
+	if len(resp.Data["client_id"].(string)) != 32 || !strings.HasPrefix(resp.Data["client_secret"].(string), clientSecretPrefix) {
+		t.Fatalf("unexpected client credentials: %#v", resp.Data)
+	}
+
+	// Change the client type -- should fail
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/client/test-client",
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"client_type": "public",
+		},
+		Storage: storage,
+	})
+	expectError(t, resp, err)
+
+	// Create a provider with an issuer that has a path -- should fail
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/provider/test-provider",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"issuer": "https://example.com/some/path",
+		},
+		Storage: storage,
+	})
+	expectError(t, resp, err)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/provider/test-provider",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"issuer":             "https://example.com:8200",
+			"allowed_client_ids": "*",
+			"scopes_supported":   "test-scope",
+		},
+		Storage: storage,
+	})
+	expectSuccess(t, resp, err)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/provider/test-provider",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+	expected = map[string]interface{}{
+		"issuer":             "https://example.com:8200/v1/identity/oidc/provider/test-provider",
+		"allowed_client_ids": []string{"*"},
+		"scopes_supported":   []string{"test-scope"},
+	}
+	if diff := deep.Equal(expected, resp.Data); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// Delete objects that are still referenced -- should fail
+	for _, path := range []string{"oidc/key/test-key", "oidc/assignment/test-assignment", "oidc/scope/test-scope"} {
+		resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+			Path:      path,
+			Operation: logical.DeleteOperation,
+			Storage:   storage,
+		})
+		expectError(t, resp, err)
+	}
+
+	// Delete in dependency order -- should succeed
+	for _, path := range []string{"oidc/provider/test-provider", "oidc/scope/test-scope", "oidc/client/test-client", "oidc/assignment/test-assignment", "oidc/key/test-key"} {
+		resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+			Path:      path,
+			Operation: logical.DeleteOperation,
+			Storage:   storage,
+		})
+		expectSuccess(t, resp, err)
+	}
+}
+
+// TestOIDC_ProviderFlow tests the authorization code flow with PKCE, from
+// the authorization request to the userinfo endpoint
+func TestOIDC_ProviderFlow(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	storage := &logical.InmemStorage{}
+
+	testEntity := &identity.Entity{
+		Name:      "test-entity-name",
+		ID:        "test-entity-id",
+		BucketKey: "test-entity-bucket-key",
+	}
+	txn := c.identityStore.db.Txn(true)
+	defer txn.Abort()
+	if err := c.identityStore.upsertEntityInTxn(ctx, txn, testEntity, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	txn.Commit()
+
+	requests := []*logical.Request{
+		{Path: "oidc/key/test-key"},
+		{Path: "oidc/assignment/test-assignment", Data: map[string]interface{}{"entity_ids": "test-entity-id"}},
+		{Path: "oidc/scope/profile", Data: map[string]interface{}{"template": `{"name": {{identity.entity.name}}}`}},
+		{Path: "oidc/client/test-client", Data: map[string]interface{}{
+			"key":           "test-key",
+			"redirect_uris": "https://example.com/callback",
+			"assignments":   "test-assignment",
+		}},
+		{Path: "oidc/client/other-client", Data: map[string]interface{}{
+			"key":           "test-key",
+			"redirect_uris": "https://example.com/callback",
+		}},
+	}
+	for _, req := range requests {
+		req.Operation = logical.CreateOperation
+		req.Storage = storage
+		resp, err := c.identityStore.HandleRequest(ctx, req)
+		expectSuccess(t, resp, err)
+	}
+
+	resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/client/test-client",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+	clientID := resp.Data["client_id"].(string)
+	clientSecret := resp.Data["client_secret"].(string)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/client/other-client",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+	otherClientID := resp.Data["client_id"].(string)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/provider/test-provider",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"issuer":             "https://example.com",
+			"allowed_client_ids": clientID + "," + otherClientID,
+			"scopes_supported":   "profile",
+		},
+		Storage: storage,
+	})
+	expectSuccess(t, resp, err)
+	issuer := "https://example.com/v1/identity/oidc/provider/test-provider"
+
+	// Check the discovery document
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/provider/test-provider/.well-known/openid-configuration",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+	var disc providerDiscovery
+	if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &disc); err != nil {
+		t.Fatal(err)
+	}
+	if disc.Issuer != issuer || disc.Token != issuer+"/token" || disc.Keys != issuer+"/.well-known/keys" {
+		t.Fatalf("unexpected discovery document: %#v", disc)
+	}
+	if diff := deep.Equal(disc.Scopes, []string{"openid", "profile"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	codeVerifier := strings.Repeat("v", 43)
+	sum := sha256.Sum256([]byte(codeVerifier))
+	codeChallenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	authorize := func(clientID, redirectURI string) (int, map[string]interface{}) {
+		t.Helper()
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Path:      "oidc/provider/test-provider/authorize",
+			Operation: logical.UpdateOperation,
+			Data: map[string]interface{}{
+				"client_id":             clientID,
+				"scope":                 "openid profile unknown",
+				"redirect_uri":          redirectURI,
+				"response_type":         "code",
+				"state":                 "test-state",
+				"nonce":                 "test-nonce",
+				"code_challenge":        codeChallenge,
+				"code_challenge_method": "S256",
+			},
+			Storage:  storage,
+			EntityID: "test-entity-id",
+		})
+		expectSuccess(t, resp, err)
+		var body map[string]interface{}
+		if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &body); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data[logical.HTTPStatusCode].(int), body
+	}
+
+	// Use a redirect URI that isn't allowed -- should fail
+	status, body := authorize(clientID, "https://example.com/other")
+	if status != http.StatusBadRequest || body["error"] != "invalid_request" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+
+	// Authorize a client the entity isn't assigned to -- should fail
+	status, body = authorize(otherClientID, "https://example.com/callback")
+	if status != http.StatusForbidden || body["error"] != "access_denied" || body["state"] != "test-state" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+
+	status, body = authorize(clientID, "https://example.com/callback")
+	if status != http.StatusOK || body["state"] != "test-state" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+	code := body["code"].(string)
+
+	token := func(authHeader string, data map[string]interface{}) (int, map[string]interface{}) {
+		t.Helper()
+		req := &logical.Request{
+			Path:      "oidc/provider/test-provider/token",
+			Operation: logical.UpdateOperation,
+			Data:      data,
+			Storage:   storage,
+		}
+		if authHeader != "" {
+			req.Headers = map[string][]string{"Authorization": {authHeader}}
+		}
+		resp, err := c.identityStore.HandleRequest(ctx, req)
+		expectSuccess(t, resp, err)
+		var body map[string]interface{}
+		if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &body); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data[logical.HTTPStatusCode].(int), body
+	}
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret))
+
+	// Use the wrong client secret -- should fail
+	status, body = token("", map[string]interface{}{
+		"client_id":     clientID,
+		"client_secret": "wrong",
+		"grant_type":    "authorization_code",
+		"code":          code,
+	})
+	if status != http.StatusUnauthorized || body["error"] != "invalid_client" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+
+	// Exchange the code -- should succeed
+	status, body = token(basicAuth, map[string]interface{}{
+		"grant_type":    "authorization_code",
+		"code":          code,
+		"redirect_uri":  "https://example.com/callback",
+		"code_verifier": codeVerifier,
+	})
+	if status != http.StatusOK || body["token_type"] != "Bearer" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+	idToken := body["id_token"].(string)
+	accessToken := body["access_token"].(string)
+
+	// Reuse the code -- should fail
+	status, body = token(basicAuth, map[string]interface{}{
+		"grant_type":    "authorization_code",
+		"code":          code,
+		"redirect_uri":  "https://example.com/callback",
+		"code_verifier": codeVerifier,
+	})
+	if status != http.StatusBadRequest || body["error"] != "invalid_grant" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+
+	// Validate the ID token against the provider's keys
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/provider/test-provider/.well-known/keys",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	expectSuccess(t, resp, err)
+	jwks := &jose.JSONWebKeySet{}
+	if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), jwks); err != nil {
+		t.Fatal(err)
+	}
+
+	parsedIDToken, err := jwt.ParseSigned(idToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := map[string]interface{}{}
+	if err := parsedIDToken.Claims(jwks.Keys[0], &claims); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]interface{}{
+		"iss":   issuer,
+		"sub":   "test-entity-id",
+		"aud":   clientID,
+		"nonce": "test-nonce",
+		"name":  "test-entity-name",
+	} {
+		if claims[k] != v {
+			t.Fatalf("bad claim %q: expected %v, got %v", k, v, claims[k])
+		}
+	}
+	if _, ok := claims["auth_time"]; !ok {
+		t.Fatal("expected auth_time claim")
+	}
+
+	// An ID token isn't accepted by the userinfo endpoint
+	userInfo := func(token string) (int, map[string]interface{}) {
+		t.Helper()
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Path:      "oidc/provider/test-provider/userinfo",
+			Operation: logical.ReadOperation,
+			Headers:   map[string][]string{"Authorization": {"Bearer " + token}},
+			Storage:   storage,
+		})
+		expectSuccess(t, resp, err)
+		var body map[string]interface{}
+		if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &body); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data[logical.HTTPStatusCode].(int), body
+	}
+	status, body = userInfo(idToken)
+	if status != http.StatusUnauthorized || body["error"] != "invalid_token" {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+
+	status, body = userInfo(accessToken)
+	expectedUserInfo := map[string]interface{}{
+		"sub":  "test-entity-id",
+		"name": "test-entity-name",
+	}
+	if status != http.StatusOK {
+		t.Fatalf("unexpected response: %d %#v", status, body)
+	}
+	if diff := deep.Equal(expectedUserInfo, body); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+func TestOIDC_verifyCodeChallenge(t *testing.T) {
+	// Example from RFC 7636 appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if !verifyCodeChallenge(challenge, codeChallengeS256, verifier) {
+		t.Fatal("expected S256 challenge to verify")
+	}
+	if verifyCodeChallenge(challenge, codeChallengePlain, verifier) {
+		t.Fatal("expected plain challenge to fail")
+	}
+	if !verifyCodeChallenge(verifier, codeChallengePlain, verifier) {
+		t.Fatal("expected plain challenge to verify")
+	}
+}
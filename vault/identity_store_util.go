@@ -2065,3 +2065,56 @@ func (i *IdentityStore) handleAliasListCommon(ctx context.Context, groupAlias bo
 
 	return logical.ListResponseWithInfo(aliasIDs, aliasInfo), nil
 }
+
+// deleteNamespaceArtifacts deletes the groups and entities of the namespace
+// in the context, which is being deleted
+func (i *IdentityStore) deleteNamespaceArtifacts(ctx context.Context) error {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var groupIDs []string
+	txn := i.db.Txn(false)
+	iter, err := txn.Get(groupsTable, "namespace_id", ns.ID)
+	if err != nil {
+		return errwrap.Wrapf("failed to lookup groups using namespace ID: {{err}}", err)
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		groupIDs = append(groupIDs, raw.(*identity.Group).ID)
+	}
+	for _, groupID := range groupIDs {
+		if _, err := i.handleGroupDeleteCommon(ctx, groupID, true); err != nil {
+			return err
+		}
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	txn = i.db.Txn(true)
+	defer txn.Abort()
+
+	iter, err = txn.Get(entitiesTable, "namespace_id", ns.ID)
+	if err != nil {
+		return errwrap.Wrapf("failed to lookup entities using namespace ID: {{err}}", err)
+	}
+	var entities []*identity.Entity
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		entities = append(entities, raw.(*identity.Entity))
+	}
+	for _, entity := range entities {
+		// Clone the entity as its groups and aliases are updated in the
+		// same transaction
+		entity, err = entity.Clone()
+		if err != nil {
+			return err
+		}
+		if err := i.handleEntityDeleteCommon(ctx, txn, entity); err != nil {
+			return err
+		}
+	}
+
+	txn.Commit()
+	return nil
+}
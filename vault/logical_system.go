@@ -32,6 +32,8 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/jsonutil"
 	"github.com/hashicorp/vault/sdk/helper/parseutil"
+	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/helper/random"
 	"github.com/hashicorp/vault/sdk/helper/strutil"
 	"github.com/hashicorp/vault/sdk/helper/wrapping"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -158,6 +160,10 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.authPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.leasePaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.policyPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.passwordPolicyPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.namespacePaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.controlGroupPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.quotasPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.wrappingPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.toolsPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.capabilitiesPaths()...)
@@ -582,7 +588,7 @@ func (b *SystemBackend) handleCapabilities(ctx context.Context, req *logical.Req
 	}
 
 	for _, path := range paths {
-		pathCap, err := b.Core.Capabilities(ctx, token, path)
+		pathCap, err := b.Core.capabilities(ctx, token, path, req.Connection)
 		if err != nil {
 			if !strings.HasSuffix(req.Path, "capabilities-self") && errwrap.Contains(err, logical.ErrPermissionDenied.Error()) {
 				return nil, &logical.StatusBadRequest{Err: "invalid token"}
@@ -600,6 +606,153 @@ func (b *SystemBackend) handleCapabilities(ctx context.Context, req *logical.Req
 	return ret, nil
 }
 
+// handlePolicyExplain evaluates a request against the policies of a token,
+// or a given set of policies, and explains the decision
+func (b *SystemBackend) handlePolicyExplain(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	path := strings.TrimPrefix(d.Get("path").(string), "/")
+	if path == "" {
+		return logical.ErrorResponse("path must be supplied"), logical.ErrInvalidRequest
+	}
+
+	operation := logical.Operation(strings.ToLower(d.Get("operation").(string)))
+	if _, ok := policyExplainOperations[operation]; !ok {
+		return logical.ErrorResponse(fmt.Sprintf("unsupported operation %q", operation)), logical.ErrInvalidRequest
+	}
+
+	parameters := d.Get("parameters").(map[string]interface{})
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+
+	token := d.Get("token").(string)
+	policyNames := d.Get("policies").([]string)
+	if token != "" && len(policyNames) > 0 {
+		return logical.ErrorResponse("only one of token and policies can be supplied"), logical.ErrInvalidRequest
+	}
+	if token == "" && len(policyNames) == 0 {
+		token = req.ClientToken
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	explainReq := &logical.Request{
+		Operation:  operation,
+		Path:       path,
+		Data:       parameters,
+		Connection: req.Connection,
+	}
+
+	var warnings []string
+	var policies []*Policy
+	var entity *identity.Entity
+	var groups []*identity.Group
+	aclCtx := ctx
+	if token != "" {
+		te, err := b.Core.tokenStore.Lookup(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if te == nil {
+			return logical.ErrorResponse("invalid token"), logical.ErrInvalidRequest
+		}
+		explainReq.SetTokenEntry(te)
+
+		var tokenNS *namespace.Namespace
+		var tokenPolicyNames map[string][]string
+		tokenNS, entity, tokenPolicyNames, err = b.Core.tokenPolicyNames(ctx, te)
+		if err != nil {
+			return nil, err
+		}
+		aclCtx = namespace.ContextWithNamespace(ctx, tokenNS)
+		policies, groups, err = b.Core.policyStore.aclPolicies(aclCtx, entity, tokenPolicyNames)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		policyNames = policyutil.SanitizePolicies(policyNames, false)
+		policies, _, err = b.Core.policyStore.aclPolicies(ctx, nil, map[string][]string{ns.ID: policyNames})
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range policyNames {
+			var found bool
+			for _, policy := range policies {
+				if policy.Name == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				warnings = append(warnings, fmt.Sprintf("policy %q does not exist", name))
+			}
+		}
+	}
+
+	data, err := b.Core.explainPolicies(ctx, aclCtx, explainReq, policies, entity, groups)
+	if err != nil {
+		return handleError(err)
+	}
+
+	resp := &logical.Response{
+		Data: data,
+	}
+	for _, warning := range warnings {
+		resp.AddWarning(warning)
+	}
+	return resp, nil
+}
+
+// handlePolicyLint parses the given ACL policy, or an existing one, and
+// reports likely mistakes in it
+func (b *SystemBackend) handlePolicyLint(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	raw := d.Get("policy").(string)
+	name := strings.ToLower(d.Get("name").(string))
+	switch {
+	case raw != "" && name != "":
+		return logical.ErrorResponse("only one of policy and name can be supplied"), logical.ErrInvalidRequest
+	case raw == "" && name == "":
+		return logical.ErrorResponse("policy or name must be supplied"), logical.ErrInvalidRequest
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		stored, err := b.Core.policyStore.GetPolicy(ctx, name, PolicyTypeACL)
+		if err != nil {
+			return handleError(err)
+		}
+		if stored == nil {
+			return logical.ErrorResponse(fmt.Sprintf("policy %q does not exist", name)), logical.ErrInvalidRequest
+		}
+		raw = stored.Raw
+	} else if polBytes, err := base64.StdEncoding.DecodeString(raw); err == nil {
+		raw = string(polBytes)
+	}
+
+	policy, err := ParseACLPolicy(ns, raw)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	findings := lintACLPolicy(policy, b.Core.policyLintMountPaths(ns))
+	ret := make([]map[string]interface{}, 0, len(findings))
+	for _, finding := range findings {
+		ret = append(ret, finding.toMap())
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"findings": ret,
+		},
+	}, nil
+}
+
 // handleRekeyRetrieve returns backed-up, PGP-encrypted unseal keys from a
 // rekey operation
 func (b *SystemBackend) handleRekeyRetrieve(
@@ -1550,6 +1703,32 @@ func (b *SystemBackend) handleLeaseLookupList(ctx context.Context, req *logical.
 	return logical.ListResponse(keys), nil
 }
 
+// handleLeaseCount returns the number of leases of the mounts within the
+// namespace of the request
+func (b *SystemBackend) handleLeaseCount(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	total, counts := b.Core.expiration.leaseCounts(ns.Path)
+	mounts := make(map[string]int, len(counts))
+	for mountPath, count := range counts {
+		mounts[strings.TrimPrefix(mountPath, ns.Path)] = count
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"lease_count": total,
+			"mounts":      mounts,
+		},
+	}
+	if b.Core.expiration.inRestoreMode() {
+		resp.AddWarning("Leases are still being restored, so the counts don't include every lease yet.")
+	}
+	return resp, nil
+}
+
 // handleRenew is used to renew a lease with a given LeaseID
 func (b *SystemBackend) handleRenew(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	// Get all the options
@@ -2040,6 +2219,483 @@ func (b *SystemBackend) handlePoliciesDelete(policyType PolicyType) framework.Op
 	}
 }
 
+const passwordPolicySubPath = "password_policy/"
+
+// passwordPolicyConfig is the storage entry of a password policy
+type passwordPolicyConfig struct {
+	HCLPolicy string `json:"policy"`
+}
+
+func retrievePasswordPolicy(ctx context.Context, s logical.Storage, name string) (*passwordPolicyConfig, error) {
+	entry, err := s.Get(ctx, passwordPolicySubPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var policy passwordPolicyConfig
+	if err := entry.DecodeJSON(&policy); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// handlePasswordPoliciesList lists the names of the password policies
+func (b *SystemBackend) handlePasswordPoliciesList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	keys, err := req.Storage.List(ctx, passwordPolicySubPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(keys), nil
+}
+
+// handlePasswordPoliciesSet validates and stores a password policy
+func (b *SystemBackend) handlePasswordPoliciesSet(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing policy name"), nil
+	}
+
+	rawPolicy := data.Get("policy").(string)
+	if rawPolicy == "" {
+		return logical.ErrorResponse("'policy' parameter not supplied or empty"), nil
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(rawPolicy); err == nil {
+		rawPolicy = string(decoded)
+	}
+
+	generator, err := random.ParsePolicy(rawPolicy)
+	if err != nil {
+		return logical.ErrorResponse("invalid password policy: %s", err), nil
+	}
+
+	// Make sure the policy can actually produce passwords before storing it
+	if _, err := generator.Generate(ctx, nil); err != nil {
+		return logical.ErrorResponse("unable to generate password from the policy: %s", err), nil
+	}
+
+	entry, err := logical.StorageEntryJSON(passwordPolicySubPath+name, &passwordPolicyConfig{
+		HCLPolicy: rawPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// handlePasswordPoliciesRead returns the rules of a password policy
+func (b *SystemBackend) handlePasswordPoliciesRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	policy, err := retrievePasswordPolicy(ctx, req.Storage, data.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"policy": policy.HCLPolicy,
+		},
+	}, nil
+}
+
+// handlePasswordPoliciesDelete deletes a password policy
+func (b *SystemBackend) handlePasswordPoliciesDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, passwordPolicySubPath+data.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handlePasswordPoliciesGenerate generates a password from a password policy
+func (b *SystemBackend) handlePasswordPoliciesGenerate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	policy, err := retrievePasswordPolicy(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return logical.ErrorResponse("password policy %q not found", name), logical.ErrInvalidRequest
+	}
+
+	generator, err := random.ParsePolicy(policy.HCLPolicy)
+	if err != nil {
+		return nil, errwrap.Wrapf("stored password policy is invalid: {{err}}", err)
+	}
+
+	password, err := generator.Generate(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"password": password,
+		},
+	}, nil
+}
+
+// handleNamespacesList lists the direct children of the request namespace
+func (b *SystemBackend) handleNamespacesList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	keyInfo := make(map[string]interface{})
+	for _, child := range b.Core.ListNamespaces(ns, false) {
+		key := strings.TrimPrefix(child.Path, ns.Path)
+		keys = append(keys, key)
+		keyInfo[key] = map[string]interface{}{
+			"id":   child.ID,
+			"path": child.Path,
+		}
+	}
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+// handleNamespacesRead returns the ID and full path of a namespace
+func (b *SystemBackend) handleNamespacesRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ns, err := b.Core.lookupNamespace(ctx, data.Get("path").(string))
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"id":   ns.ID,
+			"path": ns.Path,
+		},
+	}, nil
+}
+
+// handleNamespacesCreate creates a namespace nested in the request namespace
+func (b *SystemBackend) handleNamespacesCreate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	path := data.Get("path").(string)
+	if namespace.Canonicalize(path) == "" {
+		return logical.ErrorResponse("missing namespace path"), logical.ErrInvalidRequest
+	}
+
+	ns, err := b.Core.createNamespace(ctx, path)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"id":   ns.ID,
+			"path": ns.Path,
+		},
+	}, nil
+}
+
+// handleNamespacesDelete deletes a namespace along with everything it holds
+func (b *SystemBackend) handleNamespacesDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.deleteNamespace(ctx, data.Get("path").(string)); err != nil {
+		return handleError(err)
+	}
+	return nil, nil
+}
+
+// quotaStoreOrError returns the quota store, which is not set up on DR
+// secondaries
+func (b *SystemBackend) quotaStoreOrError() (*QuotaStore, error) {
+	store := b.Core.quotaStore
+	if store == nil {
+		return nil, errors.New("quotas are not available on this node")
+	}
+	return store, nil
+}
+
+// handleLeaseCountQuotasList lists the names of the lease count quotas
+func (b *SystemBackend) handleLeaseCountQuotasList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(store.ListLeaseCountQuotas()), nil
+}
+
+// handleLeaseCountQuotasRead returns a lease count quota along with the
+// number of leases counted against it
+func (b *SystemBackend) handleLeaseCountQuotasRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	quota, count := store.GetLeaseCountQuota(data.Get("name").(string))
+	if quota == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"name":       quota.Name,
+			"path":       quota.Path,
+			"role":       quota.Role,
+			"entity_id":  quota.EntityID,
+			"max_leases": quota.MaxLeases,
+			"leases":     count,
+		},
+	}, nil
+}
+
+// handleLeaseCountQuotasUpdate creates or updates a lease count quota
+func (b *SystemBackend) handleLeaseCountQuotasUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	name := data.Get("name").(string)
+	quota, _ := store.GetLeaseCountQuota(name)
+	if quota == nil {
+		quota = &LeaseCountQuota{
+			Name: name,
+		}
+	}
+
+	if pathRaw, ok := data.GetOk("path"); ok {
+		quota.Path, err = b.Core.quotaPath(pathRaw.(string))
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+	}
+	if roleRaw, ok := data.GetOk("role"); ok {
+		quota.Role = roleRaw.(string)
+	}
+	if entityIDRaw, ok := data.GetOk("entity_id"); ok {
+		quota.EntityID = entityIDRaw.(string)
+	}
+	if maxLeasesRaw, ok := data.GetOk("max_leases"); ok {
+		quota.MaxLeases = maxLeasesRaw.(int)
+	}
+	if quota.MaxLeases <= 0 {
+		return logical.ErrorResponse("'max_leases' must be greater than zero"), logical.ErrInvalidRequest
+	}
+
+	countLeases := func(quota *LeaseCountQuota) (int, error) {
+		return b.Core.expiration.countQuotaLeases(ctx, quota)
+	}
+	if err := store.SetLeaseCountQuota(ctx, quota, countLeases); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleLeaseCountQuotasDelete deletes a lease count quota
+func (b *SystemBackend) handleLeaseCountQuotasDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+	if err := store.DeleteLeaseCountQuota(ctx, data.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleRateLimitQuotasList lists the names of the rate limit quotas
+func (b *SystemBackend) handleRateLimitQuotasList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(store.ListRateLimitQuotas()), nil
+}
+
+// handleRateLimitQuotasRead returns a rate limit quota
+func (b *SystemBackend) handleRateLimitQuotasRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	quota := store.GetRateLimitQuota(data.Get("name").(string))
+	if quota == nil {
+		return nil, nil
+	}
+
+	sourceCIDRs := make([]string, 0, len(quota.SourceCIDRs))
+	for _, cidr := range quota.SourceCIDRs {
+		sourceCIDRs = append(sourceCIDRs, cidr.String())
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"name":         quota.Name,
+			"path":         quota.Path,
+			"role":         quota.Role,
+			"entity_id":    quota.EntityID,
+			"source_cidrs": sourceCIDRs,
+			"rate":         quota.Rate,
+			"interval":     int64(quota.Interval.Seconds()),
+			"burst":        quota.Burst,
+		},
+	}, nil
+}
+
+// handleRateLimitQuotasUpdate creates or updates a rate limit quota
+func (b *SystemBackend) handleRateLimitQuotasUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	name := data.Get("name").(string)
+	quota := store.GetRateLimitQuota(name)
+	if quota == nil {
+		quota = &RateLimitQuota{
+			Name:     name,
+			Interval: time.Second,
+		}
+	}
+
+	if pathRaw, ok := data.GetOk("path"); ok {
+		quota.Path, err = b.Core.quotaPath(pathRaw.(string))
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+	}
+	if roleRaw, ok := data.GetOk("role"); ok {
+		quota.Role = roleRaw.(string)
+	}
+	if entityIDRaw, ok := data.GetOk("entity_id"); ok {
+		quota.EntityID = entityIDRaw.(string)
+	}
+	if sourceCIDRsRaw, ok := data.GetOk("source_cidrs"); ok {
+		quota.SourceCIDRs, err = parseutil.ParseAddrs(sourceCIDRsRaw.([]string))
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("invalid 'source_cidrs': %v", err)), logical.ErrInvalidRequest
+		}
+	}
+	if rateRaw, ok := data.GetOk("rate"); ok {
+		quota.Rate = rateRaw.(int)
+	}
+	if intervalRaw, ok := data.GetOk("interval"); ok {
+		quota.Interval = time.Duration(intervalRaw.(int)) * time.Second
+	}
+	if burstRaw, ok := data.GetOk("burst"); ok {
+		quota.Burst = burstRaw.(int)
+	}
+	if quota.Burst == 0 {
+		quota.Burst = quota.Rate
+	}
+
+	switch {
+	case quota.Rate <= 0:
+		return logical.ErrorResponse("'rate' must be greater than zero"), logical.ErrInvalidRequest
+	case quota.Interval <= 0:
+		return logical.ErrorResponse("'interval' must be greater than zero"), logical.ErrInvalidRequest
+	case quota.Burst <= 0:
+		return logical.ErrorResponse("'burst' must be greater than zero"), logical.ErrInvalidRequest
+	}
+
+	if err := store.SetRateLimitQuota(ctx, quota); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleRateLimitQuotasDelete deletes a rate limit quota
+func (b *SystemBackend) handleRateLimitQuotasDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+	if err := store.DeleteRateLimitQuota(ctx, data.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleQuotasConfigRead returns the quota configuration
+func (b *SystemBackend) handleQuotasConfigRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"rate_limit_exempt_paths": store.RateLimitExemptPaths(),
+		},
+	}, nil
+}
+
+// handleQuotasConfigUpdate updates the quota configuration
+func (b *SystemBackend) handleQuotasConfigUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	store, err := b.quotaStoreOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	pathsRaw, ok := data.GetOk("rate_limit_exempt_paths")
+	if !ok {
+		return nil, nil
+	}
+	paths := make([]string, 0, len(pathsRaw.([]string)))
+	for _, path := range pathsRaw.([]string) {
+		if path = strings.TrimPrefix(path, "/"); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if err := store.SetRateLimitExemptPaths(ctx, paths); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleControlGroupAuthorize records the approval of a control group request
+// by the entity of the calling token
+func (b *SystemBackend) handleControlGroupAuthorize(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	accessor := data.Get("accessor").(string)
+	if accessor == "" {
+		return logical.ErrorResponse("missing accessor"), logical.ErrInvalidRequest
+	}
+
+	_, approved, err := b.Core.authorizeControlGroupRequest(ctx, req, accessor)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"approved": approved,
+		},
+	}, nil
+}
+
+// handleControlGroupRequest returns the status of a control group request
+func (b *SystemBackend) handleControlGroupRequest(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	accessor := data.Get("accessor").(string)
+	if accessor == "" {
+		return logical.ErrorResponse("missing accessor"), logical.ErrInvalidRequest
+	}
+
+	info, err := b.Core.controlGroupRequestInfo(ctx, accessor)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return &logical.Response{
+		Data: info,
+	}, nil
+}
+
 // handleAuditTable handles the "audit" endpoint to provide the audit table
 func (b *SystemBackend) handleAuditTable(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	b.Core.auditLock.RLock()
@@ -2920,6 +3576,11 @@ func hasMountAccess(ctx context.Context, acl *ACL, path string) bool {
 
 			aclCapabilitiesGiven = true
 
+			return true
+
+		case perms.hasConditionalAccess():
+			aclCapabilitiesGiven = true
+
 			return true
 		}
 
@@ -3738,6 +4399,249 @@ or delete a policy.
 		`,
 	},
 
+	"policy-explain": {
+		`Explain whether a request is allowed by a token or set of policies.`,
+		`
+Evaluates a request on the given path with the given operation and parameters
+against the policies of a token, or against a given set of policies, without
+running it. Defaults to the policies of the calling token. The response holds
+the final verdict and its reason, the rules matching the path in decreasing
+order of priority along with why each of them lost to the selected one, the
+rule each policy contributes and the result of the parameter constraints.
+		`,
+	},
+
+	"policy-explain-token": {
+		`The token whose policies the request is evaluated against.`,
+		"",
+	},
+
+	"policy-explain-policies": {
+		`The names of the policies the request is evaluated against, instead of the policies of a token.`,
+		"",
+	},
+
+	"policy-explain-path": {
+		`The path of the request.`,
+		"",
+	},
+
+	"policy-explain-operation": {
+		`The operation of the request: create, read, update, delete or list. Defaults to read.`,
+		"",
+	},
+
+	"policy-explain-parameters": {
+		`The parameters of the request, checked against the parameter constraints of the policies.`,
+		"",
+	},
+
+	"policy-lint": {
+		`Report likely mistakes in an ACL policy.`,
+		`
+Parses the given ACL policy, or an existing policy, and reports likely
+mistakes without storing anything: path stanzas repeated or shadowed by rules
+of higher priority, overlapping rules where the rule taking priority drops
+capabilities of the other, paths matching no mounted secrets engine or auth
+method, the deprecated "policy" field, and globs or segment wildcards granting
+broader access than likely intended. Each finding holds the check reporting
+it, the path of the rule, its line in the policy and a message.
+		`,
+	},
+
+	"policy-lint-policy": {
+		`The ACL policy to lint, in HCL or JSON, optionally base64-encoded.`,
+		"",
+	},
+
+	"policy-lint-name": {
+		`The name of an existing ACL policy to lint, instead of the given policy.`,
+		"",
+	},
+
+	"control-group-authorize": {
+		`Authorize a control group request.`,
+		`
+Requests on paths whose policy has a control_group block return a control
+group token instead of running. Members of the identity groups of the control
+group authorize the request through this endpoint; once every factor has its
+required number of approvals, the requester unwraps the token to run the
+request.
+		`,
+	},
+
+	"control-group-request": {
+		`Check the status of a control group request.`,
+		`
+Returns the path and requesting entity of a control group request, the
+entities that authorized it so far and whether it has been approved.
+		`,
+	},
+
+	"control-group-accessor": {
+		`The accessor of the control group token.`,
+		"",
+	},
+
+	"namespaces-list": {
+		`List the namespaces nested directly in the current namespace.`,
+		"",
+	},
+
+	"namespaces": {
+		`Create, read or delete a namespace.`,
+		`
+Namespaces are isolated environments with their own mounts, auth methods,
+policies, tokens and identities. The path is relative to the namespace of the
+request. A namespace can only be deleted once it has no child namespaces;
+deleting it revokes its tokens and leases and removes all of its data.
+		`,
+	},
+
+	"namespaces-path": {
+		`The path of the namespace, relative to the current namespace.`,
+		"",
+	},
+
+	"lease-count-quota-list": {
+		`List the configured lease count quotas.`,
+		"",
+	},
+
+	"lease-count-quota": {
+		`Create, read, update or delete a lease count quota.`,
+		`
+Lease count quotas cap the number of leases, including token leases, that can
+exist at once. A quota applies to a mount or a namespace, and can be narrowed
+down to the clients of an auth role or to a single entity. Requests that would
+create a lease beyond a quota are rejected with a 429 status code. Leases that
+already exist when a quota is created count against it but are not revoked.
+		`,
+	},
+
+	"lease-count-quota-name": {
+		`The name of the quota.`,
+		"",
+	},
+
+	"lease-count-quota-path": {
+		`The mount or namespace path the quota applies to, relative to the root namespace. If empty, the quota applies to every lease.`,
+		"",
+	},
+
+	"lease-count-quota-role": {
+		`If set, the quota only applies to the leases of tokens issued against this role. This is the token store role, or the 'role' or 'role_name' metadata set by role based auth methods.`,
+		"",
+	},
+
+	"lease-count-quota-entity-id": {
+		`If set, the quota only applies to the leases of this entity.`,
+		"",
+	},
+
+	"lease-count-quota-max-leases": {
+		`The maximum number of leases the quota allows.`,
+		"",
+	},
+
+	"rate-limit-quota-list": {
+		`List the configured rate limit quotas.`,
+		"",
+	},
+
+	"rate-limit-quota": {
+		`Create, read, update or delete a rate limit quota.`,
+		`
+Rate limit quotas limit the rate of the requests made to a mount or a
+namespace with a token bucket, and can be narrowed down to the clients of an
+auth role, to a single entity or to a set of source addresses. Requests
+beyond a quota are rejected with a 429 status code and a Retry-After header
+before they are processed. The paths configured at sys/quotas/config are
+exempt from rate limit quotas.
+		`,
+	},
+
+	"rate-limit-quota-name": {
+		`The name of the quota.`,
+		"",
+	},
+
+	"rate-limit-quota-path": {
+		`The mount or namespace path the quota applies to, relative to the root namespace. If empty, the quota applies to every request.`,
+		"",
+	},
+
+	"rate-limit-quota-role": {
+		`If set, the quota only applies to the requests of tokens issued against this role, and to the login requests against it. This is the token store role, or the 'role' or 'role_name' metadata set by role based auth methods.`,
+		"",
+	},
+
+	"rate-limit-quota-entity-id": {
+		`If set, the quota only applies to the requests of this entity.`,
+		"",
+	},
+
+	"rate-limit-quota-source-cidrs": {
+		`If set, the quota only applies to the requests coming from these CIDR blocks.`,
+		"",
+	},
+
+	"rate-limit-quota-rate": {
+		`The number of requests allowed per interval.`,
+		"",
+	},
+
+	"rate-limit-quota-interval": {
+		`The interval the rate applies to. Defaults to one second.`,
+		"",
+	},
+
+	"rate-limit-quota-burst": {
+		`The number of requests that can be made at once. Defaults to the rate.`,
+		"",
+	},
+
+	"quotas-config": {
+		`Read or update the quota configuration.`,
+		"",
+	},
+
+	"quotas-config-rate-limit-exempt-paths": {
+		`The paths, relative to the root namespace, that are not subject to rate limit quotas. A path ending in '/' exempts every path under it.`,
+		"",
+	},
+
+	"password-policy-list": {
+		`List the configured password policies.`,
+		"",
+	},
+
+	"password-policy": {
+		`Read, Modify, or Delete a password policy.`,
+		`
+Read the rules of an existing password policy, create or update the rules of
+a password policy, or delete a password policy. Password policies are written
+in HCL and define the length of generated passwords and the charsets they are
+generated from, along with the minimum number of characters required from each
+charset.
+		`,
+	},
+
+	"password-policy-name": {
+		`The name of the password policy.`,
+		"",
+	},
+
+	"password-policy-rules": {
+		`The rules of the password policy in HCL format. This may be base64-encoded.`,
+		"",
+	},
+
+	"password-policy-generate": {
+		`Generate a password from a password policy.`,
+		"",
+	},
+
 	"policy-name": {
 		`The name of the policy. Example: "ops"`,
 		"",
@@ -3845,6 +4749,17 @@ Enable a new audit backend or disable an existing backend.
 		on a given path.`,
 	},
 
+	"count-leases": {
+		"Count the leases of the mounts.",
+		`
+This path responds to the following HTTP methods.
+
+    GET /
+        Returns the number of leases, in total and for each mount of the
+        namespace and its child namespaces.
+		`,
+	},
+
 	"tidy_leases": {
 		`This endpoint performs cleanup tasks that can be run if certain error
 conditions have occurred.`,
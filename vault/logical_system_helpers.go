@@ -2,7 +2,6 @@ package vault
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -26,8 +25,8 @@ var (
 	addSentinelPolicyData     = func(map[string]interface{}, *Policy) {}
 	inputSentinelPolicyData   = func(*framework.FieldData, *Policy) *logical.Response { return nil }
 
-	controlGroupUnwrap = func(context.Context, *SystemBackend, string, bool) (string, error) {
-		return "", errors.New("control groups unavailable")
+	controlGroupUnwrap = func(ctx context.Context, b *SystemBackend, token string, _ bool) (string, error) {
+		return b.Core.controlGroupRun(ctx, token)
 	}
 
 	pathInternalUINamespacesRead = func(b *SystemBackend) framework.OperationFunc {
@@ -51,7 +50,17 @@ var (
 				return nil, logical.ErrPermissionDenied
 			}
 
-			return logical.ListResponse([]string{""}), nil
+			ns, err := namespace.FromContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			keys := []string{}
+			for _, child := range b.Core.ListNamespaces(ns, true) {
+				keys = append(keys, strings.TrimPrefix(child.Path, ns.Path))
+			}
+
+			return logical.ListResponse(keys), nil
 		}
 	}
 
@@ -1074,6 +1074,17 @@ func (b *SystemBackend) leasePaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["revoke-prefix"][1]),
 		},
 
+		{
+			Pattern: "leases/count$",
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.handleLeaseCount,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["count-leases"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["count-leases"][1]),
+		},
+
 		{
 			Pattern: "leases/tidy$",
 
@@ -1274,6 +1285,31 @@ func (b *SystemBackend) policyPaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["policy-list"][1]),
 		},
 
+		{
+			Pattern: "policy/lint$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"policy": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-lint-policy"][0]),
+				},
+				"name": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-lint-name"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePolicyLint,
+					Summary:  "Report likely mistakes in an ACL policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["policy-lint"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["policy-lint"][1]),
+		},
+
 		{
 			Pattern: "policy/(?P<name>.+)",
 
@@ -1355,6 +1391,356 @@ func (b *SystemBackend) policyPaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysHelp["policy"][0]),
 			HelpDescription: strings.TrimSpace(sysHelp["policy"][1]),
 		},
+
+		{
+			Pattern: "policy-explain$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"token": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-explain-token"][0]),
+				},
+				"policies": &framework.FieldSchema{
+					Type:        framework.TypeCommaStringSlice,
+					Description: strings.TrimSpace(sysHelp["policy-explain-policies"][0]),
+				},
+				"path": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-explain-path"][0]),
+				},
+				"operation": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Default:     "read",
+					Description: strings.TrimSpace(sysHelp["policy-explain-operation"][0]),
+				},
+				"parameters": &framework.FieldSchema{
+					Type:        framework.TypeMap,
+					Description: strings.TrimSpace(sysHelp["policy-explain-parameters"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePolicyExplain,
+					Summary:  "Explain whether a request is allowed by a token or set of policies.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["policy-explain"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["policy-explain"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) passwordPolicyPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "policies/password/?$",
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.handlePasswordPoliciesList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["password-policy-list"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["password-policy-list"][1]),
+		},
+
+		{
+			Pattern: "policies/password/(?P<name>[^/]+)/generate$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["password-policy-name"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handlePasswordPoliciesGenerate,
+					Summary:  "Generate a password from the named password policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["password-policy-generate"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["password-policy-generate"][1]),
+		},
+
+		{
+			Pattern: "policies/password/(?P<name>[^/]+)$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["password-policy-name"][0]),
+				},
+				"policy": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["password-policy-rules"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handlePasswordPoliciesRead,
+					Summary:  "Retrieve the rules of the named password policy.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePasswordPoliciesSet,
+					Summary:  "Add a new or update an existing password policy.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handlePasswordPoliciesDelete,
+					Summary:  "Delete the password policy with the given name.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["password-policy"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["password-policy"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) namespacePaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "namespaces/?$",
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.handleNamespacesList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["namespaces-list"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["namespaces-list"][1]),
+		},
+
+		{
+			Pattern: "namespaces/(?P<path>.+)$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["namespaces-path"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesRead,
+					Summary:  "Retrieve information about the namespace.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesCreate,
+					Summary:  "Create a namespace.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesDelete,
+					Summary:  "Delete a namespace.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["namespaces"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["namespaces"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) quotasPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "quotas/lease-count/?$",
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.handleLeaseCountQuotasList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["lease-count-quota-list"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["lease-count-quota-list"][1]),
+		},
+
+		{
+			Pattern: "quotas/lease-count/" + framework.GenericNameRegex("name"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["lease-count-quota-name"][0]),
+				},
+				"path": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["lease-count-quota-path"][0]),
+				},
+				"role": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["lease-count-quota-role"][0]),
+				},
+				"entity_id": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["lease-count-quota-entity-id"][0]),
+				},
+				"max_leases": &framework.FieldSchema{
+					Type:        framework.TypeInt,
+					Description: strings.TrimSpace(sysHelp["lease-count-quota-max-leases"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLeaseCountQuotasRead,
+					Summary:  "Retrieve a lease count quota and its current usage.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleLeaseCountQuotasUpdate,
+					Summary:  "Create or update a lease count quota.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleLeaseCountQuotasDelete,
+					Summary:  "Delete a lease count quota.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["lease-count-quota"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["lease-count-quota"][1]),
+		},
+
+		{
+			Pattern: "quotas/rate-limit/?$",
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.handleRateLimitQuotasList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rate-limit-quota-list"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rate-limit-quota-list"][1]),
+		},
+
+		{
+			Pattern: "quotas/rate-limit/" + framework.GenericNameRegex("name"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-name"][0]),
+				},
+				"path": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-path"][0]),
+				},
+				"role": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-role"][0]),
+				},
+				"entity_id": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-entity-id"][0]),
+				},
+				"source_cidrs": &framework.FieldSchema{
+					Type:        framework.TypeCommaStringSlice,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-source-cidrs"][0]),
+				},
+				"rate": &framework.FieldSchema{
+					Type:        framework.TypeInt,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-rate"][0]),
+				},
+				"interval": &framework.FieldSchema{
+					Type:        framework.TypeDurationSecond,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-interval"][0]),
+				},
+				"burst": &framework.FieldSchema{
+					Type:        framework.TypeInt,
+					Description: strings.TrimSpace(sysHelp["rate-limit-quota-burst"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleRateLimitQuotasRead,
+					Summary:  "Retrieve a rate limit quota.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleRateLimitQuotasUpdate,
+					Summary:  "Create or update a rate limit quota.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleRateLimitQuotasDelete,
+					Summary:  "Delete a rate limit quota.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rate-limit-quota"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rate-limit-quota"][1]),
+		},
+
+		{
+			Pattern: "quotas/config$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"rate_limit_exempt_paths": &framework.FieldSchema{
+					Type:        framework.TypeCommaStringSlice,
+					Description: strings.TrimSpace(sysHelp["quotas-config-rate-limit-exempt-paths"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleQuotasConfigRead,
+					Summary:  "Read the quota configuration.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleQuotasConfigUpdate,
+					Summary:  "Configure the quotas.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["quotas-config"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["quotas-config"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) controlGroupPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "control-group/authorize$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"accessor": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["control-group-accessor"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupAuthorize,
+					Summary:  "Authorize a control group request.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["control-group-authorize"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["control-group-authorize"][1]),
+		},
+
+		{
+			Pattern: "control-group/request$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"accessor": &framework.FieldSchema{
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["control-group-accessor"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupRequest,
+					Summary:  "Check the status of a control group request.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["control-group-request"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["control-group-request"][1]),
+		},
 	}
 }
 
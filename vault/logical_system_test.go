@@ -200,9 +200,10 @@ func TestSystemBackend_mounts(t *testing.T) {
 			"accessor":    resp.Data["identity/"].(map[string]interface{})["accessor"],
 			"uuid":        resp.Data["identity/"].(map[string]interface{})["uuid"],
 			"config": map[string]interface{}{
-				"default_lease_ttl": resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["default_lease_ttl"].(int64),
-				"max_lease_ttl":     resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["max_lease_ttl"].(int64),
-				"force_no_cache":    false,
+				"default_lease_ttl":           resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["default_lease_ttl"].(int64),
+				"max_lease_ttl":               resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["max_lease_ttl"].(int64),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []string{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -297,9 +298,10 @@ func TestSystemBackend_mount(t *testing.T) {
 			"accessor":    resp.Data["identity/"].(map[string]interface{})["accessor"],
 			"uuid":        resp.Data["identity/"].(map[string]interface{})["uuid"],
 			"config": map[string]interface{}{
-				"default_lease_ttl": resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["default_lease_ttl"].(int64),
-				"max_lease_ttl":     resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["max_lease_ttl"].(int64),
-				"force_no_cache":    false,
+				"default_lease_ttl":           resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["default_lease_ttl"].(int64),
+				"max_lease_ttl":               resp.Data["identity/"].(map[string]interface{})["config"].(map[string]interface{})["max_lease_ttl"].(int64),
+				"force_no_cache":              false,
+				"passthrough_request_headers": []string{"Authorization"},
 			},
 			"local":     false,
 			"seal_wrap": false,
@@ -900,6 +902,70 @@ func TestSystemBackend_leases_list(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_leases_count(t *testing.T) {
+	core, b, root := testCoreSystemBackend(t)
+
+	// Create a token and a key with a lease
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+	req.ClientToken = root
+	req.Data["ttl"] = "1h"
+	resp, err := core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "secret/foo")
+	req.Data["foo"] = "bar"
+	req.ClientToken = root
+	resp, err = core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	req = logical.TestRequest(t, logical.ReadOperation, "secret/foo")
+	req.ClientToken = root
+	resp, err = core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.Secret == nil || resp.Secret.LeaseID == "" {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "leases/count")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := map[string]interface{}{
+		"lease_count": 2,
+		"mounts": map[string]int{
+			"auth/token/": 1,
+			"secret/":     1,
+		},
+	}
+	if !reflect.DeepEqual(resp.Data, expected) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Revoked leases are no longer counted
+	req = logical.TestRequest(t, logical.UpdateOperation, "leases/revoke-prefix/secret/")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	req = logical.TestRequest(t, logical.ReadOperation, "leases/count")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected = map[string]interface{}{
+		"lease_count": 1,
+		"mounts": map[string]int{
+			"auth/token/": 1,
+		},
+	}
+	if !reflect.DeepEqual(resp.Data, expected) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+}
+
 func TestSystemBackend_renew(t *testing.T) {
 	core, b, root := testCoreSystemBackend(t)
 
@@ -1684,6 +1750,100 @@ func TestSystemBackend_policyCRUD(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_passwordPolicyCRUD(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+
+	// The router hands the system backend the system barrier view, which is
+	// also what the system view reads policies from
+	request := func(op logical.Operation, path string) *logical.Request {
+		req := logical.TestRequest(t, op, path)
+		req.Storage = c.systemBarrierView
+		return req
+	}
+
+	// Invalid policies are rejected
+	req := request(logical.UpdateOperation, "policies/password/test")
+	req.Data["policy"] = `length = 2 rule "charset" { charset = "abc" min-chars = 3 }`
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("expected error response, got: %#v", resp)
+	}
+
+	// Create the policy
+	rules := `
+length = 16
+rule "charset" {
+  charset = "abcdefghij"
+  min-chars = 1
+}
+rule "charset" {
+  charset = "0123456789"
+  min-chars = 4
+}`
+	req = request(logical.UpdateOperation, "policies/password/test")
+	req.Data["policy"] = base64.StdEncoding.EncodeToString([]byte(rules))
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v %#v", err, resp)
+	}
+
+	// Read the policy
+	req = request(logical.ReadOperation, "policies/password/test")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp == nil || resp.Data["policy"] != rules {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	// List the policies
+	req = request(logical.ListOperation, "policies/password")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Data["keys"], []string{"test"}) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Generate a password through the endpoint and the system view
+	req = request(logical.ReadOperation, "policies/password/test/generate")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	sysView := dynamicSystemView{core: c}
+	fromView, err := sysView.GeneratePasswordFromPolicy(namespace.RootContext(nil), "test")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for _, password := range []string{resp.Data["password"].(string), fromView} {
+		if len(password) != 16 || strings.Trim(password, "abcdefghij0123456789") != "" {
+			t.Fatalf("bad password: %q", password)
+		}
+	}
+
+	// Delete the policy
+	req = request(logical.DeleteOperation, "policies/password/test")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	req = request(logical.ReadOperation, "policies/password/test/generate")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("expected invalid request, got: %v %#v", err, resp)
+	}
+	if _, err := sysView.GeneratePasswordFromPolicy(namespace.RootContext(nil), "test"); err == nil {
+		t.Fatal("expected error for deleted policy")
+	}
+}
+
 func TestSystemBackend_enableAudit(t *testing.T) {
 	c, b, _ := testCoreSystemBackend(t)
 	c.auditBackends["noop"] = func(ctx context.Context, config *audit.BackendConfig) (audit.Backend, error) {
@@ -2329,9 +2489,10 @@ func TestSystemBackend_InternalUIMounts(t *testing.T) {
 				"accessor":    resp.Data["secret"].(map[string]interface{})["identity/"].(map[string]interface{})["accessor"],
 				"uuid":        resp.Data["secret"].(map[string]interface{})["identity/"].(map[string]interface{})["uuid"],
 				"config": map[string]interface{}{
-					"default_lease_ttl": resp.Data["secret"].(map[string]interface{})["identity/"].(map[string]interface{})["config"].(map[string]interface{})["default_lease_ttl"].(int64),
-					"max_lease_ttl":     resp.Data["secret"].(map[string]interface{})["identity/"].(map[string]interface{})["config"].(map[string]interface{})["max_lease_ttl"].(int64),
-					"force_no_cache":    false,
+					"default_lease_ttl":           resp.Data["secret"].(map[string]interface{})["identity/"].(map[string]interface{})["config"].(map[string]interface{})["default_lease_ttl"].(int64),
+					"max_lease_ttl":               resp.Data["secret"].(map[string]interface{})["identity/"].(map[string]interface{})["config"].(map[string]interface{})["max_lease_ttl"].(int64),
+					"force_no_cache":              false,
+					"passthrough_request_headers": []string{"Authorization"},
 				},
 				"local":     false,
 				"seal_wrap": false,
@@ -885,10 +885,19 @@ func (c *Core) loadMounts(ctx context.Context) error {
 		needPersist = true
 	}
 
+	// Upgrade identity mounts to pass through the Authorization header, which
+	// clients of the OIDC provider authenticate with
+	for _, entry := range c.mounts.Entries {
+		if entry.Type == "identity" && !strutil.StrListContains(entry.Config.PassthroughRequestHeaders, "Authorization") {
+			entry.Config.PassthroughRequestHeaders = append(entry.Config.PassthroughRequestHeaders, "Authorization")
+			needPersist = true
+		}
+	}
+
 	for _, requiredMount := range c.requiredMountTable().Entries {
 		foundRequired := false
 		for _, coreMount := range c.mounts.Entries {
-			if coreMount.Type == requiredMount.Type {
+			if coreMount.Type == requiredMount.Type && (coreMount.NamespaceID == "" || coreMount.NamespaceID == namespace.RootNamespaceID) {
 				foundRequired = true
 				coreMount.Config = requiredMount.Config
 				break
@@ -1178,6 +1187,14 @@ func (c *Core) newLogicalBackend(ctx context.Context, entry *MountEntry, sysView
 		t = alias
 	}
 
+	// Namespaces share the identity store of the root namespace
+	if t == identityMountType && entry.NamespaceID != "" && entry.NamespaceID != namespace.RootNamespaceID {
+		if c.identityStore == nil {
+			return nil, errors.New("identity store is not set up")
+		}
+		return &sharedBackend{c.identityStore}, nil
+	}
+
 	f, ok := c.logicalBackends[t]
 	if !ok {
 		f = plugin.Factory
@@ -1346,6 +1363,11 @@ func (c *Core) requiredMountTable() *MountTable {
 		UUID:             identityUUID,
 		Accessor:         identityAccessor,
 		BackendAwareUUID: identityBackendUUID,
+		Config: MountConfig{
+			// OIDC provider clients authenticate and present access tokens
+			// using the Authorization header
+			PassthroughRequestHeaders: []string{"Authorization"},
+		},
 	}
 
 	table.Entries = append(table.Entries, cubbyholeMount)
@@ -1385,15 +1407,23 @@ func (c *Core) singletonMountTables() (mounts, auth *MountTable) {
 }
 
 func (c *Core) setCoreBackend(entry *MountEntry, backend logical.Backend, view *BarrierView) {
+	if entry.Type == cubbyholeMountType {
+		ch := backend.(*CubbyholeBackend)
+		ch.saltUUID = entry.UUID
+		ch.storageView = view
+	}
+
+	// Only the backends of the root namespace are used by the core
+	if entry.NamespaceID != "" && entry.NamespaceID != namespace.RootNamespaceID {
+		return
+	}
+
 	switch entry.Type {
 	case systemMountType:
 		c.systemBackend = backend.(*SystemBackend)
 		c.systemBarrierView = view
 	case cubbyholeMountType:
-		ch := backend.(*CubbyholeBackend)
-		ch.saltUUID = entry.UUID
-		ch.storageView = view
-		c.cubbyholeBackend = ch
+		c.cubbyholeBackend = backend.(*CubbyholeBackend)
 	case identityMountType:
 		c.identityStore = backend.(*IdentityStore)
 	}
@@ -673,6 +673,62 @@ func testCore_MountTable_UpgradeToTyped_Common(
 	}
 }
 
+func TestCore_MountTable_UpgradeIdentityPassthroughHeaders(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	// Create a version of the table from before the identity mount passed
+	// through the Authorization header
+	for _, entry := range c.mounts.Entries {
+		if entry.Type == "identity" {
+			entry.Config.PassthroughRequestHeaders = nil
+		}
+	}
+	raw, err := json.Marshal(c.mounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := &logical.StorageEntry{
+		Key:   coreMountConfigPath,
+		Value: raw,
+	}
+	if err := c.barrier.Put(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// It should load successfully and be upgraded and persisted
+	if err := c.loadMounts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	checkHeaders := func(mt *MountTable) {
+		t.Helper()
+		var found bool
+		for _, entry := range mt.Entries {
+			if entry.Type != "identity" {
+				continue
+			}
+			found = true
+			if !reflect.DeepEqual(entry.Config.PassthroughRequestHeaders, []string{"Authorization"}) {
+				t.Fatalf("bad: passthrough request headers: %v", entry.Config.PassthroughRequestHeaders)
+			}
+		}
+		if !found {
+			t.Fatal("identity mount not found")
+		}
+	}
+	checkHeaders(c.mounts)
+
+	entry, err = c.barrier.Get(context.Background(), coreMountConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	persisted := new(MountTable)
+	if err := jsonutil.DecodeJSON(entry.Value, persisted); err != nil {
+		t.Fatal(err)
+	}
+	checkHeaders(persisted)
+}
+
 func verifyDefaultTable(t *testing.T, table *MountTable, expected int) {
 	if len(table.Entries) != expected {
 		t.Fatalf("bad: %v", table.Entries)
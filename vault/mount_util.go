@@ -4,7 +4,9 @@ package vault
 
 import (
 	"context"
+	"fmt"
 	"path"
+	"strings"
 
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -21,23 +23,39 @@ func addLicenseCallback(*Core, logical.Backend)                               {}
 
 // ViewPath returns storage prefix for the view
 func (e *MountEntry) ViewPath() string {
+	// The storage of a namespace mirrors the layout of the root namespace
+	var prefix string
+	if e.NamespaceID != "" && e.NamespaceID != namespace.RootNamespaceID {
+		prefix = namespaceBarrierPrefix + e.NamespaceID + "/"
+	}
+
 	switch e.Type {
 	case systemMountType:
-		return systemBarrierPrefix
+		return prefix + systemBarrierPrefix
 	case "token":
-		return path.Join(systemBarrierPrefix, tokenSubPath) + "/"
+		return prefix + path.Join(systemBarrierPrefix, tokenSubPath) + "/"
 	}
 
 	switch e.Table {
 	case mountTableType:
-		return backendBarrierPrefix + e.UUID + "/"
+		return prefix + backendBarrierPrefix + e.UUID + "/"
 	case credentialTableType:
-		return credentialBarrierPrefix + e.UUID + "/"
+		return prefix + credentialBarrierPrefix + e.UUID + "/"
 	case auditTableType:
-		return auditBarrierPrefix + e.UUID + "/"
+		return prefix + auditBarrierPrefix + e.UUID + "/"
 	}
 
 	panic("invalid mount entry")
 }
 
-func verifyNamespace(*Core, *namespace.Namespace, *MountEntry) error { return nil }
+// verifyNamespace ensures a mount in the given namespace doesn't shadow the
+// path of one of its child namespaces
+func verifyNamespace(c *Core, ns *namespace.Namespace, entry *MountEntry) error {
+	for _, child := range c.ListNamespaces(ns, false) {
+		childPath := strings.TrimPrefix(child.Path, ns.Path)
+		if strings.HasPrefix(entry.Path, childPath) || strings.HasPrefix(childPath, entry.Path) {
+			return logical.CodedError(409, fmt.Sprintf("path is already in use by namespace %s", child.Path))
+		}
+	}
+	return nil
+}
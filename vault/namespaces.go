@@ -2,17 +2,526 @@ package vault
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 
+	radix "github.com/armon/go-radix"
+	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/pathmanager"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// coreNamespacesPath is the storage prefix of the namespace entries
+	coreNamespacesPath = "core/namespaces/"
+
+	// namespaceBarrierPrefix is the prefix of the storage of the namespaces
+	// other than root. The storage of each namespace mirrors the layout of
+	// the root namespace under namespaceBarrierPrefix + ID + "/".
+	namespaceBarrierPrefix = "namespaces/"
+
+	// namespaceIDLength is the length of the generated namespace IDs
+	namespaceIDLength = 5
 )
 
 var (
 	NamespaceByID func(context.Context, string, *Core) (*namespace.Namespace, error) = namespaceByID
+
+	// namespaceNameRegex matches the allowed names of a namespace, which
+	// make up a single segment of its path
+	namespaceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	// reservedNamespaceNames can't be used as namespace names since they
+	// would shadow the paths of the built-in mounts
+	reservedNamespaceNames = []string{
+		namespace.RootNamespaceID,
+		"sys",
+		"audit",
+		"auth",
+		"cubbyhole",
+		"identity",
+	}
+
+	// namespaceRestrictedPaths are the paths that affect the whole cluster
+	// and are therefore only available in the root namespace
+	namespaceRestrictedPaths = pathmanager.New()
 )
 
+func init() {
+	namespaceRestrictedPaths.AddPaths([]string{
+		"sys/audit",
+		"sys/config/",
+		"sys/generate-root",
+		"sys/health",
+		"sys/host-info",
+		"sys/init",
+		"sys/internal/counters/",
+		"sys/key-status",
+		"sys/leader",
+		"sys/license",
+		"sys/metrics",
+		"sys/plugins/",
+		"sys/quotas/",
+		"sys/raw",
+		"sys/rekey",
+		"sys/replication/",
+		"sys/rotate",
+		"sys/seal",
+		"sys/step-down",
+		"sys/storage/",
+		"sys/unseal",
+	})
+}
+
 func namespaceByID(ctx context.Context, nsID string, c *Core) (*namespace.Namespace, error) {
 	if nsID == namespace.RootNamespaceID {
 		return namespace.RootNamespace, nil
 	}
-	return nil, namespace.ErrNoNamespace
+	if c.namespaceStore == nil {
+		return nil, nil
+	}
+	return c.namespaceStore.byID(nsID), nil
+}
+
+// NamespaceStore keeps the namespaces of the cluster, indexed by ID and by
+// path. The root namespace is implicit and never stored.
+type NamespaceStore struct {
+	view *BarrierView
+
+	// lock protects the indexes
+	lock     sync.RWMutex
+	ids      map[string]*namespace.Namespace
+	paths    *radix.Tree
+	children map[string][]string
+
+	// modifyLock serializes the creation and deletion of namespaces, which
+	// span several mount, policy and token operations
+	modifyLock sync.Mutex
+}
+
+// setupNamespaceStore loads the namespaces from storage when the vault is
+// being unsealed. It must happen before the mount tables are loaded, since
+// mount entries are resolved to their namespace.
+func (c *Core) setupNamespaceStore(ctx context.Context) error {
+	store := &NamespaceStore{
+		view:     NewBarrierView(c.barrier, coreNamespacesPath),
+		ids:      make(map[string]*namespace.Namespace),
+		paths:    radix.New(),
+		children: make(map[string][]string),
+	}
+
+	keys, err := logical.CollectKeys(ctx, store.view)
+	if err != nil {
+		return errwrap.Wrapf("failed to list namespaces: {{err}}", err)
+	}
+	for _, key := range keys {
+		entry, err := store.view.Get(ctx, key)
+		if err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to read namespace %q: {{err}}", key), err)
+		}
+		if entry == nil {
+			continue
+		}
+		ns := new(namespace.Namespace)
+		if err := jsonutil.DecodeJSON(entry.Value, ns); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to decode namespace %q: {{err}}", key), err)
+		}
+		store.insert(ns)
+	}
+
+	c.namespaceStore = store
+	if len(keys) > 0 {
+		c.logger.Info("namespaces loaded", "count", len(keys))
+	}
+	return nil
+}
+
+// teardownNamespaceStore is used to reverse setupNamespaceStore when the
+// vault is being sealed.
+func (c *Core) teardownNamespaceStore() {
+	c.namespaceStore = nil
+}
+
+func (s *NamespaceStore) insert(ns *namespace.Namespace) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.ids[ns.ID] = ns
+	s.paths.Insert(ns.Path, ns)
+	parent := parentNamespacePath(ns.Path)
+	s.children[parent] = strutil.AppendIfMissing(s.children[parent], ns.ID)
+}
+
+func (s *NamespaceStore) remove(ns *namespace.Namespace) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.ids, ns.ID)
+	delete(s.children, ns.Path)
+	s.paths.Delete(ns.Path)
+	parent := parentNamespacePath(ns.Path)
+	s.children[parent] = strutil.StrListDelete(s.children[parent], ns.ID)
+}
+
+// parentNamespacePath returns the path of the parent of the namespace at the
+// given path
+func parentNamespacePath(nsPath string) string {
+	trimmed := strings.TrimSuffix(nsPath, "/")
+	idx := strings.LastIndex(trimmed, "/")
+	if idx == -1 {
+		return ""
+	}
+	return trimmed[:idx+1]
 }
+
+func (s *NamespaceStore) byID(id string) *namespace.Namespace {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.ids[id]
+}
+
+// byPath returns the namespace at exactly the given path
+func (s *NamespaceStore) byPath(nsPath string) *namespace.Namespace {
+	if nsPath == "" {
+		return namespace.RootNamespace
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	raw, ok := s.paths.Get(namespace.Canonicalize(nsPath))
+	if !ok {
+		return nil
+	}
+	return raw.(*namespace.Namespace)
+}
+
+// longestPrefix returns the deepest namespace containing the given path
+func (s *NamespaceStore) longestPrefix(path string) *namespace.Namespace {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	_, raw, ok := s.paths.LongestPrefix(path)
+	if !ok {
+		return namespace.RootNamespace
+	}
+	return raw.(*namespace.Namespace)
+}
+
+// childrenOf returns the direct children of the given namespace sorted by
+// path
+func (s *NamespaceStore) childrenOf(ns *namespace.Namespace) []*namespace.Namespace {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var ret []*namespace.Namespace
+	for _, id := range s.children[ns.Path] {
+		ret = append(ret, s.ids[id])
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Path < ret[j].Path
+	})
+	return ret
+}
+
+// descendantsOf returns all the namespaces nested in the given namespace
+// sorted by path
+func (s *NamespaceStore) descendantsOf(ns *namespace.Namespace) []*namespace.Namespace {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var ret []*namespace.Namespace
+	s.paths.WalkPrefix(ns.Path, func(_ string, raw interface{}) bool {
+		if child := raw.(*namespace.Namespace); child.ID != ns.ID {
+			ret = append(ret, child)
+		}
+		return false
+	})
+	return ret
+}
+
+// all returns every namespace but root
+func (s *NamespaceStore) all() []*namespace.Namespace {
+	return s.descendantsOf(namespace.RootNamespace)
+}
+
+// namespaceView returns a view of the given storage prefix within the
+// storage of the given namespace
+func (c *Core) namespaceView(ns *namespace.Namespace, prefix string) *BarrierView {
+	if ns.ID == namespace.RootNamespaceID {
+		return NewBarrierView(c.barrier, prefix)
+	}
+	return NewBarrierView(c.barrier, namespaceBarrierPrefix+ns.ID+"/"+prefix)
+}
+
+// NamespaceByPath returns the deepest namespace containing the given API
+// path, relative to the root namespace. The boolean is false when namespaces
+// can't be resolved, such as when the vault is sealed or in standby.
+func (c *Core) NamespaceByPath(path string) (*namespace.Namespace, bool) {
+	store := c.namespaceStore
+	if store == nil {
+		return nil, false
+	}
+	return store.longestPrefix(path), true
+}
+
+// ListNamespaces returns the namespaces nested in the given namespace. Unless
+// includeNested is set, only the direct children are returned.
+func (c *Core) ListNamespaces(parent *namespace.Namespace, includeNested bool) []*namespace.Namespace {
+	store := c.namespaceStore
+	if store == nil {
+		return nil
+	}
+	if includeNested {
+		return store.descendantsOf(parent)
+	}
+	return store.childrenOf(parent)
+}
+
+// lookupNamespace returns the namespace at the given path, relative to the
+// namespace of the context, or nil if it doesn't exist
+func (c *Core) lookupNamespace(ctx context.Context, nsPath string) (*namespace.Namespace, error) {
+	store := c.namespaceStore
+	if store == nil {
+		return nil, errors.New("namespace store is not set up")
+	}
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nsPath = namespace.Canonicalize(nsPath)
+	if nsPath == "" {
+		return nil, nil
+	}
+	return store.byPath(requestNS.Path + nsPath), nil
+}
+
+// createNamespace creates the namespace at the given path, relative to the
+// namespace of the context, along with its system, cubbyhole, identity and
+// token mounts and its default policies. Creating an existing namespace
+// returns it unchanged.
+func (c *Core) createNamespace(ctx context.Context, nsPath string) (*namespace.Namespace, error) {
+	store := c.namespaceStore
+	if store == nil {
+		return nil, errors.New("namespace store is not set up")
+	}
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nsPath = namespace.Canonicalize(nsPath)
+	if nsPath == "" {
+		return nil, logical.CodedError(400, "missing namespace path")
+	}
+	fullPath := requestNS.Path + nsPath
+
+	store.modifyLock.Lock()
+	defer store.modifyLock.Unlock()
+
+	if existing := store.byPath(fullPath); existing != nil {
+		return existing, nil
+	}
+
+	// Nested paths are created in the namespace holding the last segment,
+	// which must already exist
+	parentPath := parentNamespacePath(fullPath)
+	parent := store.byPath(parentPath)
+	if parent == nil {
+		return nil, logical.CodedError(400, fmt.Sprintf("parent namespace %q does not exist", parentPath))
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(fullPath, parentPath), "/")
+	if !namespaceNameRegex.MatchString(name) {
+		return nil, logical.CodedError(400, fmt.Sprintf("invalid namespace name %q", name))
+	}
+	if strutil.StrListContains(reservedNamespaceNames, strings.ToLower(name)) {
+		return nil, logical.CodedError(400, fmt.Sprintf("namespace name %q is reserved", name))
+	}
+
+	// The namespace path must not shadow a mount of its parent
+	parentCtx := namespace.ContextWithNamespace(ctx, parent)
+	if match := c.router.MountConflict(parentCtx, name+"/"); match != "" {
+		return nil, logical.CodedError(409, fmt.Sprintf("existing mount at %s", match))
+	}
+
+	var id string
+	for id == "" || store.byID(id) != nil {
+		id, err = base62.Random(namespaceIDLength)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ns := &namespace.Namespace{
+		ID:   id,
+		Path: fullPath,
+	}
+
+	entry, err := logical.StorageEntryJSON(ns.ID, ns)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.view.Put(ctx, entry); err != nil {
+		return nil, errwrap.Wrapf("failed to persist namespace: {{err}}", err)
+	}
+	store.insert(ns)
+
+	if err := c.setupNamespace(namespace.ContextWithNamespace(ctx, ns)); err != nil {
+		c.logger.Error("failed to set up namespace, removing it", "namespace", ns.Path, "error", err)
+		if delErr := c.deleteNamespaceInternal(ctx, ns); delErr != nil {
+			c.logger.Error("failed to remove namespace", "namespace", ns.Path, "error", delErr)
+		}
+		return nil, err
+	}
+
+	if c.logger.IsInfo() {
+		c.logger.Info("namespace created", "namespace", ns.Path, "id", ns.ID)
+	}
+	return ns, nil
+}
+
+// setupNamespace mounts the singleton backends and loads the default
+// policies of a newly created namespace
+func (c *Core) setupNamespace(ctx context.Context) error {
+	for _, entry := range c.requiredMountTable().Entries {
+		if err := c.mountInternal(ctx, entry, MountTableUpdateStorage); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to mount %q: {{err}}", entry.Path), err)
+		}
+	}
+	for _, entry := range c.defaultAuthTable().Entries {
+		entry.Config.TokenType = logical.TokenTypeDefaultService
+		if err := c.enableCredentialInternal(ctx, entry, MountTableUpdateStorage); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to enable %q: {{err}}", entry.Path), err)
+		}
+	}
+
+	for name, policy := range map[string]string{
+		defaultPolicyName:          defaultPolicy,
+		responseWrappingPolicyName: responseWrappingPolicy,
+		controlGroupPolicyName:     controlGroupPolicy,
+	} {
+		if err := c.policyStore.loadACLPolicyInternal(ctx, name, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteNamespace deletes the namespace at the given path, relative to the
+// namespace of the context. All the leases and tokens of the namespace are
+// revoked, its mounts are removed and its storage is cleared.
+func (c *Core) deleteNamespace(ctx context.Context, nsPath string) error {
+	store := c.namespaceStore
+	if store == nil {
+		return errors.New("namespace store is not set up")
+	}
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	nsPath = namespace.Canonicalize(nsPath)
+	if nsPath == "" {
+		return logical.CodedError(400, "missing namespace path")
+	}
+
+	store.modifyLock.Lock()
+	defer store.modifyLock.Unlock()
+
+	ns := store.byPath(requestNS.Path + nsPath)
+	if ns == nil {
+		return nil
+	}
+	if children := store.childrenOf(ns); len(children) > 0 {
+		return logical.CodedError(400, fmt.Sprintf("namespace %q has child namespaces, which must be deleted first", ns.Path))
+	}
+
+	if err := c.deleteNamespaceInternal(ctx, ns); err != nil {
+		return err
+	}
+
+	if c.logger.IsInfo() {
+		c.logger.Info("namespace deleted", "namespace", ns.Path, "id", ns.ID)
+	}
+	return nil
+}
+
+func (c *Core) deleteNamespaceInternal(ctx context.Context, ns *namespace.Namespace) error {
+	nsCtx := namespace.ContextWithNamespace(ctx, ns)
+
+	var mounts, auths []string
+	c.mountsLock.RLock()
+	for _, entry := range c.mounts.Entries {
+		if entry.NamespaceID == ns.ID {
+			mounts = append(mounts, entry.Path)
+		}
+	}
+	c.mountsLock.RUnlock()
+	c.authLock.RLock()
+	for _, entry := range c.auth.Entries {
+		if entry.NamespaceID == ns.ID {
+			auths = append(auths, entry.Path)
+		}
+	}
+	c.authLock.RUnlock()
+
+	// Auth methods go first so that the tokens, and with them the leases of
+	// the namespace, are revoked while the secrets engines and the cubbyhole
+	// are still mounted. The token store is disabled last among them as the
+	// other methods revoke their tokens through it, and the system backend
+	// last overall since its storage holds the policies and leases.
+	sort.SliceStable(auths, func(i, j int) bool {
+		return auths[i] != "token/" && auths[j] == "token/"
+	})
+	for _, path := range auths {
+		if err := c.disableCredentialInternal(nsCtx, path, MountTableUpdateStorage); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to disable auth method %q: {{err}}", path), err)
+		}
+	}
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return mounts[i] != systemMountPath && mounts[j] == systemMountPath
+	})
+	for _, path := range mounts {
+		if err := c.unmountInternal(nsCtx, path, MountTableUpdateStorage); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to unmount %q: {{err}}", path), err)
+		}
+	}
+
+	if c.identityStore != nil {
+		if err := c.identityStore.deleteNamespaceArtifacts(nsCtx); err != nil {
+			return errwrap.Wrapf("failed to delete identity artifacts: {{err}}", err)
+		}
+	}
+	if c.policyStore != nil {
+		c.policyStore.removeNamespace(ns)
+	}
+	if c.tokenStore != nil {
+		c.tokenStore.removeNamespaceSalt(ns)
+	}
+
+	if err := logical.ClearView(ctx, c.namespaceView(ns, "")); err != nil {
+		return errwrap.Wrapf("failed to clear namespace storage: {{err}}", err)
+	}
+
+	if err := c.namespaceStore.view.Delete(ctx, ns.ID); err != nil {
+		return errwrap.Wrapf("failed to delete namespace: {{err}}", err)
+	}
+	c.namespaceStore.remove(ns)
+	return nil
+}
+
+// sharedBackend exposes a backend owned by the root namespace, such as the
+// token or identity store, in another namespace. The backend scopes its data
+// with the namespace of each request, and is only cleaned up when unmounted
+// from the root namespace.
+type sharedBackend struct {
+	logical.Backend
+}
+
+func (b *sharedBackend) Cleanup(context.Context) {}
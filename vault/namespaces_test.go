@@ -0,0 +1,211 @@
+package vault
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func testNamespaceRequest(t *testing.T, c *Core, ns *namespace.Namespace, req *logical.Request) *logical.Response {
+	t.Helper()
+	resp, err := c.HandleRequest(namespace.ContextWithNamespace(context.Background(), ns), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: path: %s, resp: %#v, err: %v", req.Path, resp, err)
+	}
+	return resp
+}
+
+func TestNamespaces_CreateListDelete(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/namespaces/ns1")
+	req.ClientToken = root
+	resp := testNamespaceRequest(t, c, namespace.RootNamespace, req)
+	if resp.Data["path"] != "ns1/" || resp.Data["id"] == "" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	ns1, ok := c.NamespaceByPath("ns1/foo")
+	if !ok || ns1.Path != "ns1/" {
+		t.Fatalf("bad: %#v", ns1)
+	}
+
+	// Nested namespaces are created relative to the request namespace
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/namespaces/team")
+	req.ClientToken = root
+	resp = testNamespaceRequest(t, c, ns1, req)
+	if resp.Data["path"] != "ns1/team/" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	req = logical.TestRequest(t, logical.ListOperation, "sys/namespaces")
+	req.ClientToken = root
+	resp = testNamespaceRequest(t, c, namespace.RootNamespace, req)
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != "ns1/" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Parents can't be deleted before their children
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/namespaces/ns1")
+	req.ClientToken = root
+	if _, err := c.HandleRequest(namespace.RootContext(nil), req); err == nil {
+		t.Fatal("expected error deleting a namespace with children")
+	}
+
+	for _, path := range []string{"ns1/team", "ns1"} {
+		req = logical.TestRequest(t, logical.DeleteOperation, "sys/namespaces/"+path)
+		req.ClientToken = root
+		testNamespaceRequest(t, c, namespace.RootNamespace, req)
+	}
+
+	if len(c.ListNamespaces(namespace.RootNamespace, true)) != 0 {
+		t.Fatal("expected no namespaces")
+	}
+	keys, err := logical.CollectKeys(context.Background(), c.namespaceView(ns1, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("expected namespace storage to be cleared, got %v", keys)
+	}
+	for _, entry := range c.mounts.Entries {
+		if entry.NamespaceID == ns1.ID {
+			t.Fatalf("mount left behind: %#v", entry)
+		}
+	}
+}
+
+func TestNamespaces_InvalidPaths(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	for _, path := range []string{"sys", "a/b", "bad name", "secret"} {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/namespaces/"+path)
+		req.ClientToken = root
+		if _, err := c.HandleRequest(namespace.RootContext(nil), req); err == nil {
+			t.Fatalf("expected error creating %q", path)
+		}
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/namespaces/ns1")
+	req.ClientToken = root
+	testNamespaceRequest(t, c, namespace.RootNamespace, req)
+
+	// Mounts can't shadow a namespace
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/ns1")
+	req.ClientToken = root
+	req.Data["type"] = "kv"
+	if _, err := c.HandleRequest(namespace.RootContext(nil), req); err == nil {
+		t.Fatal("expected error mounting over a namespace")
+	}
+}
+
+func TestNamespaces_Isolation(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/namespaces/ns1")
+	req.ClientToken = root
+	testNamespaceRequest(t, c, namespace.RootNamespace, req)
+	ns1, _ := c.NamespaceByPath("ns1/")
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/kv")
+	req.ClientToken = root
+	req.Data["type"] = "kv"
+	testNamespaceRequest(t, c, ns1, req)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "kv/foo")
+	req.ClientToken = root
+	req.Data["value"] = "bar"
+	testNamespaceRequest(t, c, ns1, req)
+
+	// The mount is invisible from the root namespace
+	req = logical.TestRequest(t, logical.ReadOperation, "kv/foo")
+	req.ClientToken = root
+	if _, err := c.HandleRequest(namespace.RootContext(nil), req); err == nil {
+		t.Fatal("expected error reading the namespace mount from root")
+	}
+
+	// Policies are scoped to the namespace
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/policy/reader")
+	req.ClientToken = root
+	req.Data["policy"] = `path "kv/*" { capabilities = ["read"] }`
+	testNamespaceRequest(t, c, ns1, req)
+
+	policy, err := c.policyStore.GetPolicy(namespace.RootContext(nil), "reader", PolicyTypeACL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy != nil {
+		t.Fatal("expected namespace policy to be absent from root")
+	}
+
+	// Tokens created in the namespace carry its ID and its policies
+	req = logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+	req.ClientToken = root
+	req.Data["policies"] = []string{"reader"}
+	resp := testNamespaceRequest(t, c, ns1, req)
+	token := resp.Auth.ClientToken
+	if !strings.HasSuffix(token, "."+ns1.ID) {
+		t.Fatalf("expected token to be scoped to the namespace, got %q", token)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "kv/foo")
+	req.ClientToken = token
+	resp = testNamespaceRequest(t, c, ns1, req)
+	if resp.Data["value"] != "bar" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Root-only system paths are rejected within namespaces
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/audit")
+	req.ClientToken = root
+	if _, err := c.HandleRequest(namespace.ContextWithNamespace(context.Background(), ns1), req); err == nil {
+		t.Fatal("expected error reading a root-only path in a namespace")
+	}
+
+	// Deleting the namespace revokes its tokens
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/namespaces/ns1")
+	req.ClientToken = root
+	testNamespaceRequest(t, c, namespace.RootNamespace, req)
+
+	te, err := c.tokenStore.Lookup(namespace.RootContext(nil), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te != nil {
+		t.Fatalf("expected token to be revoked, got %#v", te)
+	}
+}
+
+func TestNamespaces_Persistence(t *testing.T) {
+	c, keys, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/namespaces/ns1")
+	req.ClientToken = root
+	testNamespaceRequest(t, c, namespace.RootNamespace, req)
+	ns1, _ := c.NamespaceByPath("ns1/")
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/kv")
+	req.ClientToken = root
+	req.Data["type"] = "kv"
+	testNamespaceRequest(t, c, ns1, req)
+
+	if err := c.Seal(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if _, err := TestCoreUnseal(c, TestKeyCopy(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ns, _ := c.NamespaceByPath("ns1/")
+	if ns.ID != ns1.ID {
+		t.Fatalf("expected namespace to be restored, got %#v", ns)
+	}
+	if match := c.router.MatchingMount(namespace.ContextWithNamespace(context.Background(), ns), "kv/foo"); match != "ns1/kv/" {
+		t.Fatalf("expected namespace mount to be restored, got %q", match)
+	}
+}
@@ -128,6 +128,7 @@ type PathRules struct {
 	RequiredParametersHCL []string                 `hcl:"required_parameters"`
 	MFAMethodsHCL         []string                 `hcl:"mfa_methods"`
 	ControlGroupHCL       *ControlGroupHCL         `hcl:"control_group"`
+	ConditionsHCL         []*PolicyConditionHCL    `hcl:"-"`
 }
 
 type ControlGroupHCL struct {
@@ -160,6 +161,12 @@ type ACLPermissions struct {
 	RequiredParameters []string
 	MFAMethods         []string
 	ControlGroup       *ControlGroup
+
+	// Conditions restrict the capabilities of the path to the requests
+	// matching all of them. Once compiled into an ACL, conditional
+	// capabilities are held in ConditionalGrants instead.
+	Conditions        []*PolicyCondition
+	ConditionalGrants []*conditionalGrant
 }
 
 func (p *ACLPermissions) Clone() (*ACLPermissions, error) {
@@ -216,6 +223,13 @@ func (p *ACLPermissions) Clone() (*ACLPermissions, error) {
 		ret.ControlGroup = clonedControlGroup.(*ControlGroup)
 	}
 
+	// Conditions are never modified once parsed and hold time locations,
+	// which can't be deep copied, so they are shared
+	ret.Conditions = p.Conditions
+	if len(p.ConditionalGrants) > 0 {
+		ret.ConditionalGrants = append([]*conditionalGrant(nil), p.ConditionalGrants...)
+	}
+
 	return ret, nil
 }
 
@@ -317,6 +331,7 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 			"max_wrapping_ttl",
 			"mfa_methods",
 			"control_group",
+			"condition",
 		}
 		if err := hclutil.CheckHCLKeys(item.Val, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("path %q:", key))
@@ -333,6 +348,12 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 			return multierror.Prefix(err, fmt.Sprintf("path %q:", key))
 		}
 
+		conditions, err := decodePolicyConditions(item.Val)
+		if err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("path %q:", key))
+		}
+		pc.ConditionsHCL = conditions
+
 		// Strip a leading '/' as paths in Vault start after the / in the API path
 		if len(pc.Path) > 0 && pc.Path[0] == '/' {
 			pc.Path = pc.Path[1:]
@@ -375,6 +396,14 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 			}
 		}
 
+		if len(pc.ConditionsHCL) > 0 {
+			conditions, err := parsePolicyConditions(pc.ConditionsHCL)
+			if err != nil {
+				return errwrap.Wrapf(fmt.Sprintf("path %q: {{err}}", key), err)
+			}
+			pc.Permissions.Conditions = conditions
+		}
+
 		// Initialize the map
 		pc.Permissions.CapabilitiesBitmap = 0
 		for _, cap := range pc.Capabilities {
@@ -0,0 +1,418 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	multierror "github.com/hashicorp/go-multierror"
+	sockaddr "github.com/hashicorp/go-sockaddr"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/sdk/helper/hclutil"
+	"github.com/hashicorp/vault/sdk/helper/parseutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// PolicyConditionHCL is the HCL representation of a condition clause on a
+// path stanza
+type PolicyConditionHCL struct {
+	TimeWindows    []*TimeWindowHCL  `hcl:"-"`
+	SourceCIDRs    []string          `hcl:"source_cidrs"`
+	MFAValidated   *bool             `hcl:"mfa_validated"`
+	EntityMetadata map[string]string `hcl:"entity_metadata"`
+	GroupMetadata  map[string]string `hcl:"group_metadata"`
+}
+
+type TimeWindowHCL struct {
+	Days     []string `hcl:"days"`
+	Start    string   `hcl:"start"`
+	End      string   `hcl:"end"`
+	Timezone string   `hcl:"timezone"`
+}
+
+// PolicyCondition restricts the capabilities granted by a path stanza to the
+// requests matching all of its predicates
+type PolicyCondition struct {
+	TimeWindows    []*TimeWindow
+	SourceCIDRs    []*sockaddr.SockAddrMarshaler
+	MFAValidated   *bool
+	EntityMetadata map[string]string
+	GroupMetadata  map[string]string
+}
+
+// TimeWindow is a range of the day, in minutes, on the given days of the week
+// in the given location. Windows whose end precedes their start span
+// midnight.
+type TimeWindow struct {
+	Days     []time.Weekday
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+// conditionalGrant holds the capabilities and parameter constraints of path
+// stanzas with condition clauses, which are only applied to matching requests
+type conditionalGrant struct {
+	Conditions         []*PolicyCondition
+	CapabilitiesBitmap uint32
+	AllowedParameters  map[string][]interface{}
+	DeniedParameters   map[string][]interface{}
+	RequiredParameters []string
+}
+
+// policyConditionInput is the request context the conditions are evaluated
+// against
+type policyConditionInput struct {
+	now          time.Time
+	remoteAddr   string
+	mfaValidated bool
+	entity       *identity.Entity
+	groups       []*identity.Group
+}
+
+// decodePolicyConditions decodes the condition clauses, and their time
+// windows, of the given path stanza. They are decoded one by one as the HCL
+// decoder flattens lists nested in slices of blocks.
+func decodePolicyConditions(node ast.Node) ([]*PolicyConditionHCL, error) {
+	obj, ok := node.(*ast.ObjectType)
+	if !ok {
+		return nil, nil
+	}
+
+	var ret []*PolicyConditionHCL
+	for _, item := range obj.List.Filter("condition").Items {
+		if err := hclutil.CheckHCLKeys(item.Val, []string{
+			"time_window",
+			"source_cidrs",
+			"mfa_validated",
+			"entity_metadata",
+			"group_metadata",
+		}); err != nil {
+			return nil, multierror.Prefix(err, "condition:")
+		}
+
+		var cond PolicyConditionHCL
+		if err := hcl.DecodeObject(&cond, item.Val); err != nil {
+			return nil, multierror.Prefix(err, "condition:")
+		}
+
+		if condObj, ok := item.Val.(*ast.ObjectType); ok {
+			for _, windowItem := range condObj.List.Filter("time_window").Items {
+				if err := hclutil.CheckHCLKeys(windowItem.Val, []string{
+					"days",
+					"start",
+					"end",
+					"timezone",
+				}); err != nil {
+					return nil, multierror.Prefix(err, "condition: time_window:")
+				}
+
+				var window TimeWindowHCL
+				if err := hcl.DecodeObject(&window, windowItem.Val); err != nil {
+					return nil, multierror.Prefix(err, "condition: time_window:")
+				}
+				cond.TimeWindows = append(cond.TimeWindows, &window)
+			}
+		}
+		ret = append(ret, &cond)
+	}
+	return ret, nil
+}
+
+func parsePolicyConditions(in []*PolicyConditionHCL) ([]*PolicyCondition, error) {
+	ret := make([]*PolicyCondition, 0, len(in))
+	for _, hclCond := range in {
+		cond := &PolicyCondition{
+			MFAValidated:   hclCond.MFAValidated,
+			EntityMetadata: hclCond.EntityMetadata,
+			GroupMetadata:  hclCond.GroupMetadata,
+		}
+
+		for _, hclWindow := range hclCond.TimeWindows {
+			window, err := parseTimeWindow(hclWindow)
+			if err != nil {
+				return nil, errwrap.Wrapf("invalid time_window: {{err}}", err)
+			}
+			cond.TimeWindows = append(cond.TimeWindows, window)
+		}
+
+		if len(hclCond.SourceCIDRs) > 0 {
+			cidrs, err := parseutil.ParseAddrs(hclCond.SourceCIDRs)
+			if err != nil {
+				return nil, errwrap.Wrapf("invalid source_cidrs: {{err}}", err)
+			}
+			cond.SourceCIDRs = cidrs
+		}
+
+		if len(cond.TimeWindows) == 0 && len(cond.SourceCIDRs) == 0 && cond.MFAValidated == nil &&
+			len(cond.EntityMetadata) == 0 && len(cond.GroupMetadata) == 0 {
+			return nil, errors.New("condition must specify at least one predicate")
+		}
+		ret = append(ret, cond)
+	}
+	return ret, nil
+}
+
+func parseTimeWindow(in *TimeWindowHCL) (*TimeWindow, error) {
+	window := &TimeWindow{
+		End:      24 * 60,
+		Location: time.UTC,
+	}
+
+	for _, day := range in.Days {
+		abbrev := strings.ToLower(day)
+		if len(abbrev) > 3 {
+			abbrev = abbrev[:3]
+		}
+		weekday, ok := weekdays[abbrev]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q", day)
+		}
+		window.Days = append(window.Days, weekday)
+	}
+
+	var err error
+	if in.Start != "" {
+		if window.Start, err = parseTimeOfDay(in.Start); err != nil {
+			return nil, err
+		}
+	}
+	if in.End != "" {
+		if window.End, err = parseTimeOfDay(in.End); err != nil {
+			return nil, err
+		}
+	}
+	if window.Start == window.End {
+		return nil, errors.New("start and end must differ")
+	}
+
+	if in.Timezone != "" {
+		if window.Location, err = time.LoadLocation(in.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", in.Timezone)
+		}
+	}
+	return window, nil
+}
+
+// parseTimeOfDay parses an "HH:MM" time into minutes since midnight; "24:00"
+// is accepted to end windows at midnight
+func parseTimeOfDay(in string) (int, error) {
+	parts := strings.Split(in, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", in)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", in)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", in)
+	}
+	ret := hours*60 + minutes
+	if hours < 0 || minutes < 0 || minutes > 59 || ret > 24*60 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", in)
+	}
+	return ret, nil
+}
+
+// matches returns whether the time falls in the window
+func (w *TimeWindow) matches(now time.Time) bool {
+	local := now.In(w.Location)
+	if len(w.Days) > 0 {
+		var dayMatch bool
+		for _, day := range w.Days {
+			if local.Weekday() == day {
+				dayMatch = true
+				break
+			}
+		}
+		if !dayMatch {
+			return false
+		}
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// matches returns whether all the predicates of the condition hold for the
+// given request context
+func (cond *PolicyCondition) matches(in *policyConditionInput) bool {
+	if len(cond.TimeWindows) > 0 {
+		var windowMatch bool
+		for _, window := range cond.TimeWindows {
+			if window.matches(in.now) {
+				windowMatch = true
+				break
+			}
+		}
+		if !windowMatch {
+			return false
+		}
+	}
+
+	if len(cond.SourceCIDRs) > 0 {
+		if in.remoteAddr == "" {
+			return false
+		}
+		remoteSockAddr, err := sockaddr.NewSockAddr(in.remoteAddr)
+		if err != nil {
+			return false
+		}
+		var cidrMatch bool
+		for _, cidr := range cond.SourceCIDRs {
+			if cidr.Contains(remoteSockAddr) {
+				cidrMatch = true
+				break
+			}
+		}
+		if !cidrMatch {
+			return false
+		}
+	}
+
+	if cond.MFAValidated != nil && *cond.MFAValidated != in.mfaValidated {
+		return false
+	}
+
+	if len(cond.EntityMetadata) > 0 {
+		if in.entity == nil || !metadataMatches(cond.EntityMetadata, in.entity.Metadata) {
+			return false
+		}
+	}
+
+	if len(cond.GroupMetadata) > 0 {
+		var groupMatch bool
+		for _, group := range in.groups {
+			if metadataMatches(cond.GroupMetadata, group.Metadata) {
+				groupMatch = true
+				break
+			}
+		}
+		if !groupMatch {
+			return false
+		}
+	}
+
+	return true
+}
+
+// metadataMatches returns whether every expected key is present in the
+// metadata with a value matching the expected, possibly globbed, value
+func metadataMatches(expected, metadata map[string]string) bool {
+	for key, value := range expected {
+		actual, ok := metadata[key]
+		if !ok || !strutil.GlobbedStringsMatch(value, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasGroupConditions returns whether any condition of the policy is on the
+// metadata of the groups of the entity
+func (p *Policy) hasGroupConditions() bool {
+	for _, pr := range p.Paths {
+		if pr.Permissions == nil {
+			continue
+		}
+		for _, cond := range pr.Permissions.Conditions {
+			if len(cond.GroupMetadata) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// conditionInput gathers the request context the policy conditions of the
+// ACL are evaluated against
+func (a *ACL) conditionInput(req *logical.Request) *policyConditionInput {
+	in := &policyConditionInput{
+		now:    time.Now(),
+		entity: a.entity,
+		groups: a.groups,
+	}
+	if req.Connection != nil {
+		in.remoteAddr = req.Connection.RemoteAddr
+	}
+	if te := req.TokenEntry(); te != nil {
+		in.mfaValidated = te.MFAValidated
+	}
+	return in
+}
+
+// applyConditionalGrants returns the permissions and capabilities of the path
+// once the conditional grants matching the request are applied. The
+// parameter constraints of the matching grants are merged into a copy of the
+// permissions, which are shared by every request.
+func (a *ACL) applyConditionalGrants(req *logical.Request, permissions *ACLPermissions, capabilities uint32) (*ACLPermissions, uint32) {
+	if capabilities&DenyCapabilityInt > 0 {
+		return permissions, capabilities
+	}
+
+	in := a.conditionInput(req)
+	effective := permissions
+GRANTS:
+	for _, grant := range permissions.ConditionalGrants {
+		for _, cond := range grant.Conditions {
+			if !cond.matches(in) {
+				continue GRANTS
+			}
+		}
+		if grant.CapabilitiesBitmap&DenyCapabilityInt > 0 {
+			return permissions, DenyCapabilityInt
+		}
+		capabilities |= grant.CapabilitiesBitmap
+
+		if len(grant.AllowedParameters) == 0 && len(grant.DeniedParameters) == 0 && len(grant.RequiredParameters) == 0 {
+			continue
+		}
+		if effective == permissions {
+			cloned, err := permissions.Clone()
+			if err != nil {
+				return permissions, DenyCapabilityInt
+			}
+			cloned.MFAMethods = permissions.MFAMethods
+			cloned.ControlGroup = permissions.ControlGroup
+			effective = cloned
+		}
+		if err := mergeACLParameters(effective, grant.AllowedParameters, grant.DeniedParameters, grant.RequiredParameters); err != nil {
+			return permissions, DenyCapabilityInt
+		}
+	}
+	return effective, capabilities
+}
+
+// hasConditionalAccess reports whether any of the conditional grants of the
+// permissions grants a capability. Mount access checks are made without a
+// request to evaluate the conditions against, so such grants count as access
+// to the mount.
+func (p *ACLPermissions) hasConditionalAccess() bool {
+	for _, grant := range p.ConditionalGrants {
+		if grant.CapabilitiesBitmap&DenyCapabilityInt == 0 && grant.CapabilitiesBitmap > 0 {
+			return true
+		}
+	}
+	return false
+}
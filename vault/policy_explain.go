@@ -0,0 +1,303 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// policyExplainOperations maps the operations accepted by sys/policy-explain
+// to the capability they require
+var policyExplainOperations = map[logical.Operation]string{
+	logical.CreateOperation: CreateCapability,
+	logical.ReadOperation:   ReadCapability,
+	logical.UpdateOperation: UpdateCapability,
+	logical.DeleteOperation: DeleteCapability,
+	logical.ListOperation:   ListCapability,
+}
+
+const (
+	aclRuleTypeExact           = "exact"
+	aclRuleTypeGlob            = "glob"
+	aclRuleTypeSegmentWildcard = "segment_wildcard"
+)
+
+// aclRuleMatch is a rule of an ACL matching the path of a request
+type aclRuleMatch struct {
+	path        string
+	ruleType    string
+	permissions *ACLPermissions
+
+	// reason explains why the rule was not selected over the matching rule
+	// of highest priority
+	reason string
+}
+
+// explainRuleMatches returns the rules of the ACL matching the path of the
+// request, starting with the one AllowOperation selects and followed by the
+// others in decreasing order of priority
+func (a *ACL) explainRuleMatches(ctx context.Context, req *logical.Request) ([]*aclRuleMatch, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path := strings.TrimLeft(ns.Path+req.Path, "/")
+
+	var matches []*aclRuleMatch
+	if raw, ok := a.exactRules.Get(path); ok {
+		matches = append(matches, &aclRuleMatch{
+			path:        path,
+			ruleType:    aclRuleTypeExact,
+			permissions: raw.(*ACLPermissions),
+		})
+	} else if req.Operation == logical.ListOperation {
+		trimmed := strings.TrimSuffix(path, "/")
+		if raw, ok := a.exactRules.Get(trimmed); ok {
+			matches = append(matches, &aclRuleMatch{
+				path:        trimmed,
+				ruleType:    aclRuleTypeExact,
+				permissions: raw.(*ACLPermissions),
+			})
+		}
+	}
+
+	_, wcPathDescrs := a.nonExactPathMatches(path, false)
+	for i := len(wcPathDescrs) - 1; i >= 0; i-- {
+		pd := wcPathDescrs[i]
+		match := &aclRuleMatch{
+			path:        pd.wcPath,
+			ruleType:    aclRuleTypeGlob,
+			permissions: pd.perms,
+		}
+		if pd.isPrefix {
+			match.path += "*"
+		}
+		if _, ok := a.segmentWildcardPaths[match.path]; ok {
+			match.ruleType = aclRuleTypeSegmentWildcard
+		}
+
+		switch {
+		case len(matches) > 0 && matches[0].ruleType == aclRuleTypeExact:
+			match.reason = "exact rules take priority over glob and segment wildcard rules"
+		case len(matches) > 0:
+			match.reason = wcPathPriorityReason(wcPathDescrs[len(wcPathDescrs)-1], pd)
+		}
+		matches = append(matches, match)
+	}
+
+	for _, match := range matches {
+		match.path = strings.TrimPrefix(match.path, ns.Path)
+	}
+	return matches, nil
+}
+
+// wcPathPriorityReason explains why the selected path takes priority over
+// the other one, following the order of CheckAllowedFromNonExactPaths
+func wcPathPriorityReason(selected, other wcPathDescr) string {
+	switch {
+	case selected.firstWCOrGlob != other.firstWCOrGlob:
+		return "the first wildcard or glob of the selected rule appears later in the path"
+	case selected.isPrefix != other.isPrefix:
+		return "rules without a trailing glob take priority over rules with one"
+	case selected.wildcards != other.wildcards:
+		return "the selected rule has fewer segment wildcards"
+	case len(selected.wcPath) != len(other.wcPath):
+		return "the selected rule is longer"
+	default:
+		return "the selected rule sorts last lexicographically"
+	}
+}
+
+// explainParameters checks each parameter of the request against the
+// parameter constraints of the permissions, following AllowOperation
+func explainParameters(permissions *ACLPermissions, req *logical.Request) []map[string]interface{} {
+	switch req.Operation {
+	case logical.ReadOperation, logical.UpdateOperation, logical.CreateOperation:
+	default:
+		return nil
+	}
+
+	ret := []map[string]interface{}{}
+	for _, parameter := range permissions.RequiredParameters {
+		if _, ok := req.Data[strings.ToLower(parameter)]; !ok {
+			ret = append(ret, map[string]interface{}{
+				"name":    parameter,
+				"allowed": false,
+				"reason":  "required parameter is missing",
+			})
+		}
+	}
+
+	parameters := make([]string, 0, len(req.Data))
+	for parameter := range req.Data {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+
+	_, deniedAll := permissions.DeniedParameters["*"]
+	_, allowedAll := permissions.AllowedParameters["*"]
+	for _, parameter := range parameters {
+		value := req.Data[parameter]
+		result := map[string]interface{}{
+			"name":    parameter,
+			"allowed": false,
+		}
+		deniedValues, denied := permissions.DeniedParameters[strings.ToLower(parameter)]
+		allowedValues, allowed := permissions.AllowedParameters[strings.ToLower(parameter)]
+
+		switch {
+		case deniedAll:
+			result["reason"] = "all parameters are denied"
+		case denied && valueInParameterList(value, deniedValues):
+			result["reason"] = "parameter value is denied"
+		case len(permissions.AllowedParameters) == 0:
+			result["allowed"] = true
+		case !allowed && !allowedAll:
+			result["reason"] = "parameter is not in the allowed parameters"
+		case allowed && !valueInParameterList(value, allowedValues):
+			result["reason"] = "parameter value is not allowed"
+		default:
+			result["allowed"] = true
+		}
+		ret = append(ret, result)
+	}
+	return ret
+}
+
+// aclCapabilityNames returns the capability names set in the bitmap
+func aclCapabilityNames(bitmap uint32) []string {
+	if bitmap&DenyCapabilityInt > 0 {
+		return []string{DenyCapability}
+	}
+
+	var ret []string
+	for _, capability := range []string{CreateCapability, ReadCapability, UpdateCapability, DeleteCapability, ListCapability, SudoCapability} {
+		if bitmap&cap2Int[capability] > 0 {
+			ret = append(ret, capability)
+		}
+	}
+	if len(ret) == 0 {
+		return []string{DenyCapability}
+	}
+	return ret
+}
+
+// explainPolicies evaluates the request against the policies, as the ACL
+// built for a token entitled to them would, and explains the decision: the
+// rules matching the path along with their priority, the rule each policy
+// contributes, the parameter constraint results and the final verdict
+func (c *Core) explainPolicies(ctx, aclCtx context.Context, req *logical.Request, policies []*Policy, entity *identity.Entity, groups []*identity.Group) (map[string]interface{}, error) {
+	ret := map[string]interface{}{
+		"path":      req.Path,
+		"operation": string(req.Operation),
+	}
+
+	acl, err := NewACL(aclCtx, policies)
+	if err != nil {
+		return nil, err
+	}
+	acl.entity = entity
+	acl.groups = groups
+
+	capReq := *req
+	capReq.Operation = logical.ListOperation
+	ret["capabilities"] = acl.requestCapabilities(ctx, &capReq)
+
+	results := acl.AllowOperation(ctx, req, false)
+	ret["allowed"] = results.Allowed
+
+	explainedPolicies := make([]map[string]interface{}, 0, len(policies))
+	for _, policy := range policies {
+		if policy == nil || policy.Type != PolicyTypeACL {
+			continue
+		}
+		explained := map[string]interface{}{
+			"name": policy.Name,
+		}
+		if policy.namespace != nil {
+			explained["namespace"] = policy.namespace.Path
+		}
+
+		policyACL, err := NewACL(aclCtx, []*Policy{policy})
+		if err != nil {
+			return nil, err
+		}
+		policyACL.entity = entity
+		policyACL.groups = groups
+
+		explained["allowed"] = policyACL.AllowOperation(ctx, req, false).Allowed
+		explained["capabilities"] = policyACL.requestCapabilities(ctx, &capReq)
+
+		matches, err := policyACL.explainRuleMatches(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > 0 {
+			explained["matched_rule"] = matches[0].path
+			explained["matched_rule_type"] = matches[0].ruleType
+		}
+		explainedPolicies = append(explainedPolicies, explained)
+	}
+	ret["policies"] = explainedPolicies
+
+	if results.IsRoot {
+		ret["reason"] = "the root policy allows every request"
+		return ret, nil
+	}
+
+	matches, err := acl.explainRuleMatches(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	matchedRules := make([]map[string]interface{}, 0, len(matches))
+	for i, match := range matches {
+		_, capabilities := acl.applyConditionalGrants(req, match.permissions, match.permissions.CapabilitiesBitmap)
+		matchedRule := map[string]interface{}{
+			"path":         match.path,
+			"type":         match.ruleType,
+			"selected":     i == 0,
+			"capabilities": aclCapabilityNames(capabilities),
+		}
+		if len(match.permissions.ConditionalGrants) > 0 {
+			matchedRule["conditional"] = true
+		}
+		if match.reason != "" {
+			matchedRule["reason"] = match.reason
+		}
+		matchedRules = append(matchedRules, matchedRule)
+	}
+	ret["matched_rules"] = matchedRules
+
+	if len(matches) == 0 {
+		ret["reason"] = "no policy has a rule matching the path"
+		return ret, nil
+	}
+
+	permissions, capabilities := acl.applyConditionalGrants(req, matches[0].permissions, matches[0].permissions.CapabilitiesBitmap)
+	parameters := explainParameters(permissions, req)
+	if parameters != nil {
+		ret["parameters"] = parameters
+	}
+
+	if results.Allowed {
+		ret["reason"] = fmt.Sprintf("the rule %q grants the %q capability", matches[0].path, policyExplainOperations[req.Operation])
+		return ret, nil
+	}
+
+	switch {
+	case capabilities&DenyCapabilityInt > 0:
+		ret["reason"] = fmt.Sprintf("the rule %q denies the path", matches[0].path)
+	case capabilities&cap2Int[policyExplainOperations[req.Operation]] == 0:
+		ret["reason"] = fmt.Sprintf("the rule %q does not grant the %q capability", matches[0].path, policyExplainOperations[req.Operation])
+	case permissions.MinWrappingTTL > 0 || permissions.MaxWrappingTTL > 0:
+		ret["reason"] = fmt.Sprintf("the rule %q requires the response to be wrapped", matches[0].path)
+	default:
+		ret["reason"] = fmt.Sprintf("the request parameters do not meet the constraints of the rule %q", matches[0].path)
+	}
+	return ret, nil
+}
@@ -0,0 +1,143 @@
+package vault
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const testPolicyExplainBroad = `
+path "secret/*" {
+	capabilities = ["read", "list"]
+}
+path "secret/+/config" {
+	capabilities = ["read", "update"]
+	allowed_parameters = {
+		"ttl" = ["1h", "2h"]
+	}
+}
+`
+
+const testPolicyExplainDeny = `
+path "secret/team/config" {
+	capabilities = ["deny"]
+}
+`
+
+func testPolicyExplain(t *testing.T, c *Core, token string, data map[string]interface{}) *logical.Response {
+	t.Helper()
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/policy-explain")
+	req.ClientToken = token
+	req.Data = data
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	return resp
+}
+
+func TestPolicyExplain(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	for name, policy := range map[string]string{
+		"broad": testPolicyExplainBroad,
+		"deny":  testPolicyExplainDeny,
+	} {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/policy/"+name)
+		req.ClientToken = root
+		req.Data["policy"] = policy
+		if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+		}
+	}
+
+	// The segment wildcard rule takes priority over the glob
+	resp := testPolicyExplain(t, c, root, map[string]interface{}{
+		"policies":   "broad",
+		"path":       "secret/app/config",
+		"operation":  "update",
+		"parameters": map[string]interface{}{"ttl": "1h"},
+	})
+	if resp.Data["allowed"] != true {
+		t.Fatalf("expected the request to be allowed: %#v", resp.Data)
+	}
+	rules := resp.Data["matched_rules"].([]map[string]interface{})
+	if len(rules) != 2 || rules[0]["path"] != "secret/+/config" || rules[0]["type"] != aclRuleTypeSegmentWildcard || rules[0]["selected"] != true {
+		t.Fatalf("bad: %#v", rules)
+	}
+	if rules[1]["path"] != "secret/*" || rules[1]["type"] != aclRuleTypeGlob || rules[1]["reason"] != "rules without a trailing glob take priority over rules with one" {
+		t.Fatalf("bad: %#v", rules[1])
+	}
+
+	// Parameter constraints are reported per parameter
+	resp = testPolicyExplain(t, c, root, map[string]interface{}{
+		"policies":   "broad",
+		"path":       "secret/app/config",
+		"operation":  "update",
+		"parameters": map[string]interface{}{"ttl": "24h", "other": "x"},
+	})
+	if resp.Data["allowed"] != false {
+		t.Fatalf("expected the request to be denied: %#v", resp.Data)
+	}
+	expected := []map[string]interface{}{
+		{"name": "other", "allowed": false, "reason": "parameter is not in the allowed parameters"},
+		{"name": "ttl", "allowed": false, "reason": "parameter value is not allowed"},
+	}
+	if !reflect.DeepEqual(resp.Data["parameters"], expected) {
+		t.Fatalf("bad: %#v", resp.Data["parameters"])
+	}
+
+	// Explain for a token, with the exact deny rule of one of its policies
+	te := &logical.TokenEntry{
+		Path:     "auth/token/create",
+		Policies: []string{"broad", "deny"},
+		TTL:      time.Hour,
+	}
+	testMakeTokenDirectly(t, c.tokenStore, te)
+
+	resp = testPolicyExplain(t, c, root, map[string]interface{}{
+		"token": te.ID,
+		"path":  "secret/team/config",
+	})
+	if resp.Data["allowed"] != false || resp.Data["reason"] != `the rule "secret/team/config" denies the path` {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	rules = resp.Data["matched_rules"].([]map[string]interface{})
+	if len(rules) != 3 || rules[0]["type"] != aclRuleTypeExact || rules[2]["reason"] != "exact rules take priority over glob and segment wildcard rules" {
+		t.Fatalf("bad: %#v", rules)
+	}
+	policies := resp.Data["policies"].([]map[string]interface{})
+	explained := make(map[string]map[string]interface{}, len(policies))
+	for _, policy := range policies {
+		explained[policy["name"].(string)] = policy
+	}
+	if explained["broad"]["allowed"] != true || explained["broad"]["matched_rule"] != "secret/+/config" {
+		t.Fatalf("bad: %#v", explained["broad"])
+	}
+	if explained["deny"]["allowed"] != false || explained["deny"]["matched_rule"] != "secret/team/config" {
+		t.Fatalf("bad: %#v", explained["deny"])
+	}
+
+	// Unknown policies are reported, and paths with no rule explained
+	resp = testPolicyExplain(t, c, root, map[string]interface{}{
+		"policies": "broad,missing",
+		"path":     "other/path",
+	})
+	if resp.Data["allowed"] != false || resp.Data["reason"] != "no policy has a rule matching the path" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	if len(resp.Warnings) != 1 {
+		t.Fatalf("expected a warning for the missing policy, got %#v", resp.Warnings)
+	}
+
+	// Defaults to the policies of the calling token
+	resp = testPolicyExplain(t, c, root, map[string]interface{}{
+		"path": "any/path",
+	})
+	if resp.Data["allowed"] != true || !reflect.DeepEqual(resp.Data["capabilities"], []string{RootCapability}) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+}
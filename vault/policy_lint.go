@@ -0,0 +1,403 @@
+package vault
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/vault/helper/namespace"
+)
+
+const (
+	policyLintDuplicateRule      = "duplicate_rule"
+	policyLintShadowedRule       = "shadowed_rule"
+	policyLintConflictingOverlap = "conflicting_overlap"
+	policyLintUnmountedPath      = "unmounted_path"
+	policyLintDeprecatedSyntax   = "deprecated_syntax"
+	policyLintBroadPattern       = "broad_pattern"
+)
+
+// policyLintFinding is an issue found in a policy by the linter
+type policyLintFinding struct {
+	Check   string
+	Path    string
+	Line    int
+	Message string
+}
+
+func (f *policyLintFinding) toMap() map[string]interface{} {
+	ret := map[string]interface{}{
+		"check":   f.Check,
+		"path":    f.Path,
+		"message": f.Message,
+	}
+	if f.Line > 0 {
+		ret["line"] = f.Line
+	}
+	return ret
+}
+
+// policyLintRule is the merge of the path stanzas of a policy on the same
+// path, as NewACL would merge them
+type policyLintRule struct {
+	// pattern is the full path of the rule, with a trailing "*" for globs
+	pattern string
+
+	// path is the pattern relative to the namespace of the policy
+	path string
+
+	lines        []int
+	exact        bool
+	capabilities uint32
+	denyLine     int
+	grantLines   []int
+	conditional  bool
+	descr        wcPathDescr
+}
+
+// lintACLPolicy statically analyzes the parsed ACL policy. mountPaths holds
+// the full API paths of the mounts the paths of the policy are checked
+// against; mount checks are skipped when it is nil.
+func lintACLPolicy(policy *Policy, mountPaths []string) []*policyLintFinding {
+	ns := policy.namespace
+	if ns == nil {
+		ns = namespace.RootNamespace
+	}
+	lines := policyPathLines(policy)
+
+	var findings []*policyLintFinding
+	var rules []*policyLintRule
+	rulesByPattern := make(map[string]*policyLintRule, len(policy.Paths))
+	for i, pr := range policy.Paths {
+		line := 0
+		if len(lines) == len(policy.Paths) {
+			line = lines[i]
+		}
+
+		pattern := pr.Path
+		if pr.IsPrefix {
+			pattern += "*"
+		}
+		relPath := strings.TrimPrefix(pattern, ns.Path)
+
+		if pr.Policy != "" {
+			findings = append(findings, &policyLintFinding{
+				Check: policyLintDeprecatedSyntax,
+				Path:  relPath,
+				Line:  line,
+				Message: fmt.Sprintf("policy = %q is deprecated; use capabilities = [%s] instead",
+					pr.Policy, quotedList(pr.Capabilities)),
+			})
+		}
+
+		rule, ok := rulesByPattern[pattern]
+		if !ok {
+			rule = &policyLintRule{
+				pattern: pattern,
+				path:    relPath,
+				exact:   !pr.IsPrefix && !pr.HasSegmentWildcards,
+				descr:   lintWCPathDescr(pr),
+			}
+			rulesByPattern[pattern] = rule
+			rules = append(rules, rule)
+		}
+		rule.lines = append(rule.lines, line)
+
+		if pr.Permissions == nil {
+			continue
+		}
+		bitmap := pr.Permissions.CapabilitiesBitmap
+		if bitmap != 0 && bitmap&DenyCapabilityInt == 0 {
+			rule.grantLines = append(rule.grantLines, line)
+		}
+		if len(pr.Permissions.Conditions) > 0 {
+			rule.conditional = true
+			continue
+		}
+		switch {
+		case bitmap&DenyCapabilityInt > 0:
+			rule.capabilities = DenyCapabilityInt
+			if rule.denyLine == 0 {
+				rule.denyLine = line
+			}
+		case rule.capabilities&DenyCapabilityInt == 0:
+			rule.capabilities |= bitmap
+		}
+	}
+
+	for _, rule := range rules {
+		if len(rule.lines) > 1 {
+			findings = append(findings, &policyLintFinding{
+				Check: policyLintDuplicateRule,
+				Path:  rule.path,
+				Line:  rule.lines[1],
+				Message: fmt.Sprintf("path is defined %d times, on lines %s; the stanzas are merged",
+					len(rule.lines), joinInts(rule.lines)),
+			})
+		}
+		if rule.denyLine > 0 {
+			for _, line := range rule.grantLines {
+				findings = append(findings, &policyLintFinding{
+					Check:   policyLintShadowedRule,
+					Path:    rule.path,
+					Line:    line,
+					Message: fmt.Sprintf("capabilities are ignored as the path is denied on line %d", rule.denyLine),
+				})
+			}
+		}
+
+		findings = append(findings, lintBroadPattern(rule, ns)...)
+
+		if mountPaths != nil {
+			var mounted bool
+			for _, mountPath := range mountPaths {
+				if globPatternsOverlap(rule.pattern, mountPath+"*") {
+					mounted = true
+					break
+				}
+			}
+			if !mounted {
+				findings = append(findings, &policyLintFinding{
+					Check:   policyLintUnmountedPath,
+					Path:    rule.path,
+					Line:    rule.lines[0],
+					Message: "path does not match any mounted secrets engine, auth method or system path",
+				})
+			}
+		}
+	}
+
+	// Compare the rules matching common paths. Conditional rules only apply
+	// to some requests and are left out.
+	for i, a := range rules {
+		for _, b := range rules[i+1:] {
+			if a.conditional || b.conditional || a.capabilities == 0 || b.capabilities == 0 {
+				continue
+			}
+			if !globPatternsOverlap(a.pattern, b.pattern) {
+				continue
+			}
+
+			high, low := a, b
+			if lintRulePriorityLess(a, b) {
+				high, low = b, a
+			}
+			if finding := lintOverlap(high, low); finding != nil {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// lintOverlap reports the rule of higher priority when it drops the deny or
+// capabilities of the other on the paths both match
+func lintOverlap(high, low *policyLintRule) *policyLintFinding {
+	switch {
+	case high.capabilities&DenyCapabilityInt > 0:
+		// Denying a subset of the paths of a broader rule is intended
+		return nil
+
+	case low.capabilities&DenyCapabilityInt > 0:
+		return &policyLintFinding{
+			Check: policyLintConflictingOverlap,
+			Path:  high.path,
+			Line:  high.lines[0],
+			Message: fmt.Sprintf("rule takes priority over the deny of %q (line %d) on the paths both match",
+				low.path, low.lines[0]),
+		}
+	}
+
+	missing := low.capabilities &^ high.capabilities
+	if missing == 0 {
+		return nil
+	}
+	return &policyLintFinding{
+		Check: policyLintConflictingOverlap,
+		Path:  high.path,
+		Line:  high.lines[0],
+		Message: fmt.Sprintf("rule takes priority over %q (line %d) on the paths both match but does not grant %s; capabilities are not merged across paths",
+			low.path, low.lines[0], quotedList(aclCapabilityNames(missing))),
+	}
+}
+
+// lintBroadPattern reports globs and segment wildcards likely to grant
+// broader access than intended
+func lintBroadPattern(rule *policyLintRule, ns *namespace.Namespace) []*policyLintFinding {
+	finding := func(msg string, args ...interface{}) *policyLintFinding {
+		return &policyLintFinding{
+			Check:   policyLintBroadPattern,
+			Path:    rule.path,
+			Line:    rule.lines[0],
+			Message: fmt.Sprintf(msg, args...),
+		}
+	}
+
+	if rule.capabilities&DenyCapabilityInt > 0 {
+		return nil
+	}
+
+	trimmed := strings.TrimSuffix(rule.path, "*")
+	if !rule.exact && (trimmed == "" || trimmed == "+" || strings.HasPrefix(trimmed, "+/")) {
+		return []*policyLintFinding{finding("rule matches paths on every mount")}
+	}
+
+	var ret []*policyLintFinding
+	if strings.Contains(trimmed, "*") {
+		ret = append(ret, finding(`"*" is only a glob at the end of a path and is matched literally elsewhere; use "+" to match a path segment`))
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment != "+" && strings.Contains(segment, "+") && !strings.Contains(segment, "{{") {
+			ret = append(ret, finding(`"+" is only a wildcard when it makes up a whole path segment and is matched literally in %q`, segment))
+			break
+		}
+	}
+	if strings.HasSuffix(rule.path, "*") && !strings.HasSuffix(trimmed, "/") {
+		ret = append(ret, finding("glob also matches sibling paths such as %q; use %q to only match paths under %q",
+			trimmed+"-other", trimmed+"/*", trimmed+"/"))
+	}
+	if !rule.exact && !strings.HasPrefix(rule.pattern, ns.Path+"sys/") && globPatternsOverlap(rule.pattern, ns.Path+"sys/*") {
+		ret = append(ret, finding(`rule also grants access to the system backend at "sys/"`))
+	}
+	return ret
+}
+
+// lintWCPathDescr describes a glob or segment wildcard rule for priority
+// comparisons, as CheckAllowedFromNonExactPaths does
+func lintWCPathDescr(pr *PathRules) wcPathDescr {
+	if !pr.HasSegmentWildcards {
+		return wcPathDescr{
+			firstWCOrGlob: len(pr.Path),
+			wcPath:        pr.Path,
+			isPrefix:      pr.IsPrefix,
+		}
+	}
+
+	pd := wcPathDescr{
+		firstWCOrGlob: strings.Index(pr.Path, "+"),
+		wcPath:        strings.TrimSuffix(pr.Path, "*"),
+		isPrefix:      strings.HasSuffix(pr.Path, "*"),
+	}
+	for _, segment := range strings.Split(pd.wcPath, "/") {
+		if segment == "+" {
+			pd.wildcards++
+		}
+	}
+	return pd
+}
+
+// lintRulePriorityLess returns whether rule a has a lower priority than rule
+// b on the paths both match
+func lintRulePriorityLess(a, b *policyLintRule) bool {
+	switch {
+	case a.exact:
+		return false
+	case b.exact:
+		return true
+	default:
+		return wcPathDescrLess(a.descr, b.descr)
+	}
+}
+
+// splitGlobPattern splits a policy path into its segments, returning whether
+// it ends with a glob. Templated segments are treated as segment wildcards.
+func splitGlobPattern(pattern string) ([]string, bool) {
+	isPrefix := strings.HasSuffix(pattern, "*")
+	segments := strings.Split(strings.TrimSuffix(pattern, "*"), "/")
+	for i, segment := range segments {
+		if strings.Contains(segment, "{{") {
+			segments[i] = "+"
+		}
+	}
+	return segments, isPrefix
+}
+
+// globPatternsOverlap returns whether some path matches both policy paths
+func globPatternsOverlap(a, b string) bool {
+	aSegs, aPrefix := splitGlobPattern(a)
+	bSegs, bPrefix := splitGlobPattern(b)
+
+	for i := 0; i < len(aSegs) && i < len(bSegs); i++ {
+		sa, sb := aSegs[i], bSegs[i]
+		aLast, bLast := i == len(aSegs)-1, i == len(bSegs)-1
+
+		switch {
+		case aLast && aPrefix && bLast && bPrefix:
+			return sa == "+" || sb == "+" || strings.HasPrefix(sa, sb) || strings.HasPrefix(sb, sa)
+		case aLast && aPrefix:
+			return sb == "+" || strings.HasPrefix(sb, sa)
+		case bLast && bPrefix:
+			return sa == "+" || strings.HasPrefix(sa, sb)
+		case sa != sb && sa != "+" && sb != "+":
+			return false
+		case aLast || bLast:
+			return aLast && bLast
+		}
+	}
+	return false
+}
+
+// policyPathLines returns the lines the path stanzas of the policy start on,
+// in the order ParseACLPolicy parses them
+func policyPathLines(policy *Policy) []int {
+	root, err := hcl.Parse(policy.Raw)
+	if err != nil {
+		return nil
+	}
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil
+	}
+
+	var lines []int
+	for _, item := range list.Filter("path").Items {
+		lines = append(lines, item.Pos().Line)
+	}
+	return lines
+}
+
+func quotedList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, fmt.Sprintf("%q", item))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func joinInts(items []int) string {
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		strs = append(strs, fmt.Sprintf("%d", item))
+	}
+	return strings.Join(strs, ", ")
+}
+
+// policyLintMountPaths returns the full API paths of the mounts and auth
+// methods in the namespace and its children
+func (c *Core) policyLintMountPaths(ns *namespace.Namespace) []string {
+	var paths []string
+
+	c.mountsLock.RLock()
+	for _, entry := range c.mounts.Entries {
+		if entry.Namespace() != nil && strings.HasPrefix(entry.APIPath(), ns.Path) {
+			paths = append(paths, entry.APIPath())
+		}
+	}
+	c.mountsLock.RUnlock()
+
+	c.authLock.RLock()
+	for _, entry := range c.auth.Entries {
+		if entry.Namespace() != nil && strings.HasPrefix(entry.APIPath(), ns.Path) {
+			paths = append(paths, entry.APIPath())
+		}
+	}
+	c.authLock.RUnlock()
+
+	return paths
+}
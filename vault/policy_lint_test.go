@@ -0,0 +1,181 @@
+package vault
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const testPolicyLint = `
+path "secret/*" {
+	capabilities = ["read", "list"]
+}
+
+path "secret/+/config" {
+	capabilities = ["read"]
+}
+
+path "secret/foo" {
+	policy = "write"
+}
+
+path "secret/foo" {
+	capabilities = ["deny"]
+}
+
+path "secret/foo" {
+	capabilities = ["read"]
+	condition {
+		mfa_validated = true
+	}
+}
+
+path "secret/data/*" {
+	capabilities = ["read"]
+}
+
+path "secret/data/*" {
+	capabilities = ["list"]
+}
+
+path "secret/data/app/*" {
+	capabilities = ["deny"]
+}
+
+path "secret/team*" {
+	capabilities = ["read", "list"]
+}
+
+path "missing/*" {
+	capabilities = ["read"]
+}
+
+path "+/config" {
+	capabilities = ["read"]
+}
+`
+
+func TestPolicyLint_GlobPatternsOverlap(t *testing.T) {
+	overlapTests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"secret/*", "secret/foo", true},
+		{"secret/*", "other/foo", false},
+		{"secret/foo*", "secret/f*", true},
+		{"secret/foo*", "secret/bar*", false},
+		{"secret/+/config", "secret/app/*", true},
+		{"secret/+/config", "secret/app/data", false},
+		{"secret/+", "secret/+/config", false},
+		{"secret/+/*", "secret/app", false},
+		{"secret/{{identity.entity.id}}/*", "secret/app/foo", true},
+		{"*", "sys/*", true},
+		{"+/config", "sys/*", true},
+	}
+	for _, tc := range overlapTests {
+		if actual := globPatternsOverlap(tc.a, tc.b); actual != tc.expected {
+			t.Fatalf("overlap of %q and %q: expected %t, got %t", tc.a, tc.b, tc.expected, actual)
+		}
+		if actual := globPatternsOverlap(tc.b, tc.a); actual != tc.expected {
+			t.Fatalf("overlap of %q and %q: expected %t, got %t", tc.b, tc.a, tc.expected, actual)
+		}
+	}
+}
+
+func TestPolicyLint(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/policy/lint")
+	req.ClientToken = root
+	req.Data["policy"] = testPolicyLint
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	type finding struct {
+		check string
+		path  string
+		line  int
+	}
+	var actual []finding
+	for _, f := range resp.Data["findings"].([]map[string]interface{}) {
+		actual = append(actual, finding{f["check"].(string), f["path"].(string), f["line"].(int)})
+	}
+	expected := []finding{
+		{policyLintConflictingOverlap, "secret/+/config", 6},
+		{policyLintDeprecatedSyntax, "secret/foo", 10},
+		{policyLintShadowedRule, "secret/foo", 10},
+		{policyLintDuplicateRule, "secret/foo", 14},
+		{policyLintShadowedRule, "secret/foo", 18},
+		{policyLintDuplicateRule, "secret/data/*", 29},
+		{policyLintBroadPattern, "secret/team*", 37},
+		{policyLintUnmountedPath, "missing/*", 41},
+		{policyLintBroadPattern, "+/config", 45},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// Lint a stored policy
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/policy/lint")
+	req.ClientToken = root
+	req.Data["name"] = "default"
+	resp, err = c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	if _, ok := resp.Data["findings"]; !ok {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Parse errors are returned
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/policy/lint")
+	req.ClientToken = root
+	req.Data["policy"] = `path "secret/*" { capabilities = ["bogus"] }`
+	resp, err = c.HandleRequest(namespace.RootContext(nil), req)
+	if err == nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected a parse error: resp: %#v\nerr: %v", resp, err)
+	}
+}
+
+func TestPolicyLint_BroadPatterns(t *testing.T) {
+	policy, err := ParseACLPolicy(namespace.RootNamespace, `
+path "+/*" {
+	capabilities = ["read"]
+}
+
+path "secret/a*b/+x/*" {
+	capabilities = ["read"]
+}
+
+path "s*" {
+	capabilities = ["read"]
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mount checks are skipped without mounts
+	findings := lintACLPolicy(policy, nil)
+	var messages []string
+	for _, finding := range findings {
+		if finding.Check != policyLintBroadPattern {
+			t.Fatalf("unexpected finding: %#v", finding)
+		}
+		messages = append(messages, finding.Path+": "+finding.Message)
+	}
+	expected := []string{
+		"+/*: rule matches paths on every mount",
+		`secret/a*b/+x/*: "*" is only a glob at the end of a path and is matched literally elsewhere; use "+" to match a path segment`,
+		`secret/a*b/+x/*: "+" is only a wildcard when it makes up a whole path segment and is matched literally in "+x"`,
+		`s*: glob also matches sibling paths such as "s-other"; use "s/*" to only match paths under "s/"`,
+		`s*: rule also grants access to the system backend at "sys/"`,
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("bad: %#v", messages)
+	}
+}
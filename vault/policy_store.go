@@ -757,21 +757,40 @@ func (t *TemplateError) Error() string {
 // ACL is used to return an ACL which is built using the
 // named policies.
 func (ps *PolicyStore) ACL(ctx context.Context, entity *identity.Entity, policyNames map[string][]string) (*ACL, error) {
+	policies, groups, err := ps.aclPolicies(ctx, entity, policyNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct the ACL
+	acl, err := NewACL(ctx, policies)
+	if err != nil {
+		return nil, errwrap.Wrapf("failed to construct ACL: {{err}}", err)
+	}
+	acl.entity = entity
+	acl.groups = groups
+
+	return acl, nil
+}
+
+// aclPolicies fetches the named policies, templated for the entity, along
+// with the groups of the entity when the policies depend on them
+func (ps *PolicyStore) aclPolicies(ctx context.Context, entity *identity.Entity, policyNames map[string][]string) ([]*Policy, []*identity.Group, error) {
 	var policies []*Policy
 	// Fetch the policies
 	for nsID, nsPolicyNames := range policyNames {
 		policyNS, err := NamespaceByID(ctx, nsID, ps.core)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if policyNS == nil {
-			return nil, namespace.ErrNoNamespace
+			return nil, nil, namespace.ErrNoNamespace
 		}
 		policyCtx := namespace.ContextWithNamespace(ctx, policyNS)
 		for _, nsPolicyName := range nsPolicyNames {
 			p, err := ps.GetPolicy(policyCtx, nsPolicyName, PolicyTypeToken)
 			if err != nil {
-				return nil, errwrap.Wrapf("failed to get policy: {{err}}", err)
+				return nil, nil, errwrap.Wrapf("failed to get policy: {{err}}", err)
 			}
 			if p != nil {
 				policies = append(policies, p)
@@ -781,34 +800,37 @@ func (ps *PolicyStore) ACL(ctx context.Context, entity *identity.Entity, policyN
 
 	var fetchedGroups bool
 	var groups []*identity.Group
+	fetchGroups := func() error {
+		if fetchedGroups {
+			return nil
+		}
+		fetchedGroups = true
+		if entity != nil {
+			directGroups, inheritedGroups, err := ps.core.identityStore.groupsByEntityID(entity.ID)
+			if err != nil {
+				return errwrap.Wrapf("failed to fetch group memberships: {{err}}", err)
+			}
+			groups = append(directGroups, inheritedGroups...)
+		}
+		return nil
+	}
 	for i, policy := range policies {
-		if policy.Type == PolicyTypeACL && policy.Templated {
-			if !fetchedGroups {
-				fetchedGroups = true
-				if entity != nil {
-					directGroups, inheritedGroups, err := ps.core.identityStore.groupsByEntityID(entity.ID)
-					if err != nil {
-						return nil, errwrap.Wrapf("failed to fetch group memberships: {{err}}", err)
-					}
-					groups = append(directGroups, inheritedGroups...)
-				}
+		if policy.Type == PolicyTypeACL && (policy.Templated || policy.hasGroupConditions()) {
+			if err := fetchGroups(); err != nil {
+				return nil, nil, err
 			}
+		}
+		if policy.Type == PolicyTypeACL && policy.Templated {
 			p, err := parseACLPolicyWithTemplating(policy.namespace, policy.Raw, true, entity, groups)
 			if err != nil {
-				return nil, errwrap.Wrapf(fmt.Sprintf("error parsing templated policy %q: {{err}}", policy.Name), err)
+				return nil, nil, errwrap.Wrapf(fmt.Sprintf("error parsing templated policy %q: {{err}}", policy.Name), err)
 			}
 			p.Name = policy.Name
 			policies[i] = p
 		}
 	}
 
-	// Construct the ACL
-	acl, err := NewACL(ctx, policies)
-	if err != nil {
-		return nil, errwrap.Wrapf("failed to construct ACL: {{err}}", err)
-	}
-
-	return acl, nil
+	return policies, groups, nil
 }
 
 // loadACLPolicy is used to load default ACL policies. The default policies will
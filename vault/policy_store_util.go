@@ -4,6 +4,7 @@ package vault
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -14,18 +15,68 @@ type entPolicyStore struct{}
 func (ps *PolicyStore) extraInit() {
 }
 
-func (ps *PolicyStore) loadNamespacePolicies(context.Context, *Core) error { return nil }
+func (ps *PolicyStore) loadNamespacePolicies(ctx context.Context, c *Core) error {
+	for _, ns := range c.ListNamespaces(namespace.RootNamespace, true) {
+		keys, err := logical.CollectKeys(namespace.ContextWithNamespace(ctx, ns), ps.getACLView(ns))
+		if err != nil {
+			ps.logger.Error("error collecting acl policy keys", "namespace", ns.Path, "error", err)
+			return err
+		}
+		for _, key := range keys {
+			ps.policyTypeMap.Store(ps.cacheKey(ns, ps.sanitizeName(key)), PolicyTypeACL)
+		}
+	}
+	return nil
+}
 
-func (ps *PolicyStore) getACLView(*namespace.Namespace) *BarrierView {
-	return ps.aclView
+func (ps *PolicyStore) getACLView(ns *namespace.Namespace) *BarrierView {
+	if ns.ID == namespace.RootNamespaceID {
+		return ps.aclView
+	}
+	return ps.core.namespaceView(ns, systemBarrierPrefix+policyACLSubPath)
 }
 
 func (ps *PolicyStore) getRGPView(ns *namespace.Namespace) *BarrierView {
-	return ps.rgpView
+	if ns.ID == namespace.RootNamespaceID {
+		return ps.rgpView
+	}
+	return ps.core.namespaceView(ns, systemBarrierPrefix+policyRGPSubPath)
 }
 
 func (ps *PolicyStore) getEGPView(ns *namespace.Namespace) *BarrierView {
-	return ps.egpView
+	if ns.ID == namespace.RootNamespaceID {
+		return ps.egpView
+	}
+	return ps.core.namespaceView(ns, systemBarrierPrefix+policyEGPSubPath)
+}
+
+// removeNamespace drops the cached policies of a deleted namespace
+func (ps *PolicyStore) removeNamespace(ns *namespace.Namespace) {
+	prefix := ns.ID + "/"
+
+	ps.modifyLock.Lock()
+	defer ps.modifyLock.Unlock()
+
+	ps.policyTypeMap.Range(func(k, _ interface{}) bool {
+		if strings.HasPrefix(k.(string), prefix) {
+			ps.policyTypeMap.Delete(k)
+		}
+		return true
+	})
+	if ps.tokenPoliciesLRU != nil {
+		for _, k := range ps.tokenPoliciesLRU.Keys() {
+			if strings.HasPrefix(k.(string), prefix) {
+				ps.tokenPoliciesLRU.Remove(k)
+			}
+		}
+	}
+	if ps.egpLRU != nil {
+		for _, k := range ps.egpLRU.Keys() {
+			if strings.HasPrefix(k.(string), prefix) {
+				ps.egpLRU.Remove(k)
+			}
+		}
+	}
 }
 
 func (ps *PolicyStore) getBarrierView(ns *namespace.Namespace, _ PolicyType) *BarrierView {
@@ -43,5 +94,13 @@ func (ps *PolicyStore) invalidateEGPTreePath(string) {}
 func (ps *PolicyStore) pathsToEGPPaths(*Policy) ([]*egpPath, error) { return nil, nil }
 
 func (ps *PolicyStore) loadACLPolicyNamespaces(ctx context.Context, policyName, policyText string) error {
-	return ps.loadACLPolicyInternal(namespace.RootContext(ctx), policyName, policyText)
+	if err := ps.loadACLPolicyInternal(namespace.RootContext(ctx), policyName, policyText); err != nil {
+		return err
+	}
+	for _, ns := range ps.core.ListNamespaces(namespace.RootNamespace, true) {
+		if err := ps.loadACLPolicyInternal(namespace.ContextWithNamespace(ctx, ns), policyName, policyText); err != nil {
+			return err
+		}
+	}
+	return nil
 }
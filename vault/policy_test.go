@@ -1,6 +1,7 @@
 package vault
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -429,3 +430,92 @@ path "foo/+*" {
 		t.Errorf("bad error: %s", err)
 	}
 }
+
+func TestPolicy_ParseConditions(t *testing.T) {
+	p, err := ParseACLPolicy(namespace.RootNamespace, strings.TrimSpace(`
+path "secret/foo" {
+	capabilities = ["read"]
+	condition {
+		time_window {
+			days = ["mon", "Tuesday"]
+			start = "22:00"
+			end = "06:00"
+			timezone = "America/New_York"
+		}
+		source_cidrs = ["10.0.0.0/8", "127.0.0.1"]
+		mfa_validated = true
+		entity_metadata = {
+			team = "ops"
+		}
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conditions := p.Paths[0].Permissions.Conditions
+	if len(conditions) != 1 {
+		t.Fatalf("bad: %#v", conditions)
+	}
+	cond := conditions[0]
+	window := cond.TimeWindows[0]
+	if diff := deep.Equal(window.Days, []time.Weekday{time.Monday, time.Tuesday}); diff != nil {
+		t.Fatal(diff)
+	}
+	if window.Start != 22*60 || window.End != 6*60 || window.Location.String() != "America/New_York" {
+		t.Fatalf("bad: %#v", window)
+	}
+	if len(cond.SourceCIDRs) != 2 || cond.MFAValidated == nil || !*cond.MFAValidated || cond.EntityMetadata["team"] != "ops" {
+		t.Fatalf("bad: %#v", cond)
+	}
+
+	for _, tc := range []struct {
+		condition string
+		err       string
+	}{
+		{`condition {}`, "at least one predicate"},
+		{`condition { source_cidrs = ["nope"] }`, "invalid source_cidrs"},
+		{`condition { time_window { days = ["someday"] } }`, `invalid day "someday"`},
+		{`condition { time_window { start = "25:00" } }`, `invalid time "25:00"`},
+		{`condition { time_window { start = "08:00", end = "08:00" } }`, "start and end must differ"},
+		{`condition { time_window { timezone = "Mars/Olympus" } }`, `invalid timezone "Mars/Olympus"`},
+		{`condition { source_cidr = ["10.0.0.0/8"] }`, `invalid key "source_cidr"`},
+	} {
+		_, err := ParseACLPolicy(namespace.RootNamespace, fmt.Sprintf(`path "secret/foo" {
+	capabilities = ["read"]
+	%s
+}`, tc.condition))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("expected error containing %q for %s, got %v", tc.err, tc.condition, err)
+		}
+	}
+}
+
+func TestPolicy_TimeWindow(t *testing.T) {
+	window := &TimeWindow{
+		Days:     []time.Weekday{time.Friday},
+		Start:    22 * 60,
+		End:      6 * 60,
+		Location: time.UTC,
+	}
+
+	for _, tc := range []struct {
+		time     string
+		expected bool
+	}{
+		{"2019-11-01T23:30:00Z", true},
+		{"2019-11-01T05:59:00Z", true},
+		{"2019-11-01T06:00:00Z", false},
+		{"2019-11-01T12:00:00Z", false},
+		{"2019-11-02T01:00:00Z", false},
+	} {
+		now, err := time.Parse(time.RFC3339, tc.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := window.matches(now); actual != tc.expected {
+			t.Fatalf("bad: %s: expected %t", tc.time, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/errwrap"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// coreQuotasPath is the storage prefix of the quota rules
+	coreQuotasPath = "core/quotas/"
+
+	// leaseCountQuotaPrefix is the storage prefix of the lease count quotas,
+	// relative to coreQuotasPath
+	leaseCountQuotaPrefix = "lease-count/"
+)
+
+// LeaseCountQuota caps the number of leases that can exist at once for a
+// mount or namespace, optionally narrowed down to the clients of an auth role
+// or to a single entity
+type LeaseCountQuota struct {
+	Name string `json:"name"`
+
+	// Path is the mount or namespace path the quota applies to, relative to
+	// the root namespace. An empty path applies the quota to every lease.
+	Path string `json:"path"`
+
+	// Role, if set, restricts the quota to the leases of tokens issued
+	// against this role
+	Role string `json:"role"`
+
+	// EntityID, if set, restricts the quota to the leases of this entity
+	EntityID string `json:"entity_id"`
+
+	MaxLeases int `json:"max_leases"`
+
+	// count is the number of leases currently counted against the quota
+	count int
+}
+
+// leaseQuotaAttrs are the attributes of a lease that lease count quotas are
+// matched against
+type leaseQuotaAttrs struct {
+	mountPath string
+	role      string
+	entityID  string
+}
+
+// matches returns true if the lease with the given attributes counts against
+// the quota
+func (q *LeaseCountQuota) matches(attrs leaseQuotaAttrs) bool {
+	if !strings.HasPrefix(attrs.mountPath, q.Path) {
+		return false
+	}
+	if q.Role != "" && attrs.role != q.Role {
+		return false
+	}
+	if q.EntityID != "" && attrs.entityID != q.EntityID {
+		return false
+	}
+	return true
+}
+
+// QuotaStore holds the quota rules of the cluster along with the state used
+// to enforce them
+type QuotaStore struct {
+	view   *BarrierView
+	logger log.Logger
+
+	// lock protects the quotas along with their lease counts
+	lock       sync.RWMutex
+	leaseCount map[string]*LeaseCountQuota
+	rateLimit  map[string]*RateLimitQuota
+
+	// config is nil until the quota configuration is first written, in which
+	// case the defaults apply
+	config *quotaConfig
+}
+
+// setupQuotaStore loads the quota rules when the vault is being unsealed. It
+// must happen before the expiration manager is set up, since restored leases
+// are counted against the quotas.
+func (c *Core) setupQuotaStore(ctx context.Context) error {
+	store := &QuotaStore{
+		view:       NewBarrierView(c.barrier, coreQuotasPath),
+		logger:     c.baseLogger.Named("quotas"),
+		leaseCount: make(map[string]*LeaseCountQuota),
+		rateLimit:  make(map[string]*RateLimitQuota),
+	}
+	c.AddLogger(store.logger)
+
+	keys, err := store.view.List(ctx, leaseCountQuotaPrefix)
+	if err != nil {
+		return errwrap.Wrapf("failed to list lease count quotas: {{err}}", err)
+	}
+	for _, key := range keys {
+		entry, err := store.view.Get(ctx, leaseCountQuotaPrefix+key)
+		if err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to read lease count quota %q: {{err}}", key), err)
+		}
+		if entry == nil {
+			continue
+		}
+		quota := new(LeaseCountQuota)
+		if err := jsonutil.DecodeJSON(entry.Value, quota); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to decode lease count quota %q: {{err}}", key), err)
+		}
+		store.leaseCount[quota.Name] = quota
+	}
+
+	if err := store.loadRateLimitQuotas(ctx); err != nil {
+		return err
+	}
+	if err := store.loadConfig(ctx); err != nil {
+		return err
+	}
+
+	c.quotaStore = store
+	return nil
+}
+
+// teardownQuotaStore is used to reverse setupQuotaStore when the vault is
+// being sealed.
+func (c *Core) teardownQuotaStore() {
+	c.quotaStore = nil
+}
+
+// quotaPath validates and normalizes the path of a quota, which must be a
+// mount or a namespace path relative to the root namespace, or empty
+func (c *Core) quotaPath(path string) (string, error) {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return "", nil
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	if c.router.MatchingMount(namespace.RootContext(nil), path) == path {
+		return path, nil
+	}
+	if ns, ok := c.NamespaceByPath(path); ok && ns.Path == path {
+		return path, nil
+	}
+	return "", fmt.Errorf("path %q is neither a mount nor a namespace", path)
+}
+
+// ListLeaseCountQuotas returns the names of the lease count quotas
+func (s *QuotaStore) ListLeaseCountQuotas() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	names := make([]string, 0, len(s.leaseCount))
+	for name := range s.leaseCount {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetLeaseCountQuota returns a copy of the named lease count quota along with
+// the number of leases counted against it, or nil if it doesn't exist
+func (s *QuotaStore) GetLeaseCountQuota(name string) (*LeaseCountQuota, int) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	quota, ok := s.leaseCount[name]
+	if !ok {
+		return nil, 0
+	}
+	ret := *quota
+	return &ret, quota.count
+}
+
+// SetLeaseCountQuota creates or replaces a lease count quota. The leases that
+// already exist are counted against it with countLeases, unless it replaces a
+// quota matching the same leases, but are not revoked if they exceed it.
+// Leases can't be registered in the meantime.
+func (s *QuotaStore) SetLeaseCountQuota(ctx context.Context, quota *LeaseCountQuota, countLeases func(*LeaseCountQuota) (int, error)) error {
+	entry, err := logical.StorageEntryJSON(leaseCountQuotaPrefix+quota.Name, quota)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stored := *quota
+	if existing, ok := s.leaseCount[quota.Name]; ok && existing.Path == stored.Path && existing.Role == stored.Role && existing.EntityID == stored.EntityID {
+		stored.count = existing.count
+	} else {
+		stored.count, err = countLeases(&stored)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.view.Put(ctx, entry); err != nil {
+		return errwrap.Wrapf("failed to persist lease count quota: {{err}}", err)
+	}
+	s.leaseCount[quota.Name] = &stored
+
+	if stored.count > stored.MaxLeases {
+		s.logger.Warn("lease count quota is already exceeded", "name", stored.Name, "count", stored.count, "max_leases", stored.MaxLeases)
+	}
+	return nil
+}
+
+// DeleteLeaseCountQuota removes a lease count quota
+func (s *QuotaStore) DeleteLeaseCountQuota(ctx context.Context, name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.view.Delete(ctx, leaseCountQuotaPrefix+name); err != nil {
+		return errwrap.Wrapf("failed to delete lease count quota: {{err}}", err)
+	}
+	delete(s.leaseCount, name)
+	return nil
+}
+
+// hasLeaseCountQuotas returns true if any lease count quota is configured
+func (s *QuotaStore) hasLeaseCountQuotas() bool {
+	if s == nil {
+		return false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.leaseCount) > 0
+}
+
+// reserveLease counts a new lease against the lease count quotas it matches.
+// It fails without counting the lease if any of them is already full.
+func (s *QuotaStore) reserveLease(attrs leaseQuotaAttrs) error {
+	if !s.hasLeaseCountQuotas() {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, quota := range s.leaseCount {
+		if quota.matches(attrs) && quota.count >= quota.MaxLeases {
+			metrics.IncrCounterWithLabels([]string{"quota", "lease_count", "violation"}, 1, quotaMetricLabels(quota.Name))
+			return errwrap.Wrapf(fmt.Sprintf("quota %q allows at most %d leases: {{err}}", quota.Name, quota.MaxLeases), logical.ErrLeaseCountQuotaExceeded)
+		}
+	}
+	s.countLeaseLocked(attrs, 1)
+	return nil
+}
+
+// trackLease counts an existing lease against the lease count quotas it
+// matches, regardless of whether they are full
+func (s *QuotaStore) trackLease(attrs leaseQuotaAttrs) {
+	if !s.hasLeaseCountQuotas() {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.countLeaseLocked(attrs, 1)
+}
+
+// releaseLease stops counting a lease against the lease count quotas
+func (s *QuotaStore) releaseLease(attrs leaseQuotaAttrs) {
+	if !s.hasLeaseCountQuotas() {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.countLeaseLocked(attrs, -1)
+}
+
+func (s *QuotaStore) countLeaseLocked(attrs leaseQuotaAttrs, delta int) {
+	for _, quota := range s.leaseCount {
+		if !quota.matches(attrs) {
+			continue
+		}
+		// Leases registered while a quota is created may be released
+		// without having been counted against it
+		if quota.count += delta; quota.count < 0 {
+			quota.count = 0
+		}
+	}
+}
+
+// emitMetrics reports the usage of the lease count quotas
+func (s *QuotaStore) emitMetrics() {
+	if s == nil {
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, quota := range s.leaseCount {
+		metrics.SetGaugeWithLabels([]string{"quota", "lease_count", "leases"}, float32(quota.count), quotaMetricLabels(quota.Name))
+		metrics.SetGaugeWithLabels([]string{"quota", "lease_count", "max"}, float32(quota.MaxLeases), quotaMetricLabels(quota.Name))
+	}
+}
+
+func quotaMetricLabels(name string) []metrics.Label {
+	return []metrics.Label{{Name: "name", Value: name}}
+}
+
+// quotaRoleName returns the role a token was issued against: its token store
+// role, or the role recorded in its metadata by role based auth methods
+func quotaRoleName(path, role string, meta map[string]string) string {
+	switch {
+	case role != "":
+		return role
+	case strings.HasPrefix(path, "auth/token/create/"):
+		return strings.SplitN(strings.TrimPrefix(path, "auth/token/create/"), "/", 2)[0]
+	case meta["role"] != "":
+		return meta["role"]
+	default:
+		return meta["role_name"]
+	}
+}
@@ -0,0 +1,390 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/errwrap"
+	sockaddr "github.com/hashicorp/go-sockaddr"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/cidrutil"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitQuotaPrefix is the storage prefix of the rate limit quotas,
+	// relative to coreQuotasPath
+	rateLimitQuotaPrefix = "rate-limit/"
+
+	// quotaConfigPath is the storage path of the quota configuration,
+	// relative to coreQuotasPath
+	quotaConfigPath = "config"
+)
+
+// defaultRateLimitExemptPaths are the paths that are not subject to rate
+// limit quotas unless configured otherwise. They keep the health and seal
+// endpoints reachable, and allow operators to fix a quota that locks clients
+// out.
+var defaultRateLimitExemptPaths = []string{
+	"sys/generate-root/",
+	"sys/health",
+	"sys/init",
+	"sys/leader",
+	"sys/quotas/",
+	"sys/seal-status",
+	"sys/unseal",
+}
+
+// quotaConfig holds the settings that apply to all the quotas
+type quotaConfig struct {
+	// RateLimitExemptPaths are the paths, relative to the root namespace,
+	// that are not subject to rate limit quotas. A path ending in "/" exempts
+	// every path under it.
+	RateLimitExemptPaths []string `json:"rate_limit_exempt_paths"`
+}
+
+// RateLimitQuota limits the rate of the requests made to a mount or
+// namespace with a token bucket, optionally narrowed down to the clients of
+// an auth role, an entity or a set of source addresses
+type RateLimitQuota struct {
+	Name string `json:"name"`
+
+	// Path is the mount or namespace path the quota applies to, relative to
+	// the root namespace. An empty path applies the quota to every request.
+	Path string `json:"path"`
+
+	// Role, if set, restricts the quota to the requests of tokens issued
+	// against this role, and to the logins against it
+	Role string `json:"role"`
+
+	// EntityID, if set, restricts the quota to the requests of this entity
+	EntityID string `json:"entity_id"`
+
+	// SourceCIDRs, if set, restricts the quota to the requests coming from
+	// these addresses
+	SourceCIDRs []*sockaddr.SockAddrMarshaler `json:"source_cidrs"`
+
+	// Rate is the number of requests allowed per Interval, and Burst the
+	// number of requests that can be made at once
+	Rate     int           `json:"rate"`
+	Interval time.Duration `json:"interval"`
+	Burst    int           `json:"burst"`
+
+	limiter *rate.Limiter
+}
+
+// limit returns the rate of the quota in requests per second
+func (q *RateLimitQuota) limit() rate.Limit {
+	return rate.Limit(float64(q.Rate) / q.Interval.Seconds())
+}
+
+// rateLimitQuotaAttrs are the attributes of a request that rate limit quotas
+// are matched against
+type rateLimitQuotaAttrs struct {
+	mountPath  string
+	remoteAddr string
+	role       string
+	entityID   string
+}
+
+// matchesRequest returns true if a request with the given mount path and
+// source address may count against the quota, depending on its role and
+// entity
+func (q *RateLimitQuota) matchesRequest(attrs rateLimitQuotaAttrs) bool {
+	if !strings.HasPrefix(attrs.mountPath, q.Path) {
+		return false
+	}
+	if len(q.SourceCIDRs) > 0 && !cidrutil.RemoteAddrIsOk(attrs.remoteAddr, q.SourceCIDRs) {
+		return false
+	}
+	return true
+}
+
+// matchesClient returns true if a request made by the given role and entity
+// counts against the quota
+func (q *RateLimitQuota) matchesClient(attrs rateLimitQuotaAttrs) bool {
+	if q.Role != "" && attrs.role != q.Role {
+		return false
+	}
+	if q.EntityID != "" && attrs.entityID != q.EntityID {
+		return false
+	}
+	return true
+}
+
+// loadRateLimitQuotas loads the rate limit quotas from storage
+func (s *QuotaStore) loadRateLimitQuotas(ctx context.Context) error {
+	keys, err := s.view.List(ctx, rateLimitQuotaPrefix)
+	if err != nil {
+		return errwrap.Wrapf("failed to list rate limit quotas: {{err}}", err)
+	}
+	for _, key := range keys {
+		entry, err := s.view.Get(ctx, rateLimitQuotaPrefix+key)
+		if err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to read rate limit quota %q: {{err}}", key), err)
+		}
+		if entry == nil {
+			continue
+		}
+		quota := new(RateLimitQuota)
+		if err := jsonutil.DecodeJSON(entry.Value, quota); err != nil {
+			return errwrap.Wrapf(fmt.Sprintf("failed to decode rate limit quota %q: {{err}}", key), err)
+		}
+		s.putRateLimitQuotaLocked(quota)
+	}
+	return nil
+}
+
+// loadConfig loads the quota configuration from storage
+func (s *QuotaStore) loadConfig(ctx context.Context) error {
+	entry, err := s.view.Get(ctx, quotaConfigPath)
+	if err != nil {
+		return errwrap.Wrapf("failed to read quota configuration: {{err}}", err)
+	}
+	if entry == nil {
+		return nil
+	}
+	config := new(quotaConfig)
+	if err := jsonutil.DecodeJSON(entry.Value, config); err != nil {
+		return errwrap.Wrapf("failed to decode quota configuration: {{err}}", err)
+	}
+	s.config = config
+	return nil
+}
+
+// ListRateLimitQuotas returns the names of the rate limit quotas
+func (s *QuotaStore) ListRateLimitQuotas() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	names := make([]string, 0, len(s.rateLimit))
+	for name := range s.rateLimit {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetRateLimitQuota returns a copy of the named rate limit quota, or nil if
+// it doesn't exist
+func (s *QuotaStore) GetRateLimitQuota(name string) *RateLimitQuota {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	quota, ok := s.rateLimit[name]
+	if !ok {
+		return nil
+	}
+	ret := *quota
+	ret.limiter = nil
+	return &ret
+}
+
+// SetRateLimitQuota creates or replaces a rate limit quota. When a quota is
+// replaced with the same burst, the requests already made within its bucket
+// still count.
+func (s *QuotaStore) SetRateLimitQuota(ctx context.Context, quota *RateLimitQuota) error {
+	entry, err := logical.StorageEntryJSON(rateLimitQuotaPrefix+quota.Name, quota)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.view.Put(ctx, entry); err != nil {
+		return errwrap.Wrapf("failed to persist rate limit quota: {{err}}", err)
+	}
+
+	s.putRateLimitQuotaLocked(quota)
+	return nil
+}
+
+// putRateLimitQuotaLocked stores a copy of the quota in memory, keeping the
+// token bucket of the quota it replaces unless its size changed
+func (s *QuotaStore) putRateLimitQuotaLocked(quota *RateLimitQuota) {
+	stored := *quota
+	if existing, ok := s.rateLimit[quota.Name]; ok && existing.Burst == stored.Burst {
+		stored.limiter = existing.limiter
+		stored.limiter.SetLimit(stored.limit())
+	} else {
+		stored.limiter = rate.NewLimiter(stored.limit(), stored.Burst)
+	}
+	s.rateLimit[quota.Name] = &stored
+}
+
+// DeleteRateLimitQuota removes a rate limit quota
+func (s *QuotaStore) DeleteRateLimitQuota(ctx context.Context, name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.view.Delete(ctx, rateLimitQuotaPrefix+name); err != nil {
+		return errwrap.Wrapf("failed to delete rate limit quota: {{err}}", err)
+	}
+	delete(s.rateLimit, name)
+	return nil
+}
+
+// RateLimitExemptPaths returns the paths that are not subject to rate limit
+// quotas
+func (s *QuotaStore) RateLimitExemptPaths() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.config == nil {
+		return defaultRateLimitExemptPaths
+	}
+	return s.config.RateLimitExemptPaths
+}
+
+// SetRateLimitExemptPaths replaces the paths that are not subject to rate
+// limit quotas
+func (s *QuotaStore) SetRateLimitExemptPaths(ctx context.Context, paths []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	config := &quotaConfig{
+		RateLimitExemptPaths: paths,
+	}
+	entry, err := logical.StorageEntryJSON(quotaConfigPath, config)
+	if err != nil {
+		return err
+	}
+	if err := s.view.Put(ctx, entry); err != nil {
+		return errwrap.Wrapf("failed to persist quota configuration: {{err}}", err)
+	}
+	s.config = config
+	return nil
+}
+
+// hasRateLimitQuotas returns true if any rate limit quota is configured
+func (s *QuotaStore) hasRateLimitQuotas() bool {
+	if s == nil {
+		return false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.rateLimit) > 0
+}
+
+// rateLimitQuotasForRequest returns the rate limit quotas that may apply to a
+// request to the given path, relative to the root namespace, made with the
+// given attributes
+func (s *QuotaStore) rateLimitQuotasForRequest(path string, attrs rateLimitQuotaAttrs) []*RateLimitQuota {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	exempt := defaultRateLimitExemptPaths
+	if s.config != nil {
+		exempt = s.config.RateLimitExemptPaths
+	}
+	for _, exemptPath := range exempt {
+		if path == strings.TrimSuffix(exemptPath, "/") || strings.HasPrefix(path, strings.TrimSuffix(exemptPath, "/")+"/") {
+			return nil
+		}
+	}
+
+	var quotas []*RateLimitQuota
+	for _, quota := range s.rateLimit {
+		if quota.matchesRequest(attrs) {
+			quotas = append(quotas, quota)
+		}
+	}
+	return quotas
+}
+
+// applyRateLimitQuotas takes a request from the token bucket of every rate
+// limit quota matching the request. If any of them is empty, nothing is
+// taken and the request is rejected with an error response telling the
+// client when to retry.
+func (c *Core) applyRateLimitQuotas(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	if !c.quotaStore.hasRateLimitQuotas() {
+		return nil, nil
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := rateLimitQuotaAttrs{
+		mountPath: c.router.MatchingMount(ctx, req.Path),
+	}
+	if req.Connection != nil {
+		attrs.remoteAddr = req.Connection.RemoteAddr
+	}
+
+	quotas := c.quotaStore.rateLimitQuotasForRequest(ns.Path+req.Path, attrs)
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	// The role and entity of the client are only resolved when a quota
+	// depends on them, since it costs a token lookup
+	for _, quota := range quotas {
+		if quota.Role == "" && quota.EntityID == "" {
+			continue
+		}
+		if c.router.LoginPath(ctx, req.Path) {
+			for _, field := range []string{"role", "role_name"} {
+				if role, ok := req.Data[field].(string); ok && role != "" {
+					attrs.role = role
+					break
+				}
+			}
+		} else if req.ClientToken != "" {
+			te, err := c.tokenStore.Lookup(ctx, req.ClientToken)
+			if err != nil {
+				return nil, err
+			}
+			if te != nil {
+				attrs.role = quotaRoleName(te.Path, te.Role, te.Meta)
+				attrs.entityID = te.EntityID
+			}
+		}
+		break
+	}
+
+	now := time.Now()
+	var reservations []*rate.Reservation
+	var rejected *RateLimitQuota
+	var retryAfter time.Duration
+	for _, quota := range quotas {
+		if !quota.matchesClient(attrs) {
+			continue
+		}
+		reservation := quota.limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			metrics.IncrCounterWithLabels([]string{"quota", "rate_limit", "violation"}, 1, quotaMetricLabels(quota.Name))
+			if rejected == nil || delay > retryAfter {
+				rejected = quota
+				retryAfter = delay
+			}
+		}
+	}
+	if rejected == nil {
+		return nil, nil
+	}
+
+	for _, reservation := range reservations {
+		reservation.CancelAt(now)
+	}
+
+	err = errwrap.Wrapf(fmt.Sprintf("request rejected by quota %q: {{err}}", rejected.Name), logical.ErrRateLimitQuotaExceeded)
+	resp := logical.ErrorResponse(err.Error())
+	resp.Headers = map[string][]string{
+		"Retry-After": {strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds()))))},
+	}
+	return resp, err
+}
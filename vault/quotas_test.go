@@ -0,0 +1,389 @@
+package vault
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func testCreateToken(t *testing.T, c *Core, root, path string) (*logical.Response, error) {
+	t.Helper()
+	req := logical.TestRequest(t, logical.UpdateOperation, path)
+	req.ClientToken = root
+	req.Data["ttl"] = "1h"
+	return c.HandleRequest(namespace.RootContext(nil), req)
+}
+
+func testLeaseCountQuotaLeases(t *testing.T, c *Core, root, name string) int {
+	t.Helper()
+	req := logical.TestRequest(t, logical.ReadOperation, "sys/quotas/lease-count/"+name)
+	req.ClientToken = root
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	return resp.Data["leases"].(int)
+}
+
+// testWaitForRestore waits for the leases to be restored, since the existing
+// leases of a quota can't be counted until then
+func testWaitForRestore(t *testing.T, c *Core) {
+	t.Helper()
+	for start := time.Now(); c.expiration.inRestoreMode(); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 10*time.Second {
+			t.Fatal("leases were not restored")
+		}
+	}
+}
+
+func TestQuotas_LeaseCount(t *testing.T) {
+	c, keys, root := TestCoreUnsealed(t)
+	testWaitForRestore(t, c)
+
+	// Existing leases are counted when the quota is created
+	resp, err := testCreateToken(t, c, root, "auth/token/create")
+	if err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	first := resp.Auth.ClientToken
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/lease-count/tokens")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"path":       "auth/token",
+		"max_leases": 2,
+	}
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if leases := testLeaseCountQuotaLeases(t, c, root, "tokens"); leases != 1 {
+		t.Fatalf("expected 1 lease, got %d", leases)
+	}
+
+	if resp, err := testCreateToken(t, c, root, "auth/token/create"); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	resp, err = testCreateToken(t, c, root, "auth/token/create")
+	if !errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()) || resp == nil || !resp.IsError() {
+		t.Fatalf("expected quota error, got err: %v\nresp: %#v", err, resp)
+	}
+	if code, _ := logical.RespondErrorCommon(req, resp, err); code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, code)
+	}
+
+	// The rejected token is revoked, but not the calling token
+	if leases := testLeaseCountQuotaLeases(t, c, root, "tokens"); leases != 2 {
+		t.Fatalf("expected 2 leases, got %d", leases)
+	}
+	if te, err := c.tokenStore.Lookup(namespace.RootContext(nil), root); err != nil || te == nil {
+		t.Fatalf("expected root token to survive, err: %v", err)
+	}
+
+	// Revoking a token frees up the quota
+	req = logical.TestRequest(t, logical.UpdateOperation, "auth/token/revoke")
+	req.ClientToken = root
+	req.Data["token"] = first
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if leases := testLeaseCountQuotaLeases(t, c, root, "tokens"); leases != 1 {
+		t.Fatalf("expected 1 lease, got %d", leases)
+	}
+	if resp, err := testCreateToken(t, c, root, "auth/token/create"); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	// Leases are counted again once restored after an unseal
+	if err := c.Seal(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if _, err := TestCoreUnseal(c, TestKeyCopy(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testWaitForRestore(t, c)
+	if leases := testLeaseCountQuotaLeases(t, c, root, "tokens"); leases != 2 {
+		t.Fatalf("expected 2 leases after unseal, got %d", leases)
+	}
+	resp, err = testCreateToken(t, c, root, "auth/token/create")
+	if !errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()) {
+		t.Fatalf("expected quota error after unseal, got err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.ListOperation, "sys/quotas/lease-count")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != "tokens" {
+		t.Fatalf("bad: keys %v", keys)
+	}
+}
+
+func TestQuotas_LeaseCount_Role(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	testWaitForRestore(t, c)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/roles/ci")
+	req.ClientToken = root
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	// Existing leases are read back to be matched against the role
+	if resp, err := testCreateToken(t, c, root, "auth/token/create/ci"); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/lease-count/ci")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"role":       "ci",
+		"max_leases": 2,
+	}
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if leases := testLeaseCountQuotaLeases(t, c, root, "ci"); leases != 1 {
+		t.Fatalf("expected 1 lease, got %d", leases)
+	}
+
+	if resp, err := testCreateToken(t, c, root, "auth/token/create/ci"); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp, err := testCreateToken(t, c, root, "auth/token/create/ci"); !errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()) {
+		t.Fatalf("expected quota error, got err: %v\nresp: %#v", err, resp)
+	}
+
+	// Tokens issued without the role are not affected
+	if resp, err := testCreateToken(t, c, root, "auth/token/create"); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if leases := testLeaseCountQuotaLeases(t, c, root, "ci"); leases != 2 {
+		t.Fatalf("expected 2 leases, got %d", leases)
+	}
+}
+
+func TestQuotas_LeaseCount_Validation(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	testWaitForRestore(t, c)
+
+	cases := map[string]map[string]interface{}{
+		"unknown path":      {"path": "nonexistent", "max_leases": 1},
+		"missing max":       {"path": "secret"},
+		"negative max":      {"max_leases": -1},
+		"path within mount": {"path": "secret/foo", "max_leases": 1},
+	}
+	for name, data := range cases {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/lease-count/bad")
+		req.ClientToken = root
+		req.Data = data
+		resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+		if !errwrap.Contains(err, logical.ErrInvalidRequest.Error()) || resp == nil || !resp.IsError() {
+			t.Fatalf("%s: expected invalid request, got err: %v\nresp: %#v", name, err, resp)
+		}
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/lease-count/secret")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"path":       "/secret",
+		"max_leases": 10,
+	}
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	// Updates only change the given fields
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/lease-count/secret")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"max_leases": 20,
+	}
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/quotas/lease-count/secret")
+	req.ClientToken = root
+	resp, err := c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp.Data["path"] != "secret/" || resp.Data["max_leases"] != 20 {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/quotas/lease-count/secret")
+	req.ClientToken = root
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/quotas/lease-count/secret")
+	req.ClientToken = root
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || resp != nil {
+		t.Fatalf("expected quota to be deleted, got err: %v\nresp: %#v", err, resp)
+	}
+}
+
+func TestQuotas_RateLimit(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/rate-limit/tokens")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"path":     "auth/token",
+		"rate":     2,
+		"interval": "1h",
+	}
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	lookupSelf := func() (*logical.Response, error) {
+		req := logical.TestRequest(t, logical.ReadOperation, "auth/token/lookup-self")
+		req.ClientToken = root
+		return c.HandleRequest(namespace.RootContext(nil), req)
+	}
+
+	for i := 0; i < 2; i++ {
+		if resp, err := lookupSelf(); err != nil || resp.IsError() {
+			t.Fatalf("err: %v\nresp: %#v", err, resp)
+		}
+	}
+	resp, err := lookupSelf()
+	if !errwrap.Contains(err, logical.ErrRateLimitQuotaExceeded.Error()) || resp == nil || !resp.IsError() {
+		t.Fatalf("expected quota error, got err: %v\nresp: %#v", err, resp)
+	}
+	if code, _ := logical.RespondErrorCommon(req, resp, err); code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, code)
+	}
+	if retryAfter := resp.Headers["Retry-After"]; len(retryAfter) != 1 || retryAfter[0] == "0" {
+		t.Fatalf("bad: Retry-After %v", retryAfter)
+	}
+
+	// Other mounts are not affected, and quotas can still be managed
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/mounts")
+	req.ClientToken = root
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/quotas/rate-limit/tokens")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp.Data["path"] != "auth/token/" || resp.Data["rate"] != 2 || resp.Data["burst"] != 2 || resp.Data["interval"] != int64(3600) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Exempt paths are not rate limited
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/config")
+	req.ClientToken = root
+	req.Data["rate_limit_exempt_paths"] = "sys/quotas/,auth/token/lookup-self"
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp, err := lookupSelf(); err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/quotas/config")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if paths := resp.Data["rate_limit_exempt_paths"].([]string); len(paths) != 2 || paths[1] != "auth/token/lookup-self" {
+		t.Fatalf("bad: exempt paths %v", paths)
+	}
+}
+
+func TestQuotas_RateLimit_Client(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/roles/ci")
+	req.ClientToken = root
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	resp, err := testCreateToken(t, c, root, "auth/token/create/ci")
+	if err != nil || resp.IsError() {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	ciToken := resp.Auth.ClientToken
+
+	for name, data := range map[string]map[string]interface{}{
+		"ci": {
+			"role":     "ci",
+			"rate":     1,
+			"interval": "1h",
+		},
+		"office": {
+			"source_cidrs": "10.0.0.0/8",
+			"rate":         1,
+			"interval":     "1h",
+		},
+	} {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/rate-limit/"+name)
+		req.ClientToken = root
+		req.Data = data
+		if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v\nresp: %#v", err, resp)
+		}
+	}
+
+	lookupSelf := func(token, remoteAddr string) error {
+		req := logical.TestRequest(t, logical.ReadOperation, "auth/token/lookup-self")
+		req.ClientToken = token
+		req.Connection = &logical.Connection{RemoteAddr: remoteAddr}
+		_, err := c.HandleRequest(namespace.RootContext(nil), req)
+		return err
+	}
+
+	// Each quota only applies to its own clients
+	if err := lookupSelf(ciToken, "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lookupSelf(ciToken, "127.0.0.1"); !errwrap.Contains(err, logical.ErrRateLimitQuotaExceeded.Error()) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if err := lookupSelf(root, "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lookupSelf(root, "10.1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lookupSelf(root, "10.1.2.3"); !errwrap.Contains(err, logical.ErrRateLimitQuotaExceeded.Error()) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if err := lookupSelf(root, "192.168.1.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A request rejected by one quota does not count against the others
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/quotas/rate-limit/ci")
+	req.ClientToken = root
+	req.Data["rate"] = 2
+	req.Data["burst"] = 2
+	if resp, err := c.HandleRequest(namespace.RootContext(nil), req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if err := lookupSelf(ciToken, "10.1.2.3"); !errwrap.Contains(err, logical.ErrRateLimitQuotaExceeded.Error()) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := lookupSelf(ciToken, "127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package vault
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -113,6 +115,16 @@ func (c *Core) fetchEntityAndDerivedPolicies(ctx context.Context, tokenNS *names
 	return entity, policies, err
 }
 
+// clientCertFingerprint returns the hex-encoded SHA-256 fingerprint of the
+// TLS client certificate presented on the connection, if any
+func clientCertFingerprint(conn *logical.Connection) string {
+	if conn == nil || conn.ConnState == nil || len(conn.ConnState.PeerCertificates) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(conn.ConnState.PeerCertificates[0].Raw)
+	return hex.EncodeToString(sum[:])
+}
+
 func (c *Core) fetchACLTokenEntryAndEntity(ctx context.Context, req *logical.Request) (*ACL, *logical.TokenEntry, *identity.Entity, map[string][]string, error) {
 	defer metrics.MeasureSince([]string{"core", "fetch_acl_and_token"}, time.Now())
 
@@ -168,6 +180,15 @@ func (c *Core) fetchACLTokenEntryAndEntity(ctx context.Context, req *logical.Req
 		}
 	}
 
+	// Certificate bound tokens must be presented over a connection using the
+	// client certificate they were issued to
+	if te.BoundCertFingerprint != "" && clientCertFingerprint(req.Connection) != te.BoundCertFingerprint {
+		if c.Logger().IsDebug() {
+			c.Logger().Debug("client certificate does not match the certificate the token is bound to", "accessor", te.Accessor)
+		}
+		return nil, nil, nil, nil, logical.ErrPermissionDenied
+	}
+
 	policies := make(map[string][]string)
 	// Add tokens policies
 	policies[te.NamespaceID] = append(policies[te.NamespaceID], te.Policies...)
@@ -271,6 +292,11 @@ func (c *Core) checkToken(ctx context.Context, req *logical.Request, unauth bool
 	case logical.ClientTokenFromVaultHeader:
 		delete(req.Headers, consts.AuthHeaderName)
 	case logical.ClientTokenFromAuthzHeader:
+		// A bearer token that isn't a Vault token on an unauthenticated
+		// path is meant for the backend, e.g. an OIDC access token
+		if unauth && te == nil {
+			break
+		}
 		if headers, ok := req.Headers["Authorization"]; ok {
 			retHeaders := make([]string, 0, len(headers))
 			for _, v := range headers {
@@ -434,6 +460,12 @@ func (c *Core) handleCancelableRequest(ctx context.Context, ns *namespace.Namesp
 		return logical.ErrorResponse("cannot write to a path ending in '/'"), nil
 	}
 
+	// Reject the request before doing any work for it if the client is over
+	// a rate limit quota
+	if resp, err := c.applyRateLimitQuotas(ctx, req); resp != nil || err != nil {
+		return resp, err
+	}
+
 	err = waitForReplicationState(ctx, c, req)
 	if err != nil {
 		return nil, err
@@ -443,6 +475,10 @@ func (c *Core) handleCancelableRequest(ctx context.Context, ns *namespace.Namesp
 		return nil, logical.CodedError(403, "namespaces feature not enabled")
 	}
 
+	if ns.ID != namespace.RootNamespaceID && namespaceRestrictedPaths.HasPath(req.Path) {
+		return nil, logical.CodedError(400, fmt.Sprintf("path %q is only available in the root namespace", req.Path))
+	}
+
 	var auth *logical.Auth
 	if c.router.LoginPath(ctx, req.Path) {
 		resp, auth, err = c.handleLoginRequest(ctx, req)
@@ -812,6 +848,10 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 
 			leaseID, err := registerFunc(ctx, req, resp)
 			if err != nil {
+				if errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()) {
+					c.logger.Warn("lease count quota exceeded", "request_path", req.Path, "error", err)
+					return logical.ErrorResponse(err.Error()), auth, err
+				}
 				c.logger.Error("failed to register lease", "request_path", req.Path, "error", err)
 				retErr = multierror.Append(retErr, ErrInternalError)
 				return nil, auth, retErr
@@ -870,6 +910,13 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 				Path:        resp.Auth.CreationPath,
 				NamespaceID: ns.ID,
 			}, resp.Auth); err != nil {
+				if errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()) {
+					// Only the newly created token is revoked, the calling
+					// token is not at fault
+					c.tokenStore.revokeOrphan(ctx, resp.Auth.ClientToken)
+					c.logger.Warn("lease count quota exceeded", "request_path", req.Path, "error", err)
+					return logical.ErrorResponse(err.Error()), auth, err
+				}
 				c.tokenStore.revokeOrphan(ctx, te.ID)
 				c.logger.Error("failed to register token lease", "request_path", req.Path, "error", err)
 				retErr = multierror.Append(retErr, ErrInternalError)
@@ -1125,6 +1172,16 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 			}
 		}
 
+		if auth.BindClientCert {
+			if auth.TokenType == logical.TokenTypeBatch {
+				return logical.ErrorResponse("batch tokens cannot be bound to client certificates"), nil, logical.ErrInvalidRequest
+			}
+			auth.BoundCertFingerprint = clientCertFingerprint(req.Connection)
+			if auth.BoundCertFingerprint == "" {
+				return logical.ErrorResponse("a TLS client certificate is required to issue a token bound to it"), nil, logical.ErrInvalidRequest
+			}
+		}
+
 		var registerFunc RegisterAuthFunc
 		var funcGetErr error
 		// Batch tokens should not be forwarded to perf standby
@@ -1143,6 +1200,8 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 		case err == nil:
 		case err == ErrInternalError:
 			return nil, auth, err
+		case errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()):
+			return logical.ErrorResponse(err.Error()), auth, err
 		default:
 			return logical.ErrorResponse(err.Error()), auth, logical.ErrInvalidRequest
 		}
@@ -1173,18 +1232,20 @@ func (c *Core) RegisterAuth(ctx context.Context, tokenTTL time.Duration, path st
 		return err
 	}
 	te := logical.TokenEntry{
-		Path:           path,
-		Meta:           auth.Metadata,
-		DisplayName:    auth.DisplayName,
-		CreationTime:   time.Now().Unix(),
-		TTL:            tokenTTL,
-		NumUses:        auth.NumUses,
-		EntityID:       auth.EntityID,
-		BoundCIDRs:     auth.BoundCIDRs,
-		Policies:       auth.TokenPolicies,
-		NamespaceID:    ns.ID,
-		ExplicitMaxTTL: auth.ExplicitMaxTTL,
-		Type:           auth.TokenType,
+		Path:                 path,
+		Meta:                 auth.Metadata,
+		DisplayName:          auth.DisplayName,
+		CreationTime:         time.Now().Unix(),
+		TTL:                  tokenTTL,
+		NumUses:              auth.NumUses,
+		EntityID:             auth.EntityID,
+		BoundCIDRs:           auth.BoundCIDRs,
+		Policies:             auth.TokenPolicies,
+		NamespaceID:          ns.ID,
+		ExplicitMaxTTL:       auth.ExplicitMaxTTL,
+		Type:                 auth.TokenType,
+		MFAValidated:         auth.MFAValidated,
+		BoundCertFingerprint: auth.BoundCertFingerprint,
 	}
 
 	if err := c.tokenStore.create(ctx, &te); err != nil {
@@ -1206,6 +1267,10 @@ func (c *Core) RegisterAuth(ctx context.Context, tokenTTL time.Duration, path st
 		// Register with the expiration manager
 		if err := c.expiration.RegisterAuth(ctx, &te, auth); err != nil {
 			c.tokenStore.revokeOrphan(ctx, te.ID)
+			if errwrap.Contains(err, logical.ErrLeaseCountQuotaExceeded.Error()) {
+				c.logger.Warn("lease count quota exceeded", "request_path", path, "error", err)
+				return err
+			}
 			c.logger.Error("failed to register token lease", "request_path", path, "error", err)
 			return ErrInternalError
 		}
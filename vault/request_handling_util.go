@@ -11,12 +11,37 @@ import (
 
 func waitForReplicationState(context.Context, *Core, *logical.Request) error { return nil }
 
-func checkNeedsCG(context.Context, *Core, *logical.Request, *logical.Auth, error, []string) (error, *logical.Response, *logical.Auth, error) {
-	return nil, nil, nil, nil
+// checkNeedsCG holds requests refused because they require control group
+// authorization. The request is stored along with a new control group token,
+// returned to the client as wrapping information, and run when the client
+// unwraps the token once the request is approved.
+func checkNeedsCG(ctx context.Context, c *Core, req *logical.Request, auth *logical.Auth, err error, nonHMACReqDataKeys []string) (error, *logical.Response, *logical.Auth, error) {
+	cgErr := controlGroupErrorFrom(err)
+	if cgErr == nil {
+		return nil, nil, nil, nil
+	}
+
+	// The original request is audited here as it doesn't go any further; the
+	// response holding the control group token is audited by the caller
+	logInput := &logical.LogInput{
+		Auth:               auth,
+		Request:            req,
+		NonHMACReqDataKeys: nonHMACReqDataKeys,
+	}
+	if err := c.auditBroker.LogRequest(ctx, logInput, c.auditedHeaders); err != nil {
+		c.logger.Error("failed to audit request", "path", req.Path, "error", err)
+		return nil, nil, auth, ErrInternalError
+	}
+
+	resp, err := c.createControlGroupRequest(ctx, req, auth, cgErr.controlGroup)
+	if err != nil {
+		return nil, nil, auth, err
+	}
+	return nil, resp, auth, nil
 }
 
 func checkErrControlGroupTokenNeedsCreated(err error) bool {
-	return false
+	return controlGroupErrorFrom(err) != nil
 }
 
 func shouldForward(c *Core, routeErr error) bool {
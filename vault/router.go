@@ -128,7 +128,7 @@ func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *Mount
 		storageView:   storageView,
 	}
 	re.rootPaths.Store(pathsToRadix(paths.Root))
-	re.loginPaths.Store(pathsToRadix(paths.Unauthenticated))
+	re.loginPaths.Store(parseLoginPaths(paths.Unauthenticated))
 
 	switch {
 	case prefix == "":
@@ -774,8 +774,14 @@ func (r *Router) LoginPath(ctx context.Context, path string) bool {
 	remain := strings.TrimPrefix(adjustedPath, mount)
 
 	// Check the loginPaths of this backend
-	loginPaths := re.loginPaths.Load().(*radix.Tree)
-	match, raw, ok := loginPaths.LongestPrefix(remain)
+	loginPaths := re.loginPaths.Load().(*loginPathsEntry)
+	for _, wildcardPath := range loginPaths.wildcardPaths {
+		if matchWildcardPath(wildcardPath, remain) {
+			return true
+		}
+	}
+
+	match, raw, ok := loginPaths.paths.LongestPrefix(remain)
 	if !ok {
 		return false
 	}
@@ -790,6 +796,62 @@ func (r *Router) LoginPath(ctx context.Context, path string) bool {
 	return match == remain
 }
 
+// loginPathsEntry holds the unauthenticated paths of a backend. Paths with a
+// "+" segment, which matches any single path segment, can't be looked up in
+// the radix tree and are kept separately.
+type loginPathsEntry struct {
+	paths         *radix.Tree
+	wildcardPaths []string
+}
+
+func parseLoginPaths(paths []string) *loginPathsEntry {
+	var plainPaths, wildcardPaths []string
+	for _, path := range paths {
+		if strutil.StrListContains(strings.Split(path, "/"), "+") {
+			wildcardPaths = append(wildcardPaths, path)
+			continue
+		}
+		plainPaths = append(plainPaths, path)
+	}
+
+	return &loginPathsEntry{
+		paths:         pathsToRadix(plainPaths),
+		wildcardPaths: wildcardPaths,
+	}
+}
+
+// matchWildcardPath reports whether the path matches the special path
+// pattern, where a "+" segment matches any single non-empty segment and a
+// trailing "*" matches any suffix.
+func matchWildcardPath(pattern, path string) bool {
+	prefixMatch := strings.HasSuffix(pattern, "*")
+	pattern = strings.TrimSuffix(pattern, "*")
+
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(pathSegments) < len(patternSegments) || (!prefixMatch && len(pathSegments) != len(patternSegments)) {
+		return false
+	}
+
+	last := len(patternSegments) - 1
+	for i, segment := range patternSegments {
+		switch {
+		case segment == "+":
+			if pathSegments[i] == "" {
+				return false
+			}
+		case i == last && prefixMatch:
+			if !strings.HasPrefix(pathSegments[i], segment) {
+				return false
+			}
+		case segment != pathSegments[i]:
+			return false
+		}
+	}
+
+	return true
+}
+
 // pathsToRadix converts a list of special paths to a radix tree.
 func pathsToRadix(paths []string) *radix.Tree {
 	tree := radix.New()
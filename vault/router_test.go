@@ -348,6 +348,8 @@ func TestRouter_LoginPath(t *testing.T) {
 		Login: []string{
 			"login",
 			"oauth/*",
+			"role/+/login",
+			"provider/+/.well-known/*",
 		},
 	}
 	err = r.Mount(n, "auth/foo/", &MountEntry{UUID: meUUID, Accessor: "authfooaccessor", NamespaceID: namespace.RootNamespaceID, namespace: namespace.RootNamespace}, view)
@@ -365,6 +367,14 @@ func TestRouter_LoginPath(t *testing.T) {
 		{"auth/foo/login", true},
 		{"auth/foo/oauth", false},
 		{"auth/foo/oauth/redirect", true},
+		{"auth/foo/role/test/login", true},
+		{"auth/foo/role/test", false},
+		{"auth/foo/role//login", false},
+		{"auth/foo/role/test/login/extra", false},
+		{"auth/foo/role/a/b/login", false},
+		{"auth/foo/provider/test/.well-known/keys", true},
+		{"auth/foo/provider/test/.well-known/openid-configuration", true},
+		{"auth/foo/provider/test/authorize", false},
 	}
 
 	for _, tc := range tcases {
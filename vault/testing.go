@@ -1683,6 +1683,8 @@ func (m *mockBuiltinRegistry) Keys(pluginType consts.PluginType) []string {
 		"mongodb-database-plugin",
 		"hana-database-plugin",
 		"influxdb-database-plugin",
+		"redis-database-plugin",
+		"sql-database-plugin",
 	}
 }
 
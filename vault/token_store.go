@@ -36,6 +36,8 @@ import (
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/sdk/plugin/pb"
 	"github.com/mitchellh/mapstructure"
+	cache "github.com/patrickmn/go-cache"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -98,7 +100,15 @@ var (
 			if te.CubbyholeID == "" {
 				return fmt.Errorf("missing cubbyhole ID while destroying")
 			}
-			return ts.cubbyholeBackend.revoke(ctx, te.CubbyholeID)
+			cubbyholeBackend, err := ts.namespaceCubbyhole(ctx, te.NamespaceID)
+			if err != nil {
+				return err
+			}
+			if cubbyholeBackend == nil {
+				// The namespace is being deleted along with its cubbyhole
+				return nil
+			}
+			return cubbyholeBackend.revoke(ctx, te.CubbyholeID)
 		}
 	}
 )
@@ -127,6 +137,32 @@ func (ts *TokenStore) paths() []*framework.Path {
 			HelpDescription: tokenListAccessorsHelp,
 		},
 
+		{
+			Pattern: "search$",
+
+			Fields: tokenSearchFields(),
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: ts.handleSearch,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenSearchHelp),
+			HelpDescription: strings.TrimSpace(tokenSearchDesc),
+		},
+
+		{
+			Pattern: "revoke-search$",
+
+			Fields: tokenSearchFields(),
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: ts.handleRevokeSearch,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenRevokeSearchHelp),
+			HelpDescription: strings.TrimSpace(tokenRevokeSearchDesc),
+		},
+
 		{
 			Pattern: "create-orphan$",
 
@@ -409,7 +445,7 @@ func (ts *TokenStore) paths() []*framework.Path {
 		ExistenceCheck: ts.tokenStoreRoleExistenceCheck,
 	}
 
-	tokenutil.AddTokenFieldsWithAllowList(rolesPath.Fields, []string{"token_bound_cidrs", "token_explicit_max_ttl", "token_period", "token_type", "token_no_default_policy", "token_num_uses"})
+	tokenutil.AddTokenFieldsWithAllowList(rolesPath.Fields, []string{"token_bound_cidrs", "token_explicit_max_ttl", "token_period", "token_type", "token_no_default_policy", "token_num_uses", "token_bind_client_cert"})
 	p = append(p, rolesPath)
 
 	return p
@@ -478,6 +514,14 @@ type TokenStore struct {
 
 	tidyLock *uint32
 
+	// bulkRevokeLimiter paces revocations made through the revoke-search
+	// endpoint
+	bulkRevokeLimiter *rate.Limiter
+
+	// searchCursors holds the accessor index listings of the searches being
+	// paged through
+	searchCursors *cache.Cache
+
 	identityPoliciesDeriverFunc func(string) (*identity.Entity, []string, error)
 
 	quitContext context.Context
@@ -505,6 +549,8 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 		tokensPendingDeletion: &sync.Map{},
 		saltLock:              sync.RWMutex{},
 		tidyLock:              new(uint32),
+		bulkRevokeLimiter:     newTokenBulkRevokeLimiter(),
+		searchCursors:         newTokenSearchCursors(),
 		quitContext:           core.activeContext,
 		salts:                 make(map[string]*salt.Salt),
 	}
@@ -517,6 +563,8 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 			Root: []string{
 				"revoke-orphan/*",
 				"accessors*",
+				"search",
+				"revoke-search",
 			},
 
 			// Most token store items are local since tokens are local, but a
@@ -2417,6 +2465,13 @@ func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Reque
 			te.BoundCIDRs = role.TokenBoundCIDRs
 		}
 
+		if role.TokenBindClientCert {
+			te.BoundCertFingerprint = clientCertFingerprint(req.Connection)
+			if te.BoundCertFingerprint == "" {
+				return logical.ErrorResponse("a TLS client certificate is required to create a token bound to it"), logical.ErrInvalidRequest
+			}
+		}
+
 	case data.NoParent:
 		// Only allow an orphan token if the client has sudo policy
 		if !isSudo {
@@ -2439,15 +2494,20 @@ func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Reque
 	if te.Parent != "" {
 		te.EntityID = parent.EntityID
 
-		// If the parent has bound CIDRs, copy those into the child. We don't
-		// do this if role is not nil because then we always use the role's
-		// bound CIDRs; roles allow escalation of privilege in proper
-		// circumstances.
+		// If the parent has bound CIDRs or a bound client certificate, copy
+		// those into the child. We don't do this if role is not nil because
+		// then we always use the role's bindings; roles allow escalation of
+		// privilege in proper circumstances.
 		if role == nil {
 			te.BoundCIDRs = parent.BoundCIDRs
+			te.BoundCertFingerprint = parent.BoundCertFingerprint
 		}
 	}
 
+	if te.BoundCertFingerprint != "" && te.Type == logical.TokenTypeBatch {
+		return logical.ErrorResponse("batch tokens cannot be bound to client certificates"), logical.ErrInvalidRequest
+	}
+
 	var explicitMaxTTLToUse time.Duration
 	if data.ExplicitMaxTTL != "" {
 		dur, err := parseutil.ParseDurationSecond(data.ExplicitMaxTTL)
@@ -2580,14 +2640,15 @@ func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Reque
 			TTL:       te.TTL,
 			Renewable: renewable,
 		},
-		ClientToken:    te.ID,
-		Accessor:       te.Accessor,
-		EntityID:       te.EntityID,
-		Period:         periodToUse,
-		ExplicitMaxTTL: explicitMaxTTLToUse,
-		CreationPath:   te.Path,
-		TokenType:      te.Type,
-		Orphan:         te.Parent == "",
+		ClientToken:          te.ID,
+		Accessor:             te.Accessor,
+		EntityID:             te.EntityID,
+		Period:               periodToUse,
+		ExplicitMaxTTL:       explicitMaxTTLToUse,
+		CreationPath:         te.Path,
+		TokenType:            te.Type,
+		Orphan:               te.Parent == "",
+		BoundCertFingerprint: te.BoundCertFingerprint,
 	}
 
 	for _, p := range te.Policies {
@@ -2768,6 +2829,10 @@ func (ts *TokenStore) handleLookup(ctx context.Context, req *logical.Request, da
 		resp.Data["bound_cidrs"] = out.BoundCIDRs
 	}
 
+	if out.BoundCertFingerprint != "" {
+		resp.Data["bound_cert_fingerprint"] = out.BoundCertFingerprint
+	}
+
 	tokenNS, err := NamespaceByID(ctx, out.NamespaceID, ts.core)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
@@ -2990,6 +3055,9 @@ func (ts *TokenStore) tokenStoreRoleRead(ctx context.Context, req *logical.Reque
 	if role.TokenNumUses > 0 {
 		resp.Data["token_num_uses"] = role.TokenNumUses
 	}
+	if role.TokenBindClientCert {
+		resp.Data["token_bind_client_cert"] = true
+	}
 
 	return resp, nil
 }
@@ -0,0 +1,543 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/errwrap"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
+	"github.com/hashicorp/vault/sdk/helper/parseutil"
+	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	cache "github.com/patrickmn/go-cache"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// tokenSearchDefaultLimit is the number of results returned by a search
+	// or bulk revocation when no limit is given
+	tokenSearchDefaultLimit = 100
+
+	// tokenSearchMaxLimit caps the number of results returned by a single
+	// search or bulk revocation request
+	tokenSearchMaxLimit = 1000
+
+	// tokenBulkRevokeRate is the number of tokens per second that bulk
+	// revocation will revoke across all requests on a node
+	tokenBulkRevokeRate = 50
+
+	// tokenSearchCursorTTL is how long the accessor listing of a search is
+	// kept after its last page was returned
+	tokenSearchCursorTTL = 5 * time.Minute
+)
+
+var (
+	// tokenSearchScanBudget caps the number of accessor index entries a
+	// single search or bulk revocation request examines, whether or not
+	// they match, so that selective filters don't walk the whole index at
+	// once
+	tokenSearchScanBudget = 10000
+
+	// tokenSearchMaxCursors caps the number of search listings kept at once.
+	// Searches started past it page through the index by listing it again
+	// for every page.
+	tokenSearchMaxCursors = 100
+)
+
+// tokenSearchCursor holds the sorted accessor index listing a search pages
+// through, so that its pages don't each list the whole index again
+type tokenSearchCursor struct {
+	namespaceID     string
+	saltedAccessors []string
+}
+
+// tokenSearchFilter holds the criteria a token entry must match to be
+// returned by a search or a bulk revocation. Zero values do not filter.
+type tokenSearchFilter struct {
+	RoleName      string
+	Policies      []string
+	EntityID      string
+	DisplayName   string
+	Meta          map[string]string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+}
+
+// tokenSearchMatch is a token entry matching a search, along with its
+// expiration time if it has one
+type tokenSearchMatch struct {
+	entry      *logical.TokenEntry
+	expireTime time.Time
+}
+
+func tokenSearchFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"role_name": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens created against this token role.",
+		},
+		"policies": &framework.FieldSchema{
+			Type:        framework.TypeCommaStringSlice,
+			Description: "Only match tokens that carry all of these policies.",
+		},
+		"entity_id": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens tied to this identity entity.",
+		},
+		"display_name": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens with this display name. A trailing '*' matches on prefix.",
+		},
+		"meta": &framework.FieldSchema{
+			Type:        framework.TypeKVPairs,
+			Description: "Only match tokens whose metadata contains all of these key/value pairs.",
+		},
+		"created_after": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens created after this RFC 3339 time, or this long ago if given as a duration.",
+		},
+		"created_before": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens created before this RFC 3339 time, or this long ago if given as a duration.",
+		},
+		"expires_after": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens expiring after this RFC 3339 time, or this far in the future if given as a duration. Tokens that never expire match.",
+		},
+		"expires_before": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Only match tokens expiring before this RFC 3339 time, or this far in the future if given as a duration. Tokens that never expire do not match.",
+		},
+		"after": &framework.FieldSchema{
+			Type:        framework.TypeString,
+			Description: "Continue a previous request from the 'next' value it returned.",
+		},
+		"limit": &framework.FieldSchema{
+			Type:        framework.TypeInt,
+			Default:     tokenSearchDefaultLimit,
+			Description: fmt.Sprintf("The maximum number of tokens to return or revoke. Cannot exceed %d.", tokenSearchMaxLimit),
+		},
+	}
+}
+
+// parseTokenSearchTime parses a search time bound given either as an RFC 3339
+// time or as a duration, which is applied to the current time in the given
+// direction.
+func parseTokenSearchTime(d *framework.FieldData, field string, direction time.Duration) (time.Time, error) {
+	raw := d.Get(field).(string)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+
+	dur, err := parseutil.ParseDurationSecond(raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q must be an RFC 3339 time or a duration", field)
+	}
+	return time.Now().Add(direction * dur), nil
+}
+
+func parseTokenSearchFilter(d *framework.FieldData) (*tokenSearchFilter, error) {
+	f := &tokenSearchFilter{
+		RoleName:    d.Get("role_name").(string),
+		EntityID:    d.Get("entity_id").(string),
+		DisplayName: d.Get("display_name").(string),
+		Meta:        d.Get("meta").(map[string]string),
+	}
+
+	if policies := d.Get("policies").([]string); len(policies) > 0 {
+		f.Policies = policyutil.SanitizePolicies(policies, policyutil.DoNotAddDefaultPolicy)
+	}
+
+	var err error
+	if f.CreatedAfter, err = parseTokenSearchTime(d, "created_after", -1); err != nil {
+		return nil, err
+	}
+	if f.CreatedBefore, err = parseTokenSearchTime(d, "created_before", -1); err != nil {
+		return nil, err
+	}
+	if f.ExpiresAfter, err = parseTokenSearchTime(d, "expires_after", 1); err != nil {
+		return nil, err
+	}
+	if f.ExpiresBefore, err = parseTokenSearchTime(d, "expires_before", 1); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// empty returns true if the filter would match every token
+func (f *tokenSearchFilter) empty() bool {
+	return f.RoleName == "" &&
+		len(f.Policies) == 0 &&
+		f.EntityID == "" &&
+		f.DisplayName == "" &&
+		len(f.Meta) == 0 &&
+		f.CreatedAfter.IsZero() &&
+		f.CreatedBefore.IsZero() &&
+		f.ExpiresAfter.IsZero() &&
+		f.ExpiresBefore.IsZero()
+}
+
+// needsExpiration returns true if matching requires the token's expiration
+// time
+func (f *tokenSearchFilter) needsExpiration() bool {
+	return !f.ExpiresAfter.IsZero() || !f.ExpiresBefore.IsZero()
+}
+
+// matchesEntry checks the parts of the filter that only depend on the token
+// entry itself
+func (f *tokenSearchFilter) matchesEntry(te *logical.TokenEntry) bool {
+	if f.RoleName != "" && te.Role != f.RoleName {
+		return false
+	}
+	if f.EntityID != "" && te.EntityID != f.EntityID {
+		return false
+	}
+
+	switch {
+	case f.DisplayName == "":
+	case strings.HasSuffix(f.DisplayName, "*"):
+		if !strings.HasPrefix(te.DisplayName, strings.TrimSuffix(f.DisplayName, "*")) {
+			return false
+		}
+	case te.DisplayName != f.DisplayName:
+		return false
+	}
+
+	for _, policy := range f.Policies {
+		if !strutil.StrListContains(te.Policies, policy) {
+			return false
+		}
+	}
+
+	for k, v := range f.Meta {
+		if actual, ok := te.Meta[k]; !ok || actual != v {
+			return false
+		}
+	}
+
+	created := time.Unix(te.CreationTime, 0)
+	if !f.CreatedAfter.IsZero() && !created.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !created.Before(f.CreatedBefore) {
+		return false
+	}
+
+	return true
+}
+
+// matchesExpiration checks the expiration bounds of the filter. A zero
+// expiration time means the token never expires.
+func (f *tokenSearchFilter) matchesExpiration(expireTime time.Time) bool {
+	if !f.ExpiresAfter.IsZero() && !expireTime.IsZero() && !expireTime.After(f.ExpiresAfter) {
+		return false
+	}
+	if !f.ExpiresBefore.IsZero() && (expireTime.IsZero() || !expireTime.Before(f.ExpiresBefore)) {
+		return false
+	}
+	return true
+}
+
+// searchTokens walks the accessor index of the namespace in the context in
+// key order, starting after the given position, and returns up to limit token
+// entries matching the filter. At most tokenSearchScanBudget entries are
+// examined per call, so a page may hold fewer matches, or none, while the
+// search isn't complete. The returned position can be passed back as after to
+// continue the search; it is empty once the index has been exhausted.
+//
+// The index is listed once per search: the listing is kept under a cursor
+// named in the returned position, and later pages continue through it. If
+// the cursor is gone, e.g. after it expired or the active node changed, or
+// too many searches are in progress to keep it, the index is listed again and
+// the search resumes from the last key returned. Tokens created after a
+// search started may thus be left out of it.
+func (ts *TokenStore) searchTokens(ctx context.Context, filter *tokenSearchFilter, after string, limit int) ([]*tokenSearchMatch, string, []string, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	cursorID, afterKey := parseTokenSearchPosition(after)
+	var cursor *tokenSearchCursor
+	if raw, ok := ts.searchCursors.Get(cursorID); ok && raw.(*tokenSearchCursor).namespaceID == ns.ID {
+		cursor = raw.(*tokenSearchCursor)
+	} else {
+		cursorID = ""
+		saltedAccessors, err := ts.accessorView(ns).List(ctx, "")
+		if err != nil {
+			return nil, "", nil, errwrap.Wrapf("failed to fetch accessor index entries: {{err}}", err)
+		}
+		sort.Strings(saltedAccessors)
+
+		if ts.searchCursors.ItemCount() < tokenSearchMaxCursors {
+			cursorID, err = uuid.GenerateUUID()
+			if err != nil {
+				return nil, "", nil, err
+			}
+		}
+		cursor = &tokenSearchCursor{
+			namespaceID:     ns.ID,
+			saltedAccessors: saltedAccessors,
+		}
+	}
+	saltedAccessors := cursor.saltedAccessors
+
+	start := sort.SearchStrings(saltedAccessors, afterKey)
+	if start < len(saltedAccessors) && saltedAccessors[start] == afterKey {
+		start++
+	}
+
+	var matches []*tokenSearchMatch
+	var warnings []string
+	for i := start; i < len(saltedAccessors); i++ {
+		if len(matches) == limit || i-start == tokenSearchScanBudget {
+			if cursorID == "" {
+				return matches, saltedAccessors[i-1], warnings, nil
+			}
+			ts.searchCursors.Set(cursorID, cursor, cache.DefaultExpiration)
+			return matches, cursorID + ":" + saltedAccessors[i-1], warnings, nil
+		}
+
+		aEntry, err := ts.lookupByAccessor(ctx, saltedAccessors[i], true, false)
+		if _, ok := err.(*logical.StatusBadRequest); ok {
+			// The token was revoked since the index was listed
+			continue
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Found an accessor entry that could not be successfully decoded; associated error is %q", err.Error()))
+			continue
+		}
+		if aEntry.TokenID == "" || aEntry.NamespaceID != ns.ID {
+			continue
+		}
+
+		lock := locksutil.LockForKey(ts.tokenLocks, aEntry.TokenID)
+		lock.RLock()
+		te, err := ts.lookupInternal(ctx, aEntry.TokenID, false, false)
+		lock.RUnlock()
+		if err != nil {
+			return nil, "", nil, errwrap.Wrapf("failed to look up token: {{err}}", err)
+		}
+		if te == nil || !filter.matchesEntry(te) {
+			continue
+		}
+
+		match := &tokenSearchMatch{
+			entry: te,
+		}
+		leaseTimes, err := ts.expiration.FetchLeaseTimesByToken(ctx, te)
+		if err != nil {
+			return nil, "", nil, errwrap.Wrapf("failed to fetch token lease: {{err}}", err)
+		}
+		if leaseTimes != nil {
+			match.expireTime = leaseTimes.ExpireTime
+		}
+		if filter.needsExpiration() && !filter.matchesExpiration(match.expireTime) {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	if cursorID != "" {
+		ts.searchCursors.Delete(cursorID)
+	}
+	return matches, "", warnings, nil
+}
+
+// parseTokenSearchPosition splits the position a search resumes from into the
+// ID of the cursor holding its listing, if any, and the last key returned
+func parseTokenSearchPosition(after string) (string, string) {
+	if i := strings.Index(after, ":"); i >= 0 {
+		return after[:i], after[i+1:]
+	}
+	return "", after
+}
+
+func parseTokenSearchLimit(d *framework.FieldData) (int, error) {
+	limit := d.Get("limit").(int)
+	switch {
+	case limit <= 0:
+		return 0, errors.New("'limit' must be greater than zero")
+	case limit > tokenSearchMaxLimit:
+		return 0, fmt.Errorf("'limit' cannot be greater than %d", tokenSearchMaxLimit)
+	}
+	return limit, nil
+}
+
+// handleSearch handles the auth/token/search path, returning the accessors
+// of tokens matching the given criteria
+func (ts *TokenStore) handleSearch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	filter, err := parseTokenSearchFilter(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	limit, err := parseTokenSearchLimit(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	matches, next, warnings, err := ts.searchTokens(ctx, filter, d.Get("after").(string), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(matches))
+	keyInfo := make(map[string]interface{}, len(matches))
+	for _, match := range matches {
+		te := match.entry
+		info := map[string]interface{}{
+			"display_name":  te.DisplayName,
+			"policies":      te.Policies,
+			"meta":          te.Meta,
+			"entity_id":     te.EntityID,
+			"role":          te.Role,
+			"creation_time": te.CreationTime,
+			"expire_time":   nil,
+		}
+		if !match.expireTime.IsZero() {
+			info["expire_time"] = match.expireTime
+		}
+		keys = append(keys, te.Accessor)
+		keyInfo[te.Accessor] = info
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"keys":     keys,
+			"key_info": keyInfo,
+			"next":     next,
+		},
+	}
+	for _, warning := range warnings {
+		resp.AddWarning(warning)
+	}
+	return resp, nil
+}
+
+// handleRevokeSearch handles the auth/token/revoke-search path, revoking the
+// tokens matching the given criteria along with their children. Revocations
+// are paced by a limiter shared by all bulk revocation requests.
+func (ts *TokenStore) handleRevokeSearch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	filter, err := parseTokenSearchFilter(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	if filter.empty() {
+		return logical.ErrorResponse("at least one search criterion is required to revoke tokens in bulk"), logical.ErrInvalidRequest
+	}
+	limit, err := parseTokenSearchLimit(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	matches, next, warnings, err := ts.searchTokens(ctx, filter, d.Get("after").(string), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := make([]string, 0, len(matches))
+	resp := &logical.Response{}
+	for _, warning := range warnings {
+		resp.AddWarning(warning)
+	}
+
+	for _, match := range matches {
+		if match.entry.Type == logical.TokenTypeBatch {
+			continue
+		}
+
+		// The token may have been revoked since the search, e.g. as the child
+		// of a token revoked earlier in this loop
+		te, err := ts.Lookup(ctx, match.entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		if te == nil {
+			continue
+		}
+
+		if err := ts.bulkRevokeLimiter.Wait(ctx); err != nil {
+			resp.AddWarning(fmt.Sprintf("Bulk revocation stopped early: %v", err))
+			break
+		}
+
+		tokenNS, err := NamespaceByID(ctx, te.NamespaceID, ts.core)
+		if err != nil {
+			return nil, err
+		}
+		if tokenNS == nil {
+			return nil, namespace.ErrNoNamespace
+		}
+
+		revokeCtx := namespace.ContextWithNamespace(ts.quitContext, tokenNS)
+		leaseID, err := ts.expiration.CreateOrFetchRevocationLeaseByToken(revokeCtx, te)
+		if err != nil {
+			return nil, err
+		}
+		if err := ts.expiration.Revoke(revokeCtx, leaseID); err != nil {
+			return nil, errwrap.Wrapf(fmt.Sprintf("failed to revoke token with accessor %q: {{err}}", te.Accessor), err)
+		}
+
+		ts.logger.Info("revoked token by search", "accessor", te.Accessor, "display_name", te.DisplayName)
+		revoked = append(revoked, te.Accessor)
+	}
+
+	metrics.IncrCounter([]string{"token", "revoke-search"}, float32(len(revoked)))
+
+	resp.Data = map[string]interface{}{
+		"revoked": revoked,
+		"next":    next,
+	}
+	return resp, nil
+}
+
+// newTokenBulkRevokeLimiter returns the limiter used to pace bulk
+// revocations
+func newTokenBulkRevokeLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(tokenBulkRevokeRate), tokenBulkRevokeRate)
+}
+
+// newTokenSearchCursors returns the cache holding the listings of the
+// searches being paged through
+func newTokenSearchCursors() *cache.Cache {
+	return cache.New(tokenSearchCursorTTL, tokenSearchCursorTTL)
+}
+
+const (
+	tokenSearchHelp = `This endpoint returns the accessors of tokens matching the given criteria.`
+	tokenSearchDesc = `
+Searches the tokens of the current namespace by role, policies, entity ID,
+display name, metadata, and creation or expiration time. Results are returned
+in pages; pass the returned 'next' value as 'after' to fetch the next page.
+A page may hold fewer tokens than 'limit', or none, as each request examines
+a bounded number of tokens. An empty 'next' means the search is complete. Tokens created after the first
+page was returned may be left out. Because this scans every token, this
+endpoint requires 'sudo' capability.
+`
+	tokenRevokeSearchHelp = `This endpoint revokes the tokens matching the given criteria, along with their child tokens.`
+	tokenRevokeSearchDesc = `
+Revokes the tokens of the current namespace matching the same criteria as the
+'search' endpoint. At least one criterion must be given. Up to 'limit' tokens
+are revoked per request, paced to a fixed rate across all requests on the node,
+and each revocation is logged with the token's accessor. Pass the returned
+'next' value as 'after' to continue. Batch tokens are skipped since they
+cannot be revoked. This endpoint requires 'sudo' capability.
+`
+)
@@ -0,0 +1,372 @@
+package vault
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func TestTokenStore_Search(t *testing.T) {
+	exp := mockExpiration(t)
+	ts := exp.tokenStore
+
+	ciRecent := &logical.TokenEntry{
+		ID:          "ci-recent",
+		Role:        "ci",
+		DisplayName: "token-ci-1",
+		Policies:    []string{"ci", "default"},
+		Meta:        map[string]string{"job": "1"},
+		Path:        "auth/token/create/ci",
+		TTL:         time.Hour,
+	}
+	ciOld := &logical.TokenEntry{
+		ID:           "ci-old",
+		Role:         "ci",
+		DisplayName:  "token-ci-2",
+		Policies:     []string{"ci", "default", "extra"},
+		Meta:         map[string]string{"job": "2"},
+		Path:         "auth/token/create/ci",
+		TTL:          30 * time.Minute,
+		CreationTime: time.Now().Add(-2 * time.Hour).Unix(),
+	}
+	other := &logical.TokenEntry{
+		ID:          "other",
+		DisplayName: "token-other",
+		Policies:    []string{"default"},
+		Path:        "auth/token/create",
+		TTL:         2 * time.Hour,
+	}
+	for _, te := range []*logical.TokenEntry{ciRecent, ciOld, other} {
+		testMakeTokenDirectly(t, ts, te)
+	}
+
+	search := func(data map[string]interface{}) []string {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "search")
+		req.Data = data
+		resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v\nresp: %#v", err, resp)
+		}
+		if next := resp.Data["next"].(string); next != "" {
+			t.Fatalf("unexpected next page %q", next)
+		}
+		keys := resp.Data["keys"].([]string)
+		sort.Strings(keys)
+		return keys
+	}
+
+	accessors := func(entries ...*logical.TokenEntry) []string {
+		ret := []string{}
+		for _, te := range entries {
+			ret = append(ret, te.Accessor)
+		}
+		sort.Strings(ret)
+		return ret
+	}
+
+	cases := []struct {
+		name     string
+		data     map[string]interface{}
+		expected []string
+	}{
+		{"role", map[string]interface{}{"role_name": "ci"}, accessors(ciRecent, ciOld)},
+		{"policies", map[string]interface{}{"policies": "ci,extra"}, accessors(ciOld)},
+		{"display name prefix", map[string]interface{}{"display_name": "token-ci*"}, accessors(ciRecent, ciOld)},
+		{"display name", map[string]interface{}{"display_name": "token-other"}, accessors(other)},
+		{"meta", map[string]interface{}{"meta": map[string]interface{}{"job": "1"}}, accessors(ciRecent)},
+		{"created before", map[string]interface{}{"created_before": "1h"}, accessors(ciOld)},
+		{"created after", map[string]interface{}{"created_after": time.Now().Add(-time.Hour).Format(time.RFC3339), "role_name": "ci"}, accessors(ciRecent)},
+		{"expires before", map[string]interface{}{"expires_before": "45m"}, accessors(ciOld)},
+		{"expires after", map[string]interface{}{"expires_after": "45m", "policies": "default"}, accessors(ciRecent, other)},
+		{"no match", map[string]interface{}{"role_name": "ci", "policies": "root"}, []string{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := search(tc.data); !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("bad: expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+
+	// Page through the results one at a time
+	var paged []string
+	after := ""
+	for i := 0; ; i++ {
+		if i > 10 {
+			t.Fatal("search did not terminate")
+		}
+		req := logical.TestRequest(t, logical.UpdateOperation, "search")
+		req.Data = map[string]interface{}{
+			"role_name": "ci",
+			"limit":     1,
+			"after":     after,
+		}
+		resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v\nresp: %#v", err, resp)
+		}
+		keys := resp.Data["keys"].([]string)
+		if len(keys) > 1 {
+			t.Fatalf("expected at most one key, got %v", keys)
+		}
+		paged = append(paged, keys...)
+		after = resp.Data["next"].(string)
+		if after == "" {
+			break
+		}
+	}
+	sort.Strings(paged)
+	if expected := accessors(ciRecent, ciOld); !reflect.DeepEqual(paged, expected) {
+		t.Fatalf("bad: expected %v, got %v", expected, paged)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "search")
+	req.Data = map[string]interface{}{
+		"created_after": "yesterday",
+	}
+	resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest || resp == nil || !resp.IsError() {
+		t.Fatalf("expected invalid time to be rejected, got err: %v\nresp: %#v", err, resp)
+	}
+}
+
+func TestTokenStore_RevokeSearch(t *testing.T) {
+	exp := mockExpiration(t)
+	ts := exp.tokenStore
+
+	ci := &logical.TokenEntry{
+		ID:          "ci",
+		Role:        "ci",
+		DisplayName: "token-ci",
+		Policies:    []string{"default"},
+		Path:        "auth/token/create/ci",
+		TTL:         time.Hour,
+	}
+	ciChild := &logical.TokenEntry{
+		ID:          "ci-child",
+		Parent:      "ci",
+		Role:        "ci",
+		DisplayName: "token-ci",
+		Policies:    []string{"default"},
+		Path:        "auth/token/create/ci",
+		TTL:         time.Hour,
+	}
+	other := &logical.TokenEntry{
+		ID:          "other",
+		DisplayName: "token-other",
+		Policies:    []string{"default"},
+		Path:        "auth/token/create",
+		TTL:         time.Hour,
+	}
+	for _, te := range []*logical.TokenEntry{ci, ciChild, other} {
+		testMakeTokenDirectly(t, ts, te)
+	}
+
+	// Revoking everything requires at least one criterion
+	req := logical.TestRequest(t, logical.UpdateOperation, "revoke-search")
+	resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest || resp == nil || !resp.IsError() {
+		t.Fatalf("expected empty criteria to be rejected, got err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "revoke-search")
+	req.Data = map[string]interface{}{
+		"role_name": "ci",
+	}
+	resp, err = ts.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if next := resp.Data["next"].(string); next != "" {
+		t.Fatalf("unexpected next page %q", next)
+	}
+
+	// The child is revoked along with its parent, so depending on the order
+	// of the index it may not be reported as revoked by the search itself
+	revoked := resp.Data["revoked"].([]string)
+	if len(revoked) == 0 || len(revoked) > 2 {
+		t.Fatalf("bad: revoked %v", revoked)
+	}
+
+	for _, id := range []string{"ci", "ci-child"} {
+		te, err := ts.Lookup(namespace.RootContext(nil), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if te != nil {
+			t.Fatalf("expected token %q to be revoked", id)
+		}
+	}
+
+	te, err := ts.Lookup(namespace.RootContext(nil), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te == nil {
+		t.Fatal("expected unmatched token to survive bulk revocation")
+	}
+}
+
+func TestTokenStore_Search_Cursor(t *testing.T) {
+	exp := mockExpiration(t)
+	ts := exp.tokenStore
+
+	var entries []*logical.TokenEntry
+	for _, id := range []string{"ci-1", "ci-2", "ci-3"} {
+		te := &logical.TokenEntry{
+			ID:       id,
+			Role:     "ci",
+			Policies: []string{"default"},
+			Path:     "auth/token/create/ci",
+			TTL:      time.Hour,
+		}
+		testMakeTokenDirectly(t, ts, te)
+		entries = append(entries, te)
+	}
+
+	page := func(after string) ([]string, string) {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "search")
+		req.Data = map[string]interface{}{
+			"role_name": "ci",
+			"limit":     1,
+			"after":     after,
+		}
+		resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v\nresp: %#v", err, resp)
+		}
+		if len(resp.Warnings) > 0 {
+			t.Fatalf("unexpected warnings: %v", resp.Warnings)
+		}
+		return resp.Data["keys"].([]string), resp.Data["next"].(string)
+	}
+
+	paged, next := page("")
+	if len(paged) != 1 || next == "" {
+		t.Fatalf("bad: keys %v, next %q", paged, next)
+	}
+	if ts.searchCursors.ItemCount() != 1 {
+		t.Fatal("expected the listing to be kept for the next page")
+	}
+
+	// Tokens revoked after the index was listed are skipped
+	var revoked *logical.TokenEntry
+	for _, te := range entries {
+		if te.Accessor != paged[0] {
+			revoked = te
+			break
+		}
+	}
+	if err := ts.revokeOrphan(namespace.RootContext(nil), revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; next != ""; i++ {
+		if i > 10 {
+			t.Fatal("search did not terminate")
+		}
+		// The search resumes from the last key once the listing is gone
+		if i == 1 {
+			ts.searchCursors.Flush()
+		}
+		var keys []string
+		keys, next = page(next)
+		paged = append(paged, keys...)
+	}
+	if ts.searchCursors.ItemCount() != 0 {
+		t.Fatal("expected the listing to be dropped once the search completed")
+	}
+
+	var expected []string
+	for _, te := range entries {
+		if te != revoked {
+			expected = append(expected, te.Accessor)
+		}
+	}
+	sort.Strings(paged)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(paged, expected) {
+		t.Fatalf("bad: expected %v, got %v", expected, paged)
+	}
+}
+
+func TestTokenStore_Search_ScanBudget(t *testing.T) {
+	exp := mockExpiration(t)
+	ts := exp.tokenStore
+
+	oldBudget, oldMaxCursors := tokenSearchScanBudget, tokenSearchMaxCursors
+	defer func() {
+		tokenSearchScanBudget, tokenSearchMaxCursors = oldBudget, oldMaxCursors
+	}()
+	tokenSearchScanBudget = 2
+
+	var expected []string
+	for i, role := range []string{"web", "web", "ci", "web", "web"} {
+		te := &logical.TokenEntry{
+			ID:       fmt.Sprintf("%s-%d", role, i),
+			Role:     role,
+			Policies: []string{"default"},
+			Path:     "auth/token/create/" + role,
+			TTL:      time.Hour,
+		}
+		testMakeTokenDirectly(t, ts, te)
+		if role == "ci" {
+			expected = append(expected, te.Accessor)
+		}
+	}
+
+	search := func() ([]string, int) {
+		t.Helper()
+		var keys []string
+		var emptyPages int
+		next := ""
+		for i := 0; i == 0 || next != ""; i++ {
+			if i > 10 {
+				t.Fatal("search did not terminate")
+			}
+			req := logical.TestRequest(t, logical.UpdateOperation, "search")
+			req.Data = map[string]interface{}{
+				"role_name": "ci",
+				"limit":     10,
+				"after":     next,
+			}
+			resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+			if err != nil || (resp != nil && resp.IsError()) {
+				t.Fatalf("err: %v\nresp: %#v", err, resp)
+			}
+			page := resp.Data["keys"].([]string)
+			next = resp.Data["next"].(string)
+			if len(page) == 0 && next != "" {
+				emptyPages++
+			}
+			keys = append(keys, page...)
+		}
+		return keys, emptyPages
+	}
+
+	// Pages stop once the budget is spent, even without any match
+	keys, emptyPages := search()
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("bad: expected %v, got %v", expected, keys)
+	}
+	if emptyPages == 0 {
+		t.Fatal("expected pages without matches while the search was incomplete")
+	}
+
+	// Searches past the cursor cap aren't kept but still complete
+	tokenSearchMaxCursors = 0
+	keys, _ = search()
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("bad: expected %v, got %v", expected, keys)
+	}
+	if ts.searchCursors.ItemCount() != 0 {
+		t.Fatal("expected no listing to be kept")
+	}
+}
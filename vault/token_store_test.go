@@ -2,6 +2,8 @@ package vault
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"path"
@@ -3208,6 +3210,113 @@ func TestTokenStore_RoleOrphan(t *testing.T) {
 	}
 }
 
+func TestTokenStore_RoleBindClientCert(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	connWithCert := func(raw string) *logical.Connection {
+		return &logical.Connection{
+			RemoteAddr: "127.0.0.1",
+			ConnState: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Raw: []byte(raw)}},
+			},
+		}
+	}
+	handle := func(req *logical.Request) (*logical.Response, error) {
+		return c.HandleRequest(namespace.RootContext(nil), req)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/roles/bound")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"token_bind_client_cert": true,
+	}
+	if resp, err := handle(req); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "auth/token/roles/bound")
+	req.ClientToken = root
+	resp, err := handle(req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp.Data["token_bind_client_cert"] != true {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Batch tokens cannot be bound
+	req = logical.TestRequest(t, logical.UpdateOperation, "auth/token/roles/bound-batch")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"token_bind_client_cert": true,
+		"token_type":             "batch",
+		"orphan":                 true,
+	}
+	if resp, _ := handle(req); resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error for a batch role: %#v", resp)
+	}
+
+	// A client certificate is required to create a bound token
+	req = logical.TestRequest(t, logical.UpdateOperation, "auth/token/create/bound")
+	req.ClientToken = root
+	if resp, _ := handle(req); resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error without a client certificate: %#v", resp)
+	}
+
+	req.Connection = connWithCert("cert-a")
+	resp, err = handle(req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	token := resp.Auth.ClientToken
+	fingerprint := clientCertFingerprint(connWithCert("cert-a"))
+	if resp.Auth.BoundCertFingerprint != fingerprint {
+		t.Fatalf("bad: %#v", resp.Auth)
+	}
+
+	// The token is only usable with the certificate it is bound to
+	req = logical.TestRequest(t, logical.ReadOperation, "auth/token/lookup-self")
+	req.ClientToken = token
+	req.Connection = connWithCert("cert-a")
+	resp, err = handle(req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp.Data["bound_cert_fingerprint"] != fingerprint {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	for _, conn := range []*logical.Connection{connWithCert("cert-b"), {RemoteAddr: "127.0.0.1"}} {
+		req.Connection = conn
+		if _, err := handle(req); err == nil || !errwrap.Contains(err, logical.ErrPermissionDenied.Error()) {
+			t.Fatalf("expected permission denied, got %v", err)
+		}
+	}
+
+	// Children of the token are bound to the same certificate
+	req = logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+	req.ClientToken = token
+	req.Connection = connWithCert("cert-a")
+	resp, err = handle(req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	child, err := c.tokenStore.Lookup(namespace.RootContext(nil), resp.Auth.ClientToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.BoundCertFingerprint != fingerprint {
+		t.Fatalf("bad: %#v", child)
+	}
+
+	req.Data = map[string]interface{}{
+		"type": "batch",
+	}
+	if resp, _ := handle(req); resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error for a bound batch token: %#v", resp)
+	}
+}
+
 func TestTokenStore_RolePathSuffix(t *testing.T) {
 	c, _, root := TestCoreUnsealed(t)
 	ts := c.tokenStore
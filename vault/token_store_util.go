@@ -3,25 +3,66 @@
 package vault
 
 import (
+	"context"
+
 	"github.com/hashicorp/vault/helper/namespace"
 )
 
 func (ts *TokenStore) baseView(ns *namespace.Namespace) *BarrierView {
-	return ts.baseBarrierView
+	if ns.ID == namespace.RootNamespaceID {
+		return ts.baseBarrierView
+	}
+	return ts.core.namespaceView(ns, systemBarrierPrefix+tokenSubPath)
 }
 
 func (ts *TokenStore) idView(ns *namespace.Namespace) *BarrierView {
-	return ts.idBarrierView
+	if ns.ID == namespace.RootNamespaceID {
+		return ts.idBarrierView
+	}
+	return ts.baseView(ns).SubView(idPrefix)
 }
 
 func (ts *TokenStore) accessorView(ns *namespace.Namespace) *BarrierView {
-	return ts.accessorBarrierView
+	if ns.ID == namespace.RootNamespaceID {
+		return ts.accessorBarrierView
+	}
+	return ts.baseView(ns).SubView(accessorPrefix)
 }
 
 func (ts *TokenStore) parentView(ns *namespace.Namespace) *BarrierView {
-	return ts.parentBarrierView
+	if ns.ID == namespace.RootNamespaceID {
+		return ts.parentBarrierView
+	}
+	return ts.baseView(ns).SubView(parentPrefix)
 }
 
 func (ts *TokenStore) rolesView(ns *namespace.Namespace) *BarrierView {
-	return ts.rolesBarrierView
+	if ns.ID == namespace.RootNamespaceID {
+		return ts.rolesBarrierView
+	}
+	return ts.baseView(ns).SubView(rolesPrefix)
+}
+
+// namespaceCubbyhole returns the cubbyhole backend of the namespace with the
+// given ID, or nil if it isn't mounted anymore
+func (ts *TokenStore) namespaceCubbyhole(ctx context.Context, nsID string) (*CubbyholeBackend, error) {
+	if nsID == namespace.RootNamespaceID {
+		return ts.cubbyholeBackend, nil
+	}
+	ns, err := NamespaceByID(ctx, nsID, ts.core)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		return nil, nil
+	}
+	ch, _ := ts.core.router.MatchingBackend(namespace.ContextWithNamespace(ctx, ns), cubbyholeMountPath).(*CubbyholeBackend)
+	return ch, nil
+}
+
+// removeNamespaceSalt drops the salt of a deleted namespace
+func (ts *TokenStore) removeNamespaceSalt(ns *namespace.Namespace) {
+	ts.saltLock.Lock()
+	defer ts.saltLock.Unlock()
+	delete(ts.salts, ns.ID)
 }